@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"dev-dashboard/internal/github"
+	"dev-dashboard/pkg/types"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const (
+	auditArtifactDownload = "artifact_download"
+
+	// artifactDownloadMaxBytes bounds the archives DownloadActionArtifact saves; larger ones are
+	// better fetched from GitHub directly
+	artifactDownloadMaxBytes = 1 << 30
+
+	artifactListTimeout     = 20 * time.Second
+	artifactDownloadTimeout = 30 * time.Minute
+)
+
+// GetActionArtifacts returns the artifacts the workflow run behind an action uploaded, with their
+// size and expiry. Expired ones are listed too, but can't be downloaded.
+func (a *App) GetActionArtifacts(actionID int64) ([]*types.ActionArtifact, error) {
+	if a.actionModel == nil || a.repoModel == nil {
+		return nil, fmt.Errorf("action model not initialized")
+	}
+	action, err := a.actionModel.GetByID(actionID)
+	if err != nil {
+		return nil, err
+	}
+	repo, err := a.repoModel.GetByID(action.RepositoryID)
+	if err != nil {
+		return nil, fmt.Errorf("repository not found: %w", err)
+	}
+	client, owner, repoName, err := a.repositoryGitHubClient(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), artifactListTimeout)
+	defer cancel()
+	artifacts, err := client.ListWorkflowRunArtifacts(ctx, owner, repoName, action.WorkflowRunID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*types.ActionArtifact, 0, len(artifacts))
+	for _, artifact := range artifacts {
+		result = append(result, &types.ActionArtifact{
+			ID:        artifact.ID,
+			Name:      artifact.Name,
+			SizeBytes: artifact.SizeBytes,
+			Expired:   artifact.Expired,
+			CreatedAt: artifact.CreatedAt,
+			ExpiresAt: artifact.ExpiresAt,
+			TooLarge:  artifact.SizeBytes > artifactDownloadMaxBytes,
+		})
+	}
+	return result, nil
+}
+
+// DownloadActionArtifact saves the zip archive of an artifact of an action's workflow run to
+// destPath, streaming it to disk. An empty destPath opens a save dialog. It returns the file's path,
+// or an empty string when the dialog was cancelled. Downloads are recorded in the audit log.
+func (a *App) DownloadActionArtifact(actionID, artifactID int64, destPath string) (path string, err error) {
+	if a.actionModel == nil || a.repoModel == nil {
+		return "", fmt.Errorf("action model not initialized")
+	}
+	action, err := a.actionModel.GetByID(actionID)
+	if err != nil {
+		return "", err
+	}
+	repo, err := a.repoModel.GetByID(action.RepositoryID)
+	if err != nil {
+		return "", fmt.Errorf("repository not found: %w", err)
+	}
+	client, owner, repoName, err := a.repositoryGitHubClient(repo)
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), artifactDownloadTimeout)
+	defer cancel()
+	artifact, err := client.GetArtifact(ctx, owner, repoName, artifactID)
+	if err != nil {
+		return "", err
+	}
+	if artifact.WorkflowRunID != action.WorkflowRunID {
+		return "", fmt.Errorf("artifact %d doesn't belong to workflow run %d", artifactID, action.WorkflowRunID)
+	}
+	if artifact.Expired {
+		expired := "its retention period passed"
+		if artifact.ExpiresAt != nil {
+			expired = "it expired on " + artifact.ExpiresAt.Local().Format("2006-01-02 15:04")
+		}
+		return "", fmt.Errorf("%w: %s can't be downloaded anymore, %s", github.ErrArtifactExpired, artifact.Name, expired)
+	}
+	if artifact.SizeBytes > artifactDownloadMaxBytes {
+		return "", fmt.Errorf("%w: %s is %d bytes, the limit is %d", github.ErrArtifactTooLarge, artifact.Name, artifact.SizeBytes, artifactDownloadMaxBytes)
+	}
+
+	if destPath == "" {
+		destPath, err = runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+			Title:           "Save artifact",
+			DefaultFilename: artifact.Name + ".zip",
+			Filters:         []runtime.FileFilter{{DisplayName: "Zip archives (*.zip)", Pattern: "*.zip"}},
+		})
+		if err != nil || destPath == "" {
+			return "", err
+		}
+	}
+
+	defer func() {
+		a.audit(auditArtifactDownload, repo.ID, fmt.Sprintf("%s: %s of workflow run %d -> %s", repo.Name, artifact.Name, action.WorkflowRunID, destPath), err)
+	}()
+
+	written, err := downloadArtifactFile(ctx, client, owner, repoName, artifactID, destPath)
+	if err != nil {
+		return "", err
+	}
+	log.Printf("Downloaded artifact %s of workflow run %d (%d bytes) to %s", artifact.Name, action.WorkflowRunID, written, destPath)
+	return destPath, nil
+}
+
+// downloadArtifactFile streams an artifact into a temporary file next to destPath and moves it
+// into place once complete, so a failed download never leaves a partial archive behind
+func downloadArtifactFile(ctx context.Context, client *github.Client, owner, repoName string, artifactID int64, destPath string) (int64, error) {
+	file, err := os.CreateTemp(filepath.Dir(destPath), ".artifact-*.zip")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create download file: %w", err)
+	}
+	tempPath := file.Name()
+
+	written, err := client.DownloadArtifact(ctx, owner, repoName, artifactID, file, artifactDownloadMaxBytes)
+	if closeErr := file.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to write download file: %w", closeErr)
+	}
+	if err == nil {
+		if renameErr := os.Rename(tempPath, destPath); renameErr != nil {
+			err = fmt.Errorf("failed to save artifact: %w", renameErr)
+		}
+	}
+	if err != nil {
+		os.Remove(tempPath)
+		return 0, err
+	}
+	return written, nil
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"dev-dashboard/pkg/types"
+)
+
+// GetActivityFeed returns the most recent actions of a repository, or of all repositories when
+// repositoryID is 0. typeFilter keeps only build or deployment actions when set. groupBy is
+// "none" (or empty) for one list newest first, or "repo", "service" or "type" for groups ordered by
+// their newest action, each newest first.
+func (a *App) GetActivityFeed(repositoryID int64, limit int, groupBy, typeFilter string) (*types.ActivityFeed, error) {
+	if a.actionModel == nil {
+		return nil, fmt.Errorf("action model not initialized")
+	}
+	if limit <= 0 {
+		limit = 50
+	}
+	if groupBy == "" {
+		groupBy = types.ActivityGroupNone
+	}
+	switch groupBy {
+	case types.ActivityGroupNone, types.ActivityGroupRepository, types.ActivityGroupService, types.ActivityGroupType:
+	default:
+		return nil, fmt.Errorf("unknown activity grouping %q", groupBy)
+	}
+	switch types.ActionType(typeFilter) {
+	case "", types.BuildAction, types.DeploymentAction:
+	default:
+		return nil, fmt.Errorf("unknown action type %q", typeFilter)
+	}
+
+	actions, err := a.actionModel.GetRecent(repositoryID, types.ActionType(typeFilter), limit)
+	if err != nil {
+		return nil, err
+	}
+	a.displayClock().annotateActionsWithDetails(actions)
+
+	feed := &types.ActivityFeed{GroupBy: groupBy, Actions: []*types.ActionWithDetails{}, Groups: []*types.ActivityGroup{}}
+	if groupBy == types.ActivityGroupNone {
+		if actions != nil {
+			feed.Actions = actions
+		}
+		return feed, nil
+	}
+
+	repoNames := make(map[int64]string)
+	if groupBy == types.ActivityGroupRepository && a.repoModel != nil {
+		repos, err := a.repoModel.GetAll()
+		if err != nil {
+			return nil, err
+		}
+		for _, repo := range repos {
+			repoNames[repo.ID] = repo.Name
+		}
+	}
+
+	// Actions come newest first, so groups are created in order of their newest action
+	groups := make(map[string]*types.ActivityGroup)
+	for _, action := range actions {
+		key, label := activityGroupKey(action, groupBy, repoNames)
+		group, ok := groups[key]
+		if !ok {
+			group = &types.ActivityGroup{Key: key, Label: label}
+			groups[key] = group
+			feed.Groups = append(feed.Groups, group)
+		}
+		group.Actions = append(group.Actions, action)
+	}
+	return feed, nil
+}
+
+// activityGroupKey returns the key and label of the activity feed group an action belongs to
+func activityGroupKey(action *types.ActionWithDetails, groupBy string, repoNames map[int64]string) (string, string) {
+	switch groupBy {
+	case types.ActivityGroupRepository:
+		label := repoNames[action.RepositoryID]
+		if label == "" {
+			label = fmt.Sprintf("Repository %d", action.RepositoryID)
+		}
+		return strconv.FormatInt(action.RepositoryID, 10), label
+	case types.ActivityGroupService:
+		if action.ServiceID != nil && action.ServiceName != nil {
+			return strconv.FormatInt(*action.ServiceID, 10), *action.ServiceName
+		}
+		if action.ResourceID != nil && action.ResourceName != nil {
+			return "resource:" + strconv.FormatInt(*action.ResourceID, 10), *action.ResourceName
+		}
+		return "", "No service"
+	default:
+		return string(action.Type), string(action.Type)
+	}
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"dev-dashboard/pkg/types"
+)
+
+const (
+	defaultRecentAnnotations = 20
+	maxRecentAnnotations     = 200
+	maxAnnotationLength      = 2000
+)
+
+var commitSHAPattern = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// AddAnnotation attaches a note to a deployment history entry or action (by ID) or to a commit (by
+// full SHA)
+func (a *App) AddAnnotation(entityType, entityID, text string) (*types.Annotation, error) {
+	if a.annotationModel == nil {
+		return nil, fmt.Errorf("annotation model not initialized")
+	}
+	entityID, err := normalizeAnnotationEntity(entityType, entityID)
+	if err != nil {
+		return nil, err
+	}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, fmt.Errorf("annotation text is required")
+	}
+	if len(text) > maxAnnotationLength {
+		return nil, fmt.Errorf("annotation is longer than %d characters", maxAnnotationLength)
+	}
+
+	exists, err := a.annotationModel.EntityExists(entityType, entityID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("%s %s not found", entityType, entityID)
+	}
+
+	annotation := &types.Annotation{EntityType: entityType, EntityID: entityID, Text: text}
+	if err := a.annotationModel.Create(annotation); err != nil {
+		return nil, err
+	}
+	annotation.CreatedAtRelative = a.displayClock().relative(annotation.CreatedAt)
+	return annotation, nil
+}
+
+// GetAnnotations returns the notes attached to an entity, newest first
+func (a *App) GetAnnotations(entityType, entityID string) ([]*types.Annotation, error) {
+	if a.annotationModel == nil {
+		return []*types.Annotation{}, nil
+	}
+	entityID, err := normalizeAnnotationEntity(entityType, entityID)
+	if err != nil {
+		return nil, err
+	}
+	annotations, err := a.annotationModel.GetByEntity(entityType, entityID)
+	if err != nil {
+		return nil, err
+	}
+	a.displayClock().annotateAnnotations(annotations)
+	return annotations, nil
+}
+
+// DeleteAnnotation removes a note
+func (a *App) DeleteAnnotation(id int64) error {
+	if a.annotationModel == nil {
+		return fmt.Errorf("annotation model not initialized")
+	}
+	return a.annotationModel.Delete(id)
+}
+
+// GetRecentAnnotations returns the latest notes across all services, newest first. A limit of 0 or
+// less returns the default number.
+func (a *App) GetRecentAnnotations(limit int) ([]*types.Annotation, error) {
+	if a.annotationModel == nil {
+		return []*types.Annotation{}, nil
+	}
+	if limit <= 0 {
+		limit = defaultRecentAnnotations
+	}
+	annotations, err := a.annotationModel.GetRecent(min(limit, maxRecentAnnotations))
+	if err != nil {
+		return nil, err
+	}
+	a.displayClock().annotateAnnotations(annotations)
+	return annotations, nil
+}
+
+// GetServiceDeploymentEvents returns every recorded change to what a service runs, newest first,
+// with the notes attached to each
+func (a *App) GetServiceDeploymentEvents(serviceID int64) ([]*types.DeploymentHistoryEntry, error) {
+	history, err := a.deploymentModel.GetHistoryByServiceID(serviceID, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(history, func(i, j int) bool {
+		return history[i].ObservedAt.After(history[j].ObservedAt)
+	})
+	a.attachDeploymentAnnotations(history)
+	if history == nil {
+		return []*types.DeploymentHistoryEntry{}, nil
+	}
+	return history, nil
+}
+
+// normalizeAnnotationEntity checks an entity reference and returns its canonical ID
+func normalizeAnnotationEntity(entityType, entityID string) (string, error) {
+	entityID = strings.TrimSpace(entityID)
+	switch entityType {
+	case types.AnnotationDeployment, types.AnnotationAction:
+		id, err := strconv.ParseInt(entityID, 10, 64)
+		if err != nil || id <= 0 {
+			return "", fmt.Errorf("invalid %s ID %q", entityType, entityID)
+		}
+		return strconv.FormatInt(id, 10), nil
+	case types.AnnotationCommit:
+		sha := strings.ToLower(entityID)
+		if !commitSHAPattern.MatchString(sha) {
+			return "", fmt.Errorf("invalid commit SHA %q: the full 40 character SHA is required", entityID)
+		}
+		return sha, nil
+	default:
+		return "", fmt.Errorf("invalid annotation entity type %q", entityType)
+	}
+}
+
+// attachCommitAnnotations sets the notes of each commit. Notes are an extra, so failing to load them
+// is only logged.
+func (a *App) attachCommitAnnotations(commits []*types.Commit) {
+	if a.annotationModel == nil || len(commits) == 0 {
+		return
+	}
+	shas := make([]string, len(commits))
+	for i, commit := range commits {
+		shas[i] = strings.ToLower(commit.Hash)
+	}
+	byCommit, err := a.annotationModel.GetByEntities(types.AnnotationCommit, shas)
+	if err != nil {
+		log.Printf("Failed to load commit annotations: %v", err)
+		return
+	}
+	clock := a.displayClock()
+	for i, commit := range commits {
+		commit.Annotations = byCommit[shas[i]]
+		clock.annotateAnnotations(commit.Annotations)
+	}
+}
+
+// attachDeploymentAnnotations sets the notes of each deployment history entry, logging failures like
+// attachCommitAnnotations
+func (a *App) attachDeploymentAnnotations(entries []*types.DeploymentHistoryEntry) {
+	if a.annotationModel == nil || len(entries) == 0 {
+		return
+	}
+	ids := make([]string, len(entries))
+	for i, entry := range entries {
+		ids[i] = strconv.FormatInt(entry.ID, 10)
+	}
+	byEntry, err := a.annotationModel.GetByEntities(types.AnnotationDeployment, ids)
+	if err != nil {
+		log.Printf("Failed to load deployment annotations: %v", err)
+		return
+	}
+	clock := a.displayClock()
+	for i, entry := range entries {
+		entry.Annotations = byEntry[ids[i]]
+		clock.annotateAnnotations(entry.Annotations)
+	}
+}
+
+// serviceReportAnnotations collects the notes on a service's deployment history and on the commits
+// and actions of its report, newest first, with Subject describing what each note is attached to
+func (a *App) serviceReportAnnotations(report *types.ServiceReport) ([]*types.Annotation, error) {
+	if a.annotationModel == nil {
+		return nil, nil
+	}
+	var annotations []*types.Annotation
+	collect := func(entityType string, subjects map[string]string) error {
+		ids := make([]string, 0, len(subjects))
+		for id := range subjects {
+			ids = append(ids, id)
+		}
+		byEntity, err := a.annotationModel.GetByEntities(entityType, ids)
+		if err != nil {
+			return err
+		}
+		for id, notes := range byEntity {
+			for _, note := range notes {
+				note.Subject = subjects[id]
+				annotations = append(annotations, note)
+			}
+		}
+		return nil
+	}
+
+	history, err := a.deploymentModel.GetHistoryByServiceID(report.Service.ID, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	deployments := make(map[string]string, len(history))
+	for _, entry := range history {
+		deployments[strconv.FormatInt(entry.ID, 10)] = fmt.Sprintf("%s/%s %s", entry.Environment, entry.Region, entry.Tag)
+	}
+	commits := make(map[string]string, len(report.Commits))
+	for _, commit := range report.Commits {
+		commits[strings.ToLower(commit.Hash)] = "commit " + shortSHA(commit.Hash)
+	}
+	actions := make(map[string]string, len(report.Actions))
+	for _, action := range report.Actions {
+		actions[strconv.FormatInt(action.ID, 10)] = fmt.Sprintf("%s on %s at %s", action.Type, action.Branch, shortSHA(action.Commit))
+	}
+
+	if err := collect(types.AnnotationDeployment, deployments); err != nil {
+		return nil, err
+	}
+	if err := collect(types.AnnotationCommit, commits); err != nil {
+		return nil, err
+	}
+	if err := collect(types.AnnotationAction, actions); err != nil {
+		return nil, err
+	}
+	sort.Slice(annotations, func(i, j int) bool {
+		if !annotations[i].CreatedAt.Equal(annotations[j].CreatedAt) {
+			return annotations[i].CreatedAt.After(annotations[j].CreatedAt)
+		}
+		return annotations[i].ID > annotations[j].ID
+	})
+	return annotations, nil
+}
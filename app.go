@@ -2,22 +2,29 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
+	"dev-dashboard/internal/config"
 	"dev-dashboard/internal/database"
 	"dev-dashboard/internal/github"
 	"dev-dashboard/internal/jira"
+	"dev-dashboard/internal/kubernetes"
 	"dev-dashboard/internal/models"
 	"dev-dashboard/internal/sync"
+	"dev-dashboard/internal/telemetry"
+	"dev-dashboard/internal/vcs"
 	"dev-dashboard/pkg/types"
 	
 	goGithub "github.com/google/go-github/v57/github"
-	"golang.org/x/oauth2"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
 // App struct
@@ -32,13 +39,62 @@ type App struct {
 	projectModel    *models.ProjectModel
 	taskModel       *models.TaskModel
 	configModel     *models.ConfigModel
+	configSchema    *config.Schema
+	statsModel      *models.StatsSnapshotModel
+	syncLogModel    *models.SyncLogModel
+	notificationModel *models.NotificationModel
+	approvalModel   *models.PendingApprovalModel
+	usageModel      *models.ActionsUsageModel
+	webhookModel    *models.WebhookModel
+	auditModel      *models.AuditLogModel
+	usageEventModel *models.UsageEventModel
+	scorecardModel  *models.ScorecardModel
+	customFieldModel *models.CustomFieldModel
+	taskChecklistModel *models.TaskChecklistModel
+	annotationModel *models.AnnotationModel
 	jiraClient      *jira.Client
 	syncService     *sync.Service
+	jiraPoller      *sync.JiraPoller
+	notifier        *sync.Notifier
+	sensitivePRs    *sensitivePullRequestTracker
+	diffCache       *fileDiffCache
+	serviceDataCache *serviceDataCache
+	commitPRCache   *commitPullRequestCache
+	commitChecksCache *commitChecksCache
+	commitDates     *commitDateCache
+	jiraHistoryCache *jiraHistoryCache
+	jobModel        *models.JobModel
+	jobs            *jobRunner
+	discoveryChangeModel *models.DiscoveryChangeModel
+	envVarSnapshotModel *models.EnvVarSnapshotModel
+	securityAlertModel *models.SecurityAlertModel
+	servicePackageModel *models.ServicePackageModel
+	commitTicketRefModel *models.CommitTicketRefModel
+	serviceSummaryModel *models.ServiceSummaryModel
+	freezeWindowModel *models.FreezeWindowModel
+	watchRules      *watchRuleRunner
+	githubLimiter   *github.RateLimiter
+	startupError    *types.StartupError
+	presentation    *presentationRedactor
+	telemetry       *telemetryReporter
+	tokenExpiry     *tokenExpiryRecorder
 }
 
 // NewApp creates a new App application struct
 func NewApp() *App {
-	return &App{}
+	return &App{
+		diffCache: newFileDiffCache(),
+		serviceDataCache: newServiceDataCache(),
+		commitPRCache: newCommitPullRequestCache(),
+		commitChecksCache: newCommitChecksCache(),
+		commitDates: newCommitDateCache(),
+		jiraHistoryCache: newJiraHistoryCache(),
+		jobs: newJobRunner(),
+		githubLimiter: github.NewRateLimiter(github.DefaultRequestsPerSecond),
+		presentation: newPresentationRedactor(),
+		configSchema: newConfigSchema(),
+		tokenExpiry: &tokenExpiryRecorder{},
+	}
 }
 
 // startup is called when the app starts. The context is saved
@@ -48,19 +104,41 @@ func (a *App) startup(ctx context.Context) {
 	log.Println("Dev Dashboard starting up...")
 	
 	// Initialize database
-	homeDir, err := os.UserHomeDir()
+	dbPath, err := databasePath()
 	if err != nil {
 		log.Printf("Failed to get user home directory: %v", err)
 		// Continue without database for now
 		return
 	}
 	
-	dbPath := filepath.Join(homeDir, ".dev-dashboard", "database.db")
+	// A database left by an earlier build waits for the user to migrate it or keep the current one
+	if legacy, err := findLegacyDatabase(); err != nil {
+		log.Printf("Failed to look for a legacy database: %v", err)
+	} else if legacy != nil {
+		log.Printf("Found legacy database at %s, waiting for it to be migrated", legacy.Path)
+		a.startupError = &types.StartupError{
+			Stage:   legacyDatabaseStage,
+			Message: fmt.Sprintf("a database of an earlier version was found at %s", legacy.Path),
+		}
+		return
+	}
+	
 	log.Printf("Initializing database at: %s", dbPath)
 	
 	db, err := database.NewDB(dbPath)
 	if err != nil {
 		log.Printf("Failed to initialize database: %v", err)
+		a.startupError = &types.StartupError{
+			Stage:   "database",
+			Message: err.Error(),
+		}
+		var migrationErr *database.MigrationError
+		if errors.As(err, &migrationErr) {
+			a.startupError.Stage = "migration"
+			a.startupError.Migration = migrationErr.Migration
+			a.startupError.BackupPath = migrationErr.BackupPath
+			a.startupError.Restored = migrationErr.Restored
+		}
 		log.Println("Continuing without database - some features may not work")
 		// Continue without database - the UI should still load
 		return
@@ -76,21 +154,95 @@ func (a *App) startup(ctx context.Context) {
 	a.projectModel = models.NewProjectModel(db.GetConn())
 	a.taskModel = models.NewTaskModel(db.GetConn())
 	a.configModel = models.NewConfigModel(db.GetConn())
+	a.configModel.SetSchema(a.configSchema)
+	a.statsModel = models.NewStatsSnapshotModel(db.GetConn())
+	a.syncLogModel = models.NewSyncLogModel(db.GetConn())
+	a.notificationModel = models.NewNotificationModel(db.GetConn())
+	a.approvalModel = models.NewPendingApprovalModel(db.GetConn())
+	a.usageModel = models.NewActionsUsageModel(db.GetConn())
+	a.webhookModel = models.NewWebhookModel(db.GetConn())
+	a.auditModel = models.NewAuditLogModel(db.GetConn())
+	a.usageEventModel = models.NewUsageEventModel(db.GetConn())
+	a.scorecardModel = models.NewScorecardModel(db.GetConn())
+	a.customFieldModel = models.NewCustomFieldModel(db.GetConn())
+	a.taskChecklistModel = models.NewTaskChecklistModel(db.GetConn())
+	a.annotationModel = models.NewAnnotationModel(db.GetConn())
+	a.jobModel = models.NewJobModel(db.GetConn())
+	a.cleanUpJobs()
+	a.discoveryChangeModel = models.NewDiscoveryChangeModel(db.GetConn())
+	a.envVarSnapshotModel = models.NewEnvVarSnapshotModel(db.GetConn())
+	a.securityAlertModel = models.NewSecurityAlertModel(db.GetConn())
+	a.servicePackageModel = models.NewServicePackageModel(db.GetConn())
+	a.commitTicketRefModel = models.NewCommitTicketRefModel(db.GetConn())
+	a.serviceSummaryModel = models.NewServiceSummaryModel(db.GetConn())
+	a.freezeWindowModel = models.NewFreezeWindowModel(db.GetConn())
+	a.watchRules = newWatchRuleRunner(models.NewWatchRuleModel(db.GetConn()))
+	a.sensitivePRs = newSensitivePullRequestTracker(models.NewSensitivePullRequestModel(db.GetConn()))
+	a.telemetry = newTelemetryReporter(models.NewTelemetryModel(db.GetConn()))
+	go a.flushTelemetryPeriodically()
+	go a.refreshServiceSummaries(nil)
+	a.applySlowQueryThreshold()
+	a.applyGitHubRateLimit()
+	a.applyTagPrefixes()
+	
+	// Background notifications go through the notifier so quiet hours apply to all of them
+	a.notifier = sync.NewNotifier(a.notificationModel)
+	a.applyQuietHours()
+	
+	// Packaged apps don't get the shell's GITHUB_TOKEN, so look for a token elsewhere first
+	a.bootstrapGitHubToken()
+	go a.checkGitHubTokenExpiry()
 	
 	// Initialize JIRA client if configured
 	a.initJiraClient()
 	
+	// Keep tasks' JIRA tickets fresh; the poller waits for a JIRA client to be configured
+	a.jiraPoller = sync.NewJiraPoller(a.taskModel, a.notifier, func() *jira.Client { return a.jiraClient }, func(event types.DataChangedEvent) {
+		runtime.EventsEmit(a.ctx, sync.DataChangedEventName, event)
+	})
+	a.jiraPoller.SetInterval(a.getJiraPollInterval())
+	a.jiraPoller.SetEnabled(a.jiraPollEnabled())
+	a.jiraPoller.SetFillDeadlines(a.jiraDueDateFillsDeadline())
+	a.jiraPoller.Start()
+	
 	// Initialize sync service with GitHub token from config
 	githubToken := a.getGitHubToken()
 	
 	if githubToken != "" {
 		syncConfig := sync.Config{
-			GitHubToken:         githubToken,
-			GitHubEnterpriseURL: a.getGitHubEnterpriseURL(),
-			SyncInterval:        5 * time.Minute,
+			GitHubToken:              githubToken,
+			GitHubEnterpriseURL:      a.getGitHubEnterpriseURL(),
+			GitHubClientOptions:      a.githubClientOptions(),
+			SyncInterval:             5 * time.Minute,
+			DescriptionSources:       a.getDescriptionSources(),
+			CollectActionsUsage:      a.getConfigFlag("collect_actions_usage"),
+			DomainFolders:            a.getConfigFlag(serviceDomainFoldersKey),
+			RolloutStuckAfter:        a.getRolloutStuckAfter(),
+			StuckPassMultiple:        a.getSyncStuckMultiple(),
+			TagPrefixes:              a.getTagPrefixes(),
+			FluxVersionFields:        a.getFluxVersionFields(),
+			CommitAnnotations:        a.getCommitAnnotations(),
+			EnvVarSnapshots:          a.getConfigFlag(envVarSnapshotsKey),
+			DiscoveryReviewWindow:    a.getDiscoveryReviewWindow(),
+			DiscoveryReviewAutoApply: a.discoveryReviewAutoApply(),
+			CorrelationRetryWindow:   a.getCorrelationRetryWindow(),
+			FirstDeployNotifications: a.firstDeployNotificationsEnabled(),
+			PackageVersionLookup:     a.getConfigFlag(packageVersionLookupKey),
+			OnSyncComplete:           a.onSyncComplete,
+			OnDataChanged: func(event types.DataChangedEvent) {
+				a.refreshChangedServiceSummaries(event)
+				runtime.EventsEmit(a.ctx, sync.DataChangedEventName, event)
+			},
 		}
 		
-		a.syncService = sync.NewService(syncConfig, a.repoModel, a.serviceModel, a.kubernetesModel, a.actionModel, a.deploymentModel)
+		// The sync stores the results of each phase through a connection of its own, in a transaction
+		// with the phase's checkpoint; without one its phases store them as they go
+		if writer, err := a.db.OpenWriter(); err != nil {
+			log.Printf("Failed to open the sync's database connection, syncing without transactions: %v", err)
+		} else {
+			syncConfig.Transactions = writer
+		}
+		a.syncService = sync.NewService(syncConfig, a.repoModel, a.serviceModel, a.kubernetesModel, a.actionModel, a.deploymentModel, a.statsModel, a.syncLogModel, a.notifier, a.approvalModel, a.usageModel, a.auditModel, a.discoveryChangeModel, a.envVarSnapshotModel, a.securityAlertModel, a.servicePackageModel)
 		a.syncService.Start()
 		log.Println("Background sync service started")
 	} else {
@@ -100,13 +252,42 @@ func (a *App) startup(ctx context.Context) {
 	log.Println("Dev Dashboard startup completed successfully")
 }
 
+// onSyncComplete runs at the end of each sync pass: it rescores services, refreshes the services
+// list's summaries and evaluates watch rules
+func (a *App) onSyncComplete() {
+	a.updateScorecards()
+	a.refreshServiceSummaries(nil)
+	if err := a.evaluateWatchRules(); err != nil {
+		log.Printf("Failed to evaluate watch rules: %v", err)
+	}
+}
+
+// databasePath returns the location of the SQLite database in the user's home directory
+func databasePath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".dev-dashboard", "database.db"), nil
+}
+
+// GetStartupError returns the error that prevented the database from initializing, if any
+func (a *App) GetStartupError() *types.StartupError {
+	return a.startupError
+}
+
 // Repository Management Methods
 
 func (a *App) GetRepositories() ([]*types.Repository, error) {
 	if a.repoModel == nil {
 		return []*types.Repository{}, nil
 	}
-	return a.repoModel.GetAll()
+	repos, err := a.repoModel.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	a.attachSecurityAlertCounts(repos)
+	return repos, nil
 }
 
 func (a *App) CreateRepository(repo types.Repository) error {
@@ -121,6 +302,9 @@ func (a *App) CreateRepositoryWithAuth(repoData map[string]interface{}) error {
 		Description:     repoData["description"].(string),
 		ServiceLocation: repoData["service_location"].(string),
 	}
+	if script, ok := repoData["discovery_script"].(string); ok {
+		repo.DiscoveryScript = strings.TrimSpace(script)
+	}
 
 	// Create repository first
 	err := a.repoModel.Create(&repo)
@@ -136,7 +320,7 @@ func (a *App) CreateRepositoryWithAuth(repoData map[string]interface{}) error {
 		
 		log.Printf("Auth method: %s, Service location: %s", authMethod, repo.ServiceLocation)
 		
-		services, err := a.discoverServices(repo.URL, repo.ServiceLocation, authMethod, credentials)
+		services, err := a.discoverServices(context.Background(), repo.URL, repo.ServiceLocation, authMethod, credentials)
 		if err != nil {
 			log.Printf("ERROR: Failed to discover services for repository %s: %v", repo.Name, err)
 		} else {
@@ -149,6 +333,7 @@ func (a *App) CreateRepositoryWithAuth(repoData map[string]interface{}) error {
 					Name:         service.Name,
 					Path:         service.Path,
 					Description:  service.Description,
+					Domain:       service.Domain,
 				}
 				err := a.serviceModel.Create(&microservice)
 				if err != nil {
@@ -165,11 +350,8 @@ func (a *App) CreateRepositoryWithAuth(repoData map[string]interface{}) error {
 	return nil
 }
 
-func (a *App) ValidateRepositoryAccess(url, authMethod string, credentials map[string]interface{}) map[string]interface{} {
-	result := map[string]interface{}{
-		"success": false,
-		"error":   "",
-	}
+func (a *App) ValidateRepositoryAccess(url, authMethod string, credentials map[string]interface{}) *types.ValidationResult {
+	result := &types.ValidationResult{}
 
 	ctx := context.Background()
 
@@ -179,15 +361,15 @@ func (a *App) ValidateRepositoryAccess(url, authMethod string, credentials map[s
 			// Use globally configured GitHub token
 			token = a.getGitHubToken()
 			if token == "" {
-				result["error"] = "GitHub token is required - please configure it in Settings"
+				result.Error = "GitHub token is required - please configure it in Settings"
 				return result
 			}
 		}
 
 		// Extract owner and repo from URL
-		owner, repoName, err := a.parseGitHubURL(url)
+		owner, repoName, err := vcs.ParseGitHubURL(url)
 		if err != nil {
-			result["error"] = fmt.Sprintf("Invalid GitHub URL: %v", err)
+			result.Error = fmt.Sprintf("Invalid GitHub URL: %v", err)
 			return result
 		}
 
@@ -195,20 +377,20 @@ func (a *App) ValidateRepositoryAccess(url, authMethod string, credentials map[s
 		client := a.createGitHubClient(token)
 		_, _, err = client.Repositories.Get(ctx, owner, repoName)
 		if err != nil {
-			result["error"] = fmt.Sprintf("Cannot access repository: %v", err)
+			result.Error = fmt.Sprintf("Cannot access repository: %v", err)
 			return result
 		}
 
-		result["success"] = true
+		result.Success = true
 	} else {
-		result["error"] = "Only GitHub Personal Access Token authentication is supported"
+		result.Error = "Only GitHub Personal Access Token authentication is supported"
 	}
 
 	return result
 }
 
-func (a *App) DiscoverRepositoryServices(url, serviceLocation, authMethod string, credentials map[string]interface{}) []map[string]interface{} {
-	services := []map[string]interface{}{}
+func (a *App) DiscoverRepositoryServices(url, serviceLocation, authMethod string, credentials map[string]interface{}) []*types.DiscoveredService {
+	services := []*types.DiscoveredService{}
 
 	ctx := context.Background()
 
@@ -224,9 +406,11 @@ func (a *App) DiscoverRepositoryServices(url, serviceLocation, authMethod string
 
 		// Create GitHub client with Enterprise support
 		enterpriseURL := a.getGitHubEnterpriseURL()
-		githubClient := github.NewClientWithBaseURL(token, enterpriseURL)
+		githubClient := github.NewClientWithBaseURL(token, enterpriseURL, a.githubClientOptions()...)
+		githubClient.SetDescriptionSources(a.getDescriptionSources())
+		githubClient.SetDomainFolders(a.getConfigFlag(serviceDomainFoldersKey))
 		
-		owner, repo, err := githubClient.ParseRepositoryURL(url)
+		owner, repo, err := vcs.ParseGitHubURL(url)
 		if err != nil {
 			return services
 		}
@@ -238,10 +422,11 @@ func (a *App) DiscoverRepositoryServices(url, serviceLocation, authMethod string
 		}
 
 		for _, service := range discoveredServices {
-			services = append(services, map[string]interface{}{
-				"name":        service.Name,
-				"path":        service.Path,
-				"description": service.Description,
+			services = append(services, &types.DiscoveredService{
+				Name:        service.Name,
+				Path:        service.Path,
+				Description: service.Description,
+				Domain:      service.Domain,
 			})
 		}
 	} else {
@@ -251,41 +436,13 @@ func (a *App) DiscoverRepositoryServices(url, serviceLocation, authMethod string
 	return services
 }
 
-// Helper methods for repository operations
-func (a *App) parseGitHubURL(url string) (owner, repo string, err error) {
-	// Create a GitHub client to use its URL parsing capabilities
-	githubToken := a.getGitHubToken()
-	enterpriseURL := a.getGitHubEnterpriseURL()
-	
-	githubClient := github.NewClientWithBaseURL(githubToken, enterpriseURL)
-	return githubClient.ParseRepositoryURL(url)
-}
-
 func (a *App) createGitHubClient(token string) *goGithub.Client {
-	// Get Enterprise configuration
-	enterpriseURL := a.getGitHubEnterpriseURL()
-	
-	ts := oauth2.StaticTokenSource(
-		&oauth2.Token{AccessToken: token},
-	)
-	tc := oauth2.NewClient(context.Background(), ts)
-	
-	if enterpriseURL != "" {
-		client, err := goGithub.NewEnterpriseClient(enterpriseURL, enterpriseURL, tc)
-		if err != nil {
-			log.Printf("Failed to create Enterprise GitHub client: %v", err)
-			return goGithub.NewClient(tc)
-		}
-		return client
-	}
-	
-	return goGithub.NewClient(tc)
+	// Same Enterprise and API base URL configuration as the internal client
+	return github.NewClientWithBaseURL(token, a.getGitHubEnterpriseURL(), a.githubClientOptions()...).GetGitHubClient()
 }
 
 
-func (a *App) discoverServices(url, serviceLocation, authMethod string, credentials map[string]interface{}) ([]github.ServiceInfo, error) {
-	ctx := context.Background()
-
+func (a *App) discoverServices(ctx context.Context, url, serviceLocation, authMethod string, credentials map[string]interface{}) ([]github.ServiceInfo, error) {
 	log.Printf("Starting service discovery for %s using %s auth", url, authMethod)
 
 	if authMethod == "pat" {
@@ -303,9 +460,11 @@ func (a *App) discoverServices(url, serviceLocation, authMethod string, credenti
 
 		// Create GitHub client with Enterprise support
 		enterpriseURL := a.getGitHubEnterpriseURL()
-		githubClient := github.NewClientWithBaseURL(token, enterpriseURL)
+		githubClient := github.NewClientWithBaseURL(token, enterpriseURL, a.githubClientOptions()...)
+		githubClient.SetDescriptionSources(a.getDescriptionSources())
+		githubClient.SetDomainFolders(a.getConfigFlag(serviceDomainFoldersKey))
 		
-		owner, repo, err := githubClient.ParseRepositoryURL(url)
+		owner, repo, err := vcs.ParseGitHubURL(url)
 		if err != nil {
 			log.Printf("ERROR: Failed to parse GitHub URL %s: %v", url, err)
 			return nil, err
@@ -336,15 +495,78 @@ func (a *App) UpdateRepository(repo types.Repository) error {
 	return a.repoModel.Update(&repo)
 }
 
+// SetRepositoryArchived archives a repository, leaving it out of syncs while keeping its data,
+// or makes an archived or unreachable repository active again
+func (a *App) SetRepositoryArchived(id int64, archived bool) error {
+	if a.repoModel == nil {
+		return fmt.Errorf("repository model not initialized")
+	}
+	if archived {
+		return a.repoModel.UpdateStatus(id, types.RepositoryArchived)
+	}
+	return a.repoModel.UpdateStatus(id, types.RepositoryActive)
+}
+
+// SetRepositoryManualSyncOnly sets whether a repository is left out of scheduled syncs; SyncRepository
+// still syncs it on demand
+func (a *App) SetRepositoryManualSyncOnly(id int64, manual bool) error {
+	if a.repoModel == nil {
+		return fmt.Errorf("repository model not initialized")
+	}
+	return a.repoModel.SetManualSyncOnly(id, manual)
+}
+
+// ToggleFavorite stars or unstars a repository and returns whether it's now a favorite. Favorites
+// come first in GetRepositories and make up GetFavoriteRepositories.
+func (a *App) ToggleFavorite(id int64) (bool, error) {
+	if a.repoModel == nil {
+		return false, fmt.Errorf("repository model not initialized")
+	}
+	return a.repoModel.ToggleFavorite(id)
+}
+
+// GetFavoriteRepositories returns the favorite repositories, for the quick access list in the nav
+func (a *App) GetFavoriteRepositories() ([]*types.Repository, error) {
+	repos, err := a.GetRepositories()
+	if err != nil {
+		return nil, err
+	}
+	favorites := []*types.Repository{}
+	for _, repo := range repos {
+		if repo.IsFavorite {
+			favorites = append(favorites, repo)
+		}
+	}
+	return favorites, nil
+}
+
 func (a *App) DeleteRepository(id int64) error {
 	return a.repoModel.Delete(id)
 }
 
-func (a *App) SyncRepository(id int64) error {
+// SyncRepository syncs a repository now. When it's already syncing, e.g. in the scheduled pass or
+// from an earlier click, it returns SyncOutcomeAlreadyRunning instead of starting a second sync.
+func (a *App) SyncRepository(id int64) (types.SyncOutcome, error) {
 	if a.syncService == nil {
-		return fmt.Errorf("sync service not initialized - GitHub token required")
+		return "", fmt.Errorf("sync service not initialized - GitHub token required")
+	}
+	a.recordFeature(telemetry.ManualSync)
+	// Manual syncs always rescan so newly added services get matched against unchanged deployments
+	if err := a.syncService.ResyncRepository(id); err != nil {
+		if errors.Is(err, sync.ErrSyncInProgress) {
+			return types.SyncOutcomeAlreadyRunning, nil
+		}
+		return "", err
+	}
+	return types.SyncOutcomeCompleted, nil
+}
+
+// GetSyncLogs returns the most recent sync log entries for a repository
+func (a *App) GetSyncLogs(repositoryID int64, limit int) ([]*types.SyncLog, error) {
+	if a.syncLogModel == nil {
+		return nil, fmt.Errorf("sync log model not initialized")
 	}
-	return a.syncService.SyncRepository(id)
+	return a.syncLogModel.GetByRepositoryID(repositoryID, limit)
 }
 
 func (a *App) RediscoverRepositoryServices(id int64, authMethod string, credentials map[string]interface{}) error {
@@ -354,21 +576,60 @@ func (a *App) RediscoverRepositoryServices(id int64, authMethod string, credenti
 		return fmt.Errorf("failed to get repository: %w", err)
 	}
 
+	_, err = a.rediscoverRepositoryServices(context.Background(), repo, authMethod, credentials)
+	return err
+}
+
+// rediscoverRepositoryServices rediscovers the services of a monorepo, keeping the IDs of the ones it
+// already had, and returns how many were discovered, added, updated and removed. Discovery stops
+// when ctx is cancelled. Repositories with a discovery script or in review mode are refused, and so
+// is a repository being synced.
+func (a *App) rediscoverRepositoryServices(ctx context.Context, repo *types.Repository, authMethod string, credentials map[string]interface{}) (*types.ServiceRediscovery, error) {
 	if repo.Type != types.MonorepoType {
-		return fmt.Errorf("repository is not a monorepo")
+		return nil, fmt.Errorf("repository is not a monorepo")
 	}
 
 	log.Printf("Rediscovering services for repository %s (%s)", repo.Name, repo.URL)
 
 	// Only support PAT authentication
 	if authMethod != "pat" {
-		return fmt.Errorf("only GitHub PAT authentication is supported")
+		return nil, fmt.Errorf("only GitHub PAT authentication is supported")
+	}
+
+	// Discovery scripts and review mode are only honoured by the sync, so leave those repositories to it
+	if repo.DiscoveryScript != "" {
+		return nil, fmt.Errorf("services are discovered by the repository's discovery script; sync it to rediscover them")
+	}
+	if repo.DiscoveryReview {
+		return nil, fmt.Errorf("discovered services wait for review; sync the repository to rediscover them")
+	}
+
+	// Without the sync service nothing else writes the repository's services
+	if a.syncService == nil {
+		return a.rediscoverClaimedRepositoryServices(ctx, repo, authMethod, credentials)
 	}
+	// Claim the repository so a sync of it doesn't write its services at the same time
+	var result *types.ServiceRediscovery
+	err := a.syncService.WithRepositoryClaimed(ctx, repo.ID, func() error {
+		var err error
+		result, err = a.rediscoverClaimedRepositoryServices(ctx, repo, authMethod, credentials)
+		return err
+	})
+	return result, err
+}
 
+// rediscoverClaimedRepositoryServices is rediscoverRepositoryServices once the repository is claimed
+func (a *App) rediscoverClaimedRepositoryServices(ctx context.Context, repo *types.Repository, authMethod string, credentials map[string]interface{}) (*types.ServiceRediscovery, error) {
 	// Discover services using the provided credentials
-	discoveredServices, err := a.discoverServices(repo.URL, repo.ServiceLocation, authMethod, credentials)
+	discoveredServices, err := a.discoverServices(ctx, repo.URL, repo.ServiceLocation, authMethod, credentials)
 	if err != nil {
-		return fmt.Errorf("failed to discover services: %w", err)
+		return nil, fmt.Errorf("failed to discover services: %w", err)
+	}
+
+	// Description lookups fail silently, so a discovery cancelled part way returns services without
+	// their descriptions; don't store those
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
 
 	log.Printf("Discovered %d services for repository %s", len(discoveredServices), repo.Name)
@@ -381,52 +642,98 @@ func (a *App) RediscoverRepositoryServices(id int64, authMethod string, credenti
 			Name:         service.Name,
 			Path:         service.Path,
 			Description:  service.Description,
+			Domain:       service.Domain,
 		})
 	}
 
 	// Upsert services preserving existing IDs
-	err = a.serviceModel.UpsertServicesPreserveID(id, microservices)
+	changes, err := a.serviceModel.UpsertServicesCountingChanges(repo.ID, microservices)
 	if err != nil {
-		return fmt.Errorf("failed to upsert services: %w", err)
+		return nil, fmt.Errorf("failed to upsert services: %w", err)
 	}
 
 	log.Printf("Successfully updated services for repository %s", repo.Name)
 
-	return nil
+	return &types.ServiceRediscovery{
+		RepositoryID:   repo.ID,
+		RepositoryName: repo.Name,
+		Services:       len(discoveredServices),
+		Added:          changes.Added,
+		Updated:        changes.Updated,
+		Removed:        changes.Removed,
+	}, nil
 }
 
 // Microservice Management Methods
 
-func (a *App) GetMicroservices(repositoryID int64) ([]*types.Microservice, error) {
+// GetMicroservices returns the services of a repository, or of all monorepos when repositoryID is 0,
+// with their custom field values. Hidden services are only included when includeHidden is set.
+func (a *App) GetMicroservices(repositoryID int64, includeHidden bool) ([]*types.Microservice, error) {
 	if repositoryID == 0 {
-		// Return all microservices from all repositories
-		repos, err := a.repoModel.GetAll()
+		withRepository, err := a.GetMicroservicesWithRepository(includeHidden)
 		if err != nil {
 			return nil, err
 		}
-		
-		var allServices []*types.Microservice
-		for _, repo := range repos {
-			// Only include services from actual monorepo repositories (exclude kubernetes repositories)
-			if repo.Type == types.MonorepoType && !a.isKubernetesRepository(repo) {
-				services, err := a.serviceModel.GetByRepositoryID(repo.ID)
-				if err != nil {
-					continue
-				}
-				allServices = append(allServices, services...)
-			}
+		allServices := make([]*types.Microservice, len(withRepository))
+		for i, service := range withRepository {
+			allServices[i] = &service.Microservice
 		}
 		return allServices, nil
 	}
 	
-	return a.serviceModel.GetByRepositoryID(repositoryID)
+	services, err := a.serviceModel.GetByRepositoryID(repositoryID, includeHidden)
+	if err != nil {
+		return nil, err
+	}
+	a.attachServiceCustomFields(services)
+	return services, nil
+}
+
+// GetMicroservicesWithRepository returns the services of all monorepos with the name, URL and type
+// of their repository and their custom field values, read in one query however many repositories
+// there are. Hidden services are only included when includeHidden is set.
+func (a *App) GetMicroservicesWithRepository(includeHidden bool) ([]*types.MicroserviceWithRepository, error) {
+	if a.serviceModel == nil {
+		return nil, fmt.Errorf("service model not initialized")
+	}
+	services, err := a.serviceModel.GetAllWithRepository(includeHidden)
+	if err != nil {
+		return nil, err
+	}
+	plain := make([]*types.Microservice, len(services))
+	for i, service := range services {
+		plain[i] = &service.Microservice
+	}
+	a.attachServiceCustomFields(plain)
+	return services, nil
+}
+
+// HideMicroservice hides a service from the default service list without deleting it
+func (a *App) HideMicroservice(serviceID int64) error {
+	if a.serviceModel == nil {
+		return fmt.Errorf("service model not initialized")
+	}
+	return a.serviceModel.SetHidden(serviceID, true)
+}
+
+// UnhideMicroservice makes a hidden service visible again
+func (a *App) UnhideMicroservice(serviceID int64) error {
+	if a.serviceModel == nil {
+		return fmt.Errorf("service model not initialized")
+	}
+	return a.serviceModel.SetHidden(serviceID, false)
 }
 
 func (a *App) GetMicroserviceActions(serviceID int64, limit int) ([]*types.Action, error) {
 	if limit == 0 {
 		limit = 50
 	}
-	return a.actionModel.GetByServiceID(serviceID, limit)
+	actions, err := a.actionModel.GetByServiceID(serviceID, limit)
+	if err != nil {
+		return nil, err
+	}
+	a.displayClock().annotateActions(actions)
+	return actions, nil
 }
 
 // GetServicePullRequests returns service-specific pull requests from GitHub
@@ -443,24 +750,34 @@ func (a *App) GetServicePullRequests(serviceID int64) ([]*types.PullRequest, err
 		return nil, err
 	}
 	
+	prs, err := a.fetchServicePullRequests(context.Background(), service, repo)
+	if err != nil {
+		log.Printf("Failed to fetch pull requests for service %s: %v", service.Name, err)
+		return []*types.PullRequest{}, nil
+	}
+	a.serviceDataCache.putPullRequests(serviceID, prs)
+	a.summarizePullRequests(serviceID, prs)
+	
+	return prs, nil
+}
+
+// fetchServicePullRequests lists the pull requests of a service's repository that touch the service directory
+func (a *App) fetchServicePullRequests(ctx context.Context, service *types.Microservice, repo *types.Repository) ([]*types.PullRequest, error) {
 	// Create GitHub client if we have a token
 	githubToken := a.getGitHubToken()
 	if githubToken == "" {
 		return []*types.PullRequest{}, nil // Return empty list if no token
 	}
 	
-	ctx := context.Background()
 	client := a.createGitHubClient(githubToken)
 	
 	// Parse repository URL to get owner and repo name
-	owner, repoName, err := a.parseGitHubURL(repo.URL)
+	owner, repoName, err := vcs.ParseGitHubURL(repo.URL)
 	if err != nil {
-		log.Printf("Failed to parse repository URL %s: %v", repo.URL, err)
-		return []*types.PullRequest{}, nil
+		return nil, fmt.Errorf("failed to parse repository URL %s: %w", repo.URL, err)
 	}
 	if owner == "" || repoName == "" {
-		log.Printf("Empty owner or repo name for URL %s", repo.URL)
-		return []*types.PullRequest{}, nil
+		return nil, fmt.Errorf("empty owner or repo name for URL %s", repo.URL)
 	}
 	
 	// Get pull requests
@@ -470,12 +787,13 @@ func (a *App) GetServicePullRequests(serviceID int64) ([]*types.PullRequest, err
 		ListOptions: goGithub.ListOptions{PerPage: 50},
 	})
 	if err != nil {
-		log.Printf("Failed to fetch pull requests for %s/%s: %v", owner, repoName, err)
-		return []*types.PullRequest{}, nil
+		return nil, fmt.Errorf("failed to fetch pull requests for %s/%s: %w", owner, repoName, err)
 	}
 	
 	log.Printf("Found %d total PRs for repository %s/%s", len(prs), owner, repoName)
 	
+	sensitivePaths := a.sensitivePathMatcher(service)
+	
 	// Filter PRs that affect the service directory
 	var servicePRs []*types.PullRequest
 	for _, pr := range prs {
@@ -484,17 +802,20 @@ func (a *App) GetServicePullRequests(serviceID int64) ([]*types.PullRequest, err
 		}
 		
 		// Get files changed in this PR
-		files, _, err := client.PullRequests.ListFiles(ctx, owner, repoName, *pr.Number, nil)
+		files, _, err := client.PullRequests.ListFiles(ctx, owner, repoName, *pr.Number, &goGithub.ListOptions{PerPage: 100})
 		if err != nil {
 			continue
 		}
 		
 		// Check if any files in the service directory were changed
 		serviceAffected := false
+		fileNames := make([]string, 0, len(files))
 		for _, file := range files {
-			if file.Filename != nil && strings.HasPrefix(*file.Filename, service.Path) {
-				serviceAffected = true
-				break
+			if file.Filename != nil {
+				fileNames = append(fileNames, *file.Filename)
+				if vcs.PathWithin(*file.Filename, service.Path) {
+					serviceAffected = true
+				}
 			}
 		}
 		
@@ -527,7 +848,7 @@ func (a *App) GetServicePullRequests(serviceID int64) ([]*types.PullRequest, err
 				createdAt = pr.CreatedAt.Time
 			}
 			
-			servicePRs = append(servicePRs, &types.PullRequest{
+			servicePR := &types.PullRequest{
 				ID:        int64(*pr.Number),
 				Number:    *pr.Number,
 				Title:     title,
@@ -535,10 +856,22 @@ func (a *App) GetServicePullRequests(serviceID int64) ([]*types.PullRequest, err
 				Author:    author,
 				Branch:    branch,
 				CreatedAt: createdAt,
-			})
+			}
+			if sensitivePaths != nil && status == "open" {
+				servicePR.SensitiveFiles = sensitivePaths.match(fileNames)
+				servicePR.Sensitive = len(servicePR.SensitiveFiles) > 0
+			}
+			servicePRs = append(servicePRs, servicePR)
 		}
 	}
 	
+	// File listings fail silently above, so don't pass off a cancelled fetch as a complete one
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	
+	a.flagSensitivePullRequests(service, servicePRs)
+	
 	return servicePRs, nil
 }
 
@@ -556,24 +889,39 @@ func (a *App) GetServiceCommits(serviceID int64) ([]*types.Commit, error) {
 		return nil, err
 	}
 	
+	commits, err := a.fetchServiceCommits(context.Background(), service, repo)
+	if err != nil {
+		log.Printf("Failed to fetch commits for service %s: %v", service.Name, err)
+		return []*types.Commit{}, nil
+	}
+	a.serviceDataCache.putCommits(serviceID, commits)
+	a.recordCommitTicketRefs(repo.ID, commits)
+	a.summarizeCommits(serviceID, commits)
+	
+	// Attach notes to the copies, so the cache doesn't keep stale ones
+	annotated := a.displayClock().annotateCommits(commits)
+	a.attachCommitAnnotations(annotated)
+	a.attachCommitTickets(annotated)
+	return annotated, nil
+}
+
+// fetchServiceCommits lists the commits touching a service directory plus the commits its deployments run
+func (a *App) fetchServiceCommits(ctx context.Context, service *types.Microservice, repo *types.Repository) ([]*types.Commit, error) {
 	// Create GitHub client if we have a token
 	githubToken := a.getGitHubToken()
 	if githubToken == "" {
 		return []*types.Commit{}, nil // Return empty list if no token
 	}
 	
-	ctx := context.Background()
 	client := a.createGitHubClient(githubToken)
 	
 	// Parse repository URL to get owner and repo name
-	owner, repoName, err := a.parseGitHubURL(repo.URL)
+	owner, repoName, err := vcs.ParseGitHubURL(repo.URL)
 	if err != nil {
-		log.Printf("Failed to parse repository URL %s: %v", repo.URL, err)
-		return []*types.Commit{}, nil
+		return nil, fmt.Errorf("failed to parse repository URL %s: %w", repo.URL, err)
 	}
 	if owner == "" || repoName == "" {
-		log.Printf("Empty owner or repo name for URL %s", repo.URL)
-		return []*types.Commit{}, nil
+		return nil, fmt.Errorf("empty owner or repo name for URL %s", repo.URL)
 	}
 	
 	// Get commits for the service directory
@@ -583,13 +931,12 @@ func (a *App) GetServiceCommits(serviceID int64) ([]*types.Commit, error) {
 		ListOptions: goGithub.ListOptions{PerPage: 50},
 	})
 	if err != nil {
-		log.Printf("Failed to fetch commits for %s/%s path %s: %v", owner, repoName, service.Path, err)
-		return []*types.Commit{}, nil
+		return nil, fmt.Errorf("failed to fetch commits for %s/%s path %s: %w", owner, repoName, service.Path, err)
 	}
 	
 	// Also get deployment commits that might not have touched the service path
 	// but are specifically for this service
-	deployments, err := a.deploymentModel.GetByServiceID(serviceID)
+	deployments, err := a.deploymentModel.GetByServiceID(service.ID)
 	if err == nil && len(deployments) > 0 {
 		commitSHASet := make(map[string]bool)
 		for _, commit := range commits {
@@ -655,6 +1002,8 @@ func (a *App) GetServiceCommits(serviceID int64) ([]*types.Commit, error) {
 		})
 	}
 	
+	a.linkCommitPullRequests(ctx, client, owner, repoName, serviceCommits)
+	
 	return serviceCommits, nil
 }
 
@@ -688,11 +1037,61 @@ func (a *App) GetKubernetesResourceActions(resourceID int64, limit int) ([]*type
 	if limit == 0 {
 		limit = 50
 	}
-	return a.actionModel.GetByResourceID(resourceID, limit)
+	actions, err := a.actionModel.GetByResourceID(resourceID, limit)
+	if err != nil {
+		return nil, err
+	}
+	a.displayClock().annotateActions(actions)
+	return actions, nil
 }
 
 // Deployment Management Methods
 
+// GetServiceDeploymentCounts returns, keyed by service ID, how many distinct targets each service is
+// deployed to per environment, with environments in the configured environment_order
+func (a *App) GetServiceDeploymentCounts() (map[int64]*types.ServiceDeploymentCounts, error) {
+	if a.deploymentModel == nil {
+		return nil, fmt.Errorf("deployment model not initialized")
+	}
+	
+	targetCounts, err := a.deploymentModel.GetTargetCounts()
+	if err != nil {
+		return nil, err
+	}
+	
+	result := make(map[int64]*types.ServiceDeploymentCounts, len(targetCounts))
+	for serviceID, counts := range targetCounts {
+		environments := make([]string, 0, len(counts))
+		for environment := range counts {
+			environments = append(environments, environment)
+		}
+		a.sortEnvironments(environments)
+		
+		result[serviceID] = &types.ServiceDeploymentCounts{
+			ServiceID:    serviceID,
+			Counts:       counts,
+			Environments: environments,
+		}
+	}
+	
+	return result, nil
+}
+
+// GetDeploymentDimensions returns the distinct environments, regions and namespaces across all
+// deployments, for filter dropdowns. Environments follow the configured environment_order.
+func (a *App) GetDeploymentDimensions() (*types.DeploymentDimensions, error) {
+	if a.deploymentModel == nil {
+		return nil, fmt.Errorf("deployment model not initialized")
+	}
+	
+	dimensions, err := a.deploymentModel.GetDimensions()
+	if err != nil {
+		return nil, err
+	}
+	a.sortEnvironments(dimensions.Environments)
+	return dimensions, nil
+}
+
 func (a *App) GetServiceDeployments(serviceID int64) ([]*types.DeploymentOverview, error) {
 	log.Printf("GetServiceDeployments called with serviceID: %d", serviceID)
 	if a.deploymentModel == nil {
@@ -705,11 +1104,15 @@ func (a *App) GetServiceDeployments(serviceID int64) ([]*types.DeploymentOvervie
 		return nil, err
 	}
 	log.Printf("Successfully retrieved %d deployments for service %d", len(deployments), serviceID)
+	a.displayClock().annotateDeployments(deployments)
+	a.attachDeploymentChecks(serviceID, deployments)
+	a.attachDeploymentStaleness(serviceID, deployments)
 	return deployments, nil
 }
 
 func (a *App) GetServiceCommitDeployments(serviceID int64) ([]*types.CommitDeploymentStatus, error) {
 	log.Printf("GetServiceCommitDeployments called with serviceID: %d", serviceID)
+	a.recordUsage(types.UsageDeploymentMatrixViewed, &serviceID)
 	
 	// Get service commits first
 	commits, err := a.GetServiceCommits(serviceID)
@@ -726,6 +1129,17 @@ func (a *App) GetServiceCommitDeployments(serviceID int64) ([]*types.CommitDeplo
 	}
 	log.Printf("Found %d deployments for service %d", len(deployments), serviceID)
 	
+	result := buildCommitDeployments(commits, deployments)
+	
+	log.Printf("Successfully retrieved %d commit deployment statuses for service %d", len(result), serviceID)
+	a.displayClock().annotateCommitDeployments(result)
+	a.annotateCommitDeploymentStaleness(result)
+	return result, nil
+}
+
+// buildCommitDeployments marks for each commit where it is deployed, listing every known
+// environment/region/namespace as not deployed for commits that aren't running anywhere
+func buildCommitDeployments(commits []*types.Commit, deployments []*types.Deployment) []*types.CommitDeploymentStatus {
 	// Create a map of commit SHA to deployments
 	commitDeploymentMap := make(map[string][]*types.Deployment)
 	for _, deployment := range deployments {
@@ -762,7 +1176,7 @@ func (a *App) GetServiceCommitDeployments(serviceID int64) ([]*types.CommitDeplo
 					Namespace:   deployment.Namespace,
 					Tag:         deployment.Tag,
 					IsDeployed:  true,
-					DeployedAt:  deployment.UpdatedAt,
+					DeployedAt:  &deployment.UpdatedAt,
 				}
 				commitStatus.Deployments = append(commitStatus.Deployments, deploymentStatus)
 			}
@@ -778,7 +1192,6 @@ func (a *App) GetServiceCommitDeployments(serviceID int64) ([]*types.CommitDeplo
 						Namespace:   parts[2],
 						Tag:         "",
 						IsDeployed:  false,
-						DeployedAt:  time.Time{},
 					}
 					commitStatus.Deployments = append(commitStatus.Deployments, deploymentStatus)
 				}
@@ -788,83 +1201,6 @@ func (a *App) GetServiceCommitDeployments(serviceID int64) ([]*types.CommitDeplo
 		result = append(result, commitStatus)
 	}
 	
-	log.Printf("Successfully retrieved %d commit deployment statuses for service %d", len(result), serviceID)
-	return result, nil
-}
-
-// TestServiceCommitsFetch is a debug method to test GetServiceCommits specifically
-func (a *App) TestServiceCommitsFetch(serviceID int64) string {
-	log.Printf("TestServiceCommitsFetch called with serviceID: %d", serviceID)
-	
-	// Get service details
-	service, err := a.serviceModel.GetByID(serviceID)
-	if err != nil {
-		return fmt.Sprintf("ERROR getting service: %v", err)
-	}
-	
-	// Get repository details
-	repo, err := a.repoModel.GetByID(service.RepositoryID)
-	if err != nil {
-		return fmt.Sprintf("ERROR getting repository: %v", err)
-	}
-	
-	// Check GitHub token
-	githubToken := a.getGitHubToken()
-	tokenStatus := "configured"
-	if githubToken == "" {
-		tokenStatus = "missing"
-	}
-	
-	result := fmt.Sprintf("Service: %s (ID: %d)\n", service.Name, serviceID)
-	result += fmt.Sprintf("Path: %s\n", service.Path)
-	result += fmt.Sprintf("Repository: %s (ID: %d)\n", repo.Name, repo.ID)
-	result += fmt.Sprintf("Repository URL: %s\n", repo.URL)
-	result += fmt.Sprintf("GitHub token: %s\n", tokenStatus)
-	
-	// Get commits
-	commits, err := a.GetServiceCommits(serviceID)
-	if err != nil {
-		result += fmt.Sprintf("ERROR getting commits: %v\n", err)
-	} else {
-		result += fmt.Sprintf("Found %d commits:\n", len(commits))
-		for i, commit := range commits {
-			if len(commit.Message) > 50 {
-				result += fmt.Sprintf("  %d: %s - %s...\n", i, commit.Hash[:7], commit.Message[:47])
-			} else {
-				result += fmt.Sprintf("  %d: %s - %s\n", i, commit.Hash[:7], commit.Message)
-			}
-		}
-	}
-	
-	return result
-}
-
-// TestCommitDeploymentCorrelation is a debug method to test the correlation logic
-func (a *App) TestCommitDeploymentCorrelation(serviceID int64) string {
-	log.Printf("TestCommitDeploymentCorrelation called with serviceID: %d", serviceID)
-	
-	// Get service commits
-	commits, err := a.GetServiceCommits(serviceID)
-	if err != nil {
-		return fmt.Sprintf("ERROR getting commits: %v", err)
-	}
-	
-	// Get deployments
-	deployments, err := a.deploymentModel.GetByServiceID(serviceID)
-	if err != nil {
-		return fmt.Sprintf("ERROR getting deployments: %v", err)
-	}
-	
-	result := fmt.Sprintf("Found %d commits and %d deployments\n", len(commits), len(deployments))
-	result += "Commits:\n"
-	for i, commit := range commits {
-		result += fmt.Sprintf("  %d: %s - %s\n", i, commit.Hash[:7], commit.Message[:50])
-	}
-	result += "Deployments:\n"
-	for i, deployment := range deployments {
-		result += fmt.Sprintf("  %d: %s in %s/%s/%s\n", i, deployment.CommitSHA[:7], deployment.Environment, deployment.Region, deployment.Namespace)
-	}
-	
 	return result
 }
 
@@ -882,7 +1218,7 @@ func (a *App) GetServiceDeploymentHistory(serviceID int64) ([]*types.Commit, err
 	}
 
 	// Parse GitHub URL to get owner and repo name
-	owner, repoName, err := a.parseGitHubURL(repo.URL)
+	owner, repoName, err := vcs.ParseGitHubURL(repo.URL)
 	if err != nil {
 		return nil, fmt.Errorf("invalid repository URL: %w", err)
 	}
@@ -938,55 +1274,435 @@ func (a *App) GetServiceDeploymentHistory(serviceID int64) ([]*types.Commit, err
 		})
 	}
 
-	return serviceCommits, nil
+	a.recordCommitTicketRefs(repo.ID, serviceCommits)
+	annotated := a.displayClock().annotateCommits(serviceCommits)
+	a.attachCommitAnnotations(annotated)
+	a.attachCommitTickets(annotated)
+	return annotated, nil
 }
 
-// Action Management Methods
+// Deployment Approval Methods
 
-func (a *App) GetRecentActions(repositoryID int64, limit int) ([]*types.ActionWithDetails, error) {
-	if limit == 0 {
-		limit = 50
+// GetPendingApprovals returns the workflow run deployments currently waiting on environment reviewers
+func (a *App) GetPendingApprovals() ([]*types.PendingApproval, error) {
+	if a.approvalModel == nil {
+		return nil, fmt.Errorf("approval model not initialized")
 	}
-	return a.actionModel.GetByRepositoryID(repositoryID, limit)
+	return a.approvalModel.GetAll()
 }
 
-// Dashboard Statistics
+// ApproveDeployment approves an action's pending deployment to the named environment.
+// Requires the write_actions_enabled config flag.
+func (a *App) ApproveDeployment(actionID int64, environmentName, comment string) error {
+	if !a.writeActionsEnabled() {
+		return fmt.Errorf("write actions are disabled - enable write_actions_enabled in settings")
+	}
+	if a.approvalModel == nil || a.actionModel == nil {
+		return fmt.Errorf("approval model not initialized")
+	}
 
-func (a *App) GetDashboardStats() (map[string]interface{}, error) {
-	if a.repoModel == nil {
-		return map[string]interface{}{
-			"repositories":       0,
-			"microservices":      0,
-			"kubernetesResources": 0,
-			"recentActions":      []*types.ActionWithDetails{},
-		}, nil
+	action, err := a.actionModel.GetByID(actionID)
+	if err != nil {
+		return err
 	}
-	
-	repos, err := a.repoModel.GetAll()
+
+	approval, err := a.approvalModel.GetByRunAndEnvironment(action.RepositoryID, action.WorkflowRunID, environmentName)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	
-	var totalServices, totalResources int
-	var recentActions []*types.ActionWithDetails
-	
-	for _, repo := range repos {
-		if repo.Type == types.MonorepoType {
-			services, err := a.serviceModel.GetByRepositoryID(repo.ID)
-			if err == nil {
-				totalServices += len(services)
-			}
-		} else if repo.Type == types.KubernetesType {
-			resources, err := a.kubernetesModel.GetByRepositoryID(repo.ID)
-			if err == nil {
-				totalResources += len(resources)
-			}
-		}
-		
-		// Get recent actions for this repo
-		actions, err := a.actionModel.GetByRepositoryID(repo.ID, 10)
-		if err == nil {
-			recentActions = append(recentActions, actions...)
+
+	repo, err := a.repoModel.GetByID(action.RepositoryID)
+	if err != nil {
+		return fmt.Errorf("repository not found: %w", err)
+	}
+
+	githubToken := a.getGitHubToken()
+	if githubToken == "" {
+		return fmt.Errorf("GitHub token not configured")
+	}
+
+	owner, repoName, err := vcs.ParseGitHubURL(repo.URL)
+	if err != nil {
+		return fmt.Errorf("invalid repository URL: %w", err)
+	}
+
+	githubClient := github.NewClientWithBaseURL(githubToken, a.getGitHubEnterpriseURL(), a.githubClientOptions()...)
+	err = githubClient.ReviewPendingDeployment(context.Background(), owner, repoName, action.WorkflowRunID, approval.EnvironmentID, "approved", comment)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Approved deployment of run %d to %s in %s", action.WorkflowRunID, environmentName, repo.Name)
+	return a.approvalModel.Delete(approval.ID)
+}
+
+// Notification Methods
+
+func (a *App) GetNotifications(unreadOnly bool, limit int) ([]*types.Notification, error) {
+	if a.notificationModel == nil {
+		return nil, fmt.Errorf("notification model not initialized")
+	}
+	return a.notificationModel.GetRecent(unreadOnly, limit)
+}
+
+func (a *App) MarkNotificationRead(id int64) error {
+	if a.notificationModel == nil {
+		return fmt.Errorf("notification model not initialized")
+	}
+	return a.notificationModel.MarkRead(id)
+}
+
+// Actions Usage Methods
+
+// GetActionsMinutesUsage summarizes the Actions minutes recorded over the last `days` days.
+// Usage is only collected when the collect_actions_usage config flag is enabled.
+func (a *App) GetActionsMinutesUsage(days int) (*types.ActionsUsageSummary, error) {
+	if a.usageModel == nil {
+		return nil, fmt.Errorf("usage model not initialized")
+	}
+	if days <= 0 {
+		return nil, fmt.Errorf("days must be positive")
+	}
+
+	summary, err := a.usageModel.GetSummary(time.Now().AddDate(0, 0, -days), 10)
+	if err != nil {
+		return nil, err
+	}
+	summary.Days = days
+	return summary, nil
+}
+
+// Statistics Trend Methods
+
+// GetStatsTrend returns one point per day for the last `days` days of a stats metric.
+// The series starts at the first recorded snapshot; days the app wasn't running have a nil value.
+func (a *App) GetStatsTrend(metric string, days int) ([]types.StatsTrendPoint, error) {
+	if a.statsModel == nil {
+		return nil, fmt.Errorf("stats model not initialized")
+	}
+	if days <= 0 {
+		return nil, fmt.Errorf("days must be positive")
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	start := today.AddDate(0, 0, -(days - 1))
+
+	first, err := a.statsModel.GetFirstSnapshotDate()
+	if err != nil {
+		return nil, err
+	}
+	if first == nil {
+		return []types.StatsTrendPoint{}, nil
+	}
+	if first.After(start) {
+		start = *first
+	}
+
+	values, err := a.statsModel.GetMetricSince(metric, start)
+	if err != nil {
+		return nil, err
+	}
+
+	points := []types.StatsTrendPoint{}
+	for day := start; !day.After(today); day = day.AddDate(0, 0, 1) {
+		date := day.Format(models.StatsDateFormat)
+		point := types.StatsTrendPoint{Date: date}
+		if value, ok := values[date]; ok {
+			point.Value = &value
+		}
+		points = append(points, point)
+	}
+
+	return points, nil
+}
+
+// Deployment Metrics Methods
+
+// GetServiceLeadTime computes the median time from commit to production deployment for a service,
+// using commits to the service path since the given time and the recorded deployment history.
+// Production is the service's primary environment.
+// Commits that haven't reached production yet are reported as open and excluded from the median.
+func (a *App) GetServiceLeadTime(serviceID int64, since time.Time) (*types.LeadTimeStats, error) {
+	if a.deploymentModel == nil {
+		return nil, fmt.Errorf("deployment model not initialized")
+	}
+
+	service, err := a.serviceModel.GetByID(serviceID)
+	if err != nil {
+		return nil, fmt.Errorf("service not found: %w", err)
+	}
+
+	repo, err := a.repoModel.GetByID(service.RepositoryID)
+	if err != nil {
+		return nil, fmt.Errorf("repository not found: %w", err)
+	}
+
+	githubToken := a.getGitHubToken()
+	if githubToken == "" {
+		return nil, fmt.Errorf("GitHub token not configured")
+	}
+
+	owner, repoName, err := vcs.ParseGitHubURL(repo.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid repository URL: %w", err)
+	}
+
+	ctx := context.Background()
+	client := a.createGitHubClient(githubToken)
+
+	commits, err := a.listServiceCommitsSince(ctx, client, owner, repoName, service.Path, since)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := a.deploymentModel.GetHistoryByServiceID(serviceID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	primary := a.getPrimaryEnvironment(serviceID)
+	var prodHistory []*types.DeploymentHistoryEntry
+	for _, entry := range history {
+		if isPrimaryEnvironment(primary, entry.Environment) {
+			prodHistory = append(prodHistory, entry)
+		}
+	}
+
+	// Deployed commits may not have touched the service path, so look up their dates separately
+	commitDates := make(map[string]time.Time)
+	for _, commit := range commits {
+		commitDates[commit.Hash] = commit.Date
+	}
+	for _, entry := range prodHistory {
+		if _, ok := commitDates[entry.CommitSHA]; ok || entry.CommitSHA == "" {
+			continue
+		}
+		commit, _, err := client.Repositories.GetCommit(ctx, owner, repoName, entry.CommitSHA, nil)
+		if err != nil {
+			log.Printf("Failed to fetch deployed commit %s: %v", entry.CommitSHA, err)
+			continue
+		}
+		if converted := toCommit(commit); converted != nil {
+			commitDates[entry.CommitSHA] = converted.Date
+		}
+	}
+
+	stats := &types.LeadTimeStats{
+		ServiceID: serviceID,
+		Since:     since,
+		Commits:   computeLeadTimes(commits, prodHistory, commitDates),
+	}
+
+	var leadTimes []int64
+	for _, commit := range stats.Commits {
+		if commit.LeadTimeSeconds == nil {
+			stats.OpenCount++
+			continue
+		}
+		stats.DeployedCount++
+		leadTimes = append(leadTimes, *commit.LeadTimeSeconds)
+	}
+	stats.MedianLeadTimeSeconds = median(leadTimes)
+
+	return stats, nil
+}
+
+// computeLeadTimes pairs each commit with the first production deployment observed after it
+// whose deployed commit is at least as new. Commit dates stand in for ancestry, which is
+// accurate for the linear history of a main branch.
+func computeLeadTimes(commits []*types.Commit, prodHistory []*types.DeploymentHistoryEntry, commitDates map[string]time.Time) []types.CommitLeadTime {
+	var result []types.CommitLeadTime
+	for _, commit := range commits {
+		leadTime := types.CommitLeadTime{Commit: *commit}
+
+		for _, entry := range prodHistory {
+			deployedDate, ok := commitDates[entry.CommitSHA]
+			if !ok || entry.ObservedAt.Before(commit.Date) || deployedDate.Before(commit.Date) {
+				continue
+			}
+
+			observedAt := entry.ObservedAt
+			seconds := int64(observedAt.Sub(commit.Date).Seconds())
+			leadTime.DeployedAt = &observedAt
+			leadTime.Environment = entry.Environment
+			leadTime.LeadTimeSeconds = &seconds
+			break
+		}
+
+		result = append(result, leadTime)
+	}
+	return result
+}
+
+func median(values []int64) *int64 {
+	if len(values) == 0 {
+		return nil
+	}
+	sorted := append([]int64(nil), values...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	mid := len(sorted) / 2
+	result := sorted[mid]
+	if len(sorted)%2 == 0 {
+		result = (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return &result
+}
+
+// isProductionEnvironment reports whether an environment name refers to production
+func isProductionEnvironment(environment string) bool {
+	switch strings.ToLower(environment) {
+	case "prd", "prod", "production":
+		return true
+	}
+	return false
+}
+
+// listServiceCommitsSince pages through the commits touching a service path since the given time
+func (a *App) listServiceCommitsSince(ctx context.Context, client *goGithub.Client, owner, repoName, path string, since time.Time) ([]*types.Commit, error) {
+	opts := &goGithub.CommitsListOptions{
+		Path:        path,
+		Since:       since,
+		ListOptions: goGithub.ListOptions{PerPage: 100},
+	}
+
+	var commits []*types.Commit
+	for page := 0; page < 10; page++ {
+		pageCommits, resp, err := client.Repositories.ListCommits(ctx, owner, repoName, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get service commits: %w", err)
+		}
+		for _, commit := range pageCommits {
+			if converted := toCommit(commit); converted != nil {
+				commits = append(commits, converted)
+			}
+		}
+		if resp == nil || resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return commits, nil
+}
+
+// toCommit converts a GitHub commit into our commit type
+func toCommit(commit *goGithub.RepositoryCommit) *types.Commit {
+	if commit == nil || commit.SHA == nil {
+		return nil
+	}
+
+	result := &types.Commit{Hash: *commit.SHA, Date: time.Now()}
+	if commit.Commit != nil {
+		if commit.Commit.Message != nil {
+			result.Message = *commit.Commit.Message
+		}
+		if commit.Commit.Author != nil {
+			if commit.Commit.Author.Name != nil {
+				result.Author = *commit.Commit.Author.Name
+			}
+			if commit.Commit.Author.Date != nil {
+				result.Date = commit.Commit.Author.Date.Time
+			}
+		}
+	}
+	return result
+}
+
+// Action Management Methods
+
+// RerunAction re-runs the workflow run behind an action, optionally only its failed jobs.
+// The new attempt is picked up by the next sync. Requires the write_actions_enabled config flag.
+func (a *App) RerunAction(actionID int64, failedOnly bool) error {
+	if !a.writeActionsEnabled() {
+		return fmt.Errorf("write actions are disabled - enable write_actions_enabled in settings")
+	}
+	if a.actionModel == nil {
+		return fmt.Errorf("action model not initialized")
+	}
+
+	action, err := a.actionModel.GetByID(actionID)
+	if err != nil {
+		return err
+	}
+
+	repo, err := a.repoModel.GetByID(action.RepositoryID)
+	if err != nil {
+		return fmt.Errorf("repository not found: %w", err)
+	}
+
+	githubToken := a.getGitHubToken()
+	if githubToken == "" {
+		return fmt.Errorf("GitHub token not configured")
+	}
+
+	owner, repoName, err := vcs.ParseGitHubURL(repo.URL)
+	if err != nil {
+		return fmt.Errorf("invalid repository URL: %w", err)
+	}
+
+	githubClient := github.NewClientWithBaseURL(githubToken, a.getGitHubEnterpriseURL(), a.githubClientOptions()...)
+	if failedOnly {
+		err = githubClient.RerunFailedJobs(context.Background(), owner, repoName, action.WorkflowRunID)
+	} else {
+		err = githubClient.RerunWorkflowRun(context.Background(), owner, repoName, action.WorkflowRunID)
+	}
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Re-ran workflow run %d in %s (failed jobs only: %v)", action.WorkflowRunID, repo.Name, failedOnly)
+	return nil
+}
+
+func (a *App) GetRecentActions(repositoryID int64, limit int) ([]*types.ActionWithDetails, error) {
+	if limit == 0 {
+		limit = 50
+	}
+	actions, err := a.actionModel.GetByRepositoryID(repositoryID, limit)
+	if err != nil {
+		return nil, err
+	}
+	a.displayClock().annotateActionsWithDetails(actions)
+	return actions, nil
+}
+
+// Dashboard Statistics
+
+func (a *App) GetDashboardStats() (*types.DashboardStats, error) {
+	if a.repoModel == nil {
+		return &types.DashboardStats{
+			RecentActions: []*types.ActionWithDetails{},
+			BuildRollup:   rollupBuildMatrix(nil),
+		}, nil
+	}
+	
+	repos, err := a.repoModel.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	
+	var totalServices, totalResources int
+	var recentActions []*types.ActionWithDetails
+	
+	for _, repo := range repos {
+		if repo.Type == types.MonorepoType {
+			services, err := a.serviceModel.GetByRepositoryID(repo.ID, false)
+			if err == nil {
+				totalServices += len(services)
+			}
+		} else if repo.Type == types.KubernetesType {
+			resources, err := a.kubernetesModel.GetByRepositoryID(repo.ID)
+			if err == nil {
+				totalResources += len(resources)
+			}
+		}
+		
+		// Get recent actions for this repo
+		actions, err := a.actionModel.GetByRepositoryID(repo.ID, 10)
+		if err == nil {
+			recentActions = append(recentActions, actions...)
 		}
 	}
 	
@@ -1004,12 +1720,25 @@ func (a *App) GetDashboardStats() (map[string]interface{}, error) {
 	if len(recentActions) > 10 {
 		recentActions = recentActions[:10]
 	}
+	a.displayClock().annotateActionsWithDetails(recentActions)
+	
+	buildRollup := rollupBuildMatrix(nil)
+	if matrix, err := a.GetBuildMatrix(0); err == nil {
+		buildRollup = matrix.Rollup
+	} else {
+		log.Printf("Failed to get build matrix: %v", err)
+	}
 	
-	return map[string]interface{}{
-		"repositories":       len(repos),
-		"microservices":      totalServices,
-		"kubernetesResources": totalResources,
-		"recentActions":      recentActions,
+	if recentActions == nil {
+		recentActions = []*types.ActionWithDetails{}
+	}
+	
+	return &types.DashboardStats{
+		Repositories:        len(repos),
+		Microservices:       totalServices,
+		KubernetesResources: totalResources,
+		RecentActions:       recentActions,
+		BuildRollup:         buildRollup,
 	}, nil
 }
 
@@ -1112,6 +1841,7 @@ func (a *App) GetTasksGroupedByScheduledDate() ([]*types.TaskWithProject, error)
 	if a.taskModel == nil {
 		return []*types.TaskWithProject{}, nil
 	}
+	a.recordUsage(types.UsageTaskBoardViewed, nil)
 	return a.taskModel.GetTasksGroupedByScheduledDate()
 }
 
@@ -1139,15 +1869,93 @@ func (a *App) SetConfig(key, value string) error {
 		return fmt.Errorf("config model not initialized")
 	}
 	
-	err := a.configModel.Set(key, value)
+	if key == taskDefaultProjectKey && value != "" && a.projectModel != nil {
+		if id, err := strconv.ParseInt(value, 10, 64); err == nil {
+			if _, err := a.projectModel.GetByID(id); err != nil {
+				return fmt.Errorf("project %d not found", id)
+			}
+		}
+	}
+	
+	tokenChanged := key == "github_token" && a.githubTokenChanged(value)
+	
+	// Unknown keys and malformed values are rejected by the config schema
+	err := a.configModel.SetValidated(key, value)
 	if err != nil {
 		return err
 	}
 	
+	if tokenChanged {
+		a.forgetGitHubTokenSource()
+		a.forgetGitHubTokenExpiry()
+		go a.checkGitHubTokenExpiry()
+	}
+	
 	// Reinitialize JIRA client if JIRA config was changed
 	if strings.HasPrefix(key, "jira_") {
 		a.initJiraClient()
 	}
+	if key == slowQueryThresholdKey {
+		a.applySlowQueryThreshold()
+	}
+	if key == githubRequestsPerSecondKey {
+		a.applyGitHubRateLimit()
+	}
+	if key == rolloutStuckMinutesKey && a.syncService != nil {
+		a.syncService.SetRolloutStuckAfter(a.getRolloutStuckAfter())
+	}
+	if key == syncStuckMultipleKey && a.syncService != nil {
+		a.syncService.SetStuckPassMultiple(a.getSyncStuckMultiple())
+	}
+	if isQuietHoursKey(key) {
+		a.applyQuietHours()
+	}
+	if key == tagPrefixesKey {
+		a.applyTagPrefixes()
+	}
+	if key == fluxHelmReleaseFieldsKey || key == fluxKustomizationFieldsKey {
+		a.applyFluxVersionFields()
+	}
+	if key == commitAnnotationsKey {
+		a.applyCommitAnnotations()
+	}
+	if key == envVarSnapshotsKey {
+		a.applyEnvVarSnapshots()
+	}
+	if key == telemetryEnabledKey {
+		a.applyTelemetryEnabled()
+	}
+	if (key == discoveryReviewWindowKey || key == discoveryReviewExpiredActionKey) && a.syncService != nil {
+		a.syncService.SetDiscoveryReviewWindow(a.getDiscoveryReviewWindow(), a.discoveryReviewAutoApply())
+	}
+	if key == correlationRetryHoursKey && a.syncService != nil {
+		a.syncService.SetCorrelationRetryWindow(a.getCorrelationRetryWindow())
+	}
+	if key == firstDeployNotificationsKey && a.syncService != nil {
+		a.syncService.SetFirstDeployNotifications(a.firstDeployNotificationsEnabled())
+	}
+	if key == "service_description_sources" && a.syncService != nil {
+		a.syncService.SetDescriptionSources(a.getDescriptionSources())
+	}
+	if key == "collect_actions_usage" && a.syncService != nil {
+		a.syncService.SetCollectActionsUsage(a.getConfigFlag("collect_actions_usage"))
+	}
+	if key == serviceDomainFoldersKey && a.syncService != nil {
+		a.syncService.SetDomainFolders(a.getConfigFlag(serviceDomainFoldersKey))
+	}
+	if key == packageVersionLookupKey && a.syncService != nil {
+		a.syncService.SetPackageVersionLookup(a.getConfigFlag(packageVersionLookupKey))
+	}
+	if a.jiraPoller != nil {
+		switch key {
+		case jiraPollIntervalKey:
+			a.jiraPoller.SetInterval(a.getJiraPollInterval())
+		case jiraPollEnabledKey:
+			a.jiraPoller.SetEnabled(a.jiraPollEnabled())
+		case jiraDueDateFillsDeadlineKey:
+			a.jiraPoller.SetFillDeadlines(a.jiraDueDateFillsDeadline())
+		}
+	}
 	
 	return nil
 }
@@ -1181,10 +1989,40 @@ func (a *App) initJiraClient() {
 		}
 		
 		a.jiraClient = jira.NewClientWithAuth(jiraURL.Value, username, jiraToken.Value, authMethod)
+		a.applyJiraCustomFields(a.jiraClient)
 		log.Printf("JIRA client initialized with auth method: %s", authMethod)
 	}
 }
 
+const (
+	jiraPollIntervalKey = "jira_poll_interval_minutes"
+	jiraPollEnabledKey  = "jira_poll_enabled"
+)
+
+// getJiraPollInterval returns how often tasks' JIRA tickets are polled, from the
+// jira_poll_interval_minutes config key
+func (a *App) getJiraPollInterval() time.Duration {
+	if a.configModel != nil {
+		if config, err := a.configModel.Get(jiraPollIntervalKey); err == nil && config != nil {
+			if minutes, err := strconv.Atoi(config.Value); err == nil && minutes > 0 {
+				return time.Duration(minutes) * time.Minute
+			}
+		}
+	}
+	return sync.DefaultJiraPollInterval
+}
+
+// jiraPollEnabled reports whether tasks' JIRA tickets are polled in the background. Polling is on
+// unless the jira_poll_enabled config key is "false".
+func (a *App) jiraPollEnabled() bool {
+	if a.configModel != nil {
+		if config, err := a.configModel.Get(jiraPollEnabledKey); err == nil && config != nil {
+			return config.Value != "false"
+		}
+	}
+	return true
+}
+
 func (a *App) TestJiraConnection() error {
 	if a.jiraClient == nil {
 		return fmt.Errorf("JIRA client not configured")
@@ -1214,17 +2052,13 @@ func (a *App) UpdateTaskJiraTitle(taskID int64, ticketID string) error {
 		return fmt.Errorf("JIRA client not configured")
 	}
 	
-	title, err := a.FetchJiraTicketTitle(ticketID)
-	if err != nil {
-		log.Printf("Failed to fetch JIRA ticket title for %s: %v", ticketID, err)
-		return err
-	}
-	
-	return a.taskModel.UpdateJiraTitle(taskID, title)
+	return a.refreshTaskJiraFields(taskID, ticketID)
 }
 
+// RefreshAllJiraTitles refreshes the JIRA title, status and assignee of every task with a ticket
+// now, using the same batched searches as background polling
 func (a *App) RefreshAllJiraTitles() error {
-	if a.taskModel == nil {
+	if a.taskModel == nil || a.jiraPoller == nil {
 		return fmt.Errorf("task model not initialized")
 	}
 	
@@ -1232,46 +2066,25 @@ func (a *App) RefreshAllJiraTitles() error {
 		return fmt.Errorf("JIRA client not configured")
 	}
 	
-	// Get all tasks
-	tasks, err := a.taskModel.GetAllWithProjects()
+	changed, err := a.jiraPoller.Refresh()
 	if err != nil {
-		return err
-	}
-	
-	var errors []string
-	successCount := 0
-	
-	for _, task := range tasks {
-		if task.JiraTicketID != "" {
-			title, err := a.FetchJiraTicketTitle(task.JiraTicketID)
-			if err != nil {
-				errors = append(errors, fmt.Sprintf("Failed to fetch title for %s: %v", task.JiraTicketID, err))
-				continue
-			}
-			
-			err = a.taskModel.UpdateJiraTitle(task.ID, title)
-			if err != nil {
-				errors = append(errors, fmt.Sprintf("Failed to update title for task %d: %v", task.ID, err))
-				continue
-			}
-			
-			successCount++
-		}
-	}
-	
-	log.Printf("Refreshed %d JIRA titles, %d errors", successCount, len(errors))
-	
-	if len(errors) > 0 {
-		return fmt.Errorf("some titles failed to refresh: %v", errors)
+		return fmt.Errorf("failed to refresh JIRA tickets: %w", err)
 	}
 	
+	log.Printf("Refreshed JIRA tickets, %d tasks changed", changed)
 	return nil
 }
 
 // Enhanced Task Methods
 
 func (a *App) CreateTaskWithJiraTitle(task types.Task) error {
-	log.Printf("CreateTaskWithJiraTitle called with task: %+v", task)
+	return a.createTaskWithJiraFields(&task)
+}
+
+// createTaskWithJiraFields creates a task, filling in its ticket's fields first. A task without a
+// title takes its ticket's.
+func (a *App) createTaskWithJiraFields(task *types.Task) error {
+	log.Printf("CreateTaskWithJiraTitle called with task: %+v", *task)
 	
 	if a.taskModel == nil {
 		log.Printf("Error: task model not initialized")
@@ -1281,24 +2094,35 @@ func (a *App) CreateTaskWithJiraTitle(task types.Task) error {
 	// If JIRA ticket ID is provided and JIRA client is configured, fetch the title
 	if task.JiraTicketID != "" && a.jiraClient != nil {
 		log.Printf("Fetching JIRA title for ticket: %s", task.JiraTicketID)
-		title, err := a.FetchJiraTicketTitle(task.JiraTicketID)
-		if err != nil {
+		if err := a.setTaskJiraFields(task); err != nil {
 			log.Printf("Warning: Failed to fetch JIRA title for %s: %v", task.JiraTicketID, err)
 		} else {
-			task.JiraTitle = title
-			log.Printf("Successfully fetched JIRA title: %s", title)
+			log.Printf("Successfully fetched JIRA title: %s", task.JiraTitle)
 		}
 	} else {
 		log.Printf("Skipping JIRA title fetch - ticketID: %s, jiraClient: %v", task.JiraTicketID, a.jiraClient != nil)
 	}
 	
-	log.Printf("Creating task with data: %+v", task)
-	err := a.taskModel.Create(&task)
+	if task.Title == "" {
+		task.Title = task.JiraTitle
+		if task.Title == "" {
+			task.Title = "Task for " + task.JiraTicketID
+		}
+	}
+	
+	log.Printf("Creating task with data: %+v", *task)
+	err := a.taskModel.Create(task)
 	if err != nil {
 		log.Printf("Error creating task: %v", err)
 		return fmt.Errorf("failed to create task: %w", err)
 	}
 	
+	if task.JiraStatus != "" {
+		if err := a.taskModel.UpdateJiraFields(task); err != nil {
+			log.Printf("Warning: Failed to store JIRA fields of task %d: %v", task.ID, err)
+		}
+	}
+	
 	log.Printf("Task created successfully with ID: %d", task.ID)
 	return nil
 }
@@ -1308,53 +2132,6 @@ func (a *App) Greet(name string) string {
 	return fmt.Sprintf("Hello %s, It's show time!", name)
 }
 
-// TestDeploymentData is a test method to verify deployment functionality
-func (a *App) TestDeploymentData() map[string]interface{} {
-	result := make(map[string]interface{})
-	
-	// Test if we have services
-	services, err := a.serviceModel.GetAll()
-	if err != nil {
-		result["error"] = fmt.Sprintf("Failed to get services: %v", err)
-		return result
-	}
-	result["services_count"] = len(services)
-	result["services"] = services
-	
-	// Test deployment data for service-a (ID: 3)
-	if len(services) > 0 {
-		serviceID := int64(3) // service-a
-		deployments, err := a.deploymentModel.GetDeploymentOverview(serviceID)
-		if err != nil {
-			result["deployment_error"] = fmt.Sprintf("Failed to get deployments: %v", err)
-		} else {
-			result["deployments_count"] = len(deployments)
-			result["deployments"] = deployments
-		}
-	}
-	
-	return result
-}
-
-// isKubernetesRepository checks if a repository is actually a kubernetes repository
-// based on name patterns and URL content, even if incorrectly typed as monorepo
-func (a *App) isKubernetesRepository(repo *types.Repository) bool {
-	// Check if repository name suggests it's a kubernetes repository
-	name := strings.ToLower(repo.Name)
-	if strings.Contains(name, "k8s") || strings.Contains(name, "kubernetes") {
-		return true
-	}
-	
-	// Check if repository URL suggests it's a kubernetes repository
-	url := strings.ToLower(repo.URL)
-	if strings.Contains(url, "k8s") || strings.Contains(url, "kubernetes") {
-		return true
-	}
-	
-	return false
-}
-
-
 // getGitHubToken retrieves the GitHub token from config, falling back to environment variable
 func (a *App) getGitHubToken() string {
 	// Try to get from database config first
@@ -1378,6 +2155,84 @@ func (a *App) getGitHubEnterpriseURL() string {
 	return ""
 }
 
+// getDescriptionSources returns the configured order of files used to describe discovered services,
+// e.g. "service.yaml:description,README.md,package.json:description"
+func (a *App) getDescriptionSources() []github.DescriptionSource {
+	if a.configModel != nil {
+		if config, err := a.configModel.Get("service_description_sources"); err == nil && config != nil && config.Value != "" {
+			sources, err := github.ParseDescriptionSources(config.Value)
+			if err == nil {
+				return sources
+			}
+			log.Printf("Invalid service_description_sources config, using defaults: %v", err)
+		}
+	}
+	return github.DefaultDescriptionSources
+}
+
+// getEnvironmentOrder returns the environments listed in the comma separated environment_order
+// config key, e.g. "dev,stg,prd"
+func (a *App) getEnvironmentOrder() []string {
+	var order []string
+	if a.configModel != nil {
+		if config, err := a.configModel.Get("environment_order"); err == nil && config != nil {
+			for _, environment := range strings.Split(config.Value, ",") {
+				if environment = strings.TrimSpace(environment); environment != "" {
+					order = append(order, environment)
+				}
+			}
+		}
+	}
+	return order
+}
+
+// sortEnvironments orders environments by the configured environment order; environments that
+// aren't listed follow in alphabetical order
+func (a *App) sortEnvironments(environments []string) {
+	rank := a.environmentRank()
+	sort.Slice(environments, func(i, j int) bool {
+		return rank.less(environments[i], environments[j])
+	})
+}
+
+// environmentRank is the position of each environment in the configured environment order, from 1
+type environmentRank map[string]int
+
+func (a *App) environmentRank() environmentRank {
+	rank := make(environmentRank)
+	for i, environment := range a.getEnvironmentOrder() {
+		rank[environment] = i + 1
+	}
+	return rank
+}
+
+// less orders listed environments first, in the configured order, and the others alphabetically
+func (r environmentRank) less(a, b string) bool {
+	ra, rb := r[a], r[b]
+	if ra != rb {
+		if ra == 0 || rb == 0 {
+			return ra != 0
+		}
+		return ra < rb
+	}
+	return a < b
+}
+
+// getConfigFlag reports whether a boolean config key is set to "true"
+func (a *App) getConfigFlag(key string) bool {
+	if a.configModel != nil {
+		if config, err := a.configModel.Get(key); err == nil && config != nil {
+			return config.Value == "true"
+		}
+	}
+	return false
+}
+
+// writeActionsEnabled reports whether actions that change state on GitHub are allowed
+func (a *App) writeActionsEnabled() bool {
+	return a.getConfigFlag("write_actions_enabled")
+}
+
 // TestGitHubConnection tests the GitHub connection using the stored token
 func (a *App) TestGitHubConnection() error {
 	githubToken := a.getGitHubToken()
@@ -1398,154 +2253,79 @@ func (a *App) TestGitHubConnection() error {
 	return nil
 }
 
-// TestScanKubernetesDeployments manually triggers a scan of kubernetes deployments for testing
-func (a *App) TestScanKubernetesDeployments() error {
-	log.Printf("TestScanKubernetesDeployments called")
-	
-	// Get kubernetes repository
-	repos, err := a.repoModel.GetAll()
+// DiagnoseDeploymentScan runs the kustomization scan of a kubernetes repository and reports the outcome
+// of every file found: the deployments it yields and the service they match, or why it was skipped
+func (a *App) DiagnoseDeploymentScan(repoID int64) (*types.DeploymentScanDiagnostics, error) {
+	repo, err := a.repoModel.GetByID(repoID)
 	if err != nil {
-		return fmt.Errorf("failed to get repositories: %w", err)
-	}
-	
-	var kubernetesRepo *types.Repository
-	for _, repo := range repos {
-		if repo.Type == types.KubernetesType {
-			kubernetesRepo = repo
-			break
-		}
-	}
-	
-	if kubernetesRepo == nil {
-		return fmt.Errorf("no kubernetes repository found")
+		return nil, err
 	}
-	
-	log.Printf("Found kubernetes repository: %s (%s)", kubernetesRepo.Name, kubernetesRepo.URL)
-	
-	// Clear existing deployments
-	if err := a.clearAllDeployments(); err != nil {
-		log.Printf("Warning: failed to clear existing deployments: %v", err)
+	if repo.Type != types.KubernetesType {
+		return nil, fmt.Errorf("repository %s is not a kubernetes repository", repo.Name)
 	}
 	
-	// Trigger sync for kubernetes repository
-	if a.syncService != nil {
-		return a.syncService.SyncRepository(kubernetesRepo.ID)
-	}
-	
-	return fmt.Errorf("sync service not initialized - GitHub token required")
-}
-
-// Helper method to clear all deployments for testing
-func (a *App) clearAllDeployments() error {
-	if a.db == nil {
-		return fmt.Errorf("database not initialized")
-	}
-	
-	_, err := a.db.GetConn().Exec("DELETE FROM deployments")
-	return err
-}
-
-// TestKustomizationFileAccess tests if we can access the kustomization.yaml file directly
-func (a *App) TestKustomizationFileAccess() (map[string]interface{}, error) {
-	result := make(map[string]interface{})
-	
 	githubToken := a.getGitHubToken()
 	if githubToken == "" {
-		result["error"] = "No GitHub token configured"
-		result["github_token_configured"] = false
-		return result, nil
+		return nil, fmt.Errorf("no GitHub token configured")
 	}
 	
-	result["github_token_configured"] = true
-	
-	// Get kubernetes repository
-	repos, err := a.repoModel.GetAll()
+	owner, repoName, err := vcs.ParseGitHubURL(repo.URL)
 	if err != nil {
-		result["error"] = fmt.Sprintf("Failed to get repositories: %v", err)
-		return result, err
-	}
-	
-	var kubernetesRepo *types.Repository
-	for _, repo := range repos {
-		if repo.Type == types.KubernetesType {
-			kubernetesRepo = repo
-			break
-		}
-	}
-	
-	if kubernetesRepo == nil {
-		result["error"] = "No kubernetes repository found"
-		return result, nil
-	}
-	
-	result["kubernetes_repo"] = map[string]interface{}{
-		"name": kubernetesRepo.Name,
-		"url":  kubernetesRepo.URL,
-		"type": kubernetesRepo.Type,
+		return nil, fmt.Errorf("invalid repository URL: %w", err)
 	}
 	
-	// Parse GitHub URL
-	owner, repoName, err := a.parseGitHubURL(kubernetesRepo.URL)
+	services, err := a.serviceModel.GetAll()
 	if err != nil {
-		result["error"] = fmt.Sprintf("Invalid repository URL: %v", err)
-		return result, err
-	}
-	
-	result["parsed_url"] = map[string]interface{}{
-		"owner": owner,
-		"repo":  repoName,
+		return nil, err
 	}
 	
-	// Test GitHub client
-	ctx := context.Background()
-	client := a.createGitHubClient(githubToken)
-	
-	// Test repository access
-	repo, _, err := client.Repositories.Get(ctx, owner, repoName)
+	client := github.NewClientWithBaseURL(githubToken, a.getGitHubEnterpriseURL(), a.githubClientOptions()...)
+	client.SetFluxVersionFields(a.getFluxVersionFields())
+	client.SetCommitAnnotations(a.getCommitAnnotations())
+	client.SetImageNames(kubernetes.NewImageNames(services))
+	results, err := client.ScanKustomizationFilesVerbose(context.Background(), owner, repoName, repo.ServiceLocation)
 	if err != nil {
-		result["error"] = fmt.Sprintf("Cannot access repository: %v", err)
-		return result, err
+		return nil, err
 	}
 	
-	result["repo_access"] = "success"
-	result["default_branch"] = repo.GetDefaultBranch()
-	
-	// Search for kustomization.yaml files
-	searchQuery := fmt.Sprintf("filename:kustomization.yaml repo:%s/%s", owner, repoName)
-	searchResult, _, err := client.Search.Code(ctx, searchQuery, &goGithub.SearchOptions{
-		ListOptions: goGithub.ListOptions{PerPage: 10},
-	})
-	
-	if err != nil {
-		result["search_error"] = fmt.Sprintf("Search failed: %v", err)
-	} else {
-		var files []map[string]interface{}
-		for _, codeResult := range searchResult.CodeResults {
-			if codeResult.Path != nil {
-				files = append(files, map[string]interface{}{
-					"path": *codeResult.Path,
-					"url":  *codeResult.HTMLURL,
-				})
-			}
+	diagnostics := &types.DeploymentScanDiagnostics{
+		RepositoryID: repo.ID,
+		ScanPath:     github.KustomizationScanPath(repo.ServiceLocation),
+		FilesFound:   len(results),
+		Files:        []types.DeploymentScanFile{},
+	}
+	
+	for _, result := range results {
+		file := types.DeploymentScanFile{
+			Path:        result.Path,
+			Outcome:     "skipped",
+			ServiceName: result.ServiceName,
+			Environment: result.Environment,
+			Region:      result.Region,
+			Namespaces:  result.Namespaces,
+			Tag:         result.Tag,
+			Source:      result.Source,
+			CommitAnnotation: result.CommitAnnotation,
+			AnnotatedCommit:  result.AnnotatedCommit,
+			SkipReason:  result.SkipReason,
+			Detail:      result.Detail,
+			Images:      result.Images,
 		}
-		result["kustomization_files"] = files
-		result["files_found"] = len(files)
-	}
-	
-	// Try to get specific file content
-	testPath := "services/service-a/overlays/stg/us-west-2/kustomization.yaml"
-	fileContent, _, _, err := client.Repositories.GetContents(ctx, owner, repoName, testPath, nil)
-	if err != nil {
-		result["file_access_error"] = fmt.Sprintf("Cannot access %s: %v", testPath, err)
-	} else {
-		content, err := fileContent.GetContent()
-		if err != nil {
-			result["content_decode_error"] = fmt.Sprintf("Cannot decode content: %v", err)
-		} else {
-			result["file_content"] = content
-			result["file_access"] = "success"
+		
+		if result.SkipReason == "" {
+			if service := sync.MatchDeploymentService(services, result.ServiceName); service != nil {
+				file.Outcome = "matched"
+				file.MatchedServiceID = service.ID
+				file.MatchedServiceName = service.Name
+				diagnostics.FilesMatched++
+			} else {
+				file.SkipReason = "no_service_match"
+				file.Detail = fmt.Sprintf("no microservice is named like %s", result.ServiceName)
+			}
 		}
+		
+		diagnostics.Files = append(diagnostics.Files, file)
 	}
 	
-	return result, nil
-}
\ No newline at end of file
+	return diagnostics, nil
+}
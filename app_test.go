@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"dev-dashboard/internal/database"
+	"dev-dashboard/internal/models"
+	"dev-dashboard/internal/testsupport"
+	"dev-dashboard/pkg/types"
+)
+
+// newTestApp returns an app with its models on a fresh test database
+func newTestApp(t *testing.T) (*App, *database.DB) {
+	t.Helper()
+	db := testsupport.NewTestDatabase(t)
+	conn := db.GetConn()
+	return &App{
+		db:               db,
+		repoModel:        models.NewRepositoryModel(conn),
+		serviceModel:     models.NewMicroserviceModel(conn),
+		deploymentModel:  models.NewDeploymentModel(conn),
+		projectModel:     models.NewProjectModel(conn),
+		configModel:      models.NewConfigModel(conn),
+		customFieldModel: models.NewCustomFieldModel(conn),
+		presentation:     newPresentationRedactor(),
+	}, db
+}
+
+// listingQueries returns how many statements listing every service runs with services in the given
+// number of monorepos, through GetMicroservicesWithRepository and GetMicroservices
+func listingQueries(t *testing.T, repositories int) (withRepository, all int64) {
+	t.Helper()
+	app, db := newTestApp(t)
+	conn := db.GetConn()
+	for i := 0; i < repositories; i++ {
+		repo := testsupport.Repository(t, conn)
+		testsupport.Service(t, conn, repo.ID)
+		testsupport.Service(t, conn, repo.ID)
+	}
+	testsupport.KubernetesRepository(t, conn)
+
+	before := db.QueryCount()
+	services, err := app.GetMicroservicesWithRepository(false)
+	if err != nil {
+		t.Fatalf("GetMicroservicesWithRepository: %v", err)
+	}
+	if len(services) != 2*repositories {
+		t.Fatalf("got %d services, want %d", len(services), 2*repositories)
+	}
+	withRepository = db.QueryCount() - before
+
+	before = db.QueryCount()
+	if _, err := app.GetMicroservices(0, false); err != nil {
+		t.Fatalf("GetMicroservices: %v", err)
+	}
+	return withRepository, db.QueryCount() - before
+}
+
+func TestListingAllServicesDoesNotQueryPerRepository(t *testing.T) {
+	oneWithRepository, oneAll := listingQueries(t, 1)
+	manyWithRepository, manyAll := listingQueries(t, 20)
+
+	if manyWithRepository != oneWithRepository {
+		t.Errorf("GetMicroservicesWithRepository ran %d statements for 20 repositories and %d for one", manyWithRepository, oneWithRepository)
+	}
+	if manyAll != oneAll {
+		t.Errorf("GetMicroservices ran %d statements for 20 repositories and %d for one", manyAll, oneAll)
+	}
+}
+
+// servePullRequests answers the pull request and file listings of a repository with prs, mapping
+// each pull request number to the files it changes
+func servePullRequests(t *testing.T, fullName string, prs map[int][]string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		prefix := "/repos/" + fullName + "/pulls"
+		switch {
+		case r.URL.Path == prefix:
+			var list []map[string]interface{}
+			for number := range prs {
+				list = append(list, map[string]interface{}{"number": number, "state": "open", "title": "change"})
+			}
+			json.NewEncoder(w).Encode(list)
+		case strings.HasPrefix(r.URL.Path, prefix+"/") && strings.HasSuffix(r.URL.Path, "/files"):
+			number, _ := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, prefix+"/"), "/files"))
+			var files []map[string]string
+			for _, file := range prs[number] {
+				files = append(files, map[string]string{"filename": file})
+			}
+			json.NewEncoder(w).Encode(files)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestServicePullRequestsMatchNormalizedPaths(t *testing.T) {
+	app, db := newTestApp(t)
+	conn := db.GetConn()
+	repo := testsupport.Repository(t, conn)
+
+	// Rows from before paths were cleaned, two of them the same directory
+	for _, path := range []string{"/services/payments/", "./services//payments", "services/payments-v2"} {
+		if _, err := conn.Exec(`INSERT INTO microservices (repository_id, name, path, description) VALUES (?, ?, ?, '')`, repo.ID, "svc"+path, path); err != nil {
+			t.Fatalf("failed to insert service: %v", err)
+		}
+	}
+	if err := db.Migrate(); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	services, err := app.serviceModel.GetByRepositoryID(repo.ID, true)
+	if err != nil {
+		t.Fatalf("GetByRepositoryID: %v", err)
+	}
+	var payments *types.Microservice
+	for _, service := range services {
+		if service.Path == "services/payments" {
+			payments = service
+		}
+	}
+	if len(services) != 2 || payments == nil {
+		t.Fatalf("got %d services, want the payments duplicates merged at services/payments", len(services))
+	}
+
+	fullName := strings.TrimPrefix(repo.URL, "https://github.com/")
+	server := servePullRequests(t, fullName, map[int][]string{
+		1: {"services/payments/main.go"},
+		2: {"services/payments-v2/main.go"},
+		3: {"docs/payments.md", "services/payments"},
+	})
+	for key, value := range map[string]string{"github_token": "test-token", githubAPIBaseURLKey: server.URL + "/"} {
+		if err := app.configModel.Set(key, value); err != nil {
+			t.Fatalf("failed to set %s: %v", key, err)
+		}
+	}
+
+	prs, err := app.fetchServicePullRequests(context.Background(), payments, repo)
+	if err != nil {
+		t.Fatalf("fetchServicePullRequests: %v", err)
+	}
+	var numbers []int
+	for _, pr := range prs {
+		numbers = append(numbers, pr.Number)
+	}
+	slices.Sort(numbers)
+	if want := []int{1, 3}; !slices.Equal(numbers, want) {
+		t.Errorf("got pull requests %v of services/payments, want %v and not the one changing services/payments-v2", numbers, want)
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+
+	"dev-dashboard/internal/telemetry"
+	"dev-dashboard/pkg/types"
+)
+
+// GetBuildMatrix returns the latest default-branch build of every microservice in a repository,
+// or in all repositories when repositoryID is 0, with a red/green rollup
+func (a *App) GetBuildMatrix(repositoryID int64) (*types.BuildMatrix, error) {
+	if a.actionModel == nil {
+		return nil, fmt.Errorf("action model not initialized")
+	}
+	a.recordFeature(telemetry.BuildMatrix)
+
+	entries, err := a.actionModel.GetLatestDefaultBranchBuilds(repositoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range entries {
+		entries[i].Status = buildMatrixStatus(entries[i].Status, entries[i].Conclusion)
+		if entries[i].StartedAt != nil && entries[i].CompletedAt != nil {
+			entries[i].DurationSeconds = int64(entries[i].CompletedAt.Sub(*entries[i].StartedAt).Seconds())
+		}
+	}
+
+	return &types.BuildMatrix{
+		Entries: entries,
+		Rollup:  rollupBuildMatrix(entries),
+	}, nil
+}
+
+// buildMatrixStatus maps a run's status and conclusion onto a build matrix status
+func buildMatrixStatus(status, conclusion string) string {
+	switch {
+	case status == "":
+		return types.BuildStatusNoData
+	case status != "completed":
+		return types.BuildStatusRunning
+	}
+
+	switch actionOutcome(conclusion) {
+	case outcomeSuccess:
+		return types.BuildStatusSuccess
+	case outcomeFailure:
+		return types.BuildStatusFailure
+	case outcomeCancelled:
+		return types.BuildStatusCancelled
+	default:
+		// skipped or neutral runs say nothing about the build
+		return types.BuildStatusNoData
+	}
+}
+
+func rollupBuildMatrix(entries []types.BuildMatrixEntry) types.BuildMatrixRollup {
+	rollup := types.BuildMatrixRollup{Total: len(entries)}
+	for _, entry := range entries {
+		switch entry.Status {
+		case types.BuildStatusSuccess:
+			rollup.Success++
+		case types.BuildStatusFailure:
+			rollup.Failure++
+		case types.BuildStatusCancelled:
+			rollup.Cancelled++
+		case types.BuildStatusRunning:
+			rollup.Running++
+		default:
+			rollup.NoData++
+		}
+	}
+
+	switch {
+	case rollup.Failure > 0:
+		rollup.Overall = "red"
+	case rollup.Success > 0:
+		rollup.Overall = "green"
+	default:
+		rollup.Overall = types.BuildStatusNoData
+	}
+	return rollup
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"dev-dashboard/internal/github"
+	"dev-dashboard/internal/vcs"
+	"dev-dashboard/pkg/types"
+
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// commitChecksRecheckAfter is how long pending and empty rollups, and failed lookups, are kept
+	// before asking GitHub again; passed and failed rollups are final and kept for good
+	commitChecksRecheckAfter = 2 * time.Minute
+
+	commitChecksWorkers = 4
+	commitChecksTimeout = 20 * time.Second
+)
+
+// commitChecksCache remembers the check rollup of commits by SHA, so listing deployments doesn't
+// ask GitHub again for commits whose checks have finished
+type commitChecksCache struct {
+	mu      sync.Mutex
+	entries map[string]*types.CommitChecks // nil when the lookup failed
+	expires map[string]time.Time
+}
+
+func newCommitChecksCache() *commitChecksCache {
+	return &commitChecksCache{entries: make(map[string]*types.CommitChecks), expires: make(map[string]time.Time)}
+}
+
+func (c *commitChecksCache) get(key string) (*types.CommitChecks, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	checks, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if expires, ok := c.expires[key]; ok && time.Now().After(expires) {
+		delete(c.entries, key)
+		delete(c.expires, key)
+		return nil, false
+	}
+	return checks, true
+}
+
+func (c *commitChecksCache) put(key string, checks *types.CommitChecks) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = checks
+	if checks != nil && (checks.State == github.ChecksSuccess || checks.State == github.ChecksFailure) {
+		delete(c.expires, key)
+		return
+	}
+	c.expires[key] = time.Now().Add(commitChecksRecheckAfter)
+}
+
+// GetCommitChecks returns the check rollup of a commit in a service's repository, for looking up
+// historical deployments on demand
+func (a *App) GetCommitChecks(serviceID int64, sha string) (*types.CommitChecks, error) {
+	if a.serviceModel == nil || a.repoModel == nil {
+		return nil, fmt.Errorf("service model not initialized")
+	}
+	service, err := a.serviceModel.GetByID(serviceID)
+	if err != nil {
+		return nil, err
+	}
+	repo, err := a.repoModel.GetByID(service.RepositoryID)
+	if err != nil {
+		return nil, err
+	}
+	owner, repoName, err := vcs.ParseGitHubURL(repo.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid repository URL: %w", err)
+	}
+	token := a.getGitHubToken()
+	if token == "" {
+		return nil, fmt.Errorf("no GitHub token configured")
+	}
+
+	client := github.NewClientWithBaseURL(token, a.getGitHubEnterpriseURL(), a.githubClientOptions()...)
+	ctx, cancel := context.WithTimeout(context.Background(), commitChecksTimeout)
+	defer cancel()
+	return a.commitChecks(ctx, client, owner, repoName, sha)
+}
+
+// commitChecks returns a commit's rollup from the cache, or looks it up
+func (a *App) commitChecks(ctx context.Context, client *github.Client, owner, repoName, sha string) (*types.CommitChecks, error) {
+	key := owner + "/" + repoName + "@" + sha
+	if checks, ok := a.commitChecksCache.get(key); ok {
+		if checks == nil {
+			return nil, fmt.Errorf("checks of %s couldn't be looked up, try again shortly", shortSHA(sha))
+		}
+		return checks, nil
+	}
+
+	result, err := client.GetCombinedStatusAndChecks(ctx, owner, repoName, sha)
+	if err != nil {
+		a.commitChecksCache.put(key, nil)
+		return nil, err
+	}
+	checks := &types.CommitChecks{
+		SHA:          result.SHA,
+		State:        result.State,
+		Total:        result.Total,
+		Succeeded:    result.Succeeded,
+		Failed:       result.Failed,
+		Pending:      result.Pending,
+		FailedChecks: result.FailedChecks,
+		CheckedAt:    time.Now(),
+	}
+	a.commitChecksCache.put(key, checks)
+	return checks, nil
+}
+
+// attachDeploymentChecks sets the check rollup of the commits a service's current deployments run,
+// flagging the ones that weren't green. Only current deployments are looked up; failures are logged
+// and leave Checks nil.
+func (a *App) attachDeploymentChecks(serviceID int64, deployments []*types.DeploymentOverview) {
+	token := a.getGitHubToken()
+	if token == "" || len(deployments) == 0 || a.serviceModel == nil || a.repoModel == nil {
+		return
+	}
+	service, err := a.serviceModel.GetByID(serviceID)
+	if err != nil {
+		return
+	}
+	repo, err := a.repoModel.GetByID(service.RepositoryID)
+	if err != nil {
+		return
+	}
+	owner, repoName, err := vcs.ParseGitHubURL(repo.URL)
+	if err != nil {
+		return
+	}
+
+	var shas []string
+	seen := make(map[string]bool)
+	for _, deployment := range deployments {
+		if deployment.CommitSHA != "" && !seen[deployment.CommitSHA] {
+			seen[deployment.CommitSHA] = true
+			shas = append(shas, deployment.CommitSHA)
+		}
+	}
+	if len(shas) == 0 {
+		return
+	}
+
+	client := github.NewClientWithBaseURL(token, a.getGitHubEnterpriseURL(), a.githubClientOptions()...)
+	ctx, cancel := context.WithTimeout(context.Background(), commitChecksTimeout)
+	defer cancel()
+
+	var mu sync.Mutex
+	bySHA := make(map[string]*types.CommitChecks, len(shas))
+	var g errgroup.Group
+	g.SetLimit(commitChecksWorkers)
+	for _, sha := range shas {
+		g.Go(func() error {
+			checks, err := a.commitChecks(ctx, client, owner, repoName, sha)
+			if err != nil {
+				log.Printf("Failed to get checks of commit %s: %v", shortSHA(sha), err)
+				return nil
+			}
+			mu.Lock()
+			bySHA[sha] = checks
+			mu.Unlock()
+			return nil
+		})
+	}
+	g.Wait()
+
+	for _, deployment := range deployments {
+		deployment.Checks = bySHA[deployment.CommitSHA]
+		if deployment.Checks != nil {
+			deployment.ChecksNotGreen = deployment.Checks.State == github.ChecksFailure || deployment.Checks.State == github.ChecksPending
+		}
+	}
+}
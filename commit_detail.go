@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"dev-dashboard/internal/github"
+	"dev-dashboard/internal/telemetry"
+	"dev-dashboard/internal/vcs"
+	"dev-dashboard/pkg/types"
+)
+
+const (
+	// commitDetailPatchBytes bounds the patch text GetCommitDetail returns unless the full diff is
+	// asked for; patches that don't fit are left out and flagged
+	commitDetailPatchBytes = 256 * 1024
+
+	commitDetailTimeout = 30 * time.Second
+)
+
+// GetCommitDetail returns the files a commit changed inside a service's path with their patches,
+// so a commit on the service timeline can be explored in place. Large diffs are truncated unless
+// full is set.
+func (a *App) GetCommitDetail(serviceID int64, sha string, full bool) (*types.CommitDetail, error) {
+	if a.serviceModel == nil || a.repoModel == nil {
+		return nil, fmt.Errorf("service model not initialized")
+	}
+	a.recordFeature(telemetry.CommitDetail)
+
+	sha = strings.TrimSpace(sha)
+	if sha == "" {
+		return nil, fmt.Errorf("commit SHA is required")
+	}
+	service, err := a.serviceModel.GetByID(serviceID)
+	if err != nil {
+		return nil, err
+	}
+	repo, err := a.repoModel.GetByID(service.RepositoryID)
+	if err != nil {
+		return nil, err
+	}
+	owner, repoName, err := vcs.ParseGitHubURL(repo.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid repository URL: %w", err)
+	}
+	token := a.getGitHubToken()
+	if token == "" {
+		return nil, fmt.Errorf("no GitHub token configured")
+	}
+
+	client := github.NewClientWithBaseURL(token, a.getGitHubEnterpriseURL(), a.githubClientOptions()...)
+	ctx, cancel := context.WithTimeout(context.Background(), commitDetailTimeout)
+	defer cancel()
+	diff, err := client.GetCommitDiff(ctx, owner, repoName, sha)
+	if err != nil {
+		return nil, err
+	}
+
+	detail := &types.CommitDetail{
+		ServiceID: serviceID,
+		Commit: types.Commit{
+			Hash:    diff.SHA,
+			Message: diff.Message,
+			Author:  diff.Author,
+			Date:    diff.Date,
+		},
+		Files: []*types.CommitFileDiff{},
+	}
+	detail.Commit.DateRelative = a.displayClock().relative(detail.Commit.Date)
+
+	budget := commitDetailPatchBytes
+	for _, file := range diff.Files {
+		// A rename into or out of the service changed it too
+		if !vcs.PathWithin(file.Filename, service.Path) &&
+			(file.PreviousFilename == "" || !vcs.PathWithin(file.PreviousFilename, service.Path)) {
+			detail.OtherFiles++
+			continue
+		}
+
+		fileDiff := &types.CommitFileDiff{
+			Filename:         file.Filename,
+			PreviousFilename: file.PreviousFilename,
+			Status:           file.Status,
+			Additions:        file.Additions,
+			Deletions:        file.Deletions,
+			Patch:            file.Patch,
+		}
+		if !full && len(file.Patch) > budget {
+			fileDiff.Patch = ""
+			fileDiff.PatchTruncated = true
+			detail.Truncated = true
+		} else {
+			budget -= len(file.Patch)
+		}
+		detail.Additions += file.Additions
+		detail.Deletions += file.Deletions
+		detail.Files = append(detail.Files, fileDiff)
+	}
+	return detail, nil
+}
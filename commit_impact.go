@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"dev-dashboard/internal/telemetry"
+	"dev-dashboard/internal/vcs"
+	"dev-dashboard/pkg/types"
+
+	goGithub "github.com/google/go-github/v57/github"
+)
+
+// GetCommitImpact reports which services of a monorepo a commit changed and, for each, whether its
+// deployments run that commit, a later one that includes it, or one that doesn't include it yet
+func (a *App) GetCommitImpact(repositoryID int64, sha string) (*types.CommitImpact, error) {
+	if a.repoModel == nil || a.serviceModel == nil || a.deploymentModel == nil {
+		return nil, fmt.Errorf("models not initialized")
+	}
+	a.recordFeature(telemetry.CommitImpact)
+
+	sha = strings.TrimSpace(sha)
+	if sha == "" {
+		return nil, fmt.Errorf("commit SHA is required")
+	}
+
+	repo, err := a.repoModel.GetByID(repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	if repo.Type != types.MonorepoType {
+		return nil, fmt.Errorf("repository %s is not a monorepo", repo.Name)
+	}
+
+	githubToken := a.getGitHubToken()
+	if githubToken == "" {
+		return nil, fmt.Errorf("GitHub token not configured")
+	}
+
+	owner, repoName, err := vcs.ParseGitHubURL(repo.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid repository URL: %w", err)
+	}
+
+	ctx := context.Background()
+	client := a.createGitHubClient(githubToken)
+
+	commit, _, err := client.Repositories.GetCommit(ctx, owner, repoName, sha, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit %s: %w", sha, err)
+	}
+
+	files := make([]string, 0, len(commit.Files))
+	for _, file := range commit.Files {
+		files = append(files, file.GetFilename())
+		// A rename out of a service changes that service too
+		if previous := file.GetPreviousFilename(); previous != "" {
+			files = append(files, previous)
+		}
+	}
+
+	impact := &types.CommitImpact{
+		RepositoryID: repositoryID,
+		Commit: types.Commit{
+			Hash:    commit.GetSHA(),
+			Message: commit.GetCommit().GetMessage(),
+			Author:  commit.GetCommit().GetAuthor().GetName(),
+			Date:    commit.GetCommit().GetAuthor().GetDate().Time,
+		},
+		FilesChanged: len(commit.Files),
+		Services:     []*types.ServiceCommitImpact{},
+	}
+
+	services, err := a.serviceModel.GetByRepositoryID(repositoryID, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get services: %w", err)
+	}
+
+	positions := &commitPositions{ctx: ctx, client: client, owner: owner, repo: repoName, base: impact.Commit.Hash, results: make(map[string]commitPosition)}
+	for _, service := range services {
+		changed := filesUnderPath(files, service.Path)
+		if len(changed) == 0 {
+			continue
+		}
+
+		serviceImpact := &types.ServiceCommitImpact{
+			ServiceID:   service.ID,
+			ServiceName: service.Name,
+			ServicePath: service.Path,
+			Files:       changed,
+			Deployments: []*types.CommitImpactDeployment{},
+		}
+
+		deployments, err := a.deploymentModel.GetByServiceID(service.ID)
+		if err != nil {
+			log.Printf("Failed to get deployments of %s: %v", service.Name, err)
+		}
+		for _, deployment := range deployments {
+			position := positions.of(deployment.CommitSHA)
+			serviceImpact.Deployments = append(serviceImpact.Deployments, &types.CommitImpactDeployment{
+				Environment: deployment.Environment,
+				Region:      deployment.Region,
+				Namespace:   deployment.Namespace,
+				Tag:         deployment.Tag,
+				CommitSHA:   deployment.CommitSHA,
+				UpdatedAt:   deployment.UpdatedAt,
+				Position:    position.position,
+				AheadBy:     position.aheadBy,
+				BehindBy:    position.behindBy,
+			})
+		}
+
+		impact.Services = append(impact.Services, serviceImpact)
+	}
+
+	impact.Commit.DateRelative = a.displayClock().relative(impact.Commit.Date)
+	return impact, nil
+}
+
+// filesUnderPath returns the files inside the directory, without duplicates
+func filesUnderPath(files []string, dir string) []string {
+	seen := make(map[string]bool)
+	var matched []string
+	for _, file := range files {
+		if vcs.PathWithin(file, dir) && !seen[file] {
+			seen[file] = true
+			matched = append(matched, file)
+		}
+	}
+	return matched
+}
+
+type commitPosition struct {
+	position string
+	aheadBy  int
+	behindBy int
+}
+
+// commitPositions compares deployed commits with the base commit, once per deployed commit since
+// most deployments of a release share one
+type commitPositions struct {
+	ctx         context.Context
+	client      *goGithub.Client
+	owner, repo string
+	base        string
+	results     map[string]commitPosition
+}
+
+func (p *commitPositions) of(deployedSHA string) commitPosition {
+	if deployedSHA == "" {
+		return commitPosition{position: types.CommitPositionUnknown}
+	}
+	if result, ok := p.results[deployedSHA]; ok {
+		return result
+	}
+
+	result := commitPosition{position: types.CommitPositionUnknown}
+	comparison, _, err := p.client.Repositories.CompareCommits(p.ctx, p.owner, p.repo, p.base, deployedSHA, &goGithub.ListOptions{PerPage: 1})
+	if err != nil {
+		log.Printf("Failed to compare %s with deployed commit %s: %v", p.base, deployedSHA, err)
+	} else {
+		result.aheadBy = comparison.GetAheadBy()
+		result.behindBy = comparison.GetBehindBy()
+		switch comparison.GetStatus() {
+		case "identical":
+			result.position = types.CommitPositionAt
+		case "ahead":
+			result.position = types.CommitPositionAhead
+		case "behind":
+			result.position = types.CommitPositionBehind
+		case "diverged":
+			result.position = types.CommitPositionDiverged
+		}
+	}
+
+	p.results[deployedSHA] = result
+	return result
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"dev-dashboard/pkg/types"
+
+	goGithub "github.com/google/go-github/v57/github"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// linkCommitPullRequestsKey enables asking GitHub for the pull request of commits whose message
+	// doesn't name one
+	linkCommitPullRequestsKey = "link_commit_pull_requests"
+
+	// commitPRLookupsPerFetch bounds the GitHub requests made for one commit list; the remaining
+	// commits are looked up on later fetches
+	commitPRLookupsPerFetch = 20
+	commitPRLookupWorkers   = 4
+)
+
+var (
+	// "Merge pull request #123 from owner/branch"
+	mergeCommitPattern = regexp.MustCompile(`^Merge pull request #(\d+) `)
+	// "Add feature (#123)", the default squash merge title
+	squashCommitPattern = regexp.MustCompile(`\(#(\d+)\)\s*$`)
+)
+
+// commitPullRequestCache remembers which pull request introduced a commit, including commits that
+// have none (0), so each commit is looked up on GitHub at most once
+type commitPullRequestCache struct {
+	mu      sync.Mutex
+	numbers map[string]int
+}
+
+func newCommitPullRequestCache() *commitPullRequestCache {
+	return &commitPullRequestCache{numbers: make(map[string]int)}
+}
+
+func (c *commitPullRequestCache) get(sha string) (int, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	number, ok := c.numbers[sha]
+	return number, ok
+}
+
+func (c *commitPullRequestCache) put(sha string, number int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.numbers[sha] = number
+}
+
+// pullRequestNumberFromMessage returns the pull request a merge or squash commit names in its
+// first line, or 0
+func pullRequestNumberFromMessage(message string) int {
+	firstLine, _, _ := strings.Cut(message, "\n")
+	for _, pattern := range []*regexp.Regexp{mergeCommitPattern, squashCommitPattern} {
+		if match := pattern.FindStringSubmatch(firstLine); match != nil {
+			number, _ := strconv.Atoi(match[1])
+			return number
+		}
+	}
+	return 0
+}
+
+// linkCommitPullRequests sets PRNumber on commits. Merge and squash commits name their pull
+// request in the message; with the link_commit_pull_requests config flag, up to
+// commitPRLookupsPerFetch other commits are looked up with the commit's associated pull requests.
+func (a *App) linkCommitPullRequests(ctx context.Context, client *goGithub.Client, owner, repoName string, commits []*types.Commit) {
+	var unresolved []*types.Commit
+	for _, commit := range commits {
+		if number := pullRequestNumberFromMessage(commit.Message); number != 0 {
+			commit.PRNumber = number
+			continue
+		}
+		if number, ok := a.commitPRCache.get(commit.Hash); ok {
+			commit.PRNumber = number
+			continue
+		}
+		unresolved = append(unresolved, commit)
+	}
+
+	if len(unresolved) == 0 || !a.getConfigFlag(linkCommitPullRequestsKey) {
+		return
+	}
+	if len(unresolved) > commitPRLookupsPerFetch {
+		unresolved = unresolved[:commitPRLookupsPerFetch]
+	}
+
+	var g errgroup.Group
+	g.SetLimit(commitPRLookupWorkers)
+	for _, commit := range unresolved {
+		g.Go(func() error {
+			prs, _, err := client.PullRequests.ListPullRequestsWithCommit(ctx, owner, repoName, commit.Hash, nil)
+			if err != nil {
+				// Not cached, so the next fetch tries again
+				log.Printf("Failed to look up pull request of commit %s: %v", commit.Hash, err)
+				return nil
+			}
+
+			number := 0
+			for _, pr := range prs {
+				// Prefer the merged pull request over open ones that also contain the commit
+				if number == 0 || pr.MergedAt != nil {
+					number = pr.GetNumber()
+				}
+				if pr.MergedAt != nil {
+					break
+				}
+			}
+			commit.PRNumber = number
+			a.commitPRCache.put(commit.Hash, number)
+			return nil
+		})
+	}
+	g.Wait()
+}
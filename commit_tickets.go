@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"dev-dashboard/internal/jira"
+	"dev-dashboard/pkg/types"
+)
+
+const (
+	// commitTicketPatternKey is the regular expression JIRA project keys in commit messages match,
+	// e.g. PAY|OPS; empty matches any project (jira.DefaultProjectKeyPattern)
+	commitTicketPatternKey = "commit_ticket_project_pattern"
+	// commitTicketRefsPatternKey is the pattern the stored commit ticket references were extracted
+	// with. When it differs from the configured one they're extracted again the next time they're
+	// used, so changing the pattern doesn't have to wait on a rescan.
+	commitTicketRefsPatternKey = "commit_ticket_refs_pattern"
+)
+
+// commitTicketPattern returns the configured project key pattern and its extractor, or those of
+// any project when the stored pattern doesn't compile
+func (a *App) commitTicketPattern() (string, *jira.KeyExtractor) {
+	if value, err := a.GetConfig(commitTicketPatternKey); err == nil && value != "" {
+		if extractor, err := jira.NewKeyExtractor(value); err == nil {
+			return value, extractor
+		}
+		log.Printf("Invalid %s %q, matching any project", commitTicketPatternKey, value)
+	}
+	extractor, _ := jira.NewKeyExtractor("")
+	return "", extractor
+}
+
+// rescanCommitTicketRefs extracts the stored references again when the pattern changed since they
+// were extracted, and returns the extractor of the current pattern
+func (a *App) rescanCommitTicketRefs() (*jira.KeyExtractor, error) {
+	pattern, extractor := a.commitTicketPattern()
+	if scanned, err := a.GetConfig(commitTicketRefsPatternKey); err != nil || scanned == pattern {
+		return extractor, err
+	}
+	if err := a.commitTicketRefModel.Reextract(extractor.Extract); err != nil {
+		return extractor, err
+	}
+	log.Printf("Extracted commit ticket references again with project key pattern %q", pattern)
+	return extractor, a.configModel.Set(commitTicketRefsPatternKey, pattern)
+}
+
+// recordCommitTicketRefs stores the JIRA issues the messages of a repository's freshly loaded
+// commits mention. References are an extra, so failures are only logged.
+func (a *App) recordCommitTicketRefs(repositoryID int64, commits []*types.Commit) {
+	if a.commitTicketRefModel == nil || len(commits) == 0 {
+		return
+	}
+	extractor, err := a.rescanCommitTicketRefs()
+	if err != nil {
+		log.Printf("Failed to extract commit ticket references again: %v", err)
+	}
+	if err := a.commitTicketRefModel.Record(repositoryID, commits, extractor.Extract); err != nil {
+		log.Printf("Failed to record commit ticket references: %v", err)
+	}
+}
+
+// attachCommitTickets sets the JIRA issues each commit's message mentions, linked when a JIRA URL
+// is configured
+func (a *App) attachCommitTickets(commits []*types.Commit) {
+	if len(commits) == 0 {
+		return
+	}
+	_, extractor := a.commitTicketPattern()
+	jiraClient := a.jiraClient
+	for _, commit := range commits {
+		commit.Tickets = nil
+		for _, key := range extractor.Extract(commit.Message) {
+			ticket := types.CommitTicket{Key: key}
+			if jiraClient != nil {
+				ticket.URL = jiraClient.IssueURL(key)
+			}
+			commit.Tickets = append(commit.Tickets, ticket)
+		}
+	}
+}
+
+// GetCommitsForTicket returns the commits whose messages mention a JIRA issue, newest first. Only
+// commits loaded since the lookup was added (on a service's commits or deployment history) are known.
+func (a *App) GetCommitsForTicket(ticketID string) ([]*types.TicketCommit, error) {
+	if a.commitTicketRefModel == nil || a.repoModel == nil {
+		return nil, fmt.Errorf("commit ticket model not initialized")
+	}
+	ticketID = strings.ToUpper(strings.TrimSpace(ticketID))
+	if ticketID == "" {
+		return nil, fmt.Errorf("ticket ID is required")
+	}
+
+	if _, err := a.rescanCommitTicketRefs(); err != nil {
+		log.Printf("Failed to extract commit ticket references again: %v", err)
+	}
+	commits, err := a.commitTicketRefModel.GetByTicket(ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	repos, err := a.repoModel.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	repoURLs := make(map[int64]string, len(repos))
+	for _, repo := range repos {
+		repoURLs[repo.ID] = strings.TrimSuffix(strings.TrimSuffix(repo.URL, "/"), ".git")
+	}
+	clock := a.displayClock()
+	for _, commit := range commits {
+		if repoURL := repoURLs[commit.RepositoryID]; repoURL != "" {
+			commit.URL = repoURL + "/commit/" + commit.Hash
+		}
+		commit.DateRelative = clock.relative(commit.Date)
+	}
+	return commits, nil
+}
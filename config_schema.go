@@ -0,0 +1,203 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"dev-dashboard/internal/config"
+	"dev-dashboard/internal/github"
+	"dev-dashboard/internal/jira"
+	"dev-dashboard/internal/sync"
+	"dev-dashboard/internal/telemetry"
+	"dev-dashboard/pkg/types"
+)
+
+// newConfigSchema declares every config key the app reads. SetConfig rejects keys that aren't
+// declared here, so a new key has to be added before it can be set.
+func newConfigSchema() *config.Schema {
+	return config.NewSchema(
+		// GitHub
+		config.Key{Name: "github_token", Type: config.TypeString, Secret: true,
+			Description: "GitHub personal access token", Default: "imported from ~/.dev-dashboard/credentials.json or the keychain, else the GITHUB_TOKEN environment variable"},
+		config.Key{Name: githubTokenSourceKey, Type: config.TypeEnum, Internal: true,
+			Values:      []string{string(types.TokenSourceCredentialsFile), string(types.TokenSourceKeychain)},
+			Description: "Where the GitHub token was imported from on startup"},
+		config.Key{Name: githubTokenExpiresAtKey, Type: config.TypeString, Internal: true,
+			Description: "When GitHub last reported the token expires, RFC 3339"},
+		config.Key{Name: githubTokenExpiryWarningDaysKey, Type: config.TypeInt, Default: fmt.Sprint(defaultTokenExpiryWarningDays),
+			Description: "Days before the GitHub token expires a warning is shown; 0 turns it off"},
+		config.Key{Name: "github_enterprise_url", Type: config.TypeURL,
+			Description: "GitHub Enterprise Server URL", Default: "github.com"},
+		config.Key{Name: githubAPIBaseURLKey, Type: config.TypeURL, Validate: validateGitHubAPIBaseURL,
+			Description: "GitHub API URL used as is, e.g. a mock server or an API proxy"},
+		config.Key{Name: githubRequestsPerSecondKey, Type: config.TypeNumber, Default: "10",
+			Description: "GitHub API requests per second the UI and the sync may send together; 0 doesn't limit them",
+			Validate: func(value string) error {
+				_, err := parseGitHubRequestsPerSecond(value)
+				return err
+			}},
+		config.Key{Name: "write_actions_enabled", Type: config.TypeBool, Default: "false",
+			Description: "Allow actions that change state on GitHub: approvals, re-runs and webhooks"},
+		config.Key{Name: "collect_actions_usage", Type: config.TypeBool, Default: "false",
+			Description: "Record the billable time of completed workflow runs"},
+		config.Key{Name: linkCommitPullRequestsKey, Type: config.TypeBool, Default: "false",
+			Description: "Ask GitHub for the pull request of commits whose message doesn't name one"},
+
+		// Discovery and sync
+		config.Key{Name: "service_description_sources", Type: config.TypeList,
+			Description: "Files discovered services are described from, tried in order",
+			Default:     "service.yaml:description,README.md,package.json:description",
+			Validate: func(value string) error {
+				_, err := github.ParseDescriptionSources(value)
+				return err
+			}},
+		config.Key{Name: serviceDomainFoldersKey, Type: config.TypeBool, Default: "false",
+			Description: "Treat the directories under the service root as domain folders holding the services"},
+		config.Key{Name: discoveryReviewWindowKey, Type: config.TypeInt, Default: "0",
+			Description: "Hours discovery changes of repositories in review mode wait for review; 0 keeps them waiting"},
+		config.Key{Name: discoveryReviewExpiredActionKey, Type: config.TypeEnum, Values: []string{"expire", "apply"}, Default: "expire",
+			Description: "What happens to discovery changes left unreviewed past the review window"},
+		config.Key{Name: syncStuckMultipleKey, Type: config.TypeNumber, Default: fmt.Sprint(sync.DefaultStuckPassMultiple),
+			Description: "How many times its usual duration a sync pass may run before it's cancelled; 0 disables the watchdog",
+			Validate: func(value string) error {
+				_, err := parseSyncStuckMultiple(value)
+				return err
+			}},
+		config.Key{Name: correlationRetryHoursKey, Type: config.TypeInt, Default: "24", Validate: validateCorrelationRetryHours,
+			Description: "Hours sync keeps matching deployment tags with monorepo commits after falling back; 0 doesn't retry"},
+		config.Key{Name: envVarSnapshotsKey, Type: config.TypeBool, Default: "false",
+			Description: "Record the environment variables of each overlay's Deployment"},
+		config.Key{Name: packageVersionLookupKey, Type: config.TypeBool, Default: "false",
+			Description: "Look up the latest versions of collected packages in the Go module proxy and the npm registry"},
+		config.Key{Name: fluxHelmReleaseFieldsKey, Type: config.TypeList, Validate: validateFluxFieldPaths,
+			Description: "Field paths Flux HelmRelease versions are read from, tried in order"},
+		config.Key{Name: fluxKustomizationFieldsKey, Type: config.TypeList, Validate: validateFluxFieldPaths,
+			Description: "Field paths Flux Kustomization versions are read from, tried in order"},
+
+		// Deployments
+		config.Key{Name: "environment_order", Type: config.TypeList,
+			Description: "Order environments are listed in, e.g. dev,stg,prd", Default: "alphabetical"},
+		config.Key{Name: primaryEnvironmentKey, Type: config.TypeString, Default: "prd, prod or production",
+			Description: "Environment that is live for services that don't set their own"},
+		config.Key{Name: tagPrefixesKey, Type: config.TypeList, Default: "v",
+			Description: "Prefixes stripped from deployment tags before parsing them as semver"},
+		config.Key{Name: staleWarnDaysKey, Type: config.TypeInt, Default: "7",
+			Description: "Days old a deployment's commit may get before it's shown as aging"},
+		config.Key{Name: staleAlertDaysKey, Type: config.TypeInt, Default: "30",
+			Description: "Days old a deployment's commit may get before it's shown as stale"},
+		config.Key{Name: commitAnnotationsKey, Type: config.TypeList, Default: "git-commit,app.kubernetes.io/version",
+			Description: "Kustomization annotation or label keys naming a deployment's source commit, tried in order"},
+		config.Key{Name: rolloutStuckMinutesKey, Type: config.TypeInt, Default: "60",
+			Description: "Minutes an incomplete rollout may stall before a notification; 0 turns it off"},
+		config.Key{Name: firstDeployNotificationsKey, Type: config.TypeBool, Default: "true",
+			Description: "Notify when a service is first deployed to an environment and region"},
+		config.Key{Name: "reliability_exclude_cancelled", Type: config.TypeBool, Default: "false",
+			Description: "Leave cancelled runs out of reliability instead of counting them as unsuccessful"},
+
+		// Notifications
+		config.Key{Name: quietHoursKey, Type: config.TypeString, Default: "off",
+			Description: "Daily local-time window notifications are held back in, e.g. 22:00-07:00",
+			Validate: func(value string) error {
+				_, err := sync.ParseQuietWindow(value)
+				return err
+			}},
+		config.Key{Name: quietHoursEnvironmentsKey, Type: config.TypeList,
+			Description: "Quiet hours per environment, e.g. dev=20:00-09:00,prd=off",
+			Validate: func(value string) error {
+				_, err := sync.ParseEnvironmentQuietWindows(value)
+				return err
+			}},
+		config.Key{Name: quietHoursExemptEnvironmentsKey, Type: config.TypeList,
+			Description: "Environments whose notifications are delivered even during quiet hours"},
+		config.Key{Name: quietHoursExemptTypesKey, Type: config.TypeList,
+			Description: "Notification types delivered even during quiet hours"},
+		config.Key{Name: quietHoursModeKey, Type: config.TypeEnum, Values: []string{"queue", "suppress"}, Default: "queue",
+			Description: "Deliver notifications held back during quiet hours when they end, or drop them"},
+
+		// JIRA and tasks
+		config.Key{Name: "jira_url", Type: config.TypeURL, Description: "JIRA instance URL"},
+		config.Key{Name: "jira_username", Type: config.TypeString, Description: "JIRA username for basic authentication"},
+		config.Key{Name: "jira_token", Type: config.TypeString, Secret: true, Description: "JIRA API token or password"},
+		config.Key{Name: "jira_auth_method", Type: config.TypeEnum, Values: []string{"basic", "bearer", "token"}, Default: "basic",
+			Description: "How requests to JIRA authenticate"},
+		config.Key{Name: jiraPollEnabledKey, Type: config.TypeBool, Default: "true",
+			Description: "Poll the JIRA tickets linked to tasks in the background"},
+		config.Key{Name: jiraPollIntervalKey, Type: config.TypeInt, Default: "15",
+			Description: "Minutes between polls of the JIRA tickets linked to tasks",
+			Validate: func(value string) error {
+				if value == "0" {
+					return fmt.Errorf("%s must be a positive number of minutes, got %q", jiraPollIntervalKey, value)
+				}
+				return nil
+			}},
+		config.Key{Name: jiraSprintFieldKey, Type: config.TypeString, Default: jira.DefaultSprintField,
+			Description: "Custom field tickets' sprints are read from",
+			Validate: func(value string) error {
+				return validateJiraCustomField(jiraSprintFieldKey, value)
+			}},
+		config.Key{Name: jiraEpicLinkFieldKey, Type: config.TypeString,
+			Description: "Epic link custom field of instances that don't link epics through the parent",
+			Validate: func(value string) error {
+				return validateJiraCustomField(jiraEpicLinkFieldKey, value)
+			}},
+		config.Key{Name: jiraDueDateFillsDeadlineKey, Type: config.TypeBool, Default: "false",
+			Description: "Fill the deadline of tasks without one from their ticket's due date"},
+		config.Key{Name: commitTicketPatternKey, Type: config.TypeString, Default: "any project",
+			Description: "Regular expression JIRA project keys in commit messages match, e.g. PAY|OPS",
+			Validate: func(value string) error {
+				_, err := jira.NewKeyExtractor(value)
+				return err
+			}},
+		config.Key{Name: commitTicketRefsPatternKey, Type: config.TypeString, Internal: true,
+			Description: "Pattern the stored commit ticket references were extracted with"},
+		config.Key{Name: taskDefaultProjectKey, Type: config.TypeInt,
+			Description: "ID of the project quick-added tasks go to"},
+
+		// Scorecards
+		config.Key{Name: scorecardDisabledChecksKey, Type: config.TypeList, Internal: true,
+			Description: "Scorecard checks that don't run"},
+		config.Key{Name: scorecardThresholdKeyPrefix, Type: config.TypeNumber, Prefix: true, Internal: true,
+			Description: "Threshold override of a scorecard check, followed by the check ID"},
+
+		// App
+		config.Key{Name: timezoneKey, Type: config.TypeString, Default: "the system time zone",
+			Description: "IANA time zone relative timestamps are shown in, e.g. Europe/Berlin", Validate: validateTimezone},
+		config.Key{Name: slowQueryThresholdKey, Type: config.TypeInt, Default: "200",
+			Description: "Milliseconds after which a database statement is logged as slow; 0 disables",
+			Validate: func(value string) error {
+				_, err := parseSlowQueryThreshold(value)
+				return err
+			}},
+		config.Key{Name: collectUsageAnalyticsKey, Type: config.TypeBool, Default: "false",
+			Description: "Record which views are used, locally"},
+		config.Key{Name: telemetryEnabledKey, Type: config.TypeBool, Default: "false",
+			Description: "Count how often features are used"},
+		config.Key{Name: telemetryEndpointKey, Type: config.TypeURL, Validate: telemetry.ValidateEndpoint,
+			Description: "Where feature counts are sent every hour; empty keeps them local"},
+	)
+}
+
+func validateFluxFieldPaths(value string) error {
+	_, err := github.ParseFluxFieldPaths(value)
+	return err
+}
+
+// GetConfigSchema returns the known config keys with their types and descriptions, ordered by name
+func (a *App) GetConfigSchema() []*types.ConfigKey {
+	keys := a.configSchema.Keys()
+	result := make([]*types.ConfigKey, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, &types.ConfigKey{
+			Name:        key.Name,
+			Type:        string(key.Type),
+			Description: key.Description,
+			Default:     key.Default,
+			Values:      key.Values,
+			Secret:      key.Secret,
+			Prefix:      key.Prefix,
+			Internal:    key.Internal,
+		})
+	}
+	sort.SliceStable(result, func(i, j int) bool { return !result[i].Internal && result[j].Internal })
+	return result
+}
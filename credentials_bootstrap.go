@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"dev-dashboard/internal/credentials"
+	"dev-dashboard/pkg/types"
+)
+
+// githubTokenSourceKey records where a GitHub token imported on startup came from; it's cleared when
+// the token is changed in the settings
+const githubTokenSourceKey = "github_token_source"
+
+// keychainTimeout bounds reading the keychain, which may wait for the user to allow access
+const keychainTimeout = 30 * time.Second
+
+// credentialsFilePath returns the location of the credentials file next to the database
+func credentialsFilePath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".dev-dashboard", credentials.FileName), nil
+}
+
+// bootstrapGitHubToken imports a GitHub token on startup when none is stored. Apps started from the
+// Finder or Dock don't inherit the shell's environment, so GITHUB_TOKEN usually isn't set for them;
+// the credentials file and then the OS keychain are tried first, and the environment variable is
+// only used, with a warning, when neither has a token.
+func (a *App) bootstrapGitHubToken() {
+	if a.configModel == nil {
+		return
+	}
+	if config, err := a.configModel.Get("github_token"); err == nil && config != nil && config.Value != "" {
+		return
+	}
+
+	path, err := credentialsFilePath()
+	if err != nil {
+		log.Printf("Failed to locate the credentials file: %v", err)
+	} else if token, err := credentials.ReadFile(path); err != nil {
+		log.Printf("Ignoring the credentials file: %v", err)
+	} else if token != "" {
+		a.importGitHubToken(token, types.TokenSourceCredentialsFile, path)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), keychainTimeout)
+	defer cancel()
+	if token, err := credentials.ReadKeychain(ctx); err != nil {
+		log.Printf("Failed to read the GitHub token from the keychain: %v", err)
+	} else if token != "" {
+		a.importGitHubToken(token, types.TokenSourceKeychain, fmt.Sprintf("the keychain entry %s/%s", credentials.KeychainService, credentials.KeychainAccount))
+		return
+	}
+
+	if os.Getenv("GITHUB_TOKEN") != "" {
+		log.Printf("Warning: using the GITHUB_TOKEN environment variable. Apps started from the Finder or Dock don't see variables set in a shell, so set the token in the settings or in %s instead", path)
+	}
+}
+
+// importGitHubToken stores a token found on startup in the config and tells the user where it came from
+func (a *App) importGitHubToken(token string, source types.TokenSource, from string) {
+	if err := a.configModel.Set("github_token", token); err != nil {
+		log.Printf("Failed to import the GitHub token from %s: %v", from, err)
+		return
+	}
+	if err := a.configModel.Set(githubTokenSourceKey, string(source)); err != nil {
+		log.Printf("Failed to record where the GitHub token came from: %v", err)
+	}
+	log.Printf("Imported the GitHub token from %s", from)
+
+	if a.notifier != nil {
+		a.notifier.Notify(&types.Notification{
+			Type:    "github_token_imported",
+			Title:   "GitHub token imported",
+			Message: fmt.Sprintf("The GitHub token was imported from %s and is now stored in the settings.", from),
+		})
+	}
+}
+
+// githubTokenChanged reports whether token differs from the stored GitHub token
+func (a *App) githubTokenChanged(token string) bool {
+	config, err := a.configModel.Get("github_token")
+	return err != nil || config == nil || config.Value != token
+}
+
+// forgetGitHubTokenSource clears where the stored GitHub token came from, once the settings stored
+// a different one
+func (a *App) forgetGitHubTokenSource() {
+	if err := a.configModel.Delete(githubTokenSourceKey); err != nil {
+		log.Printf("Failed to clear where the GitHub token came from: %v", err)
+	}
+}
+
+// githubTokenSource reports where the token getGitHubToken returns came from
+func (a *App) githubTokenSource() types.TokenSource {
+	if a.configModel != nil {
+		if config, err := a.configModel.Get("github_token"); err == nil && config != nil && config.Value != "" {
+			if source, err := a.configModel.Get(githubTokenSourceKey); err == nil && source != nil && source.Value != "" {
+				return types.TokenSource(source.Value)
+			}
+			return types.TokenSourceSettings
+		}
+	}
+	if os.Getenv("GITHUB_TOKEN") != "" {
+		return types.TokenSourceEnvironment
+	}
+	return types.TokenSourceNone
+}
+
+// GetSetupStatus reports whether the database is ready, where the GitHub token in use came from
+// and whether the background sync runs
+func (a *App) GetSetupStatus() *types.SetupStatus {
+	status := &types.SetupStatus{
+		DatabaseReady:     a.configModel != nil,
+		GitHubTokenSource: a.githubTokenSource(),
+		SyncRunning:       a.syncService != nil,
+	}
+	if path, err := credentialsFilePath(); err == nil {
+		status.CredentialsFile = path
+	}
+	return status
+}
@@ -0,0 +1,304 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"dev-dashboard/pkg/types"
+)
+
+// attachServiceCustomFields fills in the custom field values of services. Failures are only logged
+// so the service list still loads.
+func (a *App) attachServiceCustomFields(services []*types.Microservice) {
+	if a.customFieldModel == nil || len(services) == 0 {
+		return
+	}
+	values, err := a.customFieldModel.GetValues(types.CustomFieldEntityService)
+	if err != nil {
+		log.Printf("Failed to get service custom fields: %v", err)
+		return
+	}
+	for _, service := range services {
+		service.CustomFields = values[service.ID]
+	}
+}
+
+// normalizeAllowedValues trims and deduplicates the values of an enum field, keeping their order
+func normalizeAllowedValues(fieldType string, values []string) ([]string, error) {
+	if fieldType != types.CustomFieldEnum {
+		return nil, nil
+	}
+	seen := make(map[string]bool)
+	normalized := []string{}
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" || seen[value] {
+			continue
+		}
+		if strings.ContainsAny(value, "\r\n") {
+			return nil, fmt.Errorf("allowed value %q can't contain line breaks", value)
+		}
+		seen[value] = true
+		normalized = append(normalized, value)
+	}
+	if len(normalized) == 0 {
+		return nil, fmt.Errorf("enum fields need at least one allowed value")
+	}
+	return normalized, nil
+}
+
+// normalizeCustomFieldValue checks a value against its field's type. Bool values are stored as
+// "true" or "false"; empty values clear the field.
+func normalizeCustomFieldValue(field *types.CustomFieldDefinition, value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", nil
+	}
+
+	switch field.Type {
+	case types.CustomFieldBool:
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return "", fmt.Errorf("%s must be true or false, got %q", field.Name, value)
+		}
+		return strconv.FormatBool(parsed), nil
+	case types.CustomFieldEnum:
+		for _, allowed := range field.AllowedValues {
+			if strings.EqualFold(allowed, value) {
+				return allowed, nil
+			}
+		}
+		return "", fmt.Errorf("%s must be one of %s, got %q", field.Name, strings.Join(field.AllowedValues, ", "), value)
+	}
+	return value, nil
+}
+
+// customFieldByName returns the field of an entity type named name, or nil
+func customFieldByName(fields []*types.CustomFieldDefinition, name string) *types.CustomFieldDefinition {
+	for _, field := range fields {
+		if strings.EqualFold(field.Name, name) {
+			return field
+		}
+	}
+	return nil
+}
+
+// createCustomField validates and stores a new field of an entity type
+func (a *App) createCustomField(entityType, name, fieldType string, allowedValues []string) (*types.CustomFieldDefinition, error) {
+	if a.customFieldModel == nil {
+		return nil, fmt.Errorf("custom field model not initialized")
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("custom field name is required")
+	}
+	switch fieldType {
+	case types.CustomFieldText, types.CustomFieldEnum, types.CustomFieldBool:
+	default:
+		return nil, fmt.Errorf("custom field type must be text, enum or bool, got %q", fieldType)
+	}
+	allowedValues, err := normalizeAllowedValues(fieldType, allowedValues)
+	if err != nil {
+		return nil, err
+	}
+
+	fields, err := a.customFieldModel.GetDefinitions(entityType)
+	if err != nil {
+		return nil, err
+	}
+	if customFieldByName(fields, name) != nil {
+		return nil, fmt.Errorf("a %s field named %q already exists", entityType, name)
+	}
+
+	field := &types.CustomFieldDefinition{
+		EntityType:    entityType,
+		Name:          name,
+		Type:          fieldType,
+		AllowedValues: allowedValues,
+	}
+	if err := a.customFieldModel.CreateDefinition(field); err != nil {
+		return nil, err
+	}
+	return a.customFieldModel.GetDefinition(field.ID)
+}
+
+// updateCustomField renames a field and replaces its allowed values. Allowed values still in use
+// can't be removed.
+func (a *App) updateCustomField(entityType string, id int64, name string, allowedValues []string) error {
+	field, err := a.customField(entityType, id)
+	if err != nil {
+		return err
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("custom field name is required")
+	}
+	fields, err := a.customFieldModel.GetDefinitions(entityType)
+	if err != nil {
+		return err
+	}
+	if other := customFieldByName(fields, name); other != nil && other.ID != id {
+		return fmt.Errorf("a %s field named %q already exists", entityType, name)
+	}
+
+	allowedValues, err = normalizeAllowedValues(field.Type, allowedValues)
+	if err != nil {
+		return err
+	}
+	kept := make(map[string]bool)
+	for _, value := range allowedValues {
+		kept[value] = true
+	}
+	var removed []string
+	for _, value := range field.AllowedValues {
+		if !kept[value] {
+			removed = append(removed, value)
+		}
+	}
+	inUse, err := a.customFieldModel.CountValuesIn(id, removed)
+	if err != nil {
+		return err
+	}
+	if inUse > 0 {
+		return fmt.Errorf("%d %s(s) still use the removed values %s; change them first", inUse, entityType, strings.Join(removed, ", "))
+	}
+
+	field.Name = name
+	field.AllowedValues = allowedValues
+	return a.customFieldModel.UpdateDefinition(field)
+}
+
+// customField returns a field of an entity type, or an error when there is no such field
+func (a *App) customField(entityType string, id int64) (*types.CustomFieldDefinition, error) {
+	if a.customFieldModel == nil {
+		return nil, fmt.Errorf("custom field model not initialized")
+	}
+	field, err := a.customFieldModel.GetDefinition(id)
+	if err != nil {
+		return nil, err
+	}
+	if field == nil || field.EntityType != entityType {
+		return nil, fmt.Errorf("%s custom field %d not found", entityType, id)
+	}
+	return field, nil
+}
+
+// setCustomFieldValue validates and stores an entity's value of a field
+func (a *App) setCustomFieldValue(entityType string, fieldID, entityID int64, value string) error {
+	field, err := a.customField(entityType, fieldID)
+	if err != nil {
+		return err
+	}
+	value, err = normalizeCustomFieldValue(field, value)
+	if err != nil {
+		return err
+	}
+	return a.customFieldModel.SetValue(fieldID, entityID, value)
+}
+
+// GetServiceCustomFields returns the custom fields defined for services, with how many services
+// have a value for each
+func (a *App) GetServiceCustomFields() ([]*types.CustomFieldDefinition, error) {
+	if a.customFieldModel == nil {
+		return nil, fmt.Errorf("custom field model not initialized")
+	}
+	return a.customFieldModel.GetDefinitions(types.CustomFieldEntityService)
+}
+
+// CreateServiceCustomField defines a custom field for services. fieldType is text, enum or bool;
+// allowedValues is only used for enum fields.
+func (a *App) CreateServiceCustomField(name, fieldType string, allowedValues []string) (*types.CustomFieldDefinition, error) {
+	return a.createCustomField(types.CustomFieldEntityService, name, fieldType, allowedValues)
+}
+
+// UpdateServiceCustomField renames a service custom field and replaces its allowed values
+func (a *App) UpdateServiceCustomField(id int64, name string, allowedValues []string) error {
+	return a.updateCustomField(types.CustomFieldEntityService, id, name, allowedValues)
+}
+
+// DeleteServiceCustomField deletes a service custom field and every service's value of it. The
+// frontend confirms first, showing the field's value count.
+func (a *App) DeleteServiceCustomField(id int64) error {
+	if _, err := a.customField(types.CustomFieldEntityService, id); err != nil {
+		return err
+	}
+	return a.customFieldModel.DeleteDefinition(id)
+}
+
+// SetServiceCustomFieldValue sets a service's value of a custom field; an empty value clears it
+func (a *App) SetServiceCustomFieldValue(serviceID, fieldID int64, value string) error {
+	if a.serviceModel == nil {
+		return fmt.Errorf("service model not initialized")
+	}
+	if _, err := a.serviceModel.GetByID(serviceID); err != nil {
+		return err
+	}
+	return a.setCustomFieldValue(types.CustomFieldEntityService, fieldID, serviceID, value)
+}
+
+// FilterMicroservices returns the services GetMicroservices returns whose custom fields match all
+// filters
+func (a *App) FilterMicroservices(repositoryID int64, includeHidden bool, filters []types.CustomFieldFilter) ([]*types.Microservice, error) {
+	if a.customFieldModel == nil {
+		return nil, fmt.Errorf("custom field model not initialized")
+	}
+	match, err := a.customFieldMatcher(filters)
+	if err != nil {
+		return nil, err
+	}
+
+	services, err := a.GetMicroservices(repositoryID, includeHidden)
+	if err != nil {
+		return nil, err
+	}
+	filtered := []*types.Microservice{}
+	for _, service := range services {
+		if match(service) {
+			filtered = append(filtered, service)
+		}
+	}
+	return filtered, nil
+}
+
+// customFieldMatcher returns whether a service (with its custom fields attached) matches all
+// custom field filters
+func (a *App) customFieldMatcher(filters []types.CustomFieldFilter) (func(*types.Microservice) bool, error) {
+	if len(filters) == 0 {
+		return func(*types.Microservice) bool { return true }, nil
+	}
+	if a.customFieldModel == nil {
+		return nil, fmt.Errorf("custom field model not initialized")
+	}
+	fields, err := a.customFieldModel.GetDefinitions(types.CustomFieldEntityService)
+	if err != nil {
+		return nil, err
+	}
+
+	// Filter values are normalized like stored values, so "True" matches "true"
+	type match struct{ name, value string }
+	matches := make([]match, 0, len(filters))
+	for _, filter := range filters {
+		field := customFieldByName(fields, filter.Field)
+		if field == nil {
+			return nil, fmt.Errorf("unknown service custom field %q", filter.Field)
+		}
+		value, err := normalizeCustomFieldValue(field, filter.Value)
+		if err != nil {
+			return nil, err
+		}
+		matches = append(matches, match{name: field.Name, value: value})
+	}
+
+	return func(service *types.Microservice) bool {
+		for _, m := range matches {
+			if service.CustomFields[m.name] != m.value {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
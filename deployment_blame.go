@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"dev-dashboard/internal/telemetry"
+	"dev-dashboard/internal/vcs"
+	"dev-dashboard/pkg/types"
+)
+
+// GetDeploymentBlame traces what a service runs in an environment back to the pull request that
+// produced it: the commit the deployed tag was correlated with during sync, the pull request that
+// introduced that commit and its author. An empty region picks the environment's newest deployment
+// across regions. Failing to correlate the tag or to find the pull request isn't an error; the
+// blame says what's unknown and why.
+func (a *App) GetDeploymentBlame(serviceID int64, environment, region string) (*types.DeploymentBlame, error) {
+	if a.deploymentModel == nil || a.serviceModel == nil {
+		return nil, fmt.Errorf("deployment model not initialized")
+	}
+	a.recordFeature(telemetry.DeploymentBlame)
+
+	service, err := a.serviceModel.GetByID(serviceID)
+	if err != nil {
+		return nil, fmt.Errorf("service not found: %w", err)
+	}
+	deployments, err := a.deploymentModel.GetByServiceID(serviceID)
+	if err != nil {
+		return nil, err
+	}
+	var matching []*types.Deployment
+	for _, deployment := range deployments {
+		if strings.EqualFold(deployment.Environment, environment) &&
+			(region == "" || strings.EqualFold(deployment.Region, region)) {
+			matching = append(matching, deployment)
+		}
+	}
+	if len(matching) == 0 {
+		if region != "" {
+			return nil, fmt.Errorf("%s has no deployment in %s %s", service.Name, environment, region)
+		}
+		return nil, fmt.Errorf("%s has no deployment in %s", service.Name, environment)
+	}
+	deployment := representativeDeployments(matching)[matching[0].Environment]
+
+	blame := &types.DeploymentBlame{
+		ServiceID:   serviceID,
+		Environment: deployment.Environment,
+		Region:      deployment.Region,
+		Namespace:   deployment.Namespace,
+		Tag:         deployment.Tag,
+		CommitSHA:   deployment.CommitSHA,
+	}
+	if blame.CommitSHA == "" {
+		blame.CommitUnknown = fmt.Sprintf("Tag %s isn't correlated with a commit of %s", deployment.Tag, service.Name)
+		blame.PRUnknown = "No commit to look up"
+		return blame, nil
+	}
+
+	githubToken := a.getGitHubToken()
+	if githubToken == "" {
+		blame.PRUnknown = "GitHub token not configured"
+		return blame, nil
+	}
+	repo, err := a.repoModel.GetByID(service.RepositoryID)
+	if err != nil {
+		blame.PRUnknown = "Service repository not found"
+		return blame, nil
+	}
+	owner, repoName, err := vcs.ParseGitHubURL(repo.URL)
+	if err != nil {
+		blame.PRUnknown = "Service repository isn't on GitHub"
+		return blame, nil
+	}
+
+	ctx := context.Background()
+	client := a.createGitHubClient(githubToken)
+
+	prNumber := 0
+	commit, _, err := client.Repositories.GetCommit(ctx, owner, repoName, blame.CommitSHA, nil)
+	if err != nil {
+		log.Printf("Failed to get commit %s of %s: %v", blame.CommitSHA, service.Name, err)
+	} else {
+		message := commit.GetCommit().GetMessage()
+		blame.CommitMessage, _, _ = strings.Cut(message, "\n")
+		blame.CommitAuthor = commit.GetCommit().GetAuthor().GetName()
+		if date := commit.GetCommit().GetAuthor().GetDate(); !date.IsZero() {
+			committedAt := date.Time
+			blame.CommittedAt = &committedAt
+		}
+		blame.CommitURL = commit.GetHTMLURL()
+		prNumber = pullRequestNumberFromMessage(message)
+	}
+
+	if prNumber == 0 {
+		if number, ok := a.commitPRCache.get(blame.CommitSHA); ok {
+			prNumber = number
+		} else {
+			prs, _, err := client.PullRequests.ListPullRequestsWithCommit(ctx, owner, repoName, blame.CommitSHA, nil)
+			if err != nil {
+				log.Printf("Failed to look up pull request of commit %s: %v", blame.CommitSHA, err)
+				blame.PRUnknown = "Couldn't look up the pull request of the commit"
+				return blame, nil
+			}
+			for _, pr := range prs {
+				// Prefer the merged pull request over open ones that also contain the commit
+				if prNumber == 0 || pr.MergedAt != nil {
+					prNumber = pr.GetNumber()
+				}
+				if pr.MergedAt != nil {
+					break
+				}
+			}
+			a.commitPRCache.put(blame.CommitSHA, prNumber)
+		}
+	}
+	if prNumber == 0 {
+		blame.PRUnknown = "The commit wasn't introduced by a pull request"
+		return blame, nil
+	}
+
+	blame.PRNumber = prNumber
+	pr, _, err := client.PullRequests.Get(ctx, owner, repoName, prNumber)
+	if err != nil {
+		log.Printf("Failed to get pull request #%d of %s: %v", prNumber, service.Name, err)
+		blame.PRUnknown = fmt.Sprintf("Couldn't get pull request #%d", prNumber)
+		return blame, nil
+	}
+	blame.PRTitle = pr.GetTitle()
+	blame.PRAuthor = pr.GetUser().GetLogin()
+	blame.PRURL = pr.GetHTMLURL()
+	if pr.MergedAt != nil {
+		mergedAt := pr.MergedAt.Time
+		blame.PRMergedAt = &mergedAt
+	}
+	return blame, nil
+}
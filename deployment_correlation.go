@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"dev-dashboard/internal/sync"
+)
+
+// correlationRetryHoursKey is how many hours sync keeps trying to match a deployment's tag with a
+// monorepo commit after falling back to the kubernetes repository's commit; 0 doesn't retry
+const correlationRetryHoursKey = "correlation_retry_hours"
+
+func validateCorrelationRetryHours(value string) error {
+	if hours, err := strconv.Atoi(value); err != nil || hours < 0 {
+		return fmt.Errorf("%s must be a number of hours, got %q", correlationRetryHoursKey, value)
+	}
+	return nil
+}
+
+// getCorrelationRetryWindow returns the correlation_retry_hours config key as a duration
+func (a *App) getCorrelationRetryWindow() time.Duration {
+	if a.configModel != nil {
+		if config, err := a.configModel.Get(correlationRetryHoursKey); err == nil && config != nil && config.Value != "" {
+			if hours, err := strconv.Atoi(config.Value); err == nil && hours >= 0 {
+				return time.Duration(hours) * time.Hour
+			}
+		}
+	}
+	return sync.DefaultCorrelationRetryWindow
+}
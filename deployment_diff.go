@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"dev-dashboard/internal/diff"
+	"dev-dashboard/internal/telemetry"
+	"dev-dashboard/internal/vcs"
+	"dev-dashboard/pkg/types"
+
+	goGithub "github.com/google/go-github/v57/github"
+)
+
+// fileDiffCache holds computed kustomization diffs keyed by the pair of blob SHAs they compare.
+// Blobs are content addressed, so entries never go stale.
+type fileDiffCache struct {
+	mu      sync.Mutex
+	entries map[string]*types.DeploymentFileDiff
+}
+
+func newFileDiffCache() *fileDiffCache {
+	return &fileDiffCache{entries: make(map[string]*types.DeploymentFileDiff)}
+}
+
+func (c *fileDiffCache) get(key string) (*types.DeploymentFileDiff, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	return entry, ok
+}
+
+func (c *fileDiffCache) put(key string, entry *types.DeploymentFileDiff) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = entry
+}
+
+// imageLinePrefixes identify kustomization lines that pin which image gets deployed
+var imageLinePrefixes = []string{"newTag:", "newName:", "digest:", "image:"}
+
+// GetDeploymentFileDiff compares a deployment's kustomization file with the version before its last change
+func (a *App) GetDeploymentFileDiff(deploymentID int64) (*types.DeploymentFileDiff, error) {
+	if a.deploymentModel == nil {
+		return nil, fmt.Errorf("deployment model not initialized")
+	}
+	a.recordFeature(telemetry.DeploymentFileDiff)
+
+	deployment, err := a.deploymentModel.GetByID(deploymentID)
+	if err != nil {
+		return nil, err
+	}
+	if deployment.Path == "" {
+		return nil, fmt.Errorf("deployment has no kustomization path")
+	}
+
+	repo, err := a.repoModel.GetByID(deployment.KubernetesRepoID)
+	if err != nil {
+		return nil, fmt.Errorf("kubernetes repository not found: %w", err)
+	}
+
+	githubToken := a.getGitHubToken()
+	if githubToken == "" {
+		return nil, fmt.Errorf("GitHub token not configured")
+	}
+
+	owner, repoName, err := vcs.ParseGitHubURL(repo.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid repository URL: %w", err)
+	}
+
+	ctx := context.Background()
+	client := a.createGitHubClient(githubToken)
+
+	// The most recent commit touching the file is the change we want to show
+	commits, _, err := client.Repositories.ListCommits(ctx, owner, repoName, &goGithub.CommitsListOptions{
+		Path:        deployment.Path,
+		ListOptions: goGithub.ListOptions{PerPage: 1},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commits for %s: %w", deployment.Path, err)
+	}
+	if len(commits) == 0 {
+		return nil, fmt.Errorf("no commits found for %s", deployment.Path)
+	}
+
+	lastCommit, _, err := client.Repositories.GetCommit(ctx, owner, repoName, commits[0].GetSHA(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit %s: %w", commits[0].GetSHA(), err)
+	}
+
+	result := &types.DeploymentFileDiff{
+		DeploymentID: deploymentID,
+		Path:         deployment.Path,
+		CommitSHA:    lastCommit.GetSHA(),
+	}
+
+	previousPath := deployment.Path
+	for _, file := range lastCommit.Files {
+		if file.GetFilename() != deployment.Path {
+			continue
+		}
+		switch file.GetStatus() {
+		case "added":
+			result.IsNewFile = true
+		case "renamed":
+			previousPath = file.GetPreviousFilename()
+			result.PreviousPath = previousPath
+		}
+	}
+
+	current, _, _, err := client.Repositories.GetContents(ctx, owner, repoName, deployment.Path, &goGithub.RepositoryContentGetOptions{Ref: result.CommitSHA})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s: %w", deployment.Path, err)
+	}
+	result.CurrentBlobSHA = current.GetSHA()
+
+	var previous *goGithub.RepositoryContent
+	if !result.IsNewFile && len(lastCommit.Parents) > 0 {
+		opts := &goGithub.RepositoryContentGetOptions{Ref: lastCommit.Parents[0].GetSHA()}
+		var resp *goGithub.Response
+		previous, _, resp, err = client.Repositories.GetContents(ctx, owner, repoName, previousPath, opts)
+		if err != nil {
+			if resp == nil || resp.StatusCode != http.StatusNotFound {
+				return nil, fmt.Errorf("failed to get previous version of %s: %w", previousPath, err)
+			}
+			previous = nil
+		}
+	}
+	if previous == nil {
+		result.IsNewFile = true
+		result.PreviousPath = ""
+	} else {
+		result.PreviousBlobSHA = previous.GetSHA()
+	}
+
+	cacheKey := result.PreviousBlobSHA + ":" + result.CurrentBlobSHA
+	if cached, ok := a.diffCache.get(cacheKey); ok {
+		hit := *cached
+		hit.DeploymentID = result.DeploymentID
+		hit.Path = result.Path
+		hit.PreviousPath = result.PreviousPath
+		hit.CommitSHA = result.CommitSHA
+		return &hit, nil
+	}
+
+	result.CurrentContent, err = current.GetContent()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", deployment.Path, err)
+	}
+	if previous != nil {
+		result.PreviousContent, err = previous.GetContent()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode previous version of %s: %w", previousPath, err)
+		}
+	}
+
+	lines := diff.Lines(result.PreviousContent, result.CurrentContent)
+	oldName := "a/" + previousPath
+	if result.IsNewFile {
+		oldName = "/dev/null"
+	}
+	result.UnifiedDiff = diff.Unified(oldName, "b/"+deployment.Path, lines, 3)
+
+	result.Lines = make([]types.DeploymentDiffLine, 0, len(lines))
+	for _, line := range lines {
+		result.Lines = append(result.Lines, types.DeploymentDiffLine{
+			Type:          string(line.Type),
+			OldLine:       line.OldLine,
+			NewLine:       line.NewLine,
+			Content:       line.Content,
+			IsImageChange: line.Type != diff.Equal && isImageLine(line.Content),
+		})
+	}
+
+	a.diffCache.put(cacheKey, result)
+	return result, nil
+}
+
+// isImageLine reports whether a kustomization line sets an image name, tag or digest
+func isImageLine(line string) bool {
+	trimmed := strings.TrimPrefix(strings.TrimSpace(line), "- ")
+	// JSON kustomizations quote their keys: "newTag": "v1.2.3"
+	if strings.HasPrefix(trimmed, `"`) {
+		trimmed = strings.Replace(strings.TrimPrefix(trimmed, `"`), `":`, ":", 1)
+	}
+	for _, prefix := range imageLinePrefixes {
+		if strings.HasPrefix(trimmed, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"dev-dashboard/internal/vcs"
+	"dev-dashboard/pkg/types"
+)
+
+// tagPrefixesKey lists the prefixes stripped from deployment tags before parsing them as semver,
+// comma separated (e.g. "v,release-"); empty uses "v"
+const tagPrefixesKey = "deployment_tag_prefixes"
+
+// getTagPrefixes returns the configured deployment tag prefixes
+func (a *App) getTagPrefixes() []string {
+	value, err := a.GetConfig(tagPrefixesKey)
+	if err != nil || strings.TrimSpace(value) == "" {
+		return vcs.DefaultTagPrefixes
+	}
+	var prefixes []string
+	for _, prefix := range strings.Split(value, ",") {
+		if prefix = strings.TrimSpace(prefix); prefix != "" {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	return prefixes
+}
+
+// applyTagPrefixes re-parses the stored deployment tags with the configured prefixes and hands them
+// to the sync service for the deployments it finds from now on
+func (a *App) applyTagPrefixes() {
+	prefixes := a.getTagPrefixes()
+	if a.syncService != nil {
+		a.syncService.SetTagPrefixes(prefixes)
+	}
+	if a.deploymentModel == nil {
+		return
+	}
+
+	updated, err := a.deploymentModel.UpdateVersions(func(tag string) *types.TagVersion {
+		return vcs.ParseTagVersion(tag, prefixes)
+	})
+	if err != nil {
+		log.Printf("Failed to parse deployment tag versions: %v", err)
+	} else if updated > 0 {
+		log.Printf("Updated the parsed versions of %d deployments", updated)
+	}
+}
+
+// GetDeploymentDrift compares each environment a service is deployed to with the environment before
+// it in the promotion order. Versions are compared when both tags are semver; otherwise the deployed
+// commits are compared on GitHub.
+func (a *App) GetDeploymentDrift(serviceID int64) ([]*types.DeploymentDrift, error) {
+	if a.deploymentModel == nil || a.serviceModel == nil {
+		return nil, fmt.Errorf("deployment model not initialized")
+	}
+
+	deployments, err := a.deploymentModel.GetByServiceID(serviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	current := representativeDeployments(deployments)
+	environments := make([]string, 0, len(current))
+	for environment := range current {
+		environments = append(environments, environment)
+	}
+	a.sortEnvironments(environments)
+
+	compare := a.deployedCommitComparer(serviceID)
+	drifts := []*types.DeploymentDrift{}
+	for i := 1; i < len(environments); i++ {
+		drifts = append(drifts, environmentDrift(current[environments[i]], current[environments[i-1]], compare))
+	}
+
+	return drifts, nil
+}
+
+// representativeDeployments returns the deployment that represents each environment: its highest
+// version, or its latest deployment when none of its tags are semver
+func representativeDeployments(deployments []*types.Deployment) map[string]*types.Deployment {
+	current := make(map[string]*types.Deployment)
+	for _, deployment := range deployments {
+		if best, ok := current[deployment.Environment]; !ok || newerDeployment(deployment, best) {
+			current[deployment.Environment] = deployment
+		}
+	}
+	return current
+}
+
+// environmentDrift compares a deployment with the one it is promoted from, by version when both
+// tags are semver and by the commits between them otherwise. compare may be nil when GitHub can't
+// be asked.
+func environmentDrift(deployment, compared *types.Deployment, compare commitComparer) *types.DeploymentDrift {
+	drift := &types.DeploymentDrift{
+		Environment:     deployment.Environment,
+		ComparedTo:      compared.Environment,
+		Tag:             deployment.Tag,
+		ComparedTag:     compared.Tag,
+		Version:         deployment.Version,
+		ComparedVersion: compared.Version,
+		Method:          types.DriftUnknown,
+	}
+
+	switch {
+	case deployment.Version != nil && compared.Version != nil:
+		versionDrift(drift)
+	case deployment.CommitSHA != "" && deployment.CommitSHA == compared.CommitSHA:
+		drift.Method = types.DriftByCommits
+		drift.Summary = fmt.Sprintf("%s runs the same commit as %s", drift.Environment, drift.ComparedTo)
+	default:
+		if compare != nil {
+			commitDrift(drift, deployment.CommitSHA, compared.CommitSHA, compare)
+		}
+	}
+	if drift.Method == types.DriftUnknown {
+		drift.Summary = fmt.Sprintf("%s (%s) can't be compared with %s (%s)", drift.Environment, drift.Tag, drift.ComparedTo, drift.ComparedTag)
+	}
+	return drift
+}
+
+// newerDeployment reports whether deployment should represent its environment instead of best
+func newerDeployment(deployment, best *types.Deployment) bool {
+	switch {
+	case deployment.Version != nil && best.Version != nil:
+		return vcs.CompareTagVersions(deployment.Version, best.Version) > 0
+	case deployment.Version != nil || best.Version != nil:
+		return deployment.Version != nil
+	}
+	return deployment.UpdatedAt.After(best.UpdatedAt)
+}
+
+// versionDrift fills in the version deltas and summary of a drift between two semver tags
+func versionDrift(drift *types.DeploymentDrift) {
+	version, compared := drift.Version, drift.ComparedVersion
+	drift.Method = types.DriftByVersion
+	drift.MajorDelta = compared.Major - version.Major
+	drift.MinorDelta = compared.Minor - version.Minor
+	drift.PatchDelta = compared.Patch - version.Patch
+
+	order := vcs.CompareTagVersions(version, compared)
+	if order == 0 {
+		drift.Summary = fmt.Sprintf("%s runs the same version as %s", drift.Environment, drift.ComparedTo)
+		return
+	}
+	direction := "behind"
+	if order > 0 {
+		direction = "ahead of"
+	}
+
+	var delta int
+	var component string
+	switch {
+	case drift.MajorDelta != 0:
+		delta, component = drift.MajorDelta, "major"
+	case drift.MinorDelta != 0:
+		delta, component = drift.MinorDelta, "minor"
+	case drift.PatchDelta != 0:
+		delta, component = drift.PatchDelta, "patch"
+	default:
+		// Only the prereleases differ
+		drift.Summary = fmt.Sprintf("%s is %s %s (%s vs %s)", drift.Environment, direction, drift.ComparedTo, drift.Tag, drift.ComparedTag)
+		return
+	}
+	if delta < 0 {
+		delta = -delta
+	}
+	drift.Summary = fmt.Sprintf("%s is %d %s %s %s %s", drift.Environment, delta, component, plural(delta, "version", "versions"), direction, drift.ComparedTo)
+}
+
+// commitDrift compares the deployed commits of a drift that can't be compared by version
+func commitDrift(drift *types.DeploymentDrift, commitSHA, comparedSHA string, compare commitComparer) {
+	if commitSHA == "" || comparedSHA == "" {
+		return
+	}
+	behind, ahead, err := compare(commitSHA, comparedSHA)
+	if err != nil {
+		log.Printf("Failed to compare deployed commits %s and %s: %v", commitSHA, comparedSHA, err)
+		return
+	}
+
+	drift.Method = types.DriftByCommits
+	drift.CommitsBehind = behind
+	drift.CommitsAhead = ahead
+	switch {
+	case behind == 0 && ahead == 0:
+		drift.Summary = fmt.Sprintf("%s runs the same commit as %s", drift.Environment, drift.ComparedTo)
+	case ahead == 0:
+		drift.Summary = fmt.Sprintf("%s is %d %s behind %s", drift.Environment, behind, plural(behind, "commit", "commits"), drift.ComparedTo)
+	case behind == 0:
+		drift.Summary = fmt.Sprintf("%s is %d %s ahead of %s", drift.Environment, ahead, plural(ahead, "commit", "commits"), drift.ComparedTo)
+	default:
+		drift.Summary = fmt.Sprintf("%s is %d %s behind and %d ahead of %s", drift.Environment, behind, plural(behind, "commit", "commits"), ahead, drift.ComparedTo)
+	}
+}
+
+// commitComparer returns how many commits base lacks from head and has that head doesn't
+type commitComparer func(base, head string) (behind, ahead int, err error)
+
+// deployedCommitComparer compares commits in the service's repository on GitHub, or returns nil
+// when GitHub can't be asked
+func (a *App) deployedCommitComparer(serviceID int64) commitComparer {
+	githubToken := a.getGitHubToken()
+	if githubToken == "" {
+		return nil
+	}
+	service, err := a.serviceModel.GetByID(serviceID)
+	if err != nil {
+		return nil
+	}
+	repo, err := a.repoModel.GetByID(service.RepositoryID)
+	if err != nil {
+		return nil
+	}
+	owner, repoName, err := vcs.ParseGitHubURL(repo.URL)
+	if err != nil {
+		return nil
+	}
+
+	client := a.createGitHubClient(githubToken)
+	return func(base, head string) (int, int, error) {
+		comparison, _, err := client.Repositories.CompareCommits(context.Background(), owner, repoName, base, head, nil)
+		if err != nil {
+			return 0, 0, err
+		}
+		return comparison.GetAheadBy(), comparison.GetBehindBy(), nil
+	}
+}
+
+func plural(n int, singular, pluralForm string) string {
+	if n == 1 {
+		return singular
+	}
+	return pluralForm
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"dev-dashboard/pkg/types"
+)
+
+// GetServiceDeploymentsByEnvironment returns a service's deployments grouped by environment, in the
+// configured environment order, for services deployed to so many namespaces that the flat list of
+// GetServiceDeployments gets unwieldy. The filter narrows the deployments to an environment and
+// region and pages each environment's deployments; only the deployments of the page get their
+// relative times and commit checks.
+func (a *App) GetServiceDeploymentsByEnvironment(serviceID int64, filter types.DeploymentOverviewFilter) ([]*types.EnvironmentDeployments, error) {
+	if a.deploymentModel == nil {
+		return nil, fmt.Errorf("deployment model not initialized")
+	}
+	if filter.Offset < 0 || filter.Limit < 0 {
+		return nil, fmt.Errorf("offset and limit must not be negative")
+	}
+
+	deployments, err := a.deploymentModel.GetFilteredDeploymentOverview(serviceID, strings.TrimSpace(filter.Environment), strings.TrimSpace(filter.Region))
+	if err != nil {
+		return nil, err
+	}
+
+	groups := groupDeploymentsByEnvironment(deployments)
+	environments := make([]string, 0, len(groups))
+	byEnvironment := make(map[string]*types.EnvironmentDeployments, len(groups))
+	for _, group := range groups {
+		environments = append(environments, group.Environment)
+		byEnvironment[group.Environment] = group
+	}
+	a.sortEnvironments(environments)
+
+	primary := a.getPrimaryEnvironment(serviceID)
+	result := make([]*types.EnvironmentDeployments, 0, len(environments))
+	var page []*types.DeploymentOverview
+	for _, environment := range environments {
+		group := byEnvironment[environment]
+		group.IsPrimary = isPrimaryEnvironment(primary, environment)
+		group.Deployments = pageDeployments(group.Deployments, filter.Offset, filter.Limit)
+		page = append(page, group.Deployments...)
+		result = append(result, group)
+	}
+
+	a.displayClock().annotateDeployments(page)
+	a.attachDeploymentChecks(serviceID, page)
+	return result, nil
+}
+
+// groupDeploymentsByEnvironment splits deployments ordered by environment into one group per
+// environment, listing the regions each group's deployments are in
+func groupDeploymentsByEnvironment(deployments []*types.DeploymentOverview) []*types.EnvironmentDeployments {
+	var groups []*types.EnvironmentDeployments
+	var current *types.EnvironmentDeployments
+	for _, deployment := range deployments {
+		if current == nil || current.Environment != deployment.Environment {
+			current = &types.EnvironmentDeployments{Environment: deployment.Environment, Regions: []string{}}
+			groups = append(groups, current)
+		}
+		current.Total++
+		current.Deployments = append(current.Deployments, deployment)
+		if n := len(current.Regions); n == 0 || current.Regions[n-1] != deployment.Region {
+			current.Regions = append(current.Regions, deployment.Region)
+		}
+	}
+	return groups
+}
+
+// pageDeployments returns the deployments from offset on, at most limit of them unless limit is 0
+func pageDeployments(deployments []*types.DeploymentOverview, offset, limit int) []*types.DeploymentOverview {
+	if offset >= len(deployments) {
+		return []*types.DeploymentOverview{}
+	}
+	deployments = deployments[offset:]
+	if limit > 0 && limit < len(deployments) {
+		deployments = deployments[:limit]
+	}
+	return deployments
+}
@@ -0,0 +1,16 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SetDeploymentActualTag records the tag the cluster actually runs for a deployment, e.g. while
+// ArgoCD hasn't synced the tag committed to the kubernetes repository yet. The deployment shows as
+// pending sync until the two match; an empty tag clears the recorded one.
+func (a *App) SetDeploymentActualTag(deploymentID int64, tag string) error {
+	if a.deploymentModel == nil {
+		return fmt.Errorf("deployment model not initialized")
+	}
+	return a.deploymentModel.SetActualTag(deploymentID, strings.TrimSpace(tag))
+}
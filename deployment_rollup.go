@@ -0,0 +1,154 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"dev-dashboard/internal/sync"
+	"dev-dashboard/pkg/types"
+)
+
+// rolloutStuckMinutesKey is how many minutes an incomplete rollout may go without a namespace
+// moving to the new tag before a notification; 0 turns the notification off
+const rolloutStuckMinutesKey = "rollout_stuck_minutes"
+
+// GetServiceDeploymentRollups returns the service's deployments grouped by environment and region,
+// for services that deploy the same tag to many namespaces. The deployments themselves stay per
+// namespace; a group whose namespaces run different tags is flagged as diverged.
+func (a *App) GetServiceDeploymentRollups(serviceID int64) ([]*types.DeploymentRollup, error) {
+	if a.deploymentModel == nil {
+		return nil, fmt.Errorf("deployment model not initialized")
+	}
+
+	deployments, err := a.deploymentModel.GetDeploymentOverview(serviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	rollups := rollupDeployments(deployments)
+
+	primary := a.getPrimaryEnvironment(serviceID)
+	for _, rollup := range rollups {
+		rollup.IsPrimary = isPrimaryEnvironment(primary, rollup.Environment)
+	}
+
+	var environments []string
+	position := make(map[string]int)
+	for _, rollup := range rollups {
+		if _, ok := position[rollup.Environment]; !ok {
+			position[rollup.Environment] = 0
+			environments = append(environments, rollup.Environment)
+		}
+	}
+	a.sortEnvironments(environments)
+	for i, environment := range environments {
+		position[environment] = i
+	}
+	sort.SliceStable(rollups, func(i, j int) bool {
+		return position[rollups[i].Environment] < position[rollups[j].Environment]
+	})
+
+	return rollups, nil
+}
+
+// rollupDeployments groups deployments by environment and region, keeping the order of their first
+// appearance. A group's tag is the one most of its namespaces run, ties going to the most recently
+// updated tag; namespaces running anything else are listed as divergent.
+func rollupDeployments(deployments []*types.DeploymentOverview) []*types.DeploymentRollup {
+	type groupKey struct{ environment, region string }
+
+	var rollups []*types.DeploymentRollup
+	groups := make(map[groupKey]*types.DeploymentRollup)
+	for _, deployment := range deployments {
+		key := groupKey{deployment.Environment, deployment.Region}
+		rollup, ok := groups[key]
+		if !ok {
+			rollup = &types.DeploymentRollup{
+				Environment:         deployment.Environment,
+				Region:              deployment.Region,
+				Namespaces:          []string{},
+				DivergentNamespaces: []string{},
+			}
+			groups[key] = rollup
+			rollups = append(rollups, rollup)
+		}
+		rollup.Deployments = append(rollup.Deployments, deployment)
+	}
+
+	for _, rollup := range rollups {
+		namespacesByTag := make(map[string]int)
+		latestByTag := make(map[string]*types.DeploymentOverview)
+		for _, deployment := range rollup.Deployments {
+			rollup.Namespaces = append(rollup.Namespaces, deployment.Namespace)
+			if !deployment.Synced {
+				rollup.PendingSync = true
+			}
+			namespacesByTag[deployment.Tag]++
+			if latest, ok := latestByTag[deployment.Tag]; !ok || deployment.UpdatedAt.After(latest.UpdatedAt) {
+				latestByTag[deployment.Tag] = deployment
+			}
+			if deployment.UpdatedAt.After(rollup.UpdatedAt) {
+				rollup.UpdatedAt = deployment.UpdatedAt
+			}
+		}
+		rollup.NamespaceCount = len(rollup.Namespaces)
+
+		var common *types.DeploymentOverview
+		for _, deployment := range rollup.Deployments {
+			latest := latestByTag[deployment.Tag]
+			if common == nil {
+				common = latest
+				continue
+			}
+			count, commonCount := namespacesByTag[latest.Tag], namespacesByTag[common.Tag]
+			if count > commonCount || (count == commonCount && latest.UpdatedAt.After(common.UpdatedAt)) {
+				common = latest
+			}
+		}
+		rollup.Tag = common.Tag
+		rollup.CommitSHA = common.CommitSHA
+
+		for _, deployment := range rollup.Deployments {
+			if deployment.Tag != rollup.Tag {
+				rollup.DivergentNamespaces = append(rollup.DivergentNamespaces, deployment.Namespace)
+			}
+		}
+		rollup.Diverged = len(rollup.DivergentNamespaces) > 0
+	}
+
+	return rollups
+}
+
+// GetRolloutProgress reports how far the newest tag in an environment has rolled out across the
+// service's namespaces, with the namespaces still on older tags and an estimated completion time.
+// It is derived from the deployment history recorded by the sync.
+func (a *App) GetRolloutProgress(serviceID int64, environment string) (*types.RolloutProgress, error) {
+	if a.deploymentModel == nil {
+		return nil, fmt.Errorf("deployment model not initialized")
+	}
+
+	deployments, err := a.deploymentModel.GetByServiceID(serviceID)
+	if err != nil {
+		return nil, err
+	}
+	history, err := a.deploymentModel.GetHistoryByServiceID(serviceID, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	return sync.ComputeRolloutProgress(serviceID, environment, deployments, history, time.Now(), a.getRolloutStuckAfter())
+}
+
+// getRolloutStuckAfter returns the rollout_stuck_minutes config key as a duration
+func (a *App) getRolloutStuckAfter() time.Duration {
+	if a.configModel != nil {
+		if config, err := a.configModel.Get(rolloutStuckMinutesKey); err == nil && config != nil && config.Value != "" {
+			if minutes, err := strconv.Atoi(config.Value); err == nil && minutes >= 0 {
+				return time.Duration(minutes) * time.Minute
+			}
+		}
+	}
+	return sync.DefaultRolloutStuckAfter
+}
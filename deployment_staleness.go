@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"log"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"dev-dashboard/internal/github"
+	"dev-dashboard/internal/vcs"
+	"dev-dashboard/pkg/types"
+
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// staleWarnDaysKey is how many days old the commit a deployment runs may get before it's shown
+	// as aging (yellow)
+	staleWarnDaysKey = "deployment_stale_warn_days"
+	// staleAlertDaysKey is the same for stale (red)
+	staleAlertDaysKey = "deployment_stale_alert_days"
+
+	defaultStaleWarnDays  = 7
+	defaultStaleAlertDays = 30
+)
+
+// stalenessThresholds are the commit ages a deployment turns aging and stale at
+type stalenessThresholds struct {
+	warn  time.Duration
+	alert time.Duration
+}
+
+// classify returns the staleness of a deployment running a commit from commitDate, or unknown when
+// the date isn't known
+func (t stalenessThresholds) classify(commitDate *time.Time, now time.Time) string {
+	if commitDate == nil || commitDate.IsZero() {
+		return types.StalenessUnknown
+	}
+	switch age := now.Sub(*commitDate); {
+	case age >= t.alert:
+		return types.StalenessStale
+	case age >= t.warn:
+		return types.StalenessAging
+	default:
+		return types.StalenessFresh
+	}
+}
+
+// getStalenessThresholds returns the configured staleness thresholds
+func (a *App) getStalenessThresholds() stalenessThresholds {
+	return stalenessThresholds{
+		warn:  time.Duration(a.getStaleDays(staleWarnDaysKey, defaultStaleWarnDays)) * 24 * time.Hour,
+		alert: time.Duration(a.getStaleDays(staleAlertDaysKey, defaultStaleAlertDays)) * 24 * time.Hour,
+	}
+}
+
+func (a *App) getStaleDays(key string, fallback int) int {
+	if a.configModel != nil {
+		if config, err := a.configModel.Get(key); err == nil && config != nil && config.Value != "" {
+			if days, err := strconv.Atoi(config.Value); err == nil && days >= 0 {
+				return days
+			}
+		}
+	}
+	return fallback
+}
+
+// commitDateCache remembers the dates of commits by owner/repo@SHA. Commit dates never change, so
+// entries are kept for good; failed lookups aren't cached.
+type commitDateCache struct {
+	mu    sync.Mutex
+	dates map[string]time.Time
+}
+
+func newCommitDateCache() *commitDateCache {
+	return &commitDateCache{dates: make(map[string]time.Time)}
+}
+
+func (c *commitDateCache) get(key string) (time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	date, ok := c.dates[key]
+	return date, ok
+}
+
+func (c *commitDateCache) put(key string, date time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.dates[key] = date
+}
+
+// attachDeploymentStaleness sets the date of the commit each of a service's current deployments
+// runs, looked up in the service's repository, and its staleness. Deployments whose commit date
+// can't be found, e.g. without a GitHub token, are unknown.
+func (a *App) attachDeploymentStaleness(serviceID int64, deployments []*types.DeploymentOverview) {
+	if len(deployments) == 0 {
+		return
+	}
+	dates := a.deploymentCommitDates(serviceID, deployments)
+	thresholds := a.getStalenessThresholds()
+	now := time.Now()
+	for _, deployment := range deployments {
+		if date, ok := dates[deployment.CommitSHA]; ok {
+			deployment.CommitDate = &date
+		}
+		deployment.Staleness = thresholds.classify(deployment.CommitDate, now)
+	}
+}
+
+// deploymentCommitDates returns the dates of the commits deployments run by SHA, from the cache or
+// GitHub; failures are logged and leave the commit out
+func (a *App) deploymentCommitDates(serviceID int64, deployments []*types.DeploymentOverview) map[string]time.Time {
+	dates := make(map[string]time.Time)
+	if a.serviceModel == nil || a.repoModel == nil {
+		return dates
+	}
+	service, err := a.serviceModel.GetByID(serviceID)
+	if err != nil {
+		return dates
+	}
+	repo, err := a.repoModel.GetByID(service.RepositoryID)
+	if err != nil {
+		return dates
+	}
+	owner, repoName, err := vcs.ParseGitHubURL(repo.URL)
+	if err != nil {
+		return dates
+	}
+
+	var missing []string
+	for _, deployment := range deployments {
+		sha := deployment.CommitSHA
+		if _, seen := dates[sha]; seen || sha == "" {
+			continue
+		}
+		if date, ok := a.commitDates.get(owner + "/" + repoName + "@" + sha); ok {
+			dates[sha] = date
+		} else if !slices.Contains(missing, sha) {
+			missing = append(missing, sha)
+		}
+	}
+	token := a.getGitHubToken()
+	if len(missing) == 0 || token == "" {
+		return dates
+	}
+
+	client := github.NewClientWithBaseURL(token, a.getGitHubEnterpriseURL(), a.githubClientOptions()...).GetGitHubClient()
+	ctx, cancel := context.WithTimeout(context.Background(), commitChecksTimeout)
+	defer cancel()
+
+	var mu sync.Mutex
+	var g errgroup.Group
+	g.SetLimit(commitChecksWorkers)
+	for _, sha := range missing {
+		g.Go(func() error {
+			commit, _, err := client.Repositories.GetCommit(ctx, owner, repoName, sha, nil)
+			if err != nil {
+				log.Printf("Failed to get the date of commit %s: %v", shortSHA(sha), err)
+				return nil
+			}
+			date := commit.GetCommit().GetAuthor().GetDate()
+			if date.IsZero() {
+				return nil
+			}
+			a.commitDates.put(owner+"/"+repoName+"@"+sha, date.Time)
+			mu.Lock()
+			dates[sha] = date.Time
+			mu.Unlock()
+			return nil
+		})
+	}
+	g.Wait()
+	return dates
+}
+
+// annotateCommitDeploymentStaleness sets the staleness of every deployed cell of the commit
+// deployment matrix from the age of its commit
+func (a *App) annotateCommitDeploymentStaleness(statuses []*types.CommitDeploymentStatus) {
+	thresholds := a.getStalenessThresholds()
+	now := time.Now()
+	for _, status := range statuses {
+		date := status.Commit.Date
+		for i := range status.Deployments {
+			if status.Deployments[i].IsDeployed {
+				status.Deployments[i].Staleness = thresholds.classify(&date, now)
+			}
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"dev-dashboard/internal/database"
+	"dev-dashboard/pkg/types"
+)
+
+const slowQueryThresholdKey = "slow_query_threshold_ms"
+
+// GetSlowQueries returns the database statements that exceeded the slow query threshold since
+// startup, newest first
+func (a *App) GetSlowQueries() ([]types.SlowQuery, error) {
+	if a.db == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+
+	queries := []types.SlowQuery{}
+	for _, query := range a.db.RecentSlowQueries() {
+		queries = append(queries, types.SlowQuery{
+			SQL:        query.SQL,
+			DurationMs: float64(query.Duration.Microseconds()) / 1000,
+			ExecutedAt: query.ExecutedAt,
+		})
+	}
+	return queries, nil
+}
+
+// parseSlowQueryThreshold parses a slow_query_threshold_ms value in milliseconds
+func parseSlowQueryThreshold(value string) (time.Duration, error) {
+	ms, err := strconv.Atoi(value)
+	if err != nil || ms < 0 {
+		return 0, fmt.Errorf("%s must be a whole number of milliseconds, got %q", slowQueryThresholdKey, value)
+	}
+	return time.Duration(ms) * time.Millisecond, nil
+}
+
+// applySlowQueryThreshold sets the slow query threshold from the slow_query_threshold_ms config
+// key. An unset key uses the default; 0 turns slow query logging off.
+func (a *App) applySlowQueryThreshold() {
+	if a.db == nil || a.configModel == nil {
+		return
+	}
+
+	threshold := database.DefaultSlowQueryThreshold
+	if config, err := a.configModel.Get(slowQueryThresholdKey); err == nil && config != nil && config.Value != "" {
+		parsed, err := parseSlowQueryThreshold(config.Value)
+		if err != nil {
+			log.Printf("Ignoring invalid slow query threshold: %v", err)
+		} else {
+			threshold = parsed
+		}
+	}
+	a.db.SetSlowQueryThreshold(threshold)
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"dev-dashboard/internal/sync"
+	"dev-dashboard/pkg/types"
+)
+
+// discoveryReviewWindowKey is how many hours discovery changes of repositories in review mode
+// wait for review before discoveryReviewExpiredActionKey applies; 0 keeps them waiting
+const discoveryReviewWindowKey = "discovery_review_window_hours"
+
+// discoveryReviewExpiredActionKey is what happens to changes left unreviewed past the window:
+// "expire" (the default) drops them, "apply" applies them
+const discoveryReviewExpiredActionKey = "discovery_review_expired_action"
+
+// getDiscoveryReviewWindow returns the discovery_review_window_hours config key as a duration
+func (a *App) getDiscoveryReviewWindow() time.Duration {
+	if a.configModel != nil {
+		if config, err := a.configModel.Get(discoveryReviewWindowKey); err == nil && config != nil && config.Value != "" {
+			if hours, err := strconv.Atoi(config.Value); err == nil && hours >= 0 {
+				return time.Duration(hours) * time.Hour
+			}
+		}
+	}
+	return sync.DefaultDiscoveryReviewWindow
+}
+
+// discoveryReviewAutoApply reports whether discovery changes left unreviewed are applied
+func (a *App) discoveryReviewAutoApply() bool {
+	if a.configModel != nil {
+		if config, err := a.configModel.Get(discoveryReviewExpiredActionKey); err == nil && config != nil {
+			return config.Value == "apply"
+		}
+	}
+	return false
+}
+
+// SetRepositoryDiscoveryReview sets whether services a sync discovers, removes or renames in a
+// repository wait for review through GetPendingDiscoveryChanges instead of being applied
+func (a *App) SetRepositoryDiscoveryReview(id int64, review bool) error {
+	if a.repoModel == nil {
+		return fmt.Errorf("repository model not initialized")
+	}
+	return a.repoModel.SetDiscoveryReview(id, review)
+}
+
+// GetPendingDiscoveryChanges returns the service changes syncs of a repository in review mode
+// found, including the rejected and expired ones discovery still reports
+func (a *App) GetPendingDiscoveryChanges(repoID int64) ([]*types.DiscoveryChange, error) {
+	if a.discoveryChangeModel == nil {
+		return nil, fmt.Errorf("discovery change model not initialized")
+	}
+	changes, err := a.discoveryChangeModel.GetByRepository(repoID)
+	if err != nil {
+		return nil, err
+	}
+
+	if window := a.getDiscoveryReviewWindow(); window > 0 {
+		for _, change := range changes {
+			if change.Status == types.DiscoveryChangePending {
+				expiresAt := change.DetectedAt.Add(window)
+				change.ExpiresAt = &expiresAt
+			}
+		}
+	}
+	return changes, nil
+}
+
+// GetPendingDiscoveryChangeCounts returns the number of changes waiting for review per repository
+func (a *App) GetPendingDiscoveryChangeCounts() (map[int64]int, error) {
+	if a.discoveryChangeModel == nil {
+		return nil, fmt.Errorf("discovery change model not initialized")
+	}
+	return a.discoveryChangeModel.CountPending()
+}
+
+// ApplyDiscoveryChanges applies the accepted changes of a repository and rejects the others. Either
+// all decisions take effect or none do.
+func (a *App) ApplyDiscoveryChanges(repoID int64, decisions []types.DiscoveryChangeDecision) error {
+	if a.discoveryChangeModel == nil {
+		return fmt.Errorf("discovery change model not initialized")
+	}
+	_, err := a.discoveryChangeModel.Apply(repoID, decisions)
+	return err
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"dev-dashboard/internal/telemetry"
+	"dev-dashboard/pkg/types"
+)
+
+// envVarSnapshotsKey makes the deployment scan record the environment variables the Deployment of
+// each service gets in each overlay, for GetEnvVarDiff
+const envVarSnapshotsKey = "env_var_snapshots"
+
+// applyEnvVarSnapshots turns env var snapshots on or off in the sync service and rescans the
+// kubernetes repositories, so snapshots are taken without waiting for a manifest to change
+func (a *App) applyEnvVarSnapshots() {
+	if a.syncService != nil {
+		a.syncService.SetEnvVarSnapshots(a.getConfigFlag(envVarSnapshotsKey))
+	}
+	a.resetKubernetesScanTrees()
+}
+
+// GetEnvVarDiff compares the environment variables of a service's Deployment in two environments:
+// variables added in envB, removed from it and changed between them. Secret variables are listed by
+// name only. When the environments share a region, that region is compared; otherwise each
+// environment's most recently updated snapshot is.
+func (a *App) GetEnvVarDiff(serviceID int64, envA, envB string) (*types.EnvVarDiff, error) {
+	if a.envVarSnapshotModel == nil {
+		return nil, fmt.Errorf("env var snapshot model not initialized")
+	}
+	a.recordFeature(telemetry.EnvVarDiff)
+
+	snapshots, err := a.envVarSnapshotModel.GetByServiceID(serviceID)
+	if err != nil {
+		return nil, err
+	}
+	snapshotA, snapshotB := pickEnvVarSnapshots(snapshots, envA, envB)
+	for _, missing := range []struct {
+		snapshot    *types.EnvVarSnapshot
+		environment string
+	}{{snapshotA, envA}, {snapshotB, envB}} {
+		if missing.snapshot == nil {
+			return nil, fmt.Errorf("no environment variables recorded in %s; turn on %s and sync", missing.environment, envVarSnapshotsKey)
+		}
+	}
+
+	diff := &types.EnvVarDiff{
+		ServiceID:    serviceID,
+		EnvironmentA: snapshotA.Environment,
+		RegionA:      snapshotA.Region,
+		EnvironmentB: snapshotB.Environment,
+		RegionB:      snapshotB.Region,
+		Changes:      []types.EnvVarChange{},
+	}
+
+	varsA, varsB := envVarsByKey(snapshotA.Variables), envVarsByKey(snapshotB.Variables)
+	keys := make(map[string]bool)
+	for key := range varsA {
+		keys[key] = true
+	}
+	for key := range varsB {
+		keys[key] = true
+	}
+	sortedKeys := make([]string, 0, len(keys))
+	for key := range keys {
+		sortedKeys = append(sortedKeys, key)
+	}
+	sort.Strings(sortedKeys)
+
+	for _, key := range sortedKeys {
+		varA, inA := varsA[key]
+		varB, inB := varsB[key]
+		v := varB
+		if !inB {
+			v = varA
+		}
+		change := types.EnvVarChange{Container: v.Container, Name: v.Name, Secret: varA.Secret || varB.Secret}
+		switch {
+		case !inA:
+			change.Change = "added"
+		case !inB:
+			change.Change = "removed"
+		case varA != varB:
+			change.Change = "changed"
+		default:
+			diff.Unchanged++
+			continue
+		}
+		if !change.Secret {
+			change.ValueA, change.ValueB = envVarDisplayValue(varA), envVarDisplayValue(varB)
+		}
+		diff.Changes = append(diff.Changes, change)
+	}
+	return diff, nil
+}
+
+// pickEnvVarSnapshots picks the snapshots to compare: those of the first region both environments
+// have, else the most recently updated of each
+func pickEnvVarSnapshots(snapshots []*types.EnvVarSnapshot, envA, envB string) (*types.EnvVarSnapshot, *types.EnvVarSnapshot) {
+	var inA, inB []*types.EnvVarSnapshot
+	for _, snapshot := range snapshots {
+		if strings.EqualFold(snapshot.Environment, envA) {
+			inA = append(inA, snapshot)
+		}
+		if strings.EqualFold(snapshot.Environment, envB) {
+			inB = append(inB, snapshot)
+		}
+	}
+	for _, a := range inA {
+		for _, b := range inB {
+			if strings.EqualFold(a.Region, b.Region) {
+				return a, b
+			}
+		}
+	}
+	return newestEnvVarSnapshot(inA), newestEnvVarSnapshot(inB)
+}
+
+func newestEnvVarSnapshot(snapshots []*types.EnvVarSnapshot) *types.EnvVarSnapshot {
+	var newest *types.EnvVarSnapshot
+	for _, snapshot := range snapshots {
+		if newest == nil || snapshot.UpdatedAt.After(newest.UpdatedAt) {
+			newest = snapshot
+		}
+	}
+	return newest
+}
+
+// envVarsByKey indexes variables by container and name
+func envVarsByKey(vars []types.EnvVar) map[string]types.EnvVar {
+	byKey := make(map[string]types.EnvVar, len(vars))
+	for _, v := range vars {
+		byKey[v.Container+"\x00"+v.Name] = v
+	}
+	return byKey
+}
+
+// envVarDisplayValue is a variable's literal value, or where it comes from
+func envVarDisplayValue(v types.EnvVar) string {
+	if v.ValueFrom != "" {
+		return "from " + v.ValueFrom
+	}
+	return v.Value
+}
@@ -0,0 +1,398 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"dev-dashboard/internal/telemetry"
+	"dev-dashboard/internal/vcs"
+	"dev-dashboard/pkg/types"
+
+	goGithub "github.com/google/go-github/v57/github"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+	"golang.org/x/sync/errgroup"
+)
+
+// environmentComparisonConcurrency bounds the services compared on GitHub at once
+const environmentComparisonConcurrency = 4
+
+// GenerateEnvironmentComparisonReport lists every service whose deployments differ between
+// source and target (e.g. stg and prd) as a Markdown or CSV document: the tag in each environment,
+// how far the target is behind, when each was last deployed, the owner and open promotion pull
+// requests. Services with the largest drift come first.
+func (a *App) GenerateEnvironmentComparisonReport(source, target, format string) (string, error) {
+	a.recordFeature(telemetry.EnvironmentReport)
+	comparison, err := a.compareEnvironments(source, target, format)
+	if err != nil {
+		return "", err
+	}
+	return renderEnvironmentComparison(comparison, format, a.displayClock().loc)
+}
+
+// SaveEnvironmentComparisonReport generates the report and writes it to a file picked in a save
+// dialog. It returns the file's path, or an empty string when the dialog was cancelled.
+func (a *App) SaveEnvironmentComparisonReport(source, target, format string) (string, error) {
+	if format == "" {
+		format = types.ReportMarkdown
+	}
+	report, err := a.GenerateEnvironmentComparisonReport(source, target, format)
+	if err != nil {
+		return "", err
+	}
+
+	extension, filter := "md", runtime.FileFilter{DisplayName: "Markdown (*.md)", Pattern: "*.md"}
+	if format == types.ReportCSV {
+		extension, filter = "csv", runtime.FileFilter{DisplayName: "CSV (*.csv)", Pattern: "*.csv"}
+	}
+	path, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:           "Save environment comparison",
+		DefaultFilename: fmt.Sprintf("%s-vs-%s-%s.%s", source, target, time.Now().Format("2006-01-02"), extension),
+		Filters:         []runtime.FileFilter{filter},
+	})
+	if err != nil || path == "" {
+		return "", err
+	}
+
+	if err := os.WriteFile(path, []byte(report), 0644); err != nil {
+		return "", fmt.Errorf("failed to save report: %w", err)
+	}
+	return path, nil
+}
+
+// compareEnvironments builds the comparison of every visible service deployed to source or target
+func (a *App) compareEnvironments(source, target, format string) (*types.EnvironmentComparison, error) {
+	if a.deploymentModel == nil || a.serviceModel == nil || a.repoModel == nil {
+		return nil, fmt.Errorf("deployment model not initialized")
+	}
+	source, target = strings.TrimSpace(source), strings.TrimSpace(target)
+	if source == "" || target == "" || source == target {
+		return nil, fmt.Errorf("two different environments are required")
+	}
+	if format == "" {
+		format = types.ReportMarkdown
+	}
+	if format != types.ReportMarkdown && format != types.ReportCSV {
+		return nil, fmt.Errorf("unsupported report format %q, expected %s or %s", format, types.ReportMarkdown, types.ReportCSV)
+	}
+
+	services, err := a.GetMicroservices(0, false)
+	if err != nil {
+		return nil, err
+	}
+	repoNames := make(map[int64]string)
+	if repos, err := a.repoModel.GetAll(); err == nil {
+		for _, repo := range repos {
+			repoNames[repo.ID] = repo.Name
+		}
+	}
+
+	comparison := &types.EnvironmentComparison{
+		GeneratedAt: time.Now(),
+		GeneratedBy: reportGenerator(),
+		Source:      source,
+		Target:      target,
+		Services:    []*types.EnvironmentComparisonRow{},
+	}
+	promotions := newPromotionPullRequests(a)
+
+	var mu sync.Mutex
+	g := new(errgroup.Group)
+	g.SetLimit(environmentComparisonConcurrency)
+	for _, service := range services {
+		service := service
+		g.Go(func() error {
+			row, err := a.compareServiceEnvironments(service, source, target, promotions)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				comparison.Warnings = append(comparison.Warnings, fmt.Sprintf("%s: %v", service.Name, err))
+				return nil
+			}
+			if row != nil {
+				row.Repository = repoNames[service.RepositoryID]
+				comparison.Services = append(comparison.Services, row)
+			}
+			return nil
+		})
+	}
+	g.Wait()
+
+	sortEnvironmentComparison(comparison.Services)
+	sort.Strings(comparison.Warnings)
+	return comparison, nil
+}
+
+// compareServiceEnvironments returns the comparison row of a service, or nil when it runs the same
+// tag and commit in both environments or is deployed to neither
+func (a *App) compareServiceEnvironments(service *types.Microservice, source, target string, promotions *promotionPullRequests) (*types.EnvironmentComparisonRow, error) {
+	deployments, err := a.deploymentModel.GetByServiceID(service.ID)
+	if err != nil {
+		return nil, err
+	}
+	current := representativeDeployments(deployments)
+	from, to := current[source], current[target]
+	if from == nil && to == nil {
+		return nil, nil
+	}
+	if from != nil && to != nil && from.Tag == to.Tag && from.CommitSHA == to.CommitSHA {
+		return nil, nil
+	}
+
+	row := &types.EnvironmentComparisonRow{
+		ServiceID:             service.ID,
+		Service:               service.Name,
+		Owner:                 service.Owner,
+		PromotionPullRequests: []*types.PullRequest{},
+	}
+	deployedAt := a.lastDeployedAt(service.ID)
+	if from != nil {
+		row.SourceTag = from.Tag
+		row.SourceDeployedAt = deployedAt(from)
+	}
+	if to == nil {
+		return row, nil
+	}
+	row.TargetTag = to.Tag
+	row.TargetDeployedAt = deployedAt(to)
+	row.PromotionPullRequests = promotions.touching(to)
+	if from == nil {
+		return row, nil
+	}
+
+	compare := a.deployedCommitComparer(service.ID)
+	row.Drift = environmentDrift(to, from, compare)
+	// Commits behind are reported for versioned tags too, when the deployed commits are known
+	if row.Drift.Method == types.DriftByVersion && compare != nil && to.CommitSHA != "" && from.CommitSHA != "" && to.CommitSHA != from.CommitSHA {
+		if behind, ahead, err := compare(to.CommitSHA, from.CommitSHA); err == nil {
+			row.Drift.CommitsBehind, row.Drift.CommitsAhead = behind, ahead
+		}
+	}
+	return row, nil
+}
+
+// lastDeployedAt returns when a deployment target of the service last changed tag, from the
+// deployment history, falling back to when the deployment was discovered
+func (a *App) lastDeployedAt(serviceID int64) func(*types.Deployment) *time.Time {
+	history, err := a.deploymentModel.GetHistoryByServiceID(serviceID, time.Time{})
+	if err != nil {
+		log.Printf("Failed to get deployment history of service %d: %v", serviceID, err)
+	}
+	return func(deployment *types.Deployment) *time.Time {
+		latest := deployment.DiscoveredAt
+		for _, entry := range history {
+			if entry.Environment == deployment.Environment && entry.Region == deployment.Region &&
+				entry.Namespace == deployment.Namespace && entry.ObservedAt.After(latest) {
+				latest = entry.ObservedAt
+			}
+		}
+		if latest.IsZero() {
+			return nil
+		}
+		return &latest
+	}
+}
+
+// driftRank orders comparison rows: measured version drift, then measured commit drift, then drift
+// that couldn't be measured, then services deployed to only one of the environments
+func driftRank(row *types.EnvironmentComparisonRow) (int, int) {
+	drift := row.Drift
+	switch {
+	case drift == nil:
+		return 3, 0
+	case drift.Method == types.DriftByVersion:
+		return 0, abs(drift.MajorDelta)*1_000_000 + abs(drift.MinorDelta)*1_000 + abs(drift.PatchDelta)
+	case drift.Method == types.DriftByCommits:
+		return 1, drift.CommitsBehind + drift.CommitsAhead
+	}
+	return 2, 0
+}
+
+// sortEnvironmentComparison sorts rows by drift rank, largest drift first, then by service name
+func sortEnvironmentComparison(rows []*types.EnvironmentComparisonRow) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		rankI, sizeI := driftRank(rows[i])
+		rankJ, sizeJ := driftRank(rows[j])
+		if rankI != rankJ {
+			return rankI < rankJ
+		}
+		if sizeI != sizeJ {
+			return sizeI > sizeJ
+		}
+		return rows[i].Service < rows[j].Service
+	})
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// promotionPullRequests finds the open pull requests of kubernetes repositories that change a
+// deployment's kustomization file, listing each repository's pull requests once per report
+type promotionPullRequests struct {
+	app   *App
+	mu    sync.Mutex
+	files map[int64]map[string][]*types.PullRequest // by repository ID and changed file
+}
+
+func newPromotionPullRequests(app *App) *promotionPullRequests {
+	return &promotionPullRequests{app: app, files: make(map[int64]map[string][]*types.PullRequest)}
+}
+
+// touching returns the open pull requests that change the deployment's kustomization file. Pull
+// requests that can't be listed are logged and left out.
+func (p *promotionPullRequests) touching(deployment *types.Deployment) []*types.PullRequest {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	files, ok := p.files[deployment.KubernetesRepoID]
+	if !ok {
+		var err error
+		if files, err = p.list(deployment.KubernetesRepoID); err != nil {
+			log.Printf("Failed to list promotion pull requests of repository %d: %v", deployment.KubernetesRepoID, err)
+		}
+		p.files[deployment.KubernetesRepoID] = files
+	}
+	if prs := files[strings.TrimPrefix(deployment.Path, "/")]; prs != nil {
+		return prs
+	}
+	return []*types.PullRequest{}
+}
+
+// list maps the files changed by the open pull requests of a kubernetes repository to the pull
+// requests changing them
+func (p *promotionPullRequests) list(repositoryID int64) (map[string][]*types.PullRequest, error) {
+	githubToken := p.app.getGitHubToken()
+	if githubToken == "" {
+		return nil, nil
+	}
+	repo, err := p.app.repoModel.GetByID(repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	owner, repoName, err := vcs.ParseGitHubURL(repo.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	client := p.app.createGitHubClient(githubToken)
+	prs, _, err := client.PullRequests.List(ctx, owner, repoName, &goGithub.PullRequestListOptions{
+		State:       "open",
+		ListOptions: goGithub.ListOptions{PerPage: 50},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string][]*types.PullRequest)
+	for _, pr := range prs {
+		changed, _, err := client.PullRequests.ListFiles(ctx, owner, repoName, pr.GetNumber(), &goGithub.ListOptions{PerPage: 100})
+		if err != nil {
+			log.Printf("Failed to list files of %s/%s#%d: %v", owner, repoName, pr.GetNumber(), err)
+			continue
+		}
+		promotion := &types.PullRequest{
+			ID:        pr.GetID(),
+			Number:    pr.GetNumber(),
+			Title:     pr.GetTitle(),
+			Status:    "open",
+			Author:    pr.GetUser().GetLogin(),
+			Branch:    pr.GetHead().GetRef(),
+			CreatedAt: pr.GetCreatedAt().Time,
+		}
+		for _, file := range changed {
+			files[file.GetFilename()] = append(files[file.GetFilename()], promotion)
+		}
+	}
+	return files, nil
+}
+
+// renderEnvironmentComparison writes the comparison as Markdown or CSV with timestamps in loc
+func renderEnvironmentComparison(comparison *types.EnvironmentComparison, format string, loc *time.Location) (string, error) {
+	stamp := func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return t.In(loc).Format("2006-01-02 15:04 MST")
+	}
+	driftSummary := func(row *types.EnvironmentComparisonRow) string {
+		switch {
+		case row.Drift != nil:
+			return row.Drift.Summary
+		case row.TargetTag == "":
+			return fmt.Sprintf("not deployed to %s", comparison.Target)
+		default:
+			return fmt.Sprintf("not deployed to %s", comparison.Source)
+		}
+	}
+	commitsBehind := func(row *types.EnvironmentComparisonRow) string {
+		if row.Drift == nil || (row.Drift.CommitsBehind == 0 && row.Drift.CommitsAhead == 0) {
+			return ""
+		}
+		return strconv.Itoa(row.Drift.CommitsBehind)
+	}
+	promotionNumbers := func(row *types.EnvironmentComparisonRow) []string {
+		numbers := make([]string, 0, len(row.PromotionPullRequests))
+		for _, pr := range row.PromotionPullRequests {
+			numbers = append(numbers, fmt.Sprintf("#%d", pr.Number))
+		}
+		return numbers
+	}
+
+	if format == types.ReportCSV {
+		var b strings.Builder
+		w := csv.NewWriter(&b)
+		w.Write([]string{"service", "repository", "owner",
+			comparison.Source + "_tag", comparison.Target + "_tag", "drift", "commits_behind",
+			comparison.Source + "_deployed_at", comparison.Target + "_deployed_at", "promotion_pull_requests"})
+		for _, row := range comparison.Services {
+			w.Write([]string{row.Service, row.Repository, row.Owner, row.SourceTag, row.TargetTag,
+				driftSummary(row), commitsBehind(row), stamp(row.SourceDeployedAt), stamp(row.TargetDeployedAt),
+				strings.Join(promotionNumbers(row), " ")})
+		}
+		w.Flush()
+		return b.String(), w.Error()
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s vs %s\n\n", comparison.Source, comparison.Target)
+	fmt.Fprintf(&b, "_Generated %s by %s_\n\n", stamp(&comparison.GeneratedAt), comparison.GeneratedBy)
+	if len(comparison.Services) == 0 {
+		fmt.Fprintf(&b, "Every service runs the same tag in %s and %s.\n", comparison.Source, comparison.Target)
+	} else {
+		fmt.Fprintf(&b, "%d services differ.\n\n", len(comparison.Services))
+		fmt.Fprintf(&b, "| Service | Owner | %s | %s | Drift | Commits behind | %s deployed | %s deployed | Promotion PRs |\n",
+			comparison.Source, comparison.Target, comparison.Source, comparison.Target)
+		b.WriteString("|---|---|---|---|---|---|---|---|---|\n")
+		for _, row := range comparison.Services {
+			owner := row.Owner
+			if owner == "" {
+				owner = "unassigned"
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s | %s | %s | %s |\n",
+				markdownCell(row.Service), markdownCell(owner), markdownCell(row.SourceTag), markdownCell(row.TargetTag),
+				markdownCell(driftSummary(row)), commitsBehind(row), stamp(row.SourceDeployedAt), stamp(row.TargetDeployedAt),
+				strings.Join(promotionNumbers(row), ", "))
+		}
+	}
+
+	if len(comparison.Warnings) > 0 {
+		b.WriteString("\n## Warnings\n\n")
+		for _, warning := range comparison.Warnings {
+			fmt.Fprintf(&b, "- %s\n", warning)
+		}
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"encoding/csv"
+	"slices"
+	"strings"
+	"testing"
+
+	"dev-dashboard/internal/testsupport"
+	"dev-dashboard/internal/vcs"
+	"dev-dashboard/pkg/types"
+)
+
+// comparisonFixtures stores services of one monorepo deployed to dev and prd through one
+// kubernetes repository:
+//   - web is a major version behind in prd and api two minor versions
+//   - cron runs untagged builds that can't be compared without GitHub
+//   - batch is only deployed to dev
+//   - worker runs the same tag and commit in both, and legacy is hidden
+func comparisonFixtures(t *testing.T) *App {
+	t.Helper()
+	// Without a token nothing is asked of GitHub
+	t.Setenv("GITHUB_TOKEN", "")
+	app, db := newTestApp(t)
+	conn := db.GetConn()
+	if err := app.configModel.Set(timezoneKey, "UTC"); err != nil {
+		t.Fatalf("failed to set the time zone: %v", err)
+	}
+	mono := testsupport.Repository(t, conn, func(repo *types.Repository) { repo.Name = "platform" })
+	k8s := testsupport.KubernetesRepository(t, conn)
+
+	service := func(name string) *types.Microservice {
+		return testsupport.Service(t, conn, mono.ID, func(service *types.Microservice) { service.Name = name })
+	}
+	deploy := func(service *types.Microservice, environment, tag, commit string) {
+		testsupport.Deployment(t, conn, service.ID, k8s.ID, func(deployment *types.Deployment) {
+			deployment.Environment = environment
+			deployment.Tag = tag
+			deployment.Version = vcs.ParseTagVersion(tag, vcs.DefaultTagPrefixes)
+			if commit != "" {
+				deployment.CommitSHA = commit
+			}
+		})
+	}
+
+	web := service("web")
+	deploy(web, "dev", "v2.0.0", "")
+	deploy(web, "prd", "v1.0.0", "")
+	api := service("api")
+	if err := app.serviceModel.SetOwner(api.ID, "team-payments"); err != nil {
+		t.Fatalf("SetOwner: %v", err)
+	}
+	deploy(api, "dev", "v1.4.0", "")
+	deploy(api, "prd", "v1.2.0", "")
+	cron := service("cron")
+	deploy(cron, "dev", "main-abc123", "")
+	deploy(cron, "prd", "main-def456", "")
+	batch := service("batch")
+	deploy(batch, "dev", "v0.3.0", "")
+	worker := service("worker")
+	deploy(worker, "dev", "v3.1.0", strings.Repeat("a", 40))
+	deploy(worker, "prd", "v3.1.0", strings.Repeat("a", 40))
+	legacy := service("legacy")
+	deploy(legacy, "dev", "v9.0.0", "")
+	deploy(legacy, "prd", "v1.0.0", "")
+	if err := app.serviceModel.SetHidden(legacy.ID, true); err != nil {
+		t.Fatalf("SetHidden: %v", err)
+	}
+	return app
+}
+
+func TestEnvironmentComparisonReportCSV(t *testing.T) {
+	app := comparisonFixtures(t)
+
+	report, err := app.GenerateEnvironmentComparisonReport("dev", "prd", types.ReportCSV)
+	if err != nil {
+		t.Fatalf("GenerateEnvironmentComparisonReport: %v", err)
+	}
+	records, err := csv.NewReader(strings.NewReader(report)).ReadAll()
+	if err != nil {
+		t.Fatalf("the report isn't valid CSV: %v\n%s", err, report)
+	}
+
+	wantHeader := []string{"service", "repository", "owner", "dev_tag", "prd_tag", "drift", "commits_behind", "dev_deployed_at", "prd_deployed_at", "promotion_pull_requests"}
+	if !slices.Equal(records[0], wantHeader) {
+		t.Errorf("got header %v, want %v", records[0], wantHeader)
+	}
+	// Largest version drift first, then drift that couldn't be measured, then services deployed
+	// to one environment only
+	want := [][]string{
+		{"web", "platform", "", "v2.0.0", "v1.0.0", "prd is 1 major version behind dev"},
+		{"api", "platform", "team-payments", "v1.4.0", "v1.2.0", "prd is 2 minor versions behind dev"},
+		{"cron", "platform", "", "main-abc123", "main-def456", "prd (main-def456) can't be compared with dev (main-abc123)"},
+		{"batch", "platform", "", "v0.3.0", "", "not deployed to prd"},
+	}
+	rows := records[1:]
+	if len(rows) != len(want) {
+		t.Fatalf("got %d rows, want %d:\n%s", len(rows), len(want), report)
+	}
+	for i, row := range rows {
+		if !slices.Equal(row[:6], want[i]) {
+			t.Errorf("row %d is %v, want %v", i+1, row[:6], want[i])
+		}
+		if row[6] != "" || row[9] != "" {
+			t.Errorf("row %d has commits behind %q and promotions %q, want neither without GitHub", i+1, row[6], row[9])
+		}
+		if !strings.HasSuffix(row[7], " UTC") {
+			t.Errorf("row %d was deployed to dev at %q, want a time in the configured time zone", i+1, row[7])
+		}
+	}
+	if rows[3][8] != "" {
+		t.Errorf("batch was deployed to prd at %q, want nothing", rows[3][8])
+	}
+}
+
+func TestEnvironmentComparisonReportMarkdown(t *testing.T) {
+	app := comparisonFixtures(t)
+
+	report, err := app.GenerateEnvironmentComparisonReport("dev", "prd", types.ReportMarkdown)
+	if err != nil {
+		t.Fatalf("GenerateEnvironmentComparisonReport: %v", err)
+	}
+	for _, want := range []string{
+		"# dev vs prd\n",
+		"4 services differ.",
+		"| Service | Owner | dev | prd | Drift | Commits behind | dev deployed | prd deployed | Promotion PRs |",
+		"| web | unassigned | v2.0.0 | v1.0.0 | prd is 1 major version behind dev |",
+		"| api | team-payments | v1.4.0 | v1.2.0 | prd is 2 minor versions behind dev |",
+		"| batch | unassigned | v0.3.0 |  | not deployed to prd |",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("the report doesn't contain %q:\n%s", want, report)
+		}
+	}
+	for _, left := range []string{"worker", "legacy"} {
+		if strings.Contains(report, left) {
+			t.Errorf("the report lists %s, which shouldn't be in it:\n%s", left, report)
+		}
+	}
+	if strings.Index(report, "| web |") > strings.Index(report, "| api |") || strings.Index(report, "| cron |") > strings.Index(report, "| batch |") {
+		t.Errorf("the rows aren't ordered by drift:\n%s", report)
+	}
+}
+
+func TestEnvironmentComparisonReportRejectsInvalidRequests(t *testing.T) {
+	app := comparisonFixtures(t)
+
+	for _, request := range [][3]string{{"dev", "dev", "csv"}, {"", "prd", "csv"}, {"dev", "prd", "pdf"}} {
+		if _, err := app.GenerateEnvironmentComparisonReport(request[0], request[1], request[2]); err == nil {
+			t.Errorf("GenerateEnvironmentComparisonReport(%q, %q, %q) succeeded, want an error", request[0], request[1], request[2])
+		}
+	}
+}
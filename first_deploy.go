@@ -0,0 +1,16 @@
+package main
+
+// firstDeployNotificationsKey turns off the first_deploy notification raised when a service is first
+// deployed to an environment and region, when set to "false"
+const firstDeployNotificationsKey = "first_deploy_notifications"
+
+// firstDeployNotificationsEnabled reports whether first_deploy notifications are raised; they are
+// unless first_deploy_notifications is "false"
+func (a *App) firstDeployNotificationsEnabled() bool {
+	if a.configModel != nil {
+		if config, err := a.configModel.Get(firstDeployNotificationsKey); err == nil && config != nil {
+			return config.Value != "false"
+		}
+	}
+	return true
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"log"
+	"strings"
+
+	"dev-dashboard/internal/github"
+	"dev-dashboard/pkg/types"
+)
+
+const (
+	// fluxHelmReleaseFieldsKey lists the field paths a deployment version is read from in Flux
+	// HelmReleases, comma separated and tried in order; empty uses
+	// github.DefaultFluxVersionFields
+	fluxHelmReleaseFieldsKey = "flux_helmrelease_version_fields"
+	// fluxKustomizationFieldsKey is the same for Flux Kustomizations
+	fluxKustomizationFieldsKey = "flux_kustomization_version_fields"
+	// commitAnnotationsKey lists the kustomization commonAnnotations, commonLabels and labels keys a
+	// deployment's source commit is read from, comma separated and tried in order; empty uses
+	// github.DefaultCommitAnnotations
+	commitAnnotationsKey = "deployment_commit_annotations"
+)
+
+// getFluxVersionFields returns the configured Flux version fields
+func (a *App) getFluxVersionFields() github.FluxVersionFields {
+	fields := github.DefaultFluxVersionFields
+	if value, err := a.GetConfig(fluxHelmReleaseFieldsKey); err == nil {
+		if paths, err := github.ParseFluxFieldPaths(value); err == nil && len(paths) > 0 {
+			fields.HelmRelease = paths
+		}
+	}
+	if value, err := a.GetConfig(fluxKustomizationFieldsKey); err == nil {
+		if paths, err := github.ParseFluxFieldPaths(value); err == nil && len(paths) > 0 {
+			fields.Kustomization = paths
+		}
+	}
+	return fields
+}
+
+// getCommitAnnotations returns the configured commit annotation keys
+func (a *App) getCommitAnnotations() []string {
+	value, err := a.GetConfig(commitAnnotationsKey)
+	if err != nil {
+		return github.DefaultCommitAnnotations
+	}
+	var keys []string
+	for _, key := range strings.Split(value, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return github.DefaultCommitAnnotations
+	}
+	return keys
+}
+
+// applyCommitAnnotations hands the configured commit annotation keys to the sync service and
+// forgets the scan trees, so the next sync reads the commits of unchanged overlays too
+func (a *App) applyCommitAnnotations() {
+	if a.syncService != nil {
+		a.syncService.SetCommitAnnotations(a.getCommitAnnotations())
+	}
+	a.resetKubernetesScanTrees()
+}
+
+// applyFluxVersionFields hands the configured Flux version fields to the sync service and forgets
+// the scan tree of every kubernetes repository, so the next sync rescans them with the new fields
+func (a *App) applyFluxVersionFields() {
+	if a.syncService != nil {
+		a.syncService.SetFluxVersionFields(a.getFluxVersionFields())
+	}
+	a.resetKubernetesScanTrees()
+}
+
+// resetKubernetesScanTrees forgets the scan tree of every kubernetes repository, so the next sync
+// rescans them even when nothing changed
+func (a *App) resetKubernetesScanTrees() {
+	if a.repoModel == nil {
+		return
+	}
+
+	repos, err := a.repoModel.GetAll()
+	if err != nil {
+		log.Printf("Failed to get repositories to rescan: %v", err)
+		return
+	}
+	for _, repo := range repos {
+		if repo.Type != types.KubernetesType {
+			continue
+		}
+		if err := a.repoModel.UpdateScanTreeSHA(repo.ID, ""); err != nil {
+			log.Printf("Failed to reset the scan tree of %s: %v", repo.Name, err)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"dev-dashboard/internal/freeze"
+	"dev-dashboard/pkg/types"
+)
+
+// GetFreezeWindows returns the change freeze windows
+func (a *App) GetFreezeWindows() ([]*types.FreezeWindow, error) {
+	if a.freezeWindowModel == nil {
+		return nil, fmt.Errorf("freeze window model not initialized")
+	}
+	return a.freezeWindowModel.GetAll()
+}
+
+// CreateFreezeWindow adds a change freeze. Deployment history entries recorded inside it from then
+// on are flagged and raise a freeze_violation notification.
+func (a *App) CreateFreezeWindow(window types.FreezeWindow) (*types.FreezeWindow, error) {
+	if a.freezeWindowModel == nil {
+		return nil, fmt.Errorf("freeze window model not initialized")
+	}
+	if err := freeze.Validate(&window); err != nil {
+		return nil, err
+	}
+	if err := a.freezeWindowModel.Create(&window); err != nil {
+		return nil, err
+	}
+	return &window, nil
+}
+
+// UpdateFreezeWindow changes a freeze window; entries flagged before keep their flag
+func (a *App) UpdateFreezeWindow(window types.FreezeWindow) error {
+	if a.freezeWindowModel == nil {
+		return fmt.Errorf("freeze window model not initialized")
+	}
+	if err := freeze.Validate(&window); err != nil {
+		return err
+	}
+	return a.freezeWindowModel.Update(&window)
+}
+
+// DeleteFreezeWindow removes a freeze window and unflags the entries that fell into it
+func (a *App) DeleteFreezeWindow(id int64) error {
+	if a.freezeWindowModel == nil {
+		return fmt.Errorf("freeze window model not initialized")
+	}
+	return a.freezeWindowModel.Delete(id)
+}
+
+// GetFreezeViolations returns the deployments recorded inside a freeze window over the last days,
+// newest first
+func (a *App) GetFreezeViolations(days int) ([]*types.FreezeViolation, error) {
+	if a.freezeWindowModel == nil {
+		return nil, fmt.Errorf("freeze window model not initialized")
+	}
+	if days <= 0 {
+		return nil, fmt.Errorf("days must be positive")
+	}
+	return a.freezeWindowModel.GetViolations(time.Now().AddDate(0, 0, -days))
+}
+
+// IsFrozen returns the freeze window covering an environment at a time (now when zero), or nil when
+// the environment isn't frozen then, so promotion tooling can warn before opening a pull request
+func (a *App) IsFrozen(environment string, at time.Time) (*types.FreezeWindow, error) {
+	windows, err := a.GetFreezeWindows()
+	if err != nil {
+		return nil, err
+	}
+	if at.IsZero() {
+		at = time.Now()
+	}
+	return freeze.Active(windows, environment, at), nil
+}
@@ -0,0 +1,89 @@
+import React, { useState } from 'react';
+import { Archive, Download } from 'lucide-react';
+
+const formatSize = (bytes) => {
+  if (bytes < 1024) return `${bytes} B`;
+  if (bytes < 1024 * 1024) return `${(bytes / 1024).toFixed(1)} KB`;
+  if (bytes < 1024 * 1024 * 1024) return `${(bytes / (1024 * 1024)).toFixed(1)} MB`;
+  return `${(bytes / (1024 * 1024 * 1024)).toFixed(1)} GB`;
+};
+
+// ActionArtifacts lists the artifacts a workflow run uploaded and saves them through a save dialog
+const ActionArtifacts = ({ actionId }) => {
+  const [artifacts, setArtifacts] = useState(null);
+  const [loading, setLoading] = useState(false);
+  const [downloading, setDownloading] = useState(null);
+
+  const loadArtifacts = async () => {
+    setLoading(true);
+    try {
+      setArtifacts((await window.go.main.App.GetActionArtifacts(actionId)) || []);
+    } catch (error) {
+      console.error('Failed to load artifacts:', error);
+      alert(`Failed to load artifacts: ${error}`);
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const download = async (artifact) => {
+    setDownloading(artifact.id);
+    try {
+      const path = await window.go.main.App.DownloadActionArtifact(actionId, artifact.id, '');
+      if (path) {
+        alert(`Saved ${artifact.name} to ${path}`);
+      }
+    } catch (error) {
+      alert(`Failed to download ${artifact.name}: ${error}`);
+    } finally {
+      setDownloading(null);
+    }
+  };
+
+  if (artifacts === null) {
+    return (
+      <button onClick={loadArtifacts} disabled={loading} className="text-xs text-blue-600 hover:underline disabled:opacity-50">
+        {loading ? 'Loading artifacts...' : 'Artifacts'}
+      </button>
+    );
+  }
+
+  if (artifacts.length === 0) {
+    return <span className="text-xs text-gray-500">No artifacts</span>;
+  }
+
+  return (
+    <ul className="mt-2 space-y-1 w-full">
+      {artifacts.map(artifact => (
+        <li key={artifact.id} className="flex items-center text-xs text-gray-700">
+          <Archive className="h-3 w-3 mr-1 text-gray-400" />
+          <span className="font-mono">{artifact.name}</span>
+          <span className="ml-2 text-gray-500">{formatSize(artifact.size_bytes)}</span>
+          {artifact.expired ? (
+            <span className="ml-2 text-gray-400">expired</span>
+          ) : (
+            <>
+              {artifact.expires_at && (
+                <span className="ml-2 text-gray-400">expires {new Date(artifact.expires_at).toLocaleDateString()}</span>
+              )}
+              {artifact.too_large ? (
+                <span className="ml-2 text-gray-400" title="Larger than the download limit, fetch it from GitHub">too large</span>
+              ) : (
+                <button
+                  onClick={() => download(artifact)}
+                  disabled={downloading !== null}
+                  className="ml-2 text-blue-600 hover:text-blue-800 disabled:opacity-50"
+                  title="Download"
+                >
+                  <Download className="h-3 w-3" />
+                </button>
+              )}
+            </>
+          )}
+        </li>
+      ))}
+    </ul>
+  );
+};
+
+export default ActionArtifacts;
@@ -0,0 +1,127 @@
+import React, { useState, useEffect } from 'react';
+import { GetConfigSchema, GetAllConfig, SetConfig } from '../../wailsjs/go/main/App';
+import { SlidersHorizontal, Save } from 'lucide-react';
+
+// Advanced settings lists every config key the app knows, from the config schema, so keys
+// without a dedicated form can still be found and set. Values are validated when saved.
+const AdvancedSettings = ({ showMessage }) => {
+  const [keys, setKeys] = useState([]);
+  const [values, setValues] = useState({});
+  const [saved, setSaved] = useState({});
+  const [filter, setFilter] = useState('');
+  const [expanded, setExpanded] = useState(false);
+
+  useEffect(() => {
+    loadSettings();
+  }, []);
+
+  const loadSettings = async () => {
+    try {
+      const [schema, config] = await Promise.all([GetConfigSchema(), GetAllConfig()]);
+      setKeys((schema || []).filter(key => !key.internal && !key.prefix));
+      setValues(config || {});
+      setSaved(config || {});
+    } catch (err) {
+      console.error('Failed to load config schema:', err);
+    }
+  };
+
+  const handleSave = async (key) => {
+    try {
+      await SetConfig(key.name, values[key.name] || '');
+      setSaved({ ...saved, [key.name]: values[key.name] || '' });
+      showMessage(`Saved ${key.name}`, 'success');
+    } catch (err) {
+      console.error(`Failed to save ${key.name}:`, err);
+      showMessage(`Failed to save ${key.name}: ` + err, 'error');
+    }
+  };
+
+  const renderInput = (key) => {
+    const value = values[key.name] || '';
+    const onChange = (e) => setValues({ ...values, [key.name]: e.target.value });
+    const className = 'border border-gray-300 rounded-lg px-3 py-1.5 text-sm w-64';
+
+    if (key.type === 'bool' || key.type === 'enum') {
+      const options = key.type === 'bool' ? ['true', 'false'] : key.values;
+      return (
+        <select value={value} onChange={onChange} className={className}>
+          <option value="">Default{key.default ? ` (${key.default})` : ''}</option>
+          {options.map(option => <option key={option} value={option}>{option}</option>)}
+        </select>
+      );
+    }
+    return (
+      <input
+        type={key.secret ? 'password' : 'text'}
+        value={value}
+        onChange={onChange}
+        placeholder={key.default || ''}
+        className={className}
+      />
+    );
+  };
+
+  const visible = keys.filter(key =>
+    !filter || key.name.includes(filter.toLowerCase()) || key.description.toLowerCase().includes(filter.toLowerCase())
+  );
+
+  return (
+    <div className="bg-white rounded-lg shadow-sm border border-gray-200">
+      <div className="px-6 py-4 border-b border-gray-200">
+        <div className="flex items-center justify-between">
+          <div className="flex items-center gap-3">
+            <SlidersHorizontal className="w-6 h-6 text-gray-700" />
+            <div>
+              <h2 className="text-lg font-semibold text-gray-900">Advanced Settings</h2>
+              <p className="text-sm text-gray-600 mt-1">
+                Every setting the app knows. An empty value falls back to the default.
+              </p>
+            </div>
+          </div>
+          <button onClick={() => setExpanded(!expanded)} className="text-sm text-blue-600 hover:underline">
+            {expanded ? 'Hide' : `Show ${keys.length} settings`}
+          </button>
+        </div>
+      </div>
+
+      {expanded && (
+        <div className="p-6 space-y-4">
+          <input
+            type="text"
+            value={filter}
+            onChange={(e) => setFilter(e.target.value)}
+            placeholder="Filter settings"
+            className="border border-gray-300 rounded-lg px-3 py-2 text-sm w-full"
+          />
+          <ul className="divide-y divide-gray-200 border border-gray-200 rounded-lg">
+            {visible.map(key => (
+              <li key={key.name} className="px-4 py-3 flex items-center justify-between gap-4">
+                <div className="min-w-0">
+                  <div className="text-sm">
+                    <span className="font-mono text-gray-900">{key.name}</span>
+                    <span className="ml-2 text-xs text-gray-400">{key.type}</span>
+                  </div>
+                  <div className="text-xs text-gray-500">{key.description}</div>
+                </div>
+                <div className="flex items-center gap-2 flex-shrink-0">
+                  {renderInput(key)}
+                  <button
+                    onClick={() => handleSave(key)}
+                    disabled={(values[key.name] || '') === (saved[key.name] || '')}
+                    className="text-blue-600 hover:text-blue-800 disabled:opacity-30"
+                    title="Save"
+                  >
+                    <Save className="w-4 h-4" />
+                  </button>
+                </div>
+              </li>
+            ))}
+          </ul>
+        </div>
+      )}
+    </div>
+  );
+};
+
+export default AdvancedSettings;
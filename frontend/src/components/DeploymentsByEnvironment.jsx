@@ -0,0 +1,126 @@
+import React, { useState, useEffect } from 'react';
+import { Cloud, Filter } from 'lucide-react';
+import useDataChanged from '../hooks/useDataChanged';
+
+const PAGE_SIZE = 10;
+
+// DeploymentsByEnvironment lists a service's current deployments grouped by environment, a page of
+// namespaces at a time, narrowed by environment and region.
+const DeploymentsByEnvironment = ({ serviceId }) => {
+  const [groups, setGroups] = useState([]);
+  const [options, setOptions] = useState({ environments: [], regions: [] });
+  const [filter, setFilter] = useState({ environment: '', region: '' });
+
+  useEffect(() => {
+    loadGroups();
+  }, [serviceId, filter]);
+
+  useDataChanged(['deployments'], 0, () => loadGroups());
+
+  const loadGroups = async () => {
+    try {
+      const result = await window.go.main.App.GetServiceDeploymentsByEnvironment(serviceId, { ...filter, offset: 0, limit: PAGE_SIZE });
+      setGroups(result || []);
+      // The filter options come from the unfiltered groups, so picking one doesn't hide the others
+      if (!filter.environment && !filter.region) {
+        setOptions({
+          environments: (result || []).map(group => group.environment),
+          regions: [...new Set((result || []).flatMap(group => group.regions))].sort(),
+        });
+      }
+    } catch (error) {
+      console.error('Failed to load deployments by environment:', error);
+      setGroups([]);
+    }
+  };
+
+  const loadMore = async (group) => {
+    try {
+      const result = await window.go.main.App.GetServiceDeploymentsByEnvironment(serviceId, {
+        environment: group.environment,
+        region: filter.region,
+        offset: group.deployments.length,
+        limit: PAGE_SIZE,
+      });
+      const more = result?.[0]?.deployments || [];
+      setGroups(groups.map(g => g.environment === group.environment
+        ? { ...g, deployments: [...g.deployments, ...more] }
+        : g));
+    } catch (error) {
+      console.error('Failed to load more deployments:', error);
+    }
+  };
+
+  if (options.environments.length === 0) {
+    return null;
+  }
+
+  return (
+    <div className="mb-6 card">
+      <div className="flex items-center justify-between mb-4">
+        <h2 className="text-lg font-semibold text-gray-900 flex items-center">
+          <Cloud className="h-5 w-5 mr-2 text-blue-600" />
+          Deployments by Environment
+        </h2>
+        <div className="flex items-center gap-2 text-sm">
+          <Filter className="h-4 w-4 text-gray-400" />
+          <select
+            value={filter.environment}
+            onChange={(e) => setFilter({ ...filter, environment: e.target.value })}
+            className="border border-gray-300 rounded px-2 py-1"
+          >
+            <option value="">All environments</option>
+            {options.environments.map(environment => (
+              <option key={environment} value={environment}>{environment}</option>
+            ))}
+          </select>
+          <select
+            value={filter.region}
+            onChange={(e) => setFilter({ ...filter, region: e.target.value })}
+            className="border border-gray-300 rounded px-2 py-1"
+          >
+            <option value="">All regions</option>
+            {options.regions.map(region => (
+              <option key={region} value={region}>{region}</option>
+            ))}
+          </select>
+        </div>
+      </div>
+
+      {groups.length === 0 && (
+        <p className="text-sm text-gray-500">No deployments match the filter.</p>
+      )}
+
+      {groups.map(group => (
+        <div key={group.environment} className="mb-4 last:mb-0">
+          <h3 className="text-sm font-semibold text-gray-800 mb-1">
+            {group.environment}
+            {group.is_primary && <span className="ml-2 text-xs font-medium text-red-700">live</span>}
+            <span className="ml-2 text-xs font-normal text-gray-500">
+              {group.total} {group.total === 1 ? 'target' : 'targets'} in {group.regions.join(', ')}
+            </span>
+          </h3>
+          <table className="min-w-full text-sm">
+            <tbody className="divide-y divide-gray-100">
+              {group.deployments.map(deployment => (
+                <tr key={deployment.id} className={deployment.synced ? '' : 'bg-yellow-50'}>
+                  <td className="py-1 pr-4 text-gray-700">{deployment.region} / {deployment.namespace || '(default)'}</td>
+                  <td className="py-1 pr-4 font-mono">{deployment.tag}</td>
+                  <td className="py-1 pr-4 font-mono text-gray-500">{deployment.commit_sha?.substring(0, 7)}</td>
+                  <td className="py-1 text-gray-500">{deployment.updated_at_relative}</td>
+                </tr>
+              ))}
+            </tbody>
+          </table>
+          {group.deployments.length < group.total && (
+            <button onClick={() => loadMore(group)} className="mt-1 text-xs text-blue-600 hover:underline">
+              Show {Math.min(PAGE_SIZE, group.total - group.deployments.length)} more of {group.total - group.deployments.length}
+            </button>
+          )}
+        </div>
+      ))}
+    </div>
+  );
+};
+
+export default DeploymentsByEnvironment;
@@ -0,0 +1,186 @@
+import React, { useState, useEffect } from 'react';
+import { GetFreezeWindows, CreateFreezeWindow, DeleteFreezeWindow, GetFreezeViolations } from '../../wailsjs/go/main/App';
+import { Snowflake, Plus, Trash2 } from 'lucide-react';
+
+const violationDays = 30;
+
+const emptyWindow = {
+  environment_pattern: 'prd',
+  recurring: false,
+  recurrence: 'fri 16:00-mon 09:00',
+  starts_at: '',
+  ends_at: '',
+  reason: '',
+};
+
+const describeWindow = (window) => {
+  const bounds = [
+    window.starts_at && `from ${new Date(window.starts_at).toLocaleString()}`,
+    window.ends_at && `until ${new Date(window.ends_at).toLocaleString()}`,
+  ].filter(Boolean).join(' ');
+  if (window.recurrence) {
+    return bounds ? `${window.recurrence}, ${bounds}` : window.recurrence;
+  }
+  return bounds;
+};
+
+// Freeze windows flag deployments recorded while an environment is frozen and notify about them
+const FreezeWindows = ({ showMessage }) => {
+  const [windows, setWindows] = useState([]);
+  const [violations, setViolations] = useState([]);
+  const [newWindow, setNewWindow] = useState(emptyWindow);
+
+  useEffect(() => {
+    loadWindows();
+  }, []);
+
+  const loadWindows = async () => {
+    try {
+      setWindows((await GetFreezeWindows()) || []);
+      setViolations((await GetFreezeViolations(violationDays)) || []);
+    } catch (err) {
+      console.error('Failed to load freeze windows:', err);
+    }
+  };
+
+  const handleCreate = async () => {
+    try {
+      await CreateFreezeWindow({
+        environment_pattern: newWindow.environment_pattern,
+        recurrence: newWindow.recurring ? newWindow.recurrence : '',
+        starts_at: newWindow.starts_at ? new Date(newWindow.starts_at).toISOString() : undefined,
+        ends_at: newWindow.ends_at ? new Date(newWindow.ends_at).toISOString() : undefined,
+        reason: newWindow.reason,
+      });
+      setNewWindow(emptyWindow);
+      showMessage('Freeze window created', 'success');
+      loadWindows();
+    } catch (err) {
+      console.error('Failed to create freeze window:', err);
+      showMessage('Failed to create freeze window: ' + err, 'error');
+    }
+  };
+
+  const handleDelete = async (window) => {
+    if (!confirm(`Delete the freeze window for ${window.environment_pattern}? Deployments it flagged are no longer flagged.`)) {
+      return;
+    }
+    try {
+      await DeleteFreezeWindow(window.id);
+      loadWindows();
+    } catch (err) {
+      console.error('Failed to delete freeze window:', err);
+      showMessage('Failed to delete freeze window: ' + err, 'error');
+    }
+  };
+
+  return (
+    <div className="bg-white rounded-lg shadow-sm border border-gray-200">
+      <div className="px-6 py-4 border-b border-gray-200">
+        <div className="flex items-center gap-3">
+          <Snowflake className="w-6 h-6 text-gray-700" />
+          <div>
+            <h2 className="text-lg font-semibold text-gray-900">Freeze Windows</h2>
+            <p className="text-sm text-gray-600 mt-1">
+              Deployments synced while a matching environment is frozen are flagged in the deployment history and raise a notification.
+            </p>
+          </div>
+        </div>
+      </div>
+
+      <div className="p-6 space-y-4">
+        {windows.length > 0 && (
+          <ul className="divide-y divide-gray-200 border border-gray-200 rounded-lg">
+            {windows.map(window => (
+              <li key={window.id} className="px-4 py-2 text-sm flex items-center justify-between">
+                <div>
+                  <span className="font-medium text-gray-900">{window.environment_pattern}</span>
+                  <span className="ml-2 text-gray-500">{describeWindow(window)}</span>
+                  {window.reason && <div className="text-gray-500">{window.reason}</div>}
+                </div>
+                <button onClick={() => handleDelete(window)} className="text-red-600 hover:text-red-800" title="Delete freeze window">
+                  <Trash2 className="w-4 h-4" />
+                </button>
+              </li>
+            ))}
+          </ul>
+        )}
+
+        <div className="space-y-3 border border-gray-200 rounded-lg p-4">
+          <div className="flex flex-wrap items-center gap-3 text-sm">
+            <input
+              type="text"
+              value={newWindow.environment_pattern}
+              onChange={(e) => setNewWindow({ ...newWindow, environment_pattern: e.target.value })}
+              placeholder="Environments, e.g. prd or prd-*"
+              className="border border-gray-300 rounded-lg px-3 py-2 text-sm"
+            />
+            <label className="flex items-center gap-1 text-gray-700">
+              <input
+                type="checkbox"
+                checked={newWindow.recurring}
+                onChange={(e) => setNewWindow({ ...newWindow, recurring: e.target.checked })}
+              />
+              Recurring
+            </label>
+            {newWindow.recurring && (
+              <input
+                type="text"
+                value={newWindow.recurrence}
+                onChange={(e) => setNewWindow({ ...newWindow, recurrence: e.target.value })}
+                placeholder="fri 16:00-mon 09:00 or daily 18:00-08:00"
+                className="border border-gray-300 rounded-lg px-3 py-2 text-sm w-56"
+              />
+            )}
+            <input
+              type="datetime-local"
+              value={newWindow.starts_at}
+              onChange={(e) => setNewWindow({ ...newWindow, starts_at: e.target.value })}
+              title={newWindow.recurring ? 'Optional first day' : 'Start'}
+              className="border border-gray-300 rounded-lg px-3 py-2 text-sm"
+            />
+            <input
+              type="datetime-local"
+              value={newWindow.ends_at}
+              onChange={(e) => setNewWindow({ ...newWindow, ends_at: e.target.value })}
+              title={newWindow.recurring ? 'Optional last day' : 'End'}
+              className="border border-gray-300 rounded-lg px-3 py-2 text-sm"
+            />
+            <input
+              type="text"
+              value={newWindow.reason}
+              onChange={(e) => setNewWindow({ ...newWindow, reason: e.target.value })}
+              placeholder="Reason"
+              className="border border-gray-300 rounded-lg px-3 py-2 text-sm flex-1"
+            />
+          </div>
+          <button
+            onClick={handleCreate}
+            disabled={!newWindow.environment_pattern.trim()}
+            className="flex items-center gap-2 px-4 py-2 border border-blue-600 text-blue-600 rounded-lg hover:bg-blue-50 disabled:opacity-50"
+          >
+            <Plus className="w-4 h-4" />
+            Add Freeze Window
+          </button>
+        </div>
+
+        {violations.length > 0 && (
+          <div>
+            <h3 className="text-sm font-medium text-gray-900 mb-2">Deployments during a freeze in the last {violationDays} days</h3>
+            <ul className="text-sm text-gray-700 space-y-1">
+              {violations.map(violation => (
+                <li key={violation.entry.id}>
+                  <span className="font-medium">{violation.service_name}</span> to {violation.entry.environment} ({violation.entry.region}) on {violation.entry.tag}
+                  <span className="ml-2 text-gray-500">{new Date(violation.entry.observed_at).toLocaleString()}</span>
+                  {violation.freeze_window.reason && <span className="ml-2 text-gray-500">· {violation.freeze_window.reason}</span>}
+                </li>
+              ))}
+            </ul>
+          </div>
+        )}
+      </div>
+    </div>
+  );
+};
+
+export default FreezeWindows;
@@ -0,0 +1,123 @@
+import React, { useState, useEffect } from 'react';
+import { Loader, CheckCircle, XCircle, Ban, X } from 'lucide-react';
+
+const jobLabels = {
+  rediscover_services: 'Rediscover services',
+  export_usage_data: 'Export usage data',
+};
+
+const summarize = (job) => {
+  if (job.status === 'failed') return job.error;
+  if (job.status === 'cancelled') return 'Cancelled';
+  if (job.status === 'running') return job.message || 'Starting…';
+
+  let result = {};
+  try {
+    result = job.result ? JSON.parse(job.result) : {};
+  } catch (error) {
+    console.error('Failed to parse job result:', error);
+  }
+  switch (job.kind) {
+    case 'rediscover_services': {
+      const failed = result.failed?.length ? `, ${result.failed.length} failed` : '';
+      return `${result.services} services in ${result.repositories} repositories${failed}`;
+    }
+    case 'export_usage_data':
+      return `${result.events} events saved to ${job.file_path}`;
+    default:
+      return 'Done';
+  }
+};
+
+// Lists the running background jobs and the finished ones that haven't been dismissed yet
+const JobsTray = () => {
+  const [jobs, setJobs] = useState([]);
+
+  const loadJobs = async () => {
+    try {
+      setJobs((await window.go.main.App.GetJobs()) || []);
+    } catch (error) {
+      console.error('Failed to load jobs:', error);
+    }
+  };
+
+  useEffect(() => {
+    loadJobs();
+    if (!window.runtime?.EventsOn) return;
+
+    const update = (job) => {
+      setJobs((current) => {
+        if (!current.some((j) => j.id === job.id)) return [job, ...current];
+        return current.map((j) => (j.id === job.id ? job : j));
+      });
+    };
+    const offProgress = window.runtime.EventsOn('job:progress', update);
+    const offFinished = window.runtime.EventsOn('job:finished', update);
+    return () => {
+      offProgress();
+      offFinished();
+    };
+  }, []);
+
+  const handleCancel = async (job) => {
+    try {
+      await window.go.main.App.CancelJob(job.id);
+    } catch (error) {
+      console.error('Failed to cancel job:', error);
+      alert('Failed to cancel job: ' + error);
+    }
+  };
+
+  const handleDismiss = async (job) => {
+    try {
+      await window.go.main.App.AcknowledgeJob(job.id);
+      setJobs((current) => current.filter((j) => j.id !== job.id));
+    } catch (error) {
+      console.error('Failed to dismiss job:', error);
+    }
+  };
+
+  if (jobs.length === 0) return null;
+
+  return (
+    <div className="fixed bottom-4 right-4 z-50 w-80 space-y-2">
+      {jobs.map((job) => (
+        <div key={job.id} className="bg-white rounded-lg shadow-lg border border-gray-200 p-3">
+          <div className="flex items-start">
+            {job.status === 'running' && <Loader className="h-4 w-4 mr-2 mt-0.5 text-blue-500 animate-spin flex-shrink-0" />}
+            {job.status === 'succeeded' && <CheckCircle className="h-4 w-4 mr-2 mt-0.5 text-green-500 flex-shrink-0" />}
+            {job.status === 'failed' && <XCircle className="h-4 w-4 mr-2 mt-0.5 text-red-500 flex-shrink-0" />}
+            {job.status === 'cancelled' && <Ban className="h-4 w-4 mr-2 mt-0.5 text-gray-400 flex-shrink-0" />}
+            <div className="flex-1 min-w-0">
+              <p className="text-sm font-medium text-gray-900">{jobLabels[job.kind] || job.kind}</p>
+              <p className="text-xs text-gray-500 break-words">{summarize(job)}</p>
+            </div>
+            {job.status === 'running' ? (
+              <button
+                onClick={() => handleCancel(job)}
+                className="ml-2 text-xs text-gray-500 hover:text-red-600"
+              >
+                Cancel
+              </button>
+            ) : (
+              <button
+                onClick={() => handleDismiss(job)}
+                className="ml-2 text-gray-400 hover:text-gray-600"
+                title="Dismiss"
+              >
+                <X className="h-4 w-4" />
+              </button>
+            )}
+          </div>
+          {job.status === 'running' && (
+            <div className="mt-2 h-1.5 bg-gray-100 rounded-full overflow-hidden">
+              <div className="h-full bg-blue-500 transition-all" style={{ width: `${job.progress}%` }} />
+            </div>
+          )}
+        </div>
+      ))}
+    </div>
+  );
+};
+
+export default JobsTray;
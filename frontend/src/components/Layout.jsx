@@ -15,8 +15,12 @@ import {
   GitPullRequest,
   GitCommit,
   Cloud,
-  Clock
+  Clock,
+  EyeOff,
+  Star
 } from 'lucide-react';
+import JobsTray from './JobsTray';
+import { isPresenting, setPresentationMode } from '../presentation';
 
 const Layout = ({ children }) => {
   const location = useLocation();
@@ -25,6 +29,53 @@ const Layout = ({ children }) => {
   const [selectedService, setSelectedService] = useState('');
   const [selectedServiceId, setSelectedServiceId] = useState('');
   const [isDropdownOpen, setIsDropdownOpen] = useState(false);
+  const [presenting, setPresenting] = useState(isPresenting());
+  const [favorites, setFavorites] = useState([]);
+  const [legacyDatabase, setLegacyDatabase] = useState(null);
+  const [migrating, setMigrating] = useState(false);
+  const [systemWarnings, setSystemWarnings] = useState([]);
+
+  useEffect(() => {
+    window.go.main.App.GetPresentationMode().then(setPresenting).catch(() => {});
+  }, []);
+
+  // A database left by an earlier build keeps the app from opening one until it's migrated
+  useEffect(() => {
+    window.go.main.App.GetLegacyDatabase().then(setLegacyDatabase).catch(() => {});
+  }, []);
+
+  // Warnings such as an expiring GitHub token are checked again every few minutes
+  useEffect(() => {
+    const loadSystemStatus = () => {
+      window.go.main.App.GetSystemStatus()
+        .then(status => setSystemWarnings(status?.warnings || []))
+        .catch(() => {});
+    };
+    loadSystemStatus();
+    const interval = setInterval(loadSystemStatus, 5 * 60 * 1000);
+    return () => clearInterval(interval);
+  }, []);
+
+  const handleTogglePresentationMode = async () => {
+    try {
+      await setPresentationMode(!presenting);
+    } catch (error) {
+      console.error('Failed to toggle presentation mode:', error);
+      alert('Failed to toggle presentation mode: ' + error);
+    }
+  };
+
+  const handleMigrateLegacyDatabase = async (keep) => {
+    setMigrating(true);
+    try {
+      await window.go.main.App.MigrateLegacyDatabase(keep);
+      window.location.reload();
+    } catch (error) {
+      console.error('Failed to migrate legacy database:', error);
+      alert('Failed to migrate the database: ' + error);
+      setMigrating(false);
+    }
+  };
 
   // Extract service ID from current URL if we're on a service page
   useEffect(() => {
@@ -50,6 +101,13 @@ const Layout = ({ children }) => {
     loadServices();
   }, []);
 
+  // Favorite repositories are listed under the navigation; the Repositories page announces changes
+  useEffect(() => {
+    loadFavorites();
+    window.addEventListener('favorites:changed', loadFavorites);
+    return () => window.removeEventListener('favorites:changed', loadFavorites);
+  }, []);
+
   // Close dropdown when clicking outside
   useEffect(() => {
     const handleClickOutside = (event) => {
@@ -66,7 +124,7 @@ const Layout = ({ children }) => {
 
   const loadServices = async () => {
     try {
-      const allServices = await window.go.main.App.GetMicroservices(0);
+      const allServices = await window.go.main.App.GetMicroservicesWithRepository(false);
       setServices(allServices || []);
     } catch (error) {
       console.error('Failed to load services for dropdown:', error);
@@ -74,6 +132,16 @@ const Layout = ({ children }) => {
     }
   };
 
+  const loadFavorites = async () => {
+    try {
+      const repos = await window.go.main.App.GetFavoriteRepositories();
+      setFavorites(repos || []);
+    } catch (error) {
+      console.error('Failed to load favorite repositories:', error);
+      setFavorites([]);
+    }
+  };
+
   const handleServiceSelect = (serviceId, serviceName) => {
     setSelectedService(serviceName);
     setSelectedServiceId(serviceId);
@@ -216,6 +284,30 @@ const Layout = ({ children }) => {
               </Link>
             );
           })}
+
+          {favorites.length > 0 && (
+            <>
+              <div className="my-4">
+                <div className="border-t border-gray-200"></div>
+              </div>
+              <p className="px-2 pb-1 text-xs font-semibold uppercase tracking-wide text-gray-400">Favorites</p>
+              {favorites.map((repo) => {
+                const href = repo.type === 'monorepo' ? `/microservices/${repo.id}` : `/kubernetes/${repo.id}`;
+                return (
+                  <Link
+                    key={`favorite-${repo.id}`}
+                    to={href}
+                    className={`group flex items-center px-2 py-2 text-sm font-medium rounded-md transition-colors ${
+                      location.pathname === href ? 'bg-blue-100 text-blue-700' : 'text-gray-700 hover:bg-gray-100 hover:text-gray-900'
+                    }`}
+                  >
+                    <Star className="mr-3 h-4 w-4 flex-shrink-0 text-yellow-500" fill="currentColor" />
+                    <span className="truncate">{repo.name}</span>
+                  </Link>
+                );
+              })}
+            </>
+          )}
         </nav>
 
         <div className="absolute bottom-4 left-4 right-4">
@@ -237,7 +329,18 @@ const Layout = ({ children }) => {
       <div className="flex-1 ml-64">
         {/* Top bar with service selector */}
         <div className="bg-white border-b border-gray-200 px-8 py-4">
-          <div className="flex justify-end">
+          <div className="flex justify-end items-center gap-3">
+            {/* Presentation mode disguises names for screenshots and blocks changes */}
+            <button
+              onClick={handleTogglePresentationMode}
+              title={presenting ? 'Names, URLs and JIRA keys are disguised; changes are disabled' : 'Disguise names, URLs and JIRA keys for screenshots'}
+              className={`flex items-center px-3 py-2 text-sm font-medium rounded-md transition-colors ${
+                presenting ? 'bg-purple-100 text-purple-800 hover:bg-purple-200' : 'text-gray-600 bg-gray-100 hover:bg-gray-200'
+              }`}
+            >
+              <EyeOff className="h-4 w-4 mr-2" />
+              {presenting ? 'Presenting' : 'Presentation mode'}
+            </button>
             {/* Service Selector Dropdown */}
             <div className="relative service-dropdown">
               <button
@@ -265,7 +368,7 @@ const Layout = ({ children }) => {
                             <Package className="h-4 w-4 mr-3 mt-0.5 text-blue-500 flex-shrink-0" />
                             <div className="min-w-0 flex-1">
                               <div className="font-medium text-gray-900 truncate">{service.name}</div>
-                              <div className="text-xs text-gray-500 truncate mt-0.5">{service.path}</div>
+                              <div className="text-xs text-gray-500 truncate mt-0.5">{service.repository_name} · {service.path}</div>
                               {service.description && (
                                 <div className="text-xs text-gray-400 truncate mt-1">{service.description}</div>
                               )}
@@ -286,9 +389,48 @@ const Layout = ({ children }) => {
         </div>
         
         <main className="p-8">
+          {systemWarnings.map(warning => (
+            <div
+              key={warning.kind}
+              className={`mb-6 p-4 rounded-lg text-sm border ${warning.severity === 'error' ? 'bg-red-50 border-red-200 text-red-900' : 'bg-amber-50 border-amber-200 text-amber-900'}`}
+            >
+              {warning.message}
+              {warning.kind === 'github_token_expiry' && (
+                <Link to="/settings" className="ml-2 font-medium underline">Replace it in the settings</Link>
+              )}
+            </div>
+          ))}
+          {legacyDatabase && (
+            <div className="mb-6 p-4 bg-amber-50 border border-amber-200 rounded-lg text-sm text-amber-900">
+              <p className="font-medium">A database from an earlier version was found at {legacyDatabase.path}</p>
+              <p className="mt-1">
+                {legacyDatabase.current_has_data
+                  ? `${legacyDatabase.current_path} holds data too. Choose which database to keep; they aren't merged.`
+                  : `Migrate it to ${legacyDatabase.current_path} to keep your data. Nothing loads until you do.`}
+              </p>
+              <div className="mt-3 flex gap-2">
+                <button
+                  onClick={() => handleMigrateLegacyDatabase('legacy')}
+                  disabled={migrating}
+                  className="btn-primary"
+                >
+                  {legacyDatabase.current_has_data ? 'Use the old database' : 'Migrate'}
+                </button>
+                <button
+                  onClick={() => handleMigrateLegacyDatabase('current')}
+                  disabled={migrating}
+                  className="px-3 py-2 text-sm font-medium text-gray-700 bg-white border border-gray-300 rounded-md hover:bg-gray-50"
+                >
+                  {legacyDatabase.current_has_data ? 'Keep the current database' : 'Start fresh'}
+                </button>
+              </div>
+            </div>
+          )}
           {children}
         </main>
       </div>
+
+      <JobsTray />
     </div>
   );
 };
@@ -110,10 +110,10 @@ const RepositoryModal = ({ onClose, onRepositoryCreated }) => {
 
     if (!formData.url.trim()) {
       newErrors.url = 'Repository URL is required';
-    } else if (!formData.url.match(/^https:\/\/.+/)) {
-      newErrors.url = 'Please enter a valid HTTPS URL';
-    } else if (!formData.url.match(/^https:\/\/[^\/]+\/[^\/]+\/[^\/]+/)) {
-      newErrors.url = 'Please enter a valid GitHub repository URL (e.g., https://github.com/owner/repo or https://github.company.com/owner/repo)';
+    } else if (!formData.url.match(/^(https|ssh):\/\/.+/) && !formData.url.match(/^[^\/:@]+@[^\/:]+:.+/)) {
+      newErrors.url = 'Please enter a valid HTTPS or SSH URL';
+    } else if (!formData.url.match(/^(https|ssh):\/\/[^\/]+\/[^\/]+\/[^\/]+/) && !formData.url.match(/^[^\/:@]+@[^\/:]+:\/?[^\/]+\/[^\/]+/)) {
+      newErrors.url = 'Please enter a valid GitHub repository URL (e.g., https://github.com/owner/repo or git@github.com:owner/repo.git)';
     }
 
     if (!githubTokenConfigured) {
@@ -0,0 +1,85 @@
+import React, { useState, useEffect } from 'react';
+import { Boxes, AlertTriangle } from 'lucide-react';
+
+// ServicePackages lists the direct dependencies of a service's go.mod and package.json, flagging
+// the ones a major version or more behind. Nothing shows until the repository collects packages.
+const ServicePackages = ({ serviceId }) => {
+  const [result, setResult] = useState(null);
+  const [showDev, setShowDev] = useState(false);
+
+  useEffect(() => {
+    window.go.main.App.GetServicePackages(serviceId)
+      .then(setResult)
+      .catch(error => console.error('Failed to load service packages:', error));
+  }, [serviceId]);
+
+  if (!result || result.manifests.length === 0) {
+    return null;
+  }
+
+  const devCount = result.packages.filter(pkg => pkg.dev).length;
+  const visible = result.packages.filter(pkg => showDev || !pkg.dev);
+
+  return (
+    <div className="card mb-8">
+      <div className="flex items-center justify-between mb-4">
+        <h2 className="text-xl font-semibold text-gray-900 flex items-center">
+          <Boxes className="h-6 w-6 mr-2 text-indigo-600" />
+          Packages
+          {result.major_behind > 0 && (
+            <span className="ml-3 px-2 py-0.5 text-xs font-medium rounded-full bg-amber-100 text-amber-800">
+              {result.major_behind} a major version behind
+            </span>
+          )}
+        </h2>
+        {devCount > 0 && (
+          <label className="flex items-center gap-1 text-sm text-gray-600">
+            <input type="checkbox" checked={showDev} onChange={(e) => setShowDev(e.target.checked)} />
+            Dev dependencies ({devCount})
+          </label>
+        )}
+      </div>
+
+      {result.manifests.filter(manifest => manifest.parse_error).map(manifest => (
+        <p key={manifest.path} className="text-sm text-red-600 mb-2">
+          <AlertTriangle className="inline h-4 w-4 mr-1" />
+          {manifest.path} couldn't be read: {manifest.parse_error}
+        </p>
+      ))}
+
+      <table className="min-w-full text-sm">
+        <thead>
+          <tr className="text-left text-gray-500 border-b border-gray-200">
+            <th className="py-2 pr-4 font-medium">Package</th>
+            <th className="py-2 pr-4 font-medium">Version</th>
+            <th className="py-2 pr-4 font-medium">Latest</th>
+            <th className="py-2 font-medium">Manifest</th>
+          </tr>
+        </thead>
+        <tbody className="divide-y divide-gray-100">
+          {visible.map(pkg => (
+            <tr key={pkg.id}>
+              <td className="py-1.5 pr-4 font-mono text-gray-900">
+                {pkg.name}
+                {pkg.dev && <span className="ml-2 text-xs text-gray-400">dev</span>}
+              </td>
+              <td className="py-1.5 pr-4 font-mono text-gray-700">{pkg.version}</td>
+              <td className="py-1.5 pr-4 font-mono">
+                {pkg.latest_version ? (
+                  <span className={pkg.major_behind ? 'text-amber-700 font-semibold' : 'text-gray-700'}>
+                    {pkg.latest_version}
+                  </span>
+                ) : (
+                  <span className="text-gray-400" title={pkg.lookup_error || 'Not looked up yet'}>—</span>
+                )}
+              </td>
+              <td className="py-1.5 text-gray-500">{pkg.manifest_path}</td>
+            </tr>
+          ))}
+        </tbody>
+      </table>
+    </div>
+  );
+};
+
+export default ServicePackages;
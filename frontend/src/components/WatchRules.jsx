@@ -0,0 +1,411 @@
+import React, { useState, useEffect } from 'react';
+import { GetWatchRules, CreateWatchRule, SetWatchRuleEnabled, DeleteWatchRule, GetWatchRuleEvaluations, EvaluateWatchRules, GetMicroservices, GetServiceCustomFields, GetConfig } from '../../wailsjs/go/main/App';
+import { Eye, Plus, Trash2, Play, List } from 'lucide-react';
+
+const conditionLabels = {
+  tag_changed: 'Tag changed',
+  deploy_failed: 'Deploy failed',
+  no_deploy: 'No deploy in N days',
+  drift: 'Drift exceeds N commits',
+};
+
+const thresholdConditions = ['no_deploy', 'drift'];
+
+const emptyRule = {
+  name: '',
+  scope: 'services',
+  service_ids: [],
+  scope_field: '',
+  scope_value: '',
+  environments: [],
+  conditions: {},
+};
+
+const describeScope = (rule, services) => {
+  switch (rule.scope) {
+    case 'all':
+      return 'All services';
+    case 'services':
+      return rule.service_ids
+        .map(id => services.find(service => service.id === id)?.name || `Service ${id}`)
+        .join(', ');
+    case 'custom_field':
+      return `${rule.scope_field} = ${rule.scope_value}`;
+    case 'domain':
+      return `Domain ${rule.scope_value}`;
+    default:
+      return rule.scope;
+  }
+};
+
+const describeCondition = (condition) => {
+  switch (condition.type) {
+    case 'no_deploy':
+      return `No deploy in ${condition.threshold} days`;
+    case 'drift':
+      return `Drift over ${condition.threshold} commits`;
+    default:
+      return conditionLabels[condition.type] || condition.type;
+  }
+};
+
+// Watch rules raise notifications for deployment events of the services and environments they
+// scope; they're evaluated at the end of each sync pass
+const WatchRules = ({ showMessage }) => {
+  const [rules, setRules] = useState([]);
+  const [services, setServices] = useState([]);
+  const [customFields, setCustomFields] = useState([]);
+  const [knownEnvironments, setKnownEnvironments] = useState([]);
+  const [newRule, setNewRule] = useState(emptyRule);
+  const [extraEnvironment, setExtraEnvironment] = useState('');
+  const [evaluations, setEvaluations] = useState({});
+  const [evaluating, setEvaluating] = useState(false);
+
+  useEffect(() => {
+    loadRules();
+    loadOptions();
+  }, []);
+
+  const loadRules = async () => {
+    try {
+      setRules((await GetWatchRules()) || []);
+    } catch (err) {
+      console.error('Failed to load watch rules:', err);
+    }
+  };
+
+  const loadOptions = async () => {
+    try {
+      setServices((await GetMicroservices(0, false)) || []);
+      setCustomFields((await GetServiceCustomFields()) || []);
+      const order = await GetConfig('environment_order');
+      setKnownEnvironments((order || '').split(',').map(env => env.trim()).filter(Boolean));
+    } catch (err) {
+      console.error('Failed to load watch rule options:', err);
+    }
+  };
+
+  const toggleListValue = (key, value) => {
+    setNewRule(prev => ({
+      ...prev,
+      [key]: prev[key].includes(value) ? prev[key].filter(v => v !== value) : [...prev[key], value],
+    }));
+  };
+
+  const toggleCondition = (type) => {
+    setNewRule(prev => {
+      const conditions = { ...prev.conditions };
+      if (type in conditions) {
+        delete conditions[type];
+      } else {
+        conditions[type] = thresholdConditions.includes(type) ? (type === 'no_deploy' ? 7 : 10) : 0;
+      }
+      return { ...prev, conditions };
+    });
+  };
+
+  const addExtraEnvironment = () => {
+    const environment = extraEnvironment.trim();
+    if (environment && !newRule.environments.includes(environment)) {
+      setNewRule(prev => ({ ...prev, environments: [...prev.environments, environment] }));
+    }
+    setExtraEnvironment('');
+  };
+
+  const handleCreate = async () => {
+    try {
+      await CreateWatchRule({
+        name: newRule.name,
+        enabled: true,
+        scope: newRule.scope,
+        service_ids: newRule.service_ids,
+        scope_field: newRule.scope_field,
+        scope_value: newRule.scope_value,
+        environments: newRule.environments,
+        conditions: Object.entries(newRule.conditions).map(([type, threshold]) => ({ type, threshold: Number(threshold) })),
+      });
+      setNewRule(emptyRule);
+      showMessage('Watch rule created', 'success');
+      loadRules();
+    } catch (err) {
+      console.error('Failed to create watch rule:', err);
+      showMessage('Failed to create watch rule: ' + err, 'error');
+    }
+  };
+
+  const handleToggle = async (rule) => {
+    try {
+      await SetWatchRuleEnabled(rule.id, !rule.enabled);
+      loadRules();
+    } catch (err) {
+      console.error('Failed to update watch rule:', err);
+      showMessage('Failed to update watch rule: ' + err, 'error');
+    }
+  };
+
+  const handleDelete = async (rule) => {
+    if (!window.confirm(`Delete watch rule "${rule.name}" and its evaluation log?`)) {
+      return;
+    }
+    try {
+      await DeleteWatchRule(rule.id);
+      loadRules();
+    } catch (err) {
+      console.error('Failed to delete watch rule:', err);
+      showMessage('Failed to delete watch rule: ' + err, 'error');
+    }
+  };
+
+  const handleToggleLog = async (rule) => {
+    if (evaluations[rule.id]) {
+      setEvaluations(prev => ({ ...prev, [rule.id]: undefined }));
+      return;
+    }
+    try {
+      const log = (await GetWatchRuleEvaluations(rule.id, 20)) || [];
+      setEvaluations(prev => ({ ...prev, [rule.id]: log }));
+    } catch (err) {
+      console.error('Failed to load watch rule evaluations:', err);
+      showMessage('Failed to load evaluations: ' + err, 'error');
+    }
+  };
+
+  const handleEvaluate = async () => {
+    setEvaluating(true);
+    try {
+      await EvaluateWatchRules();
+      setEvaluations({});
+      loadRules();
+      showMessage('Watch rules evaluated', 'success');
+    } catch (err) {
+      console.error('Failed to evaluate watch rules:', err);
+      showMessage('Failed to evaluate watch rules: ' + err, 'error');
+    } finally {
+      setEvaluating(false);
+    }
+  };
+
+  const environments = [...new Set([...knownEnvironments, ...newRule.environments])];
+  const selectedField = customFields.find(field => field.name === newRule.scope_field);
+
+  return (
+    <div className="bg-white rounded-lg shadow-sm border border-gray-200">
+      <div className="px-6 py-4 border-b border-gray-200">
+        <div className="flex items-center justify-between">
+          <div className="flex items-center gap-3">
+            <Eye className="w-6 h-6 text-gray-700" />
+            <div>
+              <h2 className="text-lg font-semibold text-gray-900">Watch Rules</h2>
+              <p className="text-sm text-gray-600 mt-1">
+                Get notified when watched services change tags, fail to deploy, go without a deploy or drift behind in the environments you pick. Rules are checked after every sync.
+              </p>
+            </div>
+          </div>
+          <button
+            onClick={handleEvaluate}
+            disabled={evaluating || rules.length === 0}
+            className="flex items-center gap-2 px-3 py-2 text-sm border border-gray-300 rounded-lg hover:bg-gray-50 disabled:opacity-50"
+          >
+            <Play className="w-4 h-4" />
+            Evaluate Now
+          </button>
+        </div>
+      </div>
+
+      <div className="p-6 space-y-4">
+        {rules.length > 0 && (
+          <ul className="divide-y divide-gray-200 border border-gray-200 rounded-lg">
+            {rules.map(rule => (
+              <li key={rule.id} className="px-4 py-2 text-sm">
+                <div className="flex items-center justify-between">
+                  <div>
+                    <span className={`font-medium ${rule.enabled ? 'text-gray-900' : 'text-gray-400'}`}>{rule.name}</span>
+                    <span className="ml-2 text-gray-500">{describeScope(rule, services)}</span>
+                    <span className="ml-2 text-gray-500">
+                      in {rule.environments.length > 0 ? rule.environments.join(', ') : 'all environments'}
+                    </span>
+                    <div className="text-gray-500">
+                      {rule.conditions.map(describeCondition).join(' · ')}
+                      {rule.last_triggered_at && (
+                        <span className="ml-2 text-gray-400">last triggered {new Date(rule.last_triggered_at).toLocaleString()}</span>
+                      )}
+                    </div>
+                  </div>
+                  <div className="flex items-center gap-3">
+                    <label className="flex items-center gap-1 text-gray-600">
+                      <input type="checkbox" checked={rule.enabled} onChange={() => handleToggle(rule)} />
+                      Enabled
+                    </label>
+                    <button onClick={() => handleToggleLog(rule)} className="text-gray-600 hover:text-gray-900" title="Evaluation log">
+                      <List className="w-4 h-4" />
+                    </button>
+                    <button onClick={() => handleDelete(rule)} className="text-red-600 hover:text-red-800" title="Delete rule">
+                      <Trash2 className="w-4 h-4" />
+                    </button>
+                  </div>
+                </div>
+
+                {evaluations[rule.id] && (
+                  <div className="mt-2 space-y-2">
+                    {evaluations[rule.id].length === 0 && (
+                      <p className="text-gray-500">Not evaluated yet</p>
+                    )}
+                    {evaluations[rule.id].map(evaluation => (
+                      <div key={evaluation.id} className="border border-gray-100 rounded p-2">
+                        <div className="text-gray-700">
+                          {new Date(evaluation.evaluated_at).toLocaleString()} · {evaluation.services} services · {evaluation.fired} fired
+                        </div>
+                        {evaluation.error && <div className="text-red-600">{evaluation.error}</div>}
+                        <ul className="text-xs text-gray-500">
+                          {evaluation.outcomes.map((outcome, i) => (
+                            <li key={i} className={outcome.fired ? 'text-orange-700 font-medium' : ''}>
+                              {outcome.service_name}: {outcome.detail}
+                            </li>
+                          ))}
+                        </ul>
+                      </div>
+                    ))}
+                  </div>
+                )}
+              </li>
+            ))}
+          </ul>
+        )}
+
+        <div className="space-y-3 border border-gray-200 rounded-lg p-4">
+          <div className="flex flex-wrap gap-3">
+            <input
+              type="text"
+              value={newRule.name}
+              onChange={(e) => setNewRule({ ...newRule, name: e.target.value })}
+              placeholder="Rule name"
+              className="border border-gray-300 rounded-lg px-3 py-2 text-sm"
+            />
+            <select
+              value={newRule.scope}
+              onChange={(e) => setNewRule({ ...newRule, scope: e.target.value })}
+              className="border border-gray-300 rounded-lg px-3 py-2 text-sm"
+            >
+              <option value="services">Selected services</option>
+              <option value="custom_field">Services with a custom field value</option>
+              <option value="domain">Services in a domain</option>
+              <option value="all">All services</option>
+            </select>
+            {newRule.scope === 'custom_field' && (
+              <>
+                <select
+                  value={newRule.scope_field}
+                  onChange={(e) => setNewRule({ ...newRule, scope_field: e.target.value, scope_value: '' })}
+                  className="border border-gray-300 rounded-lg px-3 py-2 text-sm"
+                >
+                  <option value="">Field…</option>
+                  {customFields.map(field => (
+                    <option key={field.id} value={field.name}>{field.name}</option>
+                  ))}
+                </select>
+                {selectedField?.type === 'enum' ? (
+                  <select
+                    value={newRule.scope_value}
+                    onChange={(e) => setNewRule({ ...newRule, scope_value: e.target.value })}
+                    className="border border-gray-300 rounded-lg px-3 py-2 text-sm"
+                  >
+                    <option value="">Value…</option>
+                    {selectedField.allowed_values.map(value => (
+                      <option key={value} value={value}>{value}</option>
+                    ))}
+                  </select>
+                ) : (
+                  <input
+                    type="text"
+                    value={newRule.scope_value}
+                    onChange={(e) => setNewRule({ ...newRule, scope_value: e.target.value })}
+                    placeholder="Value"
+                    className="border border-gray-300 rounded-lg px-3 py-2 text-sm"
+                  />
+                )}
+              </>
+            )}
+            {newRule.scope === 'domain' && (
+              <input
+                type="text"
+                value={newRule.scope_value}
+                onChange={(e) => setNewRule({ ...newRule, scope_value: e.target.value })}
+                placeholder="Domain"
+                className="border border-gray-300 rounded-lg px-3 py-2 text-sm"
+              />
+            )}
+          </div>
+
+          {newRule.scope === 'services' && (
+            <div className="max-h-40 overflow-y-auto border border-gray-200 rounded-lg p-2 grid grid-cols-2 md:grid-cols-3 gap-1 text-sm">
+              {services.map(service => (
+                <label key={service.id} className="flex items-center gap-2 text-gray-700">
+                  <input
+                    type="checkbox"
+                    checked={newRule.service_ids.includes(service.id)}
+                    onChange={() => toggleListValue('service_ids', service.id)}
+                  />
+                  {service.name}
+                </label>
+              ))}
+            </div>
+          )}
+
+          <div className="flex flex-wrap items-center gap-3 text-sm text-gray-700">
+            <span className="font-medium">Environments</span>
+            {environments.map(environment => (
+              <label key={environment} className="flex items-center gap-1">
+                <input
+                  type="checkbox"
+                  checked={newRule.environments.includes(environment)}
+                  onChange={() => toggleListValue('environments', environment)}
+                />
+                {environment}
+              </label>
+            ))}
+            <input
+              type="text"
+              value={extraEnvironment}
+              onChange={(e) => setExtraEnvironment(e.target.value)}
+              onKeyDown={(e) => e.key === 'Enter' && addExtraEnvironment()}
+              onBlur={addExtraEnvironment}
+              placeholder="Other environment"
+              className="border border-gray-300 rounded-lg px-2 py-1 text-sm w-36"
+            />
+            {newRule.environments.length === 0 && <span className="text-gray-400">none selected watches all</span>}
+          </div>
+
+          <div className="flex flex-wrap items-center gap-4 text-sm text-gray-700">
+            <span className="font-medium">Conditions</span>
+            {Object.entries(conditionLabels).map(([type, label]) => (
+              <label key={type} className="flex items-center gap-1">
+                <input type="checkbox" checked={type in newRule.conditions} onChange={() => toggleCondition(type)} />
+                {label}
+                {thresholdConditions.includes(type) && type in newRule.conditions && (
+                  <input
+                    type="number"
+                    min="1"
+                    value={newRule.conditions[type]}
+                    onChange={(e) => setNewRule(prev => ({ ...prev, conditions: { ...prev.conditions, [type]: e.target.value } }))}
+                    className="ml-1 border border-gray-300 rounded px-2 py-1 w-16"
+                  />
+                )}
+              </label>
+            ))}
+          </div>
+
+          <button
+            onClick={handleCreate}
+            disabled={!newRule.name.trim() || Object.keys(newRule.conditions).length === 0}
+            className="flex items-center gap-2 px-4 py-2 border border-blue-600 text-blue-600 rounded-lg hover:bg-blue-50 disabled:opacity-50"
+          >
+            <Plus className="w-4 h-4" />
+            Add Rule
+          </button>
+        </div>
+      </div>
+    </div>
+  );
+};
+
+export default WatchRules;
@@ -0,0 +1,29 @@
+import { useEffect, useRef } from 'react';
+
+// Calls onChange when a background sync changed any of the given entity types
+// ('services', 'deployments', 'actions', 'resources', 'tasks'). When repositoryId is set,
+// only changes in that repository trigger a reload.
+const useDataChanged = (entities, repositoryId, onChange) => {
+  const onChangeRef = useRef(onChange);
+  onChangeRef.current = onChange;
+
+  const entityKey = entities.join(',');
+
+  useEffect(() => {
+    if (!window.runtime?.EventsOn) return;
+
+    return window.runtime.EventsOn('data:changed', (event) => {
+      const relevant = entityKey.split(',').some((entity) => {
+        const repoIds = event?.changes?.[entity];
+        if (!repoIds) return false;
+        return !repositoryId || repoIds.includes(repositoryId);
+      });
+
+      if (relevant) {
+        onChangeRef.current();
+      }
+    });
+  }, [entityKey, repositoryId]);
+};
+
+export default useDataChanged;
@@ -2,6 +2,9 @@ import React from 'react'
 import {createRoot} from 'react-dom/client'
 import './index.css'
 import App from './App'
+import {installPresentationMode} from './presentation'
+
+installPresentationMode()
 
 const container = document.getElementById('root')
 
@@ -9,16 +9,24 @@ import {
   Clock,
   CheckCircle,
   XCircle,
-  AlertCircle
+  AlertCircle,
+  MessageSquare
 } from 'lucide-react';
+import useDataChanged from '../hooks/useDataChanged';
 
 const Dashboard = () => {
   const [stats, setStats] = useState({
     repositories: 0,
     microservices: 0,
     kubernetesResources: 0,
-    recentActions: []
+    recentActions: [],
+    buildRollup: null
   });
+  const [buildMatrix, setBuildMatrix] = useState([]);
+  const [annotations, setAnnotations] = useState([]);
+  const [feedGroupBy, setFeedGroupBy] = useState('none');
+  const [feedType, setFeedType] = useState('');
+  const [feed, setFeed] = useState(null);
   const [loading, setLoading] = useState(true);
 
   // Load real dashboard stats
@@ -26,6 +34,24 @@ const Dashboard = () => {
     loadDashboardStats();
   }, []);
 
+  useEffect(() => {
+    loadFeed();
+  }, [feedGroupBy, feedType]);
+
+  useDataChanged(['services', 'actions', 'resources'], 0, () => {
+    loadDashboardStats();
+    loadFeed();
+  });
+
+  const loadFeed = async () => {
+    try {
+      setFeed(await window.go.main.App.GetActivityFeed(0, 10, feedGroupBy, feedType));
+    } catch (error) {
+      console.error('Failed to load activity feed:', error);
+      setFeed(null);
+    }
+  };
+
   const loadDashboardStats = async () => {
     try {
       // Add a small delay to ensure Wails is initialized
@@ -37,8 +63,15 @@ const Dashboard = () => {
         repositories: dashboardStats?.repositories || 0,
         microservices: dashboardStats?.microservices || 0,
         kubernetesResources: dashboardStats?.kubernetesResources || 0,
-        recentActions: dashboardStats?.recentActions || []
+        recentActions: dashboardStats?.recentActions || [],
+        buildRollup: dashboardStats?.buildRollup || null
       });
+
+      const matrix = await window.go.main.App.GetBuildMatrix(0);
+      setBuildMatrix(matrix?.entries || []);
+
+      const recentAnnotations = await window.go.main.App.GetRecentAnnotations(10);
+      setAnnotations(recentAnnotations || []);
     } catch (error) {
       console.error('Failed to load dashboard stats:', error);
       // Set empty stats on error
@@ -46,7 +79,8 @@ const Dashboard = () => {
         repositories: 0,
         microservices: 0,
         kubernetesResources: 0,
-        recentActions: []
+        recentActions: [],
+        buildRollup: null
       });
     } finally {
       setLoading(false);
@@ -66,6 +100,41 @@ const Dashboard = () => {
     }
   };
 
+  const renderAction = (action) => (
+    <div key={action.id} className="flex items-center space-x-4 p-3 bg-gray-50 rounded-lg">
+      {getStatusIcon(action.status)}
+      <div className="flex-1 min-w-0">
+        <p className="text-sm font-medium text-gray-900">
+          {action.type} • {action.service_name || action.resource_name || 'Unknown'}
+        </p>
+        <div className="flex items-center space-x-2 text-sm text-gray-500">
+          <GitBranch className="h-4 w-4" />
+          <span>{action.branch}</span>
+          <span>•</span>
+          <span>{action.commit}</span>
+        </div>
+      </div>
+      <div className="flex items-center text-sm text-gray-500">
+        <Clock className="h-4 w-4 mr-1" />
+        <span title={new Date(action.started_at).toLocaleString()}>
+          {action.started_at_relative || new Date(action.started_at).toLocaleDateString()}
+        </span>
+      </div>
+    </div>
+  );
+
+  const formatDuration = (seconds) => {
+    if (!seconds) return '';
+    const minutes = Math.floor(seconds / 60);
+    return minutes > 0 ? `${minutes}m ${seconds % 60}s` : `${seconds}s`;
+  };
+
+  const rollupClasses = {
+    green: 'bg-green-100 text-green-800',
+    red: 'bg-red-100 text-red-800',
+    no_data: 'bg-gray-100 text-gray-600'
+  };
+
   if (loading) {
     return (
       <div className="flex justify-center items-center min-h-64">
@@ -134,32 +203,84 @@ const Dashboard = () => {
         </div>
       </div>
 
+      {/* Build Matrix */}
+      {stats.buildRollup && stats.buildRollup.total > 0 && (
+        <div className="card mb-8">
+          <div className="flex items-center justify-between mb-4">
+            <h2 className="text-lg font-semibold text-gray-900">Default Branch Builds</h2>
+            <span className={`px-2 py-1 rounded text-xs font-medium ${rollupClasses[stats.buildRollup.overall] || rollupClasses.no_data}`}>
+              {stats.buildRollup.overall === 'no_data' ? 'no data' : stats.buildRollup.overall}
+            </span>
+          </div>
+          <p className="text-sm text-gray-500 mb-4">
+            {stats.buildRollup.success} passing • {stats.buildRollup.failure} failing • {stats.buildRollup.running} running
+            {stats.buildRollup.cancelled > 0 && ` • ${stats.buildRollup.cancelled} cancelled`} • {stats.buildRollup.no_data} no data
+          </p>
+          <div className="grid grid-cols-1 gap-2 sm:grid-cols-2 lg:grid-cols-3">
+            {buildMatrix.map((entry) => (
+              <Link
+                key={entry.service_id}
+                to={`/service/${entry.service_id}`}
+                className="flex items-center space-x-3 p-2 bg-gray-50 hover:bg-gray-100 rounded-lg"
+              >
+                {entry.status === 'no_data'
+                  ? <AlertCircle className="h-5 w-5 text-gray-300" />
+                  : getStatusIcon(entry.status)}
+                <div className="flex-1 min-w-0">
+                  <p className="text-sm font-medium text-gray-900 truncate">{entry.service_name}</p>
+                  <p className="text-xs text-gray-500 truncate">
+                    {entry.status === 'no_data'
+                      ? 'no data'
+                      : `${entry.branch} • ${entry.commit?.substring(0, 7)}${entry.duration_seconds ? ` • ${formatDuration(entry.duration_seconds)}` : ''}`}
+                  </p>
+                </div>
+              </Link>
+            ))}
+          </div>
+        </div>
+      )}
+
       {/* Recent Activity */}
       <div className="grid grid-cols-1 lg:grid-cols-2 gap-6">
         <div className="card">
-          <h2 className="text-lg font-semibold text-gray-900 mb-4">Recent Actions</h2>
+          <div className="flex items-center justify-between mb-4">
+            <h2 className="text-lg font-semibold text-gray-900">Recent Actions</h2>
+            <div className="flex items-center space-x-2 text-sm">
+              <select
+                value={feedType}
+                onChange={(e) => setFeedType(e.target.value)}
+                className="border border-gray-300 rounded px-2 py-1"
+              >
+                <option value="">All types</option>
+                <option value="build">Builds</option>
+                <option value="deployment">Deployments</option>
+              </select>
+              <select
+                value={feedGroupBy}
+                onChange={(e) => setFeedGroupBy(e.target.value)}
+                className="border border-gray-300 rounded px-2 py-1"
+              >
+                <option value="none">No grouping</option>
+                <option value="repo">By repository</option>
+                <option value="service">By service</option>
+                <option value="type">By type</option>
+              </select>
+            </div>
+          </div>
           <div className="space-y-4">
-            {stats.recentActions && stats.recentActions.length > 0 ? (
-              stats.recentActions.map((action) => (
-                <div key={action.id} className="flex items-center space-x-4 p-3 bg-gray-50 rounded-lg">
-                  {getStatusIcon(action.status)}
-                  <div className="flex-1 min-w-0">
-                    <p className="text-sm font-medium text-gray-900">
-                      {action.type} • {action.service_name || action.resource_name || 'Unknown'}
-                    </p>
-                    <div className="flex items-center space-x-2 text-sm text-gray-500">
-                      <GitBranch className="h-4 w-4" />
-                      <span>{action.branch}</span>
-                      <span>•</span>
-                      <span>{action.commit}</span>
-                    </div>
-                  </div>
-                  <div className="flex items-center text-sm text-gray-500">
-                    <Clock className="h-4 w-4 mr-1" />
-                    {new Date(action.started_at).toLocaleDateString()}
+            {feed && (feed.actions?.length > 0 || feed.groups?.length > 0) ? (
+              feed.group_by === 'none' ? (
+                feed.actions.map(renderAction)
+              ) : (
+                feed.groups.map((group) => (
+                  <div key={group.key}>
+                    <h3 className="text-sm font-medium text-gray-700 mb-2">
+                      {group.label} <span className="text-gray-400">({group.actions.length})</span>
+                    </h3>
+                    <div className="space-y-2">{group.actions.map(renderAction)}</div>
                   </div>
-                </div>
-              ))
+                ))
+              )
             ) : (
               <div className="text-center py-8">
                 <Activity className="mx-auto h-12 w-12 text-gray-300" />
@@ -223,6 +344,38 @@ const Dashboard = () => {
           </div>
         </div>
       </div>
+
+      {/* Recent Notes */}
+      {annotations.length > 0 && (
+        <div className="card mt-6">
+          <h2 className="text-lg font-semibold text-gray-900 mb-4">Recent Notes</h2>
+          <div className="space-y-3">
+            {annotations.map((annotation) => (
+              <div key={annotation.id} className="flex items-start space-x-3 p-3 bg-amber-50 rounded-lg">
+                <MessageSquare className="h-4 w-4 text-amber-600 mt-0.5" />
+                <div className="flex-1 min-w-0">
+                  <p className="text-sm text-gray-900 whitespace-pre-wrap">{annotation.text}</p>
+                  <p className="text-xs text-gray-500 mt-1">
+                    {annotation.service_id > 0 ? (
+                      <Link
+                        to={`/service/${annotation.service_id}/deployment-history`}
+                        className="text-blue-600 hover:text-blue-500"
+                      >
+                        {annotation.service_name}
+                      </Link>
+                    ) : null}
+                    {annotation.service_id > 0 && annotation.subject ? ' • ' : ''}
+                    {annotation.subject}
+                  </p>
+                </div>
+                <span className="text-xs text-gray-500 whitespace-nowrap" title={new Date(annotation.created_at).toLocaleString()}>
+                  {annotation.created_at_relative}
+                </span>
+              </div>
+            ))}
+          </div>
+        </div>
+      )}
     </div>
   );
 };
@@ -10,8 +10,11 @@ import {
   AlertCircle,
   Activity,
   ExternalLink,
-  Filter
+  Filter,
+  FolderTree
 } from 'lucide-react';
+import useDataChanged from '../hooks/useDataChanged';
+import ActionArtifacts from '../components/ActionArtifacts';
 
 const Microservices = () => {
   const { repoId } = useParams();
@@ -20,6 +23,14 @@ const Microservices = () => {
   const [repository, setRepository] = useState(null);
   const [filter, setFilter] = useState('all');
   const [selectedService, setSelectedService] = useState(null);
+  const [deploymentCounts, setDeploymentCounts] = useState({});
+  const [groupByDomain, setGroupByDomain] = useState(false);
+  const [domainGroups, setDomainGroups] = useState(null);
+  const [customFields, setCustomFields] = useState([]);
+  // Custom field filters by field name; fields without an entry aren't filtered on
+  const [fieldFilters, setFieldFilters] = useState({});
+  const [comparison, setComparison] = useState({ source: '', target: '', format: 'markdown' });
+  const [registries, setRegistries] = useState([]);
 
   // Load real microservices data
   useEffect(() => {
@@ -27,45 +38,158 @@ const Microservices = () => {
     if (repoId) {
       loadRepository();
     }
-  }, [repoId]);
+  }, [repoId, fieldFilters]);
 
+  useEffect(() => {
+    loadCustomFields();
+  }, []);
+
+  // Reload when a background sync changes services or their actions
+  useDataChanged(['services', 'actions'], repoId ? parseInt(repoId) : 0, () => loadMicroservices());
+  useDataChanged(['deployments'], 0, () => {
+    loadMicroservices();
+    loadRegistries();
+  });
+
+  useEffect(() => {
+    loadRegistries();
+  }, []);
+
+  const exportCatalog = async (format) => {
+    try {
+      const path = await window.go.main.App.ExportServiceCatalog('', format);
+      if (path) {
+        alert(`Service catalog saved to ${path}`);
+      }
+    } catch (error) {
+      console.error('Failed to export service catalog:', error);
+      alert(`Failed to export service catalog: ${error}`);
+    }
+  };
+
+  // Shows what the picked catalog file would change and applies it once confirmed
+  const importCatalog = async () => {
+    try {
+      const plan = await window.go.main.App.PreviewServiceCatalogImport('');
+      if (!plan) {
+        return;
+      }
+      const problems = plan.problems.map(problem => `Row ${problem.row}: ${problem.message}`);
+      if (plan.changes.length === 0) {
+        alert(['No changes to import.', ...problems].join('\n'));
+        return;
+      }
+      const changes = plan.changes.map(change =>
+        `${change.service_name} ${change.field}: "${change.old}" → "${change.new}"`
+      );
+      const summary = [
+        `${plan.changes.length} change(s) from ${plan.path}:`,
+        ...changes.slice(0, 20),
+        ...(changes.length > 20 ? [`…and ${changes.length - 20} more`] : []),
+        ...(problems.length > 0 ? ['', `${problems.length} problem(s), skipped:`, ...problems.slice(0, 10)] : []),
+        '',
+        'Apply these changes?',
+      ];
+      if (!window.confirm(summary.join('\n'))) {
+        return;
+      }
+      const result = await window.go.main.App.ImportServiceCatalog(plan.path);
+      alert(`Imported ${result.changes.length} change(s)`);
+      loadMicroservices();
+    } catch (error) {
+      console.error('Failed to import service catalog:', error);
+      alert(`Failed to import service catalog: ${error}`);
+    }
+  };
+
+  // Environments any service is deployed to, for the comparison report
+  const knownEnvironments = [...new Set(Object.values(deploymentCounts).flatMap(count => count.environments))].sort();
+
+  const saveComparisonReport = async () => {
+    try {
+      const path = await window.go.main.App.SaveEnvironmentComparisonReport(comparison.source, comparison.target, comparison.format);
+      if (path) {
+        alert(`Environment comparison saved to ${path}`);
+      }
+    } catch (error) {
+      console.error('Failed to save environment comparison:', error);
+      alert(`Failed to save environment comparison: ${error}`);
+    }
+  };
+
+  const loadRegistries = async () => {
+    try {
+      const usage = await window.go.main.App.GetImageRegistries();
+      setRegistries(usage || []);
+    } catch (error) {
+      console.error('Failed to load image registries:', error);
+    }
+  };
+
+  const loadCustomFields = async () => {
+    try {
+      const fields = await window.go.main.App.GetServiceCustomFields();
+      setCustomFields(fields || []);
+    } catch (error) {
+      console.error('Failed to load custom fields:', error);
+    }
+  };
+
+  // The whole list comes from the summaries sync maintains, in one call
   const loadMicroservices = async () => {
     try {
       // If no repoId, get all microservices (pass 0), otherwise get for specific repo
       const repositoryId = repoId ? parseInt(repoId) : 0;
-      const microservices = await window.go.main.App.GetMicroservices(repositoryId);
-      
-      // Transform the data to include action information
-      const servicesWithActions = await Promise.all(
-        (microservices || []).map(async (service) => {
-          try {
-            const actions = await window.go.main.App.GetMicroserviceActions(service.id, 10);
-            const buildActions = actions?.filter(a => a.type === 'build') || [];
-            const deployActions = actions?.filter(a => a.type === 'deployment') || [];
-            
-            return {
-              ...service,
-              lastBuild: buildActions.length > 0 ? buildActions[0] : null,
-              lastDeployment: deployActions.length > 0 ? deployActions[0] : null
-            };
-          } catch (err) {
-            console.error(`Failed to load actions for service ${service.name}:`, err);
-            return {
-              ...service,
-              lastBuild: null,
-              lastDeployment: null
-            };
-          }
-        })
-      );
-      
+      const filters = Object.entries(fieldFilters).map(([field, value]) => ({ field, value }));
+      const summaries = await window.go.main.App.GetServiceSummaries({
+        repository_id: repositoryId,
+        include_hidden: false,
+        custom_fields: filters,
+      });
+
+      const counts = {};
+      const servicesWithActions = (summaries || []).map((summary) => {
+        if (summary.environments.length > 0) {
+          counts[summary.id] = {
+            service_id: summary.id,
+            environments: summary.environments.map(env => env.environment),
+            counts: Object.fromEntries(summary.environments.map(env => [env.environment, env.targets])),
+          };
+        }
+        return {
+          ...summary,
+          lastBuild: summary.last_build,
+          lastDeployment: summary.last_deployment
+        };
+      });
+
       setServices(servicesWithActions);
+      setDeploymentCounts(counts);
+
+      // Same order as GetMicroservicesGroupedByDomain: by domain, services without one last
+      const domains = [...new Set(servicesWithActions.map(service => service.domain || 'ungrouped'))]
+        .sort((a, b) => (a === 'ungrouped') - (b === 'ungrouped') || a.localeCompare(b));
+      setDomainGroups(domains.map(domain => ({
+        domain,
+        serviceIds: new Set(servicesWithActions.filter(service => (service.domain || 'ungrouped') === domain).map(service => service.id))
+      })));
     } catch (error) {
       console.error('Failed to load microservices:', error);
       setServices([]);
     }
   };
 
+  // Recomputes every service's summary, for when the list looks out of date
+  const rebuildSummaries = async () => {
+    try {
+      await window.go.main.App.RebuildServiceSummaries();
+      await loadMicroservices();
+    } catch (error) {
+      console.error('Failed to rebuild service summaries:', error);
+      alert(`Failed to rebuild service summaries: ${error}`);
+    }
+  };
+
   const loadRepository = async () => {
     if (!repoId) return;
     
@@ -114,6 +238,24 @@ const Microservices = () => {
     });
   };
 
+  // Folds a duplicate service into another of the same repository, which keeps its ID
+  const handleMergeInto = async (duplicate, keepID) => {
+    const keep = services.find((s) => s.id === keepID);
+    if (!keep || !confirm(`Merge ${duplicate.name} into ${keep.name}? Its deployments and actions move to ${keep.name} and ${duplicate.name} is deleted.`)) {
+      return;
+    }
+    try {
+      const result = await window.go.main.App.MergeServices(keep.id, duplicate.id);
+      const dropped = result.dropped_deployments ? `, ${result.dropped_deployments} duplicate deployments dropped` : '';
+      alert(`Moved ${result.deployments} deployments, ${result.deployment_history} history entries and ${result.actions} actions to ${keep.name}${dropped}.`);
+      setSelectedService(null);
+      await loadMicroservices();
+    } catch (error) {
+      console.error('Failed to merge services:', error);
+      alert('Failed to merge services: ' + error);
+    }
+  };
+
   const handleViewDetails = (serviceId) => {
     // Navigate to service details page, which will trigger the Layout component
     // to update the dropdown and show service-specific navigation
@@ -128,6 +270,194 @@ const Microservices = () => {
     return true;
   });
 
+  const renderService = (service) => (
+    <div key={service.id} className="card">
+      <div className="flex items-start justify-between mb-4">
+        <div className="flex items-start space-x-4">
+          <div className="p-3 bg-blue-100 rounded-lg">
+            <Package className="h-6 w-6 text-blue-600" />
+          </div>
+          <div>
+            <div className="flex items-center space-x-2">
+              <h3 className="text-lg font-semibold text-gray-900">{service.name}</h3>
+              {deploymentCounts[service.id] && (
+                <span
+                  className="px-2 py-0.5 rounded bg-gray-100 text-xs font-mono text-gray-600"
+                  title="Deployment targets per environment"
+                >
+                  {deploymentCounts[service.id].environments
+                    .map(env => `${env}:${deploymentCounts[service.id].counts[env]}`)
+                    .join(' ')}
+                </span>
+              )}
+              {service.open_pull_requests > 0 && (
+                <span className="px-2 py-0.5 rounded bg-blue-50 text-xs text-blue-700" title="Open pull requests when last fetched">
+                  {service.open_pull_requests} open PR{service.open_pull_requests === 1 ? '' : 's'}
+                </span>
+              )}
+              {service.open_tasks > 0 && (
+                <span className="px-2 py-0.5 rounded bg-amber-50 text-xs text-amber-700" title="Open tasks whose tickets its recent commits mention">
+                  {service.open_tasks} open task{service.open_tasks === 1 ? '' : 's'}
+                </span>
+              )}
+            </div>
+            <p className="text-gray-600">{service.description}</p>
+            <div className="flex items-center mt-1 text-sm text-gray-500">
+              <ExternalLink className="h-4 w-4 mr-1" />
+              <span>{service.path}</span>
+              {service.owner && <span className="ml-3">Owner: {service.owner}</span>}
+            </div>
+            {service.custom_fields && (
+              <div className="flex flex-wrap gap-1 mt-2">
+                {Object.entries(service.custom_fields).map(([name, value]) => (
+                  <span key={name} className="px-2 py-0.5 rounded bg-purple-50 text-xs text-purple-700">
+                    {name}: {value}
+                  </span>
+                ))}
+              </div>
+            )}
+          </div>
+        </div>
+        <div className="flex space-x-2">
+          <button
+            onClick={() => handleViewDetails(service.id)}
+            className="btn-primary"
+          >
+            More Details
+          </button>
+          <button
+            onClick={() => setSelectedService(selectedService === service.id ? null : service.id)}
+            className="btn-secondary"
+          >
+            {selectedService === service.id ? 'Hide' : 'Quick View'}
+          </button>
+        </div>
+      </div>
+
+      {/* Build and Deployment Status */}
+      <div className="grid grid-cols-1 md:grid-cols-2 gap-4">
+        {/* Last Build */}
+        <div className="bg-gray-50 p-4 rounded-lg">
+          <div className="flex items-center justify-between mb-2">
+            <h4 className="text-sm font-medium text-gray-700">Last Build</h4>
+            <span className={getStatusClass(service.lastBuild?.status || 'pending')}>
+              {service.lastBuild?.status || 'No builds'}
+            </span>
+          </div>
+          <div className="space-y-1 text-sm text-gray-600">
+            {service.lastBuild ? (
+              <>
+                <div className="flex items-center">
+                  <GitBranch className="h-4 w-4 mr-2" />
+                  <span>{service.lastBuild.branch} • {service.lastBuild.commit}</span>
+                </div>
+                <div className="flex items-center">
+                  <Clock className="h-4 w-4 mr-2" />
+                  <span>{formatDate(service.lastBuild.started_at)}</span>
+                </div>
+                {service.lastBuild.build_hash && (
+                  <div className="text-xs text-gray-500">
+                    Build: {service.lastBuild.build_hash}
+                  </div>
+                )}
+              </>
+            ) : (
+              <div className="text-gray-500">No build history available</div>
+            )}
+          </div>
+        </div>
+
+        {/* Last Deployment */}
+        <div className="bg-gray-50 p-4 rounded-lg">
+          <div className="flex items-center justify-between mb-2">
+            <h4 className="text-sm font-medium text-gray-700">Last Deployment</h4>
+            <span className={getStatusClass(service.lastDeployment?.status || 'pending')}>
+              {service.lastDeployment?.status || 'No deployments'}
+            </span>
+          </div>
+          <div className="space-y-1 text-sm text-gray-600">
+            {service.lastDeployment ? (
+              <>
+                <div className="flex items-center">
+                  <GitBranch className="h-4 w-4 mr-2" />
+                  <span>{service.lastDeployment.branch} • {service.lastDeployment.commit}</span>
+                </div>
+                <div className="flex items-center">
+                  <Clock className="h-4 w-4 mr-2" />
+                  <span>{formatDate(service.lastDeployment.started_at)}</span>
+                </div>
+                {service.lastDeployment.build_hash && (
+                  <div className="text-xs text-gray-500">
+                    Build: {service.lastDeployment.build_hash}
+                  </div>
+                )}
+              </>
+            ) : (
+              <div className="text-gray-500">No deployment history available</div>
+            )}
+          </div>
+        </div>
+      </div>
+
+      {/* Detailed Actions (expandable) */}
+      {selectedService === service.id && (
+        <div className="mt-4 p-4 bg-gray-50 rounded-lg">
+          <h4 className="text-sm font-medium text-gray-700 mb-3">Recent Actions</h4>
+          <div className="space-y-2">
+            {service.lastBuild && (
+              <div className="flex flex-wrap items-center space-x-3 text-sm">
+                {getStatusIcon(service.lastBuild.status)}
+                <span className="capitalize">build</span>
+                <span className="text-gray-500">•</span>
+                <span className="text-gray-500">{formatDate(service.lastBuild.started_at)}</span>
+                <ActionArtifacts key={service.lastBuild.id} actionId={service.lastBuild.id} />
+              </div>
+            )}
+            {service.lastDeployment && (
+              <div className="flex items-center space-x-3 text-sm">
+                {getStatusIcon(service.lastDeployment.status)}
+                <span className="capitalize">deployment</span>
+                <span className="text-gray-500">•</span>
+                <span className="text-gray-500">{formatDate(service.lastDeployment.started_at)}</span>
+              </div>
+            )}
+            {!service.lastBuild && !service.lastDeployment && (
+              <div className="text-gray-500 text-sm">No recent actions available</div>
+            )}
+          </div>
+          <div className="mt-3 flex space-x-2">
+            <button 
+              onClick={() => handleViewDetails(service.id)}
+              className="btn-primary text-xs"
+            >
+              Full Details
+            </button>
+            <button className="btn-secondary text-xs">
+              <Play className="h-4 w-4 mr-1" />
+              Trigger Build
+            </button>
+            <button className="btn-secondary text-xs">
+              View Logs
+            </button>
+            <select
+              value=""
+              onChange={(e) => handleMergeInto(service, parseInt(e.target.value))}
+              className="text-xs border border-gray-300 rounded-md px-2"
+              title="Merge this duplicate into another service"
+            >
+              <option value="">Merge into…</option>
+              {services
+                .filter((s) => s.repository_id === service.repository_id && s.id !== service.id)
+                .map((s) => (
+                  <option key={s.id} value={s.id}>{s.name} ({s.path})</option>
+                ))}
+            </select>
+          </div>
+        </div>
+      )}
+    </div>
+  );
+
   return (
     <div className="max-w-7xl mx-auto">
       <div className="mb-8">
@@ -163,151 +493,157 @@ const Microservices = () => {
             </button>
           ))}
         </div>
+        <button
+          onClick={() => setGroupByDomain(!groupByDomain)}
+          className={`ml-auto flex items-center px-3 py-1 rounded-full text-sm font-medium transition-colors ${
+            groupByDomain
+              ? 'bg-blue-100 text-blue-800'
+              : 'bg-gray-100 text-gray-700 hover:bg-gray-200'
+          }`}
+        >
+          <FolderTree className="h-4 w-4 mr-1" />
+          Group by Domain
+        </button>
+        <button
+          onClick={() => exportCatalog('csv')}
+          className="px-3 py-1 rounded-full text-sm font-medium bg-gray-100 text-gray-700 hover:bg-gray-200"
+          title="Export the description, owner, primary environment and custom fields of every service"
+        >
+          Export catalog
+        </button>
+        <button
+          onClick={importCatalog}
+          className="px-3 py-1 rounded-full text-sm font-medium bg-gray-100 text-gray-700 hover:bg-gray-200"
+          title="Update service metadata from an edited catalog file"
+        >
+          Import catalog
+        </button>
+        <button
+          onClick={rebuildSummaries}
+          className="px-3 py-1 rounded-full text-sm font-medium bg-gray-100 text-gray-700 hover:bg-gray-200"
+          title="Recompute the build, deployment and count summaries shown on the cards"
+        >
+          Rebuild summaries
+        </button>
       </div>
 
-      {/* Services Grid */}
-      <div className="grid gap-6">
-        {filteredServices.map((service) => (
-          <div key={service.id} className="card">
-            <div className="flex items-start justify-between mb-4">
-              <div className="flex items-start space-x-4">
-                <div className="p-3 bg-blue-100 rounded-lg">
-                  <Package className="h-6 w-6 text-blue-600" />
-                </div>
-                <div>
-                  <h3 className="text-lg font-semibold text-gray-900">{service.name}</h3>
-                  <p className="text-gray-600">{service.description}</p>
-                  <div className="flex items-center mt-1 text-sm text-gray-500">
-                    <ExternalLink className="h-4 w-4 mr-1" />
-                    <span>{service.path}</span>
-                  </div>
-                </div>
-              </div>
-              <div className="flex space-x-2">
-                <button
-                  onClick={() => handleViewDetails(service.id)}
-                  className="btn-primary"
-                >
-                  More Details
-                </button>
-                <button
-                  onClick={() => setSelectedService(selectedService === service.id ? null : service.id)}
-                  className="btn-secondary"
-                >
-                  {selectedService === service.id ? 'Hide' : 'Quick View'}
-                </button>
-              </div>
-            </div>
+      {/* Custom field filters; text fields are shown on the cards but not filtered on here */}
+      {customFields.some(field => field.type !== 'text') && (
+        <div className="flex flex-wrap items-center gap-3 mb-6">
+          {customFields.filter(field => field.type !== 'text').map(field => (
+            <label key={field.id} className="flex items-center text-sm text-gray-700">
+              <span className="mr-2">{field.name}:</span>
+              <select
+                value={fieldFilters[field.name] ?? '*'}
+                onChange={(e) => {
+                  const { [field.name]: _, ...rest } = fieldFilters;
+                  setFieldFilters(e.target.value === '*' ? rest : { ...rest, [field.name]: e.target.value });
+                }}
+                className="border border-gray-300 rounded px-2 py-1 text-sm"
+              >
+                <option value="*">Any</option>
+                <option value="">Not set</option>
+                {(field.type === 'bool' ? ['true', 'false'] : field.allowed_values).map(value => (
+                  <option key={value} value={value}>{value}</option>
+                ))}
+              </select>
+            </label>
+          ))}
+        </div>
+      )}
 
-            {/* Build and Deployment Status */}
-            <div className="grid grid-cols-1 md:grid-cols-2 gap-4">
-              {/* Last Build */}
-              <div className="bg-gray-50 p-4 rounded-lg">
-                <div className="flex items-center justify-between mb-2">
-                  <h4 className="text-sm font-medium text-gray-700">Last Build</h4>
-                  <span className={getStatusClass(service.lastBuild?.status || 'pending')}>
-                    {service.lastBuild?.status || 'No builds'}
-                  </span>
-                </div>
-                <div className="space-y-1 text-sm text-gray-600">
-                  {service.lastBuild ? (
-                    <>
-                      <div className="flex items-center">
-                        <GitBranch className="h-4 w-4 mr-2" />
-                        <span>{service.lastBuild.branch} • {service.lastBuild.commit}</span>
-                      </div>
-                      <div className="flex items-center">
-                        <Clock className="h-4 w-4 mr-2" />
-                        <span>{formatDate(service.lastBuild.timestamp)}</span>
-                      </div>
-                      {service.lastBuild.buildHash && (
-                        <div className="text-xs text-gray-500">
-                          Build: {service.lastBuild.buildHash}
-                        </div>
-                      )}
-                    </>
-                  ) : (
-                    <div className="text-gray-500">No build history available</div>
-                  )}
-                </div>
-              </div>
+      {/* Environment comparison report across all services */}
+      {knownEnvironments.length > 1 && (
+        <div className="flex flex-wrap items-center gap-3 mb-6 text-sm text-gray-700">
+          <span>Compare environments:</span>
+          {['source', 'target'].map(side => (
+            <select
+              key={side}
+              value={comparison[side]}
+              onChange={(e) => setComparison({ ...comparison, [side]: e.target.value })}
+              className="border border-gray-300 rounded px-2 py-1 text-sm"
+            >
+              <option value="">{side === 'source' ? 'From…' : 'To…'}</option>
+              {knownEnvironments.map(env => (
+                <option key={env} value={env}>{env}</option>
+              ))}
+            </select>
+          ))}
+          <select
+            value={comparison.format}
+            onChange={(e) => setComparison({ ...comparison, format: e.target.value })}
+            className="border border-gray-300 rounded px-2 py-1 text-sm"
+          >
+            <option value="markdown">Markdown</option>
+            <option value="csv">CSV</option>
+          </select>
+          <button
+            onClick={saveComparisonReport}
+            disabled={!comparison.source || !comparison.target || comparison.source === comparison.target}
+            className="px-3 py-1 rounded bg-blue-600 text-white text-sm font-medium hover:bg-blue-700 disabled:opacity-50"
+          >
+            Save report
+          </button>
+        </div>
+      )}
 
-              {/* Last Deployment */}
-              <div className="bg-gray-50 p-4 rounded-lg">
-                <div className="flex items-center justify-between mb-2">
-                  <h4 className="text-sm font-medium text-gray-700">Last Deployment</h4>
-                  <span className={getStatusClass(service.lastDeployment?.status || 'pending')}>
-                    {service.lastDeployment?.status || 'No deployments'}
+      {/* Which services pull from which image registry */}
+      {registries.length > 0 && (
+        <details className="card mb-6">
+          <summary className="cursor-pointer text-sm font-medium text-gray-700">
+            Image registries ({registries.length})
+          </summary>
+          <div className="mt-3 space-y-4">
+            {registries.map(registry => (
+              <div key={registry.registry}>
+                <h3 className="text-sm font-semibold text-gray-900">
+                  <span className="font-mono">{registry.registry}</span>
+                  <span className="ml-2 font-normal text-gray-500">
+                    {registry.services.length} services, {registry.deployments} deployments
                   </span>
-                </div>
-                <div className="space-y-1 text-sm text-gray-600">
-                  {service.lastDeployment ? (
-                    <>
-                      <div className="flex items-center">
-                        <GitBranch className="h-4 w-4 mr-2" />
-                        <span>{service.lastDeployment.branch} • {service.lastDeployment.commit}</span>
-                      </div>
-                      <div className="flex items-center">
-                        <Clock className="h-4 w-4 mr-2" />
-                        <span>{formatDate(service.lastDeployment.timestamp)}</span>
-                      </div>
-                      {service.lastDeployment.buildHash && (
-                        <div className="text-xs text-gray-500">
-                          Build: {service.lastDeployment.buildHash}
-                        </div>
-                      )}
-                    </>
-                  ) : (
-                    <div className="text-gray-500">No deployment history available</div>
-                  )}
-                </div>
+                </h3>
+                <ul className="mt-1 text-sm text-gray-700">
+                  {registry.services.map(service => (
+                    <li key={`${service.service_id}-${service.image_repository}`} className="flex flex-wrap gap-x-2">
+                      <button
+                        onClick={() => navigate(`/service/${service.service_id}/deployments`)}
+                        className="text-blue-600 hover:underline"
+                      >
+                        {service.service_name}
+                      </button>
+                      <span className="font-mono text-xs text-gray-500">{service.image_repository}</span>
+                      <span className="text-xs text-gray-500">({service.environments.join(', ')})</span>
+                    </li>
+                  ))}
+                </ul>
               </div>
-            </div>
+            ))}
+          </div>
+        </details>
+      )}
 
-            {/* Detailed Actions (expandable) */}
-            {selectedService === service.id && (
-              <div className="mt-4 p-4 bg-gray-50 rounded-lg">
-                <h4 className="text-sm font-medium text-gray-700 mb-3">Recent Actions</h4>
-                <div className="space-y-2">
-                  {service.lastBuild && (
-                    <div className="flex items-center space-x-3 text-sm">
-                      {getStatusIcon(service.lastBuild.status)}
-                      <span className="capitalize">build</span>
-                      <span className="text-gray-500">•</span>
-                      <span className="text-gray-500">{formatDate(service.lastBuild.timestamp)}</span>
-                    </div>
-                  )}
-                  {service.lastDeployment && (
-                    <div className="flex items-center space-x-3 text-sm">
-                      {getStatusIcon(service.lastDeployment.status)}
-                      <span className="capitalize">deployment</span>
-                      <span className="text-gray-500">•</span>
-                      <span className="text-gray-500">{formatDate(service.lastDeployment.timestamp)}</span>
-                    </div>
-                  )}
-                  {!service.lastBuild && !service.lastDeployment && (
-                    <div className="text-gray-500 text-sm">No recent actions available</div>
-                  )}
-                </div>
-                <div className="mt-3 flex space-x-2">
-                  <button 
-                    onClick={() => handleViewDetails(service.id)}
-                    className="btn-primary text-xs"
-                  >
-                    Full Details
-                  </button>
-                  <button className="btn-secondary text-xs">
-                    <Play className="h-4 w-4 mr-1" />
-                    Trigger Build
-                  </button>
-                  <button className="btn-secondary text-xs">
-                    View Logs
-                  </button>
+      {/* Services Grid */}
+      <div className="grid gap-6">
+        {groupByDomain && domainGroups ? (
+          domainGroups.map((group) => {
+            const groupServices = filteredServices.filter(service => group.serviceIds.has(service.id));
+            if (groupServices.length === 0) return null;
+            return (
+              <div key={group.domain}>
+                <h2 className="text-sm font-semibold text-gray-500 uppercase tracking-wide mb-3 flex items-center">
+                  <FolderTree className="h-4 w-4 mr-2" />
+                  {group.domain}
+                  <span className="ml-2 font-normal normal-case">({groupServices.length})</span>
+                </h2>
+                <div className="grid gap-6">
+                  {groupServices.map(renderService)}
                 </div>
               </div>
-            )}
-          </div>
-        ))}
+            );
+          })
+        ) : (
+          filteredServices.map(renderService)
+        )}
       </div>
 
       {filteredServices.length === 0 && (
@@ -1,6 +1,10 @@
 import React, { useState, useEffect } from 'react';
-import { GetProjects, GetTasksByProject, CreateProject, UpdateProject, DeleteProject } from '../../wailsjs/go/main/App';
-import { Plus, Edit, Trash2, Calendar, Clock } from 'lucide-react';
+import {
+  GetProjects, GetTasksByProject, CreateProject, UpdateProject, DeleteProject,
+  GetTaskChecklist, AddTaskChecklistItem, ToggleTaskChecklistItem, ReorderTaskChecklist, DeleteTaskChecklistItem,
+  GetCommitsForTicket
+} from '../../wailsjs/go/main/App';
+import { Plus, Edit, Trash2, Calendar, Clock, CheckSquare, ArrowUp, ArrowDown, GitCommit } from 'lucide-react';
 import ProjectModal from '../components/ProjectModal';
 import TaskModal from '../components/TaskModal';
 
@@ -13,6 +17,9 @@ const Projects = () => {
   const [showProjectModal, setShowProjectModal] = useState(false);
   const [showTaskModal, setShowTaskModal] = useState(false);
   const [editingProject, setEditingProject] = useState(null);
+  const [checklists, setChecklists] = useState({}); // task id -> items, for expanded checklists
+  const [newChecklistItems, setNewChecklistItems] = useState({}); // task id -> text being added
+  const [relatedCommits, setRelatedCommits] = useState({}); // task id -> commits mentioning its ticket
 
   useEffect(() => {
     loadProjects();
@@ -48,6 +55,66 @@ const Projects = () => {
     }
   };
 
+  const toggleChecklist = async (taskId) => {
+    if (checklists[taskId]) {
+      const { [taskId]: _, ...rest } = checklists;
+      setChecklists(rest);
+      return;
+    }
+    await reloadChecklist(taskId);
+  };
+
+  const toggleRelatedCommits = async (task) => {
+    if (relatedCommits[task.id]) {
+      const { [task.id]: _, ...rest } = relatedCommits;
+      setRelatedCommits(rest);
+      return;
+    }
+    try {
+      const commits = await GetCommitsForTicket(task.jira_ticket_id);
+      setRelatedCommits(prev => ({ ...prev, [task.id]: commits || [] }));
+    } catch (err) {
+      setError('Failed to load related commits: ' + err);
+    }
+  };
+
+  // Reloads a task's checklist and the task list, whose done/total counts changed
+  const reloadChecklist = async (taskId) => {
+    try {
+      const items = await GetTaskChecklist(taskId);
+      setChecklists(prev => ({ ...prev, [taskId]: items || [] }));
+      if (selectedProject) {
+        await loadTasks(selectedProject.id);
+      }
+    } catch (err) {
+      console.error('Failed to load checklist:', err);
+    }
+  };
+
+  const updateChecklist = async (taskId, change) => {
+    try {
+      await change();
+      await reloadChecklist(taskId);
+    } catch (err) {
+      setError('Failed to update checklist: ' + err);
+    }
+  };
+
+  const handleAddChecklistItem = (taskId) => {
+    const text = (newChecklistItems[taskId] || '').trim();
+    if (!text) return;
+    setNewChecklistItems(prev => ({ ...prev, [taskId]: '' }));
+    updateChecklist(taskId, () => AddTaskChecklistItem(taskId, text));
+  };
+
+  const handleMoveChecklistItem = (taskId, index, offset) => {
+    const ids = checklists[taskId].map(item => item.id);
+    const target = index + offset;
+    if (target < 0 || target >= ids.length) return;
+    [ids[index], ids[target]] = [ids[target], ids[index]];
+    updateChecklist(taskId, () => ReorderTaskChecklist(taskId, ids));
+  };
+
   const handleCreateProject = async (projectData) => {
     try {
       await CreateProject(projectData);
@@ -241,6 +308,22 @@ const Projects = () => {
                           </div>
                           <div className="flex items-center gap-4 text-sm text-gray-600">
                             <span>Jira: {task.jira_ticket_id}</span>
+                            <button
+                              onClick={() => toggleChecklist(task.id)}
+                              className="flex items-center gap-1 hover:text-gray-900"
+                              title="Checklist"
+                            >
+                              <CheckSquare className="w-4 h-4" />
+                              {task.checklist_total > 0 ? `${task.checklist_done}/${task.checklist_total}` : 'Checklist'}
+                            </button>
+                            <button
+                              onClick={() => toggleRelatedCommits(task)}
+                              className="flex items-center gap-1 hover:text-gray-900"
+                              title="Commits whose messages mention the ticket"
+                            >
+                              <GitCommit className="w-4 h-4" />
+                              Commits
+                            </button>
                             {task.scheduled_date && (
                               <span className="flex items-center gap-1">
                                 <Calendar className="w-4 h-4" />
@@ -260,6 +343,64 @@ const Projects = () => {
                           {task.description && (
                             <p className="text-sm text-gray-700 mt-2">{task.description}</p>
                           )}
+                          {relatedCommits[task.id] && (
+                            <div className="mt-3 space-y-1 text-sm">
+                              {relatedCommits[task.id].length === 0 ? (
+                                <p className="text-gray-500">No loaded commits mention {task.jira_ticket_id}.</p>
+                              ) : (
+                                relatedCommits[task.id].map(commit => (
+                                  <div key={`${commit.repository_id}-${commit.hash}`} className="flex items-center gap-2">
+                                    <GitCommit className="w-4 h-4 text-gray-400" />
+                                    {commit.url ? (
+                                      <a href={commit.url} target="_blank" rel="noopener noreferrer" className="font-mono text-blue-600 hover:underline">
+                                        {commit.hash.substring(0, 7)}
+                                      </a>
+                                    ) : (
+                                      <span className="font-mono">{commit.hash.substring(0, 7)}</span>
+                                    )}
+                                    <span className="flex-1 truncate text-gray-800">{commit.message.split('\n')[0]}</span>
+                                    <span className="text-gray-500">{commit.repository_name} • {commit.author} • {commit.date_relative}</span>
+                                  </div>
+                                ))
+                              )}
+                            </div>
+                          )}
+                          {checklists[task.id] && (
+                            <div className="mt-3 space-y-1">
+                              {checklists[task.id].map((item, index) => (
+                                <div key={item.id} className="flex items-center gap-2 text-sm">
+                                  <input
+                                    type="checkbox"
+                                    checked={item.done}
+                                    onChange={() => updateChecklist(task.id, () => ToggleTaskChecklistItem(item.id))}
+                                  />
+                                  <span className={`flex-1 ${item.done ? 'line-through text-gray-400' : 'text-gray-800'}`}>{item.text}</span>
+                                  <button onClick={() => handleMoveChecklistItem(task.id, index, -1)} disabled={index === 0} className="text-gray-400 hover:text-gray-700 disabled:opacity-30" title="Move up">
+                                    <ArrowUp className="w-4 h-4" />
+                                  </button>
+                                  <button onClick={() => handleMoveChecklistItem(task.id, index, 1)} disabled={index === checklists[task.id].length - 1} className="text-gray-400 hover:text-gray-700 disabled:opacity-30" title="Move down">
+                                    <ArrowDown className="w-4 h-4" />
+                                  </button>
+                                  <button onClick={() => updateChecklist(task.id, () => DeleteTaskChecklistItem(item.id))} className="text-gray-400 hover:text-red-600" title="Delete">
+                                    <Trash2 className="w-4 h-4" />
+                                  </button>
+                                </div>
+                              ))}
+                              <div className="flex items-center gap-2 pt-1">
+                                <input
+                                  type="text"
+                                  value={newChecklistItems[task.id] || ''}
+                                  onChange={(e) => setNewChecklistItems(prev => ({ ...prev, [task.id]: e.target.value }))}
+                                  onKeyDown={(e) => e.key === 'Enter' && handleAddChecklistItem(task.id)}
+                                  placeholder="Add a step…"
+                                  className="flex-1 border border-gray-300 rounded px-2 py-1 text-sm"
+                                />
+                                <button onClick={() => handleAddChecklistItem(task.id)} className="text-blue-600 hover:text-blue-800" title="Add">
+                                  <Plus className="w-4 h-4" />
+                                </button>
+                              </div>
+                            </div>
+                          )}
                         </div>
                       </div>
                     </div>
@@ -8,28 +8,68 @@ import {
   Clock,
   Settings,
   Trash2,
-  RefreshCw
+  RefreshCw,
+  Stethoscope,
+  Webhook,
+  AlertTriangle,
+  Archive,
+  GitCommit,
+  Hand,
+  ListChecks,
+  ShieldAlert,
+  Star,
+  Boxes,
+  GitBranch
 } from 'lucide-react';
 import RepositoryModal from '../components/RepositoryModal';
 
 const Repositories = () => {
   const [repositories, setRepositories] = useState([]);
   const [showAddModal, setShowAddModal] = useState(false);
+  const [diagnostics, setDiagnostics] = useState({}); // repo id -> { loading, result, error }
+  const [webhooks, setWebhooks] = useState({}); // repo id -> { loading, status, error, targetURL }
+  const [commitImpacts, setCommitImpacts] = useState({}); // repo id -> { sha, loading, result, error }
+  const [accessReport, setAccessReport] = useState(null);
+  const [syncStatus, setSyncStatus] = useState({}); // repo id -> sync status
+  const [waitingForSync, setWaitingForSync] = useState({}); // repo id -> true while a sync started elsewhere runs
+  const [discoveryCounts, setDiscoveryCounts] = useState({}); // repo id -> pending discovery changes
+  const [discoveryReviews, setDiscoveryReviews] = useState({}); // repo id -> { loading, changes, error }
+  const [securityAlerts, setSecurityAlerts] = useState({}); // repo id -> { loading, reports, error }
 
   // Load repositories from backend
   useEffect(() => {
     loadRepositories();
   }, []);
 
+  // Rediscovering every monorepo runs as a background job; reload once it's done
+  useEffect(() => {
+    if (!window.runtime?.EventsOn) return;
+    return window.runtime.EventsOn('job:finished', (job) => {
+      if (job?.kind === 'rediscover_services') {
+        loadRepositories();
+      }
+    });
+  }, []);
+
   const loadRepositories = async () => {
     try {
       const repos = await window.go.main.App.GetRepositories();
       setRepositories(repos || []);
+      setAccessReport(await window.go.main.App.GetAccessReport());
+      const statuses = await window.go.main.App.GetSyncStatus();
+      setSyncStatus(Object.fromEntries((statuses || []).map(status => [status.repository_id, status])));
+      setDiscoveryCounts((await window.go.main.App.GetPendingDiscoveryChangeCounts()) || {});
     } catch (error) {
       console.error('Failed to load repositories:', error);
     }
   };
 
+  const accessStateLabels = {
+    token_missing: 'Token missing or rejected',
+    forbidden: 'Forbidden',
+    not_found: 'Not found',
+  };
+
   const handleRepositoryCreated = async () => {
     await loadRepositories(); // Refresh the list
     setShowAddModal(false);
@@ -64,6 +104,327 @@ const Repositories = () => {
     }
   };
 
+  const handleRediscoverAll = async () => {
+    try {
+      await window.go.main.App.StartJob('rediscover_services', {});
+    } catch (error) {
+      console.error('Failed to start service rediscovery:', error);
+      alert(`Failed to start service rediscovery: ${error}`);
+    }
+  };
+
+  const handleDiagnoseScan = async (repo) => {
+    if (diagnostics[repo.id] && !diagnostics[repo.id].loading) {
+      // Toggle the panel off
+      setDiagnostics(prev => {
+        const next = { ...prev };
+        delete next[repo.id];
+        return next;
+      });
+      return;
+    }
+
+    setDiagnostics(prev => ({ ...prev, [repo.id]: { loading: true } }));
+    try {
+      const result = await window.go.main.App.DiagnoseDeploymentScan(repo.id);
+      setDiagnostics(prev => ({ ...prev, [repo.id]: { loading: false, result } }));
+    } catch (error) {
+      console.error('Failed to diagnose deployment scan:', error);
+      setDiagnostics(prev => ({ ...prev, [repo.id]: { loading: false, error: String(error) } }));
+    }
+  };
+
+  const handleToggleCommitImpactPanel = (repo) => {
+    setCommitImpacts(prev => {
+      const next = { ...prev };
+      if (next[repo.id]) {
+        delete next[repo.id];
+      } else {
+        next[repo.id] = { sha: '' };
+      }
+      return next;
+    });
+  };
+
+  const handleLoadCommitImpact = async (repo) => {
+    const sha = (commitImpacts[repo.id]?.sha || '').trim();
+    if (!sha) return;
+
+    setCommitImpacts(prev => ({ ...prev, [repo.id]: { ...prev[repo.id], loading: true, result: null, error: null } }));
+    try {
+      const result = await window.go.main.App.GetCommitImpact(repo.id, sha);
+      setCommitImpacts(prev => ({ ...prev, [repo.id]: { ...prev[repo.id], loading: false, result } }));
+    } catch (error) {
+      console.error('Failed to get commit impact:', error);
+      setCommitImpacts(prev => ({ ...prev, [repo.id]: { ...prev[repo.id], loading: false, error: String(error) } }));
+    }
+  };
+
+  const commitPositionLabel = (deployment) => {
+    switch (deployment.position) {
+      case 'at':
+        return 'at this commit';
+      case 'ahead':
+        return `includes it (+${deployment.ahead_by})`;
+      case 'behind':
+        return `behind by ${deployment.behind_by}`;
+      case 'diverged':
+        return 'diverged';
+      default:
+        return 'unknown';
+    }
+  };
+
+  const loadWebhookStatus = async (repoId) => {
+    setWebhooks(prev => ({ ...prev, [repoId]: { ...prev[repoId], loading: true, error: null } }));
+    try {
+      const status = await window.go.main.App.GetWebhookStatus(repoId);
+      setWebhooks(prev => ({ ...prev, [repoId]: { ...prev[repoId], loading: false, status } }));
+    } catch (error) {
+      console.error('Failed to get webhook status:', error);
+      setWebhooks(prev => ({ ...prev, [repoId]: { ...prev[repoId], loading: false, error: String(error) } }));
+    }
+  };
+
+  const handleToggleWebhookPanel = (repo) => {
+    if (webhooks[repo.id] && !webhooks[repo.id].loading) {
+      setWebhooks(prev => {
+        const next = { ...prev };
+        delete next[repo.id];
+        return next;
+      });
+      return;
+    }
+    loadWebhookStatus(repo.id);
+  };
+
+  const handleInstallWebhook = async (repo) => {
+    const targetURL = (webhooks[repo.id]?.targetURL || '').trim();
+    if (!targetURL) {
+      alert('Enter the URL GitHub should deliver events to.');
+      return;
+    }
+    try {
+      await window.go.main.App.InstallRepositoryWebhook(repo.id, targetURL);
+      await loadWebhookStatus(repo.id);
+    } catch (error) {
+      console.error('Failed to install webhook:', error);
+      alert('Failed to install webhook: ' + error);
+    }
+  };
+
+  const handleRemoveWebhook = async (repo) => {
+    if (!window.confirm(`Remove the webhook from ${repo.name}?`)) {
+      return;
+    }
+    try {
+      await window.go.main.App.RemoveRepositoryWebhook(repo.id);
+      await loadWebhookStatus(repo.id);
+    } catch (error) {
+      console.error('Failed to remove webhook:', error);
+      alert('Failed to remove webhook: ' + error);
+    }
+  };
+
+  const handleFixRepositoryURL = async (repo) => {
+    const url = window.prompt(`New URL for ${repo.name}:`, repo.url);
+    if (!url || url.trim() === repo.url) {
+      return;
+    }
+    try {
+      await window.go.main.App.UpdateRepository({ ...repo, url: url.trim() });
+      if (await window.go.main.App.SyncRepository(repo.id) === 'already_running') {
+        await waitForSync(repo);
+      }
+    } catch (error) {
+      console.error('Failed to update repository URL:', error);
+      alert('Failed to sync with the new URL: ' + error);
+    }
+    await loadRepositories();
+  };
+
+  const handleSetArchived = async (repo, archived) => {
+    try {
+      await window.go.main.App.SetRepositoryArchived(repo.id, archived);
+      await loadRepositories();
+    } catch (error) {
+      console.error('Failed to update repository status:', error);
+      alert('Failed to update repository: ' + error);
+    }
+  };
+
+  const handleToggleFavorite = async (repo) => {
+    try {
+      await window.go.main.App.ToggleFavorite(repo.id);
+      await loadRepositories();
+      // The nav lists favorites too
+      window.dispatchEvent(new Event('favorites:changed'));
+    } catch (error) {
+      console.error('Failed to update repository favorite:', error);
+      alert('Failed to update repository: ' + error);
+    }
+  };
+
+  const handleSetManualSyncOnly = async (repo, manual) => {
+    try {
+      await window.go.main.App.SetRepositoryManualSyncOnly(repo.id, manual);
+      await loadRepositories();
+    } catch (error) {
+      console.error('Failed to update repository sync mode:', error);
+      alert('Failed to update repository: ' + error);
+    }
+  };
+
+  const handleSetDiscoveryReview = async (repo, review) => {
+    try {
+      await window.go.main.App.SetRepositoryDiscoveryReview(repo.id, review);
+      await loadRepositories();
+    } catch (error) {
+      console.error('Failed to update repository discovery mode:', error);
+      alert('Failed to update repository: ' + error);
+    }
+  };
+
+  const handleSetCollectPackages = async (repo, collect) => {
+    try {
+      await window.go.main.App.SetRepositoryCollectPackages(repo.id, collect);
+      await loadRepositories();
+    } catch (error) {
+      console.error('Failed to update repository package collection:', error);
+      alert('Failed to update repository: ' + error);
+    }
+  };
+
+  const handleSetWorkflowBranches = async (repo) => {
+    const branches = window.prompt(
+      `Branches whose workflow runs ${repo.name} records, comma-separated ({default} is the default branch, * every branch; empty for {default},release/*):`,
+      repo.workflow_branches
+    );
+    if (branches === null || branches.trim() === repo.workflow_branches) {
+      return;
+    }
+    try {
+      await window.go.main.App.SetRepositoryWorkflowBranches(repo.id, branches);
+      await loadRepositories();
+    } catch (error) {
+      console.error('Failed to update repository workflow branches:', error);
+      alert('Failed to update repository: ' + error);
+      return;
+    }
+    if (window.confirm(`Delete the workflow runs of ${repo.name} already recorded on other branches?`)) {
+      try {
+        await window.go.main.App.StartJob('prune_workflow_runs', { repository_id: repo.id });
+      } catch (error) {
+        console.error('Failed to start pruning workflow runs:', error);
+        alert('Failed to prune workflow runs: ' + error);
+      }
+    }
+  };
+
+  const loadDiscoveryChanges = async (repo) => {
+    setDiscoveryReviews((prev) => ({ ...prev, [repo.id]: { loading: true } }));
+    try {
+      const changes = await window.go.main.App.GetPendingDiscoveryChanges(repo.id);
+      setDiscoveryReviews((prev) => ({ ...prev, [repo.id]: { changes: changes || [] } }));
+    } catch (error) {
+      console.error('Failed to load discovery changes:', error);
+      setDiscoveryReviews((prev) => ({ ...prev, [repo.id]: { error: String(error) } }));
+    }
+  };
+
+  const handleToggleDiscoveryReviewPanel = (repo) => {
+    if (discoveryReviews[repo.id]) {
+      setDiscoveryReviews((prev) => {
+        const next = { ...prev };
+        delete next[repo.id];
+        return next;
+      });
+      return;
+    }
+    loadDiscoveryChanges(repo);
+  };
+
+  const handleToggleSecurityAlertsPanel = async (repo) => {
+    if (securityAlerts[repo.id]) {
+      setSecurityAlerts((prev) => {
+        const next = { ...prev };
+        delete next[repo.id];
+        return next;
+      });
+      return;
+    }
+    setSecurityAlerts((prev) => ({ ...prev, [repo.id]: { loading: true } }));
+    try {
+      const reports = await window.go.main.App.GetSecurityAlerts(repo.id);
+      setSecurityAlerts((prev) => ({ ...prev, [repo.id]: { reports: reports || [] } }));
+    } catch (error) {
+      console.error('Failed to load security alerts:', error);
+      setSecurityAlerts((prev) => ({ ...prev, [repo.id]: { error: String(error) } }));
+    }
+  };
+
+  const securityAlertSourceLabels = {
+    dependabot: 'Dependabot',
+    code_scanning: 'Code scanning',
+  };
+
+  const handleDecideDiscoveryChanges = async (repo, changes, accept) => {
+    try {
+      await window.go.main.App.ApplyDiscoveryChanges(repo.id, changes.map((change) => ({ id: change.id, accept })));
+      await loadDiscoveryChanges(repo);
+      await loadRepositories();
+    } catch (error) {
+      console.error('Failed to apply discovery changes:', error);
+      alert('Failed to apply discovery changes: ' + error);
+    }
+  };
+
+  const describeDiscoveryChange = (change) => {
+    switch (change.kind) {
+      case 'add':
+        return `Add ${change.name} at ${change.path}`;
+      case 'remove':
+        return `Remove ${change.name} (${change.path})`;
+      case 'rename':
+        return `Rename ${change.old_name} (${change.old_path}) to ${change.name} (${change.path})`;
+      default:
+        return change.name;
+    }
+  };
+
+  // A sync that was already running, e.g. the scheduled pass, isn't started again; show it as
+  // running until it's done instead
+  const waitForSync = async (repo) => {
+    setWaitingForSync(prev => ({ ...prev, [repo.id]: true }));
+    try {
+      for (;;) {
+        await new Promise(resolve => setTimeout(resolve, 2000));
+        const statuses = await window.go.main.App.GetSyncStatus();
+        if (!(statuses || []).some(status => status.repository_id === repo.id && status.running)) {
+          break;
+        }
+      }
+    } finally {
+      setWaitingForSync(prev => {
+        const next = { ...prev };
+        delete next[repo.id];
+        return next;
+      });
+    }
+  };
+
+  const handleSyncNow = async (repo) => {
+    try {
+      if (await window.go.main.App.SyncRepository(repo.id) === 'already_running') {
+        await waitForSync(repo);
+      }
+    } catch (error) {
+      console.error('Failed to sync repository:', error);
+      alert('Failed to sync repository: ' + error);
+    }
+    await loadRepositories();
+  };
+
   const handleDeleteRepository = async (id) => {
     if (window.confirm('Are you sure you want to delete this repository?')) {
       try {
@@ -103,13 +464,23 @@ const Repositories = () => {
             Manage your monorepo and Kubernetes resource repositories
           </p>
         </div>
-        <button
-          onClick={() => setShowAddModal(true)}
-          className="btn-primary flex items-center"
-        >
-          <Plus className="h-5 w-5 mr-2" />
-          Add Repository
-        </button>
+        <div className="flex items-center gap-3">
+          <button
+            onClick={handleRediscoverAll}
+            className="btn-secondary flex items-center"
+            title="Rediscover the services of every monorepo in the background"
+          >
+            <RefreshCw className="h-5 w-5 mr-2" />
+            Rediscover All
+          </button>
+          <button
+            onClick={() => setShowAddModal(true)}
+            className="btn-primary flex items-center"
+          >
+            <Plus className="h-5 w-5 mr-2" />
+            Add Repository
+          </button>
+        </div>
       </div>
 
       {/* Add Repository Modal */}
@@ -120,6 +491,47 @@ const Repositories = () => {
         />
       )}
 
+      {/* Access audit: repositories the token can no longer see */}
+      {accessReport && accessReport.repositories.some(entry => entry.state !== 'ok') && (
+        <div className="card mb-6">
+          <h2 className="text-lg font-semibold text-gray-900 flex items-center mb-2">
+            <AlertTriangle className="h-5 w-5 mr-2 text-red-600" />
+            Repository access
+          </h2>
+          <p className="text-sm text-gray-600 mb-3">
+            {Object.entries(accessStateLabels)
+              .filter(([state]) => accessReport.counts[state])
+              .map(([state, label]) => `${label}: ${accessReport.counts[state]}`)
+              .join(' · ')}
+            {accessReport.counts.ok ? ` · OK: ${accessReport.counts.ok}` : ''}
+          </p>
+          <table className="min-w-full text-sm">
+            <thead>
+              <tr className="text-left text-gray-500">
+                <th className="py-1 pr-4 font-medium">Repository</th>
+                <th className="py-1 pr-4 font-medium">State</th>
+                <th className="py-1 pr-4 font-medium">Checked</th>
+                <th className="py-1 pr-4 font-medium">Next retry</th>
+                <th className="py-1 pr-4 font-medium">Last successful sync</th>
+              </tr>
+            </thead>
+            <tbody>
+              {accessReport.repositories.filter(entry => entry.state !== 'ok').map(entry => (
+                <tr key={entry.repository_id} className="border-t border-gray-100" title={entry.reason}>
+                  <td className="py-1 pr-4 text-gray-900">{entry.name}</td>
+                  <td className="py-1 pr-4 text-red-700">
+                    {accessStateLabels[entry.state]}{entry.http_status ? ` (${entry.http_status})` : ''}
+                  </td>
+                  <td className="py-1 pr-4 text-gray-600">{entry.checked_at ? new Date(entry.checked_at).toLocaleString() : '—'}</td>
+                  <td className="py-1 pr-4 text-gray-600">{entry.retry_at ? new Date(entry.retry_at).toLocaleString() : '—'}</td>
+                  <td className="py-1 pr-4 text-gray-600">{entry.last_sync_at ? new Date(entry.last_sync_at).toLocaleString() : 'Never'}</td>
+                </tr>
+              ))}
+            </tbody>
+          </table>
+        </div>
+      )}
+
       {/* Repositories List */}
       <div className="grid gap-6">
         {repositories.map((repo) => (
@@ -135,6 +547,45 @@ const Repositories = () => {
                     <span className={`inline-flex items-center px-2.5 py-0.5 rounded-full text-xs font-medium ${getTypeColor(repo.type)}`}>
                       {repo.type === 'monorepo' ? 'Monorepo' : 'Kubernetes'}
                     </span>
+                    {repo.status === 'unreachable' && (
+                      <span className="ml-2 inline-flex items-center px-2.5 py-0.5 rounded-full text-xs font-medium bg-red-100 text-red-800">
+                        Unreachable
+                      </span>
+                    )}
+                    {repo.status === 'archived' && (
+                      <span className="ml-2 inline-flex items-center px-2.5 py-0.5 rounded-full text-xs font-medium bg-gray-100 text-gray-700">
+                        Archived
+                      </span>
+                    )}
+                    {repo.manual_sync_only && (
+                      <span className="ml-2 inline-flex items-center px-2.5 py-0.5 rounded-full text-xs font-medium bg-amber-100 text-amber-800">
+                        Manual sync
+                      </span>
+                    )}
+                    {discoveryCounts[repo.id] > 0 && (
+                      <button
+                        onClick={() => handleToggleDiscoveryReviewPanel(repo)}
+                        className="ml-2 inline-flex items-center px-2.5 py-0.5 rounded-full text-xs font-medium bg-blue-100 text-blue-800 hover:bg-blue-200"
+                      >
+                        {discoveryCounts[repo.id]} service {discoveryCounts[repo.id] === 1 ? 'change' : 'changes'} to review
+                      </button>
+                    )}
+                    {repo.security_alerts && (
+                      <button
+                        onClick={() => handleToggleSecurityAlertsPanel(repo)}
+                        className={`ml-2 inline-flex items-center px-2.5 py-0.5 rounded-full text-xs font-medium ${
+                          repo.security_alerts.critical + repo.security_alerts.high > 0
+                            ? 'bg-red-100 text-red-800 hover:bg-red-200'
+                            : 'bg-gray-100 text-gray-700 hover:bg-gray-200'
+                        }`}
+                        title={`Dependabot: ${repo.security_alerts.dependabot_unavailable ? 'unavailable' : repo.security_alerts.dependabot}, code scanning: ${repo.security_alerts.code_scanning_unavailable ? 'unavailable' : repo.security_alerts.code_scanning}`}
+                      >
+                        <ShieldAlert className="h-3 w-3 mr-1" />
+                        {repo.security_alerts.dependabot_unavailable && repo.security_alerts.code_scanning_unavailable
+                          ? 'Alerts unavailable'
+                          : `${repo.security_alerts.dependabot + repo.security_alerts.code_scanning} security alerts`}
+                      </button>
+                    )}
                   </div>
                 </div>
                 
@@ -149,8 +600,22 @@ const Repositories = () => {
                   </div>
                   <div className="flex items-center">
                     <Clock className="h-4 w-4 mr-1" />
-                    Last sync: {repo.lastSyncAt ? formatDate(repo.lastSyncAt) : 'Never'}
+                    Last sync: {repo.last_sync_at ? formatDate(repo.last_sync_at) : 'Never'}
                   </div>
+                  {syncStatus[repo.id]?.running && (
+                    <div className="text-blue-600">Syncing {syncStatus[repo.id].phase}…</div>
+                  )}
+                  {waitingForSync[repo.id] && !syncStatus[repo.id]?.running && (
+                    <div className="flex items-center text-blue-600">
+                      <RefreshCw className="h-4 w-4 mr-1 animate-spin" />
+                      Already syncing…
+                    </div>
+                  )}
+                  {syncStatus[repo.id]?.interrupted && (
+                    <div className="text-amber-600" title="The next sync skips the phases already completed">
+                      Sync interrupted during {syncStatus[repo.id].phase}
+                    </div>
+                  )}
                   {repo.servicesCount && (
                     <div>
                       {repo.servicesCount} services
@@ -165,6 +630,70 @@ const Repositories = () => {
               </div>
               
               <div className="flex items-center space-x-2">
+                {repo.type === 'kubernetes' && (
+                  <button
+                    onClick={() => handleDiagnoseScan(repo)}
+                    className="p-2 text-gray-400 hover:text-purple-600 rounded-md hover:bg-gray-100"
+                    title="Diagnose Deployment Scan"
+                  >
+                    <Stethoscope className="h-5 w-5" />
+                  </button>
+                )}
+                {repo.type === 'monorepo' && (
+                  <button
+                    onClick={() => handleToggleCommitImpactPanel(repo)}
+                    className="p-2 text-gray-400 hover:text-orange-600 rounded-md hover:bg-gray-100"
+                    title="Commit Impact"
+                  >
+                    <GitCommit className="h-5 w-5" />
+                  </button>
+                )}
+                <button
+                  onClick={() => handleToggleWebhookPanel(repo)}
+                  className="p-2 text-gray-400 hover:text-green-600 rounded-md hover:bg-gray-100"
+                  title="Webhook"
+                >
+                  <Webhook className="h-5 w-5" />
+                </button>
+                <button
+                  onClick={() => handleToggleFavorite(repo)}
+                  className={`p-2 rounded-md hover:bg-gray-100 ${repo.is_favorite ? 'text-yellow-500' : 'text-gray-400 hover:text-yellow-500'}`}
+                  title={repo.is_favorite ? 'Remove from favorites' : 'Add to favorites'}
+                >
+                  <Star className="h-5 w-5" fill={repo.is_favorite ? 'currentColor' : 'none'} />
+                </button>
+                <button
+                  onClick={() => handleSetManualSyncOnly(repo, !repo.manual_sync_only)}
+                  className={`p-2 rounded-md hover:bg-gray-100 ${repo.manual_sync_only ? 'text-amber-600' : 'text-gray-400 hover:text-amber-600'}`}
+                  title={repo.manual_sync_only ? 'Include in scheduled syncs' : 'Sync on demand only'}
+                >
+                  <Hand className="h-5 w-5" />
+                </button>
+                {repo.type === 'monorepo' && (
+                  <button
+                    onClick={() => handleSetDiscoveryReview(repo, !repo.discovery_review)}
+                    className={`p-2 rounded-md hover:bg-gray-100 ${repo.discovery_review ? 'text-blue-600' : 'text-gray-400 hover:text-blue-600'}`}
+                    title={repo.discovery_review ? 'Apply discovered service changes directly' : 'Review discovered service changes before applying them'}
+                  >
+                    <ListChecks className="h-5 w-5" />
+                  </button>
+                )}
+                {repo.type === 'monorepo' && (
+                  <button
+                    onClick={() => handleSetCollectPackages(repo, !repo.collect_packages)}
+                    className={`p-2 rounded-md hover:bg-gray-100 ${repo.collect_packages ? 'text-indigo-600' : 'text-gray-400 hover:text-indigo-600'}`}
+                    title={repo.collect_packages ? "Stop reading services' packages" : "Read services' packages from go.mod and package.json"}
+                  >
+                    <Boxes className="h-5 w-5" />
+                  </button>
+                )}
+                <button
+                  onClick={() => handleSetWorkflowBranches(repo)}
+                  className={`p-2 rounded-md hover:bg-gray-100 ${repo.workflow_branches ? 'text-teal-600' : 'text-gray-400 hover:text-teal-600'}`}
+                  title={`Workflow runs recorded from: ${repo.workflow_branches || '{default},release/*'}`}
+                >
+                  <GitBranch className="h-5 w-5" />
+                </button>
                 <button 
                   onClick={() => handleRediscoverServices(repo)}
                   className="p-2 text-gray-400 hover:text-blue-600 rounded-md hover:bg-gray-100"
@@ -186,6 +715,323 @@ const Repositories = () => {
                 </button>
               </div>
             </div>
+
+            {repo.status === 'unreachable' && (
+              <div className="mt-4 p-3 bg-red-50 border border-red-200 rounded-md flex items-center justify-between">
+                <div className="flex items-center text-sm text-red-800">
+                  <AlertTriangle className="h-4 w-4 mr-2" />
+                  GitHub keeps returning 404 for this repository, so scheduled syncs are paused. Was it deleted, or did the token lose access?
+                </div>
+                <div className="flex space-x-2">
+                  <button onClick={() => handleFixRepositoryURL(repo)} className="btn-secondary">
+                    Fix URL
+                  </button>
+                  <button onClick={() => handleSetArchived(repo, true)} className="btn-secondary flex items-center">
+                    <Archive className="h-4 w-4 mr-1" />
+                    Archive
+                  </button>
+                </div>
+              </div>
+            )}
+
+            {repo.last_sync_error && repo.status === 'active' && (
+              <div className="mt-4 p-3 bg-red-50 border border-red-200 rounded-md flex items-center text-sm text-red-800">
+                <AlertTriangle className="h-4 w-4 mr-2" />
+                Sync skipped: {repo.last_sync_error}
+              </div>
+            )}
+
+            {repo.manual_sync_only && repo.status === 'active' && (
+              <div className="mt-4 flex items-center justify-between text-sm text-gray-600">
+                <span>Scheduled syncs skip this repository; it's only synced on demand.</span>
+                <button
+                  onClick={() => handleSyncNow(repo)}
+                  disabled={waitingForSync[repo.id]}
+                  className="btn-secondary flex items-center"
+                >
+                  <RefreshCw className={`h-4 w-4 mr-1 ${waitingForSync[repo.id] ? 'animate-spin' : ''}`} />
+                  Sync now
+                </button>
+              </div>
+            )}
+
+            {repo.discovery_review && !discoveryReviews[repo.id] && (
+              <div className="mt-4 flex items-center justify-between text-sm text-gray-600">
+                <span>Services discovery adds, removes or renames wait for review.</span>
+                <button onClick={() => handleToggleDiscoveryReviewPanel(repo)} className="btn-secondary flex items-center">
+                  <ListChecks className="h-4 w-4 mr-1" />
+                  Review changes
+                </button>
+              </div>
+            )}
+
+            {securityAlerts[repo.id] && (
+              <div className="mt-4 border-t border-gray-200 pt-4">
+                {securityAlerts[repo.id].loading && (
+                  <p className="text-sm text-gray-500">Loading security alerts...</p>
+                )}
+                {securityAlerts[repo.id].error && (
+                  <p className="text-sm text-red-600">{securityAlerts[repo.id].error}</p>
+                )}
+                {securityAlerts[repo.id].reports && securityAlerts[repo.id].reports.length === 0 && (
+                  <p className="text-sm text-gray-500">Security alerts haven't been checked yet.</p>
+                )}
+                {securityAlerts[repo.id].reports && securityAlerts[repo.id].reports.map((report) => (
+                  <div key={report.source} className="mb-3">
+                    <p className="text-sm text-gray-700 mb-1">
+                      <span className="font-medium">{securityAlertSourceLabels[report.source] || report.source}</span>
+                      {report.available ? (
+                        <span className="ml-2 text-gray-500">
+                          {report.critical} critical, {report.high} high, {report.medium} medium, {report.low} low
+                        </span>
+                      ) : (
+                        <span className="ml-2 text-gray-500" title={report.detail}>unavailable</span>
+                      )}
+                      <span className="ml-2 text-xs text-gray-400">checked {formatDate(report.checked_at)}</span>
+                    </p>
+                    {report.top_alerts.length > 0 && (
+                      <ul className="text-sm divide-y divide-gray-100">
+                        {report.top_alerts.map((alert) => (
+                          <li key={alert.number} className="py-1 flex items-center justify-between">
+                            <span className="text-gray-900">
+                              <span className={`mr-2 text-xs font-medium uppercase ${alert.severity === 'critical' || alert.severity === 'high' ? 'text-red-700' : 'text-gray-500'}`}>
+                                {alert.severity}
+                              </span>
+                              {alert.summary}
+                              {alert.location && <span className="ml-2 text-xs text-gray-500 font-mono">{alert.location}</span>}
+                            </span>
+                            {alert.url && (
+                              <a href={alert.url} target="_blank" rel="noopener noreferrer" className="text-gray-400 hover:text-blue-600">
+                                <ExternalLink className="h-4 w-4" />
+                              </a>
+                            )}
+                          </li>
+                        ))}
+                      </ul>
+                    )}
+                  </div>
+                ))}
+              </div>
+            )}
+
+            {discoveryReviews[repo.id] && (
+              <div className="mt-4 border-t border-gray-200 pt-4">
+                {discoveryReviews[repo.id].loading && (
+                  <p className="text-sm text-gray-500">Loading service changes...</p>
+                )}
+                {discoveryReviews[repo.id].error && (
+                  <p className="text-sm text-red-600">{discoveryReviews[repo.id].error}</p>
+                )}
+                {discoveryReviews[repo.id].changes && discoveryReviews[repo.id].changes.length === 0 && (
+                  <p className="text-sm text-gray-500">No service changes waiting for review.</p>
+                )}
+                {discoveryReviews[repo.id].changes && discoveryReviews[repo.id].changes.length > 0 && (
+                  <div>
+                    <div className="flex items-center justify-between mb-2">
+                      <p className="text-sm text-gray-700">Service changes found by discovery</p>
+                      <button
+                        onClick={() => handleDecideDiscoveryChanges(repo, discoveryReviews[repo.id].changes.filter((change) => change.status === 'pending'), true)}
+                        className="btn-secondary text-xs"
+                      >
+                        Accept all pending
+                      </button>
+                    </div>
+                    <ul className="divide-y divide-gray-100">
+                      {discoveryReviews[repo.id].changes.map((change) => (
+                        <li key={change.id} className="py-2 flex items-center justify-between text-sm">
+                          <div className={change.status === 'pending' ? 'text-gray-900' : 'text-gray-400'}>
+                            {describeDiscoveryChange(change)}
+                            {change.status !== 'pending' && <span className="ml-2 text-xs">({change.status})</span>}
+                            {change.expires_at && (
+                              <span className="ml-2 text-xs text-gray-500">until {formatDate(change.expires_at)}</span>
+                            )}
+                          </div>
+                          <div className="flex space-x-2">
+                            <button
+                              onClick={() => handleDecideDiscoveryChanges(repo, [change], true)}
+                              className="text-xs text-green-700 hover:text-green-900"
+                            >
+                              Accept
+                            </button>
+                            {change.status === 'pending' && (
+                              <button
+                                onClick={() => handleDecideDiscoveryChanges(repo, [change], false)}
+                                className="text-xs text-red-600 hover:text-red-800"
+                              >
+                                Reject
+                              </button>
+                            )}
+                          </div>
+                        </li>
+                      ))}
+                    </ul>
+                  </div>
+                )}
+              </div>
+            )}
+
+            {repo.status === 'archived' && (
+              <div className="mt-4 flex items-center justify-between text-sm text-gray-600">
+                <span>This repository is archived and isn't synced.</span>
+                <button onClick={() => handleSetArchived(repo, false)} className="btn-secondary">
+                  Unarchive
+                </button>
+              </div>
+            )}
+
+            {diagnostics[repo.id] && (
+              <div className="mt-4 border-t border-gray-200 pt-4">
+                {diagnostics[repo.id].loading && (
+                  <p className="text-sm text-gray-500">Scanning kustomization files...</p>
+                )}
+                {diagnostics[repo.id].error && (
+                  <p className="text-sm text-red-600">{diagnostics[repo.id].error}</p>
+                )}
+                {diagnostics[repo.id].result && (
+                  <div>
+                    <p className="text-sm text-gray-700 mb-2">
+                      Scanned <code>{diagnostics[repo.id].result.scan_path}</code>: {diagnostics[repo.id].result.files_found} files found, {diagnostics[repo.id].result.files_matched} matched a service
+                    </p>
+                    <div className="max-h-80 overflow-y-auto">
+                      <table className="min-w-full text-xs">
+                        <thead>
+                          <tr className="text-left text-gray-500">
+                            <th className="py-1 pr-4">File</th>
+                            <th className="py-1 pr-4">Outcome</th>
+                            <th className="py-1">Details</th>
+                          </tr>
+                        </thead>
+                        <tbody>
+                          {diagnostics[repo.id].result.files.map((file) => (
+                            <tr key={file.path} className="border-t border-gray-100 align-top">
+                              <td className="py-1 pr-4 font-mono text-gray-700">{file.path}</td>
+                              <td className={`py-1 pr-4 ${file.outcome === 'matched' ? 'text-green-600' : 'text-yellow-700'}`}>
+                                {file.outcome === 'matched' ? 'matched' : file.skip_reason.replace(/_/g, ' ')}
+                              </td>
+                              <td className="py-1 text-gray-600">
+                                {file.outcome === 'matched'
+                                  ? `${file.matched_service_name} ${file.environment}/${file.region}/${(file.namespaces || []).join(', ')} tag ${file.tag}${file.source ? ` (from ${file.source})` : ''}${file.annotated_commit ? `, commit ${file.annotated_commit.slice(0, 7)} (from ${file.commit_annotation})` : ''}`
+                                  : file.detail}
+                              </td>
+                            </tr>
+                          ))}
+                        </tbody>
+                      </table>
+                    </div>
+                  </div>
+                )}
+              </div>
+            )}
+
+            {commitImpacts[repo.id] && (
+              <div className="mt-4 border-t border-gray-200 pt-4 text-sm">
+                <div className="flex items-center space-x-2">
+                  <input
+                    type="text"
+                    placeholder="Commit SHA"
+                    value={commitImpacts[repo.id].sha}
+                    onChange={(e) => {
+                      const sha = e.target.value;
+                      setCommitImpacts(prev => ({ ...prev, [repo.id]: { ...prev[repo.id], sha } }));
+                    }}
+                    onKeyDown={(e) => e.key === 'Enter' && handleLoadCommitImpact(repo)}
+                    className="flex-1 px-3 py-2 border border-gray-300 rounded-md font-mono"
+                  />
+                  <button onClick={() => handleLoadCommitImpact(repo)} className="btn-primary">
+                    Show Impact
+                  </button>
+                </div>
+                {commitImpacts[repo.id].loading && (
+                  <p className="mt-2 text-gray-500">Comparing deployments with the commit...</p>
+                )}
+                {commitImpacts[repo.id].error && (
+                  <p className="mt-2 text-red-600">{commitImpacts[repo.id].error}</p>
+                )}
+                {commitImpacts[repo.id].result && (
+                  <div className="mt-3">
+                    <p className="text-gray-700 mb-2">
+                      <span className="font-mono">{commitImpacts[repo.id].result.commit.hash.substring(0, 7)}</span>{' '}
+                      {commitImpacts[repo.id].result.commit.message.split('\n')[0]} ({commitImpacts[repo.id].result.files_changed} files, {commitImpacts[repo.id].result.services.length} services changed)
+                    </p>
+                    {commitImpacts[repo.id].result.services.map((service) => (
+                      <div key={service.service_id} className="mb-3">
+                        <Link to={`/service/${service.service_id}`} className="font-medium text-blue-600 hover:text-blue-800">
+                          {service.service_name}
+                        </Link>
+                        <span className="text-gray-500"> • {service.files.length} files</span>
+                        {service.deployments.length === 0 ? (
+                          <p className="text-xs text-gray-500">Not deployed anywhere</p>
+                        ) : (
+                          <table className="min-w-full text-xs mt-1">
+                            <tbody>
+                              {service.deployments.map((deployment) => (
+                                <tr key={`${deployment.environment}/${deployment.region}/${deployment.namespace}`} className="border-t border-gray-100">
+                                  <td className="py-1 pr-4 text-gray-700">{deployment.environment}/{deployment.region}/{deployment.namespace}</td>
+                                  <td className="py-1 pr-4 font-mono text-gray-600">{deployment.tag}</td>
+                                  <td className={`py-1 ${deployment.position === 'at' || deployment.position === 'ahead' ? 'text-green-600' : 'text-yellow-700'}`}>
+                                    {commitPositionLabel(deployment)}
+                                  </td>
+                                </tr>
+                              ))}
+                            </tbody>
+                          </table>
+                        )}
+                      </div>
+                    ))}
+                  </div>
+                )}
+              </div>
+            )}
+
+            {webhooks[repo.id] && (
+              <div className="mt-4 border-t border-gray-200 pt-4 text-sm">
+                {webhooks[repo.id].loading && (
+                  <p className="text-gray-500">Checking webhook...</p>
+                )}
+                {webhooks[repo.id].error && (
+                  <p className="text-red-600">{webhooks[repo.id].error}</p>
+                )}
+                {webhooks[repo.id].status && (webhooks[repo.id].status.hook_id ? (
+                  <div className="space-y-1">
+                    <p className="text-gray-700">
+                      Webhook {webhooks[repo.id].status.hook_id} → <code>{webhooks[repo.id].status.target_url}</code>
+                      {webhooks[repo.id].status.installed && !webhooks[repo.id].status.active && ' (inactive)'}
+                    </p>
+                    {webhooks[repo.id].status.installed && (
+                      <p className={webhooks[repo.id].status.failed_deliveries > 0 ? 'text-yellow-700' : 'text-gray-600'}>
+                        {webhooks[repo.id].status.recent_deliveries} recent deliveries, {webhooks[repo.id].status.failed_deliveries} failed
+                        {webhooks[repo.id].status.last_delivery_at && ` • last ${formatDate(webhooks[repo.id].status.last_delivery_at)} (${webhooks[repo.id].status.last_delivery_status})`}
+                      </p>
+                    )}
+                    {webhooks[repo.id].status.missing_on_github && (
+                      <p className="text-yellow-700">The webhook was deleted on GitHub. Remove it here and install it again.</p>
+                    )}
+                    {webhooks[repo.id].status.error && !webhooks[repo.id].status.missing_on_github && (
+                      <p className="text-red-600">{webhooks[repo.id].status.error}</p>
+                    )}
+                    <button onClick={() => handleRemoveWebhook(repo)} className="btn-secondary mt-2">
+                      Remove Webhook
+                    </button>
+                  </div>
+                ) : (
+                  <div className="flex items-center space-x-2">
+                    <input
+                      type="url"
+                      placeholder="https://example.com/github/webhook"
+                      value={webhooks[repo.id].targetURL || ''}
+                      onChange={(e) => {
+                        const targetURL = e.target.value;
+                        setWebhooks(prev => ({ ...prev, [repo.id]: { ...prev[repo.id], targetURL } }));
+                      }}
+                      className="flex-1 px-3 py-2 border border-gray-300 rounded-md"
+                    />
+                    <button onClick={() => handleInstallWebhook(repo)} className="btn-primary">
+                      Install Webhook
+                    </button>
+                  </div>
+                ))}
+              </div>
+            )}
           </div>
         ))}
         
@@ -2,6 +2,7 @@ import React, { useState, useEffect } from 'react';
 import { useParams } from 'react-router-dom';
 import { 
   GitCommit,
+  GitPullRequest,
   User,
   Calendar,
   Hash,
@@ -9,9 +10,74 @@ import {
   ExternalLink,
   Package,
   Search,
-  Filter
+  Filter,
+  MessageSquare,
+  Trash2,
+  FileDiff
 } from 'lucide-react';
 
+const patchLineClass = (line) => {
+  if (line.startsWith('@@')) return 'text-blue-700 bg-blue-50';
+  if (line.startsWith('+')) return 'text-green-800 bg-green-50';
+  if (line.startsWith('-')) return 'text-red-800 bg-red-50';
+  return 'text-gray-700';
+};
+
+// CommitDiff shows the files a commit changed inside the service path and their patches
+const CommitDiff = ({ state, onLoadFull }) => {
+  if (state.loading) {
+    return <div className="mt-3 text-xs text-gray-500">Loading changes...</div>;
+  }
+  if (state.error) {
+    return <div className="mt-3 text-xs text-red-600">Failed to load changes: {state.error}</div>;
+  }
+  const detail = state.data;
+  return (
+    <div className="mt-3 space-y-3">
+      <div className="text-xs text-gray-600">
+        {detail.files.length} file{detail.files.length !== 1 && 's'} changed in this service
+        <span className="ml-2 text-green-700">+{detail.additions}</span>
+        <span className="ml-1 text-red-700">-{detail.deletions}</span>
+        {detail.other_files > 0 && (
+          <span className="ml-2 text-gray-400">({detail.other_files} more outside the service path)</span>
+        )}
+      </div>
+      {detail.truncated && (
+        <div className="text-xs bg-amber-50 text-amber-900 rounded px-2 py-1">
+          This diff is large, some patches were left out.
+          <button onClick={onLoadFull} className="ml-2 underline hover:text-amber-700">Load the full diff</button>
+        </div>
+      )}
+      {detail.files.map(file => (
+        <div key={file.filename} className="border border-gray-200 rounded">
+          <div className="flex items-center justify-between px-2 py-1 bg-gray-50 text-xs">
+            <span className="font-mono text-gray-800 truncate">
+              {file.previous_filename && file.previous_filename !== file.filename && `${file.previous_filename} → `}
+              {file.filename}
+            </span>
+            <span className="ml-2 whitespace-nowrap">
+              <span className="text-gray-500 mr-2">{file.status}</span>
+              <span className="text-green-700">+{file.additions}</span>
+              <span className="ml-1 text-red-700">-{file.deletions}</span>
+            </span>
+          </div>
+          {file.patch ? (
+            <pre className="text-xs font-mono overflow-x-auto">
+              {file.patch.split('\n').map((line, i) => (
+                <div key={i} className={`px-2 ${patchLineClass(line)}`}>{line || ' '}</div>
+              ))}
+            </pre>
+          ) : (
+            <div className="px-2 py-1 text-xs text-gray-400">
+              {file.patch_truncated ? 'Patch left out of the truncated diff' : 'No patch available (binary or too large)'}
+            </div>
+          )}
+        </div>
+      ))}
+    </div>
+  );
+};
+
 const ServiceCommits = () => {
   const { serviceId } = useParams();
   const [service, setService] = useState(null);
@@ -19,6 +85,7 @@ const ServiceCommits = () => {
   const [loading, setLoading] = useState(true);
   const [searchTerm, setSearchTerm] = useState('');
   const [authorFilter, setAuthorFilter] = useState('all');
+  const [details, setDetails] = useState({});
 
   useEffect(() => {
     if (serviceId) {
@@ -30,7 +97,7 @@ const ServiceCommits = () => {
     setLoading(true);
     try {
       // Load service info
-      const allServices = await window.go.main.App.GetMicroservices(0);
+      const allServices = await window.go.main.App.GetMicroservices(0, true);
       const selectedService = allServices?.find(s => s.id === parseInt(serviceId));
       setService(selectedService || null);
 
@@ -52,6 +119,54 @@ const ServiceCommits = () => {
     }
   };
 
+  const loadDetail = async (commit, full) => {
+    setDetails(prev => ({ ...prev, [commit.hash]: { loading: true } }));
+    try {
+      const detail = await window.go.main.App.GetCommitDetail(parseInt(serviceId), commit.hash, full);
+      setDetails(prev => ({ ...prev, [commit.hash]: { data: detail } }));
+    } catch (error) {
+      console.error('Failed to load commit detail:', error);
+      setDetails(prev => ({ ...prev, [commit.hash]: { error: String(error) } }));
+    }
+  };
+
+  const toggleDetail = (commit) => {
+    if (details[commit.hash]) {
+      setDetails(prev => {
+        const next = { ...prev };
+        delete next[commit.hash];
+        return next;
+      });
+      return;
+    }
+    loadDetail(commit, false);
+  };
+
+  const annotateCommit = async (commit) => {
+    const text = window.prompt(`Note for commit ${formatCommitHash(commit.hash)}:`);
+    if (!text || !text.trim()) return;
+    try {
+      const annotation = await window.go.main.App.AddAnnotation('commit', commit.hash, text);
+      setCommits(commits.map(c => c.hash === commit.hash
+        ? { ...c, annotations: [annotation, ...(c.annotations || [])] }
+        : c));
+    } catch (error) {
+      alert(`Failed to add note: ${error}`);
+    }
+  };
+
+  const deleteAnnotation = async (commit, annotation) => {
+    if (!window.confirm('Delete this note?')) return;
+    try {
+      await window.go.main.App.DeleteAnnotation(annotation.id);
+      setCommits(commits.map(c => c.hash === commit.hash
+        ? { ...c, annotations: c.annotations.filter(a => a.id !== annotation.id) }
+        : c));
+    } catch (error) {
+      alert(`Failed to delete note: ${error}`);
+    }
+  };
+
   const formatDate = (dateString) => {
     return new Date(dateString).toLocaleDateString('en-US', {
       month: 'short',
@@ -189,7 +304,11 @@ const ServiceCommits = () => {
               <div className="flex-1 min-w-0">
                 <div className="flex items-start justify-between">
                   <div className="flex-1 min-w-0">
-                    <p className="text-sm font-medium text-gray-900 mb-1">
+                    <p
+                      onClick={() => toggleDetail(commit)}
+                      className="text-sm font-medium text-gray-900 mb-1 cursor-pointer hover:text-blue-700"
+                      title="Show what changed"
+                    >
                       {commit.message}
                     </p>
                     <div className="flex items-center space-x-4 text-xs text-gray-500">
@@ -197,6 +316,28 @@ const ServiceCommits = () => {
                         <Hash className="h-3 w-3 mr-1" />
                         <span className="font-mono">{formatCommitHash(commit.hash)}</span>
                       </div>
+                      {commit.pr_number > 0 && (
+                        <div className="flex items-center" title="Pull request that introduced this commit">
+                          <GitPullRequest className="h-3 w-3 mr-1" />
+                          <span>#{commit.pr_number}</span>
+                        </div>
+                      )}
+                      {commit.tickets?.map((ticket) => (
+                        ticket.url ? (
+                          <a
+                            key={ticket.key}
+                            href={ticket.url}
+                            target="_blank"
+                            rel="noopener noreferrer"
+                            className="font-mono text-blue-600 hover:underline"
+                            title="JIRA issue mentioned in the message"
+                          >
+                            {ticket.key}
+                          </a>
+                        ) : (
+                          <span key={ticket.key} className="font-mono" title="JIRA issue mentioned in the message">{ticket.key}</span>
+                        )
+                      ))}
                       <div className="flex items-center">
                         <User className="h-3 w-3 mr-1" />
                         <span>{commit.author}</span>
@@ -204,14 +345,54 @@ const ServiceCommits = () => {
                       <div className="flex items-center">
                         <Calendar className="h-3 w-3 mr-1" />
                         <span title={formatDate(commit.date)}>
-                          {getRelativeTime(commit.date)}
+                          {commit.date_relative || getRelativeTime(commit.date)}
                         </span>
                       </div>
+                      {commit.annotations?.length > 0 && (
+                        <div className="flex items-center text-amber-600" title="Notes on this commit">
+                          <MessageSquare className="h-3 w-3 mr-1" />
+                          <span>{commit.annotations.length}</span>
+                        </div>
+                      )}
                     </div>
+                    {commit.annotations?.length > 0 && (
+                      <ul className="mt-2 space-y-1">
+                        {commit.annotations.map(annotation => (
+                          <li key={annotation.id} className="flex items-start text-xs bg-amber-50 text-amber-900 rounded px-2 py-1">
+                            <span className="flex-1 whitespace-pre-wrap">{annotation.text}</span>
+                            <span className="ml-2 text-amber-700 whitespace-nowrap">{annotation.created_at_relative}</span>
+                            <button
+                              onClick={() => deleteAnnotation(commit, annotation)}
+                              className="ml-2 text-amber-700 hover:text-red-600"
+                              title="Delete note"
+                            >
+                              <Trash2 className="h-3 w-3" />
+                            </button>
+                          </li>
+                        ))}
+                      </ul>
+                    )}
+                    {details[commit.hash] && (
+                      <CommitDiff state={details[commit.hash]} onLoadFull={() => loadDetail(commit, true)} />
+                    )}
                   </div>
                   
                   {/* Commit Actions */}
-                  <div className="flex-shrink-0 ml-4">
+                  <div className="flex-shrink-0 ml-4 flex space-x-2">
+                    <button
+                      onClick={() => toggleDetail(commit)}
+                      className="btn-secondary text-xs p-2"
+                      title="Show what changed"
+                    >
+                      <FileDiff className="h-3 w-3" />
+                    </button>
+                    <button
+                      onClick={() => annotateCommit(commit)}
+                      className="btn-secondary text-xs p-2"
+                      title="Add a note"
+                    >
+                      <MessageSquare className="h-3 w-3" />
+                    </button>
                     <button className="btn-secondary text-xs p-2">
                       <ExternalLink className="h-3 w-3" />
                     </button>
@@ -10,7 +10,12 @@ import {
   ExternalLink,
   Clock,
   Search,
-  Filter
+  Filter,
+  MessageSquare,
+  Trash2,
+  AlertTriangle,
+  ShieldCheck,
+  Snowflake
 } from 'lucide-react';
 
 const ServiceDeploymentHistory = () => {
@@ -18,9 +23,11 @@ const ServiceDeploymentHistory = () => {
   const [service, setService] = useState(null);
   const [commits, setCommits] = useState([]);
   const [deployments, setDeployments] = useState([]);
+  const [events, setEvents] = useState([]);
   const [loading, setLoading] = useState(true);
   const [searchTerm, setSearchTerm] = useState('');
   const [authorFilter, setAuthorFilter] = useState('all');
+  const [commitChecks, setCommitChecks] = useState({}); // sha -> checks, or { error }
 
   useEffect(() => {
     if (serviceId) {
@@ -32,24 +39,30 @@ const ServiceDeploymentHistory = () => {
     setLoading(true);
     try {
       // Load service info
-      const allServices = await window.go.main.App.GetMicroservices(0);
+      const allServices = await window.go.main.App.GetMicroservices(0, true);
       const selectedService = allServices?.find(s => s.id === parseInt(serviceId));
       setService(selectedService || null);
 
       if (selectedService) {
         // Load both deployment history and current deployments
         try {
-          const [historyCommits, serviceDeployments] = await Promise.all([
+          const [historyCommits, serviceDeployments, deploymentEvents] = await Promise.all([
             window.go.main.App.GetServiceDeploymentHistory(parseInt(serviceId)),
-            window.go.main.App.GetServiceDeployments(parseInt(serviceId))
+            window.go.main.App.GetServiceDeployments(parseInt(serviceId)),
+            window.go.main.App.GetServiceDeploymentEvents(parseInt(serviceId))
           ]);
           
           setCommits(historyCommits || []);
           setDeployments(serviceDeployments || []);
+          setEvents(deploymentEvents || []);
+          setCommitChecks(Object.fromEntries(
+            (serviceDeployments || []).filter(d => d.checks).map(d => [d.commit_sha, d.checks])
+          ));
         } catch (error) {
           console.error('Failed to load deployment history:', error);
           setCommits([]);
           setDeployments([]);
+          setEvents([]);
         }
       }
     } catch (error) {
@@ -60,6 +73,60 @@ const ServiceDeploymentHistory = () => {
     }
   };
 
+  const annotateEvent = async (event) => {
+    const text = window.prompt(`Note for ${event.environment}/${event.region} ${event.tag}:`);
+    if (!text || !text.trim()) return;
+    try {
+      const annotation = await window.go.main.App.AddAnnotation('deployment_history', String(event.id), text);
+      setEvents(events.map(e => e.id === event.id
+        ? { ...e, annotations: [annotation, ...(e.annotations || [])] }
+        : e));
+    } catch (error) {
+      alert(`Failed to add note: ${error}`);
+    }
+  };
+
+  // Checks are looked up for current deployments only; older events ask for them on demand
+  const loadEventChecks = async (event) => {
+    try {
+      const checks = await window.go.main.App.GetCommitChecks(parseInt(serviceId), event.commit_sha);
+      setCommitChecks(previous => ({ ...previous, [event.commit_sha]: checks }));
+    } catch (error) {
+      setCommitChecks(previous => ({ ...previous, [event.commit_sha]: { error: String(error) } }));
+    }
+  };
+
+  const checksBadge = (checks) => {
+    if (checks.error) {
+      return <span className="text-xs text-gray-500" title={checks.error}>checks unavailable</span>;
+    }
+    const colors = {
+      success: 'bg-green-100 text-green-800',
+      failure: 'bg-red-100 text-red-800',
+      pending: 'bg-yellow-100 text-yellow-800',
+      none: 'bg-gray-100 text-gray-600',
+    };
+    const label = checks.state === 'none' ? 'no checks' : `checks ${checks.state}`;
+    const title = checks.failed_checks?.length ? `Failed: ${checks.failed_checks.join(', ')}` : `${checks.succeeded} of ${checks.total} passed`;
+    return (
+      <span className={`inline-flex items-center px-2 py-0.5 text-xs font-medium rounded-full ${colors[checks.state] || colors.none}`} title={title}>
+        {label}
+      </span>
+    );
+  };
+
+  const deleteEventAnnotation = async (event, annotation) => {
+    if (!window.confirm('Delete this note?')) return;
+    try {
+      await window.go.main.App.DeleteAnnotation(annotation.id);
+      setEvents(events.map(e => e.id === event.id
+        ? { ...e, annotations: e.annotations.filter(a => a.id !== annotation.id) }
+        : e));
+    } catch (error) {
+      alert(`Failed to delete note: ${error}`);
+    }
+  };
+
   const formatDate = (dateString) => {
     return new Date(dateString).toLocaleDateString('en-US', {
       month: 'short',
@@ -177,6 +244,98 @@ const ServiceDeploymentHistory = () => {
         </div>
       </div>
 
+      {/* Current deployments running a commit whose checks failed or hadn't finished */}
+      {deployments.some(deployment => deployment.checks_not_green) && (
+        <div className="mb-8 p-4 bg-red-50 border border-red-200 rounded-lg">
+          <div className="flex items-center mb-2">
+            <AlertTriangle className="h-5 w-5 text-red-600 mr-2" />
+            <h3 className="text-sm font-semibold text-red-900">Deployed commits that weren't green</h3>
+          </div>
+          <ul className="space-y-1 text-sm text-red-800">
+            {deployments.filter(deployment => deployment.checks_not_green).map(deployment => (
+              <li key={deployment.id} className="flex items-center space-x-2">
+                <strong>{deployment.environment} / {deployment.region}</strong>
+                <span className="font-mono">{formatCommitHash(deployment.commit_sha)}</span>
+                {checksBadge(deployment.checks)}
+                {deployment.checks.failed_checks?.length > 0 && (
+                  <span className="text-xs">failed: {deployment.checks.failed_checks.join(', ')}</span>
+                )}
+              </li>
+            ))}
+          </ul>
+        </div>
+      )}
+
+      {/* Deployment Events */}
+      {events.length > 0 && (
+        <div className="card mb-8">
+          <h2 className="text-lg font-semibold text-gray-900 mb-4">Deployment Events</h2>
+          <div className="divide-y divide-gray-100">
+            {events.map(event => (
+              <div key={event.id} className="py-3">
+                <div className="flex items-center justify-between">
+                  <div className="flex items-center space-x-3 text-sm">
+                    <span className={`inline-flex items-center px-2 py-1 text-xs font-medium rounded-full border ${getEnvironmentColor(event.environment)}`}>
+                      {event.environment} • {event.region}
+                    </span>
+                    <span className="font-medium text-gray-900">{event.tag}</span>
+                    <span className="font-mono text-xs text-gray-500">{formatCommitHash(event.commit_sha)}</span>
+                    <span className="text-xs text-gray-500" title={formatDate(event.observed_at)}>
+                      {getRelativeTime(event.observed_at)}
+                    </span>
+                    {commitChecks[event.commit_sha] && checksBadge(commitChecks[event.commit_sha])}
+                    {event.freeze_window_id && (
+                      <span
+                        className="inline-flex items-center px-2 py-0.5 text-xs font-medium rounded-full bg-sky-100 text-sky-800"
+                        title="Recorded inside a change freeze window"
+                      >
+                        <Snowflake className="h-3 w-3 mr-1" />
+                        During freeze
+                      </span>
+                    )}
+                  </div>
+                  <div className="flex items-center space-x-2">
+                    {event.commit_sha && !commitChecks[event.commit_sha] && (
+                      <button
+                        onClick={() => loadEventChecks(event)}
+                        className="btn-secondary text-xs p-2"
+                        title="Look up the commit's checks"
+                      >
+                        <ShieldCheck className="h-3 w-3" />
+                      </button>
+                    )}
+                    <button
+                      onClick={() => annotateEvent(event)}
+                      className="btn-secondary text-xs p-2"
+                      title="Add a note"
+                    >
+                      <MessageSquare className="h-3 w-3" />
+                    </button>
+                  </div>
+                </div>
+                {event.annotations?.length > 0 && (
+                  <ul className="mt-2 space-y-1">
+                    {event.annotations.map(annotation => (
+                      <li key={annotation.id} className="flex items-start text-xs bg-amber-50 text-amber-900 rounded px-2 py-1">
+                        <span className="flex-1 whitespace-pre-wrap">{annotation.text}</span>
+                        <span className="ml-2 text-amber-700 whitespace-nowrap">{annotation.created_at_relative}</span>
+                        <button
+                          onClick={() => deleteEventAnnotation(event, annotation)}
+                          className="ml-2 text-amber-700 hover:text-red-600"
+                          title="Delete note"
+                        >
+                          <Trash2 className="h-3 w-3" />
+                        </button>
+                      </li>
+                    ))}
+                  </ul>
+                )}
+              </div>
+            ))}
+          </div>
+        </div>
+      )}
+
       {/* Filters and Search */}
       <div className="flex flex-col sm:flex-row gap-4 mb-6">
         {/* Search */}
@@ -245,9 +404,18 @@ const ServiceDeploymentHistory = () => {
                         <div className="flex items-center">
                           <Calendar className="h-3 w-3 mr-1" />
                           <span title={formatDate(commit.date)}>
-                            {getRelativeTime(commit.date)}
+                            {commit.date_relative || getRelativeTime(commit.date)}
                           </span>
                         </div>
+                        {commit.annotations?.length > 0 && (
+                          <div
+                            className="flex items-center text-amber-600"
+                            title={commit.annotations.map(a => a.text).join('\n')}
+                          >
+                            <MessageSquare className="h-3 w-3 mr-1" />
+                            <span>{commit.annotations.length}</span>
+                          </div>
+                        )}
                       </div>
 
                       {/* Deployment Status */}
@@ -11,14 +11,42 @@ import {
   ExternalLink,
   RefreshCw,
   AlertCircle,
-  GitCommit
+  AlertTriangle,
+  GitCommit,
+  Layers,
+  FileDiff
 } from 'lucide-react';
+import useDataChanged from '../hooks/useDataChanged';
+import DeploymentsByEnvironment from '../components/DeploymentsByEnvironment';
+
+// Tag badge colors by how old the deployed commit is; the thresholds are the
+// deployment_stale_warn_days and deployment_stale_alert_days settings
+const stalenessClasses = {
+  fresh: 'bg-green-100 text-green-800',
+  aging: 'bg-yellow-100 text-yellow-800',
+  stale: 'bg-red-100 text-red-800',
+  unknown: 'bg-gray-100 text-gray-700',
+};
+
+const stalenessTitles = {
+  fresh: 'Recent commit',
+  aging: 'Commit getting old',
+  stale: 'Stale commit',
+  unknown: 'Commit age unknown',
+};
 
 const ServiceDeployments = () => {
   const { serviceId } = useParams();
   const [service, setService] = useState(null);
   const [commitDeployments, setCommitDeployments] = useState([]);
   const [uniqueDeploymentEnvs, setUniqueDeploymentEnvs] = useState([]);
+  const [rollups, setRollups] = useState([]);
+  const [groupNamespaces, setGroupNamespaces] = useState(true);
+  const [rollouts, setRollouts] = useState([]);
+  const [drifts, setDrifts] = useState([]);
+  const [blame, setBlame] = useState(null); // { target, loading, result, error }
+  const [envDiffEnvs, setEnvDiffEnvs] = useState({ a: '', b: '' });
+  const [envDiff, setEnvDiff] = useState(null); // { loading, result, error }
   const [loading, setLoading] = useState(true);
 
   useEffect(() => {
@@ -27,11 +55,14 @@ const ServiceDeployments = () => {
     }
   }, [serviceId]);
 
+  // Deployments are discovered in kubernetes repositories, so any repository may affect this service
+  useDataChanged(['deployments'], 0, () => loadServiceDeployments());
+
   const loadServiceDeployments = async () => {
     setLoading(true);
     try {
       // Load service info
-      const allServices = await window.go.main.App.GetMicroservices(0);
+      const allServices = await window.go.main.App.GetMicroservices(0, true);
       const selectedService = allServices?.find(s => s.id === parseInt(serviceId));
       setService(selectedService || null);
 
@@ -61,6 +92,30 @@ const ServiceDeployments = () => {
           setCommitDeployments([]);
           setUniqueDeploymentEnvs([]);
         }
+
+        try {
+          const deploymentRollups = await window.go.main.App.GetServiceDeploymentRollups(parseInt(serviceId));
+          setRollups(deploymentRollups || []);
+
+          // Only rollouts still in progress are shown
+          const environments = [...new Set((deploymentRollups || []).map(rollup => rollup.environment))];
+          const progress = await Promise.all(environments.map(environment =>
+            window.go.main.App.GetRolloutProgress(parseInt(serviceId), environment).catch(() => null)
+          ));
+          setRollouts(progress.filter(rollout => rollout && !rollout.complete));
+        } catch (error) {
+          console.error('Failed to load deployment rollups:', error);
+          setRollups([]);
+          setRollouts([]);
+        }
+
+        try {
+          const deploymentDrifts = await window.go.main.App.GetDeploymentDrift(parseInt(serviceId));
+          setDrifts(deploymentDrifts || []);
+        } catch (error) {
+          console.error('Failed to load deployment drift:', error);
+          setDrifts([]);
+        }
       }
     } catch (error) {
       console.error('Failed to load service deployments:', error);
@@ -85,6 +140,10 @@ const ServiceDeployments = () => {
   };
 
   const getEnvironmentColor = (environment) => {
+    // The service's primary environment is live even when it isn't named like production
+    if (rollups.some(rollup => rollup.is_primary && rollup.environment === environment)) {
+      return 'bg-red-100 text-red-800 border-red-200';
+    }
     switch (environment.toLowerCase()) {
       case 'prd':
       case 'prod':
@@ -110,6 +169,90 @@ const ServiceDeployments = () => {
     return region;
   };
 
+  // With namespaces grouped, an environment/region whose namespaces all run the same tag is one
+  // column; diverged groups keep a column per namespace so the partial rollout stays visible
+  const getColumns = () => {
+    if (!groupNamespaces || rollups.length === 0) {
+      return uniqueDeploymentEnvs;
+    }
+
+    const columns = [];
+    rollups.forEach(rollup => {
+      if (!rollup.diverged && rollup.namespace_count > 1) {
+        columns.push({ environment: rollup.environment, region: rollup.region, rollup });
+        return;
+      }
+      rollup.namespaces.forEach(namespace => {
+        columns.push({
+          environment: rollup.environment,
+          region: rollup.region,
+          namespace,
+          divergent: rollup.divergent_namespaces.includes(namespace)
+        });
+      });
+    });
+    return columns;
+  };
+
+  const columns = getColumns();
+  const divergedRollups = rollups.filter(rollup => rollup.diverged);
+  const rolledUpDeployments = rollups.flatMap(rollup => rollup.deployments || []);
+  const pendingSyncDeployments = rolledUpDeployments.filter(deployment => !deployment.synced);
+
+  const isPendingSync = (column) => {
+    if (column.rollup) {
+      return column.rollup.pending_sync;
+    }
+    return pendingSyncDeployments.some(deployment =>
+      deployment.environment === column.environment &&
+      deployment.region === column.region &&
+      deployment.namespace === column.namespace
+    );
+  };
+
+  // Until a cluster integration reports it, the actual tag is entered by hand
+  const editActualTag = async (deployment) => {
+    const tag = window.prompt(
+      `Tag running in ${deployment.environment}/${deployment.region}/${deployment.namespace || '(default)'} (desired: ${deployment.tag}). Leave empty to assume it's in sync.`,
+      deployment.synced ? deployment.tag : deployment.actual_tag
+    );
+    if (tag === null) {
+      return;
+    }
+    try {
+      await window.go.main.App.SetDeploymentActualTag(deployment.id, tag === deployment.tag ? '' : tag);
+      await loadServiceDeployments();
+    } catch (error) {
+      console.error('Failed to set actual tag:', error);
+      alert(`Failed to set actual tag: ${error}`);
+    }
+  };
+
+  // Traces the deployed tag back to the pull request that produced it
+  const showBlame = async (deployment) => {
+    const target = `${deployment.environment} / ${deployment.region}`;
+    setBlame({ target, loading: true });
+    try {
+      const result = await window.go.main.App.GetDeploymentBlame(parseInt(serviceId), deployment.environment, deployment.region);
+      setBlame({ target, result });
+    } catch (error) {
+      setBlame({ target, error: String(error) });
+    }
+  };
+
+  // Compares the environment variables the service's manifests set in two environments
+  const compareEnvVars = async () => {
+    setEnvDiff({ loading: true });
+    try {
+      const result = await window.go.main.App.GetEnvVarDiff(parseInt(serviceId), envDiffEnvs.a, envDiffEnvs.b);
+      setEnvDiff({ result });
+    } catch (error) {
+      setEnvDiff({ error: String(error) });
+    }
+  };
+
+  const deploymentEnvironments = [...new Set(rollups.map(rollup => rollup.environment))].sort();
+
   if (loading) {
     return (
       <div className="max-w-7xl mx-auto">
@@ -155,16 +298,287 @@ const ServiceDeployments = () => {
               </div>
             </div>
           </div>
-          <button
-            onClick={loadServiceDeployments}
-            className="btn-secondary flex items-center"
-          >
-            <RefreshCw className="h-4 w-4 mr-2" />
-            Refresh
-          </button>
+          <div className="flex items-center space-x-2">
+            <button
+              onClick={() => setGroupNamespaces(!groupNamespaces)}
+              className="btn-secondary flex items-center"
+              title="Show one column per environment and region when all namespaces run the same tag"
+            >
+              <Layers className="h-4 w-4 mr-2" />
+              {groupNamespaces ? 'Expand Namespaces' : 'Group Namespaces'}
+            </button>
+            <button
+              onClick={loadServiceDeployments}
+              className="btn-secondary flex items-center"
+            >
+              <RefreshCw className="h-4 w-4 mr-2" />
+              Refresh
+            </button>
+          </div>
         </div>
       </div>
 
+      {/* Drift between consecutive environments */}
+      {drifts.length > 0 && (
+        <div className="mb-4 p-4 rounded-lg border bg-white border-gray-200">
+          <div className="flex items-center font-medium text-gray-900 mb-2">
+            <Layers className="h-5 w-5 mr-2 text-gray-600" />
+            Environment drift
+          </div>
+          <ul className="space-y-1 text-sm text-gray-700">
+            {drifts.map(drift => (
+              <li key={`${drift.compared_to}-${drift.environment}`} className="flex items-center">
+                <span className={`px-2 py-0.5 mr-2 rounded border text-xs ${getEnvironmentColor(drift.environment)}`}>
+                  {drift.environment}
+                </span>
+                <span>{drift.summary}</span>
+                <span className="ml-2 font-mono text-xs text-gray-500">
+                  {drift.tag} → {drift.compared_tag}
+                </span>
+              </li>
+            ))}
+          </ul>
+        </div>
+      )}
+
+      {/* Deployment blame */}
+      {blame && (
+        <div className="mb-4 p-4 rounded-lg border bg-white border-gray-200 text-sm">
+          <div className="flex items-center justify-between mb-2">
+            <div className="flex items-center font-medium text-gray-900">
+              <GitCommit className="h-5 w-5 mr-2 text-gray-600" />
+              What produced {blame.target}
+            </div>
+            <button onClick={() => setBlame(null)} className="text-xs text-gray-500 hover:underline">Close</button>
+          </div>
+          {blame.loading && <p className="text-gray-500">Tracing the deployed tag...</p>}
+          {blame.error && <p className="text-red-600">{blame.error}</p>}
+          {blame.result && (
+            <div className="space-y-1 text-gray-700">
+              <p>Tag <span className="font-mono">{blame.result.tag}</span></p>
+              {blame.result.commit_sha ? (
+                <p>
+                  Commit{' '}
+                  {blame.result.commit_url ? (
+                    <a href={blame.result.commit_url} target="_blank" rel="noopener noreferrer" className="font-mono text-blue-600 hover:underline">
+                      {blame.result.commit_sha.substring(0, 7)}
+                    </a>
+                  ) : (
+                    <span className="font-mono">{blame.result.commit_sha.substring(0, 7)}</span>
+                  )}
+                  {blame.result.commit_message && <span> {blame.result.commit_message}</span>}
+                  {blame.result.commit_author && <span className="text-gray-500"> by {blame.result.commit_author}</span>}
+                </p>
+              ) : (
+                <p className="text-gray-500">Commit unknown: {blame.result.commit_unknown}</p>
+              )}
+              {blame.result.pr_number > 0 && blame.result.pr_url ? (
+                <p>
+                  Pull request{' '}
+                  <a href={blame.result.pr_url} target="_blank" rel="noopener noreferrer" className="text-blue-600 hover:underline">
+                    #{blame.result.pr_number} {blame.result.pr_title}
+                  </a>
+                  {blame.result.pr_author && <span className="text-gray-500"> by {blame.result.pr_author}</span>}
+                  {blame.result.pr_merged_at && <span className="text-gray-500">, merged {formatDate(blame.result.pr_merged_at)}</span>}
+                </p>
+              ) : (
+                <p className="text-gray-500">Pull request unknown: {blame.result.pr_unknown}</p>
+              )}
+            </div>
+          )}
+        </div>
+      )}
+
+      {/* Environment variable diff */}
+      {deploymentEnvironments.length > 1 && (
+        <div className="mb-4 p-4 rounded-lg border bg-white border-gray-200 text-sm">
+          <div className="flex items-center gap-2">
+            <FileDiff className="h-5 w-5 text-gray-600" />
+            <span className="font-medium text-gray-900">Environment variables</span>
+            {['a', 'b'].map(side => (
+              <select
+                key={side}
+                value={envDiffEnvs[side]}
+                onChange={(e) => setEnvDiffEnvs({ ...envDiffEnvs, [side]: e.target.value })}
+                className="border border-gray-300 rounded px-2 py-1 text-sm"
+              >
+                <option value="">{side === 'a' ? 'From...' : 'To...'}</option>
+                {deploymentEnvironments.map(environment => (
+                  <option key={environment} value={environment}>{environment}</option>
+                ))}
+              </select>
+            ))}
+            <button
+              onClick={compareEnvVars}
+              disabled={!envDiffEnvs.a || !envDiffEnvs.b || envDiffEnvs.a === envDiffEnvs.b}
+              className="px-3 py-1 text-sm bg-blue-600 text-white rounded hover:bg-blue-700 disabled:opacity-50"
+            >
+              Compare
+            </button>
+            {envDiff && (
+              <button onClick={() => setEnvDiff(null)} className="ml-auto text-xs text-gray-500 hover:underline">Close</button>
+            )}
+          </div>
+          {envDiff?.loading && <p className="mt-2 text-gray-500">Comparing...</p>}
+          {envDiff?.error && <p className="mt-2 text-red-600">{envDiff.error}</p>}
+          {envDiff?.result && (
+            <div className="mt-2">
+              <p className="text-xs text-gray-500 mb-1">
+                {envDiff.result.environment_a} / {envDiff.result.region_a} → {envDiff.result.environment_b} / {envDiff.result.region_b},
+                {' '}{envDiff.result.unchanged} unchanged
+              </p>
+              {envDiff.result.changes.length === 0 ? (
+                <p className="text-gray-500">No differences</p>
+              ) : (
+                <ul className="space-y-1 font-mono text-xs">
+                  {envDiff.result.changes.map(change => (
+                    <li key={`${change.container}/${change.name}`}>
+                      <span className={
+                        change.change === 'added' ? 'text-green-700' : change.change === 'removed' ? 'text-red-700' : 'text-yellow-700'
+                      }>
+                        {change.change === 'added' ? '+' : change.change === 'removed' ? '-' : '~'} {change.name}
+                      </span>
+                      <span className="text-gray-400"> ({change.container})</span>
+                      {change.secret ? (
+                        <span className="text-gray-500"> secret</span>
+                      ) : (
+                        <span className="text-gray-700">
+                          {change.change === 'changed' && <> {change.value_a} → {change.value_b}</>}
+                          {change.change === 'added' && <> {change.value_b}</>}
+                          {change.change === 'removed' && <> {change.value_a}</>}
+                        </span>
+                      )}
+                    </li>
+                  ))}
+                </ul>
+              )}
+            </div>
+          )}
+        </div>
+      )}
+
+      {/* Rollouts in progress */}
+      {rollouts.map(rollout => (
+        <div
+          key={rollout.environment}
+          className={`mb-4 p-4 rounded-lg border ${rollout.stuck ? 'bg-red-50 border-red-300' : 'bg-blue-50 border-blue-200'}`}
+        >
+          <div className="flex items-center justify-between">
+            <div className="flex items-center">
+              <Activity className={`h-5 w-5 mr-2 ${rollout.stuck ? 'text-red-600' : 'text-blue-600'}`} />
+              <span className="text-sm font-semibold text-gray-900">
+                {rollout.environment}: {rollout.updated_targets}/{rollout.total_targets} namespaces on{' '}
+                <span className="font-mono">{rollout.tag}</span>
+              </span>
+              {rollout.stuck && (
+                <span className="ml-2 px-2 py-0.5 text-xs font-medium rounded-full bg-red-100 text-red-800">Stuck</span>
+              )}
+            </div>
+            <div className="text-xs text-gray-600">
+              {rollout.last_transition_at && <span>Last namespace updated {formatDate(rollout.last_transition_at)}</span>}
+              {rollout.estimated_completion_at && !rollout.stuck && (
+                <span className="ml-3">Estimated completion {formatDate(rollout.estimated_completion_at)}</span>
+              )}
+            </div>
+          </div>
+          <div className="mt-2 h-2 bg-white rounded-full overflow-hidden">
+            <div
+              className={`h-2 ${rollout.stuck ? 'bg-red-500' : 'bg-blue-500'}`}
+              style={{ width: `${(rollout.updated_targets / rollout.total_targets) * 100}%` }}
+            />
+          </div>
+          <div className="mt-2 text-xs text-gray-600">
+            Still on older tags:{' '}
+            <span className="font-mono">
+              {rollout.stragglers.map(straggler => `${straggler.region}/${straggler.namespace || '(default)'} (${straggler.tag})`).join(', ')}
+            </span>
+          </div>
+        </div>
+      ))}
+
+      {/* Partial rollouts */}
+      {divergedRollups.length > 0 && (
+        <div className="mb-6 p-4 bg-orange-50 border border-orange-300 rounded-lg">
+          <div className="flex items-center mb-2">
+            <AlertTriangle className="h-5 w-5 text-orange-600 mr-2" />
+            <h3 className="text-sm font-semibold text-orange-900">
+              Namespaces running different tags — possible partial rollout
+            </h3>
+          </div>
+          <ul className="space-y-1 text-sm text-orange-800">
+            {divergedRollups.map(rollup => (
+              <li key={`${rollup.environment}-${rollup.region}`}>
+                <strong>{rollup.environment} / {rollup.region}</strong>: {rollup.divergent_namespaces.length} of {rollup.namespace_count} namespaces
+                not on <span className="font-mono">{rollup.tag}</span>:{' '}
+                <span className="font-mono">{rollup.divergent_namespaces.map(namespace => namespace || '(default)').join(', ')}</span>
+              </li>
+            ))}
+          </ul>
+        </div>
+      )}
+
+      {/* Desired (git) vs actual (cluster) tags */}
+      {pendingSyncDeployments.length > 0 && (
+        <div className="mb-6 p-4 bg-yellow-50 border border-yellow-300 rounded-lg">
+          <div className="flex items-center mb-2">
+            <RefreshCw className="h-5 w-5 text-yellow-700 mr-2" />
+            <h3 className="text-sm font-semibold text-yellow-900">
+              Pending sync — the cluster doesn't run the committed tag yet
+            </h3>
+          </div>
+          <ul className="space-y-1 text-sm text-yellow-800">
+            {pendingSyncDeployments.map(deployment => (
+              <li key={deployment.id} className="flex items-center">
+                <strong className="mr-2">{deployment.environment} / {deployment.region} / {deployment.namespace || '(default)'}</strong>
+                <span className="font-mono">{deployment.actual_tag}</span>
+                <span className="mx-1">→</span>
+                <span className="font-mono">{deployment.tag}</span>
+                <button onClick={() => editActualTag(deployment)} className="ml-3 text-xs underline">
+                  Update
+                </button>
+              </li>
+            ))}
+          </ul>
+        </div>
+      )}
+
+      {rolledUpDeployments.length > 0 && (
+        <details className="mb-6 card">
+          <summary className="cursor-pointer text-sm font-medium text-gray-700">Cluster tags (desired vs actual)</summary>
+          <table className="mt-3 min-w-full text-sm">
+            <thead>
+              <tr className="text-left text-xs text-gray-500 uppercase">
+                <th className="py-1 pr-4">Target</th>
+                <th className="py-1 pr-4">Image</th>
+                <th className="py-1 pr-4">Desired</th>
+                <th className="py-1 pr-4">Actual</th>
+                <th className="py-1" />
+              </tr>
+            </thead>
+            <tbody>
+              {rolledUpDeployments.map(deployment => (
+                <tr key={deployment.id} className={deployment.synced ? '' : 'bg-yellow-50'}>
+                  <td className="py-1 pr-4">{deployment.environment} / {deployment.region} / {deployment.namespace || '(default)'}</td>
+                  <td className="py-1 pr-4 font-mono text-xs text-gray-600">{deployment.image_repository || '—'}</td>
+                  <td className="py-1 pr-4 font-mono">{deployment.tag}</td>
+                  <td className="py-1 pr-4 font-mono">{deployment.actual_tag}</td>
+                  <td className="py-1">
+                    <button onClick={() => editActualTag(deployment)} className="text-xs text-blue-600 hover:underline">
+                      Set actual tag
+                    </button>
+                    <button onClick={() => showBlame(deployment)} className="ml-3 text-xs text-blue-600 hover:underline">
+                      Blame
+                    </button>
+                  </td>
+                </tr>
+              ))}
+            </tbody>
+          </table>
+        </details>
+      )}
+
+      <DeploymentsByEnvironment serviceId={parseInt(serviceId)} />
+
       {/* Deployments Overview */}
       <div className="mb-6">
         <h2 className="text-lg font-semibold text-gray-900 mb-4">Deployment Overview</h2>
@@ -208,8 +622,8 @@ const ServiceDeployments = () => {
                       </div>
                     </th>
                     {/* Dynamic environment/region/namespace columns */}
-                    {uniqueDeploymentEnvs.map((env, index) => (
-                      <th key={index} className="px-6 py-3 text-center text-xs font-medium text-gray-500 uppercase tracking-wider">
+                    {columns.map((env, index) => (
+                      <th key={index} className={`px-6 py-3 text-center text-xs font-medium text-gray-500 uppercase tracking-wider ${env.divergent ? 'bg-orange-50' : ''}`}>
                         <div className="text-center">
                           <div className={`inline-flex px-2 py-1 text-xs font-medium rounded-full border ${getEnvironmentColor(env.environment)}`}>
                             {env.environment}
@@ -217,11 +631,27 @@ const ServiceDeployments = () => {
                           <div className="text-xs text-gray-400 mt-1">
                             {getRegionFlag(env.region)}
                           </div>
-                          {env.namespace && (
+                          {env.rollup ? (
+                            <div className="text-xs text-gray-500 mt-1 normal-case" title={env.rollup.namespaces.join(', ')}>
+                              {env.rollup.namespace_count} namespaces
+                            </div>
+                          ) : env.namespace && (
                             <div className="text-xs text-gray-500 mt-1 font-mono">
                               ns: {env.namespace}
                             </div>
                           )}
+                          {env.divergent && (
+                            <div className="text-xs text-orange-700 mt-1 flex items-center justify-center normal-case">
+                              <AlertTriangle className="h-3 w-3 mr-1" />
+                              diverged
+                            </div>
+                          )}
+                          {isPendingSync(env) && (
+                            <div className="text-xs text-yellow-700 mt-1 flex items-center justify-center normal-case">
+                              <RefreshCw className="h-3 w-3 mr-1" />
+                              pending sync
+                            </div>
+                          )}
                         </div>
                       </th>
                     ))}
@@ -246,29 +676,35 @@ const ServiceDeployments = () => {
                         </div>
                       </td>
                       <td className="px-6 py-4 whitespace-nowrap">
-                        <span className="text-sm text-gray-500">
-                          {formatDate(commitDeployment.commit.date)}
+                        <span className="text-sm text-gray-500" title={formatDate(commitDeployment.commit.date)}>
+                          {commitDeployment.commit.date_relative || formatDate(commitDeployment.commit.date)}
                         </span>
                       </td>
                       {/* Deployment status cells */}
-                      {uniqueDeploymentEnvs.map((env, deployIndex) => {
-                        // Find matching deployment for this environment/region/namespace
+                      {columns.map((env, deployIndex) => {
+                        // Find matching deployment for this environment/region/namespace; a grouped
+                        // column matches any of its namespaces since they all run the same tag
                         const matchingDeployment = commitDeployment.deployments.find(d => 
                           d.environment === env.environment && 
                           d.region === env.region && 
-                          d.namespace === env.namespace
+                          (env.rollup ? d.is_deployed : d.namespace === env.namespace)
                         );
                         
                         return (
                           <td key={deployIndex} className="px-6 py-4 whitespace-nowrap text-center">
                             {matchingDeployment?.is_deployed ? (
                               <div>
-                                <span className="font-mono text-xs bg-green-100 text-green-800 px-2 py-1 rounded">
+                                <span
+                                  className={`font-mono text-xs px-2 py-1 rounded ${stalenessClasses[matchingDeployment.staleness] || stalenessClasses.fresh}`}
+                                  title={stalenessTitles[matchingDeployment.staleness]}
+                                >
                                   {matchingDeployment.tag}
                                 </span>
-                                <div className="text-xs text-gray-400 mt-1">
-                                  {formatDate(matchingDeployment.deployed_at)}
-                                </div>
+                                {matchingDeployment.deployed_at && (
+                                  <div className="text-xs text-gray-400 mt-1" title={formatDate(matchingDeployment.deployed_at)}>
+                                    {matchingDeployment.deployed_at_relative || formatDate(matchingDeployment.deployed_at)}
+                                  </div>
+                                )}
                               </div>
                             ) : (
                               <div className="text-gray-400 text-xs">
@@ -13,8 +13,15 @@ import {
   ExternalLink,
   User,
   Calendar,
-  Hash
+  Hash,
+  ClipboardCheck,
+  HelpCircle,
+  FileText,
+  Copy,
+  Tags,
+  Container
 } from 'lucide-react';
+import ServicePackages from '../components/ServicePackages';
 
 const ServiceDetails = () => {
   const { serviceId } = useParams();
@@ -23,6 +30,10 @@ const ServiceDetails = () => {
   const [commits, setCommits] = useState([]);
   const [loading, setLoading] = useState(true);
   const [githubIntegrationAvailable, setGithubIntegrationAvailable] = useState(true);
+  const [staleSections, setStaleSections] = useState({ pullRequests: false, commits: false });
+  const [scorecard, setScorecard] = useState(null);
+  const [report, setReport] = useState(null); // { format, loading, text, error }
+  const [customFields, setCustomFields] = useState([]);
 
   useEffect(() => {
     if (serviceId) {
@@ -30,37 +41,37 @@ const ServiceDetails = () => {
     }
   }, [serviceId]);
 
+  const generateReport = async (format) => {
+    setReport({ format, loading: true });
+    try {
+      const text = await window.go.main.App.GenerateServiceReport(parseInt(serviceId), format);
+      setReport({ format, loading: false, text });
+    } catch (error) {
+      console.error('Failed to generate service report:', error);
+      setReport({ format, loading: false, error: String(error) });
+    }
+  };
+
   const loadServiceDetails = async () => {
     setLoading(true);
     try {
-      // Load service info
-      const allServices = await window.go.main.App.GetMicroservices(0);
-      const selectedService = allServices?.find(s => s.id === parseInt(serviceId));
-      setService(selectedService || null);
-
-      if (selectedService) {
-        // Load service-specific PRs and commits
-        // Note: These methods need to be implemented in the backend
-        try {
-          const prs = await window.go.main.App.GetServicePullRequests(parseInt(serviceId));
-          setPullRequests(prs || []);
-        } catch (error) {
-          console.error('Failed to load pull requests:', error);
-          setPullRequests([]);
-          // Only set GitHub integration as unavailable if it's a real error, not just empty results
-          if (error.message && error.message.includes('no GitHub token')) {
-            setGithubIntegrationAvailable(false);
-          }
-        }
+      // Everything the page shows comes back in one call so it renders at once
+      const detail = await window.go.main.App.GetServiceDetail(parseInt(serviceId), {
+        pull_requests: true,
+        commits: true
+      });
+      setService(detail?.service || null);
+      setPullRequests(detail?.pull_requests?.data || []);
+      setCommits(detail?.commits?.data || []);
+      setStaleSections({
+        pullRequests: !!detail?.pull_requests?.status?.stale,
+        commits: !!detail?.commits?.status?.stale
+      });
 
-        try {
-          const commitHistory = await window.go.main.App.GetServiceCommits(parseInt(serviceId));
-          setCommits(commitHistory || []);
-        } catch (error) {
-          console.error('Failed to load commits:', error);
-          setCommits([]);
-          // Only set GitHub integration as unavailable if it's a real error, not just empty results
-          if (error.message && error.message.includes('no GitHub token')) {
+      for (const [name, section] of Object.entries({ pullRequests: detail?.pull_requests, commits: detail?.commits })) {
+        if (section?.status?.error) {
+          console.error(`Failed to load ${name}:`, section.status.error);
+          if (section.status.error.includes('no GitHub token')) {
             setGithubIntegrationAvailable(false);
           }
         }
@@ -71,6 +82,69 @@ const ServiceDetails = () => {
     } finally {
       setLoading(false);
     }
+
+    try {
+      setScorecard(await window.go.main.App.GetServiceScorecard(parseInt(serviceId)));
+    } catch (error) {
+      console.error('Failed to load scorecard:', error);
+      setScorecard(null);
+    }
+
+    try {
+      setCustomFields(await window.go.main.App.GetServiceCustomFields() || []);
+    } catch (error) {
+      console.error('Failed to load custom fields:', error);
+      setCustomFields([]);
+    }
+  };
+
+  const saveCustomField = async (field, value) => {
+    try {
+      await window.go.main.App.SetServiceCustomFieldValue(parseInt(serviceId), field.id, value);
+      const { [field.name]: _, ...rest } = service.custom_fields || {};
+      setService({ ...service, custom_fields: value === '' ? rest : { ...rest, [field.name]: value } });
+    } catch (error) {
+      console.error('Failed to save custom field:', error);
+      alert(`Failed to save ${field.name}: ${error}`);
+    }
+  };
+
+  const saveImageName = async (imageName) => {
+    try {
+      await window.go.main.App.SetServiceImageName(parseInt(serviceId), imageName);
+      setService({ ...service, image_name: imageName, suggested_image_name: '' });
+    } catch (error) {
+      console.error('Failed to save image name:', error);
+      alert(`Failed to save image name: ${error}`);
+    }
+  };
+
+  const getGradeColor = (grade) => {
+    switch (grade) {
+      case 'A':
+        return 'bg-green-100 text-green-800';
+      case 'B':
+        return 'bg-lime-100 text-lime-800';
+      case 'C':
+        return 'bg-yellow-100 text-yellow-800';
+      case 'D':
+        return 'bg-orange-100 text-orange-800';
+      case 'F':
+        return 'bg-red-100 text-red-800';
+      default:
+        return 'bg-gray-100 text-gray-600';
+    }
+  };
+
+  const getCheckIcon = (status) => {
+    switch (status) {
+      case 'pass':
+        return <CheckCircle className="h-4 w-4 text-green-500 flex-shrink-0" />;
+      case 'fail':
+        return <XCircle className="h-4 w-4 text-red-500 flex-shrink-0" />;
+      default:
+        return <HelpCircle className="h-4 w-4 text-gray-400 flex-shrink-0" />;
+    }
   };
 
   const getStatusIcon = (status) => {
@@ -138,7 +212,7 @@ const ServiceDetails = () => {
           <div className="p-3 bg-blue-100 rounded-lg mr-4">
             <Package className="h-8 w-8 text-blue-600" />
           </div>
-          <div>
+          <div className="flex-1">
             <h1 className="text-3xl font-bold text-gray-900">{service.name}</h1>
             <p className="mt-1 text-gray-600">{service.description || 'No description available'}</p>
             <div className="flex items-center mt-2 text-sm text-gray-500">
@@ -146,7 +220,45 @@ const ServiceDetails = () => {
               <span>{service.path}</span>
             </div>
           </div>
+          <div className="flex items-center space-x-2">
+            <button onClick={() => generateReport('markdown')} className="btn-secondary flex items-center">
+              <FileText className="h-4 w-4 mr-1" />
+              Markdown Report
+            </button>
+            <button onClick={() => generateReport('json')} className="btn-secondary">
+              JSON
+            </button>
+          </div>
         </div>
+
+        {report && (
+          <div className="card">
+            <div className="flex items-center justify-between mb-2">
+              <h2 className="text-sm font-semibold text-gray-900">Service Report ({report.format})</h2>
+              <div className="flex items-center space-x-2">
+                {report.text && (
+                  <button onClick={() => navigator.clipboard.writeText(report.text)} className="btn-secondary flex items-center">
+                    <Copy className="h-4 w-4 mr-1" />
+                    Copy
+                  </button>
+                )}
+                <button onClick={() => setReport(null)} className="btn-secondary">
+                  Close
+                </button>
+              </div>
+            </div>
+            {report.loading && <p className="text-sm text-gray-500">Generating report...</p>}
+            {report.error && <p className="text-sm text-red-600">{report.error}</p>}
+            {report.text && (
+              <textarea
+                readOnly
+                value={report.text}
+                rows={16}
+                className="w-full px-3 py-2 border border-gray-300 rounded-md font-mono text-xs"
+              />
+            )}
+          </div>
+        )}
       </div>
 
       {/* GitHub Integration Notice */}
@@ -164,6 +276,114 @@ const ServiceDetails = () => {
         </div>
       )}
 
+      {/* Scorecard */}
+      {scorecard && (
+        <div className="card mb-8">
+          <div className="flex items-center justify-between mb-4">
+            <h2 className="text-xl font-semibold text-gray-900 flex items-center">
+              <ClipboardCheck className="h-6 w-6 mr-2 text-purple-600" />
+              Scorecard
+            </h2>
+            <div className="flex items-center space-x-3">
+              {scorecard.score != null && (
+                <span className="text-sm text-gray-500">{Math.round(scorecard.score * 100)}% of checks passed</span>
+              )}
+              <span className={`px-3 py-1 rounded-full text-lg font-bold ${getGradeColor(scorecard.grade)}`}>
+                {scorecard.grade}
+              </span>
+            </div>
+          </div>
+
+          {scorecard.results?.length > 0 ? (
+            <div className="grid grid-cols-1 md:grid-cols-2 gap-3">
+              {scorecard.results.map((result) => (
+                <div key={result.check_id} className="flex items-start space-x-2 text-sm">
+                  {getCheckIcon(result.status)}
+                  <div>
+                    <span className="font-medium text-gray-900">{result.name}</span>
+                    {result.detail && <p className="text-gray-500">{result.detail}</p>}
+                  </div>
+                </div>
+              ))}
+            </div>
+          ) : (
+            <p className="text-sm text-gray-500">All scorecard checks are disabled</p>
+          )}
+        </div>
+      )}
+
+      {/* Deployment Image */}
+      <div className="card mb-8">
+        <h2 className="text-xl font-semibold text-gray-900 flex items-center mb-4">
+          <Container className="h-6 w-6 mr-2 text-indigo-600" />
+          Deployment Image
+        </h2>
+        <label className="flex items-center text-sm">
+          <span className="w-40 font-medium text-gray-900">Image name</span>
+          <input
+            key={service.image_name}
+            type="text"
+            defaultValue={service.image_name}
+            placeholder={`Images named like ${service.name}`}
+            onBlur={(e) => e.target.value.trim() !== service.image_name && saveImageName(e.target.value.trim())}
+            className="flex-1 border border-gray-300 rounded px-2 py-1 font-mono"
+          />
+        </label>
+        {!service.image_name && service.suggested_image_name && (
+          <p className="mt-2 text-sm text-gray-500">
+            The last deployment scan found no image named like this service. Suggested:{' '}
+            <span className="font-mono">{service.suggested_image_name}</span>{' '}
+            <button
+              onClick={() => saveImageName(service.suggested_image_name)}
+              className="text-blue-600 hover:text-blue-800"
+            >
+              Use it
+            </button>
+          </p>
+        )}
+      </div>
+
+      {/* Custom Fields */}
+      {customFields.length > 0 && (
+        <div className="card mb-8">
+          <h2 className="text-xl font-semibold text-gray-900 flex items-center mb-4">
+            <Tags className="h-6 w-6 mr-2 text-purple-600" />
+            Custom Fields
+          </h2>
+          <div className="grid grid-cols-1 md:grid-cols-2 gap-3">
+            {customFields.map(field => {
+              const value = service.custom_fields?.[field.name] || '';
+              return (
+                <label key={field.id} className="flex items-center text-sm">
+                  <span className="w-40 font-medium text-gray-900">{field.name}</span>
+                  {field.type === 'text' ? (
+                    <input
+                      type="text"
+                      defaultValue={value}
+                      onBlur={(e) => e.target.value.trim() !== value && saveCustomField(field, e.target.value.trim())}
+                      className="flex-1 border border-gray-300 rounded px-2 py-1"
+                    />
+                  ) : (
+                    <select
+                      value={value}
+                      onChange={(e) => saveCustomField(field, e.target.value)}
+                      className="flex-1 border border-gray-300 rounded px-2 py-1"
+                    >
+                      <option value="">Not set</option>
+                      {(field.type === 'bool' ? ['true', 'false'] : field.allowed_values).map(option => (
+                        <option key={option} value={option}>{option}</option>
+                      ))}
+                    </select>
+                  )}
+                </label>
+              );
+            })}
+          </div>
+        </div>
+      )}
+
+      <ServicePackages serviceId={parseInt(serviceId)} />
+
       {/* Content Grid */}
       <div className="grid grid-cols-1 lg:grid-cols-2 gap-8">
         {/* Pull Requests Section */}
@@ -175,6 +395,7 @@ const ServiceDetails = () => {
             </h2>
             <span className="text-sm text-gray-500">
               {pullRequests.length} total
+              {staleSections.pullRequests && <span className="ml-2 text-yellow-600">(cached)</span>}
             </span>
           </div>
           
@@ -189,6 +410,14 @@ const ServiceDetails = () => {
                       <span className="text-sm text-gray-500">•</span>
                       <span className="text-sm text-gray-500">{pr.title}</span>
                     </div>
+                    {pr.sensitive && (
+                      <span
+                        className="px-2 py-0.5 text-xs font-medium rounded-full bg-orange-100 text-orange-800 flex-shrink-0"
+                        title={`Touches sensitive files:\n${pr.sensitive_files.join('\n')}`}
+                      >
+                        Sensitive
+                      </span>
+                    )}
                   </div>
                   
                   <div className="flex items-center space-x-4 text-sm text-gray-600">
@@ -226,6 +455,7 @@ const ServiceDetails = () => {
             </h2>
             <span className="text-sm text-gray-500">
               {commits.length} total
+              {staleSections.commits && <span className="ml-2 text-yellow-600">(cached)</span>}
             </span>
           </div>
           
@@ -241,13 +471,19 @@ const ServiceDetails = () => {
                           <Hash className="h-4 w-4 mr-1" />
                           <span className="font-mono">{formatCommitHash(commit.hash)}</span>
                         </div>
+                        {commit.pr_number > 0 && (
+                          <div className="flex items-center" title="Pull request that introduced this commit">
+                            <GitPullRequest className="h-4 w-4 mr-1" />
+                            <span>#{commit.pr_number}</span>
+                          </div>
+                        )}
                         <div className="flex items-center">
                           <User className="h-4 w-4 mr-1" />
                           <span>{commit.author}</span>
                         </div>
                         <div className="flex items-center">
                           <Calendar className="h-4 w-4 mr-1" />
-                          <span>{formatDate(commit.date)}</span>
+                          <span title={formatDate(commit.date)}>{commit.date_relative || formatDate(commit.date)}</span>
                         </div>
                       </div>
                     </div>
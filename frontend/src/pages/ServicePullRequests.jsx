@@ -31,7 +31,7 @@ const ServicePullRequests = () => {
     setLoading(true);
     try {
       // Load service info
-      const allServices = await window.go.main.App.GetMicroservices(0);
+      const allServices = await window.go.main.App.GetMicroservices(0, true);
       const selectedService = allServices?.find(s => s.id === parseInt(serviceId));
       setService(selectedService || null);
 
@@ -1,6 +1,9 @@
 import React, { useState, useEffect } from 'react';
-import { GetAllConfig, SetConfig, TestJiraConnection, RefreshAllJiraTitles, TestGitHubConnection } from '../../wailsjs/go/main/App';
-import { Save, TestTube, RefreshCw, CheckCircle, XCircle, Settings as SettingsIcon, Github } from 'lucide-react';
+import { GetAllConfig, GetSetupStatus, SetConfig, TestJiraConnection, RefreshAllJiraTitles, TestGitHubConnection, ExportSettings, ImportSettings, GetUsageInsights, ExportUsageData, ClearUsageData, GetTelemetrySummary, FlushTelemetry, GetProjects, GetServiceCustomFields, CreateServiceCustomField, DeleteServiceCustomField } from '../../wailsjs/go/main/App';
+import { Save, TestTube, RefreshCw, CheckCircle, XCircle, Settings as SettingsIcon, Github, Download, Upload, BarChart3, Trash2, Tags, Plus, Send } from 'lucide-react';
+import WatchRules from '../components/WatchRules';
+import FreezeWindows from '../components/FreezeWindows';
+import AdvancedSettings from '../components/AdvancedSettings';
 
 const Settings = () => {
   const [config, setConfig] = useState({
@@ -8,6 +11,10 @@ const Settings = () => {
     jira_username: '',
     jira_token: '',
     jira_auth_method: 'basic',
+    jira_poll_enabled: 'true',
+    jira_poll_interval_minutes: '',
+    commit_ticket_project_pattern: '',
+    task_default_project_id: '',
     github_token: '',
     github_enterprise_url: ''
   });
@@ -18,9 +25,26 @@ const Settings = () => {
   const [testingGithub, setTestingGithub] = useState(false);
   const [message, setMessage] = useState('');
   const [messageType, setMessageType] = useState(''); // 'success', 'error', or ''
+  const [secretsMode, setSecretsMode] = useState('redact');
+  const [passphrase, setPassphrase] = useState('');
+  const [settingsJson, setSettingsJson] = useState('');
+  const [overwrite, setOverwrite] = useState(false);
+  const [transferring, setTransferring] = useState(false);
+  const [usageInsights, setUsageInsights] = useState(null);
+  const [usageJson, setUsageJson] = useState('');
+  const [telemetry, setTelemetry] = useState(null);
+  const [telemetryEndpoint, setTelemetryEndpoint] = useState('');
+  const [customFields, setCustomFields] = useState([]);
+  const [projects, setProjects] = useState([]);
+  const [newField, setNewField] = useState({ name: '', type: 'text', allowedValues: '' });
+  const [setupStatus, setSetupStatus] = useState(null);
 
   useEffect(() => {
     loadConfig();
+    loadUsageInsights();
+    loadTelemetry();
+    loadCustomFields();
+    GetProjects().then(data => setProjects(data || [])).catch(err => console.error('Failed to load projects:', err));
   }, []);
 
   const loadConfig = async () => {
@@ -32,9 +56,14 @@ const Settings = () => {
         jira_username: configData.jira_username || '',
         jira_token: configData.jira_token || '',
         jira_auth_method: configData.jira_auth_method || 'basic',
+        jira_poll_enabled: configData.jira_poll_enabled || 'true',
+        jira_poll_interval_minutes: configData.jira_poll_interval_minutes || '',
+        commit_ticket_project_pattern: configData.commit_ticket_project_pattern || '',
+        task_default_project_id: configData.task_default_project_id || '',
         github_token: configData.github_token || '',
         github_enterprise_url: configData.github_enterprise_url || ''
       });
+      setSetupStatus(await GetSetupStatus());
     } catch (err) {
       console.error('Failed to load config:', err);
       setMessage('Failed to load configuration');
@@ -68,6 +97,10 @@ const Settings = () => {
       await SetConfig('jira_username', config.jira_username);
       await SetConfig('jira_token', config.jira_token);
       await SetConfig('jira_auth_method', config.jira_auth_method);
+      await SetConfig('jira_poll_enabled', config.jira_poll_enabled);
+      await SetConfig('jira_poll_interval_minutes', config.jira_poll_interval_minutes);
+      await SetConfig('commit_ticket_project_pattern', config.commit_ticket_project_pattern);
+      await SetConfig('task_default_project_id', config.task_default_project_id);
       await SetConfig('github_token', config.github_token);
       await SetConfig('github_enterprise_url', config.github_enterprise_url);
       showMessage('Configuration saved successfully!', 'success');
@@ -79,6 +112,184 @@ const Settings = () => {
     }
   };
 
+  const handleExportSettings = async () => {
+    if (secretsMode === 'encrypt' && !passphrase) {
+      showMessage('Enter a passphrase to encrypt secrets', 'error');
+      return;
+    }
+
+    setTransferring(true);
+    try {
+      const exported = await ExportSettings({ secrets: secretsMode, passphrase });
+      setSettingsJson(exported);
+      showMessage('Settings exported. Copy the JSON below to the other machine.', 'success');
+    } catch (err) {
+      console.error('Failed to export settings:', err);
+      showMessage('Failed to export settings: ' + err, 'error');
+    } finally {
+      setTransferring(false);
+    }
+  };
+
+  const handleImportSettings = async () => {
+    if (!settingsJson.trim()) {
+      showMessage('Paste exported settings JSON first', 'error');
+      return;
+    }
+
+    setTransferring(true);
+    try {
+      const result = await ImportSettings(settingsJson, overwrite, passphrase);
+      let text = `Imported ${result.config_applied} settings (${result.config_skipped} kept), ` +
+        `${result.repositories_created} repositories created, ${result.repositories_updated} updated.`;
+      if (result.missing_secrets?.length > 0) {
+        text += ` Secrets not included: ${result.missing_secrets.join(', ')}.`;
+      }
+      if (result.unknown_config?.length > 0) {
+        text += ` Unknown settings ignored: ${result.unknown_config.join(', ')}.`;
+      }
+      showMessage(text, 'success');
+      await loadConfig();
+    } catch (err) {
+      console.error('Failed to import settings:', err);
+      showMessage('Failed to import settings: ' + err, 'error');
+    } finally {
+      setTransferring(false);
+    }
+  };
+
+  const loadUsageInsights = async () => {
+    try {
+      setUsageInsights(await GetUsageInsights(30));
+    } catch (err) {
+      console.error('Failed to load usage insights:', err);
+    }
+  };
+
+  const handleToggleUsageAnalytics = async (e) => {
+    try {
+      await SetConfig('collect_usage_analytics', e.target.checked ? 'true' : 'false');
+      await loadUsageInsights();
+    } catch (err) {
+      console.error('Failed to update usage analytics setting:', err);
+      showMessage('Failed to update usage analytics setting: ' + err, 'error');
+    }
+  };
+
+  const handleExportUsageData = async () => {
+    try {
+      setUsageJson(await ExportUsageData());
+    } catch (err) {
+      console.error('Failed to export usage data:', err);
+      showMessage('Failed to export usage data: ' + err, 'error');
+    }
+  };
+
+  const handleExportUsageDataToFile = async () => {
+    try {
+      // Runs as a background job; progress and the saved file show in the jobs tray
+      await window.go.main.App.StartJob('export_usage_data', {});
+    } catch (err) {
+      console.error('Failed to start usage data export:', err);
+      showMessage('Failed to start usage data export: ' + err, 'error');
+    }
+  };
+
+  const handleClearUsageData = async () => {
+    if (!window.confirm('Permanently delete all recorded usage data?')) {
+      return;
+    }
+    try {
+      await ClearUsageData();
+      setUsageJson('');
+      await loadUsageInsights();
+      showMessage('Usage data cleared', 'success');
+    } catch (err) {
+      console.error('Failed to clear usage data:', err);
+      showMessage('Failed to clear usage data: ' + err, 'error');
+    }
+  };
+
+  const loadTelemetry = async () => {
+    try {
+      const summary = await GetTelemetrySummary();
+      setTelemetry(summary);
+      setTelemetryEndpoint(summary.endpoint || '');
+    } catch (err) {
+      console.error('Failed to load telemetry summary:', err);
+    }
+  };
+
+  const handleToggleTelemetry = async (e) => {
+    if (!e.target.checked && !window.confirm('Turning telemetry off deletes the feature counts collected so far. Continue?')) {
+      return;
+    }
+    try {
+      await SetConfig('telemetry_enabled', e.target.checked ? 'true' : 'false');
+      await loadTelemetry();
+    } catch (err) {
+      console.error('Failed to update telemetry setting:', err);
+      showMessage('Failed to update telemetry setting: ' + err, 'error');
+    }
+  };
+
+  const handleSaveTelemetryEndpoint = async () => {
+    try {
+      await SetConfig('telemetry_endpoint', telemetryEndpoint.trim());
+      await loadTelemetry();
+      showMessage('Telemetry endpoint saved', 'success');
+    } catch (err) {
+      console.error('Failed to save telemetry endpoint:', err);
+      showMessage('Failed to save telemetry endpoint: ' + err, 'error');
+    }
+  };
+
+  const handleFlushTelemetry = async () => {
+    try {
+      await FlushTelemetry();
+      showMessage('Telemetry sent', 'success');
+    } catch (err) {
+      console.error('Failed to send telemetry:', err);
+      showMessage('Failed to send telemetry: ' + err, 'error');
+    } finally {
+      await loadTelemetry();
+    }
+  };
+
+  const loadCustomFields = async () => {
+    try {
+      setCustomFields(await GetServiceCustomFields() || []);
+    } catch (err) {
+      console.error('Failed to load custom fields:', err);
+    }
+  };
+
+  const handleCreateCustomField = async () => {
+    try {
+      const allowedValues = newField.allowedValues.split(',').map(value => value.trim()).filter(Boolean);
+      await CreateServiceCustomField(newField.name, newField.type, allowedValues);
+      setNewField({ name: '', type: 'text', allowedValues: '' });
+      await loadCustomFields();
+    } catch (err) {
+      console.error('Failed to create custom field:', err);
+      showMessage('Failed to create custom field: ' + err, 'error');
+    }
+  };
+
+  const handleDeleteCustomField = async (field) => {
+    const values = field.value_count === 1 ? '1 service value' : `${field.value_count} service values`;
+    if (!window.confirm(`Delete the "${field.name}" field? This also deletes ${values}.`)) {
+      return;
+    }
+    try {
+      await DeleteServiceCustomField(field.id);
+      await loadCustomFields();
+    } catch (err) {
+      console.error('Failed to delete custom field:', err);
+      showMessage('Failed to delete custom field: ' + err, 'error');
+    }
+  };
+
   const handleTestConnection = async () => {
     if (!config.jira_url || !config.jira_token) {
       showMessage('Please enter JIRA URL and credentials before testing', 'error');
@@ -119,7 +330,7 @@ const Settings = () => {
     setRefreshing(true);
     try {
       await RefreshAllJiraTitles();
-      showMessage('Successfully refreshed all JIRA ticket titles!', 'success');
+      showMessage('Successfully refreshed all JIRA tickets!', 'success');
     } catch (err) {
       console.error('Failed to refresh JIRA titles:', err);
       showMessage('Failed to refresh JIRA titles: ' + err.message, 'error');
@@ -275,6 +486,77 @@ const Settings = () => {
             </p>
           </div>
 
+          <label className="flex items-center gap-2 text-sm text-gray-700">
+            <input
+              type="checkbox"
+              checked={config.jira_poll_enabled !== 'false'}
+              onChange={(e) => setConfig(prev => ({ ...prev, jira_poll_enabled: e.target.checked ? 'true' : 'false' }))}
+              disabled={saving}
+            />
+            Refresh tasks' tickets in the background
+          </label>
+
+          <div>
+            <label htmlFor="jira_poll_interval_minutes" className="block text-sm font-medium text-gray-700 mb-2">
+              Ticket Poll Interval (minutes)
+            </label>
+            <input
+              type="number"
+              min="1"
+              id="jira_poll_interval_minutes"
+              name="jira_poll_interval_minutes"
+              value={config.jira_poll_interval_minutes}
+              onChange={handleInputChange}
+              className="w-full border border-gray-300 rounded-lg px-3 py-2 focus:outline-none focus:ring-2 focus:ring-blue-500"
+              placeholder="15"
+              disabled={saving || config.jira_poll_enabled === 'false'}
+            />
+            <p className="text-xs text-gray-500 mt-1">
+              How often the title, status and assignee of tasks' tickets are refreshed
+            </p>
+          </div>
+
+          <div>
+            <label htmlFor="commit_ticket_project_pattern" className="block text-sm font-medium text-gray-700 mb-2">
+              Commit Ticket Projects
+            </label>
+            <input
+              type="text"
+              id="commit_ticket_project_pattern"
+              name="commit_ticket_project_pattern"
+              value={config.commit_ticket_project_pattern}
+              onChange={handleInputChange}
+              className="w-full border border-gray-300 rounded-lg px-3 py-2 font-mono focus:outline-none focus:ring-2 focus:ring-blue-500"
+              placeholder="PAY|OPS"
+              disabled={saving}
+            />
+            <p className="text-xs text-gray-500 mt-1">
+              Regular expression of the project keys whose tickets (e.g. PAY-123) are linked in commit messages. Leave empty to link any project.
+            </p>
+          </div>
+
+          <div>
+            <label htmlFor="task_default_project_id" className="block text-sm font-medium text-gray-700 mb-2">
+              Quick Add Project
+            </label>
+            <select
+              id="task_default_project_id"
+              name="task_default_project_id"
+              value={config.task_default_project_id}
+              onChange={handleInputChange}
+              className="w-full border border-gray-300 rounded-lg px-3 py-2 focus:outline-none focus:ring-2 focus:ring-blue-500"
+              disabled={saving}
+            >
+              <option value="">{projects.length === 1 ? projects[0].name : 'None'}</option>
+              {projects.map(project => (
+                <option key={project.id} value={String(project.id)}>{project.name}</option>
+              ))}
+            </select>
+            <p className="text-xs text-gray-500 mt-1">
+              Project tasks added from the quick add box on the Tasks page go to
+            </p>
+          </div>
+
           <div className="flex gap-3">
             <button
               onClick={handleSave}
@@ -400,6 +682,18 @@ const Settings = () => {
                 'Create a token at: GitHub.com → Settings → Developer settings → Personal access tokens → Tokens (classic)'
               }
             </p>
+            {setupStatus?.github_token_source === 'credentials_file' && (
+              <p className="text-xs text-gray-500 mt-1">Imported from {setupStatus.credentials_file} on startup.</p>
+            )}
+            {setupStatus?.github_token_source === 'keychain' && (
+              <p className="text-xs text-gray-500 mt-1">Imported from the keychain on startup.</p>
+            )}
+            {setupStatus?.github_token_source === 'environment' && (
+              <p className="text-xs text-amber-700 mt-1">
+                Using the GITHUB_TOKEN environment variable, which apps started from the Finder or Dock don't see.
+                Save the token here, or put it in {setupStatus.credentials_file} (readable only by you) or the keychain.
+              </p>
+            )}
           </div>
 
           <div className="flex gap-3">
@@ -473,6 +767,354 @@ const Settings = () => {
           </div>
         </div>
       </div>
+
+      {/* Export / Import Section */}
+      <div className="bg-white rounded-lg shadow-sm border border-gray-200">
+        <div className="px-6 py-4 border-b border-gray-200">
+          <div className="flex items-center gap-3">
+            <Download className="w-6 h-6 text-gray-700" />
+            <div>
+              <h2 className="text-lg font-semibold text-gray-900">Export / Import Settings</h2>
+              <p className="text-sm text-gray-600 mt-1">
+                Move configuration and repositories to another machine or share them with your team
+              </p>
+            </div>
+          </div>
+        </div>
+
+        <div className="p-6 space-y-6">
+          <div className="grid grid-cols-1 md:grid-cols-2 gap-4">
+            <div>
+              <label htmlFor="secrets_mode" className="block text-sm font-medium text-gray-700 mb-2">
+                Secrets (GitHub and JIRA tokens)
+              </label>
+              <select
+                id="secrets_mode"
+                value={secretsMode}
+                onChange={(e) => setSecretsMode(e.target.value)}
+                className="w-full border border-gray-300 rounded-lg px-3 py-2 focus:outline-none focus:ring-2 focus:ring-blue-500"
+                disabled={transferring}
+              >
+                <option value="redact">Leave out (safe to share)</option>
+                <option value="encrypt">Encrypt with passphrase</option>
+                <option value="include">Include in plain text</option>
+              </select>
+            </div>
+            <div>
+              <label htmlFor="settings_passphrase" className="block text-sm font-medium text-gray-700 mb-2">
+                Passphrase
+              </label>
+              <input
+                type="password"
+                id="settings_passphrase"
+                value={passphrase}
+                onChange={(e) => setPassphrase(e.target.value)}
+                className="w-full border border-gray-300 rounded-lg px-3 py-2 focus:outline-none focus:ring-2 focus:ring-blue-500"
+                placeholder="Used to encrypt on export and decrypt on import"
+                disabled={transferring}
+              />
+            </div>
+          </div>
+
+          <textarea
+            value={settingsJson}
+            onChange={(e) => setSettingsJson(e.target.value)}
+            rows={8}
+            className="w-full border border-gray-300 rounded-lg px-3 py-2 font-mono text-xs focus:outline-none focus:ring-2 focus:ring-blue-500"
+            placeholder="Exported settings appear here. Paste settings from another machine to import them."
+            disabled={transferring}
+          />
+
+          <label className="flex items-center gap-2 text-sm text-gray-700">
+            <input
+              type="checkbox"
+              checked={overwrite}
+              onChange={(e) => setOverwrite(e.target.checked)}
+              disabled={transferring}
+            />
+            Overwrite settings and repositories that already exist
+          </label>
+
+          <div className="flex gap-3">
+            <button
+              onClick={handleExportSettings}
+              disabled={transferring}
+              className="flex items-center gap-2 px-4 py-2 bg-blue-600 text-white rounded-lg hover:bg-blue-700 disabled:opacity-50 disabled:cursor-not-allowed"
+            >
+              <Download className="w-4 h-4" />
+              Export
+            </button>
+            <button
+              onClick={handleImportSettings}
+              disabled={transferring || !settingsJson.trim()}
+              className="flex items-center gap-2 px-4 py-2 border border-blue-600 text-blue-600 rounded-lg hover:bg-blue-50 disabled:opacity-50 disabled:cursor-not-allowed"
+            >
+              <Upload className="w-4 h-4" />
+              Import
+            </button>
+          </div>
+        </div>
+      </div>
+
+      {/* Service Custom Fields Section */}
+      <div className="bg-white rounded-lg shadow-sm border border-gray-200">
+        <div className="px-6 py-4 border-b border-gray-200">
+          <div className="flex items-center gap-3">
+            <Tags className="w-6 h-6 text-gray-700" />
+            <div>
+              <h2 className="text-lg font-semibold text-gray-900">Service Custom Fields</h2>
+              <p className="text-sm text-gray-600 mt-1">
+                Attach your own metadata to services, such as tier, PCI scope or language, and filter the service list by it.
+              </p>
+            </div>
+          </div>
+        </div>
+
+        <div className="p-6 space-y-4">
+          {customFields.length > 0 && (
+            <ul className="divide-y divide-gray-200 border border-gray-200 rounded-lg">
+              {customFields.map(field => (
+                <li key={field.id} className="flex items-center justify-between px-4 py-2 text-sm">
+                  <div>
+                    <span className="font-medium text-gray-900">{field.name}</span>
+                    <span className="ml-2 text-gray-500">{field.type}</span>
+                    {field.type === 'enum' && (
+                      <span className="ml-2 text-gray-500">({field.allowed_values.join(', ')})</span>
+                    )}
+                    <span className="ml-2 text-gray-400">{field.value_count} set</span>
+                  </div>
+                  <button
+                    onClick={() => handleDeleteCustomField(field)}
+                    className="text-red-600 hover:text-red-800"
+                    title="Delete field"
+                  >
+                    <Trash2 className="w-4 h-4" />
+                  </button>
+                </li>
+              ))}
+            </ul>
+          )}
+
+          <div className="flex flex-wrap gap-3 items-end">
+            <input
+              type="text"
+              value={newField.name}
+              onChange={(e) => setNewField({ ...newField, name: e.target.value })}
+              placeholder="Field name"
+              className="border border-gray-300 rounded-lg px-3 py-2 text-sm"
+            />
+            <select
+              value={newField.type}
+              onChange={(e) => setNewField({ ...newField, type: e.target.value })}
+              className="border border-gray-300 rounded-lg px-3 py-2 text-sm"
+            >
+              <option value="text">Text</option>
+              <option value="enum">Enum</option>
+              <option value="bool">Yes / No</option>
+            </select>
+            {newField.type === 'enum' && (
+              <input
+                type="text"
+                value={newField.allowedValues}
+                onChange={(e) => setNewField({ ...newField, allowedValues: e.target.value })}
+                placeholder="Allowed values, comma separated"
+                className="flex-1 border border-gray-300 rounded-lg px-3 py-2 text-sm"
+              />
+            )}
+            <button
+              onClick={handleCreateCustomField}
+              disabled={!newField.name.trim()}
+              className="flex items-center gap-2 px-4 py-2 border border-blue-600 text-blue-600 rounded-lg hover:bg-blue-50 disabled:opacity-50"
+            >
+              <Plus className="w-4 h-4" />
+              Add Field
+            </button>
+          </div>
+        </div>
+      </div>
+
+      {/* Watch Rules Section */}
+      <WatchRules showMessage={showMessage} />
+
+      {/* Freeze Windows Section */}
+      <FreezeWindows showMessage={showMessage} />
+
+      {/* Advanced Settings Section */}
+      <AdvancedSettings showMessage={showMessage} />
+
+      {/* Usage Analytics Section */}
+      <div className="bg-white rounded-lg shadow-sm border border-gray-200">
+        <div className="px-6 py-4 border-b border-gray-200">
+          <div className="flex items-center gap-3">
+            <BarChart3 className="w-6 h-6 text-gray-700" />
+            <div>
+              <h2 className="text-lg font-semibold text-gray-900">Usage Analytics</h2>
+              <p className="text-sm text-gray-600 mt-1">
+                Record which services and pages you open. Data stays in the local database and is never sent anywhere.
+              </p>
+            </div>
+          </div>
+        </div>
+
+        <div className="p-6 space-y-6">
+          <label className="flex items-center gap-2 text-sm text-gray-700">
+            <input
+              type="checkbox"
+              checked={usageInsights?.enabled || false}
+              onChange={handleToggleUsageAnalytics}
+            />
+            Collect local usage analytics
+          </label>
+
+          {usageInsights && usageInsights.total_events > 0 && (
+            <div className="grid grid-cols-1 md:grid-cols-2 gap-4">
+              <div>
+                <h3 className="text-sm font-medium text-gray-700 mb-2">Most viewed services (last {usageInsights.days} days)</h3>
+                <ul className="text-sm text-gray-600 space-y-1">
+                  {usageInsights.top_services.map(service => (
+                    <li key={service.service_id} className="flex justify-between">
+                      <span>{service.service_name || `Service ${service.service_id}`}</span>
+                      <span className="text-gray-500">{service.views}</span>
+                    </li>
+                  ))}
+                </ul>
+              </div>
+              <div>
+                <h3 className="text-sm font-medium text-gray-700 mb-2">Busiest hours</h3>
+                <ul className="text-sm text-gray-600 space-y-1">
+                  {usageInsights.busiest_hours.slice(0, 5).map(hour => (
+                    <li key={hour.hour} className="flex justify-between">
+                      <span>{String(hour.hour).padStart(2, '0')}:00</span>
+                      <span className="text-gray-500">{hour.events}</span>
+                    </li>
+                  ))}
+                </ul>
+              </div>
+            </div>
+          )}
+
+          {usageJson && (
+            <textarea
+              value={usageJson}
+              readOnly
+              rows={8}
+              className="w-full border border-gray-300 rounded-lg px-3 py-2 font-mono text-xs"
+            />
+          )}
+
+          <div className="flex gap-3">
+            <button
+              onClick={handleExportUsageData}
+              className="flex items-center gap-2 px-4 py-2 border border-blue-600 text-blue-600 rounded-lg hover:bg-blue-50"
+            >
+              <Download className="w-4 h-4" />
+              Export Data
+            </button>
+            <button
+              onClick={handleExportUsageDataToFile}
+              className="flex items-center gap-2 px-4 py-2 border border-blue-600 text-blue-600 rounded-lg hover:bg-blue-50"
+            >
+              <Download className="w-4 h-4" />
+              Save to File
+            </button>
+            <button
+              onClick={handleClearUsageData}
+              className="flex items-center gap-2 px-4 py-2 border border-red-600 text-red-600 rounded-lg hover:bg-red-50"
+            >
+              <Trash2 className="w-4 h-4" />
+              Clear Data
+            </button>
+          </div>
+        </div>
+      </div>
+
+      {/* Telemetry Section */}
+      <div className="bg-white rounded-lg shadow-sm border border-gray-200">
+        <div className="px-6 py-4 border-b border-gray-200">
+          <div className="flex items-center gap-3">
+            <Send className="w-6 h-6 text-gray-700" />
+            <div>
+              <h2 className="text-lg font-semibold text-gray-900">Telemetry</h2>
+              <p className="text-sm text-gray-600 mt-1">
+                Count how often features are used, optionally sending the counts to an endpoint you choose every hour.
+                Only the feature names and counts below are collected: no names, URLs, repository contents or identifiers.
+              </p>
+            </div>
+          </div>
+        </div>
+
+        <div className="p-6 space-y-6">
+          <label className="flex items-center gap-2 text-sm text-gray-700">
+            <input
+              type="checkbox"
+              checked={telemetry?.enabled || false}
+              onChange={handleToggleTelemetry}
+            />
+            Count feature usage
+          </label>
+
+          <div>
+            <label className="block text-sm font-medium text-gray-700 mb-2">Endpoint</label>
+            <div className="flex gap-3">
+              <input
+                type="url"
+                value={telemetryEndpoint}
+                onChange={(e) => setTelemetryEndpoint(e.target.value)}
+                placeholder="Leave empty to keep counts local"
+                className="flex-1 border border-gray-300 rounded-lg px-3 py-2 text-sm"
+              />
+              <button
+                onClick={handleSaveTelemetryEndpoint}
+                className="flex items-center gap-2 px-4 py-2 border border-blue-600 text-blue-600 rounded-lg hover:bg-blue-50"
+              >
+                <Save className="w-4 h-4" />
+                Save
+              </button>
+            </div>
+          </div>
+
+          {telemetry && telemetry.counters.length > 0 && (
+            <div>
+              <h3 className="text-sm font-medium text-gray-700 mb-2">Collected</h3>
+              <ul className="text-sm text-gray-600 space-y-1">
+                {telemetry.counters.map(counter => (
+                  <li key={counter.feature} className="flex justify-between">
+                    <span className="font-mono">{counter.feature}</span>
+                    <span className="text-gray-500">{counter.count} ({counter.pending} not sent)</span>
+                  </li>
+                ))}
+              </ul>
+            </div>
+          )}
+
+          {telemetry && (
+            <div>
+              <h3 className="text-sm font-medium text-gray-700 mb-2">Next request body</h3>
+              <textarea
+                value={telemetry.next_payload}
+                readOnly
+                rows={6}
+                className="w-full border border-gray-300 rounded-lg px-3 py-2 font-mono text-xs"
+              />
+              {telemetry.last_flush_at && (
+                <p className={`text-xs mt-1 ${telemetry.last_flush_error ? 'text-red-600' : 'text-gray-500'}`}>
+                  Last sent {new Date(telemetry.last_flush_at).toLocaleString()}
+                  {telemetry.last_flush_error && `: ${telemetry.last_flush_error}`}
+                </p>
+              )}
+            </div>
+          )}
+
+          <button
+            onClick={handleFlushTelemetry}
+            disabled={!telemetry?.enabled || !telemetry?.endpoint}
+            className="flex items-center gap-2 px-4 py-2 border border-blue-600 text-blue-600 rounded-lg hover:bg-blue-50 disabled:opacity-50"
+          >
+            <Send className="w-4 h-4" />
+            Send Now
+          </button>
+        </div>
+      </div>
     </div>
   );
 };
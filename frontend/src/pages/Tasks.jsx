@@ -1,17 +1,23 @@
 import React, { useState, useEffect } from 'react';
-import { GetTasksGroupedByScheduledDate, UpdateTaskStatus } from '../../wailsjs/go/main/App';
+import { GetTasksGroupedByScheduledDate, UpdateTaskStatus, GetTaskJiraHistory, QuickAddTask } from '../../wailsjs/go/main/App';
 import { Copy, CheckCircle, Clock, AlertCircle, Calendar, ExternalLink } from 'lucide-react';
+import useDataChanged from '../hooks/useDataChanged';
 
 const Tasks = () => {
   const [tasks, setTasks] = useState([]);
   const [loading, setLoading] = useState(true);
   const [error, setError] = useState(null);
   const [copiedTicketId, setCopiedTicketId] = useState(null);
+  const [jiraHistories, setJiraHistories] = useState({}); // task id -> { loading, history, error }
+  const [quickAdd, setQuickAdd] = useState('');
+  const [quickAdding, setQuickAdding] = useState(false);
 
   useEffect(() => {
     loadTasks();
   }, []);
 
+  useDataChanged(['tasks'], 0, () => loadTasks());
+
   const loadTasks = async () => {
     setLoading(true);
     try {
@@ -33,6 +39,47 @@ const Tasks = () => {
     }
   };
 
+  const handleQuickAdd = async (e) => {
+    e.preventDefault();
+    if (!quickAdd.trim()) {
+      return;
+    }
+    setQuickAdding(true);
+    try {
+      await QuickAddTask(quickAdd);
+      setQuickAdd('');
+      setError(null);
+      await loadTasks();
+    } catch (err) {
+      setError('Failed to add task: ' + err);
+    } finally {
+      setQuickAdding(false);
+    }
+  };
+
+  const toggleJiraHistory = async (taskId) => {
+    if (jiraHistories[taskId]) {
+      const { [taskId]: _, ...rest } = jiraHistories;
+      setJiraHistories(rest);
+      return;
+    }
+    setJiraHistories(previous => ({ ...previous, [taskId]: { loading: true } }));
+    try {
+      const history = await GetTaskJiraHistory(taskId);
+      setJiraHistories(previous => ({ ...previous, [taskId]: { history } }));
+    } catch (err) {
+      setJiraHistories(previous => ({ ...previous, [taskId]: { error: String(err) } }));
+    }
+  };
+
+  const formatDuration = (seconds) => {
+    const days = Math.floor(seconds / 86400);
+    const hours = Math.floor((seconds % 86400) / 3600);
+    if (days > 0) return `${days}d ${hours}h`;
+    if (hours > 0) return `${hours}h ${Math.floor((seconds % 3600) / 60)}m`;
+    return `${Math.max(1, Math.floor(seconds / 60))}m`;
+  };
+
   const handleCopyTicketId = async (ticketId) => {
     try {
       await navigator.clipboard.writeText(ticketId);
@@ -161,6 +208,17 @@ const Tasks = () => {
         </div>
       </div>
 
+      <form onSubmit={handleQuickAdd}>
+        <input
+          type="text"
+          value={quickAdd}
+          onChange={(e) => setQuickAdd(e.target.value)}
+          placeholder="Quick add: Fix login bug #PROJ-123 !high @friday @due:+1w"
+          className="w-full border border-gray-300 rounded-lg px-3 py-2 focus:outline-none focus:ring-2 focus:ring-blue-500"
+          disabled={quickAdding}
+        />
+      </form>
+
       {error && (
         <div className="bg-red-100 border border-red-400 text-red-700 px-4 py-3 rounded">
           {error}
@@ -218,6 +276,22 @@ const Tasks = () => {
                             {getStatusIcon(task.status)}
                             {task.status.replace('_', ' ')}
                           </span>
+
+                          {task.priority && task.priority !== 'medium' && (
+                            <span className={`px-2 py-1 text-xs font-medium rounded ${task.priority === 'high' ? 'bg-red-100 text-red-800' : 'bg-gray-100 text-gray-600'}`}>
+                              {task.priority} priority
+                            </span>
+                          )}
+
+                          {task.jira_status && (
+                            <button
+                              onClick={() => toggleJiraHistory(task.id)}
+                              className="px-2 py-1 text-xs font-medium rounded bg-gray-100 text-gray-700 hover:bg-gray-200"
+                              title="JIRA status - click for its history"
+                            >
+                              {task.jira_status}
+                            </button>
+                          )}
                         </div>
 
                         <h3 className="font-medium text-gray-900 mb-2">
@@ -229,11 +303,59 @@ const Tasks = () => {
                         
                         <div className="text-sm text-gray-600 space-y-1">
                           <p>Project: <span className="font-medium">{task.project_name}</span></p>
+                          {(task.jira_assignee_name || task.jira_sprint || task.jira_parent_key || task.jira_due_date) && (
+                            <p className="flex flex-wrap gap-x-4">
+                              {task.jira_assignee_name && <span>Assignee: <span className="font-medium">{task.jira_assignee_name}</span></span>}
+                              {task.jira_sprint && <span>Sprint: <span className="font-medium">{task.jira_sprint}</span></span>}
+                              {task.jira_parent_key && <span>Parent: <span className="font-medium">{task.jira_parent_key}</span></span>}
+                              {task.jira_due_date && <span>JIRA due: <span className="font-medium">{formatDate(task.jira_due_date)}</span></span>}
+                            </p>
+                          )}
+                          {task.jira_labels && task.jira_labels.length > 0 && (
+                            <div className="flex flex-wrap gap-1">
+                              {task.jira_labels.map(label => (
+                                <span key={label} className="px-2 py-0.5 text-xs rounded bg-blue-50 text-blue-700">{label}</span>
+                              ))}
+                            </div>
+                          )}
                           {task.description && (
                             <p className="text-gray-700">{task.description}</p>
                           )}
                         </div>
 
+                        {jiraHistories[task.id] && (
+                          <div className="mt-3 p-3 bg-gray-50 rounded text-sm">
+                            {jiraHistories[task.id].loading && <p className="text-gray-500">Loading JIRA history...</p>}
+                            {jiraHistories[task.id].error && <p className="text-red-600">{jiraHistories[task.id].error}</p>}
+                            {jiraHistories[task.id].history && (
+                              <>
+                                <div className="flex flex-wrap gap-2 mb-2">
+                                  {jiraHistories[task.id].history.time_in_status.map(duration => (
+                                    <span
+                                      key={duration.status}
+                                      className={`px-2 py-0.5 rounded text-xs ${duration.current ? 'bg-blue-100 text-blue-800' : 'bg-gray-200 text-gray-700'}`}
+                                    >
+                                      {duration.status}: {formatDuration(duration.seconds)}{duration.current ? ' so far' : ''}
+                                    </span>
+                                  ))}
+                                </div>
+                                {jiraHistories[task.id].history.transitions.length === 0 ? (
+                                  <p className="text-gray-500">No status changes yet.</p>
+                                ) : (
+                                  <ul className="space-y-1 text-gray-700">
+                                    {jiraHistories[task.id].history.transitions.map((transition, index) => (
+                                      <li key={index}>
+                                        {transition.from} → <strong>{transition.to}</strong>
+                                        <span className="text-gray-500"> {transition.at_relative}{transition.author && ` by ${transition.author}`}</span>
+                                      </li>
+                                    ))}
+                                  </ul>
+                                )}
+                              </>
+                            )}
+                          </div>
+                        )}
+
                         <div className="flex items-center gap-4 mt-3 text-sm">
                           {task.scheduled_date && (
                             <span className="flex items-center gap-1 text-gray-600">
@@ -0,0 +1,43 @@
+// Presentation mode disguises names, URLs and JIRA keys for screenshots. Every binding call goes
+// through window.go.main.App, so wrapping it here covers all of them: while the mode is on, calls
+// are sent through CallInPresentationMode, which redacts read results and refuses anything else.
+// It returns the redacted result as raw JSON, so it arrives decoded just like the binding's own.
+const direct = new Set(['SetPresentationMode', 'GetPresentationMode', 'CallInPresentationMode']);
+
+let presenting = false;
+// Settles once the mode is known, so no call made at startup slips through undisguised
+let ready = Promise.resolve();
+
+export const installPresentationMode = () => {
+  const app = window.go?.main?.App;
+  if (!app) return;
+
+  window.go.main.App = new Proxy(app, {
+    get(target, method) {
+      const binding = target[method];
+      if (typeof binding !== 'function' || direct.has(method)) {
+        return binding;
+      }
+      return async (...args) => {
+        await ready;
+        return presenting ? target.CallInPresentationMode(method, args) : binding(...args);
+      };
+    },
+  });
+
+  ready = app.GetPresentationMode().then(
+    (enabled) => {
+      presenting = enabled;
+    },
+    () => {},
+  );
+};
+
+// Turns presentation mode on or off and reloads, so every page shows data the new way
+export const setPresentationMode = async (enabled) => {
+  await window.go.main.App.SetPresentationMode(enabled);
+  presenting = enabled;
+  window.location.reload();
+};
+
+export const isPresenting = () => presenting;
@@ -3,39 +3,153 @@
 import {types} from '../models';
 import {time} from '../models';
 
+export function AcknowledgeJob(arg1:number):Promise<void>;
+
+export function AddAnnotation(arg1:string,arg2:string,arg3:string):Promise<types.Annotation>;
+
+export function AddTaskChecklistItem(arg1:number,arg2:string):Promise<types.TaskChecklistItem>;
+
+export function ApplyDiscoveryChanges(arg1:number,arg2:Array<types.DiscoveryChangeDecision>):Promise<void>;
+
+export function ApproveDeployment(arg1:number,arg2:string,arg3:string):Promise<void>;
+
+export function CallInPresentationMode(arg1:string,arg2:Array<any>):Promise<Array<number>>;
+
+export function CancelJob(arg1:number):Promise<void>;
+
+export function ClearUsageData():Promise<void>;
+
+export function CreateFreezeWindow(arg1:types.FreezeWindow):Promise<types.FreezeWindow>;
+
 export function CreateProject(arg1:types.Project):Promise<void>;
 
 export function CreateRepository(arg1:types.Repository):Promise<void>;
 
 export function CreateRepositoryWithAuth(arg1:Record<string, any>):Promise<void>;
 
+export function CreateServiceCustomField(arg1:string,arg2:string,arg3:Array<string>):Promise<types.CustomFieldDefinition>;
+
 export function CreateTask(arg1:types.Task):Promise<void>;
 
 export function CreateTaskWithJiraTitle(arg1:types.Task):Promise<void>;
 
+export function CreateWatchRule(arg1:types.WatchRule):Promise<types.WatchRule>;
+
+export function DeleteAnnotation(arg1:number):Promise<void>;
+
+export function DeleteFreezeWindow(arg1:number):Promise<void>;
+
 export function DeleteProject(arg1:number):Promise<void>;
 
 export function DeleteRepository(arg1:number):Promise<void>;
 
+export function DeleteServiceCustomField(arg1:number):Promise<void>;
+
 export function DeleteTask(arg1:number):Promise<void>;
 
-export function DiscoverRepositoryServices(arg1:string,arg2:string,arg3:string,arg4:Record<string, any>):Promise<Array<Record<string, any>>>;
+export function DeleteTaskChecklistItem(arg1:number):Promise<void>;
+
+export function DeleteWatchRule(arg1:number):Promise<void>;
+
+export function DiagnoseDeploymentScan(arg1:number):Promise<types.DeploymentScanDiagnostics>;
+
+export function DiscoverRepositoryServices(arg1:string,arg2:string,arg3:string,arg4:Record<string, any>):Promise<Array<types.DiscoveredService>>;
+
+export function DownloadActionArtifact(arg1:number,arg2:number,arg3:string):Promise<string>;
+
+export function EvaluateWatchRules():Promise<void>;
+
+export function ExportServiceCatalog(arg1:string,arg2:string):Promise<string>;
+
+export function ExportSettings(arg1:types.SettingsExportOptions):Promise<string>;
+
+export function ExportUsageData():Promise<string>;
 
 export function FetchJiraTicketTitle(arg1:string):Promise<string>;
 
+export function FilterMicroservices(arg1:number,arg2:boolean,arg3:Array<types.CustomFieldFilter>):Promise<Array<types.Microservice>>;
+
+export function FlushTelemetry():Promise<void>;
+
+export function GenerateEnvironmentComparisonReport(arg1:string,arg2:string,arg3:string):Promise<string>;
+
+export function GenerateServiceReport(arg1:number,arg2:string):Promise<string>;
+
+export function GetAccessReport():Promise<types.RepositoryAccessReport>;
+
+export function GetActionArtifacts(arg1:number):Promise<Array<types.ActionArtifact>>;
+
+export function GetActionsMinutesUsage(arg1:number):Promise<types.ActionsUsageSummary>;
+
+export function GetActivityFeed(arg1:number,arg2:number,arg3:string,arg4:string):Promise<types.ActivityFeed>;
+
 export function GetAllConfig():Promise<Record<string, string>>;
 
+export function GetAnnotations(arg1:string,arg2:string):Promise<Array<types.Annotation>>;
+
+export function GetAuditLog(arg1:number):Promise<Array<types.AuditEntry>>;
+
+export function GetBuildMatrix(arg1:number):Promise<types.BuildMatrix>;
+
+export function GetCommitChecks(arg1:number,arg2:string):Promise<types.CommitChecks>;
+
+export function GetCommitDetail(arg1:number,arg2:string,arg3:boolean):Promise<types.CommitDetail>;
+
+export function GetCommitImpact(arg1:number,arg2:string):Promise<types.CommitImpact>;
+
+export function GetCommitsForTicket(arg1:string):Promise<Array<types.TicketCommit>>;
+
 export function GetConfig(arg1:string):Promise<string>;
 
-export function GetDashboardStats():Promise<Record<string, any>>;
+export function GetConfigSchema():Promise<Array<types.ConfigKey>>;
+
+export function GetDashboardStats():Promise<types.DashboardStats>;
+
+export function GetDeploymentBlame(arg1:number,arg2:string,arg3:string):Promise<types.DeploymentBlame>;
+
+export function GetDeploymentDimensions():Promise<types.DeploymentDimensions>;
+
+export function GetDeploymentDrift(arg1:number):Promise<Array<types.DeploymentDrift>>;
+
+export function GetDeploymentFileDiff(arg1:number):Promise<types.DeploymentFileDiff>;
+
+export function GetEnvVarDiff(arg1:number,arg2:string,arg3:string):Promise<types.EnvVarDiff>;
+
+export function GetFavoriteRepositories():Promise<Array<types.Repository>>;
+
+export function GetFreezeViolations(arg1:number):Promise<Array<types.FreezeViolation>>;
+
+export function GetFreezeWindows():Promise<Array<types.FreezeWindow>>;
+
+export function GetImageRegistries():Promise<Array<types.ImageRegistryUsage>>;
+
+export function GetJob(arg1:number):Promise<types.Job>;
+
+export function GetJobs():Promise<Array<types.Job>>;
 
 export function GetKubernetesResourceActions(arg1:number,arg2:number):Promise<Array<types.Action>>;
 
 export function GetKubernetesResources(arg1:number):Promise<Array<types.KubernetesResource>>;
 
+export function GetLegacyDatabase():Promise<types.LegacyDatabase>;
+
 export function GetMicroserviceActions(arg1:number,arg2:number):Promise<Array<types.Action>>;
 
-export function GetMicroservices(arg1:number):Promise<Array<types.Microservice>>;
+export function GetMicroservices(arg1:number,arg2:boolean):Promise<Array<types.Microservice>>;
+
+export function GetMicroservicesGroupedByDomain(arg1:number,arg2:boolean):Promise<Array<types.ServiceDomainGroup>>;
+
+export function GetMicroservicesWithRepository(arg1:boolean):Promise<Array<types.MicroserviceWithRepository>>;
+
+export function GetNotifications(arg1:boolean,arg2:number):Promise<Array<types.Notification>>;
+
+export function GetPendingApprovals():Promise<Array<types.PendingApproval>>;
+
+export function GetPendingDiscoveryChangeCounts():Promise<Record<number, number>>;
+
+export function GetPendingDiscoveryChanges(arg1:number):Promise<Array<types.DiscoveryChange>>;
+
+export function GetPresentationMode():Promise<boolean>;
 
 export function GetProject(arg1:number):Promise<types.Project>;
 
@@ -43,20 +157,78 @@ export function GetProjects():Promise<Array<types.Project>>;
 
 export function GetRecentActions(arg1:number,arg2:number):Promise<Array<types.ActionWithDetails>>;
 
+export function GetRecentAnnotations(arg1:number):Promise<Array<types.Annotation>>;
+
 export function GetRepositories():Promise<Array<types.Repository>>;
 
+export function GetRepositorySensitivePaths(arg1:number):Promise<Array<string>>;
+
+export function GetRolloutProgress(arg1:number,arg2:string):Promise<types.RolloutProgress>;
+
+export function GetScorecardChecks():Promise<Array<types.ScorecardCheck>>;
+
+export function GetScorecardSummary():Promise<types.ScorecardSummary>;
+
+export function GetSecurityAlerts(arg1:number):Promise<Array<types.SecurityAlertReport>>;
+
 export function GetServiceCommitDeployments(arg1:number):Promise<Array<types.CommitDeploymentStatus>>;
 
 export function GetServiceCommits(arg1:number):Promise<Array<types.Commit>>;
 
+export function GetServiceCustomFields():Promise<Array<types.CustomFieldDefinition>>;
+
+export function GetServiceDeploymentCounts():Promise<Record<number, types.ServiceDeploymentCounts>>;
+
+export function GetServiceDeploymentEvents(arg1:number):Promise<Array<types.DeploymentHistoryEntry>>;
+
 export function GetServiceDeploymentHistory(arg1:number):Promise<Array<types.Commit>>;
 
+export function GetServiceDeploymentRollups(arg1:number):Promise<Array<types.DeploymentRollup>>;
+
 export function GetServiceDeployments(arg1:number):Promise<Array<types.DeploymentOverview>>;
 
+export function GetServiceDeploymentsByEnvironment(arg1:number,arg2:types.DeploymentOverviewFilter):Promise<Array<types.EnvironmentDeployments>>;
+
+export function GetServiceDetail(arg1:number,arg2:types.ServiceDetailOptions):Promise<types.ServiceDetail>;
+
+export function GetServiceLeadTime(arg1:number,arg2:time.Time):Promise<types.LeadTimeStats>;
+
+export function GetServicePackages(arg1:number):Promise<types.ServicePackages>;
+
+export function GetServicePrimaryEnvironment(arg1:number):Promise<string>;
+
 export function GetServicePullRequests(arg1:number):Promise<Array<types.PullRequest>>;
 
+export function GetServiceReliability(arg1:number,arg2:number):Promise<types.ServiceReliability>;
+
+export function GetServiceScorecard(arg1:number):Promise<types.ServiceScorecard>;
+
+export function GetServiceSensitivePaths(arg1:number):Promise<Array<string>>;
+
+export function GetServiceSummaries(arg1:types.ServiceSummaryFilter):Promise<Array<types.ServiceSummary>>;
+
+export function GetSetupStatus():Promise<types.SetupStatus>;
+
+export function GetSlowQueries():Promise<Array<types.SlowQuery>>;
+
+export function GetStartupError():Promise<types.StartupError>;
+
+export function GetStatsTrend(arg1:string,arg2:number):Promise<Array<types.StatsTrendPoint>>;
+
+export function GetSyncLogs(arg1:number,arg2:number):Promise<Array<types.SyncLog>>;
+
+export function GetSyncStatus():Promise<Array<types.RepositorySyncStatus>>;
+
+export function GetSystemStatus():Promise<types.SystemStatus>;
+
 export function GetTask(arg1:number):Promise<types.Task>;
 
+export function GetTaskChecklist(arg1:number):Promise<Array<types.TaskChecklistItem>>;
+
+export function GetTaskDetail(arg1:number):Promise<types.TaskDetail>;
+
+export function GetTaskJiraHistory(arg1:number):Promise<types.JiraStatusHistory>;
+
 export function GetTasks():Promise<Array<types.TaskWithProject>>;
 
 export function GetTasksByProject(arg1:number):Promise<Array<types.Task>>;
@@ -65,38 +237,116 @@ export function GetTasksGroupedByScheduledDate():Promise<Array<types.TaskWithPro
 
 export function GetTasksInDateRange(arg1:time.Time,arg2:time.Time):Promise<Array<types.TaskWithProject>>;
 
+export function GetTelemetrySummary():Promise<types.TelemetrySummary>;
+
+export function GetUsageInsights(arg1:number):Promise<types.UsageInsights>;
+
+export function GetWatchRuleEvaluations(arg1:number,arg2:number):Promise<Array<types.WatchEvaluation>>;
+
+export function GetWatchRules():Promise<Array<types.WatchRule>>;
+
+export function GetWebhookStatus(arg1:number):Promise<types.WebhookStatus>;
+
 export function Greet(arg1:string):Promise<string>;
 
+export function HideMicroservice(arg1:number):Promise<void>;
+
+export function ImportServiceCatalog(arg1:string):Promise<types.ServiceCatalogImport>;
+
+export function ImportSettings(arg1:string,arg2:boolean,arg3:string):Promise<types.SettingsImportResult>;
+
+export function InstallRepositoryWebhook(arg1:number,arg2:string):Promise<types.RepositoryWebhook>;
+
+export function IsFrozen(arg1:string,arg2:time.Time):Promise<types.FreezeWindow>;
+
+export function MarkNotificationRead(arg1:number):Promise<void>;
+
+export function MergeServices(arg1:number,arg2:number):Promise<types.ServiceMergeResult>;
+
+export function MigrateLegacyDatabase(arg1:string):Promise<void>;
+
+export function PreviewServiceCatalogImport(arg1:string):Promise<types.ServiceCatalogImport>;
+
+export function QuickAddTask(arg1:string):Promise<types.Task>;
+
+export function QuickCaptureTask(arg1:string):Promise<types.QuickCaptureResult>;
+
+export function RebuildServiceSummaries():Promise<void>;
+
+export function RediscoverAllServices():Promise<Array<types.ServiceRediscovery>>;
+
 export function RediscoverRepositoryServices(arg1:number,arg2:string,arg3:Record<string, any>):Promise<void>;
 
 export function RefreshAllJiraTitles():Promise<void>;
 
+export function RemoveRepositoryWebhook(arg1:number):Promise<void>;
+
+export function ReorderTaskChecklist(arg1:number,arg2:Array<number>):Promise<void>;
+
+export function RerunAction(arg1:number,arg2:boolean):Promise<void>;
+
+export function SaveEnvironmentComparisonReport(arg1:string,arg2:string,arg3:string):Promise<string>;
+
 export function SetConfig(arg1:string,arg2:string):Promise<void>;
 
-export function SyncRepository(arg1:number):Promise<void>;
+export function SetDeploymentActualTag(arg1:number,arg2:string):Promise<void>;
+
+export function SetPresentationMode(arg1:boolean):Promise<void>;
+
+export function SetRepositoryArchived(arg1:number,arg2:boolean):Promise<void>;
 
-export function TestCommitDeploymentCorrelation(arg1:number):Promise<string>;
+export function SetRepositoryCollectPackages(arg1:number,arg2:boolean):Promise<void>;
 
-export function TestDeploymentData():Promise<Record<string, any>>;
+export function SetRepositoryDiscoveryReview(arg1:number,arg2:boolean):Promise<void>;
+
+export function SetRepositoryManualSyncOnly(arg1:number,arg2:boolean):Promise<void>;
+
+export function SetRepositorySensitivePaths(arg1:number,arg2:Array<string>):Promise<void>;
+
+export function SetRepositoryWorkflowBranches(arg1:number,arg2:string):Promise<void>;
+
+export function SetScorecardCheck(arg1:string,arg2:boolean,arg3:number):Promise<void>;
+
+export function SetServiceCustomFieldValue(arg1:number,arg2:number,arg3:string):Promise<void>;
+
+export function SetServiceImageName(arg1:number,arg2:string):Promise<void>;
+
+export function SetServiceOwner(arg1:number,arg2:string):Promise<void>;
+
+export function SetServicePrimaryEnvironment(arg1:number,arg2:string):Promise<void>;
+
+export function SetServiceSensitivePaths(arg1:number,arg2:Array<string>):Promise<void>;
+
+export function SetWatchRuleEnabled(arg1:number,arg2:boolean):Promise<void>;
+
+export function StartJob(arg1:string,arg2:Record<string, any>):Promise<number>;
+
+export function SyncRepository(arg1:number):Promise<types.SyncOutcome>;
 
 export function TestGitHubConnection():Promise<void>;
 
 export function TestJiraConnection():Promise<void>;
 
-export function TestKustomizationFileAccess():Promise<Record<string, any>>;
+export function ToggleFavorite(arg1:number):Promise<boolean>;
 
-export function TestScanKubernetesDeployments():Promise<void>;
+export function ToggleTaskChecklistItem(arg1:number):Promise<types.TaskChecklistItem>;
 
-export function TestServiceCommitsFetch(arg1:number):Promise<string>;
+export function UnhideMicroservice(arg1:number):Promise<void>;
+
+export function UpdateFreezeWindow(arg1:types.FreezeWindow):Promise<void>;
 
 export function UpdateProject(arg1:types.Project):Promise<void>;
 
 export function UpdateRepository(arg1:types.Repository):Promise<void>;
 
+export function UpdateServiceCustomField(arg1:number,arg2:string,arg3:Array<string>):Promise<void>;
+
 export function UpdateTask(arg1:types.Task):Promise<void>;
 
 export function UpdateTaskJiraTitle(arg1:number,arg2:string):Promise<void>;
 
 export function UpdateTaskStatus(arg1:number,arg2:types.TaskStatus):Promise<void>;
 
-export function ValidateRepositoryAccess(arg1:string,arg2:string,arg3:Record<string, any>):Promise<Record<string, any>>;
+export function UpdateWatchRule(arg1:types.WatchRule):Promise<void>;
+
+export function ValidateRepositoryAccess(arg1:string,arg2:string,arg3:Record<string, any>):Promise<types.ValidationResult>;
@@ -2,6 +2,42 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function AcknowledgeJob(arg1) {
+  return window['go']['main']['App']['AcknowledgeJob'](arg1);
+}
+
+export function AddAnnotation(arg1, arg2, arg3) {
+  return window['go']['main']['App']['AddAnnotation'](arg1, arg2, arg3);
+}
+
+export function AddTaskChecklistItem(arg1, arg2) {
+  return window['go']['main']['App']['AddTaskChecklistItem'](arg1, arg2);
+}
+
+export function ApplyDiscoveryChanges(arg1, arg2) {
+  return window['go']['main']['App']['ApplyDiscoveryChanges'](arg1, arg2);
+}
+
+export function ApproveDeployment(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ApproveDeployment'](arg1, arg2, arg3);
+}
+
+export function CallInPresentationMode(arg1, arg2) {
+  return window['go']['main']['App']['CallInPresentationMode'](arg1, arg2);
+}
+
+export function CancelJob(arg1) {
+  return window['go']['main']['App']['CancelJob'](arg1);
+}
+
+export function ClearUsageData() {
+  return window['go']['main']['App']['ClearUsageData']();
+}
+
+export function CreateFreezeWindow(arg1) {
+  return window['go']['main']['App']['CreateFreezeWindow'](arg1);
+}
+
 export function CreateProject(arg1) {
   return window['go']['main']['App']['CreateProject'](arg1);
 }
@@ -14,6 +50,10 @@ export function CreateRepositoryWithAuth(arg1) {
   return window['go']['main']['App']['CreateRepositoryWithAuth'](arg1);
 }
 
+export function CreateServiceCustomField(arg1, arg2, arg3) {
+  return window['go']['main']['App']['CreateServiceCustomField'](arg1, arg2, arg3);
+}
+
 export function CreateTask(arg1) {
   return window['go']['main']['App']['CreateTask'](arg1);
 }
@@ -22,6 +62,18 @@ export function CreateTaskWithJiraTitle(arg1) {
   return window['go']['main']['App']['CreateTaskWithJiraTitle'](arg1);
 }
 
+export function CreateWatchRule(arg1) {
+  return window['go']['main']['App']['CreateWatchRule'](arg1);
+}
+
+export function DeleteAnnotation(arg1) {
+  return window['go']['main']['App']['DeleteAnnotation'](arg1);
+}
+
+export function DeleteFreezeWindow(arg1) {
+  return window['go']['main']['App']['DeleteFreezeWindow'](arg1);
+}
+
 export function DeleteProject(arg1) {
   return window['go']['main']['App']['DeleteProject'](arg1);
 }
@@ -30,30 +82,174 @@ export function DeleteRepository(arg1) {
   return window['go']['main']['App']['DeleteRepository'](arg1);
 }
 
+export function DeleteServiceCustomField(arg1) {
+  return window['go']['main']['App']['DeleteServiceCustomField'](arg1);
+}
+
 export function DeleteTask(arg1) {
   return window['go']['main']['App']['DeleteTask'](arg1);
 }
 
+export function DeleteTaskChecklistItem(arg1) {
+  return window['go']['main']['App']['DeleteTaskChecklistItem'](arg1);
+}
+
+export function DeleteWatchRule(arg1) {
+  return window['go']['main']['App']['DeleteWatchRule'](arg1);
+}
+
+export function DiagnoseDeploymentScan(arg1) {
+  return window['go']['main']['App']['DiagnoseDeploymentScan'](arg1);
+}
+
 export function DiscoverRepositoryServices(arg1, arg2, arg3, arg4) {
   return window['go']['main']['App']['DiscoverRepositoryServices'](arg1, arg2, arg3, arg4);
 }
 
+export function DownloadActionArtifact(arg1, arg2, arg3) {
+  return window['go']['main']['App']['DownloadActionArtifact'](arg1, arg2, arg3);
+}
+
+export function EvaluateWatchRules() {
+  return window['go']['main']['App']['EvaluateWatchRules']();
+}
+
+export function ExportServiceCatalog(arg1, arg2) {
+  return window['go']['main']['App']['ExportServiceCatalog'](arg1, arg2);
+}
+
+export function ExportSettings(arg1) {
+  return window['go']['main']['App']['ExportSettings'](arg1);
+}
+
+export function ExportUsageData() {
+  return window['go']['main']['App']['ExportUsageData']();
+}
+
 export function FetchJiraTicketTitle(arg1) {
   return window['go']['main']['App']['FetchJiraTicketTitle'](arg1);
 }
 
+export function FilterMicroservices(arg1, arg2, arg3) {
+  return window['go']['main']['App']['FilterMicroservices'](arg1, arg2, arg3);
+}
+
+export function FlushTelemetry() {
+  return window['go']['main']['App']['FlushTelemetry']();
+}
+
+export function GenerateEnvironmentComparisonReport(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GenerateEnvironmentComparisonReport'](arg1, arg2, arg3);
+}
+
+export function GenerateServiceReport(arg1, arg2) {
+  return window['go']['main']['App']['GenerateServiceReport'](arg1, arg2);
+}
+
+export function GetAccessReport() {
+  return window['go']['main']['App']['GetAccessReport']();
+}
+
+export function GetActionArtifacts(arg1) {
+  return window['go']['main']['App']['GetActionArtifacts'](arg1);
+}
+
+export function GetActionsMinutesUsage(arg1) {
+  return window['go']['main']['App']['GetActionsMinutesUsage'](arg1);
+}
+
+export function GetActivityFeed(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['GetActivityFeed'](arg1, arg2, arg3, arg4);
+}
+
 export function GetAllConfig() {
   return window['go']['main']['App']['GetAllConfig']();
 }
 
+export function GetAnnotations(arg1, arg2) {
+  return window['go']['main']['App']['GetAnnotations'](arg1, arg2);
+}
+
+export function GetAuditLog(arg1) {
+  return window['go']['main']['App']['GetAuditLog'](arg1);
+}
+
+export function GetBuildMatrix(arg1) {
+  return window['go']['main']['App']['GetBuildMatrix'](arg1);
+}
+
+export function GetCommitChecks(arg1, arg2) {
+  return window['go']['main']['App']['GetCommitChecks'](arg1, arg2);
+}
+
+export function GetCommitDetail(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GetCommitDetail'](arg1, arg2, arg3);
+}
+
+export function GetCommitImpact(arg1, arg2) {
+  return window['go']['main']['App']['GetCommitImpact'](arg1, arg2);
+}
+
+export function GetCommitsForTicket(arg1) {
+  return window['go']['main']['App']['GetCommitsForTicket'](arg1);
+}
+
 export function GetConfig(arg1) {
   return window['go']['main']['App']['GetConfig'](arg1);
 }
 
+export function GetConfigSchema() {
+  return window['go']['main']['App']['GetConfigSchema']();
+}
+
 export function GetDashboardStats() {
   return window['go']['main']['App']['GetDashboardStats']();
 }
 
+export function GetDeploymentBlame(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GetDeploymentBlame'](arg1, arg2, arg3);
+}
+
+export function GetDeploymentDimensions() {
+  return window['go']['main']['App']['GetDeploymentDimensions']();
+}
+
+export function GetDeploymentDrift(arg1) {
+  return window['go']['main']['App']['GetDeploymentDrift'](arg1);
+}
+
+export function GetDeploymentFileDiff(arg1) {
+  return window['go']['main']['App']['GetDeploymentFileDiff'](arg1);
+}
+
+export function GetEnvVarDiff(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GetEnvVarDiff'](arg1, arg2, arg3);
+}
+
+export function GetFavoriteRepositories() {
+  return window['go']['main']['App']['GetFavoriteRepositories']();
+}
+
+export function GetFreezeViolations(arg1) {
+  return window['go']['main']['App']['GetFreezeViolations'](arg1);
+}
+
+export function GetFreezeWindows() {
+  return window['go']['main']['App']['GetFreezeWindows']();
+}
+
+export function GetImageRegistries() {
+  return window['go']['main']['App']['GetImageRegistries']();
+}
+
+export function GetJob(arg1) {
+  return window['go']['main']['App']['GetJob'](arg1);
+}
+
+export function GetJobs() {
+  return window['go']['main']['App']['GetJobs']();
+}
+
 export function GetKubernetesResourceActions(arg1, arg2) {
   return window['go']['main']['App']['GetKubernetesResourceActions'](arg1, arg2);
 }
@@ -62,12 +258,44 @@ export function GetKubernetesResources(arg1) {
   return window['go']['main']['App']['GetKubernetesResources'](arg1);
 }
 
+export function GetLegacyDatabase() {
+  return window['go']['main']['App']['GetLegacyDatabase']();
+}
+
 export function GetMicroserviceActions(arg1, arg2) {
   return window['go']['main']['App']['GetMicroserviceActions'](arg1, arg2);
 }
 
-export function GetMicroservices(arg1) {
-  return window['go']['main']['App']['GetMicroservices'](arg1);
+export function GetMicroservices(arg1, arg2) {
+  return window['go']['main']['App']['GetMicroservices'](arg1, arg2);
+}
+
+export function GetMicroservicesGroupedByDomain(arg1, arg2) {
+  return window['go']['main']['App']['GetMicroservicesGroupedByDomain'](arg1, arg2);
+}
+
+export function GetMicroservicesWithRepository(arg1) {
+  return window['go']['main']['App']['GetMicroservicesWithRepository'](arg1);
+}
+
+export function GetNotifications(arg1, arg2) {
+  return window['go']['main']['App']['GetNotifications'](arg1, arg2);
+}
+
+export function GetPendingApprovals() {
+  return window['go']['main']['App']['GetPendingApprovals']();
+}
+
+export function GetPendingDiscoveryChangeCounts() {
+  return window['go']['main']['App']['GetPendingDiscoveryChangeCounts']();
+}
+
+export function GetPendingDiscoveryChanges(arg1) {
+  return window['go']['main']['App']['GetPendingDiscoveryChanges'](arg1);
+}
+
+export function GetPresentationMode() {
+  return window['go']['main']['App']['GetPresentationMode']();
 }
 
 export function GetProject(arg1) {
@@ -82,10 +310,34 @@ export function GetRecentActions(arg1, arg2) {
   return window['go']['main']['App']['GetRecentActions'](arg1, arg2);
 }
 
+export function GetRecentAnnotations(arg1) {
+  return window['go']['main']['App']['GetRecentAnnotations'](arg1);
+}
+
 export function GetRepositories() {
   return window['go']['main']['App']['GetRepositories']();
 }
 
+export function GetRepositorySensitivePaths(arg1) {
+  return window['go']['main']['App']['GetRepositorySensitivePaths'](arg1);
+}
+
+export function GetRolloutProgress(arg1, arg2) {
+  return window['go']['main']['App']['GetRolloutProgress'](arg1, arg2);
+}
+
+export function GetScorecardChecks() {
+  return window['go']['main']['App']['GetScorecardChecks']();
+}
+
+export function GetScorecardSummary() {
+  return window['go']['main']['App']['GetScorecardSummary']();
+}
+
+export function GetSecurityAlerts(arg1) {
+  return window['go']['main']['App']['GetSecurityAlerts'](arg1);
+}
+
 export function GetServiceCommitDeployments(arg1) {
   return window['go']['main']['App']['GetServiceCommitDeployments'](arg1);
 }
@@ -94,22 +346,114 @@ export function GetServiceCommits(arg1) {
   return window['go']['main']['App']['GetServiceCommits'](arg1);
 }
 
+export function GetServiceCustomFields() {
+  return window['go']['main']['App']['GetServiceCustomFields']();
+}
+
+export function GetServiceDeploymentCounts() {
+  return window['go']['main']['App']['GetServiceDeploymentCounts']();
+}
+
+export function GetServiceDeploymentEvents(arg1) {
+  return window['go']['main']['App']['GetServiceDeploymentEvents'](arg1);
+}
+
 export function GetServiceDeploymentHistory(arg1) {
   return window['go']['main']['App']['GetServiceDeploymentHistory'](arg1);
 }
 
+export function GetServiceDeploymentRollups(arg1) {
+  return window['go']['main']['App']['GetServiceDeploymentRollups'](arg1);
+}
+
 export function GetServiceDeployments(arg1) {
   return window['go']['main']['App']['GetServiceDeployments'](arg1);
 }
 
+export function GetServiceDeploymentsByEnvironment(arg1, arg2) {
+  return window['go']['main']['App']['GetServiceDeploymentsByEnvironment'](arg1, arg2);
+}
+
+export function GetServiceDetail(arg1, arg2) {
+  return window['go']['main']['App']['GetServiceDetail'](arg1, arg2);
+}
+
+export function GetServiceLeadTime(arg1, arg2) {
+  return window['go']['main']['App']['GetServiceLeadTime'](arg1, arg2);
+}
+
+export function GetServicePackages(arg1) {
+  return window['go']['main']['App']['GetServicePackages'](arg1);
+}
+
+export function GetServicePrimaryEnvironment(arg1) {
+  return window['go']['main']['App']['GetServicePrimaryEnvironment'](arg1);
+}
+
 export function GetServicePullRequests(arg1) {
   return window['go']['main']['App']['GetServicePullRequests'](arg1);
 }
 
+export function GetServiceReliability(arg1, arg2) {
+  return window['go']['main']['App']['GetServiceReliability'](arg1, arg2);
+}
+
+export function GetServiceScorecard(arg1) {
+  return window['go']['main']['App']['GetServiceScorecard'](arg1);
+}
+
+export function GetServiceSensitivePaths(arg1) {
+  return window['go']['main']['App']['GetServiceSensitivePaths'](arg1);
+}
+
+export function GetServiceSummaries(arg1) {
+  return window['go']['main']['App']['GetServiceSummaries'](arg1);
+}
+
+export function GetSetupStatus() {
+  return window['go']['main']['App']['GetSetupStatus']();
+}
+
+export function GetSlowQueries() {
+  return window['go']['main']['App']['GetSlowQueries']();
+}
+
+export function GetStartupError() {
+  return window['go']['main']['App']['GetStartupError']();
+}
+
+export function GetStatsTrend(arg1, arg2) {
+  return window['go']['main']['App']['GetStatsTrend'](arg1, arg2);
+}
+
+export function GetSyncLogs(arg1, arg2) {
+  return window['go']['main']['App']['GetSyncLogs'](arg1, arg2);
+}
+
+export function GetSyncStatus() {
+  return window['go']['main']['App']['GetSyncStatus']();
+}
+
+export function GetSystemStatus() {
+  return window['go']['main']['App']['GetSystemStatus']();
+}
+
 export function GetTask(arg1) {
   return window['go']['main']['App']['GetTask'](arg1);
 }
 
+export function GetTaskChecklist(arg1) {
+  return window['go']['main']['App']['GetTaskChecklist'](arg1);
+}
+
+export function GetTaskDetail(arg1) {
+  return window['go']['main']['App']['GetTaskDetail'](arg1);
+}
+
+export function GetTaskJiraHistory(arg1) {
+  return window['go']['main']['App']['GetTaskJiraHistory'](arg1);
+}
+
 export function GetTasks() {
   return window['go']['main']['App']['GetTasks']();
 }
@@ -126,10 +470,82 @@ export function GetTasksInDateRange(arg1, arg2) {
   return window['go']['main']['App']['GetTasksInDateRange'](arg1, arg2);
 }
 
+export function GetTelemetrySummary() {
+  return window['go']['main']['App']['GetTelemetrySummary']();
+}
+
+export function GetUsageInsights(arg1) {
+  return window['go']['main']['App']['GetUsageInsights'](arg1);
+}
+
+export function GetWatchRuleEvaluations(arg1, arg2) {
+  return window['go']['main']['App']['GetWatchRuleEvaluations'](arg1, arg2);
+}
+
+export function GetWatchRules() {
+  return window['go']['main']['App']['GetWatchRules']();
+}
+
+export function GetWebhookStatus(arg1) {
+  return window['go']['main']['App']['GetWebhookStatus'](arg1);
+}
+
 export function Greet(arg1) {
   return window['go']['main']['App']['Greet'](arg1);
 }
 
+export function HideMicroservice(arg1) {
+  return window['go']['main']['App']['HideMicroservice'](arg1);
+}
+
+export function ImportServiceCatalog(arg1) {
+  return window['go']['main']['App']['ImportServiceCatalog'](arg1);
+}
+
+export function ImportSettings(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ImportSettings'](arg1, arg2, arg3);
+}
+
+export function InstallRepositoryWebhook(arg1, arg2) {
+  return window['go']['main']['App']['InstallRepositoryWebhook'](arg1, arg2);
+}
+
+export function IsFrozen(arg1, arg2) {
+  return window['go']['main']['App']['IsFrozen'](arg1, arg2);
+}
+
+export function MarkNotificationRead(arg1) {
+  return window['go']['main']['App']['MarkNotificationRead'](arg1);
+}
+
+export function MergeServices(arg1, arg2) {
+  return window['go']['main']['App']['MergeServices'](arg1, arg2);
+}
+
+export function MigrateLegacyDatabase(arg1) {
+  return window['go']['main']['App']['MigrateLegacyDatabase'](arg1);
+}
+
+export function PreviewServiceCatalogImport(arg1) {
+  return window['go']['main']['App']['PreviewServiceCatalogImport'](arg1);
+}
+
+export function QuickAddTask(arg1) {
+  return window['go']['main']['App']['QuickAddTask'](arg1);
+}
+
+export function QuickCaptureTask(arg1) {
+  return window['go']['main']['App']['QuickCaptureTask'](arg1);
+}
+
+export function RebuildServiceSummaries() {
+  return window['go']['main']['App']['RebuildServiceSummaries']();
+}
+
+export function RediscoverAllServices() {
+  return window['go']['main']['App']['RediscoverAllServices']();
+}
+
 export function RediscoverRepositoryServices(arg1, arg2, arg3) {
   return window['go']['main']['App']['RediscoverRepositoryServices'](arg1, arg2, arg3);
 }
@@ -138,20 +554,92 @@ export function RefreshAllJiraTitles() {
   return window['go']['main']['App']['RefreshAllJiraTitles']();
 }
 
+export function RemoveRepositoryWebhook(arg1) {
+  return window['go']['main']['App']['RemoveRepositoryWebhook'](arg1);
+}
+
+export function ReorderTaskChecklist(arg1, arg2) {
+  return window['go']['main']['App']['ReorderTaskChecklist'](arg1, arg2);
+}
+
+export function RerunAction(arg1, arg2) {
+  return window['go']['main']['App']['RerunAction'](arg1, arg2);
+}
+
+export function SaveEnvironmentComparisonReport(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SaveEnvironmentComparisonReport'](arg1, arg2, arg3);
+}
+
 export function SetConfig(arg1, arg2) {
   return window['go']['main']['App']['SetConfig'](arg1, arg2);
 }
 
-export function SyncRepository(arg1) {
-  return window['go']['main']['App']['SyncRepository'](arg1);
+export function SetDeploymentActualTag(arg1, arg2) {
+  return window['go']['main']['App']['SetDeploymentActualTag'](arg1, arg2);
+}
+
+export function SetPresentationMode(arg1) {
+  return window['go']['main']['App']['SetPresentationMode'](arg1);
+}
+
+export function SetRepositoryArchived(arg1, arg2) {
+  return window['go']['main']['App']['SetRepositoryArchived'](arg1, arg2);
 }
 
-export function TestCommitDeploymentCorrelation(arg1) {
-  return window['go']['main']['App']['TestCommitDeploymentCorrelation'](arg1);
+export function SetRepositoryCollectPackages(arg1, arg2) {
+  return window['go']['main']['App']['SetRepositoryCollectPackages'](arg1, arg2);
 }
 
-export function TestDeploymentData() {
-  return window['go']['main']['App']['TestDeploymentData']();
+export function SetRepositoryDiscoveryReview(arg1, arg2) {
+  return window['go']['main']['App']['SetRepositoryDiscoveryReview'](arg1, arg2);
+}
+
+export function SetRepositoryManualSyncOnly(arg1, arg2) {
+  return window['go']['main']['App']['SetRepositoryManualSyncOnly'](arg1, arg2);
+}
+
+export function SetRepositorySensitivePaths(arg1, arg2) {
+  return window['go']['main']['App']['SetRepositorySensitivePaths'](arg1, arg2);
+}
+
+export function SetRepositoryWorkflowBranches(arg1, arg2) {
+  return window['go']['main']['App']['SetRepositoryWorkflowBranches'](arg1, arg2);
+}
+
+export function SetScorecardCheck(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SetScorecardCheck'](arg1, arg2, arg3);
+}
+
+export function SetServiceCustomFieldValue(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SetServiceCustomFieldValue'](arg1, arg2, arg3);
+}
+
+export function SetServiceImageName(arg1, arg2) {
+  return window['go']['main']['App']['SetServiceImageName'](arg1, arg2);
+}
+
+export function SetServiceOwner(arg1, arg2) {
+  return window['go']['main']['App']['SetServiceOwner'](arg1, arg2);
+}
+
+export function SetServicePrimaryEnvironment(arg1, arg2) {
+  return window['go']['main']['App']['SetServicePrimaryEnvironment'](arg1, arg2);
+}
+
+export function SetServiceSensitivePaths(arg1, arg2) {
+  return window['go']['main']['App']['SetServiceSensitivePaths'](arg1, arg2);
+}
+
+export function SetWatchRuleEnabled(arg1, arg2) {
+  return window['go']['main']['App']['SetWatchRuleEnabled'](arg1, arg2);
+}
+
+export function StartJob(arg1, arg2) {
+  return window['go']['main']['App']['StartJob'](arg1, arg2);
+}
+
+export function SyncRepository(arg1) {
+  return window['go']['main']['App']['SyncRepository'](arg1);
 }
 
 export function TestGitHubConnection() {
@@ -162,16 +650,20 @@ export function TestJiraConnection() {
   return window['go']['main']['App']['TestJiraConnection']();
 }
 
-export function TestKustomizationFileAccess() {
-  return window['go']['main']['App']['TestKustomizationFileAccess']();
+export function ToggleFavorite(arg1) {
+  return window['go']['main']['App']['ToggleFavorite'](arg1);
+}
+
+export function ToggleTaskChecklistItem(arg1) {
+  return window['go']['main']['App']['ToggleTaskChecklistItem'](arg1);
 }
 
-export function TestScanKubernetesDeployments() {
-  return window['go']['main']['App']['TestScanKubernetesDeployments']();
+export function UnhideMicroservice(arg1) {
+  return window['go']['main']['App']['UnhideMicroservice'](arg1);
 }
 
-export function TestServiceCommitsFetch(arg1) {
-  return window['go']['main']['App']['TestServiceCommitsFetch'](arg1);
+export function UpdateFreezeWindow(arg1) {
+  return window['go']['main']['App']['UpdateFreezeWindow'](arg1);
 }
 
 export function UpdateProject(arg1) {
@@ -182,6 +674,10 @@ export function UpdateRepository(arg1) {
   return window['go']['main']['App']['UpdateRepository'](arg1);
 }
 
+export function UpdateServiceCustomField(arg1, arg2, arg3) {
+  return window['go']['main']['App']['UpdateServiceCustomField'](arg1, arg2, arg3);
+}
+
 export function UpdateTask(arg1) {
   return window['go']['main']['App']['UpdateTask'](arg1);
 }
@@ -194,6 +690,10 @@ export function UpdateTaskStatus(arg1, arg2) {
   return window['go']['main']['App']['UpdateTaskStatus'](arg1, arg2);
 }
 
+export function UpdateWatchRule(arg1) {
+  return window['go']['main']['App']['UpdateWatchRule'](arg1);
+}
+
 export function ValidateRepositoryAccess(arg1, arg2, arg3) {
   return window['go']['main']['App']['ValidateRepositoryAccess'](arg1, arg2, arg3);
 }
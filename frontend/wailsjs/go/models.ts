@@ -24,6 +24,7 @@ export namespace types {
 	    resource_id?: number;
 	    type: string;
 	    status: string;
+	    conclusion: string;
 	    workflow_run_id: number;
 	    commit: string;
 	    branch: string;
@@ -32,6 +33,8 @@ export namespace types {
 	    completed_at?: time.Time;
 	    created_at: time.Time;
 	    updated_at: time.Time;
+	    started_at_relative?: string;
+	    completed_at_relative?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new Action(source);
@@ -45,6 +48,7 @@ export namespace types {
 	        this.resource_id = source["resource_id"];
 	        this.type = source["type"];
 	        this.status = source["status"];
+	        this.conclusion = source["conclusion"];
 	        this.workflow_run_id = source["workflow_run_id"];
 	        this.commit = source["commit"];
 	        this.branch = source["branch"];
@@ -53,6 +57,8 @@ export namespace types {
 	        this.completed_at = this.convertValues(source["completed_at"], time.Time);
 	        this.created_at = this.convertValues(source["created_at"], time.Time);
 	        this.updated_at = this.convertValues(source["updated_at"], time.Time);
+	        this.started_at_relative = source["started_at_relative"];
+	        this.completed_at_relative = source["completed_at_relative"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -73,6 +79,76 @@ export namespace types {
 		    return a;
 		}
 	}
+	export class ActionArtifact {
+	    id: number;
+	    name: string;
+	    size_bytes: number;
+	    expired: boolean;
+	    created_at?: time.Time;
+	    expires_at?: time.Time;
+	    too_large: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ActionArtifact(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.size_bytes = source["size_bytes"];
+	        this.expired = source["expired"];
+	        this.created_at = this.convertValues(source["created_at"], time.Time);
+	        this.expires_at = this.convertValues(source["expires_at"], time.Time);
+	        this.too_large = source["too_large"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ActionReliability {
+	    type: string;
+	    runs: number;
+	    succeeded: number;
+	    failed: number;
+	    cancelled: number;
+	    success_rate?: number;
+	    current_streak: number;
+	    current_streak_outcome?: string;
+	    mean_time_between_failures_seconds?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ActionReliability(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.type = source["type"];
+	        this.runs = source["runs"];
+	        this.succeeded = source["succeeded"];
+	        this.failed = source["failed"];
+	        this.cancelled = source["cancelled"];
+	        this.success_rate = source["success_rate"];
+	        this.current_streak = source["current_streak"];
+	        this.current_streak_outcome = source["current_streak_outcome"];
+	        this.mean_time_between_failures_seconds = source["mean_time_between_failures_seconds"];
+	    }
+	}
 	export class ActionWithDetails {
 	    id: number;
 	    repository_id: number;
@@ -80,6 +156,7 @@ export namespace types {
 	    resource_id?: number;
 	    type: string;
 	    status: string;
+	    conclusion: string;
 	    workflow_run_id: number;
 	    commit: string;
 	    branch: string;
@@ -88,6 +165,8 @@ export namespace types {
 	    completed_at?: time.Time;
 	    created_at: time.Time;
 	    updated_at: time.Time;
+	    started_at_relative?: string;
+	    completed_at_relative?: string;
 	    service_name?: string;
 	    resource_name?: string;
 	
@@ -103,6 +182,7 @@ export namespace types {
 	        this.resource_id = source["resource_id"];
 	        this.type = source["type"];
 	        this.status = source["status"];
+	        this.conclusion = source["conclusion"];
 	        this.workflow_run_id = source["workflow_run_id"];
 	        this.commit = source["commit"];
 	        this.branch = source["branch"];
@@ -111,6 +191,8 @@ export namespace types {
 	        this.completed_at = this.convertValues(source["completed_at"], time.Time);
 	        this.created_at = this.convertValues(source["created_at"], time.Time);
 	        this.updated_at = this.convertValues(source["updated_at"], time.Time);
+	        this.started_at_relative = source["started_at_relative"];
+	        this.completed_at_relative = source["completed_at_relative"];
 	        this.service_name = source["service_name"];
 	        this.resource_name = source["resource_name"];
 	    }
@@ -133,22 +215,22 @@ export namespace types {
 		    return a;
 		}
 	}
-	export class Commit {
-	    hash: string;
-	    message: string;
-	    author: string;
-	    date: time.Time;
+	export class SectionStatus {
+	    requested: boolean;
+	    stale: boolean;
+	    error?: string;
+	    fetched_at?: time.Time;
 	
 	    static createFrom(source: any = {}) {
-	        return new Commit(source);
+	        return new SectionStatus(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.hash = source["hash"];
-	        this.message = source["message"];
-	        this.author = source["author"];
-	        this.date = this.convertValues(source["date"], time.Time);
+	        this.requested = source["requested"];
+	        this.stale = source["stale"];
+	        this.error = source["error"];
+	        this.fetched_at = this.convertValues(source["fetched_at"], time.Time);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -169,26 +251,18 @@ export namespace types {
 		    return a;
 		}
 	}
-	export class DeploymentStatus {
-	    environment: string;
-	    region: string;
-	    namespace: string;
-	    tag: string;
-	    is_deployed: boolean;
-	    deployed_at: time.Time;
+	export class ActionsSection {
+	    data: Action[];
+	    status: SectionStatus;
 	
 	    static createFrom(source: any = {}) {
-	        return new DeploymentStatus(source);
+	        return new ActionsSection(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.environment = source["environment"];
-	        this.region = source["region"];
-	        this.namespace = source["namespace"];
-	        this.tag = source["tag"];
-	        this.is_deployed = source["is_deployed"];
-	        this.deployed_at = this.convertValues(source["deployed_at"], time.Time);
+	        this.data = this.convertValues(source["data"], Action);
+	        this.status = this.convertValues(source["status"], SectionStatus);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -209,18 +283,50 @@ export namespace types {
 		    return a;
 		}
 	}
-	export class CommitDeploymentStatus {
-	    commit: Commit;
-	    deployments: DeploymentStatus[];
+	export class ActionsUsageBreakdown {
+	    repository_id: number;
+	    repository_name: string;
+	    workflow_name?: string;
+	    runs: number;
+	    billable_minutes: number;
+	    weighted_minutes: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new CommitDeploymentStatus(source);
+	        return new ActionsUsageBreakdown(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.commit = this.convertValues(source["commit"], Commit);
-	        this.deployments = this.convertValues(source["deployments"], DeploymentStatus);
+	        this.repository_id = source["repository_id"];
+	        this.repository_name = source["repository_name"];
+	        this.workflow_name = source["workflow_name"];
+	        this.runs = source["runs"];
+	        this.billable_minutes = source["billable_minutes"];
+	        this.weighted_minutes = source["weighted_minutes"];
+	    }
+	}
+	export class ActionsUsageSummary {
+	    days: number;
+	    since: time.Time;
+	    runs: number;
+	    billable_minutes: number;
+	    weighted_minutes: number;
+	    repositories: ActionsUsageBreakdown[];
+	    top_workflows: ActionsUsageBreakdown[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ActionsUsageSummary(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.days = source["days"];
+	        this.since = this.convertValues(source["since"], time.Time);
+	        this.runs = source["runs"];
+	        this.billable_minutes = source["billable_minutes"];
+	        this.weighted_minutes = source["weighted_minutes"];
+	        this.repositories = this.convertValues(source["repositories"], ActionsUsageBreakdown);
+	        this.top_workflows = this.convertValues(source["top_workflows"], ActionsUsageBreakdown);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -241,28 +347,20 @@ export namespace types {
 		    return a;
 		}
 	}
-	export class DeploymentOverview {
-	    commit_sha: string;
-	    environment: string;
-	    region: string;
-	    namespace: string;
-	    tag: string;
-	    updated_at: time.Time;
-	    kubernetes_repo_name: string;
+	export class ActivityGroup {
+	    key: string;
+	    label: string;
+	    actions: ActionWithDetails[];
 	
 	    static createFrom(source: any = {}) {
-	        return new DeploymentOverview(source);
+	        return new ActivityGroup(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.commit_sha = source["commit_sha"];
-	        this.environment = source["environment"];
-	        this.region = source["region"];
-	        this.namespace = source["namespace"];
-	        this.tag = source["tag"];
-	        this.updated_at = this.convertValues(source["updated_at"], time.Time);
-	        this.kubernetes_repo_name = source["kubernetes_repo_name"];
+	        this.key = source["key"];
+	        this.label = source["label"];
+	        this.actions = this.convertValues(source["actions"], ActionWithDetails);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -283,31 +381,20 @@ export namespace types {
 		    return a;
 		}
 	}
-	
-	export class KubernetesResource {
-	    id: number;
-	    repository_id: number;
-	    name: string;
-	    path: string;
-	    resource_type: string;
-	    namespace: string;
-	    created_at: time.Time;
-	    updated_at: time.Time;
+	export class ActivityFeed {
+	    group_by: string;
+	    actions: ActionWithDetails[];
+	    groups: ActivityGroup[];
 	
 	    static createFrom(source: any = {}) {
-	        return new KubernetesResource(source);
+	        return new ActivityFeed(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.id = source["id"];
-	        this.repository_id = source["repository_id"];
-	        this.name = source["name"];
-	        this.path = source["path"];
-	        this.resource_type = source["resource_type"];
-	        this.namespace = source["namespace"];
-	        this.created_at = this.convertValues(source["created_at"], time.Time);
-	        this.updated_at = this.convertValues(source["updated_at"], time.Time);
+	        this.group_by = source["group_by"];
+	        this.actions = this.convertValues(source["actions"], ActionWithDetails);
+	        this.groups = this.convertValues(source["groups"], ActivityGroup);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -328,28 +415,32 @@ export namespace types {
 		    return a;
 		}
 	}
-	export class Microservice {
+	export class Annotation {
 	    id: number;
-	    repository_id: number;
-	    name: string;
-	    path: string;
-	    description: string;
+	    entity_type: string;
+	    entity_id: string;
+	    text: string;
 	    created_at: time.Time;
-	    updated_at: time.Time;
+	    created_at_relative?: string;
+	    service_id?: number;
+	    service_name?: string;
+	    subject?: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new Microservice(source);
+	        return new Annotation(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.id = source["id"];
-	        this.repository_id = source["repository_id"];
-	        this.name = source["name"];
-	        this.path = source["path"];
-	        this.description = source["description"];
+	        this.entity_type = source["entity_type"];
+	        this.entity_id = source["entity_id"];
+	        this.text = source["text"];
 	        this.created_at = this.convertValues(source["created_at"], time.Time);
-	        this.updated_at = this.convertValues(source["updated_at"], time.Time);
+	        this.created_at_relative = source["created_at_relative"];
+	        this.service_id = source["service_id"];
+	        this.service_name = source["service_name"];
+	        this.subject = source["subject"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -370,24 +461,26 @@ export namespace types {
 		    return a;
 		}
 	}
-	export class Project {
+	export class AuditEntry {
 	    id: number;
-	    name: string;
-	    description: string;
+	    action: string;
+	    repository_id?: number;
+	    detail: string;
+	    success: boolean;
 	    created_at: time.Time;
-	    updated_at: time.Time;
 	
 	    static createFrom(source: any = {}) {
-	        return new Project(source);
+	        return new AuditEntry(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.id = source["id"];
-	        this.name = source["name"];
-	        this.description = source["description"];
+	        this.action = source["action"];
+	        this.repository_id = source["repository_id"];
+	        this.detail = source["detail"];
+	        this.success = source["success"];
 	        this.created_at = this.convertValues(source["created_at"], time.Time);
-	        this.updated_at = this.convertValues(source["updated_at"], time.Time);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -408,28 +501,38 @@ export namespace types {
 		    return a;
 		}
 	}
-	export class PullRequest {
-	    id: number;
-	    number: number;
-	    title: string;
-	    status: string;
-	    author: string;
+	export class BuildMatrixEntry {
+	    service_id: number;
+	    service_name: string;
+	    repository_id: number;
+	    repository_name: string;
 	    branch: string;
-	    created_at: time.Time;
+	    status: string;
+	    conclusion: string;
+	    workflow_run_id?: number;
+	    commit?: string;
+	    started_at?: time.Time;
+	    completed_at?: time.Time;
+	    duration_seconds?: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new PullRequest(source);
+	        return new BuildMatrixEntry(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.id = source["id"];
-	        this.number = source["number"];
-	        this.title = source["title"];
-	        this.status = source["status"];
-	        this.author = source["author"];
+	        this.service_id = source["service_id"];
+	        this.service_name = source["service_name"];
+	        this.repository_id = source["repository_id"];
+	        this.repository_name = source["repository_name"];
 	        this.branch = source["branch"];
-	        this.created_at = this.convertValues(source["created_at"], time.Time);
+	        this.status = source["status"];
+	        this.conclusion = source["conclusion"];
+	        this.workflow_run_id = source["workflow_run_id"];
+	        this.commit = source["commit"];
+	        this.started_at = this.convertValues(source["started_at"], time.Time);
+	        this.completed_at = this.convertValues(source["completed_at"], time.Time);
+	        this.duration_seconds = source["duration_seconds"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -450,34 +553,42 @@ export namespace types {
 		    return a;
 		}
 	}
-	export class Repository {
-	    id: number;
-	    name: string;
-	    url: string;
-	    type: string;
-	    description: string;
-	    service_name?: string;
-	    service_location?: string;
-	    created_at: time.Time;
-	    updated_at: time.Time;
-	    last_sync_at?: time.Time;
+	export class BuildMatrixRollup {
+	    overall: string;
+	    total: number;
+	    success: number;
+	    failure: number;
+	    cancelled: number;
+	    running: number;
+	    no_data: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new Repository(source);
+	        return new BuildMatrixRollup(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.id = source["id"];
-	        this.name = source["name"];
-	        this.url = source["url"];
-	        this.type = source["type"];
-	        this.description = source["description"];
-	        this.service_name = source["service_name"];
-	        this.service_location = source["service_location"];
-	        this.created_at = this.convertValues(source["created_at"], time.Time);
-	        this.updated_at = this.convertValues(source["updated_at"], time.Time);
-	        this.last_sync_at = this.convertValues(source["last_sync_at"], time.Time);
+	        this.overall = source["overall"];
+	        this.total = source["total"];
+	        this.success = source["success"];
+	        this.failure = source["failure"];
+	        this.cancelled = source["cancelled"];
+	        this.running = source["running"];
+	        this.no_data = source["no_data"];
+	    }
+	}
+	export class BuildMatrix {
+	    entries: BuildMatrixEntry[];
+	    rollup: BuildMatrixRollup;
+	
+	    static createFrom(source: any = {}) {
+	        return new BuildMatrix(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.entries = this.convertValues(source["entries"], BuildMatrixEntry);
+	        this.rollup = this.convertValues(source["rollup"], BuildMatrixRollup);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -498,36 +609,44 @@ export namespace types {
 		    return a;
 		}
 	}
-	export class Task {
-	    id: number;
-	    project_id: number;
-	    jira_ticket_id: string;
-	    jira_title: string;
-	    title: string;
-	    description: string;
-	    scheduled_date?: time.Time;
-	    deadline?: time.Time;
-	    status: string;
-	    created_at: time.Time;
-	    updated_at: time.Time;
+	export class CommitTicket {
+	    key: string;
+	    url?: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new Task(source);
+	        return new CommitTicket(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.id = source["id"];
-	        this.project_id = source["project_id"];
-	        this.jira_ticket_id = source["jira_ticket_id"];
-	        this.jira_title = source["jira_title"];
-	        this.title = source["title"];
-	        this.description = source["description"];
-	        this.scheduled_date = this.convertValues(source["scheduled_date"], time.Time);
-	        this.deadline = this.convertValues(source["deadline"], time.Time);
-	        this.status = source["status"];
-	        this.created_at = this.convertValues(source["created_at"], time.Time);
-	        this.updated_at = this.convertValues(source["updated_at"], time.Time);
+	        this.key = source["key"];
+	        this.url = source["url"];
+	    }
+	}
+	export class Commit {
+	    hash: string;
+	    message: string;
+	    author: string;
+	    date: time.Time;
+	    pr_number?: number;
+	    date_relative?: string;
+	    annotations?: Annotation[];
+	    tickets?: CommitTicket[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Commit(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.hash = source["hash"];
+	        this.message = source["message"];
+	        this.author = source["author"];
+	        this.date = this.convertValues(source["date"], time.Time);
+	        this.pr_number = source["pr_number"];
+	        this.date_relative = source["date_relative"];
+	        this.annotations = this.convertValues(source["annotations"], Annotation);
+	        this.tickets = this.convertValues(source["tickets"], CommitTicket);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -548,37 +667,3727 @@ export namespace types {
 		    return a;
 		}
 	}
-	export class TaskWithProject {
-	    id: number;
-	    project_id: number;
-	    jira_ticket_id: string;
-	    jira_title: string;
-	    title: string;
-	    description: string;
-	    scheduled_date?: time.Time;
-	    deadline?: time.Time;
-	    status: string;
-	    created_at: time.Time;
-	    updated_at: time.Time;
-	    project_name: string;
+	export class CommitChecks {
+	    sha: string;
+	    state: string;
+	    total: number;
+	    succeeded: number;
+	    failed: number;
+	    pending: number;
+	    failed_checks?: string[];
+	    checked_at: time.Time;
 	
 	    static createFrom(source: any = {}) {
-	        return new TaskWithProject(source);
+	        return new CommitChecks(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.id = source["id"];
-	        this.project_id = source["project_id"];
-	        this.jira_ticket_id = source["jira_ticket_id"];
-	        this.jira_title = source["jira_title"];
+	        this.sha = source["sha"];
+	        this.state = source["state"];
+	        this.total = source["total"];
+	        this.succeeded = source["succeeded"];
+	        this.failed = source["failed"];
+	        this.pending = source["pending"];
+	        this.failed_checks = source["failed_checks"];
+	        this.checked_at = this.convertValues(source["checked_at"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class DeploymentStatus {
+	    environment: string;
+	    region: string;
+	    namespace: string;
+	    tag: string;
+	    is_deployed: boolean;
+	    deployed_at?: time.Time;
+	    deployed_at_relative?: string;
+	    staleness?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DeploymentStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.environment = source["environment"];
+	        this.region = source["region"];
+	        this.namespace = source["namespace"];
+	        this.tag = source["tag"];
+	        this.is_deployed = source["is_deployed"];
+	        this.deployed_at = this.convertValues(source["deployed_at"], time.Time);
+	        this.deployed_at_relative = source["deployed_at_relative"];
+	        this.staleness = source["staleness"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CommitDeploymentStatus {
+	    commit: Commit;
+	    deployments: DeploymentStatus[];
+	
+	    static createFrom(source: any = {}) {
+	        return new CommitDeploymentStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.commit = this.convertValues(source["commit"], Commit);
+	        this.deployments = this.convertValues(source["deployments"], DeploymentStatus);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CommitDeploymentsSection {
+	    data: CommitDeploymentStatus[];
+	    status: SectionStatus;
+	
+	    static createFrom(source: any = {}) {
+	        return new CommitDeploymentsSection(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.data = this.convertValues(source["data"], CommitDeploymentStatus);
+	        this.status = this.convertValues(source["status"], SectionStatus);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CommitFileDiff {
+	    filename: string;
+	    previous_filename?: string;
+	    status: string;
+	    additions: number;
+	    deletions: number;
+	    patch: string;
+	    patch_truncated?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new CommitFileDiff(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.filename = source["filename"];
+	        this.previous_filename = source["previous_filename"];
+	        this.status = source["status"];
+	        this.additions = source["additions"];
+	        this.deletions = source["deletions"];
+	        this.patch = source["patch"];
+	        this.patch_truncated = source["patch_truncated"];
+	    }
+	}
+	export class CommitDetail {
+	    service_id: number;
+	    commit: Commit;
+	    additions: number;
+	    deletions: number;
+	    files: CommitFileDiff[];
+	    other_files: number;
+	    truncated: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new CommitDetail(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.service_id = source["service_id"];
+	        this.commit = this.convertValues(source["commit"], Commit);
+	        this.additions = source["additions"];
+	        this.deletions = source["deletions"];
+	        this.files = this.convertValues(source["files"], CommitFileDiff);
+	        this.other_files = source["other_files"];
+	        this.truncated = source["truncated"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CommitImpactDeployment {
+	    environment: string;
+	    region: string;
+	    namespace: string;
+	    tag: string;
+	    commit_sha: string;
+	    updated_at: time.Time;
+	    position: string;
+	    ahead_by: number;
+	    behind_by: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new CommitImpactDeployment(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.environment = source["environment"];
+	        this.region = source["region"];
+	        this.namespace = source["namespace"];
+	        this.tag = source["tag"];
+	        this.commit_sha = source["commit_sha"];
+	        this.updated_at = this.convertValues(source["updated_at"], time.Time);
+	        this.position = source["position"];
+	        this.ahead_by = source["ahead_by"];
+	        this.behind_by = source["behind_by"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ServiceCommitImpact {
+	    service_id: number;
+	    service_name: string;
+	    service_path: string;
+	    files: string[];
+	    deployments: CommitImpactDeployment[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ServiceCommitImpact(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.service_id = source["service_id"];
+	        this.service_name = source["service_name"];
+	        this.service_path = source["service_path"];
+	        this.files = source["files"];
+	        this.deployments = this.convertValues(source["deployments"], CommitImpactDeployment);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CommitImpact {
+	    repository_id: number;
+	    commit: Commit;
+	    files_changed: number;
+	    services: ServiceCommitImpact[];
+	
+	    static createFrom(source: any = {}) {
+	        return new CommitImpact(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.repository_id = source["repository_id"];
+	        this.commit = this.convertValues(source["commit"], Commit);
+	        this.files_changed = source["files_changed"];
+	        this.services = this.convertValues(source["services"], ServiceCommitImpact);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CommitLeadTime {
+	    commit: Commit;
+	    deployed_at?: time.Time;
+	    environment?: string;
+	    lead_time_seconds?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new CommitLeadTime(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.commit = this.convertValues(source["commit"], Commit);
+	        this.deployed_at = this.convertValues(source["deployed_at"], time.Time);
+	        this.environment = source["environment"];
+	        this.lead_time_seconds = source["lead_time_seconds"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CommitsSection {
+	    data: Commit[];
+	    status: SectionStatus;
+	
+	    static createFrom(source: any = {}) {
+	        return new CommitsSection(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.data = this.convertValues(source["data"], Commit);
+	        this.status = this.convertValues(source["status"], SectionStatus);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ConfigKey {
+	    name: string;
+	    type: string;
+	    description: string;
+	    default?: string;
+	    values?: string[];
+	    secret: boolean;
+	    prefix: boolean;
+	    internal: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ConfigKey(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.type = source["type"];
+	        this.description = source["description"];
+	        this.default = source["default"];
+	        this.values = source["values"];
+	        this.secret = source["secret"];
+	        this.prefix = source["prefix"];
+	        this.internal = source["internal"];
+	    }
+	}
+	export class CustomFieldDefinition {
+	    id: number;
+	    entity_type: string;
+	    name: string;
+	    type: string;
+	    allowed_values: string[];
+	    value_count: number;
+	    created_at: time.Time;
+	    updated_at: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new CustomFieldDefinition(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.entity_type = source["entity_type"];
+	        this.name = source["name"];
+	        this.type = source["type"];
+	        this.allowed_values = source["allowed_values"];
+	        this.value_count = source["value_count"];
+	        this.created_at = this.convertValues(source["created_at"], time.Time);
+	        this.updated_at = this.convertValues(source["updated_at"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CustomFieldFilter {
+	    field: string;
+	    value: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CustomFieldFilter(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.field = source["field"];
+	        this.value = source["value"];
+	    }
+	}
+	export class DashboardStats {
+	    repositories: number;
+	    microservices: number;
+	    kubernetesResources: number;
+	    recentActions: ActionWithDetails[];
+	    buildRollup: BuildMatrixRollup;
+	
+	    static createFrom(source: any = {}) {
+	        return new DashboardStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.repositories = source["repositories"];
+	        this.microservices = source["microservices"];
+	        this.kubernetesResources = source["kubernetesResources"];
+	        this.recentActions = this.convertValues(source["recentActions"], ActionWithDetails);
+	        this.buildRollup = this.convertValues(source["buildRollup"], BuildMatrixRollup);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class DeploymentBlame {
+	    service_id: number;
+	    environment: string;
+	    region: string;
+	    namespace: string;
+	    tag: string;
+	    commit_sha: string;
+	    commit_message: string;
+	    commit_author: string;
+	    committed_at?: time.Time;
+	    commit_url: string;
+	    commit_unknown?: string;
+	    pr_number: number;
+	    pr_title: string;
+	    pr_author: string;
+	    pr_url: string;
+	    pr_merged_at?: time.Time;
+	    pr_unknown?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DeploymentBlame(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.service_id = source["service_id"];
+	        this.environment = source["environment"];
+	        this.region = source["region"];
+	        this.namespace = source["namespace"];
+	        this.tag = source["tag"];
+	        this.commit_sha = source["commit_sha"];
+	        this.commit_message = source["commit_message"];
+	        this.commit_author = source["commit_author"];
+	        this.committed_at = this.convertValues(source["committed_at"], time.Time);
+	        this.commit_url = source["commit_url"];
+	        this.commit_unknown = source["commit_unknown"];
+	        this.pr_number = source["pr_number"];
+	        this.pr_title = source["pr_title"];
+	        this.pr_author = source["pr_author"];
+	        this.pr_url = source["pr_url"];
+	        this.pr_merged_at = this.convertValues(source["pr_merged_at"], time.Time);
+	        this.pr_unknown = source["pr_unknown"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class DeploymentDiffLine {
+	    type: string;
+	    old_line?: number;
+	    new_line?: number;
+	    content: string;
+	    is_image_change: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new DeploymentDiffLine(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.type = source["type"];
+	        this.old_line = source["old_line"];
+	        this.new_line = source["new_line"];
+	        this.content = source["content"];
+	        this.is_image_change = source["is_image_change"];
+	    }
+	}
+	export class DeploymentDimensions {
+	    environments: string[];
+	    regions: string[];
+	    namespaces: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new DeploymentDimensions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.environments = source["environments"];
+	        this.regions = source["regions"];
+	        this.namespaces = source["namespaces"];
+	    }
+	}
+	export class TagVersion {
+	    major: number;
+	    minor: number;
+	    patch: number;
+	    prerelease?: string;
+	    build?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TagVersion(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.major = source["major"];
+	        this.minor = source["minor"];
+	        this.patch = source["patch"];
+	        this.prerelease = source["prerelease"];
+	        this.build = source["build"];
+	    }
+	}
+	export class DeploymentDrift {
+	    environment: string;
+	    compared_to: string;
+	    tag: string;
+	    compared_tag: string;
+	    version?: TagVersion;
+	    compared_version?: TagVersion;
+	    method: string;
+	    major_delta: number;
+	    minor_delta: number;
+	    patch_delta: number;
+	    commits_behind: number;
+	    commits_ahead: number;
+	    summary: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DeploymentDrift(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.environment = source["environment"];
+	        this.compared_to = source["compared_to"];
+	        this.tag = source["tag"];
+	        this.compared_tag = source["compared_tag"];
+	        this.version = this.convertValues(source["version"], TagVersion);
+	        this.compared_version = this.convertValues(source["compared_version"], TagVersion);
+	        this.method = source["method"];
+	        this.major_delta = source["major_delta"];
+	        this.minor_delta = source["minor_delta"];
+	        this.patch_delta = source["patch_delta"];
+	        this.commits_behind = source["commits_behind"];
+	        this.commits_ahead = source["commits_ahead"];
+	        this.summary = source["summary"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class DeploymentFileDiff {
+	    deployment_id: number;
+	    path: string;
+	    previous_path?: string;
+	    commit_sha: string;
+	    current_blob_sha: string;
+	    previous_blob_sha?: string;
+	    current_content: string;
+	    previous_content: string;
+	    is_new_file: boolean;
+	    unified_diff: string;
+	    lines: DeploymentDiffLine[];
+	
+	    static createFrom(source: any = {}) {
+	        return new DeploymentFileDiff(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.deployment_id = source["deployment_id"];
+	        this.path = source["path"];
+	        this.previous_path = source["previous_path"];
+	        this.commit_sha = source["commit_sha"];
+	        this.current_blob_sha = source["current_blob_sha"];
+	        this.previous_blob_sha = source["previous_blob_sha"];
+	        this.current_content = source["current_content"];
+	        this.previous_content = source["previous_content"];
+	        this.is_new_file = source["is_new_file"];
+	        this.unified_diff = source["unified_diff"];
+	        this.lines = this.convertValues(source["lines"], DeploymentDiffLine);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class DeploymentHistoryEntry {
+	    id: number;
+	    service_id: number;
+	    kubernetes_repo_id: number;
+	    commit_sha: string;
+	    environment: string;
+	    region: string;
+	    namespace: string;
+	    tag: string;
+	    observed_at: time.Time;
+	    freeze_window_id?: number;
+	    annotations?: Annotation[];
+	
+	    static createFrom(source: any = {}) {
+	        return new DeploymentHistoryEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.service_id = source["service_id"];
+	        this.kubernetes_repo_id = source["kubernetes_repo_id"];
+	        this.commit_sha = source["commit_sha"];
+	        this.environment = source["environment"];
+	        this.region = source["region"];
+	        this.namespace = source["namespace"];
+	        this.tag = source["tag"];
+	        this.observed_at = this.convertValues(source["observed_at"], time.Time);
+	        this.freeze_window_id = source["freeze_window_id"];
+	        this.annotations = this.convertValues(source["annotations"], Annotation);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class DeploymentOverview {
+	    id: number;
+	    commit_sha: string;
+	    environment: string;
+	    region: string;
+	    namespace: string;
+	    tag: string;
+	    image_repository?: string;
+	    registry?: string;
+	    actual_tag: string;
+	    synced: boolean;
+	    updated_at: time.Time;
+	    updated_at_relative?: string;
+	    kubernetes_repo_name: string;
+	    checks?: CommitChecks;
+	    checks_not_green: boolean;
+	    commit_date?: time.Time;
+	    staleness: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DeploymentOverview(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.commit_sha = source["commit_sha"];
+	        this.environment = source["environment"];
+	        this.region = source["region"];
+	        this.namespace = source["namespace"];
+	        this.tag = source["tag"];
+	        this.image_repository = source["image_repository"];
+	        this.registry = source["registry"];
+	        this.actual_tag = source["actual_tag"];
+	        this.synced = source["synced"];
+	        this.updated_at = this.convertValues(source["updated_at"], time.Time);
+	        this.updated_at_relative = source["updated_at_relative"];
+	        this.kubernetes_repo_name = source["kubernetes_repo_name"];
+	        this.checks = this.convertValues(source["checks"], CommitChecks);
+	        this.checks_not_green = source["checks_not_green"];
+	        this.commit_date = this.convertValues(source["commit_date"], time.Time);
+	        this.staleness = source["staleness"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class DeploymentOverviewFilter {
+	    environment: string;
+	    region: string;
+	    offset: number;
+	    limit: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new DeploymentOverviewFilter(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.environment = source["environment"];
+	        this.region = source["region"];
+	        this.offset = source["offset"];
+	        this.limit = source["limit"];
+	    }
+	}
+	export class DeploymentRollup {
+	    environment: string;
+	    region: string;
+	    is_primary: boolean;
+	    tag: string;
+	    commit_sha: string;
+	    updated_at: time.Time;
+	    namespace_count: number;
+	    namespaces: string[];
+	    diverged: boolean;
+	    divergent_namespaces: string[];
+	    pending_sync: boolean;
+	    deployments: DeploymentOverview[];
+	
+	    static createFrom(source: any = {}) {
+	        return new DeploymentRollup(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.environment = source["environment"];
+	        this.region = source["region"];
+	        this.is_primary = source["is_primary"];
+	        this.tag = source["tag"];
+	        this.commit_sha = source["commit_sha"];
+	        this.updated_at = this.convertValues(source["updated_at"], time.Time);
+	        this.namespace_count = source["namespace_count"];
+	        this.namespaces = source["namespaces"];
+	        this.diverged = source["diverged"];
+	        this.divergent_namespaces = source["divergent_namespaces"];
+	        this.pending_sync = source["pending_sync"];
+	        this.deployments = this.convertValues(source["deployments"], DeploymentOverview);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class DeploymentScanFile {
+	    path: string;
+	    outcome: string;
+	    service_name?: string;
+	    environment?: string;
+	    region?: string;
+	    namespaces?: string[];
+	    tag?: string;
+	    source?: string;
+	    commit_annotation?: string;
+	    annotated_commit?: string;
+	    matched_service_id?: number;
+	    matched_service_name?: string;
+	    skip_reason?: string;
+	    detail?: string;
+	    images?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new DeploymentScanFile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.outcome = source["outcome"];
+	        this.service_name = source["service_name"];
+	        this.environment = source["environment"];
+	        this.region = source["region"];
+	        this.namespaces = source["namespaces"];
+	        this.tag = source["tag"];
+	        this.source = source["source"];
+	        this.commit_annotation = source["commit_annotation"];
+	        this.annotated_commit = source["annotated_commit"];
+	        this.matched_service_id = source["matched_service_id"];
+	        this.matched_service_name = source["matched_service_name"];
+	        this.skip_reason = source["skip_reason"];
+	        this.detail = source["detail"];
+	        this.images = source["images"];
+	    }
+	}
+	export class DeploymentScanDiagnostics {
+	    repository_id: number;
+	    scan_path: string;
+	    files_found: number;
+	    files_matched: number;
+	    files: DeploymentScanFile[];
+	
+	    static createFrom(source: any = {}) {
+	        return new DeploymentScanDiagnostics(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.repository_id = source["repository_id"];
+	        this.scan_path = source["scan_path"];
+	        this.files_found = source["files_found"];
+	        this.files_matched = source["files_matched"];
+	        this.files = this.convertValues(source["files"], DeploymentScanFile);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class DeploymentsSection {
+	    data: DeploymentOverview[];
+	    status: SectionStatus;
+	
+	    static createFrom(source: any = {}) {
+	        return new DeploymentsSection(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.data = this.convertValues(source["data"], DeploymentOverview);
+	        this.status = this.convertValues(source["status"], SectionStatus);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class DiscoveredService {
+	    name: string;
+	    path: string;
+	    description: string;
+	    domain: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DiscoveredService(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.path = source["path"];
+	        this.description = source["description"];
+	        this.domain = source["domain"];
+	    }
+	}
+	export class DiscoveryChange {
+	    id: number;
+	    repository_id: number;
+	    kind: string;
+	    status: string;
+	    service_id?: number;
+	    name: string;
+	    path: string;
+	    old_name?: string;
+	    old_path?: string;
+	    description?: string;
+	    domain?: string;
+	    has_readme?: boolean;
+	    detected_at: time.Time;
+	    expires_at?: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new DiscoveryChange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.repository_id = source["repository_id"];
+	        this.kind = source["kind"];
+	        this.status = source["status"];
+	        this.service_id = source["service_id"];
+	        this.name = source["name"];
+	        this.path = source["path"];
+	        this.old_name = source["old_name"];
+	        this.old_path = source["old_path"];
+	        this.description = source["description"];
+	        this.domain = source["domain"];
+	        this.has_readme = source["has_readme"];
+	        this.detected_at = this.convertValues(source["detected_at"], time.Time);
+	        this.expires_at = this.convertValues(source["expires_at"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class DiscoveryChangeDecision {
+	    id: number;
+	    accept: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new DiscoveryChangeDecision(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.accept = source["accept"];
+	    }
+	}
+	export class EnvVarChange {
+	    container: string;
+	    name: string;
+	    change: string;
+	    secret: boolean;
+	    value_a?: string;
+	    value_b?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new EnvVarChange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.container = source["container"];
+	        this.name = source["name"];
+	        this.change = source["change"];
+	        this.secret = source["secret"];
+	        this.value_a = source["value_a"];
+	        this.value_b = source["value_b"];
+	    }
+	}
+	export class EnvVarDiff {
+	    service_id: number;
+	    environment_a: string;
+	    region_a: string;
+	    environment_b: string;
+	    region_b: string;
+	    changes: EnvVarChange[];
+	    unchanged: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new EnvVarDiff(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.service_id = source["service_id"];
+	        this.environment_a = source["environment_a"];
+	        this.region_a = source["region_a"];
+	        this.environment_b = source["environment_b"];
+	        this.region_b = source["region_b"];
+	        this.changes = this.convertValues(source["changes"], EnvVarChange);
+	        this.unchanged = source["unchanged"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class EnvironmentDeployments {
+	    environment: string;
+	    is_primary: boolean;
+	    total: number;
+	    regions: string[];
+	    deployments: DeploymentOverview[];
+	
+	    static createFrom(source: any = {}) {
+	        return new EnvironmentDeployments(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.environment = source["environment"];
+	        this.is_primary = source["is_primary"];
+	        this.total = source["total"];
+	        this.regions = source["regions"];
+	        this.deployments = this.convertValues(source["deployments"], DeploymentOverview);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class FreezeWindow {
+	    id: number;
+	    environment_pattern: string;
+	    starts_at?: time.Time;
+	    ends_at?: time.Time;
+	    recurrence: string;
+	    reason: string;
+	    created_at: time.Time;
+	    updated_at: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new FreezeWindow(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.environment_pattern = source["environment_pattern"];
+	        this.starts_at = this.convertValues(source["starts_at"], time.Time);
+	        this.ends_at = this.convertValues(source["ends_at"], time.Time);
+	        this.recurrence = source["recurrence"];
+	        this.reason = source["reason"];
+	        this.created_at = this.convertValues(source["created_at"], time.Time);
+	        this.updated_at = this.convertValues(source["updated_at"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class FreezeViolation {
+	    entry?: DeploymentHistoryEntry;
+	    service_name: string;
+	    freeze_window?: FreezeWindow;
+	
+	    static createFrom(source: any = {}) {
+	        return new FreezeViolation(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.entry = this.convertValues(source["entry"], DeploymentHistoryEntry);
+	        this.service_name = source["service_name"];
+	        this.freeze_window = this.convertValues(source["freeze_window"], FreezeWindow);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class HourCount {
+	    hour: number;
+	    events: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new HourCount(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.hour = source["hour"];
+	        this.events = source["events"];
+	    }
+	}
+	export class RegistryServiceUsage {
+	    service_id: number;
+	    service_name: string;
+	    image_repository: string;
+	    environments: string[];
+	    deployments: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new RegistryServiceUsage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.service_id = source["service_id"];
+	        this.service_name = source["service_name"];
+	        this.image_repository = source["image_repository"];
+	        this.environments = source["environments"];
+	        this.deployments = source["deployments"];
+	    }
+	}
+	export class ImageRegistryUsage {
+	    registry: string;
+	    deployments: number;
+	    services: RegistryServiceUsage[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ImageRegistryUsage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.registry = source["registry"];
+	        this.deployments = source["deployments"];
+	        this.services = this.convertValues(source["services"], RegistryServiceUsage);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class JiraStatusDuration {
+	    status: string;
+	    seconds: number;
+	    current: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new JiraStatusDuration(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.status = source["status"];
+	        this.seconds = source["seconds"];
+	        this.current = source["current"];
+	    }
+	}
+	export class JiraStatusTransition {
+	    from: string;
+	    to: string;
+	    at: time.Time;
+	    at_relative?: string;
+	    author: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new JiraStatusTransition(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.from = source["from"];
+	        this.to = source["to"];
+	        this.at = this.convertValues(source["at"], time.Time);
+	        this.at_relative = source["at_relative"];
+	        this.author = source["author"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class JiraStatusHistory {
+	    task_id: number;
+	    issue_key: string;
+	    status: string;
+	    created: time.Time;
+	    transitions: JiraStatusTransition[];
+	    time_in_status: JiraStatusDuration[];
+	    fetched_at: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new JiraStatusHistory(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.task_id = source["task_id"];
+	        this.issue_key = source["issue_key"];
+	        this.status = source["status"];
+	        this.created = this.convertValues(source["created"], time.Time);
+	        this.transitions = this.convertValues(source["transitions"], JiraStatusTransition);
+	        this.time_in_status = this.convertValues(source["time_in_status"], JiraStatusDuration);
+	        this.fetched_at = this.convertValues(source["fetched_at"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Job {
+	    id: number;
+	    kind: string;
+	    params: string;
+	    status: string;
+	    progress: number;
+	    message?: string;
+	    result?: string;
+	    file_path?: string;
+	    error?: string;
+	    created_at: time.Time;
+	    finished_at?: time.Time;
+	    acknowledged_at?: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new Job(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.kind = source["kind"];
+	        this.params = source["params"];
+	        this.status = source["status"];
+	        this.progress = source["progress"];
+	        this.message = source["message"];
+	        this.result = source["result"];
+	        this.file_path = source["file_path"];
+	        this.error = source["error"];
+	        this.created_at = this.convertValues(source["created_at"], time.Time);
+	        this.finished_at = this.convertValues(source["finished_at"], time.Time);
+	        this.acknowledged_at = this.convertValues(source["acknowledged_at"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class KubernetesResource {
+	    id: number;
+	    repository_id: number;
+	    name: string;
+	    path: string;
+	    resource_type: string;
+	    namespace: string;
+	    created_at: time.Time;
+	    updated_at: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new KubernetesResource(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.repository_id = source["repository_id"];
+	        this.name = source["name"];
+	        this.path = source["path"];
+	        this.resource_type = source["resource_type"];
+	        this.namespace = source["namespace"];
+	        this.created_at = this.convertValues(source["created_at"], time.Time);
+	        this.updated_at = this.convertValues(source["updated_at"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class LeadTimeStats {
+	    service_id: number;
+	    since: time.Time;
+	    median_lead_time_seconds?: number;
+	    deployed_count: number;
+	    open_count: number;
+	    commits: CommitLeadTime[];
+	
+	    static createFrom(source: any = {}) {
+	        return new LeadTimeStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.service_id = source["service_id"];
+	        this.since = this.convertValues(source["since"], time.Time);
+	        this.median_lead_time_seconds = source["median_lead_time_seconds"];
+	        this.deployed_count = source["deployed_count"];
+	        this.open_count = source["open_count"];
+	        this.commits = this.convertValues(source["commits"], CommitLeadTime);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class LegacyDatabase {
+	    path: string;
+	    size_bytes: number;
+	    modified_at: time.Time;
+	    has_data: boolean;
+	    current_path: string;
+	    current_has_data: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new LegacyDatabase(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.size_bytes = source["size_bytes"];
+	        this.modified_at = this.convertValues(source["modified_at"], time.Time);
+	        this.has_data = source["has_data"];
+	        this.current_path = source["current_path"];
+	        this.current_has_data = source["current_has_data"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Microservice {
+	    id: number;
+	    repository_id: number;
+	    name: string;
+	    path: string;
+	    description: string;
+	    domain: string;
+	    is_hidden: boolean;
+	    primary_environment: string;
+	    owner: string;
+	    has_readme?: boolean;
+	    image_name: string;
+	    suggested_image_name: string;
+	    custom_fields?: Record<string, string>;
+	    created_at: time.Time;
+	    updated_at: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new Microservice(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.repository_id = source["repository_id"];
+	        this.name = source["name"];
+	        this.path = source["path"];
+	        this.description = source["description"];
+	        this.domain = source["domain"];
+	        this.is_hidden = source["is_hidden"];
+	        this.primary_environment = source["primary_environment"];
+	        this.owner = source["owner"];
+	        this.has_readme = source["has_readme"];
+	        this.image_name = source["image_name"];
+	        this.suggested_image_name = source["suggested_image_name"];
+	        this.custom_fields = source["custom_fields"];
+	        this.created_at = this.convertValues(source["created_at"], time.Time);
+	        this.updated_at = this.convertValues(source["updated_at"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MicroserviceWithRepository {
+	    id: number;
+	    repository_id: number;
+	    name: string;
+	    path: string;
+	    description: string;
+	    domain: string;
+	    is_hidden: boolean;
+	    primary_environment: string;
+	    owner: string;
+	    has_readme?: boolean;
+	    image_name: string;
+	    suggested_image_name: string;
+	    custom_fields?: Record<string, string>;
+	    created_at: time.Time;
+	    updated_at: time.Time;
+	    repository_name: string;
+	    repository_url: string;
+	    repository_type: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new MicroserviceWithRepository(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.repository_id = source["repository_id"];
+	        this.name = source["name"];
+	        this.path = source["path"];
+	        this.description = source["description"];
+	        this.domain = source["domain"];
+	        this.is_hidden = source["is_hidden"];
+	        this.primary_environment = source["primary_environment"];
+	        this.owner = source["owner"];
+	        this.has_readme = source["has_readme"];
+	        this.image_name = source["image_name"];
+	        this.suggested_image_name = source["suggested_image_name"];
+	        this.custom_fields = source["custom_fields"];
+	        this.created_at = this.convertValues(source["created_at"], time.Time);
+	        this.updated_at = this.convertValues(source["updated_at"], time.Time);
+	        this.repository_name = source["repository_name"];
+	        this.repository_url = source["repository_url"];
+	        this.repository_type = source["repository_type"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Notification {
+	    id: number;
+	    repository_id?: number;
+	    type: string;
+	    title: string;
+	    message: string;
+	    environment: string;
+	    is_read: boolean;
+	    created_at: time.Time;
+	    deliver_at?: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new Notification(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.repository_id = source["repository_id"];
+	        this.type = source["type"];
+	        this.title = source["title"];
+	        this.message = source["message"];
+	        this.environment = source["environment"];
+	        this.is_read = source["is_read"];
+	        this.created_at = this.convertValues(source["created_at"], time.Time);
+	        this.deliver_at = this.convertValues(source["deliver_at"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PendingApproval {
+	    id: number;
+	    repository_id: number;
+	    workflow_run_id: number;
+	    environment_id: number;
+	    environment_name: string;
+	    reviewers: string[];
+	    waiting_since: time.Time;
+	    can_approve: boolean;
+	    action_id?: number;
+	    repository_name?: string;
+	    service_name?: string;
+	    commit_sha?: string;
+	    branch?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PendingApproval(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.repository_id = source["repository_id"];
+	        this.workflow_run_id = source["workflow_run_id"];
+	        this.environment_id = source["environment_id"];
+	        this.environment_name = source["environment_name"];
+	        this.reviewers = source["reviewers"];
+	        this.waiting_since = this.convertValues(source["waiting_since"], time.Time);
+	        this.can_approve = source["can_approve"];
+	        this.action_id = source["action_id"];
+	        this.repository_name = source["repository_name"];
+	        this.service_name = source["service_name"];
+	        this.commit_sha = source["commit_sha"];
+	        this.branch = source["branch"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Project {
+	    id: number;
+	    name: string;
+	    description: string;
+	    created_at: time.Time;
+	    updated_at: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new Project(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.created_at = this.convertValues(source["created_at"], time.Time);
+	        this.updated_at = this.convertValues(source["updated_at"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PullRequest {
+	    id: number;
+	    number: number;
+	    title: string;
+	    status: string;
+	    author: string;
+	    branch: string;
+	    created_at: time.Time;
+	    sensitive: boolean;
+	    sensitive_files?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new PullRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.number = source["number"];
+	        this.title = source["title"];
+	        this.status = source["status"];
+	        this.author = source["author"];
+	        this.branch = source["branch"];
+	        this.created_at = this.convertValues(source["created_at"], time.Time);
+	        this.sensitive = source["sensitive"];
+	        this.sensitive_files = source["sensitive_files"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PullRequestsSection {
+	    data: PullRequest[];
+	    status: SectionStatus;
+	
+	    static createFrom(source: any = {}) {
+	        return new PullRequestsSection(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.data = this.convertValues(source["data"], PullRequest);
+	        this.status = this.convertValues(source["status"], SectionStatus);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class QuickCaptureOption {
+	    label: string;
+	    token: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new QuickCaptureOption(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.label = source["label"];
+	        this.token = source["token"];
+	    }
+	}
+	export class Task {
+	    id: number;
+	    project_id: number;
+	    jira_ticket_id: string;
+	    jira_title: string;
+	    jira_status: string;
+	    jira_assignee: string;
+	    title: string;
+	    description: string;
+	    scheduled_date?: time.Time;
+	    deadline?: time.Time;
+	    status: string;
+	    priority: string;
+	    created_at: time.Time;
+	    updated_at: time.Time;
+	    jira_assignee_name: string;
+	    jira_due_date?: time.Time;
+	    jira_sprint: string;
+	    jira_labels: string[];
+	    jira_parent_key: string;
+	    checklist_done: number;
+	    checklist_total: number;
+	    checklist_completion: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Task(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.project_id = source["project_id"];
+	        this.jira_ticket_id = source["jira_ticket_id"];
+	        this.jira_title = source["jira_title"];
+	        this.jira_status = source["jira_status"];
+	        this.jira_assignee = source["jira_assignee"];
+	        this.title = source["title"];
+	        this.description = source["description"];
+	        this.scheduled_date = this.convertValues(source["scheduled_date"], time.Time);
+	        this.deadline = this.convertValues(source["deadline"], time.Time);
+	        this.status = source["status"];
+	        this.priority = source["priority"];
+	        this.created_at = this.convertValues(source["created_at"], time.Time);
+	        this.updated_at = this.convertValues(source["updated_at"], time.Time);
+	        this.jira_assignee_name = source["jira_assignee_name"];
+	        this.jira_due_date = this.convertValues(source["jira_due_date"], time.Time);
+	        this.jira_sprint = source["jira_sprint"];
+	        this.jira_labels = source["jira_labels"];
+	        this.jira_parent_key = source["jira_parent_key"];
+	        this.checklist_done = source["checklist_done"];
+	        this.checklist_total = source["checklist_total"];
+	        this.checklist_completion = source["checklist_completion"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class QuickCaptureSuggestion {
+	    field: string;
+	    token: string;
+	    message: string;
+	    options: QuickCaptureOption[];
+	
+	    static createFrom(source: any = {}) {
+	        return new QuickCaptureSuggestion(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.field = source["field"];
+	        this.token = source["token"];
+	        this.message = source["message"];
+	        this.options = this.convertValues(source["options"], QuickCaptureOption);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class QuickCaptureResult {
+	    task?: Task;
+	    project_id?: number;
+	    project_name?: string;
+	    jira_ticket_id: string;
+	    title: string;
+	    deadline?: time.Time;
+	    suggestions: QuickCaptureSuggestion[];
+	
+	    static createFrom(source: any = {}) {
+	        return new QuickCaptureResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.task = this.convertValues(source["task"], Task);
+	        this.project_id = source["project_id"];
+	        this.project_name = source["project_name"];
+	        this.jira_ticket_id = source["jira_ticket_id"];
+	        this.title = source["title"];
+	        this.deadline = this.convertValues(source["deadline"], time.Time);
+	        this.suggestions = this.convertValues(source["suggestions"], QuickCaptureSuggestion);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ReliabilityPoint {
+	    date: string;
+	    build_runs: number;
+	    build_success_rate?: number;
+	    deployment_runs: number;
+	    deployment_success_rate?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ReliabilityPoint(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.date = source["date"];
+	        this.build_runs = source["build_runs"];
+	        this.build_success_rate = source["build_success_rate"];
+	        this.deployment_runs = source["deployment_runs"];
+	        this.deployment_success_rate = source["deployment_success_rate"];
+	    }
+	}
+	export class SyncState {
+	    started_at: time.Time;
+	    phase: string;
+	    completed: string[];
+	    failed?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SyncState(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.started_at = this.convertValues(source["started_at"], time.Time);
+	        this.phase = source["phase"];
+	        this.completed = source["completed"];
+	        this.failed = source["failed"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SecurityAlertCounts {
+	    dependabot: number;
+	    code_scanning: number;
+	    critical: number;
+	    high: number;
+	    dependabot_unavailable: boolean;
+	    code_scanning_unavailable: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new SecurityAlertCounts(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.dependabot = source["dependabot"];
+	        this.code_scanning = source["code_scanning"];
+	        this.critical = source["critical"];
+	        this.high = source["high"];
+	        this.dependabot_unavailable = source["dependabot_unavailable"];
+	        this.code_scanning_unavailable = source["code_scanning_unavailable"];
+	    }
+	}
+	export class Repository {
+	    id: number;
+	    name: string;
+	    url: string;
+	    type: string;
+	    description: string;
+	    service_name?: string;
+	    service_location?: string;
+	    discovery_script?: string;
+	    status: string;
+	    created_at: time.Time;
+	    updated_at: time.Time;
+	    last_sync_at?: time.Time;
+	    last_sync_error?: string;
+	    access_state: string;
+	    access_status?: number;
+	    access_checked_at?: time.Time;
+	    access_failures: number;
+	    access_retry_at?: time.Time;
+	    sync_state?: SyncState;
+	    manual_sync_only: boolean;
+	    discovery_review: boolean;
+	    is_favorite: boolean;
+	    collect_packages: boolean;
+	    default_branch?: string;
+	    workflow_branches: string;
+	    security_alerts?: SecurityAlertCounts;
+	
+	    static createFrom(source: any = {}) {
+	        return new Repository(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.url = source["url"];
+	        this.type = source["type"];
+	        this.description = source["description"];
+	        this.service_name = source["service_name"];
+	        this.service_location = source["service_location"];
+	        this.discovery_script = source["discovery_script"];
+	        this.status = source["status"];
+	        this.created_at = this.convertValues(source["created_at"], time.Time);
+	        this.updated_at = this.convertValues(source["updated_at"], time.Time);
+	        this.last_sync_at = this.convertValues(source["last_sync_at"], time.Time);
+	        this.last_sync_error = source["last_sync_error"];
+	        this.access_state = source["access_state"];
+	        this.access_status = source["access_status"];
+	        this.access_checked_at = this.convertValues(source["access_checked_at"], time.Time);
+	        this.access_failures = source["access_failures"];
+	        this.access_retry_at = this.convertValues(source["access_retry_at"], time.Time);
+	        this.sync_state = this.convertValues(source["sync_state"], SyncState);
+	        this.manual_sync_only = source["manual_sync_only"];
+	        this.discovery_review = source["discovery_review"];
+	        this.is_favorite = source["is_favorite"];
+	        this.collect_packages = source["collect_packages"];
+	        this.default_branch = source["default_branch"];
+	        this.workflow_branches = source["workflow_branches"];
+	        this.security_alerts = this.convertValues(source["security_alerts"], SecurityAlertCounts);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class RepositoryAccessEntry {
+	    repository_id: number;
+	    name: string;
+	    url: string;
+	    status: string;
+	    state: string;
+	    http_status?: number;
+	    reason?: string;
+	    checked_at?: time.Time;
+	    retry_at?: time.Time;
+	    last_sync_at?: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new RepositoryAccessEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.repository_id = source["repository_id"];
+	        this.name = source["name"];
+	        this.url = source["url"];
+	        this.status = source["status"];
+	        this.state = source["state"];
+	        this.http_status = source["http_status"];
+	        this.reason = source["reason"];
+	        this.checked_at = this.convertValues(source["checked_at"], time.Time);
+	        this.retry_at = this.convertValues(source["retry_at"], time.Time);
+	        this.last_sync_at = this.convertValues(source["last_sync_at"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class RepositoryAccessReport {
+	    generated_at: time.Time;
+	    token_configured: boolean;
+	    counts: Record<string, number>;
+	    repositories: RepositoryAccessEntry[];
+	
+	    static createFrom(source: any = {}) {
+	        return new RepositoryAccessReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.generated_at = this.convertValues(source["generated_at"], time.Time);
+	        this.token_configured = source["token_configured"];
+	        this.counts = source["counts"];
+	        this.repositories = this.convertValues(source["repositories"], RepositoryAccessEntry);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class RepositorySyncStatus {
+	    repository_id: number;
+	    name: string;
+	    running: boolean;
+	    interrupted: boolean;
+	    phase?: string;
+	    completed_phases: string[];
+	    started_at?: time.Time;
+	    last_sync_at?: time.Time;
+	    last_sync_error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new RepositorySyncStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.repository_id = source["repository_id"];
+	        this.name = source["name"];
+	        this.running = source["running"];
+	        this.interrupted = source["interrupted"];
+	        this.phase = source["phase"];
+	        this.completed_phases = source["completed_phases"];
+	        this.started_at = this.convertValues(source["started_at"], time.Time);
+	        this.last_sync_at = this.convertValues(source["last_sync_at"], time.Time);
+	        this.last_sync_error = source["last_sync_error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class RepositoryWebhook {
+	    repository_id: number;
+	    hook_id: number;
+	    target_url: string;
+	    created_at: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new RepositoryWebhook(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.repository_id = source["repository_id"];
+	        this.hook_id = source["hook_id"];
+	        this.target_url = source["target_url"];
+	        this.created_at = this.convertValues(source["created_at"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class RolloutStraggler {
+	    region: string;
+	    namespace: string;
+	    tag: string;
+	    since?: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new RolloutStraggler(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.region = source["region"];
+	        this.namespace = source["namespace"];
+	        this.tag = source["tag"];
+	        this.since = this.convertValues(source["since"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class RolloutProgress {
+	    service_id: number;
+	    environment: string;
+	    tag: string;
+	    commit_sha: string;
+	    updated_targets: number;
+	    total_targets: number;
+	    complete: boolean;
+	    started_at?: time.Time;
+	    last_transition_at?: time.Time;
+	    estimated_completion_at?: time.Time;
+	    stuck: boolean;
+	    stragglers: RolloutStraggler[];
+	
+	    static createFrom(source: any = {}) {
+	        return new RolloutProgress(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.service_id = source["service_id"];
+	        this.environment = source["environment"];
+	        this.tag = source["tag"];
+	        this.commit_sha = source["commit_sha"];
+	        this.updated_targets = source["updated_targets"];
+	        this.total_targets = source["total_targets"];
+	        this.complete = source["complete"];
+	        this.started_at = this.convertValues(source["started_at"], time.Time);
+	        this.last_transition_at = this.convertValues(source["last_transition_at"], time.Time);
+	        this.estimated_completion_at = this.convertValues(source["estimated_completion_at"], time.Time);
+	        this.stuck = source["stuck"];
+	        this.stragglers = this.convertValues(source["stragglers"], RolloutStraggler);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ScorecardCheck {
+	    id: string;
+	    name: string;
+	    description: string;
+	    enabled: boolean;
+	    has_threshold: boolean;
+	    threshold: number;
+	    default_threshold: number;
+	    threshold_unit?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ScorecardCheck(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.enabled = source["enabled"];
+	        this.has_threshold = source["has_threshold"];
+	        this.threshold = source["threshold"];
+	        this.default_threshold = source["default_threshold"];
+	        this.threshold_unit = source["threshold_unit"];
+	    }
+	}
+	export class ScorecardCheckSummary {
+	    check_id: string;
+	    name: string;
+	    passed: number;
+	    failed: number;
+	    unknown: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ScorecardCheckSummary(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.check_id = source["check_id"];
+	        this.name = source["name"];
+	        this.passed = source["passed"];
+	        this.failed = source["failed"];
+	        this.unknown = source["unknown"];
+	    }
+	}
+	export class ScorecardResult {
+	    check_id: string;
+	    name: string;
+	    status: string;
+	    detail: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ScorecardResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.check_id = source["check_id"];
+	        this.name = source["name"];
+	        this.status = source["status"];
+	        this.detail = source["detail"];
+	    }
+	}
+	export class ServiceScorecard {
+	    service_id: number;
+	    service_name: string;
+	    grade: string;
+	    score?: number;
+	    evaluated_at: time.Time;
+	    results: ScorecardResult[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ServiceScorecard(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.service_id = source["service_id"];
+	        this.service_name = source["service_name"];
+	        this.grade = source["grade"];
+	        this.score = source["score"];
+	        this.evaluated_at = this.convertValues(source["evaluated_at"], time.Time);
+	        this.results = this.convertValues(source["results"], ScorecardResult);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ScorecardSummary {
+	    scorecards: ServiceScorecard[];
+	    grade_counts: Record<string, number>;
+	    checks: ScorecardCheckSummary[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ScorecardSummary(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.scorecards = this.convertValues(source["scorecards"], ServiceScorecard);
+	        this.grade_counts = source["grade_counts"];
+	        this.checks = this.convertValues(source["checks"], ScorecardCheckSummary);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SecurityAlert {
+	    number: number;
+	    severity: string;
+	    summary: string;
+	    location: string;
+	    url: string;
+	    created_at: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new SecurityAlert(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.number = source["number"];
+	        this.severity = source["severity"];
+	        this.summary = source["summary"];
+	        this.location = source["location"];
+	        this.url = source["url"];
+	        this.created_at = this.convertValues(source["created_at"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SecurityAlertReport {
+	    repository_id: number;
+	    source: string;
+	    available: boolean;
+	    detail?: string;
+	    critical: number;
+	    high: number;
+	    medium: number;
+	    low: number;
+	    top_alerts: SecurityAlert[];
+	    checked_at: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new SecurityAlertReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.repository_id = source["repository_id"];
+	        this.source = source["source"];
+	        this.available = source["available"];
+	        this.detail = source["detail"];
+	        this.critical = source["critical"];
+	        this.high = source["high"];
+	        this.medium = source["medium"];
+	        this.low = source["low"];
+	        this.top_alerts = this.convertValues(source["top_alerts"], SecurityAlert);
+	        this.checked_at = this.convertValues(source["checked_at"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ServiceCatalogChange {
+	    row: number;
+	    service_id: number;
+	    service_name: string;
+	    field: string;
+	    field_id?: number;
+	    old: string;
+	    new: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ServiceCatalogChange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.row = source["row"];
+	        this.service_id = source["service_id"];
+	        this.service_name = source["service_name"];
+	        this.field = source["field"];
+	        this.field_id = source["field_id"];
+	        this.old = source["old"];
+	        this.new = source["new"];
+	    }
+	}
+	export class ServiceCatalogProblem {
+	    row: number;
+	    message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ServiceCatalogProblem(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.row = source["row"];
+	        this.message = source["message"];
+	    }
+	}
+	export class ServiceCatalogImport {
+	    path: string;
+	    format: string;
+	    rows: number;
+	    unchanged: number;
+	    changes: ServiceCatalogChange[];
+	    problems: ServiceCatalogProblem[];
+	    applied: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ServiceCatalogImport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.format = source["format"];
+	        this.rows = source["rows"];
+	        this.unchanged = source["unchanged"];
+	        this.changes = this.convertValues(source["changes"], ServiceCatalogChange);
+	        this.problems = this.convertValues(source["problems"], ServiceCatalogProblem);
+	        this.applied = source["applied"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ServiceDeploymentCounts {
+	    service_id: number;
+	    counts: Record<string, number>;
+	    environments: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ServiceDeploymentCounts(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.service_id = source["service_id"];
+	        this.counts = source["counts"];
+	        this.environments = source["environments"];
+	    }
+	}
+	export class ServiceDetail {
+	    service?: Microservice;
+	    repository?: Repository;
+	    pull_requests: PullRequestsSection;
+	    commits: CommitsSection;
+	    deployments: DeploymentsSection;
+	    commit_deployments: CommitDeploymentsSection;
+	    actions: ActionsSection;
+	
+	    static createFrom(source: any = {}) {
+	        return new ServiceDetail(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.service = this.convertValues(source["service"], Microservice);
+	        this.repository = this.convertValues(source["repository"], Repository);
+	        this.pull_requests = this.convertValues(source["pull_requests"], PullRequestsSection);
+	        this.commits = this.convertValues(source["commits"], CommitsSection);
+	        this.deployments = this.convertValues(source["deployments"], DeploymentsSection);
+	        this.commit_deployments = this.convertValues(source["commit_deployments"], CommitDeploymentsSection);
+	        this.actions = this.convertValues(source["actions"], ActionsSection);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ServiceDetailOptions {
+	    pull_requests: boolean;
+	    commits: boolean;
+	    deployments: boolean;
+	    commit_deployments: boolean;
+	    actions: boolean;
+	    actions_limit: number;
+	    force_refresh: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ServiceDetailOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.pull_requests = source["pull_requests"];
+	        this.commits = source["commits"];
+	        this.deployments = source["deployments"];
+	        this.commit_deployments = source["commit_deployments"];
+	        this.actions = source["actions"];
+	        this.actions_limit = source["actions_limit"];
+	        this.force_refresh = source["force_refresh"];
+	    }
+	}
+	export class ServiceDomainGroup {
+	    domain: string;
+	    services: Microservice[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ServiceDomainGroup(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.domain = source["domain"];
+	        this.services = this.convertValues(source["services"], Microservice);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ServiceEnvironmentSummary {
+	    environment: string;
+	    region: string;
+	    tag: string;
+	    targets: number;
+	    updated_at: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new ServiceEnvironmentSummary(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.environment = source["environment"];
+	        this.region = source["region"];
+	        this.tag = source["tag"];
+	        this.targets = source["targets"];
+	        this.updated_at = this.convertValues(source["updated_at"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ServiceMergeResult {
+	    deployments: number;
+	    deployment_history: number;
+	    actions: number;
+	    usage_events: number;
+	    dropped_deployments: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ServiceMergeResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.deployments = source["deployments"];
+	        this.deployment_history = source["deployment_history"];
+	        this.actions = source["actions"];
+	        this.usage_events = source["usage_events"];
+	        this.dropped_deployments = source["dropped_deployments"];
+	    }
+	}
+	export class ServicePackage {
+	    id: number;
+	    service_id: number;
+	    manifest_path: string;
+	    ecosystem: string;
+	    name: string;
+	    version: string;
+	    dev: boolean;
+	    latest_version?: string;
+	    lookup_error?: string;
+	    latest_checked_at?: time.Time;
+	    major_behind: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ServicePackage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.service_id = source["service_id"];
+	        this.manifest_path = source["manifest_path"];
+	        this.ecosystem = source["ecosystem"];
+	        this.name = source["name"];
+	        this.version = source["version"];
+	        this.dev = source["dev"];
+	        this.latest_version = source["latest_version"];
+	        this.lookup_error = source["lookup_error"];
+	        this.latest_checked_at = this.convertValues(source["latest_checked_at"], time.Time);
+	        this.major_behind = source["major_behind"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ServicePackageManifest {
+	    service_id: number;
+	    path: string;
+	    ecosystem: string;
+	    blob_sha: string;
+	    parse_error?: string;
+	    parsed_at: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new ServicePackageManifest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.service_id = source["service_id"];
+	        this.path = source["path"];
+	        this.ecosystem = source["ecosystem"];
+	        this.blob_sha = source["blob_sha"];
+	        this.parse_error = source["parse_error"];
+	        this.parsed_at = this.convertValues(source["parsed_at"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ServicePackages {
+	    manifests: ServicePackageManifest[];
+	    packages: ServicePackage[];
+	    major_behind: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ServicePackages(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.manifests = this.convertValues(source["manifests"], ServicePackageManifest);
+	        this.packages = this.convertValues(source["packages"], ServicePackage);
+	        this.major_behind = source["major_behind"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ServiceRediscovery {
+	    repository_id: number;
+	    repository_name: string;
+	    services: number;
+	    added: number;
+	    updated: number;
+	    removed: number;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ServiceRediscovery(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.repository_id = source["repository_id"];
+	        this.repository_name = source["repository_name"];
+	        this.services = source["services"];
+	        this.added = source["added"];
+	        this.updated = source["updated"];
+	        this.removed = source["removed"];
+	        this.error = source["error"];
+	    }
+	}
+	export class ServiceReliability {
+	    service_id: number;
+	    since: time.Time;
+	    exclude_cancelled: boolean;
+	    build: ActionReliability;
+	    deployment: ActionReliability;
+	    daily: ReliabilityPoint[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ServiceReliability(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.service_id = source["service_id"];
+	        this.since = this.convertValues(source["since"], time.Time);
+	        this.exclude_cancelled = source["exclude_cancelled"];
+	        this.build = this.convertValues(source["build"], ActionReliability);
+	        this.deployment = this.convertValues(source["deployment"], ActionReliability);
+	        this.daily = this.convertValues(source["daily"], ReliabilityPoint);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ServiceSummary {
+	    id: number;
+	    repository_id: number;
+	    name: string;
+	    path: string;
+	    description: string;
+	    domain: string;
+	    is_hidden: boolean;
+	    primary_environment: string;
+	    owner: string;
+	    has_readme?: boolean;
+	    image_name: string;
+	    suggested_image_name: string;
+	    custom_fields?: Record<string, string>;
+	    created_at: time.Time;
+	    updated_at: time.Time;
+	    last_build?: Action;
+	    last_deployment?: Action;
+	    environments: ServiceEnvironmentSummary[];
+	    open_pull_requests?: number;
+	    open_tasks?: number;
+	    summarized_at?: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new ServiceSummary(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.repository_id = source["repository_id"];
+	        this.name = source["name"];
+	        this.path = source["path"];
+	        this.description = source["description"];
+	        this.domain = source["domain"];
+	        this.is_hidden = source["is_hidden"];
+	        this.primary_environment = source["primary_environment"];
+	        this.owner = source["owner"];
+	        this.has_readme = source["has_readme"];
+	        this.image_name = source["image_name"];
+	        this.suggested_image_name = source["suggested_image_name"];
+	        this.custom_fields = source["custom_fields"];
+	        this.created_at = this.convertValues(source["created_at"], time.Time);
+	        this.updated_at = this.convertValues(source["updated_at"], time.Time);
+	        this.last_build = this.convertValues(source["last_build"], Action);
+	        this.last_deployment = this.convertValues(source["last_deployment"], Action);
+	        this.environments = this.convertValues(source["environments"], ServiceEnvironmentSummary);
+	        this.open_pull_requests = source["open_pull_requests"];
+	        this.open_tasks = source["open_tasks"];
+	        this.summarized_at = this.convertValues(source["summarized_at"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ServiceSummaryFilter {
+	    repository_id: number;
+	    include_hidden: boolean;
+	    build_status: string;
+	    owner: string;
+	    search: string;
+	    custom_fields: CustomFieldFilter[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ServiceSummaryFilter(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.repository_id = source["repository_id"];
+	        this.include_hidden = source["include_hidden"];
+	        this.build_status = source["build_status"];
+	        this.owner = source["owner"];
+	        this.search = source["search"];
+	        this.custom_fields = this.convertValues(source["custom_fields"], CustomFieldFilter);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ServiceViewCount {
+	    service_id: number;
+	    service_name: string;
+	    views: number;
+	    last_viewed_at: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new ServiceViewCount(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.service_id = source["service_id"];
+	        this.service_name = source["service_name"];
+	        this.views = source["views"];
+	        this.last_viewed_at = this.convertValues(source["last_viewed_at"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SettingsExportOptions {
+	    secrets: string;
+	    passphrase?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SettingsExportOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.secrets = source["secrets"];
+	        this.passphrase = source["passphrase"];
+	    }
+	}
+	export class SettingsImportResult {
+	    config_applied: number;
+	    config_skipped: number;
+	    repositories_created: number;
+	    repositories_updated: number;
+	    repositories_skipped: number;
+	    missing_secrets: string[];
+	    unknown_config: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SettingsImportResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.config_applied = source["config_applied"];
+	        this.config_skipped = source["config_skipped"];
+	        this.repositories_created = source["repositories_created"];
+	        this.repositories_updated = source["repositories_updated"];
+	        this.repositories_skipped = source["repositories_skipped"];
+	        this.missing_secrets = source["missing_secrets"];
+	        this.unknown_config = source["unknown_config"];
+	    }
+	}
+	export class SetupStatus {
+	    database_ready: boolean;
+	    github_token_source: string;
+	    credentials_file: string;
+	    sync_running: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new SetupStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.database_ready = source["database_ready"];
+	        this.github_token_source = source["github_token_source"];
+	        this.credentials_file = source["credentials_file"];
+	        this.sync_running = source["sync_running"];
+	    }
+	}
+	export class SlowQuery {
+	    sql: string;
+	    duration_ms: number;
+	    executed_at: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new SlowQuery(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.sql = source["sql"];
+	        this.duration_ms = source["duration_ms"];
+	        this.executed_at = this.convertValues(source["executed_at"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class StartupError {
+	    stage: string;
+	    message: string;
+	    migration?: string;
+	    backup_path?: string;
+	    restored: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new StartupError(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.stage = source["stage"];
+	        this.message = source["message"];
+	        this.migration = source["migration"];
+	        this.backup_path = source["backup_path"];
+	        this.restored = source["restored"];
+	    }
+	}
+	export class StatsTrendPoint {
+	    date: string;
+	    value?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new StatsTrendPoint(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.date = source["date"];
+	        this.value = source["value"];
+	    }
+	}
+	export class SyncLog {
+	    id: number;
+	    repository_id?: number;
+	    level: string;
+	    message: string;
+	    created_at: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new SyncLog(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.repository_id = source["repository_id"];
+	        this.level = source["level"];
+	        this.message = source["message"];
+	        this.created_at = this.convertValues(source["created_at"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SystemWarning {
+	    kind: string;
+	    severity: string;
+	    message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SystemWarning(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.kind = source["kind"];
+	        this.severity = source["severity"];
+	        this.message = source["message"];
+	    }
+	}
+	export class SystemStatus {
+	    github_token_expires_at?: time.Time;
+	    warnings: SystemWarning[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SystemStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.github_token_expires_at = this.convertValues(source["github_token_expires_at"], time.Time);
+	        this.warnings = this.convertValues(source["warnings"], SystemWarning);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TaskChecklistItem {
+	    id: number;
+	    task_id: number;
+	    text: string;
+	    done: boolean;
+	    position: number;
+	    created_at: time.Time;
+	    updated_at: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new TaskChecklistItem(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.task_id = source["task_id"];
+	        this.text = source["text"];
+	        this.done = source["done"];
+	        this.position = source["position"];
+	        this.created_at = this.convertValues(source["created_at"], time.Time);
+	        this.updated_at = this.convertValues(source["updated_at"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TaskDetail {
+	    task?: Task;
+	    project_name: string;
+	    jira_url?: string;
+	    jira_parent_url?: string;
+	    tags: string[];
+	    checklist: TaskChecklistItem[];
+	
+	    static createFrom(source: any = {}) {
+	        return new TaskDetail(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.task = this.convertValues(source["task"], Task);
+	        this.project_name = source["project_name"];
+	        this.jira_url = source["jira_url"];
+	        this.jira_parent_url = source["jira_parent_url"];
+	        this.tags = source["tags"];
+	        this.checklist = this.convertValues(source["checklist"], TaskChecklistItem);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TaskWithProject {
+	    id: number;
+	    project_id: number;
+	    jira_ticket_id: string;
+	    jira_title: string;
+	    jira_status: string;
+	    jira_assignee: string;
+	    title: string;
+	    description: string;
+	    scheduled_date?: time.Time;
+	    deadline?: time.Time;
+	    status: string;
+	    priority: string;
+	    created_at: time.Time;
+	    updated_at: time.Time;
+	    jira_assignee_name: string;
+	    jira_due_date?: time.Time;
+	    jira_sprint: string;
+	    jira_labels: string[];
+	    jira_parent_key: string;
+	    checklist_done: number;
+	    checklist_total: number;
+	    checklist_completion: number;
+	    project_name: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TaskWithProject(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.project_id = source["project_id"];
+	        this.jira_ticket_id = source["jira_ticket_id"];
+	        this.jira_title = source["jira_title"];
+	        this.jira_status = source["jira_status"];
+	        this.jira_assignee = source["jira_assignee"];
 	        this.title = source["title"];
 	        this.description = source["description"];
 	        this.scheduled_date = this.convertValues(source["scheduled_date"], time.Time);
 	        this.deadline = this.convertValues(source["deadline"], time.Time);
 	        this.status = source["status"];
+	        this.priority = source["priority"];
 	        this.created_at = this.convertValues(source["created_at"], time.Time);
 	        this.updated_at = this.convertValues(source["updated_at"], time.Time);
+	        this.jira_assignee_name = source["jira_assignee_name"];
+	        this.jira_due_date = this.convertValues(source["jira_due_date"], time.Time);
+	        this.jira_sprint = source["jira_sprint"];
+	        this.jira_labels = source["jira_labels"];
+	        this.jira_parent_key = source["jira_parent_key"];
+	        this.checklist_done = source["checklist_done"];
+	        this.checklist_total = source["checklist_total"];
+	        this.checklist_completion = source["checklist_completion"];
 	        this.project_name = source["project_name"];
 	    }
 	
@@ -600,6 +4409,366 @@ export namespace types {
 		    return a;
 		}
 	}
+	export class TelemetryCounter {
+	    feature: string;
+	    count: number;
+	    pending: number;
+	    last_used_at?: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new TelemetryCounter(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.feature = source["feature"];
+	        this.count = source["count"];
+	        this.pending = source["pending"];
+	        this.last_used_at = this.convertValues(source["last_used_at"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TelemetrySummary {
+	    enabled: boolean;
+	    endpoint: string;
+	    features: string[];
+	    counters: TelemetryCounter[];
+	    next_payload: string;
+	    last_flush_at?: time.Time;
+	    last_flush_error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TelemetrySummary(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.endpoint = source["endpoint"];
+	        this.features = source["features"];
+	        this.counters = this.convertValues(source["counters"], TelemetryCounter);
+	        this.next_payload = source["next_payload"];
+	        this.last_flush_at = this.convertValues(source["last_flush_at"], time.Time);
+	        this.last_flush_error = source["last_flush_error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TicketCommit {
+	    repository_id: number;
+	    repository_name: string;
+	    hash: string;
+	    message: string;
+	    author: string;
+	    date: time.Time;
+	    url?: string;
+	    date_relative?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TicketCommit(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.repository_id = source["repository_id"];
+	        this.repository_name = source["repository_name"];
+	        this.hash = source["hash"];
+	        this.message = source["message"];
+	        this.author = source["author"];
+	        this.date = this.convertValues(source["date"], time.Time);
+	        this.url = source["url"];
+	        this.date_relative = source["date_relative"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class UsageInsights {
+	    days: number;
+	    since: time.Time;
+	    enabled: boolean;
+	    total_events: number;
+	    event_counts: Record<string, number>;
+	    top_services: ServiceViewCount[];
+	    busiest_hours: HourCount[];
+	
+	    static createFrom(source: any = {}) {
+	        return new UsageInsights(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.days = source["days"];
+	        this.since = this.convertValues(source["since"], time.Time);
+	        this.enabled = source["enabled"];
+	        this.total_events = source["total_events"];
+	        this.event_counts = source["event_counts"];
+	        this.top_services = this.convertValues(source["top_services"], ServiceViewCount);
+	        this.busiest_hours = this.convertValues(source["busiest_hours"], HourCount);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ValidationResult {
+	    success: boolean;
+	    error: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ValidationResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.error = source["error"];
+	    }
+	}
+	export class WatchCondition {
+	    type: string;
+	    threshold?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new WatchCondition(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.type = source["type"];
+	        this.threshold = source["threshold"];
+	    }
+	}
+	export class WatchOutcome {
+	    service_id: number;
+	    service_name: string;
+	    environment?: string;
+	    condition: string;
+	    fired: boolean;
+	    detail: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new WatchOutcome(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.service_id = source["service_id"];
+	        this.service_name = source["service_name"];
+	        this.environment = source["environment"];
+	        this.condition = source["condition"];
+	        this.fired = source["fired"];
+	        this.detail = source["detail"];
+	    }
+	}
+	export class WatchEvaluation {
+	    id: number;
+	    rule_id: number;
+	    evaluated_at: time.Time;
+	    services: number;
+	    fired: number;
+	    outcomes: WatchOutcome[];
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new WatchEvaluation(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.rule_id = source["rule_id"];
+	        this.evaluated_at = this.convertValues(source["evaluated_at"], time.Time);
+	        this.services = source["services"];
+	        this.fired = source["fired"];
+	        this.outcomes = this.convertValues(source["outcomes"], WatchOutcome);
+	        this.error = source["error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class WatchRule {
+	    id: number;
+	    name: string;
+	    enabled: boolean;
+	    scope: string;
+	    service_ids?: number[];
+	    scope_field?: string;
+	    scope_value?: string;
+	    environments: string[];
+	    conditions: WatchCondition[];
+	    last_evaluated_at?: time.Time;
+	    last_triggered_at?: time.Time;
+	    created_at: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new WatchRule(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.enabled = source["enabled"];
+	        this.scope = source["scope"];
+	        this.service_ids = source["service_ids"];
+	        this.scope_field = source["scope_field"];
+	        this.scope_value = source["scope_value"];
+	        this.environments = source["environments"];
+	        this.conditions = this.convertValues(source["conditions"], WatchCondition);
+	        this.last_evaluated_at = this.convertValues(source["last_evaluated_at"], time.Time);
+	        this.last_triggered_at = this.convertValues(source["last_triggered_at"], time.Time);
+	        this.created_at = this.convertValues(source["created_at"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class WebhookStatus {
+	    repository_id: number;
+	    installed: boolean;
+	    missing_on_github: boolean;
+	    hook_id?: number;
+	    target_url?: string;
+	    active: boolean;
+	    events: string[];
+	    recent_deliveries: number;
+	    failed_deliveries: number;
+	    last_delivery_at?: time.Time;
+	    last_delivery_status?: string;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new WebhookStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.repository_id = source["repository_id"];
+	        this.installed = source["installed"];
+	        this.missing_on_github = source["missing_on_github"];
+	        this.hook_id = source["hook_id"];
+	        this.target_url = source["target_url"];
+	        this.active = source["active"];
+	        this.events = source["events"];
+	        this.recent_deliveries = source["recent_deliveries"];
+	        this.failed_deliveries = source["failed_deliveries"];
+	        this.last_delivery_at = this.convertValues(source["last_delivery_at"], time.Time);
+	        this.last_delivery_status = source["last_delivery_status"];
+	        this.error = source["error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 
 }
 
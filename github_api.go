@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"dev-dashboard/internal/github"
+)
+
+// githubAPIBaseURLKey overrides the GitHub API URL, e.g. "http://localhost:8080/" for a mock server
+// or an API proxy. Unlike github_enterprise_url it is used as is, without enterprise handling.
+const githubAPIBaseURLKey = "github_api_base_url"
+
+// githubRequestsPerSecondKey is how many GitHub API requests per second the UI and the background
+// sync may send together on average, e.g. "2.5"; 0 doesn't limit them
+const githubRequestsPerSecondKey = "github_requests_per_second"
+
+// validateGitHubAPIBaseURL checks a github_api_base_url value before it is stored
+func validateGitHubAPIBaseURL(value string) error {
+	_, err := github.ParseAPIBaseURL(value)
+	return err
+}
+
+// parseGitHubRequestsPerSecond parses a github_requests_per_second value
+func parseGitHubRequestsPerSecond(value string) (float64, error) {
+	perSecond, err := strconv.ParseFloat(value, 64)
+	if err != nil || perSecond < 0 {
+		return 0, fmt.Errorf("%s must be a number of requests per second, got %q", githubRequestsPerSecondKey, value)
+	}
+	return perSecond, nil
+}
+
+// applyGitHubRateLimit sets the shared GitHub rate limiter to the github_requests_per_second key
+func (a *App) applyGitHubRateLimit() {
+	if a.githubLimiter == nil || a.configModel == nil {
+		return
+	}
+
+	perSecond := float64(github.DefaultRequestsPerSecond)
+	if config, err := a.configModel.Get(githubRequestsPerSecondKey); err == nil && config != nil && config.Value != "" {
+		parsed, err := parseGitHubRequestsPerSecond(config.Value)
+		if err != nil {
+			log.Printf("Ignoring invalid GitHub rate limit: %v", err)
+		} else {
+			perSecond = parsed
+		}
+	}
+	a.githubLimiter.SetRate(perSecond)
+}
+
+// githubClientOptions returns the options every GitHub client is created with. They all share one
+// rate limiter and record the expiry GitHub reports for the configured token.
+func (a *App) githubClientOptions() []github.Option {
+	var options []github.Option
+	if a.githubLimiter != nil {
+		options = append(options, github.WithRateLimiter(a.githubLimiter))
+	}
+	if value, err := a.GetConfig(githubAPIBaseURLKey); err == nil && value != "" {
+		options = append(options, github.WithBaseURL(value))
+	}
+	options = append(options, github.WithTokenExpirationObserver(a.recordGitHubTokenExpiry))
+	return options
+}
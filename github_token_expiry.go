@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"dev-dashboard/internal/github"
+	"dev-dashboard/pkg/types"
+)
+
+const (
+	// githubTokenExpiresAtKey is the expiry GitHub last reported for the configured token (RFC 3339);
+	// empty when the token doesn't expire or no response reported it yet
+	githubTokenExpiresAtKey = "github_token_expires_at"
+	// githubTokenExpiryWarningDaysKey is how many days before the token expires GetSystemStatus warns
+	// about it; 0 turns the warning off
+	githubTokenExpiryWarningDaysKey = "github_token_expiry_warning_days"
+
+	defaultTokenExpiryWarningDays = 7
+	// tokenExpiryCheckTimeout bounds the check made on startup and when the token changes
+	tokenExpiryCheckTimeout = 15 * time.Second
+)
+
+// tokenExpiryRecorder remembers the expiry last stored, so the responses of every request don't
+// each write the config
+type tokenExpiryRecorder struct {
+	mu       sync.Mutex
+	recorded time.Time
+}
+
+// recordGitHubTokenExpiry stores the expiry a GitHub response reported, if it's for the configured
+// token. Clients created with a token the user typed in, e.g. to test access, are ignored.
+func (a *App) recordGitHubTokenExpiry(token string, expiresAt time.Time) {
+	if a.configModel == nil || token == "" || token != a.getGitHubToken() {
+		return
+	}
+	a.tokenExpiry.mu.Lock()
+	defer a.tokenExpiry.mu.Unlock()
+	if expiresAt.Equal(a.tokenExpiry.recorded) {
+		return
+	}
+	if err := a.configModel.Set(githubTokenExpiresAtKey, expiresAt.UTC().Format(time.RFC3339)); err != nil {
+		log.Printf("Failed to store the GitHub token expiry: %v", err)
+		return
+	}
+	a.tokenExpiry.recorded = expiresAt
+	log.Printf("GitHub token expires at %s", expiresAt.Format(time.RFC3339))
+}
+
+// checkGitHubTokenExpiry asks GitHub for the expiry of the configured token, storing it or, for a
+// token that doesn't expire, forgetting the one stored. The sync keeps it current afterwards.
+func (a *App) checkGitHubTokenExpiry() {
+	token := a.getGitHubToken()
+	if token == "" || a.configModel == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), tokenExpiryCheckTimeout)
+	defer cancel()
+
+	client := github.NewClientWithBaseURL(token, a.getGitHubEnterpriseURL(), a.githubClientOptions()...)
+	expiresAt, err := client.GetTokenExpiration(ctx)
+	if err != nil {
+		log.Printf("Failed to check when the GitHub token expires: %v", err)
+		return
+	}
+	if expiresAt == nil {
+		a.forgetGitHubTokenExpiry()
+	}
+}
+
+// forgetGitHubTokenExpiry clears the stored expiry, e.g. because the token changed
+func (a *App) forgetGitHubTokenExpiry() {
+	a.tokenExpiry.mu.Lock()
+	defer a.tokenExpiry.mu.Unlock()
+	if err := a.configModel.Delete(githubTokenExpiresAtKey); err != nil {
+		log.Printf("Failed to clear the GitHub token expiry: %v", err)
+		return
+	}
+	a.tokenExpiry.recorded = time.Time{}
+}
+
+// getGitHubTokenExpiry returns the stored expiry of the configured token, or nil when it isn't known
+func (a *App) getGitHubTokenExpiry() *time.Time {
+	value, err := a.GetConfig(githubTokenExpiresAtKey)
+	if err != nil || value == "" {
+		return nil
+	}
+	expiresAt, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil
+	}
+	return &expiresAt
+}
+
+// getTokenExpiryWarningDays returns the github_token_expiry_warning_days key
+func (a *App) getTokenExpiryWarningDays() int {
+	if value, err := a.GetConfig(githubTokenExpiryWarningDaysKey); err == nil && value != "" {
+		if days, err := strconv.Atoi(value); err == nil && days >= 0 {
+			return days
+		}
+	}
+	return defaultTokenExpiryWarningDays
+}
+
+// GetSystemStatus reports problems to warn about before they break the sync, such as a GitHub
+// token that expires within the warning window or already expired
+func (a *App) GetSystemStatus() *types.SystemStatus {
+	status := &types.SystemStatus{Warnings: []types.SystemWarning{}}
+	if a.configModel == nil {
+		return status
+	}
+
+	status.GitHubTokenExpiresAt = a.getGitHubTokenExpiry()
+	if status.GitHubTokenExpiresAt != nil {
+		if warning := tokenExpiryWarning(*status.GitHubTokenExpiresAt, a.displayClock(), a.getTokenExpiryWarningDays()); warning != nil {
+			status.Warnings = append(status.Warnings, *warning)
+		}
+	}
+	return status
+}
+
+// tokenExpiryWarning returns the warning about a token expiring at expiresAt, or nil when that's
+// further away than warningDays
+func tokenExpiryWarning(expiresAt time.Time, clock displayClock, warningDays int) *types.SystemWarning {
+	remaining := expiresAt.Sub(clock.now)
+	warning := &types.SystemWarning{Kind: "github_token_expiry", Severity: "warning"}
+	switch hours := int(remaining.Hours()); {
+	case remaining <= 0:
+		warning.Severity = "error"
+		warning.Message = fmt.Sprintf("GitHub token expired on %s; the sync fails until it's replaced in the settings", expiresAt.In(clock.loc).Format("Jan 2, 2006"))
+	case warningDays == 0 || remaining > time.Duration(warningDays)*24*time.Hour:
+		return nil
+	case hours == 0:
+		warning.Message = "GitHub token expires in less than an hour"
+	case hours < 24:
+		warning.Message = fmt.Sprintf("GitHub token expires in %d %s", hours, plural(hours, "hour", "hours"))
+	default:
+		warning.Message = fmt.Sprintf("GitHub token expires in %d %s", hours/24, plural(hours/24, "day", "days"))
+	}
+	return warning
+}
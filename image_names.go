@@ -0,0 +1,24 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SetServiceImageName sets the image a service's kustomizations deploy it as, matched before images
+// named after the service directory; empty goes back to matching by name only. Every kubernetes
+// repository is rescanned on the next sync so deployments follow the new name.
+func (a *App) SetServiceImageName(serviceID int64, imageName string) error {
+	if a.serviceModel == nil {
+		return fmt.Errorf("service model not initialized")
+	}
+	imageName = strings.TrimSpace(imageName)
+	if strings.ContainsAny(imageName, " \t@") || strings.HasSuffix(imageName, "/") {
+		return fmt.Errorf("invalid image name %q", imageName)
+	}
+	if err := a.serviceModel.SetImageName(serviceID, imageName); err != nil {
+		return err
+	}
+	a.resetKubernetesScanTrees()
+	return nil
+}
@@ -0,0 +1,16 @@
+package main
+
+import (
+	"fmt"
+
+	"dev-dashboard/pkg/types"
+)
+
+// GetImageRegistries lists the registries deployments pull their images from and which services
+// use each, e.g. to find the services still on a deprecated registry
+func (a *App) GetImageRegistries() ([]*types.ImageRegistryUsage, error) {
+	if a.deploymentModel == nil {
+		return nil, fmt.Errorf("deployment model not initialized")
+	}
+	return a.deploymentModel.GetRegistryUsage()
+}
@@ -0,0 +1,165 @@
+// Package config declares the config keys the app knows, with their types and validation, so
+// settings with a misspelled key or a malformed value are rejected instead of silently ignored.
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Type is the kind of value a config key holds
+type Type string
+
+const (
+	TypeString Type = "string"
+	TypeBool   Type = "bool"   // "true" or "false"
+	TypeInt    Type = "int"    // a whole number, not negative
+	TypeNumber Type = "number" // a decimal number, not negative
+	TypeEnum   Type = "enum"   // one of Key.Values
+	TypeURL    Type = "url"    // an http or https URL
+	TypeList   Type = "list"   // comma separated values
+)
+
+// Key is a known config key. An empty value is always accepted: it clears the key, and the app
+// falls back to Default.
+type Key struct {
+	Name        string
+	Type        Type
+	Description string
+	// Default describes what an empty value means, for display
+	Default string
+	// Values are the allowed values of enum keys
+	Values []string
+	// Secret keys hold credentials
+	Secret bool
+	// Prefix keys stand for every key starting with Name, e.g. a name followed by a check ID
+	Prefix bool
+	// Internal keys are maintained by the app itself rather than set in the settings
+	Internal bool
+	// Validate checks a non-empty value beyond its type
+	Validate func(value string) error
+}
+
+// Schema is the set of known config keys
+type Schema struct {
+	keys   []Key
+	byName map[string]int
+}
+
+// NewSchema declares the known keys. Names must be unique.
+func NewSchema(keys ...Key) *Schema {
+	schema := &Schema{keys: keys, byName: make(map[string]int, len(keys))}
+	for i, key := range keys {
+		if _, ok := schema.byName[key.Name]; ok {
+			panic(fmt.Sprintf("config key %s declared twice", key.Name))
+		}
+		schema.byName[key.Name] = i
+	}
+	return schema
+}
+
+// Keys returns the known keys ordered by name
+func (s *Schema) Keys() []Key {
+	keys := append([]Key(nil), s.keys...)
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Name < keys[j].Name })
+	return keys
+}
+
+// Lookup returns the key a name is declared as, directly or through a prefix key
+func (s *Schema) Lookup(name string) (*Key, bool) {
+	if i, ok := s.byName[name]; ok && !s.keys[i].Prefix {
+		return &s.keys[i], true
+	}
+	for i, key := range s.keys {
+		if key.Prefix && strings.HasPrefix(name, key.Name) && len(name) > len(key.Name) {
+			return &s.keys[i], true
+		}
+	}
+	return nil, false
+}
+
+// Validate checks a value for a key: the key must be known and a non-empty value must match its
+// type and pass its validation
+func (s *Schema) Validate(name, value string) error {
+	key, ok := s.Lookup(name)
+	if !ok {
+		if suggestion := s.suggest(name); suggestion != "" {
+			return fmt.Errorf("unknown config key %q, did you mean %q?", name, suggestion)
+		}
+		return fmt.Errorf("unknown config key %q", name)
+	}
+	if value == "" {
+		return nil
+	}
+
+	switch key.Type {
+	case TypeBool:
+		if value != "true" && value != "false" {
+			return fmt.Errorf("%s must be true or false, got %q", name, value)
+		}
+	case TypeInt:
+		if n, err := strconv.Atoi(value); err != nil || n < 0 {
+			return fmt.Errorf("%s must be a whole number, got %q", name, value)
+		}
+	case TypeNumber:
+		if n, err := strconv.ParseFloat(value, 64); err != nil || n < 0 {
+			return fmt.Errorf("%s must be a number, got %q", name, value)
+		}
+	case TypeEnum:
+		found := false
+		for _, allowed := range key.Values {
+			found = found || value == allowed
+		}
+		if !found {
+			return fmt.Errorf("%s must be one of %s, got %q", name, strings.Join(key.Values, ", "), value)
+		}
+	case TypeURL:
+		parsed, err := url.Parse(value)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("%s must be an http or https URL, got %q", name, value)
+		}
+	}
+
+	if key.Validate != nil {
+		return key.Validate(value)
+	}
+	return nil
+}
+
+// suggest returns the known key closest to a misspelled name, or an empty string when none is close
+func (s *Schema) suggest(name string) string {
+	best, bestDistance := "", 3
+	for _, key := range s.keys {
+		if key.Prefix {
+			continue
+		}
+		if distance := editDistance(name, key.Name); distance < bestDistance {
+			best, bestDistance = key.Name, distance
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
@@ -0,0 +1,82 @@
+// Package credentials reads a GitHub token from the places a packaged app can find it without the
+// shell's environment: a credentials file in the app's directory and the OS keychain.
+package credentials
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+const (
+	// FileName is the name of the credentials file in the app's directory
+	FileName = "credentials.json"
+	// KeychainService and KeychainAccount identify the keychain entry holding the GitHub token
+	KeychainService = "dev-dashboard"
+	KeychainAccount = "github_token"
+)
+
+// File is the content of the credentials file
+type File struct {
+	GitHubToken string `json:"github_token"`
+}
+
+// ReadFile returns the GitHub token of the credentials file at path, or "" when there's no file.
+// A file other users can read or write is refused, since anyone able to change it could point the
+// app at their own token.
+func ReadFile(path string) (string, error) {
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0o077 != 0 {
+		return "", fmt.Errorf("%s must only be accessible by its owner (chmod 600), its mode is %04o", path, info.Mode().Perm())
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	var file File
+	if err := json.Unmarshal(data, &file); err != nil {
+		return "", fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return strings.TrimSpace(file.GitHubToken), nil
+}
+
+// ReadKeychain returns the GitHub token stored in the OS keychain, using security on macOS and
+// secret-tool (libsecret) on Linux. It returns "" when there's no entry, the tool isn't installed
+// or the platform has no supported keychain.
+func ReadKeychain(ctx context.Context) (string, error) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.CommandContext(ctx, "security", "find-generic-password", "-s", KeychainService, "-a", KeychainAccount, "-w")
+	case "linux":
+		cmd = exec.CommandContext(ctx, "secret-tool", "lookup", "service", KeychainService, "account", KeychainAccount)
+	default:
+		return "", nil
+	}
+
+	output, err := cmd.Output()
+	if errors.Is(err, exec.ErrNotFound) {
+		return "", nil
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && ctx.Err() == nil {
+		// Both tools exit non-zero when there's no such entry
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read the keychain: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
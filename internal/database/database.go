@@ -6,37 +6,71 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
 
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/mattn/go-sqlite3"
 )
 
 //go:embed schema.sql
 var schemaFS embed.FS
 
 type DB struct {
-	conn *sql.DB
+	conn     *sql.DB
+	dsn      string
+	path     string
+	recorder *queryRecorder
+	mu       sync.Mutex
+	writers  []*sql.DB // pools opened by OpenWriter
 }
 
+// busyTimeout is how long a statement waits for another connection's write, e.g. a sync storing
+// what it found, before failing with "database is locked"
+const busyTimeout = 10 * time.Second
+
+// memoryDBCount names in-memory databases so each NewMemoryDB gets its own
+var memoryDBCount atomic.Int64
+
 func NewDB(dbPath string) (*DB, error) {
 	// Create directory if it doesn't exist
 	if err := os.MkdirAll(filepath.Dir(dbPath), 0755); err != nil {
 		return nil, fmt.Errorf("failed to create database directory: %w", err)
 	}
 
-	// Open database with foreign keys enabled by default
-	conn, err := sql.Open("sqlite3", dbPath+"?_foreign_keys=on")
-	if err != nil {
-		return nil, fmt.Errorf("failed to open database: %w", err)
-	}
+	return open(fmt.Sprintf("%s?_foreign_keys=on&_busy_timeout=%d", dbPath, busyTimeout.Milliseconds()), dbPath)
+}
+
+// NewMemoryDB opens a private in-memory database with the full schema, e.g. for tests. The
+// connections of the pool share it through SQLite's shared cache; it is gone once closed.
+func NewMemoryDB() (*DB, error) {
+	name := fmt.Sprintf("file:memdb%d?mode=memory&cache=shared&_foreign_keys=on", memoryDBCount.Add(1))
+	return open(name, "")
+}
+
+// open connects to dsn and creates or migrates the schema. path is the database file, used for
+// backups before destructive migrations.
+func open(dsn, path string) (*DB, error) {
+	// Open database with foreign keys enabled by default, timing every statement
+	recorder := &queryRecorder{threshold: DefaultSlowQueryThreshold}
+	conn := sql.OpenDB(&instrumentedConnector{
+		dsn:      dsn,
+		driver:   &sqlite3.SQLiteDriver{},
+		recorder: recorder,
+	})
 
 	// Enable foreign keys
 	if _, err := conn.Exec("PRAGMA foreign_keys = ON"); err != nil {
 		return nil, fmt.Errorf("failed to enable foreign keys: %w", err)
 	}
 
-	db := &DB{conn: conn}
+	db := &DB{conn: conn, dsn: dsn, path: path, recorder: recorder}
 
 	if err := db.initSchema(); err != nil {
+		// Migration errors carry the backup location, so keep them unwrapped
+		if _, ok := err.(*MigrationError); ok {
+			return nil, err
+		}
 		return nil, fmt.Errorf("failed to initialize schema: %w", err)
 	}
 
@@ -68,250 +102,19 @@ func (db *DB) initSchema() error {
 	return nil
 }
 
-func (db *DB) runMigrations() error {
-	// Check if jira_title column exists in tasks table
-	var columnExists bool
-	err := db.conn.QueryRow(`
-		SELECT COUNT(*) > 0 
-		FROM pragma_table_info('tasks') 
-		WHERE name = 'jira_title'
-	`).Scan(&columnExists)
-	
-	if err != nil {
-		return fmt.Errorf("failed to check for jira_title column: %w", err)
-	}
-
-	// Add jira_title column if it doesn't exist
-	if !columnExists {
-		_, err = db.conn.Exec("ALTER TABLE tasks ADD COLUMN jira_title TEXT")
-		if err != nil {
-			return fmt.Errorf("failed to add jira_title column: %w", err)
-		}
-	}
-
-	// Check if config table exists
-	var configTableExists bool
-	err = db.conn.QueryRow(`
-		SELECT COUNT(*) > 0 
-		FROM sqlite_master 
-		WHERE type='table' AND name='config'
-	`).Scan(&configTableExists)
-	
-	if err != nil {
-		return fmt.Errorf("failed to check for config table: %w", err)
-	}
-
-	// Create config table if it doesn't exist
-	if !configTableExists {
-		_, err = db.conn.Exec(`
-			CREATE TABLE IF NOT EXISTS config (
-				key TEXT PRIMARY KEY,
-				value TEXT NOT NULL,
-				updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
-			)
-		`)
-		if err != nil {
-			return fmt.Errorf("failed to create config table: %w", err)
-		}
-
-		// Add the config table trigger
-		_, err = db.conn.Exec(`
-			CREATE TRIGGER IF NOT EXISTS update_config_updated_at
-				AFTER UPDATE ON config
-			BEGIN
-				UPDATE config SET updated_at = CURRENT_TIMESTAMP WHERE key = NEW.key;
-			END
-		`)
-		if err != nil {
-			return fmt.Errorf("failed to create config trigger: %w", err)
-		}
-
-		// Add the config table index
-		_, err = db.conn.Exec("CREATE INDEX IF NOT EXISTS idx_config_key ON config(key)")
-		if err != nil {
-			return fmt.Errorf("failed to create config index: %w", err)
-		}
-	}
-
-	// Check if deployments table exists
-	var deploymentsTableExists bool
-	err = db.conn.QueryRow(`
-		SELECT COUNT(*) > 0 
-		FROM sqlite_master 
-		WHERE type='table' AND name='deployments'
-	`).Scan(&deploymentsTableExists)
-	
-	if err != nil {
-		return fmt.Errorf("failed to check for deployments table: %w", err)
-	}
-
-	// Create deployments table if it doesn't exist
-	if !deploymentsTableExists {
-		_, err = db.conn.Exec(`
-			CREATE TABLE IF NOT EXISTS deployments (
-				id INTEGER PRIMARY KEY AUTOINCREMENT,
-				service_id INTEGER NOT NULL,
-				kubernetes_repo_id INTEGER NOT NULL,
-				commit_sha TEXT NOT NULL,
-				environment TEXT NOT NULL,
-				region TEXT NOT NULL,
-				tag TEXT NOT NULL,
-				path TEXT NOT NULL,
-				discovered_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-				updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-				FOREIGN KEY (service_id) REFERENCES microservices(id) ON DELETE CASCADE,
-				FOREIGN KEY (kubernetes_repo_id) REFERENCES repositories(id) ON DELETE CASCADE,
-				UNIQUE(service_id, environment, region)
-			)
-		`)
-		if err != nil {
-			return fmt.Errorf("failed to create deployments table: %w", err)
-		}
-
-		// Add indexes for deployments table
-		indexes := []string{
-			"CREATE INDEX IF NOT EXISTS idx_deployments_service_id ON deployments(service_id)",
-			"CREATE INDEX IF NOT EXISTS idx_deployments_kubernetes_repo_id ON deployments(kubernetes_repo_id)",
-			"CREATE INDEX IF NOT EXISTS idx_deployments_commit_sha ON deployments(commit_sha)",
-			"CREATE INDEX IF NOT EXISTS idx_deployments_environment ON deployments(environment)",
-			"CREATE INDEX IF NOT EXISTS idx_deployments_region ON deployments(region)",
-		}
-
-		for _, indexSQL := range indexes {
-			_, err = db.conn.Exec(indexSQL)
-			if err != nil {
-				return fmt.Errorf("failed to create deployments index: %w", err)
-			}
-		}
-
-		// Add the deployments table trigger
-		_, err = db.conn.Exec(`
-			CREATE TRIGGER IF NOT EXISTS update_deployments_updated_at
-				AFTER UPDATE ON deployments
-			BEGIN
-				UPDATE deployments SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
-			END
-		`)
-		if err != nil {
-			return fmt.Errorf("failed to create deployments trigger: %w", err)
-		}
-	} else {
-		// Check if namespace column exists in deployments table
-		var namespaceColumnExists bool
-		err = db.conn.QueryRow(`
-			SELECT COUNT(*) > 0 
-			FROM pragma_table_info('deployments') 
-			WHERE name = 'namespace'
-		`).Scan(&namespaceColumnExists)
-		
-		if err != nil {
-			return fmt.Errorf("failed to check for namespace column: %w", err)
-		}
-
-		// Add namespace column if it doesn't exist
-		if !namespaceColumnExists {
-			_, err = db.conn.Exec("ALTER TABLE deployments ADD COLUMN namespace TEXT")
-			if err != nil {
-				return fmt.Errorf("failed to add namespace column: %w", err)
-			}
-		}
-
-		// Update the unique constraint to include namespace
-		// Since SQLite doesn't support altering constraints, we need to check if the old constraint exists
-		// and recreate the table if necessary
-		var constraintExists bool
-		err = db.conn.QueryRow(`
-			SELECT COUNT(*) > 0 
-			FROM sqlite_master 
-			WHERE type = 'index' 
-			AND tbl_name = 'deployments' 
-			AND sql LIKE '%UNIQUE(service_id, environment, region, namespace)%'
-		`).Scan(&constraintExists)
-		
-		if err != nil {
-			return fmt.Errorf("failed to check for updated unique constraint: %w", err)
-		}
-
-		// If the constraint doesn't include namespace, we need to recreate the table
-		if !constraintExists {
-			// Create a temporary table with the new schema
-			_, err = db.conn.Exec(`
-				CREATE TABLE deployments_new (
-					id INTEGER PRIMARY KEY AUTOINCREMENT,
-					service_id INTEGER NOT NULL,
-					kubernetes_repo_id INTEGER NOT NULL,
-					commit_sha TEXT NOT NULL,
-					environment TEXT NOT NULL,
-					region TEXT NOT NULL,
-					namespace TEXT,
-					tag TEXT NOT NULL,
-					path TEXT NOT NULL,
-					discovered_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-					updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-					FOREIGN KEY (service_id) REFERENCES microservices(id) ON DELETE CASCADE,
-					FOREIGN KEY (kubernetes_repo_id) REFERENCES repositories(id) ON DELETE CASCADE,
-					UNIQUE(service_id, environment, region, namespace)
-				)
-			`)
-			if err != nil {
-				return fmt.Errorf("failed to create new deployments table: %w", err)
-			}
-
-			// Copy data from old table to new table
-			_, err = db.conn.Exec(`
-				INSERT INTO deployments_new (id, service_id, kubernetes_repo_id, commit_sha, environment, region, namespace, tag, path, discovered_at, updated_at)
-				SELECT id, service_id, kubernetes_repo_id, commit_sha, environment, region, namespace, tag, path, discovered_at, updated_at
-				FROM deployments
-			`)
-			if err != nil {
-				return fmt.Errorf("failed to copy data to new deployments table: %w", err)
-			}
-
-			// Drop the old table and rename the new one
-			_, err = db.conn.Exec("DROP TABLE deployments")
-			if err != nil {
-				return fmt.Errorf("failed to drop old deployments table: %w", err)
-			}
-
-			_, err = db.conn.Exec("ALTER TABLE deployments_new RENAME TO deployments")
-			if err != nil {
-				return fmt.Errorf("failed to rename new deployments table: %w", err)
-			}
-
-			// Recreate indexes and triggers
-			indexes := []string{
-				"CREATE INDEX IF NOT EXISTS idx_deployments_service_id ON deployments(service_id)",
-				"CREATE INDEX IF NOT EXISTS idx_deployments_kubernetes_repo_id ON deployments(kubernetes_repo_id)",
-				"CREATE INDEX IF NOT EXISTS idx_deployments_commit_sha ON deployments(commit_sha)",
-				"CREATE INDEX IF NOT EXISTS idx_deployments_environment ON deployments(environment)",
-				"CREATE INDEX IF NOT EXISTS idx_deployments_region ON deployments(region)",
-			}
-
-			for _, indexSQL := range indexes {
-				_, err = db.conn.Exec(indexSQL)
-				if err != nil {
-					return fmt.Errorf("failed to create deployments index: %w", err)
-				}
-			}
-
-			// Recreate the trigger
-			_, err = db.conn.Exec(`
-				CREATE TRIGGER IF NOT EXISTS update_deployments_updated_at
-					AFTER UPDATE ON deployments
-				BEGIN
-					UPDATE deployments SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
-				END
-			`)
-			if err != nil {
-				return fmt.Errorf("failed to create deployments trigger: %w", err)
-			}
-		}
-	}
-
-	return nil
+// Migrate applies the migrations still pending on the database. Opening an existing database
+// already does; fresh ones get the full schema instead, so tests migrate theirs to catch a schema
+// that lacks what migrated databases have.
+func (db *DB) Migrate() error {
+	return db.runMigrations()
 }
 
 func (db *DB) Close() error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	for _, writer := range db.writers {
+		writer.Close()
+	}
 	return db.conn.Close()
 }
 
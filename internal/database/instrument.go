@@ -0,0 +1,250 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// DefaultSlowQueryThreshold is used until the app applies the slow_query_threshold_ms setting
+const DefaultSlowQueryThreshold = 200 * time.Millisecond
+
+// maxSlowQueries is how many slow queries are kept for diagnostics
+const maxSlowQueries = 50
+
+// SlowQuery is a statement that took longer than the slow query threshold
+type SlowQuery struct {
+	SQL        string
+	Duration   time.Duration
+	ExecutedAt time.Time
+}
+
+// queryRecorder times statements and remembers the most recent slow ones
+type queryRecorder struct {
+	mu        sync.Mutex
+	threshold time.Duration
+	slow      []SlowQuery
+	count     int64
+}
+
+func (r *queryRecorder) observe(query string, start time.Time) {
+	elapsed := time.Since(start)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.count++
+	if r.threshold <= 0 || elapsed < r.threshold {
+		return
+	}
+
+	// Model queries span several indented lines; keep the log readable
+	query = strings.Join(strings.Fields(query), " ")
+	log.Printf("Slow query (%s): %s", elapsed.Round(time.Microsecond), query)
+
+	if len(r.slow) == maxSlowQueries {
+		r.slow = append(r.slow[:0], r.slow[1:]...)
+	}
+	r.slow = append(r.slow, SlowQuery{SQL: query, Duration: elapsed, ExecutedAt: start})
+}
+
+// SetSlowQueryThreshold changes how long a statement may take before it is logged as slow.
+// A threshold of zero or less turns slow query logging off.
+func (db *DB) SetSlowQueryThreshold(threshold time.Duration) {
+	db.recorder.mu.Lock()
+	defer db.recorder.mu.Unlock()
+	db.recorder.threshold = threshold
+}
+
+// RecentSlowQueries returns the slow queries recorded since startup, newest first
+func (db *DB) RecentSlowQueries() []SlowQuery {
+	db.recorder.mu.Lock()
+	defer db.recorder.mu.Unlock()
+
+	queries := make([]SlowQuery, len(db.recorder.slow))
+	for i, query := range db.recorder.slow {
+		queries[len(queries)-1-i] = query
+	}
+	return queries
+}
+
+// QueryCount returns how many statements ran since the database was opened, e.g. for tests
+// checking that a read doesn't issue a query per row
+func (db *DB) QueryCount() int64 {
+	db.recorder.mu.Lock()
+	defer db.recorder.mu.Unlock()
+	return db.recorder.count
+}
+
+// instrumentedConnector opens SQLite connections that report statement timings to a recorder,
+// so models can keep using the plain *sql.DB
+type instrumentedConnector struct {
+	dsn      string
+	driver   *sqlite3.SQLiteDriver
+	recorder *queryRecorder
+}
+
+func (c *instrumentedConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.driver.Open(c.dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &instrumentedConn{conn: conn.(*sqlite3.SQLiteConn), recorder: c.recorder}, nil
+}
+
+func (c *instrumentedConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+type instrumentedConn struct {
+	conn       *sqlite3.SQLiteConn
+	recorder   *queryRecorder
+	savepoints int // savepoints begun, naming the next one
+}
+
+func (c *instrumentedConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *instrumentedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	stmt, err := c.conn.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return &instrumentedStmt{stmt: stmt.(*sqlite3.SQLiteStmt), query: query, recorder: c.recorder}, nil
+}
+
+func (c *instrumentedConn) Close() error {
+	return c.conn.Close()
+}
+
+func (c *instrumentedConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+// BeginTx begins a transaction, or a savepoint when a transaction is already open on the
+// connection, as it is for the statements of a Writer's transaction
+func (c *instrumentedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if c.conn.AutoCommit() {
+		return c.conn.BeginTx(ctx, opts)
+	}
+	c.savepoints++
+	savepoint := &savepointTx{conn: c.conn, name: fmt.Sprintf("nested_%d", c.savepoints)}
+	if _, err := c.conn.ExecContext(ctx, "SAVEPOINT "+savepoint.name, nil); err != nil {
+		return nil, err
+	}
+	return savepoint, nil
+}
+
+// savepointTx is a transaction begun inside another one: committing it keeps its writes for the
+// outer transaction to commit, rolling it back discards only them
+type savepointTx struct {
+	conn *sqlite3.SQLiteConn
+	name string
+}
+
+func (s *savepointTx) Commit() error {
+	_, err := s.conn.Exec("RELEASE "+s.name, nil)
+	return err
+}
+
+func (s *savepointTx) Rollback() error {
+	if _, err := s.conn.Exec("ROLLBACK TO "+s.name, nil); err != nil {
+		return err
+	}
+	_, err := s.conn.Exec("RELEASE "+s.name, nil)
+	return err
+}
+
+func (c *instrumentedConn) Ping(ctx context.Context) error {
+	return c.conn.Ping(ctx)
+}
+
+func (c *instrumentedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	start := time.Now()
+	result, err := c.conn.ExecContext(ctx, query, args)
+	c.recorder.observe(query, start)
+	return result, err
+}
+
+func (c *instrumentedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	start := time.Now()
+	rows, err := c.conn.QueryContext(ctx, query, args)
+	if err != nil {
+		c.recorder.observe(query, start)
+		return nil, err
+	}
+	return &instrumentedRows{Rows: rows, query: query, start: start, recorder: c.recorder}, nil
+}
+
+type instrumentedStmt struct {
+	stmt     *sqlite3.SQLiteStmt
+	query    string
+	recorder *queryRecorder
+}
+
+func (s *instrumentedStmt) Close() error {
+	return s.stmt.Close()
+}
+
+func (s *instrumentedStmt) NumInput() int {
+	return s.stmt.NumInput()
+}
+
+func (s *instrumentedStmt) Exec(args []driver.Value) (driver.Result, error) {
+	start := time.Now()
+	result, err := s.stmt.Exec(args)
+	s.recorder.observe(s.query, start)
+	return result, err
+}
+
+func (s *instrumentedStmt) Query(args []driver.Value) (driver.Rows, error) {
+	start := time.Now()
+	rows, err := s.stmt.Query(args)
+	if err != nil {
+		s.recorder.observe(s.query, start)
+		return nil, err
+	}
+	return &instrumentedRows{Rows: rows, query: s.query, start: start, recorder: s.recorder}, nil
+}
+
+func (s *instrumentedStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	start := time.Now()
+	result, err := s.stmt.ExecContext(ctx, args)
+	s.recorder.observe(s.query, start)
+	return result, err
+}
+
+func (s *instrumentedStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	start := time.Now()
+	rows, err := s.stmt.QueryContext(ctx, args)
+	if err != nil {
+		s.recorder.observe(s.query, start)
+		return nil, err
+	}
+	return &instrumentedRows{Rows: rows, query: s.query, start: start, recorder: s.recorder}, nil
+}
+
+// instrumentedRows stops the clock when the rows are closed. SQLite produces rows as they are
+// read, so most of a query's time is spent in Next rather than in the call that started it.
+type instrumentedRows struct {
+	driver.Rows
+	query    string
+	start    time.Time
+	recorder *queryRecorder
+	closed   bool
+}
+
+func (r *instrumentedRows) Close() error {
+	err := r.Rows.Close()
+	if !r.closed {
+		r.closed = true
+		r.recorder.observe(r.query, r.start)
+	}
+	return err
+}
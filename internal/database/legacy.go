@@ -0,0 +1,57 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// HasData reports whether the database at dbPath holds any repositories, projects or tasks. A
+// missing file, or one without those tables, holds none.
+func HasData(dbPath string) (bool, error) {
+	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+		return false, nil
+	}
+
+	conn, err := sql.Open("sqlite3", dbPath+"?mode=ro")
+	if err != nil {
+		return false, fmt.Errorf("failed to open database: %w", err)
+	}
+	defer conn.Close()
+
+	for _, table := range []string{"repositories", "projects", "tasks"} {
+		if missing, err := tableMissing(table)(conn); err != nil {
+			return false, fmt.Errorf("failed to check table %s: %w", table, err)
+		} else if missing {
+			continue
+		}
+		var exists bool
+		if err := conn.QueryRow("SELECT EXISTS (SELECT 1 FROM " + table + ")").Scan(&exists); err != nil {
+			return false, fmt.Errorf("failed to check table %s: %w", table, err)
+		}
+		if exists {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// CopyDatabase writes a consistent copy of the database at src, including changes still in its
+// write-ahead log, to dst, which must not exist yet. Migrations run when dst is opened.
+func CopyDatabase(src, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return fmt.Errorf("failed to create database directory: %w", err)
+	}
+
+	conn, err := sql.Open("sqlite3", src+"?mode=ro")
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.Exec("VACUUM INTO ?", dst); err != nil {
+		return fmt.Errorf("failed to copy database %s: %w", src, err)
+	}
+	return nil
+}
@@ -0,0 +1,1114 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+)
+
+// Migration is a single idempotent schema change applied to an existing database.
+type Migration struct {
+	Name string
+	// Destructive marks migrations that rebuild or drop tables. The database file
+	// is snapshotted before these run and restored if they fail.
+	Destructive bool
+	// DisableForeignKeys turns foreign key enforcement off while the migration runs,
+	// which SQLite requires when rebuilding a table that other tables reference.
+	DisableForeignKeys bool
+	// Pending reports whether the migration still needs to be applied.
+	Pending func(q querier) (bool, error)
+	// Apply performs the migration inside a transaction.
+	Apply func(tx *sql.Tx) error
+}
+
+// MigrationPlan describes a pending migration without applying it.
+type MigrationPlan struct {
+	Name        string `json:"name"`
+	Destructive bool   `json:"destructive"`
+}
+
+// MigrationError is returned when a migration fails during startup.
+type MigrationError struct {
+	Migration  string
+	BackupPath string
+	Restored   bool
+	Err        error
+}
+
+func (e *MigrationError) Error() string {
+	msg := fmt.Sprintf("migration %q failed: %v", e.Migration, e.Err)
+	if e.BackupPath != "" {
+		if e.Restored {
+			msg += fmt.Sprintf(" (database restored from backup %s)", e.BackupPath)
+		} else {
+			msg += fmt.Sprintf(" (backup available at %s)", e.BackupPath)
+		}
+	}
+	return msg
+}
+
+func (e *MigrationError) Unwrap() error {
+	return e.Err
+}
+
+// querier is satisfied by both *sql.DB and *sql.Tx.
+type querier interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+var migrations = []Migration{
+	{
+		Name:    "add tasks.jira_title column",
+		Pending: columnMissing("tasks", "jira_title"),
+		Apply: func(tx *sql.Tx) error {
+			_, err := tx.Exec("ALTER TABLE tasks ADD COLUMN jira_title TEXT")
+			return err
+		},
+	},
+	{
+		Name:    "create config table",
+		Pending: tableMissing("config"),
+		Apply: execAll(
+			`CREATE TABLE IF NOT EXISTS config (
+				key TEXT PRIMARY KEY,
+				value TEXT NOT NULL,
+				updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+			)`,
+			`CREATE TRIGGER IF NOT EXISTS update_config_updated_at
+				AFTER UPDATE ON config
+			BEGIN
+				UPDATE config SET updated_at = CURRENT_TIMESTAMP WHERE key = NEW.key;
+			END`,
+			"CREATE INDEX IF NOT EXISTS idx_config_key ON config(key)",
+		),
+	},
+	{
+		Name:    "create deployments table",
+		Pending: tableMissing("deployments"),
+		Apply: execAll(append([]string{`
+			CREATE TABLE IF NOT EXISTS deployments (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				service_id INTEGER NOT NULL,
+				kubernetes_repo_id INTEGER NOT NULL,
+				commit_sha TEXT NOT NULL,
+				environment TEXT NOT NULL,
+				region TEXT NOT NULL,
+				namespace TEXT,
+				tag TEXT NOT NULL,
+				path TEXT NOT NULL,
+				discovered_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+				updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+				FOREIGN KEY (service_id) REFERENCES microservices(id) ON DELETE CASCADE,
+				FOREIGN KEY (kubernetes_repo_id) REFERENCES repositories(id) ON DELETE CASCADE,
+				UNIQUE(service_id, environment, region, namespace)
+			)`}, deploymentsIndexesAndTriggers...)...),
+	},
+	{
+		Name:    "add deployments.namespace column",
+		Pending: columnMissing("deployments", "namespace"),
+		Apply: func(tx *sql.Tx) error {
+			_, err := tx.Exec("ALTER TABLE deployments ADD COLUMN namespace TEXT")
+			return err
+		},
+	},
+	{
+		// SQLite can't alter constraints, so the table is rebuilt to include
+		// namespace in the unique key.
+		Name:        "rebuild deployments with namespace unique constraint",
+		Destructive: true,
+		Pending: func(q querier) (bool, error) {
+			var exists bool
+			if err := q.QueryRow(`SELECT COUNT(*) > 0 FROM sqlite_master WHERE type='table' AND name='deployments'`).Scan(&exists); err != nil || !exists {
+				return false, err
+			}
+			var hasConstraint bool
+			err := q.QueryRow(`
+				SELECT COUNT(*) > 0
+				FROM sqlite_master
+				WHERE type = 'table'
+				AND name = 'deployments'
+				AND sql LIKE '%UNIQUE(service_id, environment, region, namespace)%'
+			`).Scan(&hasConstraint)
+			return !hasConstraint, err
+		},
+		Apply: execAll(append([]string{
+			`CREATE TABLE deployments_new (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				service_id INTEGER NOT NULL,
+				kubernetes_repo_id INTEGER NOT NULL,
+				commit_sha TEXT NOT NULL,
+				environment TEXT NOT NULL,
+				region TEXT NOT NULL,
+				namespace TEXT,
+				tag TEXT NOT NULL,
+				path TEXT NOT NULL,
+				discovered_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+				updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+				FOREIGN KEY (service_id) REFERENCES microservices(id) ON DELETE CASCADE,
+				FOREIGN KEY (kubernetes_repo_id) REFERENCES repositories(id) ON DELETE CASCADE,
+				UNIQUE(service_id, environment, region, namespace)
+			)`,
+			`INSERT INTO deployments_new (id, service_id, kubernetes_repo_id, commit_sha, environment, region, namespace, tag, path, discovered_at, updated_at)
+				SELECT id, service_id, kubernetes_repo_id, commit_sha, environment, region, namespace, tag, path, discovered_at, updated_at
+				FROM deployments`,
+			"DROP TABLE deployments",
+			"ALTER TABLE deployments_new RENAME TO deployments",
+		}, deploymentsIndexesAndTriggers...)...),
+	},
+	{
+		Name:    "create deployment_history table",
+		Pending: tableMissing("deployment_history"),
+		Apply: execAll(
+			`CREATE TABLE IF NOT EXISTS deployment_history (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				service_id INTEGER NOT NULL,
+				kubernetes_repo_id INTEGER NOT NULL,
+				commit_sha TEXT NOT NULL,
+				environment TEXT NOT NULL,
+				region TEXT NOT NULL,
+				namespace TEXT,
+				tag TEXT NOT NULL,
+				observed_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+				FOREIGN KEY (service_id) REFERENCES microservices(id) ON DELETE CASCADE,
+				FOREIGN KEY (kubernetes_repo_id) REFERENCES repositories(id) ON DELETE CASCADE
+			)`,
+			"CREATE INDEX IF NOT EXISTS idx_deployment_history_service_observed ON deployment_history(service_id, observed_at)",
+		),
+	},
+	{
+		Name:    "create stats_snapshots table",
+		Pending: tableMissing("stats_snapshots"),
+		Apply: execAll(
+			`CREATE TABLE IF NOT EXISTS stats_snapshots (
+				snapshot_date DATE PRIMARY KEY,
+				repositories INTEGER NOT NULL DEFAULT 0,
+				microservices INTEGER NOT NULL DEFAULT 0,
+				kubernetes_resources INTEGER NOT NULL DEFAULT 0,
+				open_tasks INTEGER NOT NULL DEFAULT 0,
+				failing_builds INTEGER NOT NULL DEFAULT 0,
+				created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+			)`,
+		),
+	},
+	{
+		Name:    "add discovery_script column to repositories",
+		Pending: columnMissing("repositories", "discovery_script"),
+		Apply:   execAll("ALTER TABLE repositories ADD COLUMN discovery_script TEXT"),
+	},
+	{
+		Name:    "create sync_logs table",
+		Pending: tableMissing("sync_logs"),
+		Apply: execAll(
+			`CREATE TABLE IF NOT EXISTS sync_logs (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				repository_id INTEGER,
+				level TEXT NOT NULL CHECK (level IN ('info', 'warning', 'error')),
+				message TEXT NOT NULL,
+				created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+				FOREIGN KEY (repository_id) REFERENCES repositories(id) ON DELETE CASCADE
+			)`,
+			"CREATE INDEX IF NOT EXISTS idx_sync_logs_repository_id ON sync_logs(repository_id, created_at)",
+		),
+	},
+	{
+		Name:    "create notifications table",
+		Pending: tableMissing("notifications"),
+		Apply: execAll(
+			`CREATE TABLE IF NOT EXISTS notifications (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				repository_id INTEGER,
+				type TEXT NOT NULL,
+				title TEXT NOT NULL,
+				message TEXT NOT NULL,
+				is_read BOOLEAN NOT NULL DEFAULT 0,
+				created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+				FOREIGN KEY (repository_id) REFERENCES repositories(id) ON DELETE CASCADE
+			)`,
+			"CREATE INDEX IF NOT EXISTS idx_notifications_is_read ON notifications(is_read, created_at)",
+		),
+	},
+	{
+		Name:    "add is_hidden column to microservices",
+		Pending: columnMissing("microservices", "is_hidden"),
+		Apply:   execAll("ALTER TABLE microservices ADD COLUMN is_hidden BOOLEAN NOT NULL DEFAULT 0"),
+	},
+	{
+		Name:    "add scan_tree_sha column to repositories",
+		Pending: columnMissing("repositories", "scan_tree_sha"),
+		Apply:   execAll("ALTER TABLE repositories ADD COLUMN scan_tree_sha TEXT"),
+	},
+	{
+		Name:    "create pending_approvals table",
+		Pending: tableMissing("pending_approvals"),
+		Apply: execAll(
+			`CREATE TABLE IF NOT EXISTS pending_approvals (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				repository_id INTEGER NOT NULL,
+				workflow_run_id INTEGER NOT NULL,
+				environment_id INTEGER NOT NULL,
+				environment_name TEXT NOT NULL,
+				reviewers TEXT,
+				waiting_since DATETIME NOT NULL,
+				can_approve BOOLEAN NOT NULL DEFAULT 0,
+				created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+				FOREIGN KEY (repository_id) REFERENCES repositories(id) ON DELETE CASCADE,
+				UNIQUE(repository_id, workflow_run_id, environment_id)
+			)`,
+		),
+	},
+	{
+		Name:    "create actions_usage table",
+		Pending: tableMissing("actions_usage"),
+		Apply: execAll(
+			`CREATE TABLE IF NOT EXISTS actions_usage (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				repository_id INTEGER NOT NULL,
+				workflow_id INTEGER NOT NULL,
+				workflow_name TEXT NOT NULL,
+				workflow_run_id INTEGER NOT NULL,
+				run_started_at DATETIME NOT NULL,
+				run_duration_ms INTEGER NOT NULL DEFAULT 0,
+				ubuntu_ms INTEGER NOT NULL DEFAULT 0,
+				macos_ms INTEGER NOT NULL DEFAULT 0,
+				windows_ms INTEGER NOT NULL DEFAULT 0,
+				other_ms INTEGER NOT NULL DEFAULT 0,
+				created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+				FOREIGN KEY (repository_id) REFERENCES repositories(id) ON DELETE CASCADE,
+				UNIQUE(repository_id, workflow_run_id)
+			)`,
+			"CREATE INDEX IF NOT EXISTS idx_actions_usage_started ON actions_usage(run_started_at)",
+		),
+	},
+	{
+		Name:    "add conclusion column to actions",
+		Pending: columnMissing("actions", "conclusion"),
+		Apply:   execAll("ALTER TABLE actions ADD COLUMN conclusion TEXT NOT NULL DEFAULT ''"),
+	},
+	{
+		Name:    "create actions service/type/started_at index",
+		Pending: indexMissing("idx_actions_service_type_started"),
+		Apply:   execAll("CREATE INDEX IF NOT EXISTS idx_actions_service_type_started ON actions(service_id, type, started_at)"),
+	},
+	{
+		Name:    "add default_branch column to repositories",
+		Pending: columnMissing("repositories", "default_branch"),
+		Apply:   execAll("ALTER TABLE repositories ADD COLUMN default_branch TEXT"),
+	},
+	{
+		Name:    "add status column to repositories",
+		Pending: columnMissing("repositories", "status"),
+		Apply:   execAll("ALTER TABLE repositories ADD COLUMN status TEXT NOT NULL DEFAULT 'active'"),
+	},
+	{
+		Name:    "add not_found_count column to repositories",
+		Pending: columnMissing("repositories", "not_found_count"),
+		Apply:   execAll("ALTER TABLE repositories ADD COLUMN not_found_count INTEGER NOT NULL DEFAULT 0"),
+	},
+	{
+		Name:    "create repository_webhooks table",
+		Pending: tableMissing("repository_webhooks"),
+		Apply: execAll(
+			`CREATE TABLE IF NOT EXISTS repository_webhooks (
+				repository_id INTEGER PRIMARY KEY,
+				hook_id INTEGER NOT NULL,
+				target_url TEXT NOT NULL,
+				secret TEXT NOT NULL,
+				created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+				FOREIGN KEY (repository_id) REFERENCES repositories(id) ON DELETE CASCADE
+			)`,
+		),
+	},
+	{
+		Name:    "create audit_log table",
+		Pending: tableMissing("audit_log"),
+		Apply: execAll(
+			`CREATE TABLE IF NOT EXISTS audit_log (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				action TEXT NOT NULL,
+				repository_id INTEGER,
+				detail TEXT NOT NULL,
+				success BOOLEAN NOT NULL,
+				created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+			)`,
+			"CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log(created_at)",
+		),
+	},
+	{
+		Name:    "create usage_events table",
+		Pending: tableMissing("usage_events"),
+		Apply: execAll(
+			`CREATE TABLE IF NOT EXISTS usage_events (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				event TEXT NOT NULL,
+				service_id INTEGER,
+				created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+				FOREIGN KEY (service_id) REFERENCES microservices(id) ON DELETE CASCADE
+			)`,
+			"CREATE INDEX IF NOT EXISTS idx_usage_events_created_at ON usage_events(created_at)",
+		),
+	},
+	{
+		Name:    "add jira_status column to tasks",
+		Pending: columnMissing("tasks", "jira_status"),
+		Apply:   execAll("ALTER TABLE tasks ADD COLUMN jira_status TEXT NOT NULL DEFAULT ''"),
+	},
+	{
+		Name:    "add jira_assignee column to tasks",
+		Pending: columnMissing("tasks", "jira_assignee"),
+		Apply:   execAll("ALTER TABLE tasks ADD COLUMN jira_assignee TEXT NOT NULL DEFAULT ''"),
+	},
+	{
+		Name:    "add primary_environment column to microservices",
+		Pending: columnMissing("microservices", "primary_environment"),
+		Apply:   execAll("ALTER TABLE microservices ADD COLUMN primary_environment TEXT NOT NULL DEFAULT ''"),
+	},
+	{
+		Name:    "add owner column to microservices",
+		Pending: columnMissing("microservices", "owner"),
+		Apply:   execAll("ALTER TABLE microservices ADD COLUMN owner TEXT NOT NULL DEFAULT ''"),
+	},
+	{
+		Name:    "add has_readme column to microservices",
+		Pending: columnMissing("microservices", "has_readme"),
+		Apply:   execAll("ALTER TABLE microservices ADD COLUMN has_readme BOOLEAN"),
+	},
+	{
+		Name:    "create service scorecard tables",
+		Pending: tableMissing("scorecard_results"),
+		Apply: execAll(
+			`CREATE TABLE IF NOT EXISTS service_scorecards (
+				service_id INTEGER PRIMARY KEY,
+				grade TEXT NOT NULL,
+				score REAL,
+				evaluated_at DATETIME NOT NULL,
+				FOREIGN KEY (service_id) REFERENCES microservices(id) ON DELETE CASCADE
+			)`,
+			`CREATE TABLE IF NOT EXISTS scorecard_results (
+				service_id INTEGER NOT NULL,
+				check_id TEXT NOT NULL,
+				status TEXT NOT NULL,
+				detail TEXT NOT NULL DEFAULT '',
+				PRIMARY KEY (service_id, check_id),
+				FOREIGN KEY (service_id) REFERENCES microservices(id) ON DELETE CASCADE
+			)`,
+		),
+	},
+	{
+		Name:    "add environment column to notifications",
+		Pending: columnMissing("notifications", "environment"),
+		Apply:   execAll("ALTER TABLE notifications ADD COLUMN environment TEXT NOT NULL DEFAULT ''"),
+	},
+	{
+		Name:    "add deliver_at column to notifications",
+		Pending: columnMissing("notifications", "deliver_at"),
+		Apply:   execAll("ALTER TABLE notifications ADD COLUMN deliver_at DATETIME"),
+	},
+	{
+		Name:    "add sensitive_paths column to repositories",
+		Pending: columnMissing("repositories", "sensitive_paths"),
+		Apply:   execAll("ALTER TABLE repositories ADD COLUMN sensitive_paths TEXT NOT NULL DEFAULT ''"),
+	},
+	{
+		Name:    "add sensitive_paths column to microservices",
+		Pending: columnMissing("microservices", "sensitive_paths"),
+		Apply:   execAll("ALTER TABLE microservices ADD COLUMN sensitive_paths TEXT NOT NULL DEFAULT ''"),
+	},
+	{
+		Name:    "create sensitive_pull_requests table",
+		Pending: tableMissing("sensitive_pull_requests"),
+		Apply: execAll(
+			`CREATE TABLE IF NOT EXISTS sensitive_pull_requests (
+				service_id INTEGER NOT NULL,
+				pr_number INTEGER NOT NULL,
+				files TEXT NOT NULL,
+				flagged_at DATETIME NOT NULL,
+				PRIMARY KEY (service_id, pr_number),
+				FOREIGN KEY (service_id) REFERENCES microservices(id) ON DELETE CASCADE
+			)`,
+		),
+	},
+	{
+		Name:    "add domain column to microservices",
+		Pending: columnMissing("microservices", "domain"),
+		Apply:   execAll("ALTER TABLE microservices ADD COLUMN domain TEXT NOT NULL DEFAULT ''"),
+	},
+	{
+		// Re-syncs used to insert a new row for the same run; keep the latest copy of each run
+		// before enforcing one row per run.
+		Name:        "make actions unique per workflow run",
+		Destructive: true,
+		Pending:     indexMissing("idx_actions_repo_run"),
+		Apply: execAll(
+			`DELETE FROM actions WHERE id NOT IN (
+				SELECT MAX(id) FROM actions GROUP BY repository_id, workflow_run_id
+			)`,
+			"CREATE UNIQUE INDEX IF NOT EXISTS idx_actions_repo_run ON actions(repository_id, workflow_run_id)",
+		),
+	},
+	{
+		// Filled in from the tags at startup
+		Name:    "add semver columns to deployments",
+		Pending: columnMissing("deployments", "version_major"),
+		Apply: execAll(
+			"ALTER TABLE deployments ADD COLUMN version_major INTEGER",
+			"ALTER TABLE deployments ADD COLUMN version_minor INTEGER",
+			"ALTER TABLE deployments ADD COLUMN version_patch INTEGER",
+			"ALTER TABLE deployments ADD COLUMN version_prerelease TEXT NOT NULL DEFAULT ''",
+			"ALTER TABLE deployments ADD COLUMN version_build TEXT NOT NULL DEFAULT ''",
+		),
+	},
+	{
+		Name:    "create custom field tables",
+		Pending: tableMissing("custom_field_definitions"),
+		Apply: execAll(
+			`CREATE TABLE IF NOT EXISTS custom_field_definitions (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				entity_type TEXT NOT NULL,
+				name TEXT NOT NULL,
+				type TEXT NOT NULL,
+				allowed_values TEXT NOT NULL DEFAULT '',
+				created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+				updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+				UNIQUE(entity_type, name)
+			)`,
+			`CREATE TABLE IF NOT EXISTS custom_field_values (
+				field_id INTEGER NOT NULL,
+				entity_id INTEGER NOT NULL,
+				value TEXT NOT NULL,
+				updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+				PRIMARY KEY (field_id, entity_id),
+				FOREIGN KEY (field_id) REFERENCES custom_field_definitions(id) ON DELETE CASCADE
+			)`,
+			`CREATE TRIGGER IF NOT EXISTS delete_microservice_custom_field_values
+				AFTER DELETE ON microservices
+			BEGIN
+				DELETE FROM custom_field_values WHERE entity_id = OLD.id
+					AND field_id IN (SELECT id FROM custom_field_definitions WHERE entity_type = 'service');
+			END`,
+		),
+	},
+	{
+		Name:    "add actual_tag column to deployments",
+		Pending: columnMissing("deployments", "actual_tag"),
+		Apply:   execAll("ALTER TABLE deployments ADD COLUMN actual_tag TEXT"),
+	},
+	{
+		Name:    "add last_sync_error column to repositories",
+		Pending: columnMissing("repositories", "last_sync_error"),
+		Apply:   execAll("ALTER TABLE repositories ADD COLUMN last_sync_error TEXT"),
+	},
+	{
+		Name:    "add access state columns to repositories",
+		Pending: columnMissing("repositories", "access_state"),
+		Apply: execAll(
+			"ALTER TABLE repositories ADD COLUMN access_state TEXT NOT NULL DEFAULT 'ok' CHECK (access_state IN ('ok', 'forbidden', 'not_found', 'token_missing'))",
+			"ALTER TABLE repositories ADD COLUMN access_status INTEGER",
+			"ALTER TABLE repositories ADD COLUMN access_checked_at DATETIME",
+			"ALTER TABLE repositories ADD COLUMN access_failures INTEGER NOT NULL DEFAULT 0",
+			"ALTER TABLE repositories ADD COLUMN access_retry_at DATETIME",
+		),
+	},
+	{
+		Name:    "create task_checklist_items table",
+		Pending: tableMissing("task_checklist_items"),
+		Apply: execAll(
+			`CREATE TABLE IF NOT EXISTS task_checklist_items (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				task_id INTEGER NOT NULL,
+				text TEXT NOT NULL,
+				done BOOLEAN NOT NULL DEFAULT 0,
+				position INTEGER NOT NULL,
+				created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+				updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+				FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE
+			)`,
+			"CREATE INDEX IF NOT EXISTS idx_task_checklist_items_task_id ON task_checklist_items(task_id, position)",
+		),
+	},
+	{
+		Name:    "add sync_state column to repositories",
+		Pending: columnMissing("repositories", "sync_state"),
+		Apply:   execAll("ALTER TABLE repositories ADD COLUMN sync_state TEXT"),
+	},
+	{
+		Name:    "create annotations table",
+		Pending: tableMissing("annotations"),
+		Apply: execAll(
+			`CREATE TABLE IF NOT EXISTS annotations (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				entity_type TEXT NOT NULL CHECK (entity_type IN ('deployment_history', 'commit', 'action')),
+				entity_id TEXT NOT NULL,
+				text TEXT NOT NULL,
+				created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+			)`,
+			"CREATE INDEX IF NOT EXISTS idx_annotations_entity ON annotations(entity_type, entity_id)",
+			"CREATE INDEX IF NOT EXISTS idx_annotations_created_at ON annotations(created_at)",
+			`CREATE TRIGGER IF NOT EXISTS delete_deployment_history_annotations
+				AFTER DELETE ON deployment_history
+			BEGIN
+				DELETE FROM annotations WHERE entity_type = 'deployment_history' AND entity_id = CAST(OLD.id AS TEXT);
+			END`,
+			`CREATE TRIGGER IF NOT EXISTS delete_action_annotations
+				AFTER DELETE ON actions
+			BEGIN
+				DELETE FROM annotations WHERE entity_type = 'action' AND entity_id = CAST(OLD.id AS TEXT);
+			END`,
+		),
+	},
+	{
+		Name:    "add image_repository and registry columns to deployments",
+		Pending: columnMissing("deployments", "image_repository"),
+		Apply: execAll(
+			"ALTER TABLE deployments ADD COLUMN image_repository TEXT",
+			"ALTER TABLE deployments ADD COLUMN registry TEXT",
+			"CREATE INDEX IF NOT EXISTS idx_deployments_registry ON deployments(registry)",
+			// Force a full kustomization scan so existing deployments get their image filled in
+			"UPDATE repositories SET scan_tree_sha = NULL",
+		),
+	},
+	{
+		Name:    "add description_edited column to microservices",
+		Pending: columnMissing("microservices", "description_edited"),
+		Apply:   execAll("ALTER TABLE microservices ADD COLUMN description_edited BOOLEAN NOT NULL DEFAULT 0"),
+	},
+	{
+		Name:    "add manual_sync_only column to repositories",
+		Pending: columnMissing("repositories", "manual_sync_only"),
+		Apply:   execAll("ALTER TABLE repositories ADD COLUMN manual_sync_only BOOLEAN NOT NULL DEFAULT 0"),
+	},
+	{
+		Name:    "create jobs table",
+		Pending: tableMissing("jobs"),
+		Apply: execAll(
+			`CREATE TABLE IF NOT EXISTS jobs (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				kind TEXT NOT NULL,
+				params TEXT NOT NULL DEFAULT '{}',
+				status TEXT NOT NULL DEFAULT 'running' CHECK (status IN ('running', 'succeeded', 'failed', 'cancelled')),
+				progress INTEGER NOT NULL DEFAULT 0,
+				message TEXT,
+				result TEXT,
+				file_path TEXT,
+				error TEXT,
+				created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+				finished_at DATETIME,
+				acknowledged_at DATETIME
+			)`,
+			"CREATE INDEX IF NOT EXISTS idx_jobs_acknowledged_at ON jobs(acknowledged_at)",
+		),
+	},
+	{
+		// Merges services whose paths only differed in formatting, so snapshot the database first
+		Name:        "normalize repository, service and deployment paths",
+		Destructive: true,
+		Pending:     repoPathsUnclean,
+		Apply:       normalizeRepoPaths,
+	},
+	{
+		Name:    "add discovery_review column to repositories",
+		Pending: columnMissing("repositories", "discovery_review"),
+		Apply:   execAll("ALTER TABLE repositories ADD COLUMN discovery_review BOOLEAN NOT NULL DEFAULT 0"),
+	},
+	{
+		Name:    "create pending_discovery_changes table",
+		Pending: tableMissing("pending_discovery_changes"),
+		Apply: execAll(
+			`CREATE TABLE IF NOT EXISTS pending_discovery_changes (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				repository_id INTEGER NOT NULL,
+				kind TEXT NOT NULL CHECK (kind IN ('add', 'remove', 'rename')),
+				status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'rejected', 'expired')),
+				service_id INTEGER,
+				name TEXT NOT NULL,
+				path TEXT NOT NULL,
+				old_name TEXT NOT NULL DEFAULT '',
+				old_path TEXT NOT NULL DEFAULT '',
+				description TEXT NOT NULL DEFAULT '',
+				domain TEXT NOT NULL DEFAULT '',
+				has_readme BOOLEAN,
+				detected_at DATETIME NOT NULL,
+				FOREIGN KEY (repository_id) REFERENCES repositories(id) ON DELETE CASCADE,
+				FOREIGN KEY (service_id) REFERENCES microservices(id) ON DELETE CASCADE,
+				UNIQUE(repository_id, kind, name, path, old_name, old_path)
+			)`,
+			"CREATE INDEX IF NOT EXISTS idx_pending_discovery_changes_repository_id ON pending_discovery_changes(repository_id)",
+		),
+	},
+	{
+		Name:    "create watch_rules and watch_rule_evaluations tables",
+		Pending: tableMissing("watch_rule_evaluations"),
+		Apply: execAll(
+			`CREATE TABLE IF NOT EXISTS watch_rules (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				name TEXT NOT NULL,
+				enabled BOOLEAN NOT NULL DEFAULT 1,
+				scope TEXT NOT NULL CHECK (scope IN ('all', 'services', 'custom_field', 'domain')),
+				service_ids TEXT NOT NULL DEFAULT '[]',
+				scope_field TEXT NOT NULL DEFAULT '',
+				scope_value TEXT NOT NULL DEFAULT '',
+				environments TEXT NOT NULL DEFAULT '[]',
+				conditions TEXT NOT NULL,
+				active_keys TEXT NOT NULL DEFAULT '[]',
+				last_evaluated_at DATETIME,
+				last_triggered_at DATETIME,
+				created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+			)`,
+			`CREATE TABLE IF NOT EXISTS watch_rule_evaluations (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				rule_id INTEGER NOT NULL,
+				evaluated_at DATETIME NOT NULL,
+				services INTEGER NOT NULL DEFAULT 0,
+				fired INTEGER NOT NULL DEFAULT 0,
+				outcomes TEXT NOT NULL DEFAULT '[]',
+				error TEXT,
+				FOREIGN KEY (rule_id) REFERENCES watch_rules(id) ON DELETE CASCADE
+			)`,
+			"CREATE INDEX IF NOT EXISTS idx_watch_rule_evaluations_rule_id ON watch_rule_evaluations(rule_id, evaluated_at)",
+		),
+	},
+	{
+		Name:    "add JIRA card columns to tasks",
+		Pending: columnMissing("tasks", "jira_sprint"),
+		Apply: execAll(
+			"ALTER TABLE tasks ADD COLUMN jira_assignee_name TEXT",
+			"ALTER TABLE tasks ADD COLUMN jira_due_date DATE",
+			"ALTER TABLE tasks ADD COLUMN jira_sprint TEXT",
+			"ALTER TABLE tasks ADD COLUMN jira_labels TEXT",
+			"ALTER TABLE tasks ADD COLUMN jira_parent_key TEXT",
+		),
+	},
+	{
+		// Rows from before the namespace column have a NULL namespace, which the unique key treats
+		// as distinct from every other value, so rescans added a namespaced row next to them. The
+		// newest NULL row of a target is merged into its namespaced row (taking its values when it
+		// was updated more recently) and the rest get '' so the unique key covers them. Pending
+		// until no NULL namespace is left, so duplicates are checked for at every startup. Deletes
+		// the merged rows, so the database is snapshotted first.
+		Name:        "merge deployments without namespace into their namespaced duplicates",
+		Destructive: true,
+		Pending: func(q querier) (bool, error) {
+			var pending bool
+			err := q.QueryRow("SELECT COUNT(*) > 0 FROM deployments WHERE namespace IS NULL").Scan(&pending)
+			return pending, err
+		},
+		Apply: execAll(
+			`DELETE FROM deployments
+				WHERE namespace IS NULL AND EXISTS (
+					SELECT 1 FROM deployments newer
+					WHERE newer.namespace IS NULL AND newer.service_id = deployments.service_id
+						AND newer.environment = deployments.environment AND newer.region = deployments.region
+						AND (newer.updated_at > deployments.updated_at OR (newer.updated_at = deployments.updated_at AND newer.id > deployments.id))
+				)`,
+			`UPDATE deployments AS kept
+				SET kubernetes_repo_id = stale.kubernetes_repo_id, commit_sha = stale.commit_sha, tag = stale.tag, path = stale.path,
+					version_major = stale.version_major, version_minor = stale.version_minor, version_patch = stale.version_patch,
+					version_prerelease = stale.version_prerelease, version_build = stale.version_build, actual_tag = stale.actual_tag,
+					image_repository = stale.image_repository, registry = stale.registry, discovered_at = MIN(kept.discovered_at, stale.discovered_at)
+				FROM deployments AS stale
+				WHERE stale.namespace IS NULL AND kept.namespace IS NOT NULL AND stale.updated_at > kept.updated_at
+					AND kept.service_id = stale.service_id AND kept.environment = stale.environment AND kept.region = stale.region
+					AND (
+						SELECT COUNT(*) FROM deployments other
+						WHERE other.namespace IS NOT NULL AND other.service_id = stale.service_id
+							AND other.environment = stale.environment AND other.region = stale.region
+					) = 1`,
+			`DELETE FROM deployments
+				WHERE namespace IS NULL AND EXISTS (
+					SELECT 1 FROM deployments namespaced
+					WHERE namespaced.namespace IS NOT NULL AND namespaced.service_id = deployments.service_id
+						AND namespaced.environment = deployments.environment AND namespaced.region = deployments.region
+				)`,
+			"UPDATE deployments SET namespace = '' WHERE namespace IS NULL",
+		),
+	},
+	{
+		Name:    "add priority column to tasks",
+		Pending: columnMissing("tasks", "priority"),
+		Apply:   execAll("ALTER TABLE tasks ADD COLUMN priority TEXT NOT NULL DEFAULT 'medium' CHECK (priority IN ('low', 'medium', 'high'))"),
+	},
+	{
+		Name:    "create env_var_snapshots table",
+		Pending: tableMissing("env_var_snapshots"),
+		Apply: execAll(
+			`CREATE TABLE IF NOT EXISTS env_var_snapshots (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				service_id INTEGER NOT NULL,
+				environment TEXT NOT NULL,
+				region TEXT NOT NULL,
+				path TEXT NOT NULL,
+				fingerprint TEXT NOT NULL,
+				variables TEXT NOT NULL DEFAULT '[]',
+				updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+				FOREIGN KEY (service_id) REFERENCES microservices(id) ON DELETE CASCADE,
+				UNIQUE(service_id, environment, region)
+			)`,
+		),
+	},
+	{
+		Name:    "create telemetry_counters table",
+		Pending: tableMissing("telemetry_counters"),
+		Apply: execAll(
+			`CREATE TABLE IF NOT EXISTS telemetry_counters (
+				feature TEXT PRIMARY KEY,
+				count INTEGER NOT NULL DEFAULT 0,
+				flushed_count INTEGER NOT NULL DEFAULT 0,
+				last_used_at DATETIME
+			)`,
+		),
+	},
+	{
+		Name:    "create security_alert_reports table",
+		Pending: tableMissing("security_alert_reports"),
+		Apply: execAll(
+			`CREATE TABLE IF NOT EXISTS security_alert_reports (
+				repository_id INTEGER NOT NULL,
+				source TEXT NOT NULL,
+				available BOOLEAN NOT NULL DEFAULT 1,
+				detail TEXT NOT NULL DEFAULT '',
+				critical INTEGER NOT NULL DEFAULT 0,
+				high INTEGER NOT NULL DEFAULT 0,
+				medium INTEGER NOT NULL DEFAULT 0,
+				low INTEGER NOT NULL DEFAULT 0,
+				top_alerts TEXT NOT NULL DEFAULT '[]',
+				checked_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+				PRIMARY KEY (repository_id, source),
+				FOREIGN KEY (repository_id) REFERENCES repositories(id) ON DELETE CASCADE
+			)`,
+		),
+	},
+	{
+		Name:    "add image name columns to microservices",
+		Pending: columnMissing("microservices", "image_name"),
+		Apply: execAll(
+			"ALTER TABLE microservices ADD COLUMN image_name TEXT NOT NULL DEFAULT ''",
+			"ALTER TABLE microservices ADD COLUMN suggested_image_name TEXT NOT NULL DEFAULT ''",
+		),
+	},
+	{
+		Name:    "create commit_ticket_refs table",
+		Pending: tableMissing("commit_ticket_refs"),
+		Apply: execAll(
+			`CREATE TABLE IF NOT EXISTS commit_ticket_refs (
+				repository_id INTEGER NOT NULL,
+				commit_sha TEXT NOT NULL,
+				ticket_key TEXT NOT NULL,
+				message TEXT NOT NULL DEFAULT '',
+				author TEXT NOT NULL DEFAULT '',
+				committed_at DATETIME,
+				PRIMARY KEY (repository_id, commit_sha, ticket_key),
+				FOREIGN KEY (repository_id) REFERENCES repositories(id) ON DELETE CASCADE
+			)`,
+			"CREATE INDEX IF NOT EXISTS idx_commit_ticket_refs_ticket_key ON commit_ticket_refs(ticket_key)",
+		),
+	},
+	{
+		Name:    "create service_summaries table",
+		Pending: tableMissing("service_summaries"),
+		Apply: execAll(
+			`CREATE TABLE IF NOT EXISTS service_summaries (
+				service_id INTEGER PRIMARY KEY,
+				last_build TEXT,
+				last_deployment TEXT,
+				environments TEXT NOT NULL DEFAULT '[]',
+				open_pull_requests INTEGER,
+				ticket_keys TEXT,
+				updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+				FOREIGN KEY (service_id) REFERENCES microservices(id) ON DELETE CASCADE
+			)`,
+		),
+	},
+	{
+		Name:    "add correlation status columns to deployments",
+		Pending: columnMissing("deployments", "correlation_status"),
+		Apply: execAll(
+			"ALTER TABLE deployments ADD COLUMN correlation_status TEXT NOT NULL DEFAULT ''",
+			"ALTER TABLE deployments ADD COLUMN uncorrelated_since DATETIME",
+		),
+	},
+	{
+		Name:    "add is_favorite column to repositories",
+		Pending: columnMissing("repositories", "is_favorite"),
+		Apply:   execAll("ALTER TABLE repositories ADD COLUMN is_favorite BOOLEAN NOT NULL DEFAULT 0"),
+	},
+	{
+		Name:    "create freeze_windows table",
+		Pending: tableMissing("freeze_windows"),
+		Apply: execAll(
+			`CREATE TABLE IF NOT EXISTS freeze_windows (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				environment_pattern TEXT NOT NULL,
+				starts_at DATETIME,
+				ends_at DATETIME,
+				recurrence TEXT NOT NULL DEFAULT '',
+				reason TEXT NOT NULL DEFAULT '',
+				created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+				updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+			)`,
+		),
+	},
+	{
+		Name:    "add freeze_window_id column to deployment_history",
+		Pending: columnMissing("deployment_history", "freeze_window_id"),
+		Apply: execAll(
+			"ALTER TABLE deployment_history ADD COLUMN freeze_window_id INTEGER REFERENCES freeze_windows(id) ON DELETE SET NULL",
+			"CREATE INDEX IF NOT EXISTS idx_deployment_history_freeze_window ON deployment_history(freeze_window_id)",
+		),
+	},
+	{
+		Name:    "add collect_packages column to repositories",
+		Pending: columnMissing("repositories", "collect_packages"),
+		Apply:   execAll("ALTER TABLE repositories ADD COLUMN collect_packages BOOLEAN NOT NULL DEFAULT 0"),
+	},
+	{
+		Name:    "create service package tables",
+		Pending: tableMissing("service_dependencies_pkg"),
+		Apply: execAll(
+			`CREATE TABLE IF NOT EXISTS service_package_manifests (
+				service_id INTEGER NOT NULL,
+				path TEXT NOT NULL,
+				ecosystem TEXT NOT NULL,
+				blob_sha TEXT NOT NULL,
+				parse_error TEXT NOT NULL DEFAULT '',
+				parsed_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+				PRIMARY KEY (service_id, path),
+				FOREIGN KEY (service_id) REFERENCES microservices(id) ON DELETE CASCADE
+			)`,
+			`CREATE TABLE IF NOT EXISTS service_dependencies_pkg (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				service_id INTEGER NOT NULL,
+				manifest_path TEXT NOT NULL,
+				ecosystem TEXT NOT NULL,
+				name TEXT NOT NULL,
+				version TEXT NOT NULL,
+				dev BOOLEAN NOT NULL DEFAULT 0,
+				FOREIGN KEY (service_id, manifest_path) REFERENCES service_package_manifests(service_id, path) ON DELETE CASCADE,
+				UNIQUE(service_id, manifest_path, name)
+			)`,
+			`CREATE TABLE IF NOT EXISTS package_versions (
+				ecosystem TEXT NOT NULL,
+				name TEXT NOT NULL,
+				latest_version TEXT NOT NULL DEFAULT '',
+				error TEXT NOT NULL DEFAULT '',
+				checked_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+				PRIMARY KEY (ecosystem, name)
+			)`,
+			"CREATE INDEX IF NOT EXISTS idx_service_dependencies_pkg_name ON service_dependencies_pkg(ecosystem, name)",
+		),
+	},
+	{
+		Name:    "add workflow_branches column to repositories",
+		Pending: columnMissing("repositories", "workflow_branches"),
+		Apply:   execAll("ALTER TABLE repositories ADD COLUMN workflow_branches TEXT NOT NULL DEFAULT ''"),
+	},
+}
+
+var deploymentsIndexesAndTriggers = []string{
+	"CREATE INDEX IF NOT EXISTS idx_deployments_service_id ON deployments(service_id)",
+	"CREATE INDEX IF NOT EXISTS idx_deployments_kubernetes_repo_id ON deployments(kubernetes_repo_id)",
+	"CREATE INDEX IF NOT EXISTS idx_deployments_commit_sha ON deployments(commit_sha)",
+	"CREATE INDEX IF NOT EXISTS idx_deployments_environment ON deployments(environment)",
+	"CREATE INDEX IF NOT EXISTS idx_deployments_region ON deployments(region)",
+	`CREATE TRIGGER IF NOT EXISTS update_deployments_updated_at
+		AFTER UPDATE ON deployments
+	BEGIN
+		UPDATE deployments SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+	END`,
+}
+
+func tableMissing(table string) func(q querier) (bool, error) {
+	return func(q querier) (bool, error) {
+		var exists bool
+		err := q.QueryRow(`SELECT COUNT(*) > 0 FROM sqlite_master WHERE type='table' AND name=?`, table).Scan(&exists)
+		return !exists, err
+	}
+}
+
+func indexMissing(index string) func(q querier) (bool, error) {
+	return func(q querier) (bool, error) {
+		var exists bool
+		err := q.QueryRow(`SELECT COUNT(*) > 0 FROM sqlite_master WHERE type='index' AND name=?`, index).Scan(&exists)
+		return !exists, err
+	}
+}
+
+// columnMissing reports true only when the table exists without the column.
+func columnMissing(table, column string) func(q querier) (bool, error) {
+	return func(q querier) (bool, error) {
+		missing, err := tableMissing(table)(q)
+		if err != nil || missing {
+			return false, err
+		}
+		var exists bool
+		err = q.QueryRow(`SELECT COUNT(*) > 0 FROM pragma_table_info(?) WHERE name = ?`, table, column).Scan(&exists)
+		return !exists, err
+	}
+}
+
+func execAll(statements ...string) func(tx *sql.Tx) error {
+	return func(tx *sql.Tx) error {
+		for _, stmt := range statements {
+			if _, err := tx.Exec(stmt); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func (db *DB) runMigrations() error {
+	for i, m := range migrations {
+		pending, err := m.Pending(db.conn)
+		if err != nil {
+			return fmt.Errorf("failed to check migration %q: %w", m.Name, err)
+		}
+		if !pending {
+			continue
+		}
+
+		if err := db.applyMigration(i, m); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applyMigration runs the migration at index in the migration list
+func (db *DB) applyMigration(index int, m Migration) error {
+	var backupPath string
+	// In-memory databases have no file to snapshot or restore
+	if m.Destructive && db.path != "" {
+		var err error
+		backupPath, err = db.backup(index)
+		if err != nil {
+			return &MigrationError{Migration: m.Name, Err: fmt.Errorf("failed to back up database: %w", err)}
+		}
+		log.Printf("Backed up database to %s before destructive migration %q", backupPath, m.Name)
+	}
+
+	if err := db.execMigration(m); err != nil {
+		migrationErr := &MigrationError{Migration: m.Name, BackupPath: backupPath, Err: err}
+		if backupPath != "" {
+			if restoreErr := db.restore(backupPath); restoreErr != nil {
+				log.Printf("Failed to restore database from %s: %v", backupPath, restoreErr)
+			} else {
+				migrationErr.Restored = true
+			}
+		}
+		return migrationErr
+	}
+
+	return nil
+}
+
+func (db *DB) execMigration(m Migration) error {
+	ctx := context.Background()
+
+	// Pin a single connection so PRAGMA changes apply to the migration transaction
+	conn, err := db.conn.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Close()
+
+	if m.DisableForeignKeys {
+		if _, err := conn.ExecContext(ctx, "PRAGMA foreign_keys = OFF"); err != nil {
+			return fmt.Errorf("failed to disable foreign keys: %w", err)
+		}
+		defer conn.ExecContext(ctx, "PRAGMA foreign_keys = ON")
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := m.Apply(tx); err != nil {
+		return err
+	}
+
+	if m.DisableForeignKeys {
+		var violations int
+		if err := tx.QueryRow("SELECT COUNT(*) FROM pragma_foreign_key_check").Scan(&violations); err != nil {
+			return fmt.Errorf("failed to check foreign keys: %w", err)
+		}
+		if violations > 0 {
+			return fmt.Errorf("migration left %d foreign key violations", violations)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// backup snapshots the database next to the original file using VACUUM INTO, before the migration
+// at index. Several destructive migrations can run within a second, so the name carries the
+// nanoseconds and the migration's index; VACUUM INTO fails on an existing file.
+func (db *DB) backup(index int) (string, error) {
+	backupPath := fmt.Sprintf("%s.backup-%s-%d", db.path, time.Now().Format("20060102-150405.000000000"), index)
+	if _, err := db.conn.Exec("VACUUM INTO ?", backupPath); err != nil {
+		return "", err
+	}
+	return backupPath, nil
+}
+
+// restore closes the connection and copies the backup over the database file.
+// The DB must not be used afterwards.
+func (db *DB) restore(backupPath string) error {
+	if err := db.conn.Close(); err != nil {
+		return fmt.Errorf("failed to close database: %w", err)
+	}
+
+	// Stale journals would otherwise be replayed against the restored file
+	for _, suffix := range []string{"-journal", "-wal", "-shm"} {
+		os.Remove(db.path + suffix)
+	}
+
+	src, err := os.Open(backupPath)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.Create(db.path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return err
+	}
+	return dst.Close()
+}
+
+// PendingMigrations reports the migrations that would run against the database
+// at dbPath without applying them. A missing database yields no migrations since
+// the full schema is created fresh.
+func PendingMigrations(dbPath string) ([]MigrationPlan, error) {
+	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	conn, err := sql.Open("sqlite3", dbPath+"?mode=ro")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	defer conn.Close()
+
+	var plans []MigrationPlan
+	for _, m := range migrations {
+		pending, err := m.Pending(conn)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check migration %q: %w", m.Name, err)
+		}
+		if pending {
+			plans = append(plans, MigrationPlan{Name: m.Name, Destructive: m.Destructive})
+		}
+	}
+
+	return plans, nil
+}
@@ -0,0 +1,200 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestFreshSchemaHasEveryMigration(t *testing.T) {
+	db, err := NewMemoryDB()
+	if err != nil {
+		t.Fatalf("NewMemoryDB: %v", err)
+	}
+	defer db.Close()
+
+	for _, m := range migrations {
+		pending, err := m.Pending(db.conn)
+		if err != nil {
+			t.Fatalf("checking migration %q: %v", m.Name, err)
+		}
+		if pending {
+			t.Errorf("migration %q is pending on a fresh database; schema.sql is missing its change", m.Name)
+		}
+	}
+}
+
+// withMigrations appends extra migrations to the migration list for the rest of the test
+func withMigrations(t *testing.T, extra ...Migration) {
+	t.Helper()
+	original := migrations
+	migrations = append(append([]Migration{}, original...), extra...)
+	t.Cleanup(func() { migrations = original })
+}
+
+func alwaysPending(querier) (bool, error) {
+	return true, nil
+}
+
+// newFileDB creates a database file holding one repository and returns its path
+func newFileDB(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "database.db")
+	db, err := NewDB(path)
+	if err != nil {
+		t.Fatalf("NewDB: %v", err)
+	}
+	_, err = db.conn.Exec(`INSERT INTO repositories (name, url, type) VALUES ('api', 'https://github.com/acme/api', 'monorepo')`)
+	if err != nil {
+		t.Fatalf("failed to insert repository: %v", err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	return path
+}
+
+func repositoryCount(t *testing.T, path string) int {
+	t.Helper()
+	conn, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatalf("failed to open %s: %v", path, err)
+	}
+	defer conn.Close()
+	var count int
+	if err := conn.QueryRow("SELECT COUNT(*) FROM repositories").Scan(&count); err != nil {
+		t.Fatalf("failed to count repositories: %v", err)
+	}
+	return count
+}
+
+func TestFailedDestructiveMigrationRestoresBackup(t *testing.T) {
+	path := newFileDB(t)
+	withMigrations(t, Migration{
+		Name:        "drop repositories",
+		Destructive: true,
+		Pending:     alwaysPending,
+		Apply: func(tx *sql.Tx) error {
+			if _, err := tx.Exec("DROP TABLE repositories"); err != nil {
+				return err
+			}
+			return errors.New("rebuild failed")
+		},
+	})
+
+	_, err := NewDB(path)
+	var migrationErr *MigrationError
+	if !errors.As(err, &migrationErr) {
+		t.Fatalf("NewDB returned %v, want a MigrationError", err)
+	}
+	if migrationErr.Migration != "drop repositories" || !migrationErr.Restored {
+		t.Errorf("got %+v, want the failed migration restored from its backup", migrationErr)
+	}
+	if _, err := os.Stat(migrationErr.BackupPath); err != nil {
+		t.Errorf("backup %q is missing: %v", migrationErr.BackupPath, err)
+	}
+	if count := repositoryCount(t, path); count != 1 {
+		t.Errorf("restored database has %d repositories, want 1", count)
+	}
+}
+
+func TestDestructiveMigrationsInOneSecondGetTheirOwnBackups(t *testing.T) {
+	path := newFileDB(t)
+	withMigrations(t,
+		Migration{
+			Name:        "rebuild scratch",
+			Destructive: true,
+			Pending:     tableMissing("scratch"),
+			Apply:       execAll("CREATE TABLE scratch (id INTEGER)"),
+		},
+		Migration{
+			Name:        "rebuild scratch again",
+			Destructive: true,
+			Pending:     alwaysPending,
+			Apply: func(tx *sql.Tx) error {
+				return errors.New("rebuild failed")
+			},
+		},
+	)
+
+	_, err := NewDB(path)
+	var migrationErr *MigrationError
+	if !errors.As(err, &migrationErr) || migrationErr.Migration != "rebuild scratch again" {
+		t.Fatalf("NewDB returned %v, want the second migration to fail", err)
+	}
+	backups, err := filepath.Glob(path + ".backup-*")
+	if err != nil {
+		t.Fatalf("Glob: %v", err)
+	}
+	if len(backups) != 2 {
+		t.Errorf("got backups %v, want one per destructive migration", backups)
+	}
+	if !migrationErr.Restored {
+		t.Error("the second migration should be restored from its own backup")
+	}
+}
+
+func TestNullNamespaceDeploymentsMergeIntoNamespacedDuplicates(t *testing.T) {
+	db, err := NewMemoryDB()
+	if err != nil {
+		t.Fatalf("NewMemoryDB: %v", err)
+	}
+	defer db.Close()
+	for _, statement := range []string{
+		`INSERT INTO repositories (id, name, url, type) VALUES (1, 'platform', 'https://github.com/acme/platform', 'monorepo')`,
+		`INSERT INTO repositories (id, name, url, type) VALUES (2, 'k8s', 'https://github.com/acme/k8s', 'kubernetes')`,
+		`INSERT INTO microservices (id, repository_id, name, path) VALUES (1, 1, 'api', 'services/api')`,
+		// dev: the row from before namespaces was updated after its namespaced duplicate
+		`INSERT INTO deployments (service_id, kubernetes_repo_id, commit_sha, environment, region, namespace, tag, path, updated_at)
+			VALUES (1, 2, 'aaa', 'dev', 'eu', NULL, 'v2.0.0', 'dev/eu', '2024-03-02 00:00:00')`,
+		`INSERT INTO deployments (service_id, kubernetes_repo_id, commit_sha, environment, region, namespace, tag, path, updated_at)
+			VALUES (1, 2, 'bbb', 'dev', 'eu', 'payments', 'v1.0.0', 'dev/eu', '2024-03-01 00:00:00')`,
+		// prd: the namespaced row is the newer one
+		`INSERT INTO deployments (service_id, kubernetes_repo_id, commit_sha, environment, region, namespace, tag, path, updated_at)
+			VALUES (1, 2, 'ccc', 'prd', 'eu', NULL, 'v1.0.0', 'prd/eu', '2024-03-01 00:00:00')`,
+		`INSERT INTO deployments (service_id, kubernetes_repo_id, commit_sha, environment, region, namespace, tag, path, updated_at)
+			VALUES (1, 2, 'ddd', 'prd', 'eu', 'payments', 'v3.0.0', 'prd/eu', '2024-03-02 00:00:00')`,
+		// stg: two rows from before namespaces and no namespaced one
+		`INSERT INTO deployments (service_id, kubernetes_repo_id, commit_sha, environment, region, namespace, tag, path, updated_at)
+			VALUES (1, 2, 'eee', 'stg', 'eu', NULL, 'v4.0.0', 'stg/eu', '2024-03-01 00:00:00')`,
+		`INSERT INTO deployments (service_id, kubernetes_repo_id, commit_sha, environment, region, namespace, tag, path, updated_at)
+			VALUES (1, 2, 'fff', 'stg', 'eu', NULL, 'v5.0.0', 'stg/eu', '2024-03-02 00:00:00')`,
+	} {
+		if _, err := db.conn.Exec(statement); err != nil {
+			t.Fatalf("failed to insert fixture: %v", err)
+		}
+	}
+
+	if err := db.Migrate(); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+
+	rows, err := db.conn.Query(`SELECT environment, namespace, commit_sha, tag FROM deployments ORDER BY environment`)
+	if err != nil {
+		t.Fatalf("failed to list deployments: %v", err)
+	}
+	defer rows.Close()
+	var got []string
+	for rows.Next() {
+		var environment, commitSHA, tag string
+		var namespace sql.NullString
+		if err := rows.Scan(&environment, &namespace, &commitSHA, &tag); err != nil {
+			t.Fatalf("failed to scan deployment: %v", err)
+		}
+		if !namespace.Valid {
+			t.Errorf("the %s deployment still has a NULL namespace", environment)
+		}
+		got = append(got, environment+" "+namespace.String+" "+commitSHA+" "+tag)
+	}
+	want := []string{
+		"dev payments aaa v2.0.0",
+		"prd payments ddd v3.0.0",
+		"stg  fff v5.0.0",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("got deployments %q, want one per target, the newer values kept: %q", got, want)
+	}
+}
@@ -0,0 +1,152 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"dev-dashboard/internal/vcs"
+)
+
+// uncleanPathCondition matches values vcs.CleanRepoPath would change, leaving out the ones it
+// rejects for their ".." segments, which normalizeRepoPaths leaves alone
+const uncleanPathCondition = `(%[1]s != TRIM(%[1]s) OR %[1]s = '.' OR %[1]s LIKE '/%%' OR %[1]s LIKE '%%/'
+	OR %[1]s LIKE '%%//%%' OR %[1]s LIKE './%%' OR %[1]s LIKE '%%/./%%' OR %[1]s LIKE '%%/.')
+	AND NOT (%[1]s = '..' OR %[1]s LIKE '../%%' OR %[1]s LIKE '%%/..' OR %[1]s LIKE '%%/../%%')`
+
+// repoPathsUnclean reports whether a repository's service location, a service's path or a
+// deployment's path isn't in the form vcs.CleanServiceLocation or vcs.CleanRepoPath returns
+func repoPathsUnclean(q querier) (bool, error) {
+	var unclean bool
+	err := q.QueryRow(fmt.Sprintf(`SELECT
+		EXISTS(SELECT 1 FROM repositories WHERE service_location IS NOT NULL AND service_location != '.' AND %s)
+		OR EXISTS(SELECT 1 FROM microservices WHERE %s)
+		OR EXISTS(SELECT 1 FROM deployments WHERE %s)`,
+		fmt.Sprintf(uncleanPathCondition, "service_location"),
+		fmt.Sprintf(uncleanPathCondition, "path"),
+		fmt.Sprintf(uncleanPathCondition, "path"))).Scan(&unclean)
+	return unclean, err
+}
+
+// normalizeRepoPaths rewrites service locations with vcs.CleanServiceLocation, and service paths and
+// deployment paths with vcs.CleanRepoPath. Services of a repository whose paths only differed in formatting are merged
+// into the oldest one: their actions, deployments, history, usage and custom field values move
+// over where the oldest has none of its own, and the duplicates are deleted.
+func normalizeRepoPaths(tx *sql.Tx) error {
+	if err := normalizeColumn(tx, "repositories", "service_location", vcs.CleanServiceLocation); err != nil {
+		return err
+	}
+	if err := normalizeColumn(tx, "deployments", "path", vcs.CleanRepoPath); err != nil {
+		return err
+	}
+
+	type servicePath struct {
+		id, repositoryID int64
+		name, path       string
+	}
+	rows, err := tx.Query(`SELECT id, repository_id, name, path FROM microservices ORDER BY id`)
+	if err != nil {
+		return err
+	}
+	var services []servicePath
+	for rows.Next() {
+		var service servicePath
+		if err := rows.Scan(&service.id, &service.repositoryID, &service.name, &service.path); err != nil {
+			rows.Close()
+			return err
+		}
+		services = append(services, service)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	kept := make(map[string]int64)
+	for _, service := range services {
+		cleaned, err := vcs.CleanRepoPath(service.path)
+		if err != nil {
+			log.Printf("Leaving invalid path %q of service %s as is: %v", service.path, service.name, err)
+			continue
+		}
+		key := fmt.Sprintf("%d|%s", service.repositoryID, cleaned)
+		keepID, duplicate := kept[key]
+		if !duplicate {
+			kept[key] = service.id
+			if cleaned != service.path {
+				if _, err := tx.Exec(`UPDATE microservices SET path = ? WHERE id = ?`, cleaned, service.id); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		log.Printf("Merging service %s (%d) into %d, their paths only differ in formatting", service.name, service.id, keepID)
+		if err := mergeService(tx, service.id, keepID); err != nil {
+			return fmt.Errorf("failed to merge service %d into %d: %w", service.id, keepID, err)
+		}
+	}
+	return nil
+}
+
+func normalizeColumn(tx *sql.Tx, table, column string, clean func(string) (string, error)) error {
+	rows, err := tx.Query(fmt.Sprintf(`SELECT id, %s FROM %s WHERE %s IS NOT NULL`, column, table, column))
+	if err != nil {
+		return err
+	}
+	cleaned := make(map[int64]string)
+	for rows.Next() {
+		var id int64
+		var value string
+		if err := rows.Scan(&id, &value); err != nil {
+			rows.Close()
+			return err
+		}
+		normalized, err := clean(value)
+		if err != nil {
+			log.Printf("Leaving invalid %s.%s %q of row %d as is: %v", table, column, value, id, err)
+			continue
+		}
+		if normalized != value {
+			cleaned[id] = normalized
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for id, value := range cleaned {
+		if _, err := tx.Exec(fmt.Sprintf(`UPDATE %s SET %s = ? WHERE id = ?`, table, column), value, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeService moves the rows referencing service fromID to service toID, fills toID's owner and
+// primary environment when it has none, and deletes fromID. Rows that would collide with one toID
+// already has are deleted with fromID.
+func mergeService(tx *sql.Tx, fromID, toID int64) error {
+	statements := []string{
+		`UPDATE actions SET service_id = ?1 WHERE service_id = ?2`,
+		`UPDATE deployment_history SET service_id = ?1 WHERE service_id = ?2`,
+		`UPDATE usage_events SET service_id = ?1 WHERE service_id = ?2`,
+		`UPDATE OR IGNORE deployments SET service_id = ?1 WHERE service_id = ?2`,
+		`UPDATE OR IGNORE sensitive_pull_requests SET service_id = ?1 WHERE service_id = ?2`,
+		`UPDATE OR IGNORE custom_field_values SET entity_id = ?1 WHERE entity_id = ?2
+			AND field_id IN (SELECT id FROM custom_field_definitions WHERE entity_type = 'service')`,
+		`UPDATE microservices SET
+			owner = CASE WHEN owner = '' THEN (SELECT owner FROM microservices WHERE id = ?2) ELSE owner END,
+			primary_environment = CASE WHEN primary_environment = ''
+				THEN (SELECT primary_environment FROM microservices WHERE id = ?2) ELSE primary_environment END
+			WHERE id = ?1`,
+	}
+	for _, stmt := range statements {
+		if _, err := tx.Exec(stmt, toID, fromID); err != nil {
+			return err
+		}
+	}
+	_, err := tx.Exec(`DELETE FROM microservices WHERE id = ?`, fromID)
+	return err
+}
@@ -6,6 +6,24 @@ CREATE TABLE IF NOT EXISTS repositories (
     description TEXT,
     service_name TEXT,
     service_location TEXT,
+    discovery_script TEXT,
+    scan_tree_sha TEXT,
+    default_branch TEXT,
+    sensitive_paths TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'active',
+    not_found_count INTEGER NOT NULL DEFAULT 0,
+    last_sync_error TEXT,
+    access_state TEXT NOT NULL DEFAULT 'ok' CHECK (access_state IN ('ok', 'forbidden', 'not_found', 'token_missing')),
+    access_status INTEGER,
+    access_checked_at DATETIME,
+    access_failures INTEGER NOT NULL DEFAULT 0,
+    access_retry_at DATETIME,
+    sync_state TEXT, -- JSON checkpoint of a sync pass in progress, NULL when none is
+    manual_sync_only BOOLEAN NOT NULL DEFAULT 0, -- left out of scheduled syncs
+    discovery_review BOOLEAN NOT NULL DEFAULT 0, -- discovered service adds, removals and renames wait for review
+    is_favorite BOOLEAN NOT NULL DEFAULT 0, -- listed first, in the quick access list
+    collect_packages BOOLEAN NOT NULL DEFAULT 0, -- sync reads services' go.mod and package.json
+    workflow_branches TEXT NOT NULL DEFAULT '', -- branch patterns whose workflow runs are synced, '' for the default branch and release/*
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     last_sync_at DATETIME
@@ -17,6 +35,15 @@ CREATE TABLE IF NOT EXISTS microservices (
     name TEXT NOT NULL,
     path TEXT NOT NULL,
     description TEXT,
+    description_edited BOOLEAN NOT NULL DEFAULT 0, -- set by a catalog import; discovery then keeps the description
+    is_hidden BOOLEAN NOT NULL DEFAULT 0,
+    primary_environment TEXT NOT NULL DEFAULT '',
+    owner TEXT NOT NULL DEFAULT '',
+    has_readme BOOLEAN,
+    sensitive_paths TEXT NOT NULL DEFAULT '',
+    domain TEXT NOT NULL DEFAULT '',
+    image_name TEXT NOT NULL DEFAULT '', -- image kustomizations deploy the service as; empty matches images by service name
+    suggested_image_name TEXT NOT NULL DEFAULT '', -- image the deployment scan found for the service when nothing matched
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     FOREIGN KEY (repository_id) REFERENCES repositories(id) ON DELETE CASCADE,
@@ -43,6 +70,7 @@ CREATE TABLE IF NOT EXISTS actions (
     resource_id INTEGER,
     type TEXT NOT NULL CHECK (type IN ('build', 'deployment')),
     status TEXT NOT NULL,
+    conclusion TEXT NOT NULL DEFAULT '',
     workflow_run_id INTEGER NOT NULL,
     commit_sha TEXT NOT NULL,
     branch TEXT NOT NULL,
@@ -69,17 +97,36 @@ CREATE TABLE IF NOT EXISTS tasks (
     project_id INTEGER NOT NULL,
     jira_ticket_id TEXT NOT NULL,
     jira_title TEXT,
+    jira_status TEXT NOT NULL DEFAULT '',
+    jira_assignee TEXT NOT NULL DEFAULT '',
+    jira_assignee_name TEXT,
+    jira_due_date DATE,
+    jira_sprint TEXT,
+    jira_labels TEXT, -- JSON array
+    jira_parent_key TEXT,
     title TEXT NOT NULL,
     description TEXT,
     scheduled_date DATE,
     deadline DATE,
     status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'in_progress', 'completed')),
+    priority TEXT NOT NULL DEFAULT 'medium' CHECK (priority IN ('low', 'medium', 'high')),
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
     UNIQUE(project_id, jira_ticket_id)
 );
 
+CREATE TABLE IF NOT EXISTS task_checklist_items (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    task_id INTEGER NOT NULL,
+    text TEXT NOT NULL,
+    done BOOLEAN NOT NULL DEFAULT 0,
+    position INTEGER NOT NULL, -- order within the task, from 0
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE
+);
+
 CREATE TABLE IF NOT EXISTS deployments (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
     service_id INTEGER NOT NULL,
@@ -87,16 +134,357 @@ CREATE TABLE IF NOT EXISTS deployments (
     commit_sha TEXT NOT NULL,
     environment TEXT NOT NULL,
     region TEXT NOT NULL,
-    namespace TEXT,
+    namespace TEXT, -- '' when not namespaced; NULL only in rows from before namespaces, merged at startup
     tag TEXT NOT NULL,
     path TEXT NOT NULL,
     discovered_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    version_major INTEGER,
+    version_minor INTEGER,
+    version_patch INTEGER,
+    version_prerelease TEXT NOT NULL DEFAULT '',
+    version_build TEXT NOT NULL DEFAULT '',
+    actual_tag TEXT, -- what the cluster runs; NULL until recorded, meaning the same as tag
+    image_repository TEXT, -- image the tag applies to, from the kustomization's newName or name
+    registry TEXT, -- registry host of image_repository
+    correlation_status TEXT NOT NULL DEFAULT '', -- correlated, uncorrelated (commit_sha is the kubernetes repository's, retried) or abandoned
+    uncorrelated_since DATETIME, -- when the current tag was first left uncorrelated
     FOREIGN KEY (service_id) REFERENCES microservices(id) ON DELETE CASCADE,
     FOREIGN KEY (kubernetes_repo_id) REFERENCES repositories(id) ON DELETE CASCADE,
     UNIQUE(service_id, environment, region, namespace)
 );
 
+CREATE TABLE IF NOT EXISTS deployment_history (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    service_id INTEGER NOT NULL,
+    kubernetes_repo_id INTEGER NOT NULL,
+    commit_sha TEXT NOT NULL,
+    environment TEXT NOT NULL,
+    region TEXT NOT NULL,
+    namespace TEXT,
+    tag TEXT NOT NULL,
+    observed_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    freeze_window_id INTEGER, -- the freeze window the entry fell into when it was recorded
+    FOREIGN KEY (service_id) REFERENCES microservices(id) ON DELETE CASCADE,
+    FOREIGN KEY (kubernetes_repo_id) REFERENCES repositories(id) ON DELETE CASCADE,
+    FOREIGN KEY (freeze_window_id) REFERENCES freeze_windows(id) ON DELETE SET NULL
+);
+
+-- Change freezes; one-off windows have both bounds, recurring ones a recurrence spec
+CREATE TABLE IF NOT EXISTS freeze_windows (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    environment_pattern TEXT NOT NULL, -- path.Match pattern, e.g. prd-*
+    starts_at DATETIME,
+    ends_at DATETIME,
+    recurrence TEXT NOT NULL DEFAULT '', -- e.g. "fri 16:00-mon 09:00" or "daily 18:00-08:00", local time
+    reason TEXT NOT NULL DEFAULT '',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS stats_snapshots (
+    snapshot_date DATE PRIMARY KEY,
+    repositories INTEGER NOT NULL DEFAULT 0,
+    microservices INTEGER NOT NULL DEFAULT 0,
+    kubernetes_resources INTEGER NOT NULL DEFAULT 0,
+    open_tasks INTEGER NOT NULL DEFAULT 0,
+    failing_builds INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS sync_logs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    repository_id INTEGER,
+    level TEXT NOT NULL CHECK (level IN ('info', 'warning', 'error')),
+    message TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (repository_id) REFERENCES repositories(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    repository_id INTEGER,
+    type TEXT NOT NULL,
+    title TEXT NOT NULL,
+    message TEXT NOT NULL,
+    environment TEXT NOT NULL DEFAULT '',
+    is_read BOOLEAN NOT NULL DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    deliver_at DATETIME,
+    FOREIGN KEY (repository_id) REFERENCES repositories(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS pending_approvals (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    repository_id INTEGER NOT NULL,
+    workflow_run_id INTEGER NOT NULL,
+    environment_id INTEGER NOT NULL,
+    environment_name TEXT NOT NULL,
+    reviewers TEXT,
+    waiting_since DATETIME NOT NULL,
+    can_approve BOOLEAN NOT NULL DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (repository_id) REFERENCES repositories(id) ON DELETE CASCADE,
+    UNIQUE(repository_id, workflow_run_id, environment_id)
+);
+
+CREATE TABLE IF NOT EXISTS actions_usage (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    repository_id INTEGER NOT NULL,
+    workflow_id INTEGER NOT NULL,
+    workflow_name TEXT NOT NULL,
+    workflow_run_id INTEGER NOT NULL,
+    run_started_at DATETIME NOT NULL,
+    run_duration_ms INTEGER NOT NULL DEFAULT 0,
+    ubuntu_ms INTEGER NOT NULL DEFAULT 0,
+    macos_ms INTEGER NOT NULL DEFAULT 0,
+    windows_ms INTEGER NOT NULL DEFAULT 0,
+    other_ms INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (repository_id) REFERENCES repositories(id) ON DELETE CASCADE,
+    UNIQUE(repository_id, workflow_run_id)
+);
+
+CREATE TABLE IF NOT EXISTS repository_webhooks (
+    repository_id INTEGER PRIMARY KEY,
+    hook_id INTEGER NOT NULL,
+    target_url TEXT NOT NULL,
+    secret TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (repository_id) REFERENCES repositories(id) ON DELETE CASCADE
+);
+
+-- No foreign key so entries outlive the repositories they mention
+CREATE TABLE IF NOT EXISTS audit_log (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    action TEXT NOT NULL,
+    repository_id INTEGER,
+    detail TEXT NOT NULL,
+    success BOOLEAN NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS usage_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    event TEXT NOT NULL,
+    service_id INTEGER,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (service_id) REFERENCES microservices(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS service_scorecards (
+    service_id INTEGER PRIMARY KEY,
+    grade TEXT NOT NULL,
+    score REAL,
+    evaluated_at DATETIME NOT NULL,
+    FOREIGN KEY (service_id) REFERENCES microservices(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS scorecard_results (
+    service_id INTEGER NOT NULL,
+    check_id TEXT NOT NULL,
+    status TEXT NOT NULL,
+    detail TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (service_id, check_id),
+    FOREIGN KEY (service_id) REFERENCES microservices(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS sensitive_pull_requests (
+    service_id INTEGER NOT NULL,
+    pr_number INTEGER NOT NULL,
+    files TEXT NOT NULL,
+    flagged_at DATETIME NOT NULL,
+    PRIMARY KEY (service_id, pr_number),
+    FOREIGN KEY (service_id) REFERENCES microservices(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS custom_field_definitions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    entity_type TEXT NOT NULL, -- service
+    name TEXT NOT NULL,
+    type TEXT NOT NULL, -- text, enum or bool
+    allowed_values TEXT NOT NULL DEFAULT '', -- enum values, one per line
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(entity_type, name)
+);
+
+CREATE TABLE IF NOT EXISTS custom_field_values (
+    field_id INTEGER NOT NULL,
+    entity_id INTEGER NOT NULL, -- id in the table of the definition's entity type
+    value TEXT NOT NULL,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (field_id, entity_id),
+    FOREIGN KEY (field_id) REFERENCES custom_field_definitions(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS annotations (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    entity_type TEXT NOT NULL CHECK (entity_type IN ('deployment_history', 'commit', 'action')),
+    entity_id TEXT NOT NULL, -- row id, or the full SHA for commits
+    text TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Long-running operations run in the background; finished jobs stay listed until acknowledged
+CREATE TABLE IF NOT EXISTS jobs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    kind TEXT NOT NULL,
+    params TEXT NOT NULL DEFAULT '{}', -- JSON object
+    status TEXT NOT NULL DEFAULT 'running' CHECK (status IN ('running', 'succeeded', 'failed', 'cancelled')),
+    progress INTEGER NOT NULL DEFAULT 0, -- percent
+    message TEXT,
+    result TEXT, -- JSON
+    file_path TEXT,
+    error TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    finished_at DATETIME,
+    acknowledged_at DATETIME
+);
+
+-- Service changes found by syncs of repositories in discovery review mode, waiting to be applied.
+-- Rejected and expired changes stay until discovery stops reporting them.
+CREATE TABLE IF NOT EXISTS pending_discovery_changes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    repository_id INTEGER NOT NULL,
+    kind TEXT NOT NULL CHECK (kind IN ('add', 'remove', 'rename')),
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'rejected', 'expired')),
+    service_id INTEGER, -- service removed or renamed
+    name TEXT NOT NULL,
+    path TEXT NOT NULL,
+    old_name TEXT NOT NULL DEFAULT '',
+    old_path TEXT NOT NULL DEFAULT '',
+    description TEXT NOT NULL DEFAULT '',
+    domain TEXT NOT NULL DEFAULT '',
+    has_readme BOOLEAN,
+    detected_at DATETIME NOT NULL,
+    FOREIGN KEY (repository_id) REFERENCES repositories(id) ON DELETE CASCADE,
+    FOREIGN KEY (service_id) REFERENCES microservices(id) ON DELETE CASCADE,
+    UNIQUE(repository_id, kind, name, path, old_name, old_path)
+);
+
+CREATE TABLE IF NOT EXISTS watch_rules (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT 1,
+    scope TEXT NOT NULL CHECK (scope IN ('all', 'services', 'custom_field', 'domain')),
+    service_ids TEXT NOT NULL DEFAULT '[]', -- JSON array, for the services scope
+    scope_field TEXT NOT NULL DEFAULT '',
+    scope_value TEXT NOT NULL DEFAULT '',
+    environments TEXT NOT NULL DEFAULT '[]', -- JSON array, empty for all
+    conditions TEXT NOT NULL, -- JSON array of {type, threshold}
+    active_keys TEXT NOT NULL DEFAULT '[]', -- JSON array of the state conditions that held at the last evaluation
+    last_evaluated_at DATETIME,
+    last_triggered_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- The most recent evaluations of each watch rule, for debugging why it did or didn't fire
+CREATE TABLE IF NOT EXISTS watch_rule_evaluations (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    rule_id INTEGER NOT NULL,
+    evaluated_at DATETIME NOT NULL,
+    services INTEGER NOT NULL DEFAULT 0,
+    fired INTEGER NOT NULL DEFAULT 0,
+    outcomes TEXT NOT NULL DEFAULT '[]', -- JSON array
+    error TEXT,
+    FOREIGN KEY (rule_id) REFERENCES watch_rules(id) ON DELETE CASCADE
+);
+
+-- Environment variables of each service's Deployment per overlay, refreshed when a manifest the
+-- overlay reads changes
+CREATE TABLE IF NOT EXISTS env_var_snapshots (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    service_id INTEGER NOT NULL,
+    environment TEXT NOT NULL,
+    region TEXT NOT NULL,
+    path TEXT NOT NULL, -- kustomization the variables were read from
+    fingerprint TEXT NOT NULL, -- hash of the blob SHAs of the manifests read
+    variables TEXT NOT NULL DEFAULT '[]', -- JSON array of EnvVar; secret values are never stored
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (service_id) REFERENCES microservices(id) ON DELETE CASCADE,
+    UNIQUE(service_id, environment, region)
+);
+
+-- Manifests read for a service's packages, re-parsed only when their blob SHA changes
+CREATE TABLE IF NOT EXISTS service_package_manifests (
+    service_id INTEGER NOT NULL,
+    path TEXT NOT NULL, -- repository path, e.g. services/api/go.mod
+    ecosystem TEXT NOT NULL, -- go or npm
+    blob_sha TEXT NOT NULL,
+    parse_error TEXT NOT NULL DEFAULT '',
+    parsed_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (service_id, path),
+    FOREIGN KEY (service_id) REFERENCES microservices(id) ON DELETE CASCADE
+);
+
+-- Direct dependencies declared in a service's manifests
+CREATE TABLE IF NOT EXISTS service_dependencies_pkg (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    service_id INTEGER NOT NULL,
+    manifest_path TEXT NOT NULL,
+    ecosystem TEXT NOT NULL,
+    name TEXT NOT NULL,
+    version TEXT NOT NULL, -- as declared: a module version or an npm version range
+    dev BOOLEAN NOT NULL DEFAULT 0, -- a devDependency
+    FOREIGN KEY (service_id, manifest_path) REFERENCES service_package_manifests(service_id, path) ON DELETE CASCADE,
+    UNIQUE(service_id, manifest_path, name)
+);
+
+-- Latest versions looked up in the Go module proxy and the npm registry
+CREATE TABLE IF NOT EXISTS package_versions (
+    ecosystem TEXT NOT NULL,
+    name TEXT NOT NULL,
+    latest_version TEXT NOT NULL DEFAULT '',
+    error TEXT NOT NULL DEFAULT '', -- why the lookup failed, e.g. a private module
+    checked_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (ecosystem, name)
+);
+
+CREATE TABLE IF NOT EXISTS security_alert_reports (
+    repository_id INTEGER NOT NULL,
+    source TEXT NOT NULL, -- dependabot or code_scanning
+    available BOOLEAN NOT NULL DEFAULT 1,
+    detail TEXT NOT NULL DEFAULT '', -- why the alerts couldn't be read
+    critical INTEGER NOT NULL DEFAULT 0,
+    high INTEGER NOT NULL DEFAULT 0,
+    medium INTEGER NOT NULL DEFAULT 0,
+    low INTEGER NOT NULL DEFAULT 0,
+    top_alerts TEXT NOT NULL DEFAULT '[]', -- JSON array of SecurityAlert
+    checked_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (repository_id, source),
+    FOREIGN KEY (repository_id) REFERENCES repositories(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS telemetry_counters (
+    feature TEXT PRIMARY KEY, -- one of the features in internal/telemetry
+    count INTEGER NOT NULL DEFAULT 0,
+    flushed_count INTEGER NOT NULL DEFAULT 0, -- part of count already sent to the telemetry endpoint
+    last_used_at DATETIME
+);
+
+CREATE TABLE IF NOT EXISTS commit_ticket_refs (
+    repository_id INTEGER NOT NULL,
+    commit_sha TEXT NOT NULL,
+    ticket_key TEXT NOT NULL, -- JIRA issue key the commit message mentions
+    message TEXT NOT NULL DEFAULT '',
+    author TEXT NOT NULL DEFAULT '',
+    committed_at DATETIME,
+    PRIMARY KEY (repository_id, commit_sha, ticket_key),
+    FOREIGN KEY (repository_id) REFERENCES repositories(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS service_summaries (
+    service_id INTEGER PRIMARY KEY,
+    last_build TEXT, -- JSON of the service's most recent build action, NULL without builds
+    last_deployment TEXT, -- JSON of the service's most recent deployment action, NULL without any
+    environments TEXT NOT NULL DEFAULT '[]', -- JSON array of the most recently updated deployment per environment
+    open_pull_requests INTEGER, -- NULL until the service's pull requests have been fetched
+    ticket_keys TEXT, -- JSON array of the JIRA keys the service's last fetched commits mention, NULL until fetched
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (service_id) REFERENCES microservices(id) ON DELETE CASCADE
+);
+
 CREATE TABLE IF NOT EXISTS config (
     key TEXT PRIMARY KEY,
     value TEXT NOT NULL,
@@ -113,18 +501,36 @@ CREATE INDEX IF NOT EXISTS idx_actions_resource_id ON actions(resource_id);
 CREATE INDEX IF NOT EXISTS idx_actions_type ON actions(type);
 CREATE INDEX IF NOT EXISTS idx_actions_status ON actions(status);
 CREATE INDEX IF NOT EXISTS idx_actions_started_at ON actions(started_at);
+CREATE INDEX IF NOT EXISTS idx_actions_service_type_started ON actions(service_id, type, started_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_actions_repo_run ON actions(repository_id, workflow_run_id);
 CREATE INDEX IF NOT EXISTS idx_deployments_service_id ON deployments(service_id);
 CREATE INDEX IF NOT EXISTS idx_deployments_kubernetes_repo_id ON deployments(kubernetes_repo_id);
 CREATE INDEX IF NOT EXISTS idx_deployments_commit_sha ON deployments(commit_sha);
 CREATE INDEX IF NOT EXISTS idx_deployments_environment ON deployments(environment);
 CREATE INDEX IF NOT EXISTS idx_deployments_region ON deployments(region);
+CREATE INDEX IF NOT EXISTS idx_deployments_registry ON deployments(registry);
+CREATE INDEX IF NOT EXISTS idx_deployment_history_service_observed ON deployment_history(service_id, observed_at);
+CREATE INDEX IF NOT EXISTS idx_deployment_history_freeze_window ON deployment_history(freeze_window_id);
+CREATE INDEX IF NOT EXISTS idx_sync_logs_repository_id ON sync_logs(repository_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_notifications_is_read ON notifications(is_read, created_at);
+CREATE INDEX IF NOT EXISTS idx_actions_usage_started ON actions_usage(run_started_at);
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log(created_at);
+CREATE INDEX IF NOT EXISTS idx_usage_events_created_at ON usage_events(created_at);
 CREATE INDEX IF NOT EXISTS idx_projects_name ON projects(name);
 CREATE INDEX IF NOT EXISTS idx_tasks_project_id ON tasks(project_id);
 CREATE INDEX IF NOT EXISTS idx_tasks_deadline ON tasks(deadline);
 CREATE INDEX IF NOT EXISTS idx_tasks_scheduled_date ON tasks(scheduled_date);
 CREATE INDEX IF NOT EXISTS idx_tasks_status ON tasks(status);
 CREATE INDEX IF NOT EXISTS idx_tasks_jira_ticket_id ON tasks(jira_ticket_id);
+CREATE INDEX IF NOT EXISTS idx_task_checklist_items_task_id ON task_checklist_items(task_id, position);
+CREATE INDEX IF NOT EXISTS idx_annotations_entity ON annotations(entity_type, entity_id);
+CREATE INDEX IF NOT EXISTS idx_annotations_created_at ON annotations(created_at);
+CREATE INDEX IF NOT EXISTS idx_jobs_acknowledged_at ON jobs(acknowledged_at);
+CREATE INDEX IF NOT EXISTS idx_pending_discovery_changes_repository_id ON pending_discovery_changes(repository_id);
+CREATE INDEX IF NOT EXISTS idx_watch_rule_evaluations_rule_id ON watch_rule_evaluations(rule_id, evaluated_at);
+CREATE INDEX IF NOT EXISTS idx_commit_ticket_refs_ticket_key ON commit_ticket_refs(ticket_key);
 CREATE INDEX IF NOT EXISTS idx_config_key ON config(key);
+CREATE INDEX IF NOT EXISTS idx_service_dependencies_pkg_name ON service_dependencies_pkg(ecosystem, name);
 
 -- Triggers to update updated_at timestamps
 CREATE TRIGGER IF NOT EXISTS update_repositories_updated_at
@@ -173,4 +579,24 @@ CREATE TRIGGER IF NOT EXISTS update_config_updated_at
     AFTER UPDATE ON config
 BEGIN
     UPDATE config SET updated_at = CURRENT_TIMESTAMP WHERE key = NEW.key;
-END;
\ No newline at end of file
+END;
+
+CREATE TRIGGER IF NOT EXISTS delete_microservice_custom_field_values
+    AFTER DELETE ON microservices
+BEGIN
+    DELETE FROM custom_field_values WHERE entity_id = OLD.id
+        AND field_id IN (SELECT id FROM custom_field_definitions WHERE entity_type = 'service');
+END;
+
+-- Annotations reference rows by ID rather than by foreign key, so remove them with their rows
+CREATE TRIGGER IF NOT EXISTS delete_deployment_history_annotations
+    AFTER DELETE ON deployment_history
+BEGIN
+    DELETE FROM annotations WHERE entity_type = 'deployment_history' AND entity_id = CAST(OLD.id AS TEXT);
+END;
+
+CREATE TRIGGER IF NOT EXISTS delete_action_annotations
+    AFTER DELETE ON actions
+BEGIN
+    DELETE FROM annotations WHERE entity_type = 'action' AND entity_id = CAST(OLD.id AS TEXT);
+END;
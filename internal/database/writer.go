@@ -0,0 +1,84 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"sync"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// Writer is a second pool on the database, of a single connection, for writes that must commit as
+// a whole, such as what a step of a repository sync found. While a transaction begun with Begin is
+// open, every statement run on Conn is part of it, so models built on Conn take part without
+// changes; a model beginning its own transaction gets a savepoint inside it. Conn is only meant to
+// be used inside the writer's transactions, by whoever holds one. A transaction takes SQLite's
+// write lock when it begins and keeps it until it ends, so other connections' writes wait for it:
+// it should only write what was gathered before it began, never wait on the network.
+type Writer struct {
+	conn *sql.DB
+	mu   sync.Mutex
+}
+
+// WriterTx is a transaction open on a Writer's connection
+type WriterTx struct {
+	writer *Writer
+}
+
+// OpenWriter opens a Writer on the database, closed along with it
+func (db *DB) OpenWriter() (*Writer, error) {
+	conn := sql.OpenDB(&instrumentedConnector{
+		dsn:      db.dsn,
+		driver:   &sqlite3.SQLiteDriver{},
+		recorder: db.recorder,
+	})
+	// The transaction lives on the connection, so it has to be the only one and never be recycled
+	conn.SetMaxOpenConns(1)
+	conn.SetMaxIdleConns(1)
+	if _, err := conn.Exec("PRAGMA foreign_keys = ON"); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to enable foreign keys: %w", err)
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.writers = append(db.writers, conn)
+	return &Writer{conn: conn}, nil
+}
+
+// Conn returns the writer's connection pool, to build the models whose writes its transactions hold
+func (w *Writer) Conn() *sql.DB {
+	return w.conn
+}
+
+// Begin waits for the transaction open on the writer, if any, to end and begins another, taking
+// the write lock right away
+func (w *Writer) Begin() (*WriterTx, error) {
+	w.mu.Lock()
+	if _, err := w.conn.Exec("BEGIN IMMEDIATE"); err != nil {
+		w.mu.Unlock()
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	return &WriterTx{writer: w}, nil
+}
+
+// Commit commits the transaction. When that fails the transaction is rolled back, so either way it
+// has ended.
+func (tx *WriterTx) Commit() error {
+	defer tx.writer.mu.Unlock()
+	if _, err := tx.writer.conn.Exec("COMMIT"); err != nil {
+		// A commit that fails, e.g. on a busy database, leaves the transaction open
+		tx.writer.conn.Exec("ROLLBACK")
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// Rollback discards everything written in the transaction
+func (tx *WriterTx) Rollback() error {
+	defer tx.writer.mu.Unlock()
+	if _, err := tx.writer.conn.Exec("ROLLBACK"); err != nil {
+		return fmt.Errorf("failed to roll back transaction: %w", err)
+	}
+	return nil
+}
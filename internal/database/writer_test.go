@@ -0,0 +1,138 @@
+package database
+
+import (
+	"testing"
+)
+
+// openWriter opens the database file at path with a writer on it
+func openWriter(t *testing.T, path string) (*DB, *Writer) {
+	t.Helper()
+	db, err := NewDB(path)
+	if err != nil {
+		t.Fatalf("NewDB: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	writer, err := db.OpenWriter()
+	if err != nil {
+		t.Fatalf("OpenWriter: %v", err)
+	}
+	return db, writer
+}
+
+func insertRepository(t *testing.T, writer *Writer, name string) {
+	t.Helper()
+	_, err := writer.Conn().Exec(`INSERT INTO repositories (name, url, type) VALUES (?, ?, 'monorepo')`, name, "https://github.com/acme/"+name)
+	if err != nil {
+		t.Fatalf("failed to insert repository %s: %v", name, err)
+	}
+}
+
+func TestWriterTransactionCommitsAsAWhole(t *testing.T) {
+	path := newFileDB(t)
+	_, writer := openWriter(t, path)
+
+	tx, err := writer.Begin()
+	if err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+	insertRepository(t, writer, "web")
+	insertRepository(t, writer, "worker")
+	if n := repositoryCount(t, path); n != 1 {
+		t.Errorf("another connection sees %d repositories before the commit, want 1", n)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	if n := repositoryCount(t, path); n != 3 {
+		t.Errorf("got %d repositories after the commit, want 3", n)
+	}
+}
+
+func TestWriterRollbackDiscardsWrites(t *testing.T) {
+	path := newFileDB(t)
+	_, writer := openWriter(t, path)
+
+	tx, err := writer.Begin()
+	if err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+	insertRepository(t, writer, "web")
+	if err := tx.Rollback(); err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+	if n := repositoryCount(t, path); n != 1 {
+		t.Errorf("got %d repositories after the rollback, want 1", n)
+	}
+
+	// The writer is free for the next transaction
+	tx, err = writer.Begin()
+	if err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+	insertRepository(t, writer, "worker")
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	if n := repositoryCount(t, path); n != 2 {
+		t.Errorf("got %d repositories, want 2", n)
+	}
+}
+
+func TestWriterTransactionIsLostWhenKilled(t *testing.T) {
+	path := newFileDB(t)
+	db, writer := openWriter(t, path)
+
+	if _, err := writer.Begin(); err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+	insertRepository(t, writer, "web")
+	// The app dies with the transaction open: its connections go without committing
+	db.Close()
+
+	if n := repositoryCount(t, path); n != 1 {
+		t.Errorf("got %d repositories after reopening, want only the committed one", n)
+	}
+}
+
+func TestModelTransactionInsideWriterTransactionIsASavepoint(t *testing.T) {
+	path := newFileDB(t)
+	_, writer := openWriter(t, path)
+
+	tx, err := writer.Begin()
+	if err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+	insertRepository(t, writer, "web")
+
+	// Models begin their own transactions on the connection they were built on
+	nested, err := writer.Conn().Begin()
+	if err != nil {
+		t.Fatalf("nested Begin: %v", err)
+	}
+	if _, err := nested.Exec(`INSERT INTO repositories (name, url, type) VALUES ('discarded', 'https://github.com/acme/discarded', 'monorepo')`); err != nil {
+		t.Fatalf("nested insert: %v", err)
+	}
+	if err := nested.Rollback(); err != nil {
+		t.Fatalf("nested Rollback: %v", err)
+	}
+	nested, err = writer.Conn().Begin()
+	if err != nil {
+		t.Fatalf("nested Begin: %v", err)
+	}
+	if _, err := nested.Exec(`INSERT INTO repositories (name, url, type) VALUES ('kept', 'https://github.com/acme/kept', 'monorepo')`); err != nil {
+		t.Fatalf("nested insert: %v", err)
+	}
+	if err := nested.Commit(); err != nil {
+		t.Fatalf("nested Commit: %v", err)
+	}
+	if n := repositoryCount(t, path); n != 1 {
+		t.Errorf("a committed savepoint is visible before the transaction commits: %d repositories", n)
+	}
+
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	if n := repositoryCount(t, path); n != 3 {
+		t.Errorf("got %d repositories, want the first, web and kept", n)
+	}
+}
@@ -0,0 +1,155 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+type OpType string
+
+const (
+	Equal  OpType = "context"
+	Insert OpType = "add"
+	Delete OpType = "remove"
+)
+
+// Line is a single line of a line-based diff
+type Line struct {
+	Type    OpType
+	OldLine int // 1-based line number in the old text, 0 for inserted lines
+	NewLine int // 1-based line number in the new text, 0 for deleted lines
+	Content string
+}
+
+// Lines computes a line diff between two texts using the longest common subsequence.
+// It is quadratic in the number of lines, which is fine for manifests and config files.
+func Lines(oldText, newText string) []Line {
+	a := splitLines(oldText)
+	b := splitLines(newText)
+
+	// lcs[i][j] is the LCS length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var lines []Line
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, Line{Type: Equal, OldLine: i + 1, NewLine: j + 1, Content: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, Line{Type: Delete, OldLine: i + 1, Content: a[i]})
+			i++
+		default:
+			lines = append(lines, Line{Type: Insert, NewLine: j + 1, Content: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, Line{Type: Delete, OldLine: i + 1, Content: a[i]})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, Line{Type: Insert, NewLine: j + 1, Content: b[j]})
+	}
+
+	return lines
+}
+
+// Unified renders a line diff in unified format with the given number of context lines
+func Unified(oldName, newName string, lines []Line, context int) string {
+	hasChanges := false
+	for _, line := range lines {
+		if line.Type != Equal {
+			hasChanges = true
+			break
+		}
+	}
+	if !hasChanges {
+		return ""
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", oldName, newName)
+
+	for start := 0; start < len(lines); {
+		// Find the next change
+		first := start
+		for first < len(lines) && lines[first].Type == Equal {
+			first++
+		}
+		if first == len(lines) {
+			break
+		}
+
+		// Extend the hunk while changes are within 2*context lines of each other
+		hunkStart := max(first-context, start)
+		hunkEnd := first
+		for k := first; k < len(lines); k++ {
+			if lines[k].Type != Equal {
+				hunkEnd = k
+			} else if k-hunkEnd > 2*context {
+				break
+			}
+		}
+		hunkEnd = min(hunkEnd+context, len(lines)-1)
+
+		writeHunk(&sb, lines[hunkStart:hunkEnd+1])
+		start = hunkEnd + 1
+	}
+
+	return sb.String()
+}
+
+func writeHunk(sb *strings.Builder, hunk []Line) {
+	oldStart, newStart, oldCount, newCount := 0, 0, 0, 0
+	for _, line := range hunk {
+		if line.Type != Insert {
+			if oldStart == 0 {
+				oldStart = line.OldLine
+			}
+			oldCount++
+		}
+		if line.Type != Delete {
+			if newStart == 0 {
+				newStart = line.NewLine
+			}
+			newCount++
+		}
+	}
+
+	fmt.Fprintf(sb, "@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount)
+	for _, line := range hunk {
+		switch line.Type {
+		case Insert:
+			sb.WriteString("+")
+		case Delete:
+			sb.WriteString("-")
+		default:
+			sb.WriteString(" ")
+		}
+		sb.WriteString(line.Content)
+		sb.WriteString("\n")
+	}
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
@@ -0,0 +1,159 @@
+// Package freeze decides whether a deployment falls inside a change freeze window: one-off windows
+// between two times, and windows that recur every day or week in local time.
+package freeze
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"dev-dashboard/pkg/types"
+)
+
+const day = 24 * time.Hour
+
+// Recurrence is a window that repeats every day or week. Start and End are offsets from midnight,
+// or from Sunday midnight for weekly windows; a window ending before it starts wraps around, as
+// "fri 16:00-mon 09:00" does.
+type Recurrence struct {
+	Weekly     bool
+	Start, End time.Duration
+}
+
+// ParseRecurrence parses a recurrence spec: "daily HH:MM-HH:MM", or "DAY HH:MM-DAY HH:MM" for a
+// weekly window such as "fri 16:00-mon 09:00". Days are English weekday names or their first
+// three letters. Times are local.
+func ParseRecurrence(spec string) (*Recurrence, error) {
+	spec = strings.ToLower(strings.TrimSpace(spec))
+	startValue, endValue, ok := strings.Cut(spec, "-")
+	if !ok {
+		return nil, fmt.Errorf("invalid recurrence %q, expected daily HH:MM-HH:MM or DAY HH:MM-DAY HH:MM", spec)
+	}
+
+	if clock, daily := strings.CutPrefix(strings.TrimSpace(startValue), "daily "); daily {
+		start, err := parseClock(clock)
+		if err != nil {
+			return nil, fmt.Errorf("invalid recurrence %q: %w", spec, err)
+		}
+		end, err := parseClock(endValue)
+		if err != nil {
+			return nil, fmt.Errorf("invalid recurrence %q: %w", spec, err)
+		}
+		if start == end {
+			return nil, fmt.Errorf("invalid recurrence %q: start and end are the same", spec)
+		}
+		return &Recurrence{Start: start, End: end}, nil
+	}
+
+	start, err := parseWeekTime(startValue)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recurrence %q: %w", spec, err)
+	}
+	end, err := parseWeekTime(endValue)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recurrence %q: %w", spec, err)
+	}
+	if start == end {
+		return nil, fmt.Errorf("invalid recurrence %q: start and end are the same", spec)
+	}
+	return &Recurrence{Weekly: true, Start: start, End: end}, nil
+}
+
+// parseWeekTime parses "DAY HH:MM" into the offset from Sunday midnight
+func parseWeekTime(value string) (time.Duration, error) {
+	fields := strings.Fields(value)
+	if len(fields) != 2 {
+		return 0, fmt.Errorf("%q is not a DAY HH:MM time", strings.TrimSpace(value))
+	}
+	clock, err := parseClock(fields[1])
+	if err != nil {
+		return 0, err
+	}
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		name := strings.ToLower(weekday.String())
+		if fields[0] == name || fields[0] == name[:3] {
+			return time.Duration(weekday)*day + clock, nil
+		}
+	}
+	return 0, fmt.Errorf("%q is not a weekday", fields[0])
+}
+
+func parseClock(value string) (time.Duration, error) {
+	clock, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a HH:MM time", strings.TrimSpace(value))
+	}
+	return time.Duration(clock.Hour())*time.Hour + time.Duration(clock.Minute())*time.Minute, nil
+}
+
+// Covers reports whether a time falls inside the recurring window, in the time's location
+func (r *Recurrence) Covers(at time.Time) bool {
+	offset := time.Duration(at.Hour())*time.Hour + time.Duration(at.Minute())*time.Minute + time.Duration(at.Second())*time.Second
+	if r.Weekly {
+		offset += time.Duration(at.Weekday()) * day
+	}
+	if r.Start < r.End {
+		return offset >= r.Start && offset < r.End
+	}
+	return offset >= r.Start || offset < r.End
+}
+
+// Validate checks a window and normalizes it: the environment pattern and reason are trimmed and
+// the pattern is lower-cased. One-off windows need both a start and an end; recurring windows may
+// be limited to the period between them.
+func Validate(window *types.FreezeWindow) error {
+	window.EnvironmentPattern = strings.ToLower(strings.TrimSpace(window.EnvironmentPattern))
+	if window.EnvironmentPattern == "" {
+		return fmt.Errorf("an environment pattern is required, e.g. prd or prd-*")
+	}
+	if _, err := path.Match(window.EnvironmentPattern, ""); err != nil {
+		return fmt.Errorf("invalid environment pattern %q", window.EnvironmentPattern)
+	}
+	window.Reason = strings.TrimSpace(window.Reason)
+	window.Recurrence = strings.TrimSpace(window.Recurrence)
+
+	if window.Recurrence != "" {
+		if _, err := ParseRecurrence(window.Recurrence); err != nil {
+			return err
+		}
+	} else if window.StartsAt == nil || window.EndsAt == nil {
+		return fmt.Errorf("a freeze window needs a start and an end, or a recurrence")
+	}
+	if window.StartsAt != nil && window.EndsAt != nil && !window.EndsAt.After(*window.StartsAt) {
+		return fmt.Errorf("a freeze window must end after it starts")
+	}
+	return nil
+}
+
+// Covers reports whether a window freezes an environment at a time. Environments match the
+// window's pattern (path.Match syntax) ignoring case; recurrences are evaluated in local time.
+func Covers(window *types.FreezeWindow, environment string, at time.Time) bool {
+	if matched, err := path.Match(window.EnvironmentPattern, strings.ToLower(environment)); err != nil || !matched {
+		return false
+	}
+	if window.StartsAt != nil && at.Before(*window.StartsAt) {
+		return false
+	}
+	if window.EndsAt != nil && !at.Before(*window.EndsAt) {
+		return false
+	}
+	if window.Recurrence == "" {
+		return true
+	}
+	recurrence, err := ParseRecurrence(window.Recurrence)
+	if err != nil {
+		return false
+	}
+	return recurrence.Covers(at.Local())
+}
+
+// Active returns the first of windows that freezes an environment at a time, or nil when none does
+func Active(windows []*types.FreezeWindow, environment string, at time.Time) *types.FreezeWindow {
+	for _, window := range windows {
+		if Covers(window, environment, at) {
+			return window
+		}
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/google/go-github/v57/github"
+)
+
+// ErrArtifactExpired is returned when an artifact's retention period passed and GitHub deleted its
+// archive; it's still listed, but can't be downloaded
+var ErrArtifactExpired = errors.New("artifact expired")
+
+// ErrArtifactTooLarge is returned when an artifact's archive is larger than the download limit
+var ErrArtifactTooLarge = errors.New("artifact too large")
+
+// Artifact is a file archive a workflow run uploaded
+type Artifact struct {
+	ID            int64
+	Name          string
+	SizeBytes     int64
+	Expired       bool
+	CreatedAt     *time.Time
+	ExpiresAt     *time.Time
+	WorkflowRunID int64
+}
+
+func newArtifact(artifact *github.Artifact) Artifact {
+	result := Artifact{
+		ID:            artifact.GetID(),
+		Name:          artifact.GetName(),
+		SizeBytes:     artifact.GetSizeInBytes(),
+		Expired:       artifact.GetExpired(),
+		WorkflowRunID: artifact.GetWorkflowRun().GetID(),
+	}
+	if artifact.CreatedAt != nil {
+		result.CreatedAt = &artifact.CreatedAt.Time
+	}
+	if artifact.ExpiresAt != nil {
+		result.ExpiresAt = &artifact.ExpiresAt.Time
+	}
+	return result
+}
+
+// ListWorkflowRunArtifacts returns the artifacts a workflow run uploaded, expired ones included
+func (c *Client) ListWorkflowRunArtifacts(ctx context.Context, owner, repo string, runID int64) ([]Artifact, error) {
+	var artifacts []Artifact
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		list, resp, err := c.gh.Actions.ListWorkflowRunArtifacts(ctx, owner, repo, runID, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list artifacts of workflow run %d: %w", runID, err)
+		}
+		for _, artifact := range list.Artifacts {
+			artifacts = append(artifacts, newArtifact(artifact))
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return artifacts, nil
+}
+
+// GetArtifact returns a single artifact
+func (c *Client) GetArtifact(ctx context.Context, owner, repo string, artifactID int64) (*Artifact, error) {
+	artifact, _, err := c.gh.Actions.GetArtifact(ctx, owner, repo, artifactID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get artifact %d: %w", artifactID, err)
+	}
+	result := newArtifact(artifact)
+	return &result, nil
+}
+
+// DownloadArtifact streams the zip archive of an artifact to w, failing with ErrArtifactTooLarge
+// once more than maxBytes were read, so memory stays bounded whatever the archive's size. It
+// returns the number of bytes written. Expired artifacts fail with ErrArtifactExpired.
+func (c *Client) DownloadArtifact(ctx context.Context, owner, repo string, artifactID int64, w io.Writer, maxBytes int64) (int64, error) {
+	location, resp, err := c.gh.Actions.DownloadArtifact(ctx, owner, repo, artifactID, 0)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusGone {
+			return 0, fmt.Errorf("%w: artifact %d can no longer be downloaded", ErrArtifactExpired, artifactID)
+		}
+		return 0, fmt.Errorf("failed to get download URL of artifact %d: %w", artifactID, err)
+	}
+
+	// The archive is served from a short-lived signed URL that needs no token
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, location.String(), nil)
+	if err != nil {
+		return 0, err
+	}
+	download, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to download artifact %d: %w", artifactID, err)
+	}
+	defer download.Body.Close()
+	if download.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("failed to download artifact %d: %s", artifactID, download.Status)
+	}
+	if download.ContentLength > maxBytes {
+		return 0, fmt.Errorf("%w: artifact %d is %d bytes, the limit is %d", ErrArtifactTooLarge, artifactID, download.ContentLength, maxBytes)
+	}
+
+	written, err := io.Copy(w, io.LimitReader(download.Body, maxBytes+1))
+	if err != nil {
+		return written, fmt.Errorf("failed to download artifact %d: %w", artifactID, err)
+	}
+	if written > maxBytes {
+		return written, fmt.Errorf("%w: artifact %d is larger than the limit of %d bytes", ErrArtifactTooLarge, artifactID, maxBytes)
+	}
+	return written, nil
+}
@@ -2,11 +2,17 @@ package github
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
+	"net/url"
 	"strings"
+	"sync/atomic"
 	"time"
 
+	"dev-dashboard/internal/kubernetes"
+
 	"github.com/google/go-github/v57/github"
 	"golang.org/x/oauth2"
 )
@@ -16,12 +22,23 @@ type Client struct {
 	token   string
 	baseURL string
 	isEnterprise bool
+	descriptionSources atomic.Pointer[[]DescriptionSource]
+	domainFolders atomic.Bool
+	fluxFields atomic.Pointer[FluxVersionFields]
+	envVarSnapshots atomic.Bool
+	imageNames atomic.Pointer[kubernetes.ImageNames]
+	commitKeys atomic.Pointer[[]string]
+	cache   *requestCache
 }
 
 type ServiceInfo struct {
 	Name        string
 	Path        string
+	// Domain is the folder between the discovery root and the service, empty directly under the root
+	Domain      string
 	Description string
+	// HasReadme is nil when the service directory couldn't be listed
+	HasReadme *bool
 }
 
 type ResourceInfo struct {
@@ -34,6 +51,7 @@ type ResourceInfo struct {
 type WorkflowRun struct {
 	ID          int64
 	Status      string
+	Conclusion  string
 	Commit      string
 	Branch      string
 	StartedAt   time.Time
@@ -44,16 +62,48 @@ func NewClient(token string) *Client {
 	return NewClientWithBaseURL(token, "")
 }
 
-func NewClientWithBaseURL(token, baseURL string) *Client {
+// NewClientWithBaseURL creates a client for github.com, or for the GitHub Enterprise Server at
+// baseURL when it is set. WithBaseURL overrides the API URL for either.
+func NewClientWithBaseURL(token, baseURL string, opts ...Option) *Client {
+	var options clientOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	ctx := context.Background()
+	if options.httpClient != nil {
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, options.httpClient)
+	}
 	ts := oauth2.StaticTokenSource(
 		&oauth2.Token{AccessToken: token},
 	)
-	tc := oauth2.NewClient(context.Background(), ts)
+	tc := oauth2.NewClient(ctx, ts)
+	if options.rateLimiter != nil {
+		tc.Transport = &rateLimitedTransport{base: tc.Transport, limiter: options.rateLimiter}
+	}
+	if options.observeTokenExpiration != nil {
+		tc.Transport = &tokenExpirationTransport{base: tc.Transport, token: token, observe: options.observeTokenExpiration}
+	}
+
+	var apiURL *url.URL
+	if options.apiBaseURL != "" {
+		var err error
+		if apiURL, err = ParseAPIBaseURL(options.apiBaseURL); err != nil {
+			log.Printf("Ignoring GitHub API base URL override: %v", err)
+		}
+	}
 
 	var client *github.Client
 	isEnterprise := false
 	
-	if baseURL != "" && baseURL != "https://api.github.com/" {
+	if apiURL != nil {
+		// Custom API base URL, e.g. a test server or proxy
+		client = github.NewClient(tc)
+		client.BaseURL = apiURL
+		client.UploadURL = apiURL
+		baseURL = apiURL.String()
+		log.Printf("Created GitHub client for API base URL: %s", baseURL)
+	} else if baseURL != "" && baseURL != "https://api.github.com/" {
 		// GitHub Enterprise Server
 		var err error
 		client, err = github.NewEnterpriseClient(baseURL, baseURL, tc)
@@ -75,12 +125,57 @@ func NewClientWithBaseURL(token, baseURL string) *Client {
 		token:       token,
 		baseURL:     baseURL,
 		isEnterprise: isEnterprise,
+		cache:       newRequestCache(),
+	}
+}
+
+// ErrRepositoryNotFound is returned when GitHub answers 404 for a repository: it was deleted, or the
+// token lost access to it. Renamed and transferred repositories are redirected to instead.
+var ErrRepositoryNotFound = errors.New("repository not found on GitHub")
+
+// ErrUnauthorized is returned when GitHub answers 401: the token was revoked, expired or is invalid
+var ErrUnauthorized = errors.New("GitHub rejected the token")
+
+// ErrForbidden is returned when GitHub answers 403 for a reason other than rate limiting, e.g. the
+// token lacks the repo scope or an organization's SSO authorization
+var ErrForbidden = errors.New("GitHub denied access to the repository")
+
+// ErrRateLimited is returned when GitHub answers 403 or 429 because the primary or secondary rate
+// limit was hit, which says nothing about access to the repository
+var ErrRateLimited = errors.New("GitHub rate limit exceeded")
+
+// StatusCode returns the HTTP status of the response a GitHub error came from, or 0
+func StatusCode(err error) int {
+	switch {
+	case errors.Is(err, ErrRepositoryNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrUnauthorized):
+		return http.StatusUnauthorized
+	case errors.Is(err, ErrForbidden):
+		return http.StatusForbidden
+	}
+	var errResp *github.ErrorResponse
+	if errors.As(err, &errResp) && errResp.Response != nil {
+		return errResp.Response.StatusCode
 	}
+	return 0
 }
 
+// GetRepository returns a repository. Requests for a renamed or transferred repository are
+// redirected by GitHub, so the returned full name can differ from owner/repo.
 func (c *Client) GetRepository(ctx context.Context, owner, repo string) (*github.Repository, error) {
 	repository, _, err := c.gh.Repositories.Get(ctx, owner, repo)
 	if err != nil {
+		switch {
+		case isRateLimited(err):
+			return nil, fmt.Errorf("%w: %v", ErrRateLimited, err)
+		case isNotFound(err):
+			return nil, fmt.Errorf("%w: %s/%s", ErrRepositoryNotFound, owner, repo)
+		case hasStatus(err, http.StatusUnauthorized):
+			return nil, fmt.Errorf("%w: %v", ErrUnauthorized, err)
+		case hasStatus(err, http.StatusForbidden):
+			return nil, fmt.Errorf("%w: %s/%s: %v", ErrForbidden, owner, repo, err)
+		}
 		return nil, fmt.Errorf("failed to get repository: %w", err)
 	}
 	return repository, nil
@@ -103,7 +198,7 @@ func (c *Client) DiscoverMicroservicesInPath(ctx context.Context, owner, repo, s
 	fmt.Printf("[GitHub Client] Discovering services in %s/%s at path: %s\n", owner, repo, servicePath)
 
 	// Get contents of the specified directory
-	_, contents, _, err := c.gh.Repositories.GetContents(ctx, owner, repo, servicePath, nil)
+	_, contents, err := c.getContents(ctx, owner, repo, servicePath, nil)
 	if err != nil {
 		if githubErr, ok := err.(*github.ErrorResponse); ok {
 			fmt.Printf("[GitHub Client] Directory %s does not exist (HTTP %d): %s\n", servicePath, githubErr.Response.StatusCode, githubErr.Message)
@@ -116,25 +211,14 @@ func (c *Client) DiscoverMicroservicesInPath(ctx context.Context, owner, repo, s
 
 	fmt.Printf("[GitHub Client] Found %d items in directory %s\n", len(contents), servicePath)
 
+	if c.domainFolders.Load() {
+		return c.discoverDomainServices(ctx, owner, repo, servicePath, contents)
+	}
+
 	for _, content := range contents {
 		fmt.Printf("[GitHub Client] Processing item: %s (type: %s)\n", content.GetName(), content.GetType())
 		if content.GetType() == "dir" {
-			serviceName := content.GetName()
-			fullServicePath := fmt.Sprintf("%s/%s", servicePath, serviceName)
-
-			fmt.Printf("[GitHub Client] Found service directory: %s at path %s\n", serviceName, fullServicePath)
-
-			// Try to get a description from README or package.json
-			description := c.getServiceDescription(ctx, owner, repo, fullServicePath)
-
-			service := ServiceInfo{
-				Name:        serviceName,
-				Path:        fullServicePath,
-				Description: description,
-			}
-
-			services = append(services, service)
-			fmt.Printf("[GitHub Client] Added service: %s with description: %s\n", serviceName, description)
+			services = append(services, c.serviceInfo(ctx, owner, repo, servicePath, content.GetName()))
 		}
 	}
 
@@ -142,6 +226,24 @@ func (c *Client) DiscoverMicroservicesInPath(ctx context.Context, owner, repo, s
 	return services, nil
 }
 
+// serviceInfo describes the service in directory serviceName under parentPath
+func (c *Client) serviceInfo(ctx context.Context, owner, repo, parentPath, serviceName string) ServiceInfo {
+	fullServicePath := fmt.Sprintf("%s/%s", parentPath, serviceName)
+
+	fmt.Printf("[GitHub Client] Found service directory: %s at path %s\n", serviceName, fullServicePath)
+
+	// Try to get a description using the configured description sources
+	description, hasReadme := c.getServiceMetadata(ctx, owner, repo, fullServicePath)
+
+	fmt.Printf("[GitHub Client] Added service: %s with description: %s\n", serviceName, description)
+	return ServiceInfo{
+		Name:        serviceName,
+		Path:        fullServicePath,
+		Description: description,
+		HasReadme:   hasReadme,
+	}
+}
+
 func (c *Client) DiscoverKubernetesResources(ctx context.Context, owner, repo string) ([]ResourceInfo, error) {
 	return c.DiscoverKubernetesResourcesInPath(ctx, owner, repo, "")
 }
@@ -175,7 +277,7 @@ func (c *Client) DiscoverKubernetesResourcesInPath(ctx context.Context, owner, r
 func (c *Client) discoverResourcesInDir(ctx context.Context, owner, repo, path, namespace string) ([]ResourceInfo, error) {
 	var resources []ResourceInfo
 
-	_, contents, _, err := c.gh.Repositories.GetContents(ctx, owner, repo, path, nil)
+	_, contents, err := c.getContents(ctx, owner, repo, path, nil)
 	if err != nil {
 		return resources, err
 	}
@@ -200,7 +302,7 @@ func (c *Client) discoverResourcesInDir(ctx context.Context, owner, repo, path,
 
 func (c *Client) parseKubernetesFile(ctx context.Context, owner, repo, path string) *ResourceInfo {
 	// Get file contents
-	fileContent, _, _, err := c.gh.Repositories.GetContents(ctx, owner, repo, path, nil)
+	fileContent, _, err := c.getContents(ctx, owner, repo, path, nil)
 	if err != nil || fileContent == nil {
 		return nil
 	}
@@ -235,46 +337,15 @@ func (c *Client) parseKubernetesFile(ctx context.Context, owner, repo, path stri
 	return nil
 }
 
-func (c *Client) getServiceDescription(ctx context.Context, owner, repo, servicePath string) string {
-	// Try to get README.md
-	readme, _, _, err := c.gh.Repositories.GetContents(ctx, owner, repo, fmt.Sprintf("%s/README.md", servicePath), nil)
-	if err == nil && readme != nil {
-		content, err := readme.GetContent()
-		if err == nil {
-			lines := strings.Split(content, "\n")
-			for _, line := range lines {
-				line = strings.TrimSpace(line)
-				if line != "" && !strings.HasPrefix(line, "#") {
-					return line
-				}
-			}
-		}
-	}
-
-	// Try to get package.json for description
-	packageJSON, _, _, err := c.gh.Repositories.GetContents(ctx, owner, repo, fmt.Sprintf("%s/package.json", servicePath), nil)
-	if err == nil && packageJSON != nil {
-		content, err := packageJSON.GetContent()
-		if err == nil && strings.Contains(content, "\"description\"") {
-			// Simple JSON parsing for description field
-			lines := strings.Split(content, "\n")
-			for _, line := range lines {
-				if strings.Contains(line, "\"description\"") {
-					parts := strings.Split(line, ":")
-					if len(parts) > 1 {
-						desc := strings.Trim(strings.TrimSpace(parts[1]), "\",")
-						return strings.Trim(desc, "\"")
-					}
-				}
-			}
-		}
-	}
-
-	return ""
+func (c *Client) GetWorkflowRuns(ctx context.Context, owner, repo string, workflowID int64, limit int) ([]WorkflowRun, error) {
+	return c.GetBranchWorkflowRuns(ctx, owner, repo, workflowID, "", limit)
 }
 
-func (c *Client) GetWorkflowRuns(ctx context.Context, owner, repo string, workflowID int64, limit int) ([]WorkflowRun, error) {
+// GetBranchWorkflowRuns returns the latest runs of a workflow on a branch, or on every branch when
+// branch is empty
+func (c *Client) GetBranchWorkflowRuns(ctx context.Context, owner, repo string, workflowID int64, branch string, limit int) ([]WorkflowRun, error) {
 	opts := &github.ListWorkflowRunsOptions{
+		Branch:      branch,
 		ListOptions: github.ListOptions{PerPage: limit},
 	}
 
@@ -286,11 +357,12 @@ func (c *Client) GetWorkflowRuns(ctx context.Context, owner, repo string, workfl
 	var workflowRuns []WorkflowRun
 	for _, run := range runs.WorkflowRuns {
 		workflowRun := WorkflowRun{
-			ID:        run.GetID(),
-			Status:    run.GetStatus(),
-			Commit:    run.GetHeadSHA(),
-			Branch:    run.GetHeadBranch(),
-			StartedAt: run.GetCreatedAt().Time,
+			ID:         run.GetID(),
+			Status:     run.GetStatus(),
+			Conclusion: run.GetConclusion(),
+			Commit:     run.GetHeadSHA(),
+			Branch:     run.GetHeadBranch(),
+			StartedAt:  run.GetCreatedAt().Time,
 		}
 
 		if run.UpdatedAt != nil {
@@ -319,8 +391,17 @@ type KustomizationDeployment struct {
 	Region       string
 	Namespace    string
 	Tag          string
+	ImageRepository string
+	Registry     string
 	Path         string
 	CommitSHA    string
+	// AnnotatedCommit is the source commit the kustomization names in one of the commit annotation
+	// keys; empty when it names none
+	AnnotatedCommit string
+	// Env is the environment of the service's Deployment in the overlay, read when env var
+	// snapshots are on; EnvFingerprint is empty when it wasn't read
+	Env          []EnvVar
+	EnvFingerprint string
 }
 
 // ScanKustomizationFiles scans the Kubernetes repository for kustomization.yaml files
@@ -328,14 +409,88 @@ func (c *Client) ScanKustomizationFiles(ctx context.Context, owner, repo string)
 	return c.ScanKustomizationFilesInPath(ctx, owner, repo, "")
 }
 
+// Reasons a kustomization file produced no deployments
+const (
+	SkipBadPathStructure      = "bad_path_structure"
+	SkipUnreadable            = "unreadable"
+	SkipNoImagesSection       = "no_images_section"
+	SkipNoServiceImage        = "no_service_image"
+	SkipUnresolvedPlaceholder = "unresolved_placeholder"
+)
+
+// KustomizationFileResult is the outcome of scanning a single kustomization file.
+// SkipReason is empty when the file yields a deployment for each of its namespaces.
+type KustomizationFileResult struct {
+	Path        string
+	ServiceName string
+	Environment string
+	Region      string
+	Namespaces  []string
+	Tag         string
+	ImageRepository string // image the tag applies to; empty when the kustomization isn't valid YAML
+	Registry    string
+	CommitSHA   string
+	// AnnotatedCommit is the source commit the kustomization names under CommitAnnotation, one of
+	// the commit annotation keys
+	AnnotatedCommit  string
+	CommitAnnotation string
+	// Source names the Flux field the tag was read from (e.g. "HelmRelease spec.chart.spec.version");
+	// empty for the kustomization's images list
+	Source      string
+	Env         []EnvVar
+	EnvFingerprint string
+	SkipReason  string
+	Detail      string
+	// Images lists the image repositories of a kustomization none of whose images matched the service
+	Images      []string
+}
+
 // ScanKustomizationFilesInPath scans for kustomization files in a specific root path
 func (c *Client) ScanKustomizationFilesInPath(ctx context.Context, owner, repo, rootPath string) ([]KustomizationDeployment, error) {
+	results, err := c.ScanKustomizationFilesVerbose(ctx, owner, repo, rootPath)
+	if err != nil {
+		return nil, err
+	}
+	return KustomizationDeployments(results), nil
+}
+
+// KustomizationDeployments returns the deployments of the files a scan didn't skip, one for each
+// namespace a file deploys to
+func KustomizationDeployments(results []KustomizationFileResult) []KustomizationDeployment {
 	var deployments []KustomizationDeployment
+	for _, result := range results {
+		if result.SkipReason != "" {
+			continue
+		}
+		for _, namespace := range result.Namespaces {
+			deployments = append(deployments, KustomizationDeployment{
+				ServiceName: result.ServiceName,
+				Environment: result.Environment,
+				Region:      result.Region,
+				Namespace:   namespace,
+				Tag:         result.Tag,
+				ImageRepository: result.ImageRepository,
+				Registry:    result.Registry,
+				Path:        result.Path,
+				CommitSHA:   result.CommitSHA,
+				AnnotatedCommit: result.AnnotatedCommit,
+				Env:         result.Env,
+				EnvFingerprint: result.EnvFingerprint,
+			})
+		}
+	}
+
+	return deployments
+}
+
+// ScanKustomizationFilesVerbose scans for kustomization files like ScanKustomizationFilesInPath,
+// but reports the outcome of every file found, including why files were skipped
+func (c *Client) ScanKustomizationFilesVerbose(ctx context.Context, owner, repo, rootPath string) ([]KustomizationFileResult, error) {
+	var results []KustomizationFileResult
 
 	// Determine the search path
-	searchPath := "services" // Default path
+	searchPath := KustomizationScanPath(rootPath)
 	if rootPath != "" && rootPath != "." {
-		searchPath = strings.Trim(rootPath, "/")
 		log.Printf("Using custom root path for kustomization scan: %s", searchPath)
 	} else {
 		log.Printf("Using default path for kustomization scan: %s", searchPath)
@@ -352,101 +507,261 @@ func (c *Client) ScanKustomizationFilesInPath(ctx context.Context, owner, repo,
 
 	for _, path := range kustomizationPaths {
 		log.Printf("Processing kustomization file: %s", path)
-		
-		// Parse service name, environment, region, and namespace from path
-		// Expected patterns with flexible overlay directory names:
-		// - services/service-b/overlays/prd/us-west-2/ns-a/kustomization.yaml (standard)
-		// - services/service-b/overlays-argo/prd/us-west-2/ns-a/kustomization.yaml (argo-specific)
-		// - k8s/service-b/overlay/prd/us-west-2/ns-a/kustomization.yaml (singular form)
-		// - rootpath/service-b/envs/prd/us-west-2/ns-a/kustomization.yaml (environments)
-		// - rootpath/service-b/overlays-custom/prd/us-west-2/ns-a/kustomization.yaml (custom prefix)
-		pathParts := strings.Split(path, "/")
-		
-		// Find the overlays directory to determine the structure
-		// Support multiple overlay directory naming conventions
-		overlaysIndex := -1
-		overlaysName := ""
-		overlaysPatterns := []string{"overlays", "overlays-argo", "overlay", "envs", "environments"}
-		
-		for i, part := range pathParts {
-			for _, pattern := range overlaysPatterns {
-				if part == pattern || strings.HasPrefix(part, "overlays-") {
-					overlaysIndex = i
-					overlaysName = part
-					break
-				}
+		results = append(results, c.scanKustomizationFile(ctx, owner, repo, path))
+	}
+
+	return results, nil
+}
+
+// SetImageNames sets the image names the kustomization scan matches a service's image by before
+// falling back to images named after the service directory
+func (c *Client) SetImageNames(imageNames kubernetes.ImageNames) {
+	c.imageNames.Store(&imageNames)
+}
+
+// imageName returns the image name set for the service a kustomization directory belongs to
+func (c *Client) imageName(serviceDir string) string {
+	if imageNames := c.imageNames.Load(); imageNames != nil {
+		return imageNames.Lookup(serviceDir)
+	}
+	return ""
+}
+
+func (c *Client) scanKustomizationFile(ctx context.Context, owner, repo, path string) KustomizationFileResult {
+	result := KustomizationFileResult{Path: path}
+
+	// Parse service name, environment, region, and namespace from path
+	// Expected patterns with flexible overlay directory names:
+	// - services/service-b/overlays/prd/us-west-2/ns-a/kustomization.yaml (standard)
+	// - services/service-b/overlays/prd/us-west-2/kustomization.yaml (no namespace level)
+	// - services/service-b/overlays-argo/prd/us-west-2/ns-a/kustomization.yaml (argo-specific)
+	// - k8s/service-b/overlay/prd/us-west-2/ns-a/kustomization.yaml (singular form)
+	// - rootpath/service-b/envs/prd/us-west-2/ns-a/kustomization.yaml (environments)
+	// - rootpath/service-b/overlays-custom/prd/us-west-2/ns-a/kustomization.yaml (custom prefix)
+	pathParts := strings.Split(path, "/")
+	
+	// Find the overlays directory to determine the structure
+	// Support multiple overlay directory naming conventions
+	overlaysIndex := -1
+	overlaysName := ""
+	for i, part := range pathParts {
+		if kubernetes.IsOverlaysDir(part) {
+			overlaysIndex = i
+			overlaysName = part
+			break
+		}
+	}
+	
+	// We need at least: [root]/service/{overlays-dir}/env/region/kustomization.yaml, with an
+	// optional namespace directory after the region
+	levels := len(pathParts) - overlaysIndex - 2 // directories between the overlay dir and the file
+	if overlaysIndex < 1 || levels < 2 {
+		log.Printf("Skipping kustomization file with unexpected path structure: %s (no valid overlay directory found)", path)
+		result.SkipReason = SkipBadPathStructure
+		result.Detail = "expected <service>/overlays/<env>/<region>[/<namespace>]/kustomization.yaml"
+		return result
+	}
+
+	result.ServiceName = pathParts[overlaysIndex-1] // Service is the directory before the overlay dir
+	result.Environment = pathParts[overlaysIndex+1] // Environment is after the overlay dir
+	result.Region = pathParts[overlaysIndex+2]      // Region is after environment
+	namespace := ""                                 // Namespace is after region, when there's a level for it
+	if levels > 2 {
+		namespace = pathParts[overlaysIndex+3]
+	}
+	result.Namespaces = []string{namespace}
+	
+	log.Printf("Parsed kustomization: service=%s, overlay-dir=%s, env=%s, region=%s, namespace=%s", result.ServiceName, overlaysName, result.Environment, result.Region, namespace)
+
+	// Get the content of the kustomization.yaml file
+	fileContent, _, err := c.getContents(ctx, owner, repo, path, nil)
+	if err != nil || fileContent == nil {
+		log.Printf("Failed to get kustomization file %s: %v", path, err)
+		result.SkipReason = SkipUnreadable
+		if err != nil {
+			result.Detail = err.Error()
+		}
+		return result
+	}
+
+	content, err := fileContent.GetContent()
+	if err != nil {
+		log.Printf("Failed to decode kustomization file %s: %v", path, err)
+		result.SkipReason = SkipUnreadable
+		result.Detail = err.Error()
+		return result
+	}
+
+	// Parse YAML to extract image tag; JSON kustomizations are decoded as a whole. The image name set
+	// for the service is matched first, then images named after the service directory.
+	var tag string
+	imageName := c.imageName(result.ServiceName)
+	hasImages := hasImagesSection(content)
+	config, parseErr := kubernetes.ParseKustomization(path, []byte(content))
+	if strings.HasSuffix(path, ".json") {
+		if parseErr != nil {
+			log.Printf("Failed to parse kustomization file %s: %v", path, parseErr)
+			result.SkipReason = SkipUnreadable
+			result.Detail = parseErr.Error()
+			return result
+		}
+		if image := config.ServiceImage(result.ServiceName, imageName); image != nil {
+			tag = image.NewTag
+		}
+		hasImages = len(config.Images) > 0
+	} else {
+		tag = c.extractImageTagFromKustomization(content, result.ServiceName, imageName)
+	}
+	// Flux overlays set a chart version or source revision instead of an image tag
+	fromFlux := false
+	if tag == "" {
+		tag, result.Source = c.fluxVersion(ctx, owner, repo, path, content, result.ServiceName)
+		fromFlux = tag != ""
+	}
+	if tag == "" {
+		log.Printf("No tag found for service %s in %s", result.ServiceName, path)
+		if hasImages {
+			result.SkipReason = SkipNoServiceImage
+			result.Detail = fmt.Sprintf("no image entry naming %s has a newTag", result.ServiceName)
+			if imageName != "" {
+				result.Detail = fmt.Sprintf("no image entry naming %s or image %s has a newTag", result.ServiceName, imageName)
 			}
-			if overlaysIndex != -1 {
-				break
+			if parseErr == nil {
+				result.Images = config.ImageRepositories()
 			}
+		} else {
+			result.SkipReason = SkipNoImagesSection
 		}
-		
-		// We need at least: [root]/service/{overlays-dir}/env/region/namespace/kustomization.yaml
-		// That's minimum 6 parts after finding the overlay directory
-		if overlaysIndex < 1 || len(pathParts) < overlaysIndex + 4 {
-			log.Printf("Skipping kustomization file with unexpected path structure: %s (no valid overlay directory found)", path)
-			continue
+		return result
+	}
+	result.Tag = tag
+
+	// The full image reference comes from the parsed images list. YAML only the line-based tag
+	// extraction copes with (e.g. with template directives) leaves it unknown.
+	if parseErr == nil && !fromFlux {
+		if image := config.ServiceImage(result.ServiceName, imageName); image != nil {
+			result.ImageRepository = image.Repository()
+			result.Registry = kubernetes.ImageRegistry(result.ImageRepository)
 		}
+	}
+	if parseErr == nil {
+		result.CommitAnnotation, result.AnnotatedCommit = AnnotatedCommit(config, c.commitAnnotations())
+	}
 
-		serviceName := pathParts[overlaysIndex-1]  // Service is the directory before the overlay dir
-		environment := pathParts[overlaysIndex+1]  // Environment is after the overlay dir
-		region := pathParts[overlaysIndex+2]       // Region is after environment
-		namespace := pathParts[overlaysIndex+3]    // Namespace is after region
-		
-		log.Printf("Parsed kustomization: service=%s, overlay-dir=%s, env=%s, region=%s, namespace=%s", serviceName, overlaysName, environment, region, namespace)
+	// Tags filled in by CI templating never name a real image
+	if isUnresolvedPlaceholder(tag) {
+		log.Printf("Skipping unresolved tag placeholder %s in %s", tag, path)
+		result.SkipReason = SkipUnresolvedPlaceholder
+		result.Detail = tag
+		return result
+	}
 
-		// Get the content of the kustomization.yaml file
-		fileContent, _, _, err := c.gh.Repositories.GetContents(ctx, owner, repo, path, nil)
-		if err != nil {
-			log.Printf("Failed to get kustomization file %s: %v", path, err)
-			continue
-		}
+	// Get the commit SHA for this file
+	commits, err := c.ListCommits(ctx, owner, repo, &github.CommitsListOptions{
+		Path: path,
+		ListOptions: github.ListOptions{PerPage: 1},
+	})
+	if err == nil && len(commits) > 0 && commits[0].SHA != nil {
+		result.CommitSHA = *commits[0].SHA
+	}
 
-		if fileContent == nil {
-			continue
+	// An overlay can deploy to several namespaces at once through patches or components.
+	// When it does, record a deployment for each namespace instead of the one in the path.
+	if targeted := c.kustomizationNamespaces(ctx, owner, repo, path, content); len(targeted) > 1 {
+		log.Printf("Kustomization %s targets %d namespaces: %s", path, len(targeted), strings.Join(targeted, ", "))
+		result.Namespaces = targeted
+	}
+
+	if c.envVarSnapshots.Load() {
+		result.Env, result.EnvFingerprint = c.overlayEnvVars(ctx, owner, repo, path, fileContent.GetSHA(), content, result.ServiceName)
+	}
+
+	return result
+}
+
+// hasImagesSection reports whether kustomization content has an images list
+func hasImagesSection(content string) bool {
+	for _, line := range strings.Split(content, "\n") {
+		if strings.TrimSpace(line) == "images:" {
+			return true
 		}
+	}
+	return false
+}
+
+// isUnresolvedPlaceholder reports whether a tag is a template variable rather than a real tag
+func isUnresolvedPlaceholder(tag string) bool {
+	return strings.Contains(tag, "${") || strings.Contains(tag, "{{") || strings.HasPrefix(tag, "$") ||
+		(strings.HasPrefix(tag, "<") && strings.HasSuffix(tag, ">"))
+}
+
+// KustomizationScanPath returns the directory scanned for kustomization files given a repository root path
+func KustomizationScanPath(rootPath string) string {
+	if rootPath != "" && rootPath != "." {
+		return strings.Trim(rootPath, "/")
+	}
+	return "services"
+}
 
-		content, err := fileContent.GetContent()
+// GetTreeSHA returns the git tree SHA of a directory on the default branch.
+// The SHA changes whenever anything beneath the directory changes, so it can be used to skip rescans.
+func (c *Client) GetTreeSHA(ctx context.Context, owner, repo, path string) (string, error) {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		repository, err := c.GetRepository(ctx, owner, repo)
 		if err != nil {
-			log.Printf("Failed to decode kustomization file %s: %v", path, err)
-			continue
+			return "", err
 		}
-
-		// Parse YAML to extract image tag
-		tag := c.extractImageTagFromKustomization(content, serviceName)
-		if tag == "" {
-			log.Printf("No tag found for service %s in %s", serviceName, path)
-			continue
+		branch, _, err := c.gh.Repositories.GetBranch(ctx, owner, repo, repository.GetDefaultBranch(), 1)
+		if err != nil {
+			return "", fmt.Errorf("failed to get default branch: %w", err)
 		}
+		return branch.GetCommit().GetCommit().GetTree().GetSHA(), nil
+	}
 
-		// Get the commit SHA for this file
-		commitSHA := ""
-		commits, _, err := c.gh.Repositories.ListCommits(ctx, owner, repo, &github.CommitsListOptions{
-			Path: path,
-			ListOptions: github.ListOptions{PerPage: 1},
-		})
-		if err == nil && len(commits) > 0 && commits[0].SHA != nil {
-			commitSHA = *commits[0].SHA
-		}
+	parent := ""
+	if idx := strings.LastIndex(path, "/"); idx != -1 {
+		parent = path[:idx]
+	}
 
-		deployment := KustomizationDeployment{
-			ServiceName: serviceName,
-			Environment: environment,
-			Region:      region,
-			Namespace:   namespace,
-			Tag:         tag,
-			Path:        path,
-			CommitSHA:   commitSHA,
-		}
+	_, contents, err := c.getContents(ctx, owner, repo, parent, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to list %s: %w", parent, err)
+	}
 
-		deployments = append(deployments, deployment)
+	for _, content := range contents {
+		if content.GetPath() == path && content.GetType() == "dir" {
+			return content.GetSHA(), nil
+		}
 	}
 
-	return deployments, nil
+	return "", fmt.Errorf("directory %s not found", path)
+}
+
+// extractImageTagFromKustomization parses kustomization.yaml content to find the newTag for a service:
+// of the image matching imageName when it's set and listed, otherwise of the first image whose name
+// or newName line mentions the service name
+func (c *Client) extractImageTagFromKustomization(content, serviceName, imageName string) string {
+	if imageName != "" {
+		tag := extractImageTag(content, func(line string) bool {
+			key, value, found := strings.Cut(strings.TrimSpace(strings.TrimPrefix(line, "-")), ":")
+			if !found || (key != "name" && key != "newName") {
+				return false
+			}
+			return kubernetes.ImageNameMatches(strings.Trim(strings.TrimSpace(value), "\"'"), imageName)
+		})
+		if tag != "" {
+			return tag
+		}
+	}
+	return extractImageTag(content, func(line string) bool {
+		return (strings.Contains(line, "name:") || strings.Contains(line, "newName:")) && strings.Contains(line, serviceName)
+	})
 }
 
-// extractImageTagFromKustomization parses kustomization.yaml content to find the newTag for a service
-func (c *Client) extractImageTagFromKustomization(content, serviceName string) string {
+// extractImageTag returns the newTag of the first entry of the images section with a name or newName
+// line that matches
+func extractImageTag(content string, matches func(line string) bool) string {
 	// Simple YAML parsing to find images section and extract newTag
 	lines := strings.Split(content, "\n")
 	inImagesSection := false
@@ -470,12 +785,8 @@ func (c *Client) extractImageTagFromKustomization(content, serviceName string) s
 				continue
 			}
 
-			// Look for service name in image name or newName
-			if strings.Contains(line, "name:") && strings.Contains(line, serviceName) {
-				inServiceImage = true
-				continue
-			}
-			if strings.Contains(line, "newName:") && strings.Contains(line, serviceName) {
+			// Look for the service's image in image name or newName
+			if matches(line) {
 				inServiceImage = true
 				continue
 			}
@@ -500,10 +811,10 @@ func (c *Client) extractImageTagFromKustomization(content, serviceName string) s
 	return ""
 }
 
-// findKustomizationFiles recursively searches for kustomization.yaml files using Contents API
+// findKustomizationFiles recursively searches for kustomization.yaml and kustomization.json files using Contents API
 func (c *Client) findKustomizationFiles(ctx context.Context, owner, repo, path string, foundFiles []string) ([]string, error) {
 	// Get contents of the directory
-	_, contents, _, err := c.gh.Repositories.GetContents(ctx, owner, repo, path, nil)
+	_, contents, err := c.getContents(ctx, owner, repo, path, nil)
 	if err != nil {
 		// Directory doesn't exist, skip silently
 		return foundFiles, nil
@@ -517,8 +828,8 @@ func (c *Client) findKustomizationFiles(ctx context.Context, owner, repo, path s
 			if err != nil {
 				continue // Skip directories we can't access
 			}
-		} else if content.GetType() == "file" && content.GetName() == "kustomization.yaml" {
-			// Found a kustomization.yaml file
+		} else if content.GetType() == "file" && kubernetes.IsKustomizationFile(content.GetName()) {
+			// Found a kustomization file
 			foundFiles = append(foundFiles, content.GetPath())
 		}
 	}
@@ -541,44 +852,6 @@ func (c *Client) GetBaseURL() string {
 	return c.baseURL
 }
 
-// ParseRepositoryURL extracts owner and repo name from various GitHub URL formats
-// Supports both GitHub.com and GitHub Enterprise URLs
-func (c *Client) ParseRepositoryURL(repoURL string) (owner, repo string, err error) {
-	if repoURL == "" {
-		return "", "", fmt.Errorf("repository URL is empty")
-	}
-
-	// Remove .git suffix
-	repoURL = strings.TrimSuffix(repoURL, ".git")
-	
-	// Handle HTTPS URLs
-	if strings.HasPrefix(repoURL, "https://") {
-		return c.parseHTTPSURL(repoURL)
-	}
-	
-	return "", "", fmt.Errorf("only HTTPS URLs are supported")
-}
-
-// parseHTTPSURL handles HTTPS GitHub URLs for both github.com and Enterprise
-func (c *Client) parseHTTPSURL(repoURL string) (owner, repo string, err error) {
-	// Remove https:// prefix
-	urlPath := strings.TrimPrefix(repoURL, "https://")
-	
-	// Split by /
-	parts := strings.Split(urlPath, "/")
-	if len(parts) < 3 {
-		return "", "", fmt.Errorf("invalid repository URL format")
-	}
-	
-	// For GitHub.com: github.com/owner/repo
-	// For Enterprise: enterprise.example.com/owner/repo
-	if len(parts) >= 3 {
-		return parts[len(parts)-2], parts[len(parts)-1], nil
-	}
-	
-	return "", "", fmt.Errorf("unable to parse repository URL")
-}
-
 // IsValidGitHubURL checks if the provided URL matches this client's configuration
 func (c *Client) IsValidGitHubURL(repoURL string) bool {
 	if repoURL == "" {
@@ -0,0 +1,59 @@
+package github
+
+import (
+	"log"
+	"strings"
+
+	"dev-dashboard/internal/kubernetes"
+)
+
+// DefaultCommitAnnotations are the kustomization annotation and label keys a deployment's source
+// commit is read from when none are configured
+var DefaultCommitAnnotations = []string{"git-commit", "app.kubernetes.io/version"}
+
+// SetCommitAnnotations changes the keys of a kustomization's commonAnnotations, commonLabels and
+// labels a deployment's source commit is read from, tried in order; none uses the default ones
+func (c *Client) SetCommitAnnotations(keys []string) {
+	if len(keys) == 0 {
+		keys = DefaultCommitAnnotations
+	}
+	c.commitKeys.Store(&keys)
+}
+
+func (c *Client) commitAnnotations() []string {
+	if keys := c.commitKeys.Load(); keys != nil {
+		return *keys
+	}
+	return DefaultCommitAnnotations
+}
+
+// AnnotatedCommit returns the first of keys a kustomization sets to a full commit SHA, and that SHA.
+// Keys set to anything else, such as a semver app.kubernetes.io/version, are passed over, so the
+// deployment falls back to correlating its tag.
+func AnnotatedCommit(config *kubernetes.KustomizationConfig, keys []string) (string, string) {
+	for _, key := range keys {
+		value, ok := config.Metadata(key)
+		if !ok {
+			continue
+		}
+		value = strings.ToLower(strings.TrimSpace(value))
+		if isCommitSHA(value) {
+			return key, value
+		}
+		log.Printf("Ignoring kustomization %s %q, it isn't a full commit SHA", key, value)
+	}
+	return "", ""
+}
+
+// isCommitSHA reports whether s is a full, 40 character hex commit SHA
+func isCommitSHA(s string) bool {
+	if len(s) != 40 {
+		return false
+	}
+	for _, r := range s {
+		if !strings.ContainsRune("0123456789abcdef", r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,129 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-github/v57/github"
+)
+
+// Rolled up states of a commit's statuses and check runs
+const (
+	ChecksSuccess = "success"
+	ChecksFailure = "failure"
+	ChecksPending = "pending"
+	ChecksNone    = "none" // nothing reported a status for the commit
+)
+
+// checkRunPages bounds the check runs read for one commit; commits with more than a few hundred
+// check runs are rare and the first pages already tell whether anything failed
+const checkRunPages = 5
+
+// CommitChecks rolls up the legacy commit statuses and the check runs (GitHub Actions jobs and other
+// check apps) reported for a commit
+type CommitChecks struct {
+	SHA          string
+	State        string
+	Total        int
+	Succeeded    int
+	Failed       int
+	Pending      int
+	FailedChecks []string // contexts of failed statuses and names of failed check runs
+}
+
+func (c *CommitChecks) add(name, outcome string) {
+	c.Total++
+	switch outcome {
+	case ChecksSuccess:
+		c.Succeeded++
+	case ChecksFailure:
+		c.Failed++
+		c.FailedChecks = append(c.FailedChecks, name)
+	default:
+		c.Pending++
+	}
+}
+
+// GetCombinedStatusAndChecks merges the combined status API and the latest check runs of a commit
+// into one rollup: failure when anything failed, pending while anything is still running, success
+// when everything passed, and none when nothing reported. Branch protection isn't consulted (reading
+// it needs admin access), so every status and check counts as if it were required. A SHA the
+// repository doesn't have rolls up to none.
+func (c *Client) GetCombinedStatusAndChecks(ctx context.Context, owner, repo, sha string) (*CommitChecks, error) {
+	checks := &CommitChecks{SHA: sha}
+
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		combined, resp, err := c.gh.Repositories.GetCombinedStatus(ctx, owner, repo, sha, opts)
+		if err != nil {
+			if isNotFound(err) || hasStatus(err, http.StatusUnprocessableEntity) {
+				checks.State = ChecksNone
+				return checks, nil
+			}
+			return nil, fmt.Errorf("failed to get commit status: %w", err)
+		}
+		for _, status := range combined.Statuses {
+			outcome := ChecksPending
+			switch status.GetState() {
+			case "success":
+				outcome = ChecksSuccess
+			case "failure", "error":
+				outcome = ChecksFailure
+			}
+			checks.add(status.GetContext(), outcome)
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	runOpts := &github.ListCheckRunsOptions{
+		Filter:      github.String("latest"),
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	for page := 0; page < checkRunPages; page++ {
+		runs, resp, err := c.gh.Checks.ListCheckRunsForRef(ctx, owner, repo, sha, runOpts)
+		if err != nil {
+			if isNotFound(err) || hasStatus(err, http.StatusUnprocessableEntity) {
+				break
+			}
+			return nil, fmt.Errorf("failed to list check runs: %w", err)
+		}
+		for _, run := range runs.CheckRuns {
+			checks.add(run.GetName(), checkRunOutcome(run))
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		runOpts.Page = resp.NextPage
+	}
+
+	switch {
+	case checks.Failed > 0:
+		checks.State = ChecksFailure
+	case checks.Pending > 0:
+		checks.State = ChecksPending
+	case checks.Total > 0:
+		checks.State = ChecksSuccess
+	default:
+		checks.State = ChecksNone
+	}
+	return checks, nil
+}
+
+// checkRunOutcome maps a check run to success, failure or pending. Neutral and skipped runs don't
+// block a merge, so they count as passed.
+func checkRunOutcome(run *github.CheckRun) string {
+	if run.GetStatus() != "completed" {
+		return ChecksPending
+	}
+	switch run.GetConclusion() {
+	case "success", "neutral", "skipped":
+		return ChecksSuccess
+	case "action_required":
+		return ChecksPending
+	}
+	return ChecksFailure
+}
@@ -0,0 +1,73 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/go-github/v57/github"
+)
+
+// commitDiffPages bounds the pages of files read for one commit; GitHub lists at most 3000 files
+// of a commit anyway
+const commitDiffPages = 30
+
+// CommitDiff is a commit with the files it changed
+type CommitDiff struct {
+	SHA       string
+	Message   string
+	Author    string
+	Date      time.Time
+	Additions int
+	Deletions int
+	Files     []CommitFile
+}
+
+// CommitFile is a file a commit changed. Patch is the unified diff of the file; GitHub leaves it
+// empty for binary files and files whose diff is too large to show.
+type CommitFile struct {
+	Filename         string
+	PreviousFilename string // set for renames
+	Status           string // added, removed, modified, renamed, copied, changed or unchanged
+	Additions        int
+	Deletions        int
+	Patch            string
+}
+
+// GetCommitDiff returns a commit with every file it changed and their patches, reading the pages of
+// files GitHub splits large commits into
+func (c *Client) GetCommitDiff(ctx context.Context, owner, repo, sha string) (*CommitDiff, error) {
+	var diff *CommitDiff
+	opts := &github.ListOptions{PerPage: 100}
+	for page := 0; page < commitDiffPages; page++ {
+		commit, resp, err := c.gh.Repositories.GetCommit(ctx, owner, repo, sha, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get commit %s: %w", sha, err)
+		}
+		if diff == nil {
+			diff = &CommitDiff{
+				SHA:       commit.GetSHA(),
+				Message:   commit.GetCommit().GetMessage(),
+				Author:    commit.GetCommit().GetAuthor().GetName(),
+				Date:      commit.GetCommit().GetAuthor().GetDate().Time,
+				Additions: commit.GetStats().GetAdditions(),
+				Deletions: commit.GetStats().GetDeletions(),
+			}
+		}
+		for _, file := range commit.Files {
+			diff.Files = append(diff.Files, CommitFile{
+				Filename:         file.GetFilename(),
+				PreviousFilename: file.GetPreviousFilename(),
+				Status:           file.GetStatus(),
+				Additions:        file.GetAdditions(),
+				Deletions:        file.GetDeletions(),
+				Patch:            file.GetPatch(),
+			})
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return diff, nil
+}
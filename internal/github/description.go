@@ -0,0 +1,217 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DescriptionSource is one place to look for a service description, relative to the service directory.
+// Field selects a (dot separated) key in YAML or JSON files; markdown and plain text files ignore it.
+type DescriptionSource struct {
+	File  string `json:"file"`
+	Field string `json:"field,omitempty"`
+}
+
+// DefaultDescriptionSources is the order used when no strategy is configured
+var DefaultDescriptionSources = []DescriptionSource{
+	{File: "service.yaml", Field: "description"},
+	{File: "README.md"},
+	{File: "package.json", Field: "description"},
+}
+
+var (
+	// markdownDecorationPattern matches images, badges and links that make up a whole line on their own
+	markdownDecorationPattern = regexp.MustCompile(`^(\s*(\[?!\[[^\]]*\]\([^)]*\)\]?(\([^)]*\))?|\[[^\]]*\]\([^)]*\)|\[[^\]]*\]\[[^\]]*\]|<img[^>]*>|</?a[^>]*>|</?p[^>]*>|<br\s*/?>)\s*)+$`)
+	// markdownReferencePattern matches link reference definitions such as "[badge]: https://..."
+	markdownReferencePattern = regexp.MustCompile(`^\[[^\]]+\]:\s*\S+`)
+)
+
+// ParseDescriptionSources parses a comma separated list of "file" or "file:field" entries,
+// e.g. "service.yaml:description,README.md,package.json:description"
+func ParseDescriptionSources(spec string) ([]DescriptionSource, error) {
+	var sources []DescriptionSource
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		file, field, _ := strings.Cut(entry, ":")
+		file = strings.Trim(strings.TrimSpace(file), "/")
+		if file == "" || strings.HasPrefix(path.Clean(file), "..") {
+			return nil, fmt.Errorf("invalid description source %q", entry)
+		}
+
+		sources = append(sources, DescriptionSource{File: file, Field: strings.TrimSpace(field)})
+	}
+
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("no description sources given")
+	}
+	return sources, nil
+}
+
+// SetDescriptionSources changes the order in which service descriptions are looked up during discovery
+func (c *Client) SetDescriptionSources(sources []DescriptionSource) {
+	c.descriptionSources.Store(&sources)
+}
+
+// getServiceMetadata lists the service directory once to see whether it has a README and which
+// description sources exist, then reads the description. The README result is nil when the
+// directory couldn't be listed, in which case every description source is tried.
+func (c *Client) getServiceMetadata(ctx context.Context, owner, repo, servicePath string) (string, *bool) {
+	_, contents, err := c.getContents(ctx, owner, repo, servicePath, nil)
+	if err != nil {
+		return c.getServiceDescription(ctx, owner, repo, servicePath, nil), nil
+	}
+
+	files := make(map[string]bool)
+	hasReadme := false
+	for _, content := range contents {
+		if content.GetType() != "file" {
+			continue
+		}
+		files[content.GetName()] = true
+		if strings.HasPrefix(strings.ToLower(content.GetName()), "readme") {
+			hasReadme = true
+		}
+	}
+
+	return c.getServiceDescription(ctx, owner, repo, servicePath, files), &hasReadme
+}
+
+// getServiceDescription reads the first description found in the configured sources. When files
+// lists the service directory, sources directly in it that aren't listed are skipped.
+func (c *Client) getServiceDescription(ctx context.Context, owner, repo, servicePath string, files map[string]bool) string {
+	sources := DefaultDescriptionSources
+	if configured := c.descriptionSources.Load(); configured != nil && len(*configured) > 0 {
+		sources = *configured
+	}
+
+	for _, source := range sources {
+		if files != nil && !strings.Contains(source.File, "/") && !files[source.File] {
+			continue
+		}
+
+		file, _, err := c.getContents(ctx, owner, repo, fmt.Sprintf("%s/%s", servicePath, source.File), nil)
+		if err != nil || file == nil {
+			continue
+		}
+
+		content, err := file.GetContent()
+		if err != nil {
+			continue
+		}
+
+		if description := ExtractDescription(source, content); description != "" {
+			return description
+		}
+	}
+
+	return ""
+}
+
+// ExtractDescription pulls a description out of a file's content according to the source's format
+func ExtractDescription(source DescriptionSource, content string) string {
+	ext := strings.ToLower(path.Ext(source.File))
+
+	switch {
+	case (ext == ".yaml" || ext == ".yml") && source.Field != "":
+		var data map[string]interface{}
+		if err := yaml.Unmarshal([]byte(content), &data); err != nil {
+			return ""
+		}
+		return lookupField(data, source.Field)
+	case ext == ".json" && source.Field != "":
+		var data map[string]interface{}
+		if err := json.Unmarshal([]byte(content), &data); err != nil {
+			return ""
+		}
+		return lookupField(data, source.Field)
+	case ext == ".md" || ext == ".markdown":
+		return firstMarkdownParagraph(content)
+	default:
+		for _, line := range strings.Split(content, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				return line
+			}
+		}
+		return ""
+	}
+}
+
+// lookupField follows a dot separated key path through nested maps and returns a string value
+func lookupField(data map[string]interface{}, field string) string {
+	var current interface{} = data
+	for _, key := range strings.Split(field, ".") {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		current = m[key]
+	}
+
+	value, ok := current.(string)
+	if !ok {
+		return ""
+	}
+	return strings.TrimSpace(value)
+}
+
+// firstMarkdownParagraph returns the first paragraph of prose, skipping headings, front matter,
+// HTML comments and lines that only contain images, badges or links
+func firstMarkdownParagraph(content string) string {
+	var paragraph []string
+	inComment := false
+	inFrontMatter := false
+
+	for i, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+
+		if i == 0 && line == "---" {
+			inFrontMatter = true
+			continue
+		}
+		if inFrontMatter {
+			if line == "---" {
+				inFrontMatter = false
+			}
+			continue
+		}
+
+		if inComment {
+			if strings.Contains(line, "-->") {
+				inComment = false
+			}
+			continue
+		}
+		if strings.HasPrefix(line, "<!--") {
+			inComment = !strings.Contains(line, "-->")
+			continue
+		}
+
+		skip := line == "" ||
+			strings.HasPrefix(line, "#") ||
+			strings.HasPrefix(line, "```") ||
+			strings.Trim(line, "=-") == "" ||
+			markdownDecorationPattern.MatchString(line) ||
+			markdownReferencePattern.MatchString(line)
+
+		if skip {
+			if len(paragraph) > 0 {
+				break
+			}
+			continue
+		}
+
+		paragraph = append(paragraph, line)
+	}
+
+	return strings.Join(paragraph, " ")
+}
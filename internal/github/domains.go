@@ -0,0 +1,75 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/google/go-github/v57/github"
+)
+
+// SetDomainFolders makes discovery treat each directory under the service root as a domain (e.g.
+// services/payments) holding the service directories, instead of as a service itself
+func (c *Client) SetDomainFolders(enabled bool) {
+	c.domainFolders.Store(enabled)
+}
+
+// ServiceDomain returns the folders between the discovery root and a service directory, e.g.
+// "payments" for services/payments/ledger under root "services". Services directly under the root
+// have no domain. Paths outside the root are taken relative to the repository root.
+func ServiceDomain(root, servicePath string) string {
+	root = strings.Trim(strings.TrimPrefix(root, "./"), "/")
+	servicePath = strings.Trim(strings.TrimPrefix(servicePath, "./"), "/")
+
+	relative := servicePath
+	if root != "" {
+		if rest, ok := strings.CutPrefix(servicePath, root+"/"); ok {
+			relative = rest
+		}
+	}
+
+	domain := path.Dir(relative)
+	if domain == "." {
+		return ""
+	}
+	return domain
+}
+
+// discoverDomainServices discovers the services inside each domain directory under root. Services
+// that share a name with one found earlier in another domain are skipped, since service names are
+// unique per repository.
+func (c *Client) discoverDomainServices(ctx context.Context, owner, repo, root string, domains []*github.RepositoryContent) ([]ServiceInfo, error) {
+	var services []ServiceInfo
+	seen := make(map[string]string)
+
+	for _, domain := range domains {
+		if domain.GetType() != "dir" {
+			continue
+		}
+		domainPath := fmt.Sprintf("%s/%s", root, domain.GetName())
+
+		_, contents, err := c.getContents(ctx, owner, repo, domainPath, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get domain directory %s: %w", domainPath, err)
+		}
+
+		for _, content := range contents {
+			if content.GetType() != "dir" {
+				continue
+			}
+			if other, ok := seen[content.GetName()]; ok {
+				fmt.Printf("[GitHub Client] Skipping %s/%s: a service with that name exists in %s\n", domainPath, content.GetName(), other)
+				continue
+			}
+			seen[content.GetName()] = domain.GetName()
+
+			service := c.serviceInfo(ctx, owner, repo, domainPath, content.GetName())
+			service.Domain = domain.GetName()
+			services = append(services, service)
+		}
+	}
+
+	fmt.Printf("[GitHub Client] Total services discovered in %d domains: %d\n", len(domains), len(services))
+	return services, nil
+}
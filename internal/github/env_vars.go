@@ -0,0 +1,473 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"dev-dashboard/internal/kubernetes"
+
+	"gopkg.in/yaml.v3"
+)
+
+// envResourceDepth is how many levels of resource directories an overlay is followed through
+// looking for its Deployment, e.g. overlay -> base -> shared base
+const envResourceDepth = 3
+
+// EnvVar is an environment variable a container of a service's Deployment sets
+type EnvVar struct {
+	Container string
+	Name      string
+	// Value is the literal value; empty for secret variables
+	Value string
+	// ValueFrom describes where a variable not set literally comes from, e.g.
+	// "configMapKeyRef app-config/LOG_LEVEL"; empty for secret variables
+	ValueFrom string
+	// Secret variables come from a Secret or look like credentials by name. Only a digest of their
+	// value or reference is kept, enough to tell that it changed.
+	Secret bool
+	Digest string
+}
+
+// secretEnvName matches variable names that hold credentials
+var secretEnvName = regexp.MustCompile(`(?i)(PASSWORD|PASSWD|SECRET|TOKEN|API_?KEY|PRIVATE_?KEY|CREDENTIAL)`)
+
+// envPatchPath matches the JSON 6902 paths env patches operate on: the env list of a container, an
+// entry of it or an entry's value
+var envPatchPath = regexp.MustCompile(`^/spec/template/spec/containers/(\d+)/env(?:/(\d+|-)(/value)?)?$`)
+
+// SetEnvVarSnapshots turns reading the environment variables of each overlay's Deployment on or off
+func (c *Client) SetEnvVarSnapshots(enabled bool) {
+	c.envVarSnapshots.Store(enabled)
+}
+
+// envWorkload is a Deployment with the env of its containers, in manifest order
+type envWorkload struct {
+	name       string
+	containers []*envContainer
+}
+
+type envContainer struct {
+	name string
+	env  []map[string]interface{}
+}
+
+// envResolver builds an overlay's Deployments from the manifests it includes and the patches it
+// applies, recording the blob SHA of every file read
+type envResolver struct {
+	client    *Client
+	ctx       context.Context
+	owner     string
+	repo      string
+	files     map[string]string
+	workloads []*envWorkload
+}
+
+// overlayEnvVars returns the environment variables the Deployment of a service gets in an overlay:
+// the Deployment in the resources it includes (local files and directories, followed
+// envResourceDepth levels deep) with its patches and those of its components applied, in the
+// order kustomize applies them. The fingerprint changes whenever a file read changes; it's empty
+// when no Deployment of the service was found.
+func (c *Client) overlayEnvVars(ctx context.Context, owner, repo, kustomizationPath, kustomizationSHA, content, serviceName string) ([]EnvVar, string) {
+	r := &envResolver{
+		client: c,
+		ctx:    ctx,
+		owner:  owner,
+		repo:   repo,
+		files:  map[string]string{kustomizationPath: kustomizationSHA},
+	}
+	r.kustomization(path.Dir(kustomizationPath), content, 0)
+
+	workload := r.serviceWorkload(serviceName)
+	if workload == nil {
+		return nil, ""
+	}
+
+	var vars []EnvVar
+	for _, container := range workload.containers {
+		for _, entry := range container.env {
+			if v, ok := envVarFromEntry(container.name, entry); ok {
+				vars = append(vars, v)
+			}
+		}
+	}
+	return vars, r.fingerprint()
+}
+
+// file fetches a file of the repository and records its blob SHA
+func (r *envResolver) file(filePath string) string {
+	file, _, err := r.client.getContents(r.ctx, r.owner, r.repo, filePath, nil)
+	if err != nil || file == nil {
+		return ""
+	}
+	content, err := file.GetContent()
+	if err != nil {
+		return ""
+	}
+	r.files[filePath] = file.GetSHA()
+	return content
+}
+
+func (r *envResolver) kustomization(dir, content string, depth int) {
+	var k struct {
+		Resources             []string             `yaml:"resources"`
+		Bases                 []string             `yaml:"bases"`
+		Components            []string             `yaml:"components"`
+		Patches               []kustomizationPatch `yaml:"patches"`
+		PatchesJSON6902       []kustomizationPatch `yaml:"patchesJson6902"`
+		PatchesStrategicMerge []string             `yaml:"patchesStrategicMerge"`
+	}
+	if err := yaml.Unmarshal([]byte(content), &k); err != nil {
+		log.Printf("Failed to parse kustomization in %s: %v", dir, err)
+		return
+	}
+
+	for _, resource := range append(k.Bases, k.Resources...) {
+		switch {
+		case strings.Contains(resource, "://") || strings.HasPrefix(resource, "github.com/"):
+			// Remote bases can't be read through the contents API
+		case isLocalYAMLFile(resource):
+			r.manifests(r.file(path.Join(dir, resource)))
+		case depth < envResourceDepth:
+			r.directory(path.Join(dir, resource), depth)
+		}
+	}
+
+	// Patches are applied in the order kustomize applies them, after the resources and before
+	// the components
+	for _, patch := range k.PatchesStrategicMerge {
+		body := patch
+		if !strings.Contains(patch, "\n") {
+			body = r.file(path.Join(dir, patch))
+		}
+		r.patch(body, "")
+	}
+	for _, patch := range append(k.Patches, k.PatchesJSON6902...) {
+		body := patch.Patch
+		if body == "" && patch.Path != "" {
+			body = r.file(path.Join(dir, patch.Path))
+		}
+		target := ""
+		if patch.Target != nil && (patch.Target.Kind == "" || patch.Target.Kind == "Deployment") {
+			target = patch.Target.Name
+		}
+		r.patch(body, target)
+	}
+
+	if depth < envResourceDepth {
+		for _, component := range k.Components {
+			r.directory(path.Join(dir, component), depth)
+		}
+	}
+}
+
+// directory follows the kustomization of a resource or component directory
+func (r *envResolver) directory(dir string, depth int) {
+	for _, fileName := range kubernetes.KustomizationFileNames {
+		if content := r.file(path.Join(dir, fileName)); content != "" {
+			r.kustomization(dir, content, depth+1)
+			return
+		}
+	}
+}
+
+// manifests records the Deployments of a manifest file; a later Deployment of the same name
+// replaces an earlier one
+func (r *envResolver) manifests(content string) {
+	for _, doc := range decodeEnvDocuments(content) {
+		fields, ok := doc.(map[string]interface{})
+		if !ok || lookupField(fields, "kind") != "Deployment" {
+			continue
+		}
+		workload := &envWorkload{name: lookupField(fields, "metadata.name")}
+		for _, c := range podContainers(fields) {
+			container, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _ := container["name"].(string)
+			workload.containers = append(workload.containers, &envContainer{name: name, env: envEntries(container["env"])})
+		}
+
+		replaced := false
+		for i, existing := range r.workloads {
+			if existing.name == workload.name {
+				r.workloads[i] = workload
+				replaced = true
+			}
+		}
+		if !replaced {
+			r.workloads = append(r.workloads, workload)
+		}
+	}
+}
+
+// patch applies the env changes of a strategic merge patch or JSON 6902 operations. A patch
+// without a name applies to target, or to the only Deployment when there's no target either.
+func (r *envResolver) patch(body, target string) {
+	for _, doc := range decodeEnvDocuments(body) {
+		switch doc := doc.(type) {
+		case map[string]interface{}:
+			if kind := lookupField(doc, "kind"); kind != "" && kind != "Deployment" {
+				continue
+			}
+			name := lookupField(doc, "metadata.name")
+			if name == "" {
+				name = target
+			}
+			workload := r.workload(name)
+			if workload == nil {
+				continue
+			}
+			for _, c := range podContainers(doc) {
+				patchContainer, ok := c.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				containerName, _ := patchContainer["name"].(string)
+				container := workload.container(containerName)
+				for _, entry := range envEntries(patchContainer["env"]) {
+					container.mergeEnv(entry)
+				}
+			}
+		case []interface{}:
+			workload := r.workload(target)
+			if workload == nil {
+				continue
+			}
+			for _, op := range doc {
+				if operation, ok := op.(map[string]interface{}); ok {
+					workload.applyEnvOperation(operation)
+				}
+			}
+		}
+	}
+}
+
+// workload returns the Deployment with a name, or the only Deployment for an empty name
+func (r *envResolver) workload(name string) *envWorkload {
+	if name == "" {
+		if len(r.workloads) == 1 {
+			return r.workloads[0]
+		}
+		return nil
+	}
+	for _, workload := range r.workloads {
+		if workload.name == name {
+			return workload
+		}
+	}
+	return nil
+}
+
+// serviceWorkload picks the Deployment of a service: the one named after it, else one whose name
+// contains it, else the only one
+func (r *envResolver) serviceWorkload(serviceName string) *envWorkload {
+	for _, workload := range r.workloads {
+		if workload.name == serviceName {
+			return workload
+		}
+	}
+	for _, workload := range r.workloads {
+		if strings.Contains(workload.name, serviceName) {
+			return workload
+		}
+	}
+	if len(r.workloads) == 1 {
+		return r.workloads[0]
+	}
+	return nil
+}
+
+// fingerprint hashes the path and blob SHA of every file read
+func (r *envResolver) fingerprint() string {
+	paths := make([]string, 0, len(r.files))
+	for filePath := range r.files {
+		paths = append(paths, filePath)
+	}
+	sort.Strings(paths)
+
+	hash := sha256.New()
+	for _, filePath := range paths {
+		fmt.Fprintf(hash, "%s@%s\n", filePath, r.files[filePath])
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// container returns the container with a name, adding it when a patch introduces it
+func (w *envWorkload) container(name string) *envContainer {
+	for _, container := range w.containers {
+		if container.name == name {
+			return container
+		}
+	}
+	container := &envContainer{name: name}
+	w.containers = append(w.containers, container)
+	return container
+}
+
+// mergeEnv merges a patch's env entry the way strategic merge does, keyed by name: "$patch: delete"
+// removes the variable, otherwise the entry replaces the variable or is appended. The entry
+// replaces rather than merges so switching between value and valueFrom doesn't keep both.
+func (c *envContainer) mergeEnv(entry map[string]interface{}) {
+	name, _ := entry["name"].(string)
+	for i, existing := range c.env {
+		if existing["name"] != name {
+			continue
+		}
+		if entry["$patch"] == "delete" {
+			c.env = append(c.env[:i], c.env[i+1:]...)
+		} else {
+			c.env[i] = entry
+		}
+		return
+	}
+	if entry["$patch"] != "delete" {
+		c.env = append(c.env, entry)
+	}
+}
+
+// applyEnvOperation applies a JSON 6902 operation when it changes a container's env
+func (w *envWorkload) applyEnvOperation(operation map[string]interface{}) {
+	op := lookupField(operation, "op")
+	match := envPatchPath.FindStringSubmatch(lookupField(operation, "path"))
+	if match == nil {
+		return
+	}
+	index, _ := strconv.Atoi(match[1])
+	if index >= len(w.containers) {
+		return
+	}
+	container := w.containers[index]
+	value := operation["value"]
+
+	switch {
+	case match[2] == "":
+		// The whole env list
+		switch op {
+		case "add", "replace":
+			container.env = envEntries(value)
+		case "remove":
+			container.env = nil
+		}
+	case match[3] != "":
+		// The value of an entry
+		i, err := strconv.Atoi(match[2])
+		if err != nil || i >= len(container.env) || (op != "add" && op != "replace") {
+			return
+		}
+		entry := map[string]interface{}{"name": container.env[i]["name"], "value": value}
+		container.env[i] = entry
+	default:
+		entry, _ := value.(map[string]interface{})
+		if match[2] == "-" {
+			if op == "add" && entry != nil {
+				container.env = append(container.env, entry)
+			}
+			return
+		}
+		i, err := strconv.Atoi(match[2])
+		if err != nil || i > len(container.env) {
+			return
+		}
+		switch {
+		case op == "add" && entry != nil:
+			container.env = append(container.env[:i], append([]map[string]interface{}{entry}, container.env[i:]...)...)
+		case op == "replace" && entry != nil && i < len(container.env):
+			container.env[i] = entry
+		case op == "remove" && i < len(container.env):
+			container.env = append(container.env[:i], container.env[i+1:]...)
+		}
+	}
+}
+
+// decodeEnvDocuments decodes every document of a manifest or patch; what was decoded before a
+// broken document is kept
+func decodeEnvDocuments(content string) []interface{} {
+	var docs []interface{}
+	decoder := yaml.NewDecoder(bytes.NewReader([]byte(content)))
+	for {
+		var doc interface{}
+		if err := decoder.Decode(&doc); err != nil {
+			break
+		}
+		docs = append(docs, doc)
+	}
+	return docs
+}
+
+// podContainers returns the containers of a Deployment's pod template
+func podContainers(fields map[string]interface{}) []interface{} {
+	var value interface{} = fields
+	for _, key := range []string{"spec", "template", "spec", "containers"} {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = m[key]
+	}
+	containers, _ := value.([]interface{})
+	return containers
+}
+
+// envEntries returns the named entries of an env list
+func envEntries(value interface{}) []map[string]interface{} {
+	list, _ := value.([]interface{})
+	var entries []map[string]interface{}
+	for _, item := range list {
+		if entry, ok := item.(map[string]interface{}); ok {
+			if name, _ := entry["name"].(string); name != "" {
+				entries = append(entries, entry)
+			}
+		}
+	}
+	return entries
+}
+
+// envVarFromEntry normalizes an env entry. Variables from a Secret or named like credentials keep a
+// digest in place of their value or reference.
+func envVarFromEntry(container string, entry map[string]interface{}) (EnvVar, bool) {
+	v := EnvVar{Container: container}
+	v.Name, _ = entry["name"].(string)
+	if v.Name == "" {
+		return v, false
+	}
+	if value, ok := entry["value"]; ok && value != nil {
+		v.Value = fmt.Sprint(value)
+	}
+
+	if from, ok := entry["valueFrom"].(map[string]interface{}); ok {
+		for _, source := range []string{"secretKeyRef", "configMapKeyRef", "fieldRef", "resourceFieldRef"} {
+			ref, ok := from[source].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			switch source {
+			case "secretKeyRef", "configMapKeyRef":
+				v.ValueFrom = fmt.Sprintf("%s %s/%s", source, lookupField(ref, "name"), lookupField(ref, "key"))
+			case "fieldRef":
+				v.ValueFrom = fmt.Sprintf("%s %s", source, lookupField(ref, "fieldPath"))
+			case "resourceFieldRef":
+				v.ValueFrom = fmt.Sprintf("%s %s", source, lookupField(ref, "resource"))
+			}
+			v.Secret = source == "secretKeyRef"
+			break
+		}
+	}
+
+	if v.Secret || secretEnvName.MatchString(v.Name) {
+		sum := sha256.Sum256([]byte(v.Value + "\x00" + v.ValueFrom))
+		v.Secret = true
+		v.Digest = hex.EncodeToString(sum[:8])
+		v.Value, v.ValueFrom = "", ""
+	}
+	return v, true
+}
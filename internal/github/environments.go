@@ -0,0 +1,128 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/go-github/v57/github"
+)
+
+// PendingDeployment is a deployment of a workflow run that is waiting on an environment protection rule
+type PendingDeployment struct {
+	EnvironmentID         int64
+	EnvironmentName       string
+	WaitTimerStartedAt    *time.Time
+	CurrentUserCanApprove bool
+	Reviewers             []string
+}
+
+// pendingDeploymentResponse mirrors GET /repos/{owner}/{repo}/actions/runs/{run_id}/pending_deployments,
+// which go-github doesn't wrap yet
+type pendingDeploymentResponse struct {
+	Environment struct {
+		ID   int64  `json:"id"`
+		Name string `json:"name"`
+	} `json:"environment"`
+	WaitTimerStartedAt    *github.Timestamp `json:"wait_timer_started_at"`
+	CurrentUserCanApprove bool              `json:"current_user_can_approve"`
+	Reviewers             []struct {
+		Type     string `json:"type"`
+		Reviewer struct {
+			Login string `json:"login"`
+			Name  string `json:"name"`
+			Slug  string `json:"slug"`
+		} `json:"reviewer"`
+	} `json:"reviewers"`
+}
+
+// ListEnvironments returns the deployment environments configured for a repository.
+// Instances without the environments API return an empty list.
+func (c *Client) ListEnvironments(ctx context.Context, owner, repo string) ([]*github.Environment, error) {
+	envs, _, err := c.gh.Repositories.ListEnvironments(ctx, owner, repo, &github.EnvironmentListOptions{
+		ListOptions: github.ListOptions{PerPage: 100},
+	})
+	if err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list environments: %w", err)
+	}
+
+	return envs.Environments, nil
+}
+
+// GetPendingDeployments returns the environments a workflow run is waiting on for approval.
+// Instances without the endpoint (older GitHub Enterprise Server) return an empty list.
+func (c *Client) GetPendingDeployments(ctx context.Context, owner, repo string, runID int64) ([]PendingDeployment, error) {
+	u := fmt.Sprintf("repos/%v/%v/actions/runs/%v/pending_deployments", owner, repo, runID)
+	req, err := c.gh.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pending deployments request: %w", err)
+	}
+
+	var response []pendingDeploymentResponse
+	if _, err := c.gh.Do(ctx, req, &response); err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get pending deployments: %w", err)
+	}
+
+	var pending []PendingDeployment
+	for _, item := range response {
+		deployment := PendingDeployment{
+			EnvironmentID:         item.Environment.ID,
+			EnvironmentName:       item.Environment.Name,
+			CurrentUserCanApprove: item.CurrentUserCanApprove,
+		}
+		if item.WaitTimerStartedAt != nil {
+			deployment.WaitTimerStartedAt = &item.WaitTimerStartedAt.Time
+		}
+		for _, reviewer := range item.Reviewers {
+			switch {
+			case reviewer.Reviewer.Login != "":
+				deployment.Reviewers = append(deployment.Reviewers, reviewer.Reviewer.Login)
+			case reviewer.Reviewer.Slug != "":
+				deployment.Reviewers = append(deployment.Reviewers, reviewer.Reviewer.Slug)
+			case reviewer.Reviewer.Name != "":
+				deployment.Reviewers = append(deployment.Reviewers, reviewer.Reviewer.Name)
+			}
+		}
+		pending = append(pending, deployment)
+	}
+
+	return pending, nil
+}
+
+// ReviewPendingDeployment approves or rejects a workflow run's pending deployment to an environment
+func (c *Client) ReviewPendingDeployment(ctx context.Context, owner, repo string, runID, environmentID int64, state, comment string) error {
+	_, _, err := c.gh.Actions.PendingDeployments(ctx, owner, repo, runID, &github.PendingDeploymentsRequest{
+		EnvironmentIDs: []int64{environmentID},
+		State:          state,
+		Comment:        comment,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to review pending deployment: %w", err)
+	}
+	return nil
+}
+
+func isNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+func hasStatus(err error, status int) bool {
+	var errResp *github.ErrorResponse
+	return errors.As(err, &errResp) && errResp.Response != nil && errResp.Response.StatusCode == status
+}
+
+// isRateLimited reports whether GitHub refused a request because of the primary or secondary rate
+// limit rather than a lack of permission, though both come as 403s
+func isRateLimited(err error) bool {
+	var rateErr *github.RateLimitError
+	var abuseErr *github.AbuseRateLimitError
+	return errors.As(err, &rateErr) || errors.As(err, &abuseErr)
+}
@@ -0,0 +1,48 @@
+package github
+
+import (
+	"context"
+	"fmt"
+)
+
+// RepositoryFile is a file in a repository directory listing
+type RepositoryFile struct {
+	Name string
+	Path string
+	SHA  string // blob SHA, changes whenever the content does
+}
+
+// ListDirectoryFiles returns the files directly in a directory of the default branch, leaving out
+// subdirectories. Listings go through the request cache, so a directory discovery already listed
+// costs no request.
+func (c *Client) ListDirectoryFiles(ctx context.Context, owner, repo, dir string) ([]RepositoryFile, error) {
+	_, contents, err := c.getContents(ctx, owner, repo, dir, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", dir, err)
+	}
+
+	var files []RepositoryFile
+	for _, content := range contents {
+		if content.GetType() != "file" {
+			continue
+		}
+		files = append(files, RepositoryFile{Name: content.GetName(), Path: content.GetPath(), SHA: content.GetSHA()})
+	}
+	return files, nil
+}
+
+// GetFileText returns the content of a file of the default branch
+func (c *Client) GetFileText(ctx context.Context, owner, repo, path string) (string, error) {
+	file, _, err := c.getContents(ctx, owner, repo, path, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to get %s: %w", path, err)
+	}
+	if file == nil {
+		return "", fmt.Errorf("%s is not a file", path)
+	}
+	content, err := file.GetContent()
+	if err != nil {
+		return "", fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	return content, nil
+}
@@ -0,0 +1,231 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Flux kinds the deployment scan reads versions from, detected by apiVersion group and kind
+const (
+	FluxHelmRelease   = "HelmRelease"
+	FluxKustomization = "Kustomization"
+
+	fluxHelmGroup      = "helm.toolkit.fluxcd.io/"
+	fluxKustomizeGroup = "kustomize.toolkit.fluxcd.io/"
+)
+
+// FluxVersionFields are the dot separated field paths a version is read from in each Flux kind,
+// tried in order. A path through a list picks the entry naming the service (by name or newName),
+// or the first entry when none does.
+type FluxVersionFields struct {
+	HelmRelease   []string
+	Kustomization []string
+}
+
+// DefaultFluxVersionFields are read unless configured otherwise: a HelmRelease's chart version,
+// then its image tag value, and a Flux Kustomization's image override, then a substituted version
+var DefaultFluxVersionFields = FluxVersionFields{
+	HelmRelease:   []string{"spec.chart.spec.version", "spec.values.image.tag"},
+	Kustomization: []string{"spec.images.newTag", "spec.postBuild.substitute.version"},
+}
+
+// SetFluxVersionFields changes the fields versions are read from in Flux resources; a kind
+// without fields uses the default ones
+func (c *Client) SetFluxVersionFields(fields FluxVersionFields) {
+	if len(fields.HelmRelease) == 0 {
+		fields.HelmRelease = DefaultFluxVersionFields.HelmRelease
+	}
+	if len(fields.Kustomization) == 0 {
+		fields.Kustomization = DefaultFluxVersionFields.Kustomization
+	}
+	c.fluxFields.Store(&fields)
+}
+
+func (c *Client) fluxVersionFields() FluxVersionFields {
+	if fields := c.fluxFields.Load(); fields != nil {
+		return *fields
+	}
+	return DefaultFluxVersionFields
+}
+
+// ParseFluxFieldPaths parses a comma separated list of field paths, e.g.
+// "spec.chart.spec.version, spec.values.image.tag"
+func ParseFluxFieldPaths(value string) ([]string, error) {
+	var paths []string
+	for _, fieldPath := range strings.Split(value, ",") {
+		fieldPath = strings.TrimSpace(fieldPath)
+		if fieldPath == "" {
+			continue
+		}
+		for _, segment := range strings.Split(fieldPath, ".") {
+			if segment == "" || strings.ContainsAny(segment, " \t") {
+				return nil, fmt.Errorf("invalid field path %q, expected dot separated field names like spec.chart.spec.version", fieldPath)
+			}
+		}
+		paths = append(paths, fieldPath)
+	}
+	return paths, nil
+}
+
+// fluxDocument is a Flux resource found in or through a kustomization
+type fluxDocument struct {
+	kind   string
+	name   string
+	fields map[string]interface{}
+}
+
+// fluxKind returns the Flux kind of a decoded document, or "" when it isn't one the scan reads
+func fluxKind(doc map[string]interface{}) string {
+	apiVersion, _ := doc["apiVersion"].(string)
+	kind, _ := doc["kind"].(string)
+	switch {
+	case kind == FluxHelmRelease && strings.HasPrefix(apiVersion, fluxHelmGroup):
+		return FluxHelmRelease
+	case kind == FluxKustomization && strings.HasPrefix(apiVersion, fluxKustomizeGroup):
+		return FluxKustomization
+	}
+	return ""
+}
+
+// parseFluxDocuments returns the Flux resources among the YAML documents of content
+func parseFluxDocuments(content string) []fluxDocument {
+	var docs []fluxDocument
+	decoder := yaml.NewDecoder(bytes.NewReader([]byte(content)))
+	for {
+		var doc map[string]interface{}
+		// The end of the content or a broken document; what was decoded before it is kept
+		if err := decoder.Decode(&doc); err != nil {
+			break
+		}
+		kind := fluxKind(doc)
+		if kind == "" {
+			continue
+		}
+		name := ""
+		if metadata, ok := doc["metadata"].(map[string]interface{}); ok {
+			name, _ = metadata["name"].(string)
+		}
+		docs = append(docs, fluxDocument{kind: kind, name: name, fields: doc})
+	}
+	return docs
+}
+
+// fluxVersion looks for the version of a service in the Flux resources of a kustomization: the
+// kustomization file itself when it's a Flux Kustomization, the resource files it lists and its
+// patches, later ones overriding earlier ones. Files are fetched relative to the kustomization's
+// directory; directories and remote resources aren't followed. It returns the version and where it
+// was read from, e.g. "HelmRelease spec.chart.spec.version", or "" when there's none.
+func (c *Client) fluxVersion(ctx context.Context, owner, repo, kustomizationPath, content, serviceName string) (string, string) {
+	docs := parseFluxDocuments(content)
+
+	var k struct {
+		Resources             []string             `yaml:"resources"`
+		Patches               []kustomizationPatch `yaml:"patches"`
+		PatchesStrategicMerge []string             `yaml:"patchesStrategicMerge"`
+	}
+	if err := yaml.Unmarshal([]byte(content), &k); err == nil {
+		dir := path.Dir(kustomizationPath)
+		var bodies []string
+		for _, file := range append(k.Resources, k.PatchesStrategicMerge...) {
+			if isLocalYAMLFile(file) {
+				bodies = append(bodies, c.getFileContent(ctx, owner, repo, path.Join(dir, file)))
+			} else if strings.Contains(file, "\n") {
+				// patchesStrategicMerge entries can be inline patches
+				bodies = append(bodies, file)
+			}
+		}
+		for _, patch := range k.Patches {
+			switch {
+			case patch.Patch != "":
+				bodies = append(bodies, patch.Patch)
+			case isLocalYAMLFile(patch.Path):
+				bodies = append(bodies, c.getFileContent(ctx, owner, repo, path.Join(dir, patch.Path)))
+			}
+		}
+		for _, body := range bodies {
+			docs = append(docs, parseFluxDocuments(body)...)
+		}
+	}
+
+	return fluxDocumentsVersion(docs, serviceName, c.fluxVersionFields())
+}
+
+// fluxDocumentsVersion reads the version of a service from Flux documents in order, each one with
+// a version overriding the ones before. Documents named after the service are preferred; when none
+// is, the overlay is taken to deploy the service alone and every document counts.
+func fluxDocumentsVersion(docs []fluxDocument, serviceName string, fields FluxVersionFields) (string, string) {
+	named := docs[:0:0]
+	for _, doc := range docs {
+		if strings.Contains(doc.name, serviceName) {
+			named = append(named, doc)
+		}
+	}
+	if len(named) > 0 {
+		docs = named
+	}
+
+	version, source := "", ""
+	for _, doc := range docs {
+		paths := fields.HelmRelease
+		if doc.kind == FluxKustomization {
+			paths = fields.Kustomization
+		}
+		for _, fieldPath := range paths {
+			if value := lookupFieldPath(doc.fields, strings.Split(fieldPath, "."), serviceName); value != "" {
+				version, source = value, doc.kind+" "+fieldPath
+				break
+			}
+		}
+	}
+	return version, source
+}
+
+// lookupFieldPath returns the scalar at a field path as a string, or "" when there's none
+func lookupFieldPath(value interface{}, segments []string, serviceName string) string {
+	if len(segments) == 0 {
+		switch v := value.(type) {
+		case nil, map[string]interface{}, []interface{}:
+			return ""
+		case string:
+			return strings.TrimSpace(v)
+		default:
+			return fmt.Sprint(v)
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return lookupFieldPath(v[segments[0]], segments[1:], serviceName)
+	case []interface{}:
+		var first string
+		for _, entry := range v {
+			found := lookupFieldPath(entry, segments, serviceName)
+			if found == "" {
+				continue
+			}
+			if fields, ok := entry.(map[string]interface{}); ok {
+				for _, key := range []string{"name", "newName"} {
+					if name, _ := fields[key].(string); name != "" && strings.Contains(name, serviceName) {
+						return found
+					}
+				}
+			}
+			if first == "" {
+				first = found
+			}
+		}
+		return first
+	}
+	return ""
+}
+
+// isLocalYAMLFile reports whether a kustomization entry names a YAML file in the repository
+func isLocalYAMLFile(name string) bool {
+	return !strings.Contains(name, "://") && !strings.Contains(name, "\n") &&
+		(strings.HasSuffix(name, ".yaml") || strings.HasSuffix(name, ".yml"))
+}
@@ -0,0 +1,133 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/go-github/v57/github"
+)
+
+// WebhookEvents are the events a dashboard webhook subscribes to
+var WebhookEvents = []string{"push", "pull_request", "workflow_run"}
+
+// ErrHookPermission is returned when the token isn't allowed to manage a repository's webhooks
+var ErrHookPermission = errors.New("not permitted to manage webhooks - the GitHub token needs the admin:repo_hook scope and admin access to the repository")
+
+// ErrHookNotFound is returned when a webhook no longer exists on GitHub
+var ErrHookNotFound = errors.New("webhook not found")
+
+// Webhook is a repository webhook as configured on GitHub
+type Webhook struct {
+	ID     int64
+	URL    string
+	Events []string
+	Active bool
+}
+
+// WebhookDelivery is one attempt to deliver an event to a webhook
+type WebhookDelivery struct {
+	Event       string
+	StatusCode  int
+	Status      string
+	DeliveredAt time.Time
+}
+
+// Succeeded reports whether the receiver accepted the delivery
+func (d WebhookDelivery) Succeeded() bool {
+	return d.StatusCode >= 200 && d.StatusCode < 300
+}
+
+// CreateWebhook creates an active JSON webhook that signs its payloads with secret
+func (c *Client) CreateWebhook(ctx context.Context, owner, repo, targetURL, secret string) (*Webhook, error) {
+	hook, _, err := c.gh.Repositories.CreateHook(ctx, owner, repo, &github.Hook{
+		Name: github.String("web"),
+		Config: map[string]interface{}{
+			"url":          targetURL,
+			"content_type": "json",
+			"secret":       secret,
+			"insecure_ssl": "0",
+		},
+		Events: WebhookEvents,
+		Active: github.Bool(true),
+	})
+	if err != nil {
+		return nil, hookError("create webhook", err)
+	}
+
+	return toWebhook(hook), nil
+}
+
+// GetWebhook returns a repository webhook, or ErrHookNotFound if it was deleted on GitHub
+func (c *Client) GetWebhook(ctx context.Context, owner, repo string, hookID int64) (*Webhook, error) {
+	hook, _, err := c.gh.Repositories.GetHook(ctx, owner, repo, hookID)
+	if err != nil {
+		return nil, hookError("get webhook", err)
+	}
+
+	return toWebhook(hook), nil
+}
+
+// DeleteWebhook deletes a repository webhook; a webhook that's already gone is not an error
+func (c *Client) DeleteWebhook(ctx context.Context, owner, repo string, hookID int64) error {
+	_, err := c.gh.Repositories.DeleteHook(ctx, owner, repo, hookID)
+	if err != nil {
+		if err := hookError("delete webhook", err); !errors.Is(err, ErrHookNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListWebhookDeliveries returns the most recent deliveries of a webhook, newest first
+func (c *Client) ListWebhookDeliveries(ctx context.Context, owner, repo string, hookID int64, limit int) ([]WebhookDelivery, error) {
+	deliveries, _, err := c.gh.Repositories.ListHookDeliveries(ctx, owner, repo, hookID, &github.ListCursorOptions{PerPage: limit})
+	if err != nil {
+		return nil, hookError("list webhook deliveries", err)
+	}
+
+	var result []WebhookDelivery
+	for _, delivery := range deliveries {
+		result = append(result, WebhookDelivery{
+			Event:       delivery.GetEvent(),
+			StatusCode:  delivery.GetStatusCode(),
+			Status:      delivery.GetStatus(),
+			DeliveredAt: delivery.GetDeliveredAt().Time,
+		})
+	}
+
+	return result, nil
+}
+
+func toWebhook(hook *github.Hook) *Webhook {
+	webhook := &Webhook{
+		ID:     hook.GetID(),
+		Events: hook.Events,
+		Active: hook.GetActive(),
+	}
+	if url, ok := hook.Config["url"].(string); ok {
+		webhook.URL = url
+	}
+	return webhook
+}
+
+// hookError distinguishes missing permissions and deleted hooks from other failures. GitHub answers
+// 404 instead of 403 when creating a hook without admin access, so a 404 on create is a permission
+// problem; elsewhere it means the hook is gone.
+func hookError(operation string, err error) error {
+	var errResp *github.ErrorResponse
+	if errors.As(err, &errResp) && errResp.Response != nil {
+		switch errResp.Response.StatusCode {
+		case http.StatusForbidden:
+			return fmt.Errorf("%w: %v", ErrHookPermission, err)
+		case http.StatusNotFound:
+			if errResp.Response.Request != nil && errResp.Response.Request.Method == http.MethodPost {
+				return fmt.Errorf("%w: %v", ErrHookPermission, err)
+			}
+			return fmt.Errorf("%w: %v", ErrHookNotFound, err)
+		}
+	}
+	return fmt.Errorf("failed to %s: %w", operation, err)
+}
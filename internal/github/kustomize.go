@@ -0,0 +1,155 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"path"
+	"sort"
+	"strings"
+
+	"dev-dashboard/internal/kubernetes"
+
+	"gopkg.in/yaml.v3"
+)
+
+// kustomization holds the parts of a kustomization.yaml (or kind: Component) that can set namespaces.
+// JSON kustomizations decode into it too, JSON being valid YAML.
+type kustomization struct {
+	Namespace             string               `yaml:"namespace"`
+	Components            []string             `yaml:"components"`
+	Patches               []kustomizationPatch `yaml:"patches"`
+	PatchesJSON6902       []kustomizationPatch `yaml:"patchesJson6902"`
+	PatchesStrategicMerge []string             `yaml:"patchesStrategicMerge"`
+}
+
+// kustomizationPatch is an entry of patches or patchesJson6902, given inline or as a file path
+type kustomizationPatch struct {
+	Path   string `yaml:"path"`
+	Patch  string `yaml:"patch"`
+	Target *struct {
+		Kind      string `yaml:"kind"`
+		Name      string `yaml:"name"`
+		Namespace string `yaml:"namespace"`
+	} `yaml:"target"`
+}
+
+// kustomizationNamespaces returns every namespace a kustomization file targets: its namespace field,
+// namespaces set or targeted by its patches and those of the components it includes. Patch and
+// component files are fetched relative to the kustomization's directory.
+func (c *Client) kustomizationNamespaces(ctx context.Context, owner, repo, kustomizationPath, content string) []string {
+	found := make(map[string]bool)
+	c.collectKustomizationNamespaces(ctx, owner, repo, path.Dir(kustomizationPath), content, true, found)
+
+	var namespaces []string
+	for namespace := range found {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+func (c *Client) collectKustomizationNamespaces(ctx context.Context, owner, repo, dir, content string, followComponents bool, found map[string]bool) {
+	var k kustomization
+	if err := yaml.Unmarshal([]byte(content), &k); err != nil {
+		log.Printf("Failed to parse kustomization in %s: %v", dir, err)
+		return
+	}
+
+	if k.Namespace != "" {
+		found[k.Namespace] = true
+	}
+
+	for _, patch := range append(k.Patches, k.PatchesJSON6902...) {
+		if patch.Target != nil && patch.Target.Namespace != "" {
+			found[patch.Target.Namespace] = true
+		}
+		body := patch.Patch
+		if body == "" && patch.Path != "" {
+			body = c.getFileContent(ctx, owner, repo, path.Join(dir, patch.Path))
+		}
+		for _, namespace := range patchNamespaces(body) {
+			found[namespace] = true
+		}
+	}
+
+	for _, patch := range k.PatchesStrategicMerge {
+		body := patch
+		if !strings.Contains(patch, "\n") {
+			body = c.getFileContent(ctx, owner, repo, path.Join(dir, patch))
+		}
+		for _, namespace := range patchNamespaces(body) {
+			found[namespace] = true
+		}
+	}
+
+	// Components are only followed one level deep; they rarely include further components
+	if !followComponents {
+		return
+	}
+	for _, component := range k.Components {
+		componentDir := path.Join(dir, component)
+		var componentContent string
+		for _, fileName := range kubernetes.KustomizationFileNames {
+			if componentContent = c.getFileContent(ctx, owner, repo, path.Join(componentDir, fileName)); componentContent != "" {
+				break
+			}
+		}
+		if componentContent == "" {
+			continue
+		}
+		c.collectKustomizationNamespaces(ctx, owner, repo, componentDir, componentContent, false, found)
+	}
+}
+
+// patchNamespaces returns the namespaces a patch sets, either as a strategic merge patch with
+// metadata.namespace or as JSON 6902 operations on /metadata/namespace
+func patchNamespaces(patch string) []string {
+	var namespaces []string
+
+	decoder := yaml.NewDecoder(bytes.NewReader([]byte(patch)))
+	for {
+		var doc interface{}
+		if err := decoder.Decode(&doc); err != nil {
+			if !errors.Is(err, io.EOF) {
+				log.Printf("Failed to parse kustomization patch: %v", err)
+			}
+			break
+		}
+
+		switch doc := doc.(type) {
+		case map[string]interface{}:
+			if namespace := lookupField(doc, "metadata.namespace"); namespace != "" {
+				namespaces = append(namespaces, namespace)
+			}
+		case []interface{}:
+			for _, op := range doc {
+				operation, ok := op.(map[string]interface{})
+				if !ok || lookupField(operation, "path") != "/metadata/namespace" {
+					continue
+				}
+				if namespace := lookupField(operation, "value"); namespace != "" {
+					namespaces = append(namespaces, namespace)
+				}
+			}
+		}
+	}
+
+	return namespaces
+}
+
+// getFileContent returns the decoded content of a file, or "" if it can't be read
+func (c *Client) getFileContent(ctx context.Context, owner, repo, filePath string) string {
+	file, _, err := c.getContents(ctx, owner, repo, filePath, nil)
+	if err != nil || file == nil {
+		return ""
+	}
+
+	content, err := file.GetContent()
+	if err != nil {
+		return ""
+	}
+	return content
+}
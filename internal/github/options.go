@@ -0,0 +1,59 @@
+package github
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Option customizes how a Client reaches the GitHub API
+type Option func(*clientOptions)
+
+type clientOptions struct {
+	httpClient             *http.Client
+	apiBaseURL             string
+	rateLimiter            *RateLimiter
+	observeTokenExpiration func(token string, expiresAt time.Time)
+}
+
+// WithHTTPClient sends requests through httpClient, e.g. one with a custom transport. The token is
+// still added to every request.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(o *clientOptions) {
+		o.httpClient = httpClient
+	}
+}
+
+// WithRateLimiter sends every request through limiter, which can be shared between clients
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(o *clientOptions) {
+		o.rateLimiter = limiter
+	}
+}
+
+// WithBaseURL sends API requests to baseURL as is, e.g. a local test server or an API proxy. Unlike
+// an enterprise URL, no /api/v3/ path is added and the client isn't treated as GitHub Enterprise.
+// An empty baseURL keeps the default.
+func WithBaseURL(baseURL string) Option {
+	return func(o *clientOptions) {
+		o.apiBaseURL = baseURL
+	}
+}
+
+// ParseAPIBaseURL checks an API base URL override and adds the trailing slash the GitHub client
+// requires
+func ParseAPIBaseURL(value string) (*url.URL, error) {
+	parsed, err := url.Parse(strings.TrimSpace(value))
+	if err != nil {
+		return nil, fmt.Errorf("invalid API base URL %q: %w", value, err)
+	}
+	if (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid API base URL %q, expected http(s)://host[/path]", value)
+	}
+	if !strings.HasSuffix(parsed.Path, "/") {
+		parsed.Path += "/"
+	}
+	return parsed, nil
+}
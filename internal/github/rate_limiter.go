@@ -0,0 +1,152 @@
+package github
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultRequestsPerSecond is how many GitHub API requests per second a RateLimiter lets through
+// on average unless configured otherwise
+const DefaultRequestsPerSecond = 10
+
+const (
+	// throttlePause is how long requests wait after GitHub throttled one without a Retry-After
+	throttlePause = time.Minute
+	// throttleRecovery is how long the rate stays reduced after GitHub throttled a request before
+	// it's doubled back towards the configured rate
+	throttleRecovery = 2 * time.Minute
+	// minThrottleFactor is the lowest fraction of the configured rate throttling reduces it to
+	minThrottleFactor = 1.0 / 16
+)
+
+// RateLimiter is a token bucket the requests of every client created with WithRateLimiter pass
+// through, so bursts from the UI and the background sync are smoothed together. When GitHub
+// throttles a request, all requests pause for the time it asks and the rate is halved, recovering
+// step by step while GitHub stops complaining.
+type RateLimiter struct {
+	mu          sync.Mutex
+	rate        float64 // requests per second, 0 for no limit
+	burst       float64
+	tokens      float64
+	last        time.Time
+	factor      float64 // fraction of rate in effect after throttling
+	throttledAt time.Time
+	pausedUntil time.Time
+}
+
+// NewRateLimiter creates a limiter letting perSecond requests through on average, with bursts of
+// up to a second's worth. perSecond 0 doesn't limit requests.
+func NewRateLimiter(perSecond float64) *RateLimiter {
+	limiter := &RateLimiter{factor: 1}
+	limiter.SetRate(perSecond)
+	return limiter
+}
+
+// SetRate changes the average number of requests per second; 0 doesn't limit requests
+func (l *RateLimiter) SetRate(perSecond float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rate = max(perSecond, 0)
+	l.burst = max(l.rate, 1)
+	l.tokens = l.burst
+	l.last = time.Now()
+}
+
+// Wait blocks until a request may be sent or ctx is done
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	for {
+		delay := l.reserve(time.Now())
+		if delay <= 0 {
+			return nil
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// reserve takes a token and returns 0, or returns how long to wait before trying again
+func (l *RateLimiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Before(l.pausedUntil) {
+		return l.pausedUntil.Sub(now)
+	}
+	if l.rate <= 0 {
+		return 0
+	}
+
+	if l.factor < 1 && now.Sub(l.throttledAt) >= throttleRecovery {
+		l.factor = min(l.factor*2, 1)
+		l.throttledAt = now
+	}
+	rate := l.rate * l.factor
+	l.tokens = min(l.tokens+now.Sub(l.last).Seconds()*rate, l.burst)
+	l.last = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+	return time.Duration((1 - l.tokens) / rate * float64(time.Second))
+}
+
+// Throttled pauses all requests for retryAfter and halves the rate
+func (l *RateLimiter) Throttled(retryAfter time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if until := now.Add(retryAfter); until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+	l.factor = max(l.factor/2, minThrottleFactor)
+	l.throttledAt = now
+	l.tokens = 0
+	log.Printf("GitHub throttled requests, pausing for %s and slowing to %.2f requests per second", retryAfter, l.rate*l.factor)
+}
+
+// rateLimitedTransport sends requests once the limiter allows them and reports throttling
+// responses back to it
+type rateLimitedTransport struct {
+	base    http.RoundTripper
+	limiter *RateLimiter
+}
+
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err == nil {
+		if retryAfter, throttled := secondaryRateLimit(resp); throttled {
+			t.limiter.Throttled(retryAfter)
+		}
+	}
+	return resp, err
+}
+
+// secondaryRateLimit reports whether a response is GitHub throttling requests that came too fast,
+// and how long it asks to wait. Exhausting the primary rate limit isn't throttling: the GitHub
+// client refuses requests until the limit resets on its own.
+func secondaryRateLimit(resp *http.Response) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusForbidden {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if resp.StatusCode == http.StatusTooManyRequests && resp.Header.Get("X-RateLimit-Remaining") != "0" {
+		return throttlePause, true
+	}
+	return 0, false
+}
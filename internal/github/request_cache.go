@@ -0,0 +1,124 @@
+package github
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/google/go-github/v57/github"
+)
+
+// requestCacheSize bounds the cached responses; a cycle over a large kubernetes repository lists a
+// few hundred directories and files
+const requestCacheSize = 2000
+
+// requestCache is a small LRU of GetContents and ListCommits responses keyed by the request. The
+// sync service clears it at the start of every cycle, so responses never outlive the cycle that
+// fetched them, while repeated requests within one (e.g. tag correlation listing a service's
+// commits again for every environment) reach GitHub once. Not-found responses are cached too,
+// since probing for missing paths is common; other errors aren't.
+type requestCache struct {
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List // most recently used first
+	hits    int
+	misses  int
+}
+
+type requestCacheEntry struct {
+	key   string
+	value interface{}
+	err   error
+}
+
+func newRequestCache() *requestCache {
+	return &requestCache{entries: make(map[string]*list.Element), order: list.New()}
+}
+
+func (c *requestCache) get(key string) (*requestCacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.entries[key]
+	if !ok {
+		c.misses++
+		return nil, false
+	}
+	c.hits++
+	c.order.MoveToFront(element)
+	return element.Value.(*requestCacheEntry), true
+}
+
+func (c *requestCache) put(key string, value interface{}, err error) {
+	if err != nil && !isNotFound(err) {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.entries[key]; ok {
+		element.Value = &requestCacheEntry{key: key, value: value, err: err}
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&requestCacheEntry{key: key, value: value, err: err})
+	if c.order.Len() > requestCacheSize {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*requestCacheEntry).key)
+	}
+}
+
+func (c *requestCache) reset() (hits, misses int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	hits, misses = c.hits, c.misses
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+	c.hits, c.misses = 0, 0
+	return hits, misses
+}
+
+// ResetRequestCache empties the cache of GetContents and ListCommits responses and returns how
+// many requests it served and how many went to GitHub since the last reset
+func (c *Client) ResetRequestCache() (hits, misses int) {
+	return c.cache.reset()
+}
+
+type contentsResponse struct {
+	file *github.RepositoryContent
+	dir  []*github.RepositoryContent
+}
+
+// getContents is Repositories.GetContents through the request cache
+func (c *Client) getContents(ctx context.Context, owner, repo, path string, opts *github.RepositoryContentGetOptions) (*github.RepositoryContent, []*github.RepositoryContent, error) {
+	ref := ""
+	if opts != nil {
+		ref = opts.Ref
+	}
+	key := fmt.Sprintf("contents %s/%s/%s@%s", owner, repo, path, ref)
+	if entry, ok := c.cache.get(key); ok {
+		response := entry.value.(contentsResponse)
+		return response.file, response.dir, entry.err
+	}
+
+	file, dir, _, err := c.gh.Repositories.GetContents(ctx, owner, repo, path, opts)
+	c.cache.put(key, contentsResponse{file: file, dir: dir}, err)
+	return file, dir, err
+}
+
+// ListCommits is Repositories.ListCommits through the request cache
+func (c *Client) ListCommits(ctx context.Context, owner, repo string, opts *github.CommitsListOptions) ([]*github.RepositoryCommit, error) {
+	if opts == nil {
+		opts = &github.CommitsListOptions{}
+	}
+	key := fmt.Sprintf("commits %s/%s sha=%s path=%s author=%s since=%s until=%s page=%d per_page=%d",
+		owner, repo, opts.SHA, opts.Path, opts.Author, opts.Since, opts.Until, opts.Page, opts.PerPage)
+	if entry, ok := c.cache.get(key); ok {
+		commits, _ := entry.value.([]*github.RepositoryCommit)
+		return commits, entry.err
+	}
+
+	commits, _, err := c.gh.Repositories.ListCommits(ctx, owner, repo, opts)
+	c.cache.put(key, commits, err)
+	return commits, err
+}
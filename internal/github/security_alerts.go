@@ -0,0 +1,121 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/go-github/v57/github"
+)
+
+// ErrSecurityAlertsUnavailable is returned when a repository's security alerts can't be listed:
+// the feature is disabled for it, the token lacks the security_events scope, or the instance
+// (e.g. an older GitHub Enterprise Server) doesn't have the endpoint
+var ErrSecurityAlertsUnavailable = errors.New("security alerts not available")
+
+// maxSecurityAlertPages bounds the pages of 100 alerts read per repository and source
+const maxSecurityAlertPages = 10
+
+// SecurityAlert is an open Dependabot or code scanning alert. Severity is critical, high, medium
+// or low; Location is the vulnerable package or the file the code scanning alert is in.
+type SecurityAlert struct {
+	Number    int
+	Severity  string
+	Summary   string
+	Location  string
+	HTMLURL   string
+	CreatedAt time.Time
+}
+
+// ListDependabotAlerts returns the open Dependabot alerts of a repository
+func (c *Client) ListDependabotAlerts(ctx context.Context, owner, repo string) ([]SecurityAlert, error) {
+	opts := &github.ListAlertsOptions{
+		State:             github.String("open"),
+		ListCursorOptions: github.ListCursorOptions{PerPage: 100},
+	}
+	var alerts []SecurityAlert
+	for page := 0; page < maxSecurityAlertPages; page++ {
+		found, resp, err := c.gh.Dependabot.ListRepoAlerts(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, securityAlertsError("Dependabot", err)
+		}
+		for _, alert := range found {
+			location := alert.GetDependency().GetPackage().GetName()
+			if manifest := alert.GetDependency().GetManifestPath(); manifest != "" && location != "" {
+				location = fmt.Sprintf("%s (%s)", location, manifest)
+			}
+			alerts = append(alerts, SecurityAlert{
+				Number:    alert.GetNumber(),
+				Severity:  alert.GetSecurityAdvisory().GetSeverity(),
+				Summary:   alert.GetSecurityAdvisory().GetSummary(),
+				Location:  location,
+				HTMLURL:   alert.GetHTMLURL(),
+				CreatedAt: alert.GetCreatedAt().Time,
+			})
+		}
+		// The alerts endpoint pages with cursors
+		if resp.After == "" {
+			break
+		}
+		opts.ListCursorOptions.After = resp.After
+	}
+	return alerts, nil
+}
+
+// ListCodeScanningAlerts returns the open code scanning alerts of a repository
+func (c *Client) ListCodeScanningAlerts(ctx context.Context, owner, repo string) ([]SecurityAlert, error) {
+	opts := &github.AlertListOptions{State: "open", ListOptions: github.ListOptions{PerPage: 100}}
+	var alerts []SecurityAlert
+	for page := 0; page < maxSecurityAlertPages; page++ {
+		found, resp, err := c.gh.CodeScanning.ListAlertsForRepo(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, securityAlertsError("code scanning", err)
+		}
+		for _, alert := range found {
+			summary := alert.GetRule().GetDescription()
+			if summary == "" {
+				summary = alert.GetRuleDescription()
+			}
+			alerts = append(alerts, SecurityAlert{
+				Number:    alert.GetNumber(),
+				Severity:  codeScanningSeverity(alert.GetRule()),
+				Summary:   summary,
+				Location:  alert.GetMostRecentInstance().GetLocation().GetPath(),
+				HTMLURL:   alert.GetHTMLURL(),
+				CreatedAt: alert.GetCreatedAt().Time,
+			})
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.ListOptions.Page = resp.NextPage
+	}
+	return alerts, nil
+}
+
+// codeScanningSeverity is the security severity of a rule, or for rules without one (quality
+// rather than security queries) its error/warning/note severity mapped to high/medium/low
+func codeScanningSeverity(rule *github.Rule) string {
+	if level := rule.GetSecuritySeverityLevel(); level != "" {
+		return level
+	}
+	switch rule.GetSeverity() {
+	case "error":
+		return "high"
+	case "warning":
+		return "medium"
+	default:
+		return "low"
+	}
+}
+
+// securityAlertsError wraps permission and missing endpoint failures in ErrSecurityAlertsUnavailable.
+// Rate limits come as 403s too but aren't: they're returned as is so the next sync tries again.
+func securityAlertsError(source string, err error) error {
+	if !isRateLimited(err) && (hasStatus(err, http.StatusForbidden) || isNotFound(err)) {
+		return fmt.Errorf("%w: %s: %v", ErrSecurityAlertsUnavailable, source, err)
+	}
+	return fmt.Errorf("failed to list %s alerts: %w", source, err)
+}
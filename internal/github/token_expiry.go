@@ -0,0 +1,66 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// TokenExpirationHeader is the response header GitHub sets to the expiry of the personal access
+// token, classic or fine-grained, a request was authenticated with. Tokens without an expiry don't
+// get it.
+const TokenExpirationHeader = "GitHub-Authentication-Token-Expiration"
+
+// tokenExpirationLayouts are the formats GitHub sends the expiry in, e.g. "2024-05-01 12:00:00 UTC"
+var tokenExpirationLayouts = []string{"2006-01-02 15:04:05 MST", "2006-01-02 15:04:05 -0700"}
+
+// ParseTokenExpiration parses a TokenExpirationHeader value
+func ParseTokenExpiration(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	for _, layout := range tokenExpirationLayouts {
+		if expiresAt, err := time.Parse(layout, value); err == nil {
+			return expiresAt, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// WithTokenExpirationObserver calls observe with the client's token and its expiry whenever a
+// response reports one
+func WithTokenExpirationObserver(observe func(token string, expiresAt time.Time)) Option {
+	return func(o *clientOptions) {
+		o.observeTokenExpiration = observe
+	}
+}
+
+// tokenExpirationTransport reports the token expiry of responses to an observer
+type tokenExpirationTransport struct {
+	base    http.RoundTripper
+	token   string
+	observe func(token string, expiresAt time.Time)
+}
+
+func (t *tokenExpirationTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err == nil {
+		if expiresAt, ok := ParseTokenExpiration(resp.Header.Get(TokenExpirationHeader)); ok {
+			t.observe(t.token, expiresAt)
+		}
+	}
+	return resp, err
+}
+
+// GetTokenExpiration returns the expiry GitHub reports for the client's token, or nil when the
+// token doesn't expire. It asks for the rate limits, which don't count against them.
+func (c *Client) GetTokenExpiration(ctx context.Context) (*time.Time, error) {
+	_, resp, err := c.gh.RateLimit.Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check the token: %w", err)
+	}
+	if expiresAt, ok := ParseTokenExpiration(resp.Header.Get(TokenExpirationHeader)); ok {
+		return &expiresAt, nil
+	}
+	return nil, nil
+}
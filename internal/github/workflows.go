@@ -0,0 +1,126 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-github/v57/github"
+)
+
+// rerunnableConclusions are the conclusions a completed run can have for its failed jobs to be re-run
+var rerunnableConclusions = map[string]bool{
+	"failure":   true,
+	"cancelled": true,
+	"timed_out": true,
+}
+
+// GetWorkflowRun returns the current state of a single workflow run
+func (c *Client) GetWorkflowRun(ctx context.Context, owner, repo string, runID int64) (*WorkflowRun, error) {
+	run, _, err := c.gh.Actions.GetWorkflowRunByID(ctx, owner, repo, runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workflow run: %w", err)
+	}
+
+	workflowRun := &WorkflowRun{
+		ID:         run.GetID(),
+		Status:     run.GetStatus(),
+		Conclusion: run.GetConclusion(),
+		Commit:     run.GetHeadSHA(),
+		Branch:     run.GetHeadBranch(),
+		StartedAt:  run.GetCreatedAt().Time,
+	}
+	if run.UpdatedAt != nil {
+		workflowRun.CompletedAt = &run.UpdatedAt.Time
+	}
+
+	return workflowRun, nil
+}
+
+// RerunWorkflowRun re-runs every job of a completed workflow run
+func (c *Client) RerunWorkflowRun(ctx context.Context, owner, repo string, runID int64) error {
+	run, err := c.GetWorkflowRun(ctx, owner, repo, runID)
+	if err != nil {
+		return err
+	}
+	if run.Status != "completed" {
+		return fmt.Errorf("workflow run %d is %s and can't be re-run until it completes", runID, run.Status)
+	}
+
+	if _, err := c.gh.Actions.RerunWorkflowByID(ctx, owner, repo, runID); err != nil {
+		return rerunError(runID, err)
+	}
+	return nil
+}
+
+// RerunFailedJobs re-runs only the failed jobs of a completed workflow run
+func (c *Client) RerunFailedJobs(ctx context.Context, owner, repo string, runID int64) error {
+	run, err := c.GetWorkflowRun(ctx, owner, repo, runID)
+	if err != nil {
+		return err
+	}
+	if run.Status != "completed" {
+		return fmt.Errorf("workflow run %d is %s and can't be re-run until it completes", runID, run.Status)
+	}
+	if !rerunnableConclusions[run.Conclusion] {
+		return fmt.Errorf("workflow run %d concluded with %s and has no failed jobs to re-run", runID, run.Conclusion)
+	}
+
+	if _, err := c.gh.Actions.RerunFailedJobsByID(ctx, owner, repo, runID); err != nil {
+		return rerunError(runID, err)
+	}
+	return nil
+}
+
+// rerunError turns permission failures into a message that says what the token is missing
+func rerunError(runID int64, err error) error {
+	var errResp *github.ErrorResponse
+	if errors.As(err, &errResp) && errResp.Response != nil {
+		switch errResp.Response.StatusCode {
+		case http.StatusForbidden, http.StatusNotFound:
+			return fmt.Errorf("not permitted to re-run workflow run %d - the GitHub token needs write access to Actions: %w", runID, err)
+		}
+	}
+	return fmt.Errorf("failed to re-run workflow run %d: %w", runID, err)
+}
+
+// ErrUsageUnavailable is returned when run timing can't be read, either because the token lacks
+// permission or because the instance (e.g. GitHub Enterprise Server) doesn't expose the endpoint
+var ErrUsageUnavailable = errors.New("workflow run usage not available")
+
+// WorkflowRunUsage is the timing of a workflow run, with billable milliseconds per runner OS
+type WorkflowRunUsage struct {
+	RunDurationMS int64
+	BillableMS    map[string]int64
+}
+
+// GetWorkflowRunUsage returns the billable time of a workflow run
+func (c *Client) GetWorkflowRunUsage(ctx context.Context, owner, repo string, runID int64) (*WorkflowRunUsage, error) {
+	usage, _, err := c.gh.Actions.GetWorkflowRunUsageByID(ctx, owner, repo, runID)
+	if err != nil {
+		var errResp *github.ErrorResponse
+		if errors.As(err, &errResp) && errResp.Response != nil {
+			switch errResp.Response.StatusCode {
+			case http.StatusForbidden, http.StatusNotFound:
+				return nil, fmt.Errorf("%w: %v", ErrUsageUnavailable, err)
+			}
+		}
+		return nil, fmt.Errorf("failed to get workflow run usage: %w", err)
+	}
+
+	result := &WorkflowRunUsage{
+		RunDurationMS: usage.GetRunDurationMS(),
+		BillableMS:    make(map[string]int64),
+	}
+	if usage.Billable != nil {
+		for runnerOS, bill := range *usage.Billable {
+			if bill != nil {
+				result.BillableMS[strings.ToUpper(runnerOS)] = bill.GetTotalMS()
+			}
+		}
+	}
+
+	return result, nil
+}
@@ -16,6 +16,9 @@ type Client struct {
 	username   string
 	authMethod string // "bearer", "basic", or "token"
 	client     *http.Client
+
+	sprintField   string // custom field ID of sprints, see SetCustomFields
+	epicLinkField string // custom field ID of epic links, empty when epics are parents
 }
 
 type Issue struct {
@@ -25,15 +28,45 @@ type Issue struct {
 		Summary     string `json:"summary"`
 		Description string `json:"description"`
 		Status      struct {
-			Name string `json:"name"`
+			Name           string `json:"name"`
+			StatusCategory struct {
+				Key string `json:"key"` // "new", "indeterminate" or "done"
+			} `json:"statusCategory"`
 		} `json:"status"`
+		Assignee *User `json:"assignee"`
 		IssueType struct {
 			Name string `json:"name"`
 		} `json:"issuetype"`
 		Priority struct {
 			Name string `json:"name"`
 		} `json:"priority"`
+		DueDate string   `json:"duedate"` // e.g. "2024-05-30"
+		Labels  []string `json:"labels"`
+		Parent  *struct {
+			Key string `json:"key"`
+		} `json:"parent"`
 	} `json:"fields"`
+
+	// rawFields keeps every field as returned, including custom fields
+	rawFields map[string]json.RawMessage
+}
+
+// User is a JIRA user. Cloud identifies users by AccountID, Server and Data Center by Name.
+type User struct {
+	AccountID   string `json:"accountId"`
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+}
+
+// ID returns the identifier that stays stable for the user on either JIRA flavour
+func (u *User) ID() string {
+	if u == nil {
+		return ""
+	}
+	if u.AccountID != "" {
+		return u.AccountID
+	}
+	return u.Name
 }
 
 func NewClient(baseURL, token string) *Client {
@@ -72,6 +105,11 @@ func NewClientWithAuth(baseURL, username, token, authMethod string) *Client {
 	}
 }
 
+// IssueURL returns the address of an issue in the JIRA web UI
+func (c *Client) IssueURL(issueKey string) string {
+	return fmt.Sprintf("%s/browse/%s", c.baseURL, issueKey)
+}
+
 func (c *Client) getAPIURL(apiVersion string) string {
 	if apiVersion == "" {
 		apiVersion = "2" // Default to API v2 for enterprise compatibility
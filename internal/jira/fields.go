@@ -0,0 +1,169 @@
+package jira
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DefaultSprintField is the custom field JIRA Cloud keeps sprints in. Server and Data Center
+// assign the ID when the agile plugin is installed, so it differs between instances.
+const DefaultSprintField = "customfield_10020"
+
+// CardFields are the details of a ticket shown on its task's card
+type CardFields struct {
+	AssigneeName string
+	DueDate      *time.Time
+	Sprint       string
+	Labels       []string
+	// ParentKey is the key of the ticket's parent, or of its epic on instances linking epics
+	// through a custom field
+	ParentKey string
+}
+
+// SetCustomFields sets the IDs of the custom fields sprints and epic links are read from. An
+// empty sprint field uses DefaultSprintField; an empty epic link field leaves epics to the
+// parent field.
+func (c *Client) SetCustomFields(sprintField, epicLinkField string) {
+	if sprintField == "" {
+		sprintField = DefaultSprintField
+	}
+	c.sprintField = sprintField
+	c.epicLinkField = epicLinkField
+}
+
+// cardFieldNames are the fields requested for card details besides summary, status and assignee
+func (c *Client) cardFieldNames() []string {
+	names := []string{"duedate", "labels", "parent", c.sprintFieldID()}
+	if c.epicLinkField != "" {
+		names = append(names, c.epicLinkField)
+	}
+	return names
+}
+
+func (c *Client) sprintFieldID() string {
+	if c.sprintField == "" {
+		return DefaultSprintField
+	}
+	return c.sprintField
+}
+
+// UnmarshalJSON decodes an issue and keeps its raw fields, so custom fields whose IDs vary
+// between instances can be read
+func (i *Issue) UnmarshalJSON(data []byte) error {
+	type plainIssue Issue
+	if err := json.Unmarshal(data, (*plainIssue)(i)); err != nil {
+		return err
+	}
+	var raw struct {
+		Fields map[string]json.RawMessage `json:"fields"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	i.rawFields = raw.Fields
+	return nil
+}
+
+// CardFields returns the card details of an issue
+func (c *Client) CardFields(issue *Issue) CardFields {
+	fields := CardFields{
+		Sprint: parseSprint(issue.rawFields[c.sprintFieldID()]),
+		Labels: issue.Fields.Labels,
+	}
+	if issue.Fields.Assignee != nil {
+		fields.AssigneeName = issue.Fields.Assignee.DisplayName
+	}
+	if issue.Fields.DueDate != "" {
+		if dueDate, err := time.ParseInLocation("2006-01-02", issue.Fields.DueDate, time.Local); err == nil {
+			fields.DueDate = &dueDate
+		}
+	}
+	if issue.Fields.Parent != nil {
+		fields.ParentKey = issue.Fields.Parent.Key
+	}
+	if fields.ParentKey == "" && c.epicLinkField != "" {
+		var epicKey string
+		if err := json.Unmarshal(issue.rawFields[c.epicLinkField], &epicKey); err == nil {
+			fields.ParentKey = epicKey
+		}
+	}
+	return fields
+}
+
+// sprint is an entry of the sprint field
+type sprint struct {
+	Name  string `json:"name"`
+	State string `json:"state"`
+}
+
+// serverSprintKey finds the keys of the sprint strings older Server versions return, e.g.
+// "com.atlassian.greenhopper.service.sprint.Sprint@1f39f[id=1,rapidViewId=2,state=ACTIVE,name=Sprint 5,...]"
+var serverSprintKey = regexp.MustCompile(`(?:^|,)(\w+)=`)
+
+// parseSprint returns the name of the sprint a ticket is in: its active sprint, else its next
+// future one, else the last one it was in. Cloud returns sprints as objects, older Server versions
+// as strings; either may come as a list or a single value.
+func parseSprint(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+	var entries []json.RawMessage
+	if err := json.Unmarshal(raw, &entries); err != nil {
+		entries = []json.RawMessage{raw}
+	}
+
+	var sprints []sprint
+	for _, entry := range entries {
+		var s sprint
+		if err := json.Unmarshal(entry, &s); err == nil && s.Name != "" {
+			sprints = append(sprints, s)
+			continue
+		}
+		var text string
+		if err := json.Unmarshal(entry, &text); err == nil {
+			if s := parseServerSprint(text); s.Name != "" {
+				sprints = append(sprints, s)
+			}
+		}
+	}
+	if len(sprints) == 0 {
+		return ""
+	}
+
+	for _, state := range []string{"active", "future"} {
+		for _, s := range sprints {
+			if strings.EqualFold(s.State, state) {
+				return s.Name
+			}
+		}
+	}
+	return sprints[len(sprints)-1].Name
+}
+
+// parseServerSprint reads the name and state from a Server sprint string
+func parseServerSprint(text string) sprint {
+	start, end := strings.Index(text, "["), strings.LastIndex(text, "]")
+	if start == -1 || end <= start {
+		return sprint{}
+	}
+	body := text[start+1 : end]
+
+	var s sprint
+	keys := serverSprintKey.FindAllStringSubmatchIndex(body, -1)
+	for i, key := range keys {
+		valueEnd := len(body)
+		if i+1 < len(keys) {
+			valueEnd = keys[i+1][0]
+		}
+		value := body[key[1]:valueEnd]
+		switch body[key[2]:key[3]] {
+		case "name":
+			s.Name = value
+		case "state":
+			s.State = value
+		}
+	}
+	return s
+}
@@ -0,0 +1,40 @@
+package jira
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// DefaultProjectKeyPattern matches the key of any JIRA project
+const DefaultProjectKeyPattern = `[A-Z][A-Z0-9]+`
+
+// KeyExtractor finds issue keys such as PAY-123 in free text like commit messages
+type KeyExtractor struct {
+	pattern *regexp.Regexp
+}
+
+// NewKeyExtractor returns an extractor of the issue keys of projects whose key matches
+// projectKeyPattern, e.g. `PAY|OPS`; empty uses DefaultProjectKeyPattern
+func NewKeyExtractor(projectKeyPattern string) (*KeyExtractor, error) {
+	if projectKeyPattern == "" {
+		projectKeyPattern = DefaultProjectKeyPattern
+	}
+	pattern, err := regexp.Compile(`\b(?:` + projectKeyPattern + `)-[1-9][0-9]*\b`)
+	if err != nil {
+		return nil, fmt.Errorf("invalid project key pattern %q: %w", projectKeyPattern, err)
+	}
+	return &KeyExtractor{pattern: pattern}, nil
+}
+
+// Extract returns the issue keys in text, in order of first mention and without duplicates
+func (e *KeyExtractor) Extract(text string) []string {
+	var keys []string
+	seen := make(map[string]bool)
+	for _, key := range e.pattern.FindAllString(text, -1) {
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
@@ -0,0 +1,151 @@
+package jira
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxKeysPerSearch is the most issue keys sent in one JQL search, matching JIRA's default page
+// size so one request returns every issue
+const MaxKeysPerSearch = 50
+
+// defaultRetryAfter is assumed when a rate-limited response doesn't say how long to wait
+const defaultRetryAfter = time.Minute
+
+// RateLimitError is returned when JIRA rejects a request with 429 Too Many Requests
+type RateLimitError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("JIRA rate limit exceeded (429) - retry after %s", e.RetryAfter)
+}
+
+type searchResult struct {
+	Issues []Issue `json:"issues"`
+}
+
+// SearchIssuesByKeys fetches the summary, status and assignee of the given issues with JQL
+// "key in (...)" queries of up to MaxKeysPerSearch keys each. Keys that don't exist or aren't
+// visible are left out of the result. On error, the issues fetched by earlier queries are
+// returned along with it.
+func (c *Client) SearchIssuesByKeys(keys []string) ([]Issue, error) {
+	if c.token == "" && c.username == "" {
+		return nil, fmt.Errorf("JIRA authentication not configured")
+	}
+
+	var issues []Issue
+	for len(keys) > 0 {
+		batch := keys[:min(len(keys), MaxKeysPerSearch)]
+		keys = keys[len(batch):]
+
+		found, err := c.searchIssueKeys(batch)
+		if err != nil {
+			return issues, err
+		}
+		issues = append(issues, found...)
+	}
+	return issues, nil
+}
+
+func (c *Client) searchIssueKeys(keys []string) ([]Issue, error) {
+	quoted := make([]string, len(keys))
+	for i, key := range keys {
+		quoted[i] = `"` + strings.ReplaceAll(key, `"`, `\"`) + `"`
+	}
+
+	params := url.Values{}
+	params.Set("jql", fmt.Sprintf("key in (%s)", strings.Join(quoted, ",")))
+	params.Set("fields", strings.Join(append([]string{"summary", "status", "assignee"}, c.cardFieldNames()...), ","))
+	params.Set("maxResults", strconv.Itoa(MaxKeysPerSearch))
+	// Without this, one deleted or moved key fails the whole query
+	params.Set("validateQuery", "warn")
+
+	var result searchResult
+	if err := c.getJSON("search?"+params.Encode(), &result); err != nil {
+		return nil, fmt.Errorf("failed to search issues: %w", err)
+	}
+	return result.Issues, nil
+}
+
+// GetMyself returns the user the client is authenticated as
+func (c *Client) GetMyself() (*User, error) {
+	if c.token == "" && c.username == "" {
+		return nil, fmt.Errorf("JIRA authentication not configured")
+	}
+
+	var user User
+	if err := c.getJSON("myself", &user); err != nil {
+		return nil, fmt.Errorf("failed to get current user: %w", err)
+	}
+	return &user, nil
+}
+
+// getJSON decodes a GET of path under the REST API, trying API v2 (enterprise) before v3 (cloud)
+func (c *Client) getJSON(path string, out interface{}) error {
+	var lastErr error
+	for _, apiVersion := range []string{"2", "3"} {
+		lastErr = c.getJSONWithAPI(path, apiVersion, out)
+		if lastErr == nil {
+			return nil
+		}
+
+		// If it's an auth error or a rate limit, don't try other versions
+		var rateLimitErr *RateLimitError
+		if strings.Contains(lastErr.Error(), "unauthorized") || errors.As(lastErr, &rateLimitErr) {
+			return lastErr
+		}
+	}
+	return lastErr
+}
+
+func (c *Client) getJSONWithAPI(path, apiVersion string, out interface{}) error {
+	requestURL := fmt.Sprintf("%s/%s", c.getAPIURL(apiVersion), path)
+
+	req, err := http.NewRequest("GET", requestURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	c.setAuthHeaders(req)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request to %s: %w", requestURL, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized:
+		return fmt.Errorf("unauthorized (401) - check your JIRA credentials and permissions")
+	case http.StatusForbidden:
+		return fmt.Errorf("forbidden (403) - check your JIRA permissions")
+	case http.StatusTooManyRequests:
+		retryAfter := defaultRetryAfter
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+			retryAfter = time.Duration(seconds) * time.Second
+		}
+		return &RateLimitError{RetryAfter: retryAfter}
+	default:
+		return fmt.Errorf("JIRA API error %d: %s", resp.StatusCode, string(body))
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package kubernetes
+
+import (
+	"sort"
+	"strings"
+
+	"dev-dashboard/pkg/types"
+)
+
+// ImageNames maps service names to the image each service's kustomizations deploy it as; "" for
+// services whose image is matched by the service directory name
+type ImageNames map[string]string
+
+// NewImageNames returns the image names of services
+func NewImageNames(services []*types.Microservice) ImageNames {
+	names := make(ImageNames)
+	for _, service := range services {
+		if names[service.Name] == "" {
+			names[service.Name] = service.ImageName
+		}
+	}
+	return names
+}
+
+// Lookup returns the image name of the service a kustomization's service directory belongs to, or
+// "" when it has none. Like the deployment sync, it takes the first service, by name, whose name
+// contains the directory or is contained in it, case-insensitively.
+func (n ImageNames) Lookup(serviceDir string) string {
+	names := make([]string, 0, len(n))
+	for name := range n {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	dir := strings.ToLower(serviceDir)
+	for _, name := range names {
+		lower := strings.ToLower(name)
+		if strings.Contains(lower, dir) || strings.Contains(dir, lower) {
+			return n[name]
+		}
+	}
+	return ""
+}
+
+// ImageNameMatches reports whether an image reference from a kustomization's images list is the
+// image name set on a service. A name with a registry or path has to match exactly; a bare name
+// such as payments-svc also matches the last path component, e.g. registry.corp/payments-svc.
+func ImageNameMatches(reference, imageName string) bool {
+	if reference == "" || imageName == "" {
+		return false
+	}
+	if reference == imageName {
+		return true
+	}
+	if strings.Contains(imageName, "/") {
+		return false
+	}
+	return reference[strings.LastIndex(reference, "/")+1:] == imageName
+}
+
+// ServiceImage returns the image entry of a service: the one matching imageName when it's set and
+// listed, otherwise the first whose name or newName contains serviceName
+func (k *KustomizationConfig) ServiceImage(serviceName, imageName string) *KustomizationImage {
+	if imageName != "" {
+		for i, image := range k.Images {
+			if ImageNameMatches(image.Name, imageName) || ImageNameMatches(image.NewName, imageName) {
+				return &k.Images[i]
+			}
+		}
+	}
+	return k.Image(serviceName)
+}
+
+// ImageRepositories returns the image repositories the images list deploys, in order and without
+// duplicates
+func (k *KustomizationConfig) ImageRepositories() []string {
+	var repositories []string
+	seen := make(map[string]bool)
+	for _, image := range k.Images {
+		if repository := image.Repository(); repository != "" && !seen[repository] {
+			seen[repository] = true
+			repositories = append(repositories, repository)
+		}
+	}
+	return repositories
+}
+
+// SuggestImageName picks the image, out of those a service's kustomization lists, that most likely
+// is the service's own: the only one, or the one sharing the most dash or underscore separated words
+// with the service name. It returns "" when no image shares a word and there's more than one.
+func SuggestImageName(serviceName string, repositories []string) string {
+	if len(repositories) == 1 {
+		return repositories[0]
+	}
+	words := make(map[string]bool)
+	for _, word := range imageNameWords(serviceName) {
+		words[word] = true
+	}
+
+	best, bestShared := "", 0
+	for _, repository := range repositories {
+		shared := 0
+		for _, word := range imageNameWords(repository[strings.LastIndex(repository, "/")+1:]) {
+			if words[word] {
+				shared++
+			}
+		}
+		if shared > bestShared {
+			best, bestShared = repository, shared
+		}
+	}
+	return best
+}
+
+func imageNameWords(name string) []string {
+	return strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return r == '-' || r == '_' || r == '.'
+	})
+}
@@ -1,6 +1,7 @@
 package kubernetes
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/fs"
 	"os"
@@ -13,19 +14,142 @@ import (
 )
 
 type KustomizationConfig struct {
-	Images []struct {
-		Name    string `yaml:"name"`
-		NewName string `yaml:"newName"`
-		NewTag  string `yaml:"newTag"`
-	} `yaml:"images"`
+	Images            []KustomizationImage  `yaml:"images" json:"images"`
+	CommonAnnotations map[string]string     `yaml:"commonAnnotations" json:"commonAnnotations"`
+	CommonLabels      map[string]string     `yaml:"commonLabels" json:"commonLabels"`
+	Labels            []KustomizationLabels `yaml:"labels" json:"labels"`
 }
 
-type Scanner struct{}
+// KustomizationLabels is an entry of a kustomization's labels list
+type KustomizationLabels struct {
+	Pairs map[string]string `yaml:"pairs" json:"pairs"`
+}
+
+// Metadata returns the value the kustomization sets key to in its commonAnnotations, commonLabels
+// or labels, looked up in that order
+func (k *KustomizationConfig) Metadata(key string) (string, bool) {
+	if value, ok := k.CommonAnnotations[key]; ok {
+		return value, true
+	}
+	if value, ok := k.CommonLabels[key]; ok {
+		return value, true
+	}
+	for _, labels := range k.Labels {
+		if value, ok := labels.Pairs[key]; ok {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// KustomizationImage is an entry of a kustomization's images list
+type KustomizationImage struct {
+	Name    string `yaml:"name" json:"name"`
+	NewName string `yaml:"newName" json:"newName"`
+	NewTag  string `yaml:"newTag" json:"newTag"`
+}
+
+// Repository returns the image repository the entry deploys: newName when the image is renamed,
+// name otherwise
+func (i *KustomizationImage) Repository() string {
+	if i.NewName != "" {
+		return i.NewName
+	}
+	return i.Name
+}
+
+// ImageRegistry returns the registry host of an image repository. Like Docker, it treats the first
+// path component as a host only when it contains a "." or ":" or is localhost; anything else is on
+// Docker Hub.
+func ImageRegistry(repository string) string {
+	if repository == "" {
+		return ""
+	}
+	host, _, found := strings.Cut(repository, "/")
+	if found && (strings.ContainsAny(host, ".:") || host == "localhost") {
+		return host
+	}
+	return "docker.io"
+}
+
+// KustomizationFileNames are the file names kustomize reads a kustomization from
+var KustomizationFileNames = []string{"kustomization.yaml", "kustomization.json"}
+
+// IsKustomizationFile reports whether a file name is a YAML or JSON kustomization
+func IsKustomizationFile(name string) bool {
+	for _, fileName := range KustomizationFileNames {
+		if name == fileName {
+			return true
+		}
+	}
+	return false
+}
+
+// overlaysDirNames are the directory names overlays are kept under, besides any "overlays-" prefix
+var overlaysDirNames = []string{"overlays", "overlay", "envs", "environments"}
+
+// IsOverlaysDir reports whether a directory name is one of the overlay directory conventions,
+// e.g. overlays, overlays-argo or envs
+func IsOverlaysDir(name string) bool {
+	if strings.HasPrefix(name, "overlays-") {
+		return true
+	}
+	for _, dirName := range overlaysDirNames {
+		if name == dirName {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseKustomization parses kustomization content, as JSON when the file name ends in .json and
+// as YAML otherwise
+func ParseKustomization(fileName string, content []byte) (*KustomizationConfig, error) {
+	var config KustomizationConfig
+	if strings.HasSuffix(fileName, ".json") {
+		if err := json.Unmarshal(content, &config); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON: %w", err)
+		}
+		return &config, nil
+	}
+
+	if err := yaml.Unmarshal(content, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+	return &config, nil
+}
+
+// Image returns the first image whose name or newName contains serviceName, or nil when there's none
+func (k *KustomizationConfig) Image(serviceName string) *KustomizationImage {
+	for i, image := range k.Images {
+		if strings.Contains(image.Name, serviceName) || strings.Contains(image.NewName, serviceName) {
+			return &k.Images[i]
+		}
+	}
+	return nil
+}
+
+// ImageTag returns the newTag of the first image whose name or newName contains serviceName
+func (k *KustomizationConfig) ImageTag(serviceName string) string {
+	if image := k.Image(serviceName); image != nil {
+		return image.NewTag
+	}
+	return ""
+}
+
+type Scanner struct {
+	imageNames ImageNames
+}
 
 func NewScanner() *Scanner {
 	return &Scanner{}
 }
 
+// SetImageNames sets the image names matched before falling back to images named after the service
+func (s *Scanner) SetImageNames(imageNames ImageNames) {
+	s.imageNames = imageNames
+}
+
 func (s *Scanner) ScanRepository(repoPath string, repositoryID int64) ([]*types.Deployment, error) {
 	var deployments []*types.Deployment
 
@@ -39,7 +163,7 @@ func (s *Scanner) ScanRepository(repoPath string, repositoryID int64) ([]*types.
 			return err
 		}
 
-		if d.IsDir() || !strings.HasSuffix(d.Name(), "kustomization.yaml") {
+		if d.IsDir() || !IsKustomizationFile(d.Name()) {
 			return nil
 		}
 
@@ -68,9 +192,9 @@ func (s *Scanner) parseKustomizationFile(filePath string, repositoryID int64) (*
 		return nil, fmt.Errorf("failed to read file: %w", err)
 	}
 
-	var config KustomizationConfig
-	if err := yaml.Unmarshal(content, &config); err != nil {
-		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	config, err := ParseKustomization(filePath, content)
+	if err != nil {
+		return nil, err
 	}
 
 	if len(config.Images) == 0 {
@@ -78,38 +202,34 @@ func (s *Scanner) parseKustomizationFile(filePath string, repositoryID int64) (*
 	}
 
 	// Extract service, environment, region, and namespace from path
-	// Expected path: kubernetes-resources/services/service-b/overlays/prd/us-west-2/ns-a/kustomization.yaml
+	// Expected path: kubernetes-resources/services/service-b/overlays/prd/us-west-2/ns-a/kustomization.yaml,
+	// or without the namespace level: kubernetes-resources/services/service-b/overlays/prd/us-west-2/kustomization.yaml
 	pathParts := strings.Split(filepath.Dir(filePath), string(filepath.Separator))
-	if len(pathParts) < 6 {
+	if len(pathParts) < 5 {
 		return nil, fmt.Errorf("invalid path structure: %s", filePath)
 	}
 
 	var serviceName, environment, region, namespace string
 	for i, part := range pathParts {
-		if part == "services" && i+5 < len(pathParts) {
+		if part == "services" && i+4 < len(pathParts) && IsOverlaysDir(pathParts[i+2]) {
 			serviceName = pathParts[i+1]
-			// Skip the "overlays" directory at pathParts[i+2]
 			environment = pathParts[i+3]
 			region = pathParts[i+4]
-			namespace = pathParts[i+5]
+			// The namespace level is optional; without it the namespace is empty
+			if i+5 < len(pathParts) {
+				namespace = pathParts[i+5]
+			}
 			break
 		}
 	}
 
-	if serviceName == "" || environment == "" || region == "" || namespace == "" {
+	if serviceName == "" || environment == "" || region == "" {
 		return nil, fmt.Errorf("could not extract service info from path: %s", filePath)
 	}
 
-	// Find the image for this service
-	var imageTag string
-	for _, image := range config.Images {
-		if strings.Contains(image.Name, serviceName) || strings.Contains(image.NewName, serviceName) {
-			imageTag = image.NewTag
-			break
-		}
-	}
-
-	if imageTag == "" {
+	// Find the image for this service, by its image name first
+	image := config.ServiceImage(serviceName, s.imageNames.Lookup(serviceName))
+	if image == nil || image.NewTag == "" {
 		return nil, nil
 	}
 
@@ -118,7 +238,9 @@ func (s *Scanner) parseKustomizationFile(filePath string, repositoryID int64) (*
 		Environment:      environment,
 		Region:           region,
 		Namespace:        namespace,
-		Tag:              imageTag,
+		Tag:              image.NewTag,
+		ImageRepository:  image.Repository(),
+		Registry:         ImageRegistry(image.Repository()),
 		Path:             filePath,
 		CommitSHA:        "", // Will be populated when matching with monorepo commits
 	}
@@ -3,6 +3,7 @@ package models
 import (
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	"dev-dashboard/pkg/types"
@@ -18,14 +19,14 @@ func NewActionModel(db *sql.DB) *ActionModel {
 
 func (m *ActionModel) Create(action *types.Action) error {
 	query := `
-		INSERT INTO actions (repository_id, service_id, resource_id, type, status, workflow_run_id, commit_sha, branch, build_hash, started_at, completed_at, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO actions (repository_id, service_id, resource_id, type, status, conclusion, workflow_run_id, commit_sha, branch, build_hash, started_at, completed_at, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 	now := time.Now()
 	action.CreatedAt = now
 	action.UpdatedAt = now
 
-	result, err := m.db.Exec(query, action.RepositoryID, action.ServiceID, action.ResourceID, action.Type, action.Status, action.WorkflowRunID, action.Commit, action.Branch, action.BuildHash, action.StartedAt, action.CompletedAt, action.CreatedAt, action.UpdatedAt)
+	result, err := m.db.Exec(query, action.RepositoryID, action.ServiceID, action.ResourceID, action.Type, action.Status, action.Conclusion, action.WorkflowRunID, action.Commit, action.Branch, action.BuildHash, action.StartedAt, action.CompletedAt, action.CreatedAt, action.UpdatedAt)
 	if err != nil {
 		return fmt.Errorf("failed to create action: %w", err)
 	}
@@ -40,9 +41,15 @@ func (m *ActionModel) Create(action *types.Action) error {
 }
 
 func (m *ActionModel) GetByRepositoryID(repositoryID int64, limit int) ([]*types.ActionWithDetails, error) {
+	return m.GetRecent(repositoryID, "", limit)
+}
+
+// GetRecent returns the most recent actions, newest first, of a repository or of all repositories
+// when repositoryID is 0, and only those of actionType unless it's empty
+func (m *ActionModel) GetRecent(repositoryID int64, actionType types.ActionType, limit int) ([]*types.ActionWithDetails, error) {
 	query := `
 		SELECT 
-			a.id, a.repository_id, a.service_id, a.resource_id, a.type, a.status, 
+			a.id, a.repository_id, a.service_id, a.resource_id, a.type, a.status, a.conclusion,
 			a.workflow_run_id, a.commit_sha, a.branch, a.build_hash, a.started_at, 
 			a.completed_at, a.created_at, a.updated_at,
 			ms.name as service_name,
@@ -50,12 +57,12 @@ func (m *ActionModel) GetByRepositoryID(repositoryID int64, limit int) ([]*types
 		FROM actions a
 		LEFT JOIN microservices ms ON a.service_id = ms.id
 		LEFT JOIN kubernetes_resources kr ON a.resource_id = kr.id
-		WHERE a.repository_id = ?
+		WHERE (?1 = 0 OR a.repository_id = ?1) AND (?2 = '' OR a.type = ?2)
 		ORDER BY a.started_at DESC
-		LIMIT ?
+		LIMIT ?3
 	`
 	
-	rows, err := m.db.Query(query, repositoryID, limit)
+	rows, err := m.db.Query(query, repositoryID, actionType, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query actions: %w", err)
 	}
@@ -71,6 +78,7 @@ func (m *ActionModel) GetByRepositoryID(repositoryID int64, limit int) ([]*types
 			&action.ResourceID,
 			&action.Type,
 			&action.Status,
+			&action.Conclusion,
 			&action.WorkflowRunID,
 			&action.Commit,
 			&action.Branch,
@@ -91,9 +99,41 @@ func (m *ActionModel) GetByRepositoryID(repositoryID int64, limit int) ([]*types
 	return actions, nil
 }
 
+func (m *ActionModel) GetByID(id int64) (*types.Action, error) {
+	query := `
+		SELECT id, repository_id, service_id, resource_id, type, status, conclusion, workflow_run_id, commit_sha, branch, build_hash, started_at, completed_at, created_at, updated_at
+		FROM actions
+		WHERE id = ?
+	`
+	
+	action := &types.Action{}
+	err := m.db.QueryRow(query, id).Scan(
+		&action.ID,
+		&action.RepositoryID,
+		&action.ServiceID,
+		&action.ResourceID,
+		&action.Type,
+		&action.Status,
+		&action.Conclusion,
+		&action.WorkflowRunID,
+		&action.Commit,
+		&action.Branch,
+		&action.BuildHash,
+		&action.StartedAt,
+		&action.CompletedAt,
+		&action.CreatedAt,
+		&action.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get action: %w", err)
+	}
+
+	return action, nil
+}
+
 func (m *ActionModel) GetByServiceID(serviceID int64, limit int) ([]*types.Action, error) {
 	query := `
-		SELECT id, repository_id, service_id, resource_id, type, status, workflow_run_id, commit_sha, branch, build_hash, started_at, completed_at, created_at, updated_at
+		SELECT id, repository_id, service_id, resource_id, type, status, conclusion, workflow_run_id, commit_sha, branch, build_hash, started_at, completed_at, created_at, updated_at
 		FROM actions
 		WHERE service_id = ?
 		ORDER BY started_at DESC
@@ -116,6 +156,123 @@ func (m *ActionModel) GetByServiceID(serviceID int64, limit int) ([]*types.Actio
 			&action.ResourceID,
 			&action.Type,
 			&action.Status,
+			&action.Conclusion,
+			&action.WorkflowRunID,
+			&action.Commit,
+			&action.Branch,
+			&action.BuildHash,
+			&action.StartedAt,
+			&action.CompletedAt,
+			&action.CreatedAt,
+			&action.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan action: %w", err)
+		}
+		actions = append(actions, action)
+	}
+
+	return actions, nil
+}
+
+// GetLatestDefaultBranchBuilds returns every visible microservice of a repository (or of all
+// repositories when repositoryID is 0) with its most recent build on the default branch. Until sync
+// has recorded a repository's default branch, builds on main or master are used. Services without a
+// build are included with an empty status.
+func (m *ActionModel) GetLatestDefaultBranchBuilds(repositoryID int64) ([]types.BuildMatrixEntry, error) {
+	query := `
+		SELECT m.id, m.name, r.id, r.name, COALESCE(r.default_branch, ''),
+			a.status, a.conclusion, a.workflow_run_id, a.commit_sha, a.branch, a.started_at, a.completed_at
+		FROM microservices m
+		JOIN repositories r ON m.repository_id = r.id
+		LEFT JOIN actions a ON a.id = (
+			SELECT id FROM actions
+			WHERE service_id = m.id AND type = ?
+			AND (branch = r.default_branch OR (COALESCE(r.default_branch, '') = '' AND branch IN ('main', 'master')))
+			ORDER BY started_at DESC, id DESC
+			LIMIT 1
+		)
+		WHERE m.is_hidden = 0 AND (? = 0 OR m.repository_id = ?)
+		ORDER BY r.name, m.name
+	`
+
+	rows, err := m.db.Query(query, types.BuildAction, repositoryID, repositoryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query latest builds: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []types.BuildMatrixEntry{}
+	for rows.Next() {
+		var entry types.BuildMatrixEntry
+		var defaultBranch string
+		var status, conclusion, commit, branch sql.NullString
+		var runID sql.NullInt64
+		err := rows.Scan(
+			&entry.ServiceID,
+			&entry.ServiceName,
+			&entry.RepositoryID,
+			&entry.RepositoryName,
+			&defaultBranch,
+			&status,
+			&conclusion,
+			&runID,
+			&commit,
+			&branch,
+			&entry.StartedAt,
+			&entry.CompletedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan latest build: %w", err)
+		}
+
+		entry.Branch = defaultBranch
+		if branch.Valid {
+			entry.Branch = branch.String
+		}
+		entry.Status = status.String
+		entry.Conclusion = conclusion.String
+		entry.WorkflowRunID = runID.Int64
+		entry.Commit = commit.String
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// GetCompletedByServiceSince returns the completed runs of one action type for a service started since
+// the given time, oldest first. Sync can store a workflow run more than once, so only the latest row
+// of each run is returned.
+func (m *ActionModel) GetCompletedByServiceSince(serviceID int64, actionType types.ActionType, since time.Time) ([]*types.Action, error) {
+	query := `
+		SELECT id, repository_id, service_id, resource_id, type, status, conclusion, workflow_run_id, commit_sha, branch, build_hash, started_at, completed_at, created_at, updated_at
+		FROM actions
+		WHERE id IN (
+			SELECT MAX(id) FROM actions
+			WHERE service_id = ? AND type = ? AND started_at >= ?
+			GROUP BY workflow_run_id
+		)
+		AND status = 'completed'
+		ORDER BY started_at ASC
+	`
+
+	rows, err := m.db.Query(query, serviceID, actionType, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query completed actions: %w", err)
+	}
+	defer rows.Close()
+
+	var actions []*types.Action
+	for rows.Next() {
+		action := &types.Action{}
+		err := rows.Scan(
+			&action.ID,
+			&action.RepositoryID,
+			&action.ServiceID,
+			&action.ResourceID,
+			&action.Type,
+			&action.Status,
+			&action.Conclusion,
 			&action.WorkflowRunID,
 			&action.Commit,
 			&action.Branch,
@@ -136,7 +293,7 @@ func (m *ActionModel) GetByServiceID(serviceID int64, limit int) ([]*types.Actio
 
 func (m *ActionModel) GetByResourceID(resourceID int64, limit int) ([]*types.Action, error) {
 	query := `
-		SELECT id, repository_id, service_id, resource_id, type, status, workflow_run_id, commit_sha, branch, build_hash, started_at, completed_at, created_at, updated_at
+		SELECT id, repository_id, service_id, resource_id, type, status, conclusion, workflow_run_id, commit_sha, branch, build_hash, started_at, completed_at, created_at, updated_at
 		FROM actions
 		WHERE resource_id = ?
 		ORDER BY started_at DESC
@@ -159,6 +316,7 @@ func (m *ActionModel) GetByResourceID(resourceID int64, limit int) ([]*types.Act
 			&action.ResourceID,
 			&action.Type,
 			&action.Status,
+			&action.Conclusion,
 			&action.WorkflowRunID,
 			&action.Commit,
 			&action.Branch,
@@ -180,12 +338,12 @@ func (m *ActionModel) GetByResourceID(resourceID int64, limit int) ([]*types.Act
 func (m *ActionModel) Update(action *types.Action) error {
 	query := `
 		UPDATE actions
-		SET status = ?, build_hash = ?, completed_at = ?, updated_at = ?
+		SET status = ?, conclusion = ?, build_hash = ?, completed_at = ?, updated_at = ?
 		WHERE id = ?
 	`
 	
 	action.UpdatedAt = time.Now()
-	_, err := m.db.Exec(query, action.Status, action.BuildHash, action.CompletedAt, action.UpdatedAt, action.ID)
+	_, err := m.db.Exec(query, action.Status, action.Conclusion, action.BuildHash, action.CompletedAt, action.UpdatedAt, action.ID)
 	if err != nil {
 		return fmt.Errorf("failed to update action: %w", err)
 	}
@@ -193,6 +351,8 @@ func (m *ActionModel) Update(action *types.Action) error {
 	return nil
 }
 
+// UpsertActions stores workflow run actions; runs stored before get their status, conclusion and
+// completion time updated in place
 func (m *ActionModel) UpsertActions(actions []types.Action) error {
 	if len(actions) == 0 {
 		return nil
@@ -205,9 +365,14 @@ func (m *ActionModel) UpsertActions(actions []types.Action) error {
 	defer tx.Rollback()
 
 	query := `
-		INSERT OR REPLACE INTO actions 
-		(repository_id, service_id, resource_id, type, status, workflow_run_id, commit_sha, branch, build_hash, started_at, completed_at, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO actions 
+		(repository_id, service_id, resource_id, type, status, conclusion, workflow_run_id, commit_sha, branch, build_hash, started_at, completed_at, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(repository_id, workflow_run_id) DO UPDATE SET
+			status = excluded.status,
+			conclusion = excluded.conclusion,
+			completed_at = excluded.completed_at,
+			updated_at = excluded.updated_at
 	`
 	
 	stmt, err := tx.Prepare(query)
@@ -229,6 +394,7 @@ func (m *ActionModel) UpsertActions(actions []types.Action) error {
 			action.ResourceID,
 			action.Type,
 			action.Status,
+			action.Conclusion,
 			action.WorkflowRunID,
 			action.Commit,
 			action.Branch,
@@ -244,4 +410,41 @@ func (m *ActionModel) UpsertActions(actions []types.Action) error {
 	}
 
 	return tx.Commit()
-}
\ No newline at end of file
+}
+
+// GetBranches returns the distinct branches of a repository's recorded workflow runs
+func (m *ActionModel) GetBranches(repositoryID int64) ([]string, error) {
+	rows, err := m.db.Query(`SELECT DISTINCT branch FROM actions WHERE repository_id = ? ORDER BY branch`, repositoryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get action branches: %w", err)
+	}
+	defer rows.Close()
+
+	var branches []string
+	for rows.Next() {
+		var branch string
+		if err := rows.Scan(&branch); err != nil {
+			return nil, fmt.Errorf("failed to scan action branch: %w", err)
+		}
+		branches = append(branches, branch)
+	}
+	return branches, rows.Err()
+}
+
+// DeleteByBranches deletes a repository's recorded workflow runs on the given branches and returns
+// how many were deleted
+func (m *ActionModel) DeleteByBranches(repositoryID int64, branches []string) (int64, error) {
+	if len(branches) == 0 {
+		return 0, nil
+	}
+	args := []interface{}{repositoryID}
+	for _, branch := range branches {
+		args = append(args, branch)
+	}
+	query := `DELETE FROM actions WHERE repository_id = ? AND branch IN (?` + strings.Repeat(", ?", len(branches)-1) + `)`
+	result, err := m.db.Exec(query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete actions: %w", err)
+	}
+	return result.RowsAffected()
+}
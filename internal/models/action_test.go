@@ -0,0 +1,48 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"dev-dashboard/internal/models"
+	"dev-dashboard/internal/testsupport"
+	"dev-dashboard/pkg/types"
+)
+
+func TestUpsertActionsUpdatesARunInPlace(t *testing.T) {
+	db := testsupport.NewTestDB(t)
+	repo := testsupport.Repository(t, db)
+	model := models.NewActionModel(db)
+	started := time.Date(2024, time.March, 4, 9, 0, 0, 0, time.UTC)
+	completed := started.Add(5 * time.Minute)
+
+	run := types.Action{RepositoryID: repo.ID, Type: types.BuildAction, Status: "in_progress", WorkflowRunID: 42, Commit: "abc123", Branch: "main", StartedAt: started}
+	if err := model.UpsertActions([]types.Action{run}); err != nil {
+		t.Fatalf("UpsertActions: %v", err)
+	}
+	run.Status, run.Conclusion, run.CompletedAt = "completed", "success", &completed
+	if err := model.UpsertActions([]types.Action{run}); err != nil {
+		t.Fatalf("UpsertActions: %v", err)
+	}
+
+	actions, err := model.GetByRepositoryID(repo.ID, 10)
+	if err != nil {
+		t.Fatalf("GetByRepositoryID: %v", err)
+	}
+	if len(actions) != 1 {
+		t.Fatalf("got %d actions after storing the same run twice, want 1", len(actions))
+	}
+	got := actions[0]
+	if got.Status != "completed" || got.Conclusion != "success" || got.CompletedAt == nil || !got.CompletedAt.Equal(completed) {
+		t.Errorf("got run %s/%s completed at %v, want completed/success at %v", got.Status, got.Conclusion, got.CompletedAt, completed)
+	}
+
+	// Another run of the repository is a row of its own
+	run.WorkflowRunID = 43
+	if err := model.UpsertActions([]types.Action{run}); err != nil {
+		t.Fatalf("UpsertActions: %v", err)
+	}
+	if actions, err := model.GetByRepositoryID(repo.ID, 10); err != nil || len(actions) != 2 {
+		t.Errorf("got %d actions (%v) after storing another run, want 2", len(actions), err)
+	}
+}
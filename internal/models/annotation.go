@@ -0,0 +1,162 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"dev-dashboard/pkg/types"
+)
+
+// AnnotationModel stores notes attached to deployment history entries, commits and actions. Entities
+// are referenced by ID (or SHA for commits) rather than by foreign key, so commits, which aren't
+// stored locally, can be annotated too.
+type AnnotationModel struct {
+	db *sql.DB
+}
+
+func NewAnnotationModel(db *sql.DB) *AnnotationModel {
+	return &AnnotationModel{db: db}
+}
+
+const annotationColumns = `id, entity_type, entity_id, text, created_at`
+
+func scanAnnotation(row interface{ Scan(...interface{}) error }) (*types.Annotation, error) {
+	annotation := &types.Annotation{}
+	err := row.Scan(&annotation.ID, &annotation.EntityType, &annotation.EntityID, &annotation.Text, &annotation.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return annotation, nil
+}
+
+// Create stores an annotation and sets its ID and creation time
+func (m *AnnotationModel) Create(annotation *types.Annotation) error {
+	annotation.CreatedAt = time.Now()
+	result, err := m.db.Exec(`INSERT INTO annotations (entity_type, entity_id, text, created_at) VALUES (?, ?, ?, ?)`,
+		annotation.EntityType, annotation.EntityID, annotation.Text, annotation.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create annotation: %w", err)
+	}
+	annotation.ID, err = result.LastInsertId()
+	return err
+}
+
+// Delete removes an annotation
+func (m *AnnotationModel) Delete(id int64) error {
+	if _, err := m.db.Exec(`DELETE FROM annotations WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete annotation: %w", err)
+	}
+	return nil
+}
+
+// GetByEntity returns the annotations of an entity, newest first
+func (m *AnnotationModel) GetByEntity(entityType, entityID string) ([]*types.Annotation, error) {
+	byEntity, err := m.GetByEntities(entityType, []string{entityID})
+	if err != nil {
+		return nil, err
+	}
+	if annotations := byEntity[entityID]; annotations != nil {
+		return annotations, nil
+	}
+	return []*types.Annotation{}, nil
+}
+
+// GetByEntities returns the annotations of several entities of one type, by entity ID and newest first
+func (m *AnnotationModel) GetByEntities(entityType string, entityIDs []string) (map[string][]*types.Annotation, error) {
+	byEntity := make(map[string][]*types.Annotation)
+	// Stay well below SQLite's limit on bound parameters
+	const batchSize = 500
+	for start := 0; start < len(entityIDs); start += batchSize {
+		batch := entityIDs[start:min(start+batchSize, len(entityIDs))]
+		placeholders := make([]string, len(batch))
+		args := []interface{}{entityType}
+		for i, id := range batch {
+			placeholders[i] = "?"
+			args = append(args, id)
+		}
+
+		query := fmt.Sprintf(`SELECT `+annotationColumns+` FROM annotations WHERE entity_type = ? AND entity_id IN (%s)
+			ORDER BY created_at DESC, id DESC`, strings.Join(placeholders, ", "))
+		if err := m.collect(query, args, func(annotation *types.Annotation) {
+			byEntity[annotation.EntityID] = append(byEntity[annotation.EntityID], annotation)
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return byEntity, nil
+}
+
+// GetRecent returns the latest annotations across all entities, with the annotated service and a
+// description of the entity filled in where the entity is stored locally
+func (m *AnnotationModel) GetRecent(limit int) ([]*types.Annotation, error) {
+	rows, err := m.db.Query(`
+		SELECT a.id, a.entity_type, a.entity_id, a.text, a.created_at,
+			COALESCE(m.id, 0), COALESCE(m.name, ''),
+			CASE a.entity_type
+				WHEN 'deployment_history' THEN COALESCE(h.environment || '/' || h.region || ' ' || h.tag, '')
+				WHEN 'action' THEN COALESCE(ac.type || ' on ' || ac.branch || ' at ' || substr(ac.commit_sha, 1, 7), '')
+				ELSE 'commit ' || substr(a.entity_id, 1, 7)
+			END
+		FROM annotations a
+		LEFT JOIN deployment_history h ON a.entity_type = 'deployment_history' AND h.id = CAST(a.entity_id AS INTEGER)
+		LEFT JOIN actions ac ON a.entity_type = 'action' AND ac.id = CAST(a.entity_id AS INTEGER)
+		LEFT JOIN microservices m ON m.id = COALESCE(h.service_id, ac.service_id)
+		ORDER BY a.created_at DESC, a.id DESC
+		LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query annotations: %w", err)
+	}
+	defer rows.Close()
+
+	annotations := []*types.Annotation{}
+	for rows.Next() {
+		annotation := &types.Annotation{}
+		err := rows.Scan(&annotation.ID, &annotation.EntityType, &annotation.EntityID, &annotation.Text, &annotation.CreatedAt,
+			&annotation.ServiceID, &annotation.ServiceName, &annotation.Subject)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan annotation: %w", err)
+		}
+		annotations = append(annotations, annotation)
+	}
+	return annotations, nil
+}
+
+// EntityExists reports whether a locally stored entity (a deployment history entry or action) exists.
+// Commits aren't stored, so they always exist as far as annotations are concerned.
+func (m *AnnotationModel) EntityExists(entityType, entityID string) (bool, error) {
+	var table string
+	switch entityType {
+	case types.AnnotationDeployment:
+		table = "deployment_history"
+	case types.AnnotationAction:
+		table = "actions"
+	default:
+		return true, nil
+	}
+
+	var exists bool
+	err := m.db.QueryRow(`SELECT EXISTS(SELECT 1 FROM `+table+` WHERE id = CAST(? AS INTEGER))`, entityID).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to look up annotated %s: %w", entityType, err)
+	}
+	return exists, nil
+}
+
+func (m *AnnotationModel) collect(query string, args []interface{}, add func(*types.Annotation)) error {
+	rows, err := m.db.Query(query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query annotations: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		annotation, err := scanAnnotation(rows)
+		if err != nil {
+			return fmt.Errorf("failed to scan annotation: %w", err)
+		}
+		add(annotation)
+	}
+	return nil
+}
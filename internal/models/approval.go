@@ -0,0 +1,167 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"dev-dashboard/pkg/types"
+)
+
+type PendingApprovalModel struct {
+	db *sql.DB
+}
+
+func NewPendingApprovalModel(db *sql.DB) *PendingApprovalModel {
+	return &PendingApprovalModel{db: db}
+}
+
+// ReplaceForRepository swaps the stored pending approvals of a repository for the ones seen in the latest sync
+func (m *PendingApprovalModel) ReplaceForRepository(repositoryID int64, approvals []types.PendingApproval) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM pending_approvals WHERE repository_id = ?", repositoryID); err != nil {
+		return fmt.Errorf("failed to delete existing pending approvals: %w", err)
+	}
+
+	if len(approvals) > 0 {
+		query := `
+			INSERT OR REPLACE INTO pending_approvals
+			(repository_id, workflow_run_id, environment_id, environment_name, reviewers, waiting_since, can_approve, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		`
+		stmt, err := tx.Prepare(query)
+		if err != nil {
+			return fmt.Errorf("failed to prepare statement: %w", err)
+		}
+		defer stmt.Close()
+
+		now := time.Now()
+		for _, approval := range approvals {
+			reviewers, err := json.Marshal(approval.Reviewers)
+			if err != nil {
+				return fmt.Errorf("failed to encode reviewers: %w", err)
+			}
+
+			_, err = stmt.Exec(
+				repositoryID,
+				approval.WorkflowRunID,
+				approval.EnvironmentID,
+				approval.EnvironmentName,
+				string(reviewers),
+				approval.WaitingSince,
+				approval.CanApprove,
+				now,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to insert pending approval: %w", err)
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetAll returns every pending approval along with the action, repository and service it belongs to
+func (m *PendingApprovalModel) GetAll() ([]*types.PendingApproval, error) {
+	query := `
+		SELECT
+			pa.id, pa.repository_id, pa.workflow_run_id, pa.environment_id, pa.environment_name,
+			pa.reviewers, pa.waiting_since, pa.can_approve,
+			r.name,
+			a.id, a.commit_sha, a.branch,
+			ms.name
+		FROM pending_approvals pa
+		JOIN repositories r ON pa.repository_id = r.id
+		LEFT JOIN actions a ON a.id = (
+			SELECT MAX(id) FROM actions
+			WHERE repository_id = pa.repository_id AND workflow_run_id = pa.workflow_run_id
+		)
+		LEFT JOIN microservices ms ON a.service_id = ms.id
+		ORDER BY pa.waiting_since ASC
+	`
+
+	rows, err := m.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pending approvals: %w", err)
+	}
+	defer rows.Close()
+
+	var approvals []*types.PendingApproval
+	for rows.Next() {
+		approval := &types.PendingApproval{}
+		var reviewers, commitSHA, branch sql.NullString
+		err := rows.Scan(
+			&approval.ID,
+			&approval.RepositoryID,
+			&approval.WorkflowRunID,
+			&approval.EnvironmentID,
+			&approval.EnvironmentName,
+			&reviewers,
+			&approval.WaitingSince,
+			&approval.CanApprove,
+			&approval.RepositoryName,
+			&approval.ActionID,
+			&commitSHA,
+			&branch,
+			&approval.ServiceName,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan pending approval: %w", err)
+		}
+		approval.CommitSHA = commitSHA.String
+		approval.Branch = branch.String
+		if reviewers.Valid && reviewers.String != "" {
+			if err := json.Unmarshal([]byte(reviewers.String), &approval.Reviewers); err != nil {
+				return nil, fmt.Errorf("failed to decode reviewers: %w", err)
+			}
+		}
+		approvals = append(approvals, approval)
+	}
+
+	return approvals, nil
+}
+
+// GetByRunAndEnvironment finds the pending approval of a workflow run for the named environment
+func (m *PendingApprovalModel) GetByRunAndEnvironment(repositoryID, workflowRunID int64, environmentName string) (*types.PendingApproval, error) {
+	query := `
+		SELECT id, repository_id, workflow_run_id, environment_id, environment_name, waiting_since, can_approve
+		FROM pending_approvals
+		WHERE repository_id = ? AND workflow_run_id = ? AND environment_name = ?
+	`
+
+	approval := &types.PendingApproval{}
+	err := m.db.QueryRow(query, repositoryID, workflowRunID, environmentName).Scan(
+		&approval.ID,
+		&approval.RepositoryID,
+		&approval.WorkflowRunID,
+		&approval.EnvironmentID,
+		&approval.EnvironmentName,
+		&approval.WaitingSince,
+		&approval.CanApprove,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("no pending approval for environment %s", environmentName)
+		}
+		return nil, fmt.Errorf("failed to get pending approval: %w", err)
+	}
+
+	return approval, nil
+}
+
+func (m *PendingApprovalModel) Delete(id int64) error {
+	query := `DELETE FROM pending_approvals WHERE id = ?`
+
+	_, err := m.db.Exec(query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete pending approval: %w", err)
+	}
+
+	return nil
+}
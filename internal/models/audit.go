@@ -0,0 +1,72 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"dev-dashboard/pkg/types"
+)
+
+type AuditLogModel struct {
+	db *sql.DB
+}
+
+func NewAuditLogModel(db *sql.DB) *AuditLogModel {
+	return &AuditLogModel{db: db}
+}
+
+func (m *AuditLogModel) Create(entry *types.AuditEntry) error {
+	query := `
+		INSERT INTO audit_log (action, repository_id, detail, success, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`
+	entry.CreatedAt = time.Now()
+
+	result, err := m.db.Exec(query, entry.Action, entry.RepositoryID, entry.Detail, entry.Success, entry.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create audit entry: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get audit entry ID: %w", err)
+	}
+
+	entry.ID = id
+	return nil
+}
+
+func (m *AuditLogModel) GetRecent(limit int) ([]*types.AuditEntry, error) {
+	query := `
+		SELECT id, action, repository_id, detail, success, created_at
+		FROM audit_log
+		ORDER BY created_at DESC, id DESC
+		LIMIT ?
+	`
+
+	rows, err := m.db.Query(query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit log: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []*types.AuditEntry{}
+	for rows.Next() {
+		entry := &types.AuditEntry{}
+		err := rows.Scan(
+			&entry.ID,
+			&entry.Action,
+			&entry.RepositoryID,
+			&entry.Detail,
+			&entry.Success,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,134 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"dev-dashboard/pkg/types"
+)
+
+// CommitTicketRefModel stores which JIRA issues the messages of loaded commits mention, for looking
+// up the commits of a ticket
+type CommitTicketRefModel struct {
+	db *sql.DB
+}
+
+func NewCommitTicketRefModel(db *sql.DB) *CommitTicketRefModel {
+	return &CommitTicketRefModel{db: db}
+}
+
+// Record replaces the references of commits of a repository with the issue keys extract finds in
+// their messages
+func (m *CommitTicketRefModel) Record(repositoryID int64, commits []*types.Commit, extract func(message string) []string) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, commit := range commits {
+		err := recordCommitTicketRefs(tx, repositoryID, commit.Hash, commit.Message, commit.Author, commit.Date, extract(commit.Message))
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// Reextract replaces every stored reference with the issue keys extract finds in the stored
+// messages, after the issue key pattern changed. Commits none of whose keys matched the old pattern
+// aren't stored and are only picked up when they're loaded again.
+func (m *CommitTicketRefModel) Reextract(extract func(message string) []string) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+		SELECT DISTINCT repository_id, commit_sha, message, author, committed_at
+		FROM commit_ticket_refs
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to get commit ticket references: %w", err)
+	}
+	type storedCommit struct {
+		repositoryID         int64
+		sha, message, author string
+		committedAt          sql.NullTime
+	}
+	var commits []storedCommit
+	for rows.Next() {
+		var commit storedCommit
+		if err := rows.Scan(&commit.repositoryID, &commit.sha, &commit.message, &commit.author, &commit.committedAt); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan commit ticket reference: %w", err)
+		}
+		commits = append(commits, commit)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to get commit ticket references: %w", err)
+	}
+
+	for _, commit := range commits {
+		err := recordCommitTicketRefs(tx, commit.repositoryID, commit.sha, commit.message, commit.author, commit.committedAt.Time, extract(commit.message))
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func recordCommitTicketRefs(tx *sql.Tx, repositoryID int64, sha, message, author string, committedAt time.Time, keys []string) error {
+	if _, err := tx.Exec(`DELETE FROM commit_ticket_refs WHERE repository_id = ? AND commit_sha = ?`, repositoryID, sha); err != nil {
+		return fmt.Errorf("failed to delete ticket references of commit %s: %w", sha, err)
+	}
+	for _, key := range keys {
+		_, err := tx.Exec(`
+			INSERT INTO commit_ticket_refs (repository_id, commit_sha, ticket_key, message, author, committed_at)
+			VALUES (?, ?, ?, ?, ?, ?)
+		`, repositoryID, sha, key, message, author, committedAt)
+		if err != nil {
+			return fmt.Errorf("failed to store ticket reference of commit %s: %w", sha, err)
+		}
+	}
+	return nil
+}
+
+// GetByTicket returns the commits whose messages mention an issue key, newest first
+func (m *CommitTicketRefModel) GetByTicket(ticketKey string) ([]*types.TicketCommit, error) {
+	rows, err := m.db.Query(`
+		SELECT c.repository_id, r.name, c.commit_sha, c.message, c.author, c.committed_at
+		FROM commit_ticket_refs c
+		JOIN repositories r ON r.id = c.repository_id
+		WHERE c.ticket_key = ?
+		ORDER BY c.committed_at DESC
+	`, ticketKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commits of ticket %s: %w", ticketKey, err)
+	}
+	defer rows.Close()
+
+	commits := []*types.TicketCommit{}
+	for rows.Next() {
+		commit := &types.TicketCommit{}
+		var committedAt sql.NullTime
+		err := rows.Scan(&commit.RepositoryID, &commit.RepositoryName, &commit.Hash, &commit.Message, &commit.Author, &committedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan commit of ticket %s: %w", ticketKey, err)
+		}
+		commit.Date = committedAt.Time
+		commits = append(commits, commit)
+	}
+	return commits, rows.Err()
+}
@@ -4,10 +4,13 @@ import (
 	"database/sql"
 	"fmt"
 	"time"
+
+	"dev-dashboard/internal/config"
 )
 
 type ConfigModel struct {
-	db *sql.DB
+	db     *sql.DB
+	schema *config.Schema
 }
 
 type Config struct {
@@ -57,6 +60,23 @@ func (m *ConfigModel) Set(key, value string) error {
 	return nil
 }
 
+// SetSchema sets the known keys SetValidated checks values against
+func (m *ConfigModel) SetSchema(schema *config.Schema) {
+	m.schema = schema
+}
+
+// SetValidated stores a value after checking it against the schema: unknown keys and values that
+// don't match the key's type or validation are rejected. Set stores any key as is.
+func (m *ConfigModel) SetValidated(key, value string) error {
+	if m.schema == nil {
+		return fmt.Errorf("config schema not set")
+	}
+	if err := m.schema.Validate(key, value); err != nil {
+		return err
+	}
+	return m.Set(key, value)
+}
+
 func (m *ConfigModel) GetAll() (map[string]string, error) {
 	query := `SELECT key, value FROM config`
 	
@@ -0,0 +1,155 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"dev-dashboard/pkg/types"
+)
+
+// CustomFieldModel stores user-defined fields and their values. Definitions belong to an entity
+// type (e.g. services) and values are keyed by the entity's ID, so any table can reuse them.
+type CustomFieldModel struct {
+	db *sql.DB
+}
+
+func NewCustomFieldModel(db *sql.DB) *CustomFieldModel {
+	return &CustomFieldModel{db: db}
+}
+
+const customFieldColumns = `d.id, d.entity_type, d.name, d.type, d.allowed_values, d.created_at, d.updated_at,
+	(SELECT COUNT(*) FROM custom_field_values v WHERE v.field_id = d.id)`
+
+func scanCustomField(row interface{ Scan(...interface{}) error }) (*types.CustomFieldDefinition, error) {
+	field := &types.CustomFieldDefinition{}
+	var allowedValues string
+	err := row.Scan(&field.ID, &field.EntityType, &field.Name, &field.Type, &allowedValues, &field.CreatedAt, &field.UpdatedAt, &field.ValueCount)
+	if err != nil {
+		return nil, err
+	}
+	field.AllowedValues = splitLines(allowedValues)
+	return field, nil
+}
+
+// GetDefinitions returns the fields defined for an entity type, by name
+func (m *CustomFieldModel) GetDefinitions(entityType string) ([]*types.CustomFieldDefinition, error) {
+	rows, err := m.db.Query(`SELECT `+customFieldColumns+` FROM custom_field_definitions d WHERE d.entity_type = ? ORDER BY d.name COLLATE NOCASE`, entityType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query custom fields: %w", err)
+	}
+	defer rows.Close()
+
+	fields := []*types.CustomFieldDefinition{}
+	for rows.Next() {
+		field, err := scanCustomField(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan custom field: %w", err)
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// GetDefinition returns a field definition, or nil when it doesn't exist
+func (m *CustomFieldModel) GetDefinition(id int64) (*types.CustomFieldDefinition, error) {
+	field, err := scanCustomField(m.db.QueryRow(`SELECT `+customFieldColumns+` FROM custom_field_definitions d WHERE d.id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get custom field: %w", err)
+	}
+	return field, nil
+}
+
+// CreateDefinition stores a new field definition and sets its ID
+func (m *CustomFieldModel) CreateDefinition(field *types.CustomFieldDefinition) error {
+	result, err := m.db.Exec(`INSERT INTO custom_field_definitions (entity_type, name, type, allowed_values) VALUES (?, ?, ?, ?)`,
+		field.EntityType, field.Name, field.Type, strings.Join(field.AllowedValues, "\n"))
+	if err != nil {
+		return fmt.Errorf("failed to create custom field: %w", err)
+	}
+	field.ID, err = result.LastInsertId()
+	return err
+}
+
+// UpdateDefinition renames a field and replaces its allowed values. The type can't change, since
+// existing values were validated against it.
+func (m *CustomFieldModel) UpdateDefinition(field *types.CustomFieldDefinition) error {
+	_, err := m.db.Exec(`UPDATE custom_field_definitions SET name = ?, allowed_values = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`,
+		field.Name, strings.Join(field.AllowedValues, "\n"), field.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update custom field: %w", err)
+	}
+	return nil
+}
+
+// DeleteDefinition deletes a field along with all its values
+func (m *CustomFieldModel) DeleteDefinition(id int64) error {
+	if _, err := m.db.Exec(`DELETE FROM custom_field_definitions WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete custom field: %w", err)
+	}
+	return nil
+}
+
+// GetValues returns the field values of every entity of an entity type, by entity ID and field name
+func (m *CustomFieldModel) GetValues(entityType string) (map[int64]map[string]string, error) {
+	rows, err := m.db.Query(`SELECT v.entity_id, d.name, v.value FROM custom_field_values v
+		JOIN custom_field_definitions d ON d.id = v.field_id
+		WHERE d.entity_type = ?`, entityType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query custom field values: %w", err)
+	}
+	defer rows.Close()
+
+	values := make(map[int64]map[string]string)
+	for rows.Next() {
+		var entityID int64
+		var name, value string
+		if err := rows.Scan(&entityID, &name, &value); err != nil {
+			return nil, fmt.Errorf("failed to scan custom field value: %w", err)
+		}
+		if values[entityID] == nil {
+			values[entityID] = make(map[string]string)
+		}
+		values[entityID][name] = value
+	}
+	return values, nil
+}
+
+// CountValuesIn returns how many entities have one of values for a field
+func (m *CustomFieldModel) CountValuesIn(fieldID int64, values []string) (int, error) {
+	if len(values) == 0 {
+		return 0, nil
+	}
+	placeholders := make([]string, len(values))
+	args := []interface{}{fieldID}
+	for i, value := range values {
+		placeholders[i] = "?"
+		args = append(args, value)
+	}
+
+	var count int
+	query := fmt.Sprintf(`SELECT COUNT(*) FROM custom_field_values WHERE field_id = ? AND value IN (%s)`, strings.Join(placeholders, ", "))
+	if err := m.db.QueryRow(query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count custom field values: %w", err)
+	}
+	return count, nil
+}
+
+// SetValue sets a field's value for an entity; an empty value clears it
+func (m *CustomFieldModel) SetValue(fieldID, entityID int64, value string) error {
+	var err error
+	if value == "" {
+		_, err = m.db.Exec(`DELETE FROM custom_field_values WHERE field_id = ? AND entity_id = ?`, fieldID, entityID)
+	} else {
+		_, err = m.db.Exec(`INSERT INTO custom_field_values (field_id, entity_id, value) VALUES (?, ?, ?)
+			ON CONFLICT(field_id, entity_id) DO UPDATE SET value = excluded.value, updated_at = CURRENT_TIMESTAMP`,
+			fieldID, entityID, value)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set custom field value: %w", err)
+	}
+	return nil
+}
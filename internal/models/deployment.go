@@ -3,8 +3,12 @@ package models
 import (
 	"database/sql"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
+	"dev-dashboard/internal/freeze"
+	"dev-dashboard/internal/vcs"
 	"dev-dashboard/pkg/types"
 )
 
@@ -18,14 +22,20 @@ func NewDeploymentModel(db *sql.DB) *DeploymentModel {
 
 func (d *DeploymentModel) Create(deployment *types.Deployment) error {
 	query := `
-		INSERT INTO deployments (service_id, kubernetes_repo_id, commit_sha, environment, region, namespace, tag, path, discovered_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO deployments (service_id, kubernetes_repo_id, commit_sha, environment, region, namespace, tag, path, discovered_at, updated_at,
+			version_major, version_minor, version_patch, version_prerelease, version_build, image_repository, registry, correlation_status, uncorrelated_since)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NULLIF(?, ''), NULLIF(?, ''), ?, ?)
 	`
+	if err := cleanDeploymentPath(deployment); err != nil {
+		return err
+	}
 	now := time.Now()
 	deployment.DiscoveredAt = now
 	deployment.UpdatedAt = now
 
-	result, err := d.db.Exec(query, deployment.ServiceID, deployment.KubernetesRepoID, deployment.CommitSHA, deployment.Environment, deployment.Region, deployment.Namespace, deployment.Tag, deployment.Path, deployment.DiscoveredAt, deployment.UpdatedAt)
+	args := []interface{}{deployment.ServiceID, deployment.KubernetesRepoID, deployment.CommitSHA, deployment.Environment, deployment.Region, deployment.Namespace, deployment.Tag, deployment.Path, deployment.DiscoveredAt, deployment.UpdatedAt}
+	args = append(args, versionArgs(deployment.Version)...)
+	result, err := d.db.Exec(query, append(args, deployment.ImageRepository, deployment.Registry, deployment.CorrelationStatus, deployment.UncorrelatedSince)...)
 	if err != nil {
 		return fmt.Errorf("failed to create deployment: %w", err)
 	}
@@ -41,7 +51,9 @@ func (d *DeploymentModel) Create(deployment *types.Deployment) error {
 
 func (d *DeploymentModel) GetByServiceID(serviceID int64) ([]*types.Deployment, error) {
 	query := `
-		SELECT id, service_id, kubernetes_repo_id, commit_sha, environment, region, namespace, tag, path, discovered_at, updated_at
+		SELECT id, service_id, kubernetes_repo_id, commit_sha, environment, region, namespace, tag, actual_tag, path, discovered_at, updated_at,
+			version_major, version_minor, version_patch, version_prerelease, version_build, COALESCE(image_repository, ''), COALESCE(registry, ''),
+			correlation_status, uncorrelated_since
 		FROM deployments
 		WHERE service_id = ?
 		ORDER BY environment, region, namespace
@@ -56,7 +68,8 @@ func (d *DeploymentModel) GetByServiceID(serviceID int64) ([]*types.Deployment,
 	var deployments []*types.Deployment
 	for rows.Next() {
 		deployment := &types.Deployment{}
-		var namespace sql.NullString
+		var namespace, actualTag sql.NullString
+		var version scannedVersion
 		err := rows.Scan(
 			&deployment.ID,
 			&deployment.ServiceID,
@@ -66,13 +79,19 @@ func (d *DeploymentModel) GetByServiceID(serviceID int64) ([]*types.Deployment,
 			&deployment.Region,
 			&namespace,
 			&deployment.Tag,
+			&actualTag,
 			&deployment.Path,
 			&deployment.DiscoveredAt,
 			&deployment.UpdatedAt,
+			&version.major, &version.minor, &version.patch, &version.prerelease, &version.build,
+			&deployment.ImageRepository, &deployment.Registry,
+			&deployment.CorrelationStatus, &deployment.UncorrelatedSince,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan deployment: %w", err)
 		}
+		deployment.Version = version.tagVersion()
+		deployment.ActualTag, deployment.Synced = reconcileTag(deployment.Tag, actualTag)
 		
 		// Handle NULL namespace
 		if namespace.Valid {
@@ -89,13 +108,16 @@ func (d *DeploymentModel) GetByServiceID(serviceID int64) ([]*types.Deployment,
 
 func (d *DeploymentModel) GetByID(id int64) (*types.Deployment, error) {
 	query := `
-		SELECT id, service_id, kubernetes_repo_id, commit_sha, environment, region, namespace, tag, path, discovered_at, updated_at
+		SELECT id, service_id, kubernetes_repo_id, commit_sha, environment, region, namespace, tag, actual_tag, path, discovered_at, updated_at,
+			version_major, version_minor, version_patch, version_prerelease, version_build, COALESCE(image_repository, ''), COALESCE(registry, ''),
+			correlation_status, uncorrelated_since
 		FROM deployments
 		WHERE id = ?
 	`
 	
 	deployment := &types.Deployment{}
-	var namespace sql.NullString
+	var namespace, actualTag sql.NullString
+	var version scannedVersion
 	err := d.db.QueryRow(query, id).Scan(
 		&deployment.ID,
 		&deployment.ServiceID,
@@ -105,13 +127,19 @@ func (d *DeploymentModel) GetByID(id int64) (*types.Deployment, error) {
 		&deployment.Region,
 		&namespace,
 		&deployment.Tag,
+		&actualTag,
 		&deployment.Path,
 		&deployment.DiscoveredAt,
 		&deployment.UpdatedAt,
+		&version.major, &version.minor, &version.patch, &version.prerelease, &version.build,
+		&deployment.ImageRepository, &deployment.Registry,
+		&deployment.CorrelationStatus, &deployment.UncorrelatedSince,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get deployment: %w", err)
 	}
+	deployment.Version = version.tagVersion()
+	deployment.ActualTag, deployment.Synced = reconcileTag(deployment.Tag, actualTag)
 
 	// Handle NULL namespace
 	if namespace.Valid {
@@ -126,12 +154,19 @@ func (d *DeploymentModel) GetByID(id int64) (*types.Deployment, error) {
 func (d *DeploymentModel) Update(deployment *types.Deployment) error {
 	query := `
 		UPDATE deployments
-		SET commit_sha = ?, tag = ?, path = ?, updated_at = ?
+		SET commit_sha = ?, tag = ?, path = ?, updated_at = ?,
+			version_major = ?, version_minor = ?, version_patch = ?, version_prerelease = ?, version_build = ?,
+			image_repository = NULLIF(?, ''), registry = NULLIF(?, ''), correlation_status = ?, uncorrelated_since = ?
 		WHERE id = ?
 	`
 	
+	if err := cleanDeploymentPath(deployment); err != nil {
+		return err
+	}
 	deployment.UpdatedAt = time.Now()
-	_, err := d.db.Exec(query, deployment.CommitSHA, deployment.Tag, deployment.Path, deployment.UpdatedAt, deployment.ID)
+	args := []interface{}{deployment.CommitSHA, deployment.Tag, deployment.Path, deployment.UpdatedAt}
+	args = append(args, versionArgs(deployment.Version)...)
+	_, err := d.db.Exec(query, append(args, deployment.ImageRepository, deployment.Registry, deployment.CorrelationStatus, deployment.UncorrelatedSince, deployment.ID)...)
 	if err != nil {
 		return fmt.Errorf("failed to update deployment: %w", err)
 	}
@@ -139,26 +174,154 @@ func (d *DeploymentModel) Update(deployment *types.Deployment) error {
 	return nil
 }
 
-func (d *DeploymentModel) Upsert(deployment *types.Deployment) error {
+// cleanDeploymentPath canonicalizes the path of a deployment's kustomization file with vcs.CleanRepoPath
+func cleanDeploymentPath(deployment *types.Deployment) error {
+	deploymentPath, err := vcs.CleanRepoPath(deployment.Path)
+	if err != nil {
+		return fmt.Errorf("invalid deployment path: %w", err)
+	}
+	deployment.Path = deploymentPath
+	return nil
+}
+
+// Upsert creates or updates the deployment of a service to an environment and region.
+// It reports whether what's running changed, i.e. the deployment is new or its commit or tag moved.
+func (d *DeploymentModel) Upsert(deployment *types.Deployment) (bool, error) {
 	// Check if deployment already exists for this service, environment, and region
 	existingQuery := `
-		SELECT id FROM deployments
-		WHERE service_id = ? AND environment = ? AND region = ? AND namespace = ?
+		SELECT id, commit_sha, tag, correlation_status, uncorrelated_since FROM deployments
+		WHERE service_id = ? AND environment = ? AND region = ? AND COALESCE(namespace, '') = ?
 	`
 	
 	var existingID int64
-	err := d.db.QueryRow(existingQuery, deployment.ServiceID, deployment.Environment, deployment.Region, deployment.Namespace).Scan(&existingID)
+	var existingCommitSHA, existingTag, existingCorrelation string
+	var existingUncorrelatedSince *time.Time
+	err := d.db.QueryRow(existingQuery, deployment.ServiceID, deployment.Environment, deployment.Region, deployment.Namespace).Scan(&existingID, &existingCommitSHA, &existingTag, &existingCorrelation, &existingUncorrelatedSince)
 	
 	if err == sql.ErrNoRows {
 		// Create new deployment
-		return d.Create(deployment)
+		if err := d.Create(deployment); err != nil {
+			return false, err
+		}
+		return true, d.recordHistory(deployment)
 	} else if err != nil {
-		return fmt.Errorf("failed to check existing deployment: %w", err)
+		return false, fmt.Errorf("failed to check existing deployment: %w", err)
 	}
 	
+	// A tag that stays uncorrelated keeps the time it was first left uncorrelated, so its retries
+	// stop once the retry window passes, and stays abandoned once they did
+	if deployment.CorrelationStatus == types.CorrelationUncorrelated && existingTag == deployment.Tag &&
+		(existingCorrelation == types.CorrelationUncorrelated || existingCorrelation == types.CorrelationAbandoned) {
+		deployment.CorrelationStatus = existingCorrelation
+		if existingUncorrelatedSince != nil {
+			deployment.UncorrelatedSince = existingUncorrelatedSince
+		}
+	}
+
 	// Update existing deployment
 	deployment.ID = existingID
-	return d.Update(deployment)
+	if err := d.Update(deployment); err != nil {
+		return false, err
+	}
+
+	// Only record history when what's running actually changed
+	if existingCommitSHA != deployment.CommitSHA || existingTag != deployment.Tag {
+		return true, d.recordHistory(deployment)
+	}
+
+	return false, nil
+}
+
+// recordHistory appends an observation of a deployment's tag to the append-only history. An
+// observation inside a freeze window of the environment is flagged with it, and the window is set
+// on the deployment, unless the deployment is seeding. FirstObservation is set when the service had
+// no history in the environment and region yet.
+func (d *DeploymentModel) recordHistory(deployment *types.Deployment) error {
+	query := `
+		INSERT INTO deployment_history (service_id, kubernetes_repo_id, commit_sha, environment, region, namespace, tag, observed_at, freeze_window_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	observedAt := time.Now()
+	deployment.FreezeWindow = nil
+	if !deployment.Seeding {
+		windows, err := getFreezeWindows(d.db)
+		if err != nil {
+			return err
+		}
+		deployment.FreezeWindow = freeze.Active(windows, deployment.Environment, observedAt)
+	}
+
+	var seen bool
+	err := d.db.QueryRow(`
+		SELECT EXISTS(SELECT 1 FROM deployment_history WHERE service_id = ? AND environment = ? AND region = ?)
+	`, deployment.ServiceID, deployment.Environment, deployment.Region).Scan(&seen)
+	if err != nil {
+		return fmt.Errorf("failed to check deployment history: %w", err)
+	}
+	deployment.FirstObservation = !seen
+	var freezeWindowID *int64
+	if deployment.FreezeWindow != nil {
+		freezeWindowID = &deployment.FreezeWindow.ID
+	}
+
+	_, err = d.db.Exec(query, deployment.ServiceID, deployment.KubernetesRepoID, deployment.CommitSHA, deployment.Environment, deployment.Region, deployment.Namespace, deployment.Tag, observedAt, freezeWindowID)
+	if err != nil {
+		return fmt.Errorf("failed to record deployment history: %w", err)
+	}
+
+	return nil
+}
+
+// HasHistory reports whether any deployment in a kubernetes repository was recorded in the history
+func (d *DeploymentModel) HasHistory(kubernetesRepoID int64) (bool, error) {
+	var exists bool
+	err := d.db.QueryRow(`SELECT EXISTS(SELECT 1 FROM deployment_history WHERE kubernetes_repo_id = ?)`, kubernetesRepoID).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check deployment history: %w", err)
+	}
+	return exists, nil
+}
+
+// GetHistoryByServiceID returns the deployment observations for a service since the given time, oldest first
+func (d *DeploymentModel) GetHistoryByServiceID(serviceID int64, since time.Time) ([]*types.DeploymentHistoryEntry, error) {
+	query := `
+		SELECT id, service_id, kubernetes_repo_id, commit_sha, environment, region, namespace, tag, observed_at, freeze_window_id
+		FROM deployment_history
+		WHERE service_id = ? AND observed_at >= ?
+		ORDER BY observed_at ASC
+	`
+
+	rows, err := d.db.Query(query, serviceID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query deployment history: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*types.DeploymentHistoryEntry
+	for rows.Next() {
+		entry := &types.DeploymentHistoryEntry{}
+		var namespace sql.NullString
+		err := rows.Scan(
+			&entry.ID,
+			&entry.ServiceID,
+			&entry.KubernetesRepoID,
+			&entry.CommitSHA,
+			&entry.Environment,
+			&entry.Region,
+			&namespace,
+			&entry.Tag,
+			&entry.ObservedAt,
+			&entry.FreezeWindowID,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan deployment history: %w", err)
+		}
+		entry.Namespace = namespace.String
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
 }
 
 func (d *DeploymentModel) DeleteByServiceID(serviceID int64) error {
@@ -173,22 +336,34 @@ func (d *DeploymentModel) DeleteByServiceID(serviceID int64) error {
 }
 
 func (d *DeploymentModel) GetDeploymentOverview(serviceID int64) ([]*types.DeploymentOverview, error) {
+	return d.GetFilteredDeploymentOverview(serviceID, "", "")
+}
+
+// GetFilteredDeploymentOverview returns a service's deployments in an environment and region,
+// ordered like GetDeploymentOverview; an empty environment or region doesn't filter
+func (d *DeploymentModel) GetFilteredDeploymentOverview(serviceID int64, environment, region string) ([]*types.DeploymentOverview, error) {
 	query := `
 		SELECT 
+			d.id,
 			d.commit_sha,
 			d.environment,
 			d.region,
 			d.namespace,
 			d.tag,
+			COALESCE(d.image_repository, ''),
+			COALESCE(d.registry, ''),
+			d.actual_tag,
 			d.updated_at,
 			r.name as kubernetes_repo_name
 		FROM deployments d
 		JOIN repositories r ON d.kubernetes_repo_id = r.id
 		WHERE d.service_id = ?
+			AND (? = '' OR d.environment = ?)
+			AND (? = '' OR d.region = ?)
 		ORDER BY d.environment, d.region, d.namespace
 	`
 	
-	rows, err := d.db.Query(query, serviceID)
+	rows, err := d.db.Query(query, serviceID, environment, environment, region, region)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query deployment overview: %w", err)
 	}
@@ -197,19 +372,24 @@ func (d *DeploymentModel) GetDeploymentOverview(serviceID int64) ([]*types.Deplo
 	var deployments []*types.DeploymentOverview
 	for rows.Next() {
 		deployment := &types.DeploymentOverview{}
-		var namespace sql.NullString
+		var namespace, actualTag sql.NullString
 		err := rows.Scan(
+			&deployment.ID,
 			&deployment.CommitSHA,
 			&deployment.Environment,
 			&deployment.Region,
 			&namespace,
 			&deployment.Tag,
+			&deployment.ImageRepository,
+			&deployment.Registry,
+			&actualTag,
 			&deployment.UpdatedAt,
 			&deployment.KubernetesRepoName,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan deployment overview: %w", err)
 		}
+		deployment.ActualTag, deployment.Synced = reconcileTag(deployment.Tag, actualTag)
 		
 		// Handle NULL namespace
 		if namespace.Valid {
@@ -222,4 +402,306 @@ func (d *DeploymentModel) GetDeploymentOverview(serviceID int64) ([]*types.Deplo
 	}
 
 	return deployments, nil
-}
\ No newline at end of file
+}
+
+// SetActualTag records the tag the cluster actually runs for a deployment; an empty tag forgets
+// it, so the deployment counts as running its desired tag again
+func (d *DeploymentModel) SetActualTag(id int64, tag string) error {
+	actualTag := sql.NullString{String: tag, Valid: tag != ""}
+	result, err := d.db.Exec(`UPDATE deployments SET actual_tag = ? WHERE id = ?`, actualTag, id)
+	if err != nil {
+		return fmt.Errorf("failed to set actual tag: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return fmt.Errorf("deployment %d not found", id)
+	}
+	return nil
+}
+
+// GetUncorrelated returns the deployments of a kubernetes repository whose tag no monorepo commit
+// has matched yet, oldest first
+func (d *DeploymentModel) GetUncorrelated(kubernetesRepoID int64) ([]*types.Deployment, error) {
+	rows, err := d.db.Query(`
+		SELECT id, service_id, kubernetes_repo_id, commit_sha, environment, region, COALESCE(namespace, ''), tag, path, correlation_status, uncorrelated_since
+		FROM deployments
+		WHERE kubernetes_repo_id = ? AND correlation_status = ?
+		ORDER BY uncorrelated_since, id
+	`, kubernetesRepoID, types.CorrelationUncorrelated)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query uncorrelated deployments: %w", err)
+	}
+	defer rows.Close()
+
+	deployments := []*types.Deployment{}
+	for rows.Next() {
+		deployment := &types.Deployment{}
+		err := rows.Scan(&deployment.ID, &deployment.ServiceID, &deployment.KubernetesRepoID, &deployment.CommitSHA, &deployment.Environment,
+			&deployment.Region, &deployment.Namespace, &deployment.Tag, &deployment.Path, &deployment.CorrelationStatus, &deployment.UncorrelatedSince)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan uncorrelated deployment: %w", err)
+		}
+		deployments = append(deployments, deployment)
+	}
+	return deployments, rows.Err()
+}
+
+// CorrelateCommit sets the monorepo commit an uncorrelated deployment's tag was matched with later.
+// The history entries of the tag that recorded the fallback commit get it too, so lead times use it.
+// It reports false when the deployment is no longer uncorrelated, e.g. a rescan moved its tag.
+func (d *DeploymentModel) CorrelateCommit(id int64, commitSHA string) (bool, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var deployment types.Deployment
+	err = tx.QueryRow(`
+		SELECT service_id, commit_sha, environment, region, COALESCE(namespace, ''), tag
+		FROM deployments
+		WHERE id = ? AND correlation_status = ?
+	`, id, types.CorrelationUncorrelated).Scan(&deployment.ServiceID, &deployment.CommitSHA, &deployment.Environment,
+		&deployment.Region, &deployment.Namespace, &deployment.Tag)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get deployment %d: %w", id, err)
+	}
+
+	_, err = tx.Exec(`
+		UPDATE deployments
+		SET commit_sha = ?, correlation_status = ?, uncorrelated_since = NULL, updated_at = ?
+		WHERE id = ?
+	`, commitSHA, types.CorrelationCorrelated, time.Now(), id)
+	if err != nil {
+		return false, fmt.Errorf("failed to correlate deployment %d: %w", id, err)
+	}
+	_, err = tx.Exec(`
+		UPDATE deployment_history
+		SET commit_sha = ?
+		WHERE service_id = ? AND environment = ? AND region = ? AND COALESCE(namespace, '') = ? AND tag = ? AND commit_sha = ?
+	`, commitSHA, deployment.ServiceID, deployment.Environment, deployment.Region, deployment.Namespace, deployment.Tag, deployment.CommitSHA)
+	if err != nil {
+		return false, fmt.Errorf("failed to correlate deployment history of deployment %d: %w", id, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return true, nil
+}
+
+// AbandonCorrelation stops the correlation retries of an uncorrelated deployment; it keeps the
+// fallback commit until a rescan correlates its tag or the tag moves
+func (d *DeploymentModel) AbandonCorrelation(id int64) error {
+	_, err := d.db.Exec(`
+		UPDATE deployments SET correlation_status = ? WHERE id = ? AND correlation_status = ?
+	`, types.CorrelationAbandoned, id, types.CorrelationUncorrelated)
+	if err != nil {
+		return fmt.Errorf("failed to abandon correlation of deployment %d: %w", id, err)
+	}
+	return nil
+}
+
+// reconcileTag returns the actual tag of a deployment, its desired tag when none was recorded, and
+// whether the two match
+func reconcileTag(desired string, actual sql.NullString) (string, bool) {
+	if !actual.Valid {
+		return desired, true
+	}
+	return actual.String, actual.String == desired
+}
+
+// GetTargetCounts returns, for every service with deployments, the number of distinct
+// region/namespace targets in each environment
+func (d *DeploymentModel) GetTargetCounts() (map[int64]map[string]int, error) {
+	query := `
+		SELECT service_id, environment, COUNT(DISTINCT region || '/' || COALESCE(namespace, ''))
+		FROM deployments
+		GROUP BY service_id, environment
+	`
+
+	rows, err := d.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query deployment counts: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[int64]map[string]int)
+	for rows.Next() {
+		var serviceID int64
+		var environment string
+		var targets int
+		if err := rows.Scan(&serviceID, &environment, &targets); err != nil {
+			return nil, fmt.Errorf("failed to scan deployment count: %w", err)
+		}
+		if counts[serviceID] == nil {
+			counts[serviceID] = make(map[string]int)
+		}
+		counts[serviceID][environment] = targets
+	}
+
+	return counts, nil
+}
+
+// GetDimensions returns the distinct environments, regions and non-empty namespaces of all
+// deployments, each sorted alphabetically
+func (d *DeploymentModel) GetDimensions() (*types.DeploymentDimensions, error) {
+	dimensions := &types.DeploymentDimensions{}
+	for _, dimension := range []struct {
+		column string
+		values *[]string
+	}{
+		{"environment", &dimensions.Environments},
+		{"region", &dimensions.Regions},
+		{"namespace", &dimensions.Namespaces},
+	} {
+		values, err := d.distinctValues(dimension.column)
+		if err != nil {
+			return nil, err
+		}
+		*dimension.values = values
+	}
+	return dimensions, nil
+}
+
+// distinctValues returns the distinct non-empty values of a deployments column
+func (d *DeploymentModel) distinctValues(column string) ([]string, error) {
+	rows, err := d.db.Query(fmt.Sprintf(`
+		SELECT DISTINCT %[1]s FROM deployments
+		WHERE %[1]s IS NOT NULL AND %[1]s != ''
+		ORDER BY %[1]s
+	`, column))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query deployment %ss: %w", column, err)
+	}
+	defer rows.Close()
+
+	values := []string{}
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return nil, fmt.Errorf("failed to scan deployment %s: %w", column, err)
+		}
+		values = append(values, value)
+	}
+	return values, rows.Err()
+}
+
+// GetRegistryUsage returns the registries deployments pull their images from, by registry, with
+// each service's image repository and environments there. Deployments whose image isn't known yet
+// are left out.
+func (d *DeploymentModel) GetRegistryUsage() ([]*types.ImageRegistryUsage, error) {
+	query := `
+		SELECT d.registry, m.id, m.name, d.image_repository, GROUP_CONCAT(DISTINCT d.environment), COUNT(*)
+		FROM deployments d
+		JOIN microservices m ON m.id = d.service_id
+		WHERE d.registry IS NOT NULL
+		GROUP BY d.registry, m.id, d.image_repository
+		ORDER BY d.registry, m.name COLLATE NOCASE, d.image_repository
+	`
+
+	rows, err := d.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query registry usage: %w", err)
+	}
+	defer rows.Close()
+
+	registries := []*types.ImageRegistryUsage{}
+	for rows.Next() {
+		var registry, environments string
+		service := &types.RegistryServiceUsage{}
+		if err := rows.Scan(&registry, &service.ServiceID, &service.ServiceName, &service.ImageRepository, &environments, &service.Deployments); err != nil {
+			return nil, fmt.Errorf("failed to scan registry usage: %w", err)
+		}
+		service.Environments = strings.Split(environments, ",")
+		sort.Strings(service.Environments)
+
+		if len(registries) == 0 || registries[len(registries)-1].Registry != registry {
+			registries = append(registries, &types.ImageRegistryUsage{Registry: registry, Services: []*types.RegistryServiceUsage{}})
+		}
+		usage := registries[len(registries)-1]
+		usage.Services = append(usage.Services, service)
+		usage.Deployments += service.Deployments
+	}
+
+	return registries, nil
+}
+
+// UpdateVersions re-parses the semver components of every deployment's tag with parse, e.g.
+// after the tag prefixes changed. It returns how many deployments changed.
+func (d *DeploymentModel) UpdateVersions(parse func(tag string) *types.TagVersion) (int, error) {
+	rows, err := d.db.Query(`
+		SELECT id, tag, version_major, version_minor, version_patch, version_prerelease, version_build
+		FROM deployments
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query deployment tags: %w", err)
+	}
+
+	updates := make(map[int64]*types.TagVersion)
+	for rows.Next() {
+		var id int64
+		var tag string
+		var version scannedVersion
+		if err := rows.Scan(&id, &tag, &version.major, &version.minor, &version.patch, &version.prerelease, &version.build); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan deployment tag: %w", err)
+		}
+		parsed := parse(tag)
+		if !sameVersion(parsed, version.tagVersion()) {
+			updates[id] = parsed
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to read deployment tags: %w", err)
+	}
+
+	for id, version := range updates {
+		query := `
+			UPDATE deployments
+			SET version_major = ?, version_minor = ?, version_patch = ?, version_prerelease = ?, version_build = ?
+			WHERE id = ?
+		`
+		if _, err := d.db.Exec(query, append(versionArgs(version), id)...); err != nil {
+			return 0, fmt.Errorf("failed to update deployment version: %w", err)
+		}
+	}
+	return len(updates), nil
+}
+
+// scannedVersion reads the nullable semver columns of a deployment
+type scannedVersion struct {
+	major, minor, patch sql.NullInt64
+	prerelease, build   string
+}
+
+func (v scannedVersion) tagVersion() *types.TagVersion {
+	if !v.major.Valid || !v.minor.Valid || !v.patch.Valid {
+		return nil
+	}
+	return &types.TagVersion{
+		Major:      int(v.major.Int64),
+		Minor:      int(v.minor.Int64),
+		Patch:      int(v.patch.Int64),
+		Prerelease: v.prerelease,
+		Build:      v.build,
+	}
+}
+
+// versionArgs returns the semver column values of a version, NULL numbers for tags that aren't semver
+func versionArgs(version *types.TagVersion) []interface{} {
+	if version == nil {
+		return []interface{}{nil, nil, nil, "", ""}
+	}
+	return []interface{}{version.Major, version.Minor, version.Patch, version.Prerelease, version.Build}
+}
+
+func sameVersion(a, b *types.TagVersion) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,257 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"dev-dashboard/internal/models"
+	"dev-dashboard/internal/testsupport"
+	"dev-dashboard/pkg/types"
+)
+
+// newDeployment returns an unsaved deployment of the service to dev in us-east-1
+func newDeployment(serviceID, kubernetesRepoID int64, tag, commitSHA string) *types.Deployment {
+	return &types.Deployment{
+		ServiceID:        serviceID,
+		KubernetesRepoID: kubernetesRepoID,
+		CommitSHA:        commitSHA,
+		Environment:      "dev",
+		Region:           "us-east-1",
+		Namespace:        "default",
+		Tag:              tag,
+		Path:             "overlays/dev/us-east-1/default",
+	}
+}
+
+func deploymentFixtures(t *testing.T) (*models.DeploymentModel, *types.Microservice, *types.Repository) {
+	t.Helper()
+	db := testsupport.NewTestDB(t)
+	service := testsupport.Service(t, db, testsupport.Repository(t, db).ID)
+	return models.NewDeploymentModel(db), service, testsupport.KubernetesRepository(t, db)
+}
+
+func historyLength(t *testing.T, model *models.DeploymentModel, serviceID int64) int {
+	t.Helper()
+	history, err := model.GetHistoryByServiceID(serviceID, time.Time{})
+	if err != nil {
+		t.Fatalf("GetHistoryByServiceID: %v", err)
+	}
+	return len(history)
+}
+
+func TestDeploymentUpsertCreatesNewDeployment(t *testing.T) {
+	model, service, k8s := deploymentFixtures(t)
+
+	deployment := newDeployment(service.ID, k8s.ID, "v1.0.0", "aaa")
+	changed, err := model.Upsert(deployment)
+	if err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+	if !changed {
+		t.Error("a new deployment should be reported as changed")
+	}
+	if deployment.ID == 0 {
+		t.Error("the new deployment should get an ID")
+	}
+	if !deployment.FirstObservation {
+		t.Error("the first deployment to an environment should be its first observation")
+	}
+	if n := historyLength(t, model, service.ID); n != 1 {
+		t.Errorf("history has %d entries, want 1", n)
+	}
+}
+
+func TestDeploymentUpsertUnchangedKeepsHistory(t *testing.T) {
+	model, service, k8s := deploymentFixtures(t)
+
+	first := newDeployment(service.ID, k8s.ID, "v1.0.0", "aaa")
+	if _, err := model.Upsert(first); err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+	again := newDeployment(service.ID, k8s.ID, "v1.0.0", "aaa")
+	changed, err := model.Upsert(again)
+	if err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+	if changed {
+		t.Error("upserting the same tag and commit should not report a change")
+	}
+	if again.ID != first.ID {
+		t.Errorf("upsert created deployment %d instead of updating %d", again.ID, first.ID)
+	}
+	if n := historyLength(t, model, service.ID); n != 1 {
+		t.Errorf("history has %d entries, want 1", n)
+	}
+}
+
+func TestDeploymentUpsertNewTagUpdatesInPlace(t *testing.T) {
+	model, service, k8s := deploymentFixtures(t)
+
+	first := newDeployment(service.ID, k8s.ID, "v1.0.0", "aaa")
+	if _, err := model.Upsert(first); err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+	next := newDeployment(service.ID, k8s.ID, "v1.1.0", "bbb")
+	changed, err := model.Upsert(next)
+	if err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+	if !changed {
+		t.Error("a new tag should be reported as changed")
+	}
+	if next.ID != first.ID {
+		t.Errorf("upsert created deployment %d instead of updating %d", next.ID, first.ID)
+	}
+	if next.FirstObservation {
+		t.Error("a later tag in the same environment is not a first observation")
+	}
+
+	stored, err := model.GetByID(first.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if stored.Tag != "v1.1.0" || stored.CommitSHA != "bbb" {
+		t.Errorf("stored deployment runs %s at %s, want v1.1.0 at bbb", stored.Tag, stored.CommitSHA)
+	}
+	if n := historyLength(t, model, service.ID); n != 2 {
+		t.Errorf("history has %d entries, want 2", n)
+	}
+}
+
+func TestDeploymentUpsertSeparatesNamespaces(t *testing.T) {
+	model, service, k8s := deploymentFixtures(t)
+
+	first := newDeployment(service.ID, k8s.ID, "v1.0.0", "aaa")
+	second := newDeployment(service.ID, k8s.ID, "v1.0.0", "aaa")
+	second.Namespace = "canary"
+	for _, deployment := range []*types.Deployment{first, second} {
+		if _, err := model.Upsert(deployment); err != nil {
+			t.Fatalf("Upsert: %v", err)
+		}
+	}
+	if first.ID == second.ID {
+		t.Error("deployments to different namespaces should be separate rows")
+	}
+
+	deployments, err := model.GetByServiceID(service.ID)
+	if err != nil {
+		t.Fatalf("GetByServiceID: %v", err)
+	}
+	if len(deployments) != 2 {
+		t.Errorf("service has %d deployments, want 2", len(deployments))
+	}
+}
+
+func TestDeploymentUpsertKeepsUncorrelatedSince(t *testing.T) {
+	model, service, k8s := deploymentFixtures(t)
+
+	since := time.Now().Add(-48 * time.Hour).UTC().Truncate(time.Second)
+	first := newDeployment(service.ID, k8s.ID, "v1.0.0", "aaa")
+	first.CorrelationStatus = types.CorrelationUncorrelated
+	first.UncorrelatedSince = &since
+	if _, err := model.Upsert(first); err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+
+	now := time.Now()
+	again := newDeployment(service.ID, k8s.ID, "v1.0.0", "aaa")
+	again.CorrelationStatus = types.CorrelationUncorrelated
+	again.UncorrelatedSince = &now
+	if _, err := model.Upsert(again); err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+
+	stored, err := model.GetByID(first.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if stored.UncorrelatedSince == nil || !stored.UncorrelatedSince.Equal(since) {
+		t.Errorf("uncorrelated since %v, want the first time %v", stored.UncorrelatedSince, since)
+	}
+}
+
+func TestDeploymentUpsertFlagsFreezeViolations(t *testing.T) {
+	db := testsupport.NewTestDB(t)
+	service := testsupport.Service(t, db, testsupport.Repository(t, db).ID)
+	k8s := testsupport.KubernetesRepository(t, db)
+	window := testsupport.FreezeWindow(t, db, "dev")
+	model := models.NewDeploymentModel(db)
+
+	deployment := newDeployment(service.ID, k8s.ID, "v1.0.0", "aaa")
+	if _, err := model.Upsert(deployment); err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+	if deployment.FreezeWindow == nil || deployment.FreezeWindow.ID != window.ID {
+		t.Fatalf("got freeze window %v, want the active window %d", deployment.FreezeWindow, window.ID)
+	}
+
+	violations, err := models.NewFreezeWindowModel(db).GetViolations(time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("GetViolations: %v", err)
+	}
+	if len(violations) != 1 || violations[0].Entry.Tag != "v1.0.0" {
+		t.Errorf("got %d violations, want the v1.0.0 deploy", len(violations))
+	}
+}
+
+func TestDeploymentUpsertDoesNotFlagSeedingDeployments(t *testing.T) {
+	db := testsupport.NewTestDB(t)
+	service := testsupport.Service(t, db, testsupport.Repository(t, db).ID)
+	k8s := testsupport.KubernetesRepository(t, db)
+	testsupport.FreezeWindow(t, db, "dev")
+	model := models.NewDeploymentModel(db)
+
+	deployment := newDeployment(service.ID, k8s.ID, "v1.0.0", "aaa")
+	deployment.Seeding = true
+	changed, err := model.Upsert(deployment)
+	if err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+	if !changed || historyLength(t, model, service.ID) != 1 {
+		t.Error("a seeding deployment should still be recorded in the history")
+	}
+	if deployment.FreezeWindow != nil {
+		t.Errorf("a seeding deployment got freeze window %d", deployment.FreezeWindow.ID)
+	}
+
+	violations, err := models.NewFreezeWindowModel(db).GetViolations(time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("GetViolations: %v", err)
+	}
+	if len(violations) != 0 {
+		t.Errorf("got %d violations, want none for a deployment already running when first scanned", len(violations))
+	}
+}
+
+func TestDeploymentUpsertUpdatesADeploymentStoredWithoutANamespace(t *testing.T) {
+	db := testsupport.NewTestDB(t)
+	service := testsupport.Service(t, db, testsupport.Repository(t, db).ID)
+	k8s := testsupport.KubernetesRepository(t, db)
+	model := models.NewDeploymentModel(db)
+
+	// Deployments stored before namespaces were scanned have none
+	result, err := db.Exec(`
+		INSERT INTO deployments (service_id, kubernetes_repo_id, commit_sha, environment, region, namespace, tag, path)
+		VALUES (?, ?, 'aaa', 'dev', 'us-east-1', NULL, 'v1.0.0', 'overlays/dev/us-east-1')
+	`, service.ID, k8s.ID)
+	if err != nil {
+		t.Fatalf("failed to insert the deployment: %v", err)
+	}
+	id, _ := result.LastInsertId()
+
+	next := newDeployment(service.ID, k8s.ID, "v1.1.0", "bbb")
+	next.Namespace = ""
+	if _, err := model.Upsert(next); err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+	if next.ID != id {
+		t.Errorf("upsert created deployment %d instead of updating %d", next.ID, id)
+	}
+	deployments, err := model.GetByServiceID(service.ID)
+	if err != nil {
+		t.Fatalf("GetByServiceID: %v", err)
+	}
+	if len(deployments) != 1 || deployments[0].Tag != "v1.1.0" {
+		t.Errorf("got %d deployments, want the one without a namespace updated to v1.1.0", len(deployments))
+	}
+}
@@ -0,0 +1,252 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"dev-dashboard/pkg/types"
+)
+
+// DiscoveryChangeModel stores the service changes found by syncs of repositories in discovery
+// review mode until they're accepted, rejected or expire
+type DiscoveryChangeModel struct {
+	db *sql.DB
+}
+
+func NewDiscoveryChangeModel(db *sql.DB) *DiscoveryChangeModel {
+	return &DiscoveryChangeModel{db: db}
+}
+
+const discoveryChangeColumns = `id, repository_id, kind, status, service_id, name, path, old_name, old_path,
+	description, domain, has_readme, detected_at`
+
+func scanDiscoveryChange(row interface{ Scan(...interface{}) error }) (*types.DiscoveryChange, error) {
+	change := &types.DiscoveryChange{}
+	err := row.Scan(&change.ID, &change.RepositoryID, &change.Kind, &change.Status, &change.ServiceID, &change.Name,
+		&change.Path, &change.OldName, &change.OldPath, &change.Description, &change.Domain, &change.HasReadme,
+		&change.DetectedAt)
+	if err != nil {
+		return nil, err
+	}
+	return change, nil
+}
+
+// discoveryChangeKey identifies a change across syncs
+func discoveryChangeKey(change *types.DiscoveryChange) string {
+	return fmt.Sprintf("%s|%s|%s|%s|%s", change.Kind, change.Name, change.Path, change.OldName, change.OldPath)
+}
+
+// Sync replaces the stored changes of a repository with the ones found by its latest sync. Changes
+// found before keep their status and detection time; changes discovery no longer reports are
+// dropped. It returns how many of the changes are new.
+func (m *DiscoveryChangeModel) Sync(repositoryID int64, changes []types.DiscoveryChange) (int, error) {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	existing, err := queryDiscoveryChanges(tx, `WHERE repository_id = ?`, repositoryID)
+	if err != nil {
+		return 0, err
+	}
+	stale := make(map[string]*types.DiscoveryChange)
+	for _, change := range existing {
+		stale[discoveryChangeKey(change)] = change
+	}
+
+	added := 0
+	now := time.Now()
+	for i := range changes {
+		change := &changes[i]
+		key := discoveryChangeKey(change)
+		if previous, ok := stale[key]; ok {
+			delete(stale, key)
+			_, err := tx.Exec(`UPDATE pending_discovery_changes SET service_id = ?, description = ?, domain = ?, has_readme = ? WHERE id = ?`,
+				change.ServiceID, change.Description, change.Domain, change.HasReadme, previous.ID)
+			if err != nil {
+				return 0, fmt.Errorf("failed to update discovery change: %w", err)
+			}
+			continue
+		}
+
+		_, err := tx.Exec(`
+			INSERT INTO pending_discovery_changes
+			(repository_id, kind, status, service_id, name, path, old_name, old_path, description, domain, has_readme, detected_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`, repositoryID, change.Kind, types.DiscoveryChangePending, change.ServiceID, change.Name, change.Path,
+			change.OldName, change.OldPath, change.Description, change.Domain, change.HasReadme, now)
+		if err != nil {
+			return 0, fmt.Errorf("failed to insert discovery change: %w", err)
+		}
+		added++
+	}
+
+	for _, change := range stale {
+		if _, err := tx.Exec(`DELETE FROM pending_discovery_changes WHERE id = ?`, change.ID); err != nil {
+			return 0, fmt.Errorf("failed to delete discovery change: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return added, nil
+}
+
+// GetByRepository returns the stored changes of a repository, oldest first
+func (m *DiscoveryChangeModel) GetByRepository(repositoryID int64) ([]*types.DiscoveryChange, error) {
+	return queryDiscoveryChanges(m.db, `WHERE repository_id = ?`, repositoryID)
+}
+
+// GetPendingBefore returns a repository's pending changes detected before the given time
+func (m *DiscoveryChangeModel) GetPendingBefore(repositoryID int64, before time.Time) ([]*types.DiscoveryChange, error) {
+	return queryDiscoveryChanges(m.db, `WHERE repository_id = ? AND status = ? AND detected_at < ?`,
+		repositoryID, types.DiscoveryChangePending, before)
+}
+
+// CountPending returns the number of pending changes of each repository that has any
+func (m *DiscoveryChangeModel) CountPending() (map[int64]int, error) {
+	rows, err := m.db.Query(`SELECT repository_id, COUNT(*) FROM pending_discovery_changes WHERE status = ? GROUP BY repository_id`,
+		types.DiscoveryChangePending)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count discovery changes: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[int64]int)
+	for rows.Next() {
+		var repositoryID int64
+		var count int
+		if err := rows.Scan(&repositoryID, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan discovery change count: %w", err)
+		}
+		counts[repositoryID] = count
+	}
+	return counts, rows.Err()
+}
+
+// rowsQuerier is a *sql.DB or *sql.Tx
+type rowsQuerier interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func queryDiscoveryChanges(q rowsQuerier, where string, args ...interface{}) ([]*types.DiscoveryChange, error) {
+	rows, err := q.Query(`SELECT `+discoveryChangeColumns+` FROM pending_discovery_changes `+where+` ORDER BY detected_at, id`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query discovery changes: %w", err)
+	}
+	defer rows.Close()
+
+	changes := []*types.DiscoveryChange{}
+	for rows.Next() {
+		change, err := scanDiscoveryChange(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan discovery change: %w", err)
+		}
+		changes = append(changes, change)
+	}
+	return changes, rows.Err()
+}
+
+// ExpirePendingBefore marks a repository's pending changes detected before the given time as expired
+func (m *DiscoveryChangeModel) ExpirePendingBefore(repositoryID int64, before time.Time) (int64, error) {
+	result, err := m.db.Exec(`UPDATE pending_discovery_changes SET status = ? WHERE repository_id = ? AND status = ? AND detected_at < ?`,
+		types.DiscoveryChangeExpired, repositoryID, types.DiscoveryChangePending, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire discovery changes: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// DeleteByRepository drops the stored changes of a repository
+func (m *DiscoveryChangeModel) DeleteByRepository(repositoryID int64) error {
+	if _, err := m.db.Exec(`DELETE FROM pending_discovery_changes WHERE repository_id = ?`, repositoryID); err != nil {
+		return fmt.Errorf("failed to delete discovery changes: %w", err)
+	}
+	return nil
+}
+
+// Apply accepts or rejects changes of a repository in one transaction. Accepted changes are
+// applied to its services and dropped; rejected ones are kept as rejected so later syncs don't
+// raise them again. It reports whether any service changed.
+func (m *DiscoveryChangeModel) Apply(repositoryID int64, decisions []types.DiscoveryChangeDecision) (bool, error) {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	changed := false
+	addedOrRenamed := false
+	now := time.Now()
+	for _, decision := range decisions {
+		change, err := scanDiscoveryChange(tx.QueryRow(`SELECT `+discoveryChangeColumns+` FROM pending_discovery_changes WHERE id = ? AND repository_id = ?`,
+			decision.ID, repositoryID))
+		if err == sql.ErrNoRows {
+			return false, fmt.Errorf("discovery change %d not found", decision.ID)
+		}
+		if err != nil {
+			return false, fmt.Errorf("failed to get discovery change: %w", err)
+		}
+
+		if !decision.Accept {
+			if _, err := tx.Exec(`UPDATE pending_discovery_changes SET status = ? WHERE id = ?`, types.DiscoveryChangeRejected, change.ID); err != nil {
+				return false, fmt.Errorf("failed to reject discovery change: %w", err)
+			}
+			continue
+		}
+
+		if err := applyDiscoveryChange(tx, change, now); err != nil {
+			return false, err
+		}
+		if _, err := tx.Exec(`DELETE FROM pending_discovery_changes WHERE id = ?`, change.ID); err != nil {
+			return false, fmt.Errorf("failed to delete discovery change: %w", err)
+		}
+		changed = true
+		addedOrRenamed = addedOrRenamed || change.Kind != types.DiscoveryRemove
+	}
+
+	if addedOrRenamed {
+		if err := forgetScanTrees(tx); err != nil {
+			return false, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return changed, nil
+}
+
+func applyDiscoveryChange(tx *sql.Tx, change *types.DiscoveryChange, now time.Time) error {
+	var err error
+	switch change.Kind {
+	case types.DiscoveryAdd:
+		_, err = tx.Exec(`INSERT INTO microservices (repository_id, name, path, description, domain, has_readme, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			change.RepositoryID, change.Name, change.Path, change.Description, change.Domain, change.HasReadme, now, now)
+	case types.DiscoveryRemove:
+		if change.ServiceID == nil {
+			return nil
+		}
+		_, err = tx.Exec(`DELETE FROM microservices WHERE id = ? AND repository_id = ?`, *change.ServiceID, change.RepositoryID)
+	case types.DiscoveryRename:
+		if change.ServiceID == nil {
+			return fmt.Errorf("service of renamed %s no longer exists", change.OldName)
+		}
+		// Descriptions edited through a catalog import win over discovered ones
+		_, err = tx.Exec(`
+			UPDATE microservices
+			SET name = ?, path = ?, description = CASE WHEN description_edited THEN description ELSE ? END,
+				domain = ?, has_readme = ?, updated_at = ?
+			WHERE id = ? AND repository_id = ?
+		`, change.Name, change.Path, change.Description, change.Domain, change.HasReadme, now, *change.ServiceID, change.RepositoryID)
+	default:
+		return fmt.Errorf("unknown discovery change kind %q", change.Kind)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to apply %s of service %s: %w", change.Kind, change.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package models_test
+
+import (
+	"testing"
+
+	"dev-dashboard/internal/models"
+	"dev-dashboard/internal/testsupport"
+	"dev-dashboard/pkg/types"
+)
+
+func TestApplyDiscoveryChangesForgetsScanTreesOfAddedServices(t *testing.T) {
+	db := testsupport.NewTestDB(t)
+	changes := models.NewDiscoveryChangeModel(db)
+	repositories := models.NewRepositoryModel(db)
+	repo := testsupport.Repository(t, db)
+	k8s := testsupport.KubernetesRepository(t, db)
+	removed := testsupport.Service(t, db, repo.ID)
+
+	if _, err := changes.Sync(repo.ID, []types.DiscoveryChange{
+		{RepositoryID: repo.ID, Kind: types.DiscoveryRemove, ServiceID: &removed.ID, Name: removed.Name, Path: removed.Path},
+		{RepositoryID: repo.ID, Kind: types.DiscoveryAdd, Name: "worker", Path: "services/worker"},
+	}); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	pending, err := changes.GetByRepository(repo.ID)
+	if err != nil {
+		t.Fatalf("GetByRepository: %v", err)
+	}
+
+	// A removal leaves the scan's matches as they were, while an added service may be deployed by
+	// kustomizations the last scan skipped
+	for _, step := range []struct {
+		kind types.DiscoveryChangeKind
+		want string
+	}{{types.DiscoveryRemove, "abc123"}, {types.DiscoveryAdd, ""}} {
+		if err := repositories.UpdateScanTreeSHA(k8s.ID, "abc123"); err != nil {
+			t.Fatalf("UpdateScanTreeSHA: %v", err)
+		}
+		for _, change := range pending {
+			if change.Kind != step.kind {
+				continue
+			}
+			if _, err := changes.Apply(repo.ID, []types.DiscoveryChangeDecision{{ID: change.ID, Accept: true}}); err != nil {
+				t.Fatalf("Apply: %v", err)
+			}
+		}
+		sha, err := repositories.GetScanTreeSHA(k8s.ID)
+		if err != nil {
+			t.Fatalf("GetScanTreeSHA: %v", err)
+		}
+		if sha != step.want {
+			t.Errorf("got scan tree SHA %q after applying a %s, want %q", sha, step.kind, step.want)
+		}
+	}
+}
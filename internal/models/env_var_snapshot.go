@@ -0,0 +1,79 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"dev-dashboard/pkg/types"
+)
+
+// EnvVarSnapshotModel stores the environment variables of each service's Deployment per
+// environment and region
+type EnvVarSnapshotModel struct {
+	db *sql.DB
+}
+
+func NewEnvVarSnapshotModel(db *sql.DB) *EnvVarSnapshotModel {
+	return &EnvVarSnapshotModel{db: db}
+}
+
+// Upsert stores a snapshot unless the stored one has the same fingerprint, the manifests it was
+// read from being unchanged. It reports whether the snapshot was stored.
+func (m *EnvVarSnapshotModel) Upsert(snapshot *types.EnvVarSnapshot) (bool, error) {
+	variables := snapshot.Variables
+	if variables == nil {
+		variables = []types.EnvVar{}
+	}
+	data, err := json.Marshal(variables)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode environment variables: %w", err)
+	}
+
+	result, err := m.db.Exec(`
+		INSERT INTO env_var_snapshots (service_id, environment, region, path, fingerprint, variables, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(service_id, environment, region) DO UPDATE SET
+			path = excluded.path, fingerprint = excluded.fingerprint, variables = excluded.variables,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE env_var_snapshots.fingerprint != excluded.fingerprint
+	`, snapshot.ServiceID, snapshot.Environment, snapshot.Region, snapshot.Path, snapshot.Fingerprint, string(data))
+	if err != nil {
+		return false, fmt.Errorf("failed to store environment variable snapshot: %w", err)
+	}
+	stored, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to store environment variable snapshot: %w", err)
+	}
+	return stored > 0, nil
+}
+
+// GetByServiceID returns the snapshots of a service, ordered by environment and region
+func (m *EnvVarSnapshotModel) GetByServiceID(serviceID int64) ([]*types.EnvVarSnapshot, error) {
+	rows, err := m.db.Query(`
+		SELECT service_id, environment, region, path, fingerprint, variables, updated_at
+		FROM env_var_snapshots
+		WHERE service_id = ?
+		ORDER BY environment, region
+	`, serviceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get environment variable snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	var snapshots []*types.EnvVarSnapshot
+	for rows.Next() {
+		snapshot := &types.EnvVarSnapshot{}
+		var variables string
+		err := rows.Scan(&snapshot.ServiceID, &snapshot.Environment, &snapshot.Region, &snapshot.Path,
+			&snapshot.Fingerprint, &variables, &snapshot.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan environment variable snapshot: %w", err)
+		}
+		if err := json.Unmarshal([]byte(variables), &snapshot.Variables); err != nil {
+			return nil, fmt.Errorf("failed to decode environment variables: %w", err)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, rows.Err()
+}
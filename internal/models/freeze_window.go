@@ -0,0 +1,129 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"dev-dashboard/pkg/types"
+)
+
+// FreezeWindowModel stores change freeze windows. Deployment history entries are checked against
+// them when DeploymentModel records them.
+type FreezeWindowModel struct {
+	db *sql.DB
+}
+
+func NewFreezeWindowModel(db *sql.DB) *FreezeWindowModel {
+	return &FreezeWindowModel{db: db}
+}
+
+// Create stores a window and sets its ID and timestamps
+func (m *FreezeWindowModel) Create(window *types.FreezeWindow) error {
+	now := time.Now()
+	result, err := m.db.Exec(`
+		INSERT INTO freeze_windows (environment_pattern, starts_at, ends_at, recurrence, reason, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, window.EnvironmentPattern, window.StartsAt, window.EndsAt, window.Recurrence, window.Reason, now, now)
+	if err != nil {
+		return fmt.Errorf("failed to create freeze window: %w", err)
+	}
+	if window.ID, err = result.LastInsertId(); err != nil {
+		return fmt.Errorf("failed to get freeze window ID: %w", err)
+	}
+	window.CreatedAt, window.UpdatedAt = now, now
+	return nil
+}
+
+// Update changes a window. Entries recorded before keep the window they fell into.
+func (m *FreezeWindowModel) Update(window *types.FreezeWindow) error {
+	window.UpdatedAt = time.Now()
+	result, err := m.db.Exec(`
+		UPDATE freeze_windows
+		SET environment_pattern = ?, starts_at = ?, ends_at = ?, recurrence = ?, reason = ?, updated_at = ?
+		WHERE id = ?
+	`, window.EnvironmentPattern, window.StartsAt, window.EndsAt, window.Recurrence, window.Reason, window.UpdatedAt, window.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update freeze window: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("freeze window %d not found", window.ID)
+	}
+	return nil
+}
+
+// Delete removes a window; the history entries that fell into it are no longer flagged
+func (m *FreezeWindowModel) Delete(id int64) error {
+	result, err := m.db.Exec(`DELETE FROM freeze_windows WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete freeze window: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("freeze window %d not found", id)
+	}
+	return nil
+}
+
+// GetAll returns every window, oldest first
+func (m *FreezeWindowModel) GetAll() ([]*types.FreezeWindow, error) {
+	return getFreezeWindows(m.db)
+}
+
+func getFreezeWindows(db *sql.DB) ([]*types.FreezeWindow, error) {
+	rows, err := db.Query(`
+		SELECT id, environment_pattern, starts_at, ends_at, recurrence, reason, created_at, updated_at
+		FROM freeze_windows
+		ORDER BY id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query freeze windows: %w", err)
+	}
+	defer rows.Close()
+
+	windows := []*types.FreezeWindow{}
+	for rows.Next() {
+		window := &types.FreezeWindow{}
+		err := rows.Scan(&window.ID, &window.EnvironmentPattern, &window.StartsAt, &window.EndsAt, &window.Recurrence, &window.Reason,
+			&window.CreatedAt, &window.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan freeze window: %w", err)
+		}
+		windows = append(windows, window)
+	}
+	return windows, rows.Err()
+}
+
+// GetViolations returns the deployment history entries recorded inside a freeze window since a
+// time, newest first
+func (m *FreezeWindowModel) GetViolations(since time.Time) ([]*types.FreezeViolation, error) {
+	rows, err := m.db.Query(`
+		SELECT h.id, h.service_id, h.kubernetes_repo_id, h.commit_sha, h.environment, h.region, COALESCE(h.namespace, ''), h.tag, h.observed_at,
+			s.name, f.id, f.environment_pattern, f.starts_at, f.ends_at, f.recurrence, f.reason, f.created_at, f.updated_at
+		FROM deployment_history h
+		JOIN freeze_windows f ON f.id = h.freeze_window_id
+		JOIN microservices s ON s.id = h.service_id
+		WHERE h.observed_at >= ?
+		ORDER BY h.observed_at DESC, h.id DESC
+	`, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query freeze violations: %w", err)
+	}
+	defer rows.Close()
+
+	violations := []*types.FreezeViolation{}
+	for rows.Next() {
+		entry := &types.DeploymentHistoryEntry{}
+		window := &types.FreezeWindow{}
+		violation := &types.FreezeViolation{Entry: entry, FreezeWindow: window}
+		err := rows.Scan(&entry.ID, &entry.ServiceID, &entry.KubernetesRepoID, &entry.CommitSHA, &entry.Environment, &entry.Region,
+			&entry.Namespace, &entry.Tag, &entry.ObservedAt, &violation.ServiceName,
+			&window.ID, &window.EnvironmentPattern, &window.StartsAt, &window.EndsAt, &window.Recurrence, &window.Reason,
+			&window.CreatedAt, &window.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan freeze violation: %w", err)
+		}
+		entry.FreezeWindowID = &window.ID
+		violations = append(violations, violation)
+	}
+	return violations, rows.Err()
+}
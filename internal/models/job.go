@@ -0,0 +1,133 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"dev-dashboard/pkg/types"
+)
+
+// JobModel stores background jobs with their progress and outcome
+type JobModel struct {
+	db *sql.DB
+}
+
+func NewJobModel(db *sql.DB) *JobModel {
+	return &JobModel{db: db}
+}
+
+const jobColumns = `id, kind, params, status, progress, COALESCE(message, ''), COALESCE(result, ''),
+	COALESCE(file_path, ''), COALESCE(error, ''), created_at, finished_at, acknowledged_at`
+
+func scanJob(row interface{ Scan(...interface{}) error }) (*types.Job, error) {
+	job := &types.Job{}
+	err := row.Scan(&job.ID, &job.Kind, &job.Params, &job.Status, &job.Progress, &job.Message, &job.Result,
+		&job.FilePath, &job.Error, &job.CreatedAt, &job.FinishedAt, &job.AcknowledgedAt)
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// Create stores a running job and sets its ID and creation time
+func (m *JobModel) Create(job *types.Job) error {
+	job.Status = types.JobRunning
+	job.CreatedAt = time.Now()
+	result, err := m.db.Exec(`INSERT INTO jobs (kind, params, status, created_at) VALUES (?, ?, ?, ?)`,
+		job.Kind, job.Params, job.Status, job.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create job: %w", err)
+	}
+	job.ID, err = result.LastInsertId()
+	return err
+}
+
+// UpdateProgress sets the progress percentage and message of a running job
+func (m *JobModel) UpdateProgress(id int64, progress int, message string) error {
+	_, err := m.db.Exec(`UPDATE jobs SET progress = ?, message = ? WHERE id = ? AND status = ?`,
+		progress, message, id, types.JobRunning)
+	if err != nil {
+		return fmt.Errorf("failed to update job progress: %w", err)
+	}
+	return nil
+}
+
+// Finish records the outcome of a running job
+func (m *JobModel) Finish(id int64, status types.JobStatus, result, filePath, errMsg string) error {
+	query := `
+		UPDATE jobs
+		SET status = ?, progress = CASE WHEN ? = 'succeeded' THEN 100 ELSE progress END,
+			result = NULLIF(?, ''), file_path = NULLIF(?, ''), error = NULLIF(?, ''), finished_at = ?
+		WHERE id = ? AND status = ?
+	`
+	_, err := m.db.Exec(query, status, status, result, filePath, errMsg, time.Now(), id, types.JobRunning)
+	if err != nil {
+		return fmt.Errorf("failed to finish job: %w", err)
+	}
+	return nil
+}
+
+// GetByID returns a job
+func (m *JobModel) GetByID(id int64) (*types.Job, error) {
+	job, err := scanJob(m.db.QueryRow(`SELECT `+jobColumns+` FROM jobs WHERE id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("job %d not found", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job: %w", err)
+	}
+	return job, nil
+}
+
+// GetUnacknowledged returns the running jobs and the finished ones that haven't been acknowledged,
+// newest first
+func (m *JobModel) GetUnacknowledged() ([]*types.Job, error) {
+	rows, err := m.db.Query(`SELECT ` + jobColumns + ` FROM jobs WHERE acknowledged_at IS NULL ORDER BY created_at DESC, id DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query jobs: %w", err)
+	}
+	defer rows.Close()
+
+	jobs := []*types.Job{}
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan job: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, rows.Err()
+}
+
+// Acknowledge marks a finished job as seen, which removes it from GetUnacknowledged
+func (m *JobModel) Acknowledge(id int64) error {
+	result, err := m.db.Exec(`UPDATE jobs SET acknowledged_at = ? WHERE id = ? AND status != ?`,
+		time.Now(), id, types.JobRunning)
+	if err != nil {
+		return fmt.Errorf("failed to acknowledge job: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("job %d not found or still running", id)
+	}
+	return nil
+}
+
+// FailInterrupted marks the jobs left running by a previous run of the app as failed
+func (m *JobModel) FailInterrupted() (int64, error) {
+	result, err := m.db.Exec(`UPDATE jobs SET status = ?, error = ?, finished_at = ? WHERE status = ?`,
+		types.JobFailed, "interrupted because the app quit", time.Now(), types.JobRunning)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fail interrupted jobs: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// DeleteAcknowledgedBefore removes the jobs acknowledged before the given time
+func (m *JobModel) DeleteAcknowledgedBefore(before time.Time) (int64, error) {
+	result, err := m.db.Exec(`DELETE FROM jobs WHERE acknowledged_at < ?`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete acknowledged jobs: %w", err)
+	}
+	return result.RowsAffected()
+}
@@ -3,8 +3,10 @@ package models
 import (
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
+	"dev-dashboard/internal/vcs"
 	"dev-dashboard/pkg/types"
 )
 
@@ -18,14 +20,17 @@ func NewMicroserviceModel(db *sql.DB) *MicroserviceModel {
 
 func (m *MicroserviceModel) Create(service *types.Microservice) error {
 	query := `
-		INSERT INTO microservices (repository_id, name, path, description, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?)
+		INSERT INTO microservices (repository_id, name, path, description, domain, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
 	`
+	if err := cleanServicePath(service); err != nil {
+		return err
+	}
 	now := time.Now()
 	service.CreatedAt = now
 	service.UpdatedAt = now
 
-	result, err := m.db.Exec(query, service.RepositoryID, service.Name, service.Path, service.Description, service.CreatedAt, service.UpdatedAt)
+	result, err := m.db.Exec(query, service.RepositoryID, service.Name, service.Path, service.Description, service.Domain, service.CreatedAt, service.UpdatedAt)
 	if err != nil {
 		return fmt.Errorf("failed to create microservice: %w", err)
 	}
@@ -39,15 +44,27 @@ func (m *MicroserviceModel) Create(service *types.Microservice) error {
 	return nil
 }
 
-func (m *MicroserviceModel) GetByRepositoryID(repositoryID int64) ([]*types.Microservice, error) {
+// cleanServicePath canonicalizes a service's path with vcs.CleanRepoPath, so paths discovered or
+// entered in different forms match each other and the file paths GitHub reports
+func cleanServicePath(service *types.Microservice) error {
+	servicePath, err := vcs.CleanRepoPath(service.Path)
+	if err != nil {
+		return fmt.Errorf("invalid path of service %s: %w", service.Name, err)
+	}
+	service.Path = servicePath
+	return nil
+}
+
+// GetByRepositoryID returns the services of a repository, leaving out hidden ones unless includeHidden is set
+func (m *MicroserviceModel) GetByRepositoryID(repositoryID int64, includeHidden bool) ([]*types.Microservice, error) {
 	query := `
-		SELECT id, repository_id, name, path, description, created_at, updated_at
+		SELECT id, repository_id, name, path, description, domain, is_hidden, primary_environment, owner, has_readme, image_name, suggested_image_name, created_at, updated_at
 		FROM microservices
-		WHERE repository_id = ?
+		WHERE repository_id = ? AND (? OR is_hidden = 0)
 		ORDER BY name
 	`
 	
-	rows, err := m.db.Query(query, repositoryID)
+	rows, err := m.db.Query(query, repositoryID, includeHidden)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query microservices: %w", err)
 	}
@@ -62,21 +79,81 @@ func (m *MicroserviceModel) GetByRepositoryID(repositoryID int64) ([]*types.Micr
 			&service.Name,
 			&service.Path,
 			&service.Description,
+			&service.Domain,
+			&service.IsHidden,
+			&service.PrimaryEnvironment,
+			&service.Owner,
+			&service.HasReadme,
+			&service.ImageName,
+			&service.SuggestedImageName,
+			&service.CreatedAt,
+			&service.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan microservice: %w", err)
+		}
+		services = append(services, service)
+	}
+
+	return services, nil
+}
+
+// GetAllWithRepository returns the services of every monorepo with their repository in one query,
+// ordered like the repositories in RepositoryModel.GetAll and by name within each. Hidden services
+// are only included when includeHidden is set.
+func (m *MicroserviceModel) GetAllWithRepository(includeHidden bool) ([]*types.MicroserviceWithRepository, error) {
+	query := `
+		SELECT m.id, m.repository_id, m.name, m.path, m.description, m.domain, m.is_hidden, m.primary_environment, m.owner, m.has_readme, m.image_name, m.suggested_image_name, m.created_at, m.updated_at,
+			r.name, r.url, r.type
+		FROM microservices m
+		JOIN repositories r ON r.id = m.repository_id
+		WHERE r.type = ? AND (? OR m.is_hidden = 0)
+		ORDER BY r.is_favorite DESC, r.created_at DESC, m.name
+	`
+
+	rows, err := m.db.Query(query, types.MonorepoType, includeHidden)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query microservices: %w", err)
+	}
+	defer rows.Close()
+
+	services := []*types.MicroserviceWithRepository{}
+	for rows.Next() {
+		service := &types.MicroserviceWithRepository{}
+		err := rows.Scan(
+			&service.ID,
+			&service.RepositoryID,
+			&service.Name,
+			&service.Path,
+			&service.Description,
+			&service.Domain,
+			&service.IsHidden,
+			&service.PrimaryEnvironment,
+			&service.Owner,
+			&service.HasReadme,
+			&service.ImageName,
+			&service.SuggestedImageName,
 			&service.CreatedAt,
 			&service.UpdatedAt,
+			&service.RepositoryName,
+			&service.RepositoryURL,
+			&service.RepositoryType,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan microservice: %w", err)
 		}
 		services = append(services, service)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read microservices: %w", err)
+	}
 
 	return services, nil
 }
 
 func (m *MicroserviceModel) GetByID(id int64) (*types.Microservice, error) {
 	query := `
-		SELECT id, repository_id, name, path, description, created_at, updated_at
+		SELECT id, repository_id, name, path, description, domain, is_hidden, primary_environment, owner, has_readme, image_name, suggested_image_name, created_at, updated_at
 		FROM microservices
 		WHERE id = ?
 	`
@@ -88,6 +165,13 @@ func (m *MicroserviceModel) GetByID(id int64) (*types.Microservice, error) {
 		&service.Name,
 		&service.Path,
 		&service.Description,
+		&service.Domain,
+		&service.IsHidden,
+		&service.PrimaryEnvironment,
+		&service.Owner,
+		&service.HasReadme,
+		&service.ImageName,
+		&service.SuggestedImageName,
 		&service.CreatedAt,
 		&service.UpdatedAt,
 	)
@@ -101,12 +185,15 @@ func (m *MicroserviceModel) GetByID(id int64) (*types.Microservice, error) {
 func (m *MicroserviceModel) Update(service *types.Microservice) error {
 	query := `
 		UPDATE microservices
-		SET name = ?, path = ?, description = ?, updated_at = ?
+		SET name = ?, path = ?, description = ?, domain = ?, updated_at = ?
 		WHERE id = ?
 	`
 	
+	if err := cleanServicePath(service); err != nil {
+		return err
+	}
 	service.UpdatedAt = time.Now()
-	_, err := m.db.Exec(query, service.Name, service.Path, service.Description, service.UpdatedAt, service.ID)
+	_, err := m.db.Exec(query, service.Name, service.Path, service.Description, service.Domain, service.UpdatedAt, service.ID)
 	if err != nil {
 		return fmt.Errorf("failed to update microservice: %w", err)
 	}
@@ -114,6 +201,135 @@ func (m *MicroserviceModel) Update(service *types.Microservice) error {
 	return nil
 }
 
+// SetHidden hides or unhides a service; hidden services survive rediscovery but are left out of default listings
+func (m *MicroserviceModel) SetHidden(id int64, hidden bool) error {
+	query := `UPDATE microservices SET is_hidden = ?, updated_at = ? WHERE id = ?`
+	
+	result, err := m.db.Exec(query, hidden, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to update microservice visibility: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("microservice with ID %d not found", id)
+	}
+
+	return nil
+}
+
+// SetPrimaryEnvironment sets the environment that is live for a service; empty falls back to the
+// global default
+func (m *MicroserviceModel) SetPrimaryEnvironment(id int64, environment string) error {
+	query := `UPDATE microservices SET primary_environment = ?, updated_at = ? WHERE id = ?`
+	
+	result, err := m.db.Exec(query, environment, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to update microservice primary environment: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("microservice with ID %d not found", id)
+	}
+
+	return nil
+}
+
+// SetOwner records the team or person that owns a service
+func (m *MicroserviceModel) SetOwner(id int64, owner string) error {
+	query := `UPDATE microservices SET owner = ?, updated_at = ? WHERE id = ?`
+	
+	result, err := m.db.Exec(query, owner, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to update microservice owner: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("microservice with ID %d not found", id)
+	}
+
+	return nil
+}
+
+// SetImageName sets the image a service's kustomizations deploy it as, and drops the suggested one
+func (m *MicroserviceModel) SetImageName(id int64, imageName string) error {
+	query := `UPDATE microservices SET image_name = ?, suggested_image_name = '', updated_at = ? WHERE id = ?`
+	
+	result, err := m.db.Exec(query, imageName, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to update microservice image name: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("microservice with ID %d not found", id)
+	}
+
+	return nil
+}
+
+// SetSuggestedImageName records the image the deployment scan suggests for a service. It reports
+// whether the suggestion changed.
+func (m *MicroserviceModel) SetSuggestedImageName(id int64, imageName string) (bool, error) {
+	query := `UPDATE microservices SET suggested_image_name = ? WHERE id = ? AND suggested_image_name != ?`
+	
+	result, err := m.db.Exec(query, imageName, id, imageName)
+	if err != nil {
+		return false, fmt.Errorf("failed to update microservice suggested image name: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+// GetSensitivePaths returns the path patterns, relative to the service directory, whose changes in
+// a pull request are flagged
+func (m *MicroserviceModel) GetSensitivePaths(id int64) ([]string, error) {
+	var patterns string
+	err := m.db.QueryRow(`SELECT sensitive_paths FROM microservices WHERE id = ?`, id).Scan(&patterns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get microservice sensitive paths: %w", err)
+	}
+	return splitLines(patterns), nil
+}
+
+// SetSensitivePaths replaces the service's sensitive path patterns
+func (m *MicroserviceModel) SetSensitivePaths(id int64, patterns []string) error {
+	query := `UPDATE microservices SET sensitive_paths = ?, updated_at = ? WHERE id = ?`
+	
+	result, err := m.db.Exec(query, strings.Join(patterns, "\n"), time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to update microservice sensitive paths: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("microservice with ID %d not found", id)
+	}
+
+	return nil
+}
+
 func (m *MicroserviceModel) Delete(id int64) error {
 	query := `DELETE FROM microservices WHERE id = ?`
 	
@@ -125,6 +341,90 @@ func (m *MicroserviceModel) Delete(id int64) error {
 	return nil
 }
 
+// Merge moves the deployments, deployment history, actions and usage of service mergeID to service
+// keepID in one transaction and deletes mergeID. Both must belong to the same repository. A
+// deployment to a target keepID already deploys to, a sensitive pull request or a custom field value
+// keepID already has is dropped; keepID's owner, primary environment and image name are filled from
+// mergeID's when empty. Watch rules scoped to mergeID are scoped to keepID instead.
+func (m *MicroserviceModel) Merge(keepID, mergeID int64) (*types.ServiceMergeResult, error) {
+	if keepID == mergeID {
+		return nil, fmt.Errorf("cannot merge service %d into itself", keepID)
+	}
+
+	tx, err := m.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var keepRepositoryID, mergeRepositoryID int64
+	if err := tx.QueryRow(`SELECT repository_id FROM microservices WHERE id = ?`, keepID).Scan(&keepRepositoryID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("service %d not found", keepID)
+		}
+		return nil, fmt.Errorf("failed to get microservice: %w", err)
+	}
+	if err := tx.QueryRow(`SELECT repository_id FROM microservices WHERE id = ?`, mergeID).Scan(&mergeRepositoryID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("service %d not found", mergeID)
+		}
+		return nil, fmt.Errorf("failed to get microservice: %w", err)
+	}
+	if keepRepositoryID != mergeRepositoryID {
+		return nil, fmt.Errorf("services %d and %d belong to different repositories", keepID, mergeID)
+	}
+
+	result := &types.ServiceMergeResult{}
+	counted := []struct {
+		count *int64
+		query string
+	}{
+		{&result.Deployments, `UPDATE OR IGNORE deployments SET service_id = ?1 WHERE service_id = ?2`},
+		{&result.DeploymentHistory, `UPDATE deployment_history SET service_id = ?1 WHERE service_id = ?2`},
+		{&result.Actions, `UPDATE actions SET service_id = ?1 WHERE service_id = ?2`},
+		{&result.UsageEvents, `UPDATE usage_events SET service_id = ?1 WHERE service_id = ?2`},
+		{&result.DroppedDeployments, `DELETE FROM deployments WHERE service_id = ?2`},
+	}
+	for _, statement := range counted {
+		res, err := tx.Exec(statement.query, keepID, mergeID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to reassign records of service %d: %w", mergeID, err)
+		}
+		if *statement.count, err = res.RowsAffected(); err != nil {
+			return nil, err
+		}
+	}
+
+	statements := []string{
+		`UPDATE OR IGNORE sensitive_pull_requests SET service_id = ?1 WHERE service_id = ?2`,
+		`UPDATE OR IGNORE custom_field_values SET entity_id = ?1 WHERE entity_id = ?2
+			AND field_id IN (SELECT id FROM custom_field_definitions WHERE entity_type = 'service')`,
+		// Watch rules scoped to both services keep the kept one once, where it was listed first
+		`UPDATE watch_rules SET service_ids = (
+			SELECT json_group_array(service_id) FROM (
+				SELECT CASE WHEN value = ?2 THEN ?1 ELSE value END AS service_id, MIN(key) AS position
+				FROM json_each(watch_rules.service_ids) GROUP BY service_id ORDER BY position))
+			WHERE EXISTS (SELECT 1 FROM json_each(watch_rules.service_ids) WHERE value = ?2)`,
+		`UPDATE microservices SET
+			owner = CASE WHEN owner = '' THEN (SELECT owner FROM microservices WHERE id = ?2) ELSE owner END,
+			primary_environment = CASE WHEN primary_environment = ''
+				THEN (SELECT primary_environment FROM microservices WHERE id = ?2) ELSE primary_environment END,
+			image_name = CASE WHEN image_name = '' THEN (SELECT image_name FROM microservices WHERE id = ?2) ELSE image_name END
+			WHERE id = ?1`,
+		`DELETE FROM microservices WHERE id = ?2`,
+	}
+	for _, stmt := range statements {
+		if _, err := tx.Exec(stmt, keepID, mergeID); err != nil {
+			return nil, fmt.Errorf("failed to merge service %d into %d: %w", mergeID, keepID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return result, nil
+}
+
 func (m *MicroserviceModel) DeleteByRepositoryID(repositoryID int64) error {
 	query := `DELETE FROM microservices WHERE repository_id = ?`
 	
@@ -137,6 +437,12 @@ func (m *MicroserviceModel) DeleteByRepositoryID(repositoryID int64) error {
 }
 
 func (m *MicroserviceModel) UpsertServices(repositoryID int64, services []types.Microservice) error {
+	for i := range services {
+		if err := cleanServicePath(&services[i]); err != nil {
+			return err
+		}
+	}
+
 	tx, err := m.db.Begin()
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
@@ -152,8 +458,8 @@ func (m *MicroserviceModel) UpsertServices(repositoryID int64, services []types.
 	// Insert new services
 	if len(services) > 0 {
 		query := `
-			INSERT INTO microservices (repository_id, name, path, description, created_at, updated_at)
-			VALUES (?, ?, ?, ?, ?, ?)
+			INSERT INTO microservices (repository_id, name, path, description, domain, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)
 		`
 		stmt, err := tx.Prepare(query)
 		if err != nil {
@@ -163,7 +469,7 @@ func (m *MicroserviceModel) UpsertServices(repositoryID int64, services []types.
 
 		now := time.Now()
 		for _, service := range services {
-			_, err = stmt.Exec(repositoryID, service.Name, service.Path, service.Description, now, now)
+			_, err = stmt.Exec(repositoryID, service.Name, service.Path, service.Description, service.Domain, now, now)
 			if err != nil {
 				return fmt.Errorf("failed to insert service %s: %w", service.Name, err)
 			}
@@ -173,27 +479,48 @@ func (m *MicroserviceModel) UpsertServices(repositoryID int64, services []types.
 	return tx.Commit()
 }
 
-func (m *MicroserviceModel) UpsertServicesPreserveID(repositoryID int64, services []types.Microservice) error {
+// UpsertServicesPreserveID syncs the services of a repository without changing the IDs of existing ones.
+// It reports whether any service was added, removed or had its description or domain changed.
+func (m *MicroserviceModel) UpsertServicesPreserveID(repositoryID int64, services []types.Microservice) (bool, error) {
+	changes, err := m.UpsertServicesCountingChanges(repositoryID, services)
+	if err != nil {
+		return false, err
+	}
+	return changes.Changed(), nil
+}
+
+// UpsertServicesCountingChanges is UpsertServicesPreserveID, returning how many services were added,
+// had their description or domain updated, or were removed
+func (m *MicroserviceModel) UpsertServicesCountingChanges(repositoryID int64, services []types.Microservice) (*types.ServiceChanges, error) {
+	for i := range services {
+		if err := cleanServicePath(&services[i]); err != nil {
+			return nil, err
+		}
+	}
+
 	tx, err := m.db.Begin()
 	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer tx.Rollback()
 
 	// Get existing services for this repository
 	existingServices := make(map[string]*types.Microservice)
-	rows, err := tx.Query("SELECT id, name, path, description, created_at, updated_at FROM microservices WHERE repository_id = ?", repositoryID)
+	rows, err := tx.Query("SELECT id, name, path, COALESCE(description, ''), description_edited, domain, is_hidden, created_at, updated_at FROM microservices WHERE repository_id = ?", repositoryID)
 	if err != nil {
-		return fmt.Errorf("failed to query existing services: %w", err)
+		return nil, fmt.Errorf("failed to query existing services: %w", err)
 	}
 	defer rows.Close()
 
+	descriptionEdited := make(map[int64]bool)
 	for rows.Next() {
 		service := &types.Microservice{RepositoryID: repositoryID}
-		err := rows.Scan(&service.ID, &service.Name, &service.Path, &service.Description, &service.CreatedAt, &service.UpdatedAt)
+		var edited bool
+		err := rows.Scan(&service.ID, &service.Name, &service.Path, &service.Description, &edited, &service.Domain, &service.IsHidden, &service.CreatedAt, &service.UpdatedAt)
 		if err != nil {
-			return fmt.Errorf("failed to scan existing service: %w", err)
+			return nil, fmt.Errorf("failed to scan existing service: %w", err)
 		}
+		descriptionEdited[service.ID] = edited
 		// Use name+path as unique key
 		key := service.Name + "|" + service.Path
 		existingServices[key] = service
@@ -201,6 +528,7 @@ func (m *MicroserviceModel) UpsertServicesPreserveID(repositoryID int64, service
 
 	// Track which services we've processed to know which ones to delete
 	processedServices := make(map[string]bool)
+	changes := &types.ServiceChanges{}
 	now := time.Now()
 
 	// Process new services
@@ -209,42 +537,65 @@ func (m *MicroserviceModel) UpsertServicesPreserveID(repositoryID int64, service
 		processedServices[key] = true
 
 		if existingService, exists := existingServices[key]; exists {
+			// Descriptions edited through a catalog import win over discovered ones
+			if descriptionEdited[existingService.ID] {
+				newService.Description = existingService.Description
+			}
+			if existingService.Description != newService.Description || existingService.Domain != newService.Domain {
+				changes.Updated++
+			}
+
 			// Update existing service
 			_, err = tx.Exec(
-				"UPDATE microservices SET description = ?, updated_at = ? WHERE id = ?",
-				newService.Description, now, existingService.ID,
+				"UPDATE microservices SET description = ?, domain = ?, has_readme = ?, updated_at = ? WHERE id = ?",
+				newService.Description, newService.Domain, newService.HasReadme, now, existingService.ID,
 			)
 			if err != nil {
-				return fmt.Errorf("failed to update service %s: %w", newService.Name, err)
+				return nil, fmt.Errorf("failed to update service %s: %w", newService.Name, err)
 			}
 		} else {
+			changes.Added++
+
 			// Insert new service
 			_, err = tx.Exec(
-				"INSERT INTO microservices (repository_id, name, path, description, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)",
-				repositoryID, newService.Name, newService.Path, newService.Description, now, now,
+				"INSERT INTO microservices (repository_id, name, path, description, domain, has_readme, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+				repositoryID, newService.Name, newService.Path, newService.Description, newService.Domain, newService.HasReadme, now, now,
 			)
 			if err != nil {
-				return fmt.Errorf("failed to insert service %s: %w", newService.Name, err)
+				return nil, fmt.Errorf("failed to insert service %s: %w", newService.Name, err)
 			}
 		}
 	}
 
-	// Delete services that no longer exist
+	// Delete services that no longer exist. Hidden services are kept so they stay hidden
+	// if discovery picks them up again later.
 	for key, existingService := range existingServices {
-		if !processedServices[key] {
+		if !processedServices[key] && !existingService.IsHidden {
 			_, err = tx.Exec("DELETE FROM microservices WHERE id = ?", existingService.ID)
 			if err != nil {
-				return fmt.Errorf("failed to delete service %s: %w", existingService.Name, err)
+				return nil, fmt.Errorf("failed to delete service %s: %w", existingService.Name, err)
 			}
+			changes.Removed++
 		}
 	}
 
-	return tx.Commit()
+	// Renamed services are added under their new name and path
+	if changes.Added > 0 {
+		if err := forgetScanTrees(tx); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return changes, nil
 }
 
 func (m *MicroserviceModel) GetAll() ([]*types.Microservice, error) {
 	query := `
-		SELECT id, repository_id, name, path, description, created_at, updated_at
+		SELECT id, repository_id, name, path, description, domain, is_hidden, primary_environment, owner, has_readme, image_name, suggested_image_name, created_at, updated_at
 		FROM microservices
 		ORDER BY name
 	`
@@ -264,6 +615,13 @@ func (m *MicroserviceModel) GetAll() ([]*types.Microservice, error) {
 			&service.Name,
 			&service.Path,
 			&service.Description,
+			&service.Domain,
+			&service.IsHidden,
+			&service.PrimaryEnvironment,
+			&service.Owner,
+			&service.HasReadme,
+			&service.ImageName,
+			&service.SuggestedImageName,
 			&service.CreatedAt,
 			&service.UpdatedAt,
 		)
@@ -0,0 +1,252 @@
+package models_test
+
+import (
+	"slices"
+	"testing"
+
+	"dev-dashboard/internal/models"
+	"dev-dashboard/internal/testsupport"
+	"dev-dashboard/pkg/types"
+)
+
+func serviceFixtures(t *testing.T) (*models.MicroserviceModel, *types.Repository) {
+	t.Helper()
+	db := testsupport.NewTestDB(t)
+	return models.NewMicroserviceModel(db), testsupport.Repository(t, db)
+}
+
+// servicesByName returns the repository's services, hidden ones included, keyed by name
+func servicesByName(t *testing.T, model *models.MicroserviceModel, repositoryID int64) map[string]*types.Microservice {
+	t.Helper()
+	services, err := model.GetByRepositoryID(repositoryID, true)
+	if err != nil {
+		t.Fatalf("GetByRepositoryID: %v", err)
+	}
+	byName := make(map[string]*types.Microservice)
+	for _, service := range services {
+		byName[service.Name] = service
+	}
+	return byName
+}
+
+func upsertServices(t *testing.T, model *models.MicroserviceModel, repositoryID int64, services ...types.Microservice) bool {
+	t.Helper()
+	changed, err := model.UpsertServicesPreserveID(repositoryID, services)
+	if err != nil {
+		t.Fatalf("UpsertServicesPreserveID: %v", err)
+	}
+	return changed
+}
+
+func TestUpsertServicesPreserveIDAddsServices(t *testing.T) {
+	model, repo := serviceFixtures(t)
+
+	changed := upsertServices(t, model, repo.ID,
+		types.Microservice{Name: "api", Path: "services/api"},
+		types.Microservice{Name: "web", Path: "services/web"},
+	)
+	if !changed {
+		t.Error("adding services should report a change")
+	}
+	if services := servicesByName(t, model, repo.ID); len(services) != 2 {
+		t.Errorf("repository has %d services, want 2", len(services))
+	}
+}
+
+func TestUpsertServicesPreserveIDKeepsIDs(t *testing.T) {
+	model, repo := serviceFixtures(t)
+
+	api := types.Microservice{Name: "api", Path: "services/api", Description: "API"}
+	upsertServices(t, model, repo.ID, api)
+	before := servicesByName(t, model, repo.ID)["api"]
+
+	if upsertServices(t, model, repo.ID, api) {
+		t.Error("upserting the same services should not report a change")
+	}
+	after := servicesByName(t, model, repo.ID)["api"]
+	if after == nil || after.ID != before.ID {
+		t.Errorf("service ID changed from %d to %v", before.ID, after)
+	}
+}
+
+func TestUpsertServicesPreserveIDUpdatesDescription(t *testing.T) {
+	model, repo := serviceFixtures(t)
+
+	upsertServices(t, model, repo.ID, types.Microservice{Name: "api", Path: "services/api", Description: "old"})
+	before := servicesByName(t, model, repo.ID)["api"]
+
+	if !upsertServices(t, model, repo.ID, types.Microservice{Name: "api", Path: "services/api", Description: "new"}) {
+		t.Error("a changed description should report a change")
+	}
+	after := servicesByName(t, model, repo.ID)["api"]
+	if after.ID != before.ID || after.Description != "new" {
+		t.Errorf("got service %d described %q, want %d described \"new\"", after.ID, after.Description, before.ID)
+	}
+}
+
+func TestUpsertServicesPreserveIDRemovesMissingButKeepsHidden(t *testing.T) {
+	model, repo := serviceFixtures(t)
+
+	upsertServices(t, model, repo.ID,
+		types.Microservice{Name: "api", Path: "services/api"},
+		types.Microservice{Name: "legacy", Path: "services/legacy"},
+		types.Microservice{Name: "hidden", Path: "services/hidden"},
+	)
+	if err := model.SetHidden(servicesByName(t, model, repo.ID)["hidden"].ID, true); err != nil {
+		t.Fatalf("SetHidden: %v", err)
+	}
+
+	if !upsertServices(t, model, repo.ID, types.Microservice{Name: "api", Path: "services/api"}) {
+		t.Error("removing a service should report a change")
+	}
+	services := servicesByName(t, model, repo.ID)
+	if _, ok := services["legacy"]; ok {
+		t.Error("a service discovery no longer finds should be removed")
+	}
+	if _, ok := services["hidden"]; !ok {
+		t.Error("a hidden service should be kept so it stays hidden")
+	}
+}
+
+func TestUpsertServicesPreserveIDKeepsEditedDescription(t *testing.T) {
+	model, repo := serviceFixtures(t)
+
+	upsertServices(t, model, repo.ID, types.Microservice{Name: "api", Path: "services/api", Description: "from README"})
+	api := servicesByName(t, model, repo.ID)["api"]
+	err := model.ApplyCatalogChanges([]*types.ServiceCatalogChange{
+		{ServiceID: api.ID, Field: types.CatalogFieldDescription, New: "edited"},
+	})
+	if err != nil {
+		t.Fatalf("ApplyCatalogChanges: %v", err)
+	}
+
+	if upsertServices(t, model, repo.ID, types.Microservice{Name: "api", Path: "services/api", Description: "from README"}) {
+		t.Error("a discovered description shouldn't count as a change over an edited one")
+	}
+	if got := servicesByName(t, model, repo.ID)["api"].Description; got != "edited" {
+		t.Errorf("description is %q, want the edited one", got)
+	}
+}
+
+func TestUpsertServicesPreserveIDMatchesCleanedPaths(t *testing.T) {
+	model, repo := serviceFixtures(t)
+
+	upsertServices(t, model, repo.ID, types.Microservice{Name: "api", Path: "services/api"})
+	before := servicesByName(t, model, repo.ID)["api"]
+
+	if upsertServices(t, model, repo.ID, types.Microservice{Name: "api", Path: "./services//api/"}) {
+		t.Error("a path differing only in formatting should not report a change")
+	}
+	after := servicesByName(t, model, repo.ID)["api"]
+	if after.ID != before.ID || after.Path != "services/api" {
+		t.Errorf("got service %d at %q, want %d at services/api", after.ID, after.Path, before.ID)
+	}
+}
+
+func TestUpsertServicesPreserveIDRejectsParentPaths(t *testing.T) {
+	model, repo := serviceFixtures(t)
+
+	_, err := model.UpsertServicesPreserveID(repo.ID, []types.Microservice{{Name: "escape", Path: "services/../../etc"}})
+	if err == nil {
+		t.Error("a path with .. segments should be rejected")
+	}
+}
+
+func TestGetAllWithRepositoryRunsOneQuery(t *testing.T) {
+	db := testsupport.NewTestDatabase(t)
+	model := models.NewMicroserviceModel(db.GetConn())
+	for i := 0; i < 5; i++ {
+		repo := testsupport.Repository(t, db.GetConn())
+		testsupport.Service(t, db.GetConn(), repo.ID)
+	}
+
+	before := db.QueryCount()
+	services, err := model.GetAllWithRepository(false)
+	if err != nil {
+		t.Fatalf("GetAllWithRepository: %v", err)
+	}
+	if len(services) != 5 {
+		t.Errorf("got %d services, want 5", len(services))
+	}
+	if n := db.QueryCount() - before; n != 1 {
+		t.Errorf("ran %d statements, want one whatever the number of repositories", n)
+	}
+}
+
+func TestUpsertServicesPreserveIDForgetsScanTreesWhenServicesAreAdded(t *testing.T) {
+	db := testsupport.NewTestDB(t)
+	services := models.NewMicroserviceModel(db)
+	repositories := models.NewRepositoryModel(db)
+	repo := testsupport.Repository(t, db)
+	k8s := testsupport.KubernetesRepository(t, db)
+	scanTreeSHA := func() string {
+		t.Helper()
+		sha, err := repositories.GetScanTreeSHA(k8s.ID)
+		if err != nil {
+			t.Fatalf("GetScanTreeSHA: %v", err)
+		}
+		return sha
+	}
+	api := types.Microservice{Name: "api", Path: "services/api"}
+	upsertServices(t, services, repo.ID, api)
+
+	// Details and removals leave the scan's matches as they were
+	if err := repositories.UpdateScanTreeSHA(k8s.ID, "abc123"); err != nil {
+		t.Fatalf("UpdateScanTreeSHA: %v", err)
+	}
+	api.Description = "The API"
+	upsertServices(t, services, repo.ID, api)
+	if sha := scanTreeSHA(); sha != "abc123" {
+		t.Errorf("got scan tree SHA %q after a description changed, want it kept", sha)
+	}
+
+	// A new service may be deployed by kustomizations the last scan skipped
+	upsertServices(t, services, repo.ID, api, types.Microservice{Name: "worker", Path: "services/worker"})
+	if sha := scanTreeSHA(); sha != "" {
+		t.Errorf("got scan tree SHA %q after a service was added, want it forgotten", sha)
+	}
+}
+
+func TestMergeRescopesWatchRules(t *testing.T) {
+	db := testsupport.NewTestDB(t)
+	model := models.NewMicroserviceModel(db)
+	repo := testsupport.Repository(t, db)
+	keep := testsupport.Service(t, db, repo.ID)
+	merge := testsupport.Service(t, db, repo.ID)
+	other := testsupport.Service(t, db, repo.ID)
+
+	rules := models.NewWatchRuleModel(db)
+	scoped := map[string][]int64{
+		"merged only": {other.ID, merge.ID},
+		"both":        {merge.ID, other.ID, keep.ID},
+		"neither":     {other.ID},
+	}
+	want := map[string][]int64{
+		"merged only": {other.ID, keep.ID},
+		"both":        {keep.ID, other.ID},
+		"neither":     {other.ID},
+	}
+	ids := make(map[string]int64)
+	for name, serviceIDs := range scoped {
+		rule := &types.WatchRule{Name: name, Enabled: true, Scope: types.WatchScopeServices, ServiceIDs: serviceIDs,
+			Conditions: []types.WatchCondition{{Type: types.WatchDeployFailed}}}
+		if err := rules.Create(rule); err != nil {
+			t.Fatalf("Create(%s): %v", name, err)
+		}
+		ids[name] = rule.ID
+	}
+
+	if _, err := model.Merge(keep.ID, merge.ID); err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+
+	for name, id := range ids {
+		rule, err := rules.GetByID(id)
+		if err != nil {
+			t.Fatalf("GetByID(%s): %v", name, err)
+		}
+		if !slices.Equal(rule.ServiceIDs, want[name]) {
+			t.Errorf("rule %q is scoped to %v, want %v", name, rule.ServiceIDs, want[name])
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"dev-dashboard/pkg/types"
+)
+
+type NotificationModel struct {
+	db *sql.DB
+}
+
+func NewNotificationModel(db *sql.DB) *NotificationModel {
+	return &NotificationModel{db: db}
+}
+
+// Create stores a notification. One with DeliverAt set stays hidden from GetRecent until then.
+func (m *NotificationModel) Create(notification *types.Notification) error {
+	query := `
+		INSERT INTO notifications (repository_id, type, title, message, environment, is_read, created_at, deliver_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	notification.CreatedAt = time.Now()
+
+	result, err := m.db.Exec(query, notification.RepositoryID, notification.Type, notification.Title, notification.Message, notification.Environment, notification.IsRead, notification.CreatedAt, notification.DeliverAt)
+	if err != nil {
+		return fmt.Errorf("failed to create notification: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get notification ID: %w", err)
+	}
+
+	notification.ID = id
+	return nil
+}
+
+// GetRecent returns the latest delivered notifications. Notifications queued during quiet hours
+// are ordered by when they were delivered.
+func (m *NotificationModel) GetRecent(unreadOnly bool, limit int) ([]*types.Notification, error) {
+	query := `
+		SELECT id, repository_id, type, title, message, environment, is_read, created_at, deliver_at
+		FROM notifications
+		WHERE (? = 0 OR is_read = 0) AND (deliver_at IS NULL OR deliver_at <= ?)
+		ORDER BY COALESCE(deliver_at, created_at) DESC, id DESC
+		LIMIT ?
+	`
+
+	rows, err := m.db.Query(query, unreadOnly, time.Now(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query notifications: %w", err)
+	}
+	defer rows.Close()
+
+	var notifications []*types.Notification
+	for rows.Next() {
+		notification := &types.Notification{}
+		err := rows.Scan(
+			&notification.ID,
+			&notification.RepositoryID,
+			&notification.Type,
+			&notification.Title,
+			&notification.Message,
+			&notification.Environment,
+			&notification.IsRead,
+			&notification.CreatedAt,
+			&notification.DeliverAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan notification: %w", err)
+		}
+		notifications = append(notifications, notification)
+	}
+
+	return notifications, nil
+}
+
+func (m *NotificationModel) MarkRead(id int64) error {
+	query := `UPDATE notifications SET is_read = 1 WHERE id = ?`
+
+	_, err := m.db.Exec(query, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark notification as read: %w", err)
+	}
+
+	return nil
+}
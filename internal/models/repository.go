@@ -2,9 +2,12 @@ package models
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
+	"dev-dashboard/internal/vcs"
 	"dev-dashboard/pkg/types"
 )
 
@@ -18,14 +21,19 @@ func NewRepositoryModel(db *sql.DB) *RepositoryModel {
 
 func (m *RepositoryModel) Create(repo *types.Repository) error {
 	query := `
-		INSERT INTO repositories (name, url, type, description, service_name, service_location, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO repositories (name, url, type, description, service_name, service_location, discovery_script, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
+	location, err := vcs.CleanServiceLocation(repo.ServiceLocation)
+	if err != nil {
+		return fmt.Errorf("invalid service location: %w", err)
+	}
+	repo.ServiceLocation = location
 	now := time.Now()
 	repo.CreatedAt = now
 	repo.UpdatedAt = now
 
-	result, err := m.db.Exec(query, repo.Name, repo.URL, repo.Type, repo.Description, repo.ServiceName, repo.ServiceLocation, repo.CreatedAt, repo.UpdatedAt)
+	result, err := m.db.Exec(query, repo.Name, repo.URL, repo.Type, repo.Description, repo.ServiceName, repo.ServiceLocation, repo.DiscoveryScript, repo.CreatedAt, repo.UpdatedAt)
 	if err != nil {
 		return fmt.Errorf("failed to create repository: %w", err)
 	}
@@ -36,17 +44,22 @@ func (m *RepositoryModel) Create(repo *types.Repository) error {
 	}
 
 	repo.ID = id
+	repo.Status = types.RepositoryActive
 	return nil
 }
 
 func (m *RepositoryModel) GetByID(id int64) (*types.Repository, error) {
 	query := `
-		SELECT id, name, url, type, description, service_name, service_location, created_at, updated_at, last_sync_at
+		SELECT id, name, url, type, description, service_name, service_location, discovery_script, status, created_at, updated_at, last_sync_at, last_sync_error,
+			access_state, access_status, access_checked_at, access_failures, access_retry_at, sync_state, manual_sync_only, discovery_review, is_favorite, collect_packages,
+			COALESCE(default_branch, ''), workflow_branches
 		FROM repositories
 		WHERE id = ?
 	`
 	
 	repo := &types.Repository{}
+	var discoveryScript, lastSyncError, syncState sql.NullString
+	var accessStatus sql.NullInt64
 	err := m.db.QueryRow(query, id).Scan(
 		&repo.ID,
 		&repo.Name,
@@ -55,22 +68,43 @@ func (m *RepositoryModel) GetByID(id int64) (*types.Repository, error) {
 		&repo.Description,
 		&repo.ServiceName,
 		&repo.ServiceLocation,
+		&discoveryScript,
+		&repo.Status,
 		&repo.CreatedAt,
 		&repo.UpdatedAt,
 		&repo.LastSyncAt,
+		&lastSyncError,
+		&repo.AccessState,
+		&accessStatus,
+		&repo.AccessCheckedAt,
+		&repo.AccessFailures,
+		&repo.AccessRetryAt,
+		&syncState,
+		&repo.ManualSyncOnly,
+		&repo.DiscoveryReview,
+		&repo.IsFavorite,
+		&repo.CollectPackages,
+		&repo.DefaultBranch,
+		&repo.WorkflowBranches,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get repository: %w", err)
 	}
+	repo.DiscoveryScript = discoveryScript.String
+	repo.LastSyncError = lastSyncError.String
+	repo.AccessStatus = int(accessStatus.Int64)
+	repo.SyncState = parseSyncState(syncState)
 
 	return repo, nil
 }
 
 func (m *RepositoryModel) GetAll() ([]*types.Repository, error) {
 	query := `
-		SELECT id, name, url, type, description, service_name, service_location, created_at, updated_at, last_sync_at
+		SELECT id, name, url, type, description, service_name, service_location, discovery_script, status, created_at, updated_at, last_sync_at, last_sync_error,
+			access_state, access_status, access_checked_at, access_failures, access_retry_at, sync_state, manual_sync_only, discovery_review, is_favorite, collect_packages,
+			COALESCE(default_branch, ''), workflow_branches
 		FROM repositories
-		ORDER BY created_at DESC
+		ORDER BY is_favorite DESC, created_at DESC
 	`
 	
 	rows, err := m.db.Query(query)
@@ -82,6 +116,8 @@ func (m *RepositoryModel) GetAll() ([]*types.Repository, error) {
 	var repositories []*types.Repository
 	for rows.Next() {
 		repo := &types.Repository{}
+		var discoveryScript, lastSyncError, syncState sql.NullString
+		var accessStatus sql.NullInt64
 		err := rows.Scan(
 			&repo.ID,
 			&repo.Name,
@@ -90,13 +126,32 @@ func (m *RepositoryModel) GetAll() ([]*types.Repository, error) {
 			&repo.Description,
 			&repo.ServiceName,
 			&repo.ServiceLocation,
+			&discoveryScript,
+			&repo.Status,
 			&repo.CreatedAt,
 			&repo.UpdatedAt,
 			&repo.LastSyncAt,
+			&lastSyncError,
+			&repo.AccessState,
+			&accessStatus,
+			&repo.AccessCheckedAt,
+			&repo.AccessFailures,
+			&repo.AccessRetryAt,
+			&syncState,
+			&repo.ManualSyncOnly,
+			&repo.DiscoveryReview,
+			&repo.IsFavorite,
+			&repo.CollectPackages,
+			&repo.DefaultBranch,
+			&repo.WorkflowBranches,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan repository: %w", err)
 		}
+		repo.DiscoveryScript = discoveryScript.String
+		repo.LastSyncError = lastSyncError.String
+		repo.AccessStatus = int(accessStatus.Int64)
+		repo.SyncState = parseSyncState(syncState)
 		repositories = append(repositories, repo)
 	}
 
@@ -106,12 +161,17 @@ func (m *RepositoryModel) GetAll() ([]*types.Repository, error) {
 func (m *RepositoryModel) Update(repo *types.Repository) error {
 	query := `
 		UPDATE repositories
-		SET name = ?, url = ?, type = ?, description = ?, service_name = ?, service_location = ?, updated_at = ?
+		SET name = ?, url = ?, type = ?, description = ?, service_name = ?, service_location = ?, discovery_script = ?, updated_at = ?
 		WHERE id = ?
 	`
 	
+	location, err := vcs.CleanServiceLocation(repo.ServiceLocation)
+	if err != nil {
+		return fmt.Errorf("invalid service location: %w", err)
+	}
+	repo.ServiceLocation = location
 	repo.UpdatedAt = time.Now()
-	_, err := m.db.Exec(query, repo.Name, repo.URL, repo.Type, repo.Description, repo.ServiceName, repo.ServiceLocation, repo.UpdatedAt, repo.ID)
+	_, err = m.db.Exec(query, repo.Name, repo.URL, repo.Type, repo.Description, repo.ServiceName, repo.ServiceLocation, repo.DiscoveryScript, repo.UpdatedAt, repo.ID)
 	if err != nil {
 		return fmt.Errorf("failed to update repository: %w", err)
 	}
@@ -135,6 +195,263 @@ func (m *RepositoryModel) UpdateLastSync(id int64) error {
 	return nil
 }
 
+// GetScanTreeSHA returns the tree SHA of the kustomization scan root recorded at the last full scan
+func (m *RepositoryModel) GetScanTreeSHA(id int64) (string, error) {
+	var treeSHA sql.NullString
+	err := m.db.QueryRow(`SELECT scan_tree_sha FROM repositories WHERE id = ?`, id).Scan(&treeSHA)
+	if err != nil {
+		return "", fmt.Errorf("failed to get scan tree SHA: %w", err)
+	}
+	return treeSHA.String, nil
+}
+
+// UpdateScanTreeSHA records the tree SHA of the kustomization scan root; an empty SHA forces the next full scan
+func (m *RepositoryModel) UpdateScanTreeSHA(id int64, treeSHA string) error {
+	query := `UPDATE repositories SET scan_tree_sha = ? WHERE id = ?`
+	
+	_, err := m.db.Exec(query, sql.NullString{String: treeSHA, Valid: treeSHA != ""}, id)
+	if err != nil {
+		return fmt.Errorf("failed to update scan tree SHA: %w", err)
+	}
+
+	return nil
+}
+
+// forgetScanTrees clears the scan tree SHA of every kubernetes repository, so their next syncs
+// scan for deployments of services that were added or renamed, which an earlier scan skipped for
+// matching no service
+func forgetScanTrees(tx *sql.Tx) error {
+	if _, err := tx.Exec(`UPDATE repositories SET scan_tree_sha = NULL WHERE type = ?`, types.KubernetesType); err != nil {
+		return fmt.Errorf("failed to clear scan tree SHAs: %w", err)
+	}
+	return nil
+}
+
+// UpdateDefaultBranch records the repository's default branch as reported by GitHub
+func (m *RepositoryModel) UpdateDefaultBranch(id int64, branch string) error {
+	query := `UPDATE repositories SET default_branch = ? WHERE id = ?`
+	
+	_, err := m.db.Exec(query, sql.NullString{String: branch, Valid: branch != ""}, id)
+	if err != nil {
+		return fmt.Errorf("failed to update default branch: %w", err)
+	}
+
+	return nil
+}
+
+// GetSensitivePaths returns the path patterns, relative to the repository root, whose changes in a
+// pull request are flagged
+func (m *RepositoryModel) GetSensitivePaths(id int64) ([]string, error) {
+	var patterns string
+	err := m.db.QueryRow(`SELECT sensitive_paths FROM repositories WHERE id = ?`, id).Scan(&patterns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repository sensitive paths: %w", err)
+	}
+	return splitLines(patterns), nil
+}
+
+// UpdateSensitivePaths replaces the repository's sensitive path patterns
+func (m *RepositoryModel) UpdateSensitivePaths(id int64, patterns []string) error {
+	query := `UPDATE repositories SET sensitive_paths = ?, updated_at = ? WHERE id = ?`
+	
+	result, err := m.db.Exec(query, strings.Join(patterns, "\n"), time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to update repository sensitive paths: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("repository with ID %d not found", id)
+	}
+
+	return nil
+}
+
+// UpdateURL points a repository at a new URL, e.g. after it was renamed or transferred on GitHub
+func (m *RepositoryModel) UpdateURL(id int64, url string) error {
+	query := `UPDATE repositories SET url = ?, updated_at = ? WHERE id = ?`
+	
+	_, err := m.db.Exec(query, url, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to update repository URL: %w", err)
+	}
+
+	return nil
+}
+
+// SetManualSyncOnly sets whether scheduled syncs skip a repository
+func (m *RepositoryModel) SetManualSyncOnly(id int64, manual bool) error {
+	result, err := m.db.Exec(`UPDATE repositories SET manual_sync_only = ?, updated_at = ? WHERE id = ?`, manual, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to update repository sync mode: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("repository with ID %d not found", id)
+	}
+	return nil
+}
+
+// SetDiscoveryReview sets whether services a sync discovers, removes or renames in a repository wait
+// for review instead of being applied
+func (m *RepositoryModel) SetDiscoveryReview(id int64, review bool) error {
+	result, err := m.db.Exec(`UPDATE repositories SET discovery_review = ?, updated_at = ? WHERE id = ?`, review, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to update repository discovery mode: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("repository with ID %d not found", id)
+	}
+	return nil
+}
+
+// SetCollectPackages sets whether the sync reads the go.mod and package.json of a repository's
+// services
+func (m *RepositoryModel) SetCollectPackages(id int64, collect bool) error {
+	result, err := m.db.Exec(`UPDATE repositories SET collect_packages = ?, updated_at = ? WHERE id = ?`, collect, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to update repository package collection: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("repository with ID %d not found", id)
+	}
+	return nil
+}
+
+// SetWorkflowBranches sets the branch patterns whose workflow runs the sync records for a
+// repository; an empty value stands for the default branch and release branches
+func (m *RepositoryModel) SetWorkflowBranches(id int64, branches string) error {
+	result, err := m.db.Exec(`UPDATE repositories SET workflow_branches = ?, updated_at = ? WHERE id = ?`, branches, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to update repository workflow branches: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("repository with ID %d not found", id)
+	}
+	return nil
+}
+
+// ToggleFavorite flips whether a repository is a favorite and returns whether it now is
+func (m *RepositoryModel) ToggleFavorite(id int64) (bool, error) {
+	result, err := m.db.Exec(`UPDATE repositories SET is_favorite = NOT is_favorite, updated_at = ? WHERE id = ?`, time.Now(), id)
+	if err != nil {
+		return false, fmt.Errorf("failed to update repository favorite: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return false, fmt.Errorf("repository with ID %d not found", id)
+	}
+
+	var favorite bool
+	if err := m.db.QueryRow(`SELECT is_favorite FROM repositories WHERE id = ?`, id).Scan(&favorite); err != nil {
+		return false, fmt.Errorf("failed to get repository favorite: %w", err)
+	}
+	return favorite, nil
+}
+
+func (m *RepositoryModel) UpdateStatus(id int64, status types.RepositoryStatus) error {
+	query := `UPDATE repositories SET status = ?, updated_at = ? WHERE id = ?`
+	
+	_, err := m.db.Exec(query, status, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to update repository status: %w", err)
+	}
+
+	return nil
+}
+
+// RecordNotFound counts a sync pass in which GitHub answered 404 for the repository and
+// returns how many passes in a row did so
+func (m *RepositoryModel) RecordNotFound(id int64) (int, error) {
+	_, err := m.db.Exec(`UPDATE repositories SET not_found_count = not_found_count + 1 WHERE id = ?`, id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to record repository not found: %w", err)
+	}
+
+	var count int
+	if err := m.db.QueryRow(`SELECT not_found_count FROM repositories WHERE id = ?`, id).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to get repository not found count: %w", err)
+	}
+	return count, nil
+}
+
+// MarkReachable clears the not found count and makes an unreachable repository active again
+func (m *RepositoryModel) MarkReachable(id int64) error {
+	query := `
+		UPDATE repositories
+		SET not_found_count = 0, status = CASE WHEN status = ? THEN ? ELSE status END
+		WHERE id = ?
+	`
+	
+	_, err := m.db.Exec(query, types.RepositoryUnreachable, types.RepositoryActive, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark repository reachable: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateAccess records what GitHub answered when a sync looked the repository up: the access state,
+// the HTTP status, how many forbidden lookups in a row there were and until when scheduled syncs
+// should skip the repository (nil to not skip it)
+func (m *RepositoryModel) UpdateAccess(id int64, state types.RepositoryAccess, httpStatus, failures int, retryAt *time.Time) error {
+	query := `
+		UPDATE repositories
+		SET access_state = ?, access_status = NULLIF(?, 0), access_checked_at = ?, access_failures = ?, access_retry_at = ?
+		WHERE id = ?
+	`
+
+	_, err := m.db.Exec(query, state, httpStatus, time.Now(), failures, retryAt, id)
+	if err != nil {
+		return fmt.Errorf("failed to update repository access: %w", err)
+	}
+
+	return nil
+}
+
+// SetSyncState stores the checkpoint of a sync pass in progress; nil clears it when the pass ends
+func (m *RepositoryModel) SetSyncState(id int64, state *types.SyncState) error {
+	var value interface{}
+	if state != nil {
+		data, err := json.Marshal(state)
+		if err != nil {
+			return fmt.Errorf("failed to encode sync state: %w", err)
+		}
+		value = string(data)
+	}
+
+	_, err := m.db.Exec(`UPDATE repositories SET sync_state = ? WHERE id = ?`, value, id)
+	if err != nil {
+		return fmt.Errorf("failed to set repository sync state: %w", err)
+	}
+
+	return nil
+}
+
+// parseSyncState decodes a stored sync checkpoint; an unreadable one is dropped so the next pass
+// starts over
+func parseSyncState(value sql.NullString) *types.SyncState {
+	if !value.Valid || value.String == "" {
+		return nil
+	}
+	state := &types.SyncState{}
+	if err := json.Unmarshal([]byte(value.String), state); err != nil {
+		return nil
+	}
+	return state
+}
+
+// SetLastSyncError records why the repository's last connectivity check failed; an empty reason clears it
+func (m *RepositoryModel) SetLastSyncError(id int64, reason string) error {
+	_, err := m.db.Exec(`UPDATE repositories SET last_sync_error = NULLIF(?, '') WHERE id = ?`, reason, id)
+	if err != nil {
+		return fmt.Errorf("failed to set repository sync error: %w", err)
+	}
+
+	return nil
+}
+
 func (m *RepositoryModel) Delete(id int64) error {
 	// Start a transaction to ensure atomic deletion
 	tx, err := m.db.Begin()
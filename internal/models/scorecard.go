@@ -0,0 +1,124 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+
+	"dev-dashboard/pkg/types"
+)
+
+type ScorecardModel struct {
+	db *sql.DB
+}
+
+func NewScorecardModel(db *sql.DB) *ScorecardModel {
+	return &ScorecardModel{db: db}
+}
+
+// Save replaces the stored scorecard of a service
+func (m *ScorecardModel) Save(scorecard *types.ServiceScorecard) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO service_scorecards (service_id, grade, score, evaluated_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(service_id) DO UPDATE SET grade = excluded.grade, score = excluded.score, evaluated_at = excluded.evaluated_at
+	`, scorecard.ServiceID, scorecard.Grade, scorecard.Score, scorecard.EvaluatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save scorecard: %w", err)
+	}
+
+	if _, err := tx.Exec("DELETE FROM scorecard_results WHERE service_id = ?", scorecard.ServiceID); err != nil {
+		return fmt.Errorf("failed to delete scorecard results: %w", err)
+	}
+	for _, result := range scorecard.Results {
+		_, err := tx.Exec(
+			"INSERT INTO scorecard_results (service_id, check_id, status, detail) VALUES (?, ?, ?, ?)",
+			scorecard.ServiceID, result.CheckID, result.Status, result.Detail,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to save scorecard result %s: %w", result.CheckID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetByServiceID returns the stored scorecard of a service, or nil if it hasn't been evaluated.
+// Results carry check IDs only; names come from the check registry.
+func (m *ScorecardModel) GetByServiceID(serviceID int64) (*types.ServiceScorecard, error) {
+	scorecards, err := m.query("WHERE s.service_id = ?", serviceID)
+	if err != nil {
+		return nil, err
+	}
+	if len(scorecards) == 0 {
+		return nil, nil
+	}
+	return scorecards[0], nil
+}
+
+// GetAll returns every stored scorecard
+func (m *ScorecardModel) GetAll() ([]*types.ServiceScorecard, error) {
+	return m.query("")
+}
+
+func (m *ScorecardModel) query(where string, args ...interface{}) ([]*types.ServiceScorecard, error) {
+	rows, err := m.db.Query(`
+		SELECT s.service_id, ms.name, s.grade, s.score, s.evaluated_at
+		FROM service_scorecards s
+		JOIN microservices ms ON ms.id = s.service_id
+		`+where+`
+		ORDER BY ms.name
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query scorecards: %w", err)
+	}
+	defer rows.Close()
+
+	var scorecards []*types.ServiceScorecard
+	byService := make(map[int64]*types.ServiceScorecard)
+	for rows.Next() {
+		scorecard := &types.ServiceScorecard{Results: []types.ScorecardResult{}}
+		if err := rows.Scan(&scorecard.ServiceID, &scorecard.ServiceName, &scorecard.Grade, &scorecard.Score, &scorecard.EvaluatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan scorecard: %w", err)
+		}
+		scorecards = append(scorecards, scorecard)
+		byService[scorecard.ServiceID] = scorecard
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query scorecards: %w", err)
+	}
+	rows.Close()
+
+	resultRows, err := m.db.Query(`
+		SELECT r.service_id, r.check_id, r.status, r.detail
+		FROM scorecard_results r
+		JOIN service_scorecards s ON s.service_id = r.service_id
+		`+where+`
+		ORDER BY r.rowid
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query scorecard results: %w", err)
+	}
+	defer resultRows.Close()
+
+	for resultRows.Next() {
+		var serviceID int64
+		var result types.ScorecardResult
+		if err := resultRows.Scan(&serviceID, &result.CheckID, &result.Status, &result.Detail); err != nil {
+			return nil, fmt.Errorf("failed to scan scorecard result: %w", err)
+		}
+		if scorecard, ok := byService[serviceID]; ok {
+			scorecard.Results = append(scorecard.Results, result)
+		}
+	}
+
+	return scorecards, nil
+}
@@ -0,0 +1,114 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"dev-dashboard/pkg/types"
+)
+
+// SecurityAlertModel stores each repository's open Dependabot and code scanning alerts as of the
+// last check
+type SecurityAlertModel struct {
+	db *sql.DB
+}
+
+func NewSecurityAlertModel(db *sql.DB) *SecurityAlertModel {
+	return &SecurityAlertModel{db: db}
+}
+
+// Upsert replaces the report of a repository and source
+func (m *SecurityAlertModel) Upsert(report *types.SecurityAlertReport) error {
+	topAlerts := report.TopAlerts
+	if topAlerts == nil {
+		topAlerts = []types.SecurityAlert{}
+	}
+	data, err := json.Marshal(topAlerts)
+	if err != nil {
+		return fmt.Errorf("failed to encode security alerts: %w", err)
+	}
+
+	_, err = m.db.Exec(`
+		INSERT INTO security_alert_reports (repository_id, source, available, detail, critical, high, medium, low, top_alerts, checked_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(repository_id, source) DO UPDATE SET
+			available = excluded.available, detail = excluded.detail, critical = excluded.critical,
+			high = excluded.high, medium = excluded.medium, low = excluded.low,
+			top_alerts = excluded.top_alerts, checked_at = excluded.checked_at
+	`, report.RepositoryID, report.Source, report.Available, report.Detail, report.Critical, report.High,
+		report.Medium, report.Low, string(data), report.CheckedAt)
+	if err != nil {
+		return fmt.Errorf("failed to store security alerts: %w", err)
+	}
+	return nil
+}
+
+// GetByRepositoryID returns a repository's reports, Dependabot first
+func (m *SecurityAlertModel) GetByRepositoryID(repositoryID int64) ([]*types.SecurityAlertReport, error) {
+	rows, err := m.db.Query(`
+		SELECT repository_id, source, available, detail, critical, high, medium, low, top_alerts, checked_at
+		FROM security_alert_reports
+		WHERE repository_id = ?
+		ORDER BY source DESC
+	`, repositoryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get security alerts: %w", err)
+	}
+	defer rows.Close()
+
+	reports := []*types.SecurityAlertReport{}
+	for rows.Next() {
+		report := &types.SecurityAlertReport{}
+		var topAlerts string
+		err := rows.Scan(&report.RepositoryID, &report.Source, &report.Available, &report.Detail, &report.Critical,
+			&report.High, &report.Medium, &report.Low, &topAlerts, &report.CheckedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan security alerts: %w", err)
+		}
+		if err := json.Unmarshal([]byte(topAlerts), &report.TopAlerts); err != nil {
+			return nil, fmt.Errorf("failed to decode security alerts: %w", err)
+		}
+		reports = append(reports, report)
+	}
+	return reports, rows.Err()
+}
+
+// GetCounts returns the open alert counts of every repository that has been checked
+func (m *SecurityAlertModel) GetCounts() (map[int64]*types.SecurityAlertCounts, error) {
+	rows, err := m.db.Query(`
+		SELECT repository_id, source, available, critical, high, medium, low
+		FROM security_alert_reports
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get security alert counts: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[int64]*types.SecurityAlertCounts)
+	for rows.Next() {
+		var report types.SecurityAlertReport
+		err := rows.Scan(&report.RepositoryID, &report.Source, &report.Available, &report.Critical, &report.High,
+			&report.Medium, &report.Low)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan security alert counts: %w", err)
+		}
+
+		repoCounts := counts[report.RepositoryID]
+		if repoCounts == nil {
+			repoCounts = &types.SecurityAlertCounts{}
+			counts[report.RepositoryID] = repoCounts
+		}
+		switch report.Source {
+		case types.SecurityAlertDependabot:
+			repoCounts.Dependabot = report.Total()
+			repoCounts.DependabotUnavailable = !report.Available
+		case types.SecurityAlertCodeScanning:
+			repoCounts.CodeScanning = report.Total()
+			repoCounts.CodeScanningUnavailable = !report.Available
+		}
+		repoCounts.Critical += report.Critical
+		repoCounts.High += report.High
+	}
+	return counts, rows.Err()
+}
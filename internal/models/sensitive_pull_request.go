@@ -0,0 +1,91 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SensitivePullRequestModel remembers which sensitive files each open pull request of a service
+// touches, so a notification is only raised for files that weren't flagged before
+type SensitivePullRequestModel struct {
+	db *sql.DB
+}
+
+func NewSensitivePullRequestModel(db *sql.DB) *SensitivePullRequestModel {
+	return &SensitivePullRequestModel{db: db}
+}
+
+// GetByServiceID returns the flagged files of the service's pull requests by PR number
+func (m *SensitivePullRequestModel) GetByServiceID(serviceID int64) (map[int][]string, error) {
+	rows, err := m.db.Query(`SELECT pr_number, files FROM sensitive_pull_requests WHERE service_id = ?`, serviceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sensitive pull requests: %w", err)
+	}
+	defer rows.Close()
+
+	flagged := make(map[int][]string)
+	for rows.Next() {
+		var number int
+		var files string
+		if err := rows.Scan(&number, &files); err != nil {
+			return nil, fmt.Errorf("failed to scan sensitive pull request: %w", err)
+		}
+		flagged[number] = splitLines(files)
+	}
+	return flagged, nil
+}
+
+// Replace stores the flagged files of the service's open pull requests, forgetting pull requests
+// that are no longer open or no longer touch sensitive files
+func (m *SensitivePullRequestModel) Replace(serviceID int64, flagged map[int][]string) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `DELETE FROM sensitive_pull_requests WHERE service_id = ?`
+	args := []interface{}{serviceID}
+	if len(flagged) > 0 {
+		placeholders := make([]string, 0, len(flagged))
+		for number := range flagged {
+			placeholders = append(placeholders, "?")
+			args = append(args, number)
+		}
+		query += fmt.Sprintf(" AND pr_number NOT IN (%s)", strings.Join(placeholders, ", "))
+	}
+	if _, err := tx.Exec(query, args...); err != nil {
+		return fmt.Errorf("failed to clear sensitive pull requests: %w", err)
+	}
+
+	// flagged_at keeps the time a pull request was first flagged
+	now := time.Now()
+	for number, files := range flagged {
+		_, err := tx.Exec(`
+			INSERT INTO sensitive_pull_requests (service_id, pr_number, files, flagged_at)
+			VALUES (?, ?, ?, ?)
+			ON CONFLICT (service_id, pr_number) DO UPDATE SET files = excluded.files
+		`, serviceID, number, strings.Join(files, "\n"), now)
+		if err != nil {
+			return fmt.Errorf("failed to store sensitive pull request: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit sensitive pull requests: %w", err)
+	}
+	return nil
+}
+
+// splitLines splits a newline-separated column into its non-empty lines
+func splitLines(value string) []string {
+	lines := []string{}
+	for _, line := range strings.Split(value, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
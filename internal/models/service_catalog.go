@@ -0,0 +1,57 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"dev-dashboard/pkg/types"
+)
+
+// ApplyCatalogChanges stores the changes of a service catalog import in one transaction, so a failure
+// leaves every service as it was. An imported description is marked as edited, which keeps discovery
+// from replacing it with the README's; importing an empty description hands it back to discovery.
+func (m *MicroserviceModel) ApplyCatalogChanges(changes []*types.ServiceCatalogChange) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	for _, change := range changes {
+		var query string
+		var args []interface{}
+		switch change.Field {
+		case types.CatalogFieldDescription:
+			query = `UPDATE microservices SET description = ?, description_edited = ?, updated_at = ? WHERE id = ?`
+			args = []interface{}{change.New, change.New != "", now, change.ServiceID}
+		case types.CatalogFieldOwner:
+			query = `UPDATE microservices SET owner = ?, updated_at = ? WHERE id = ?`
+			args = []interface{}{change.New, now, change.ServiceID}
+		case types.CatalogFieldPrimaryEnvironment:
+			query = `UPDATE microservices SET primary_environment = ?, updated_at = ? WHERE id = ?`
+			args = []interface{}{change.New, now, change.ServiceID}
+		default:
+			if change.FieldID == 0 {
+				return fmt.Errorf("unknown service catalog field %q", change.Field)
+			}
+			if change.New == "" {
+				query = `DELETE FROM custom_field_values WHERE field_id = ? AND entity_id = ?`
+				args = []interface{}{change.FieldID, change.ServiceID}
+			} else {
+				query = `INSERT INTO custom_field_values (field_id, entity_id, value) VALUES (?, ?, ?)
+					ON CONFLICT(field_id, entity_id) DO UPDATE SET value = excluded.value, updated_at = CURRENT_TIMESTAMP`
+				args = []interface{}{change.FieldID, change.ServiceID, change.New}
+			}
+		}
+
+		if _, err := tx.Exec(query, args...); err != nil {
+			return fmt.Errorf("failed to update %s of %s: %w", change.Field, change.ServiceName, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"dev-dashboard/pkg/types"
+)
+
+// ServicePackageModel stores the manifests read for services' packages, the direct dependencies
+// they declare, and the latest versions looked up for them
+type ServicePackageModel struct {
+	db *sql.DB
+}
+
+func NewServicePackageModel(db *sql.DB) *ServicePackageModel {
+	return &ServicePackageModel{db: db}
+}
+
+// GetManifests returns the manifests read for a service, ordered by path
+func (m *ServicePackageModel) GetManifests(serviceID int64) ([]*types.ServicePackageManifest, error) {
+	rows, err := m.db.Query(`
+		SELECT service_id, path, ecosystem, blob_sha, parse_error, parsed_at
+		FROM service_package_manifests
+		WHERE service_id = ?
+		ORDER BY path
+	`, serviceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get package manifests: %w", err)
+	}
+	defer rows.Close()
+
+	manifests := []*types.ServicePackageManifest{}
+	for rows.Next() {
+		manifest := &types.ServicePackageManifest{}
+		if err := rows.Scan(&manifest.ServiceID, &manifest.Path, &manifest.Ecosystem, &manifest.BlobSHA, &manifest.ParseError, &manifest.ParsedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan package manifest: %w", err)
+		}
+		manifests = append(manifests, manifest)
+	}
+	return manifests, rows.Err()
+}
+
+// ReplaceManifest stores a manifest read at a new blob SHA and replaces the packages it declares
+func (m *ServicePackageModel) ReplaceManifest(manifest *types.ServicePackageManifest, packages []*types.ServicePackage) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO service_package_manifests (service_id, path, ecosystem, blob_sha, parse_error, parsed_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(service_id, path) DO UPDATE SET
+			ecosystem = excluded.ecosystem, blob_sha = excluded.blob_sha,
+			parse_error = excluded.parse_error, parsed_at = excluded.parsed_at
+	`, manifest.ServiceID, manifest.Path, manifest.Ecosystem, manifest.BlobSHA, manifest.ParseError, manifest.ParsedAt)
+	if err != nil {
+		return fmt.Errorf("failed to store package manifest: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM service_dependencies_pkg WHERE service_id = ? AND manifest_path = ?`, manifest.ServiceID, manifest.Path); err != nil {
+		return fmt.Errorf("failed to clear packages: %w", err)
+	}
+	for _, pkg := range packages {
+		_, err := tx.Exec(`
+			INSERT OR IGNORE INTO service_dependencies_pkg (service_id, manifest_path, ecosystem, name, version, dev)
+			VALUES (?, ?, ?, ?, ?, ?)
+		`, manifest.ServiceID, manifest.Path, manifest.Ecosystem, pkg.Name, pkg.Version, pkg.Dev)
+		if err != nil {
+			return fmt.Errorf("failed to store package %s: %w", pkg.Name, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// DeleteOtherManifests removes the manifests of a service, and their packages, that aren't in paths,
+// e.g. because the file was deleted
+func (m *ServicePackageModel) DeleteOtherManifests(serviceID int64, paths []string) error {
+	query := `DELETE FROM service_package_manifests WHERE service_id = ?`
+	args := []interface{}{serviceID}
+	if len(paths) > 0 {
+		query += ` AND path NOT IN (?` + strings.Repeat(", ?", len(paths)-1) + `)`
+		for _, path := range paths {
+			args = append(args, path)
+		}
+	}
+	if _, err := m.db.Exec(query, args...); err != nil {
+		return fmt.Errorf("failed to delete package manifests: %w", err)
+	}
+	return nil
+}
+
+// GetByServiceID returns a service's packages with their latest versions, runtime dependencies
+// first, each ordered by name
+func (m *ServicePackageModel) GetByServiceID(serviceID int64) ([]*types.ServicePackage, error) {
+	rows, err := m.db.Query(`
+		SELECT p.id, p.service_id, p.manifest_path, p.ecosystem, p.name, p.version, p.dev,
+			COALESCE(v.latest_version, ''), COALESCE(v.error, ''), v.checked_at
+		FROM service_dependencies_pkg p
+		LEFT JOIN package_versions v ON v.ecosystem = p.ecosystem AND v.name = p.name
+		WHERE p.service_id = ?
+		ORDER BY p.dev, p.name
+	`, serviceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service packages: %w", err)
+	}
+	defer rows.Close()
+
+	packages := []*types.ServicePackage{}
+	for rows.Next() {
+		pkg := &types.ServicePackage{}
+		err := rows.Scan(&pkg.ID, &pkg.ServiceID, &pkg.ManifestPath, &pkg.Ecosystem, &pkg.Name, &pkg.Version, &pkg.Dev,
+			&pkg.LatestVersion, &pkg.LookupError, &pkg.LatestCheckedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan service package: %w", err)
+		}
+		packages = append(packages, pkg)
+	}
+	return packages, rows.Err()
+}
+
+// GetUncheckedVersions returns up to limit packages declared by a repository's services whose
+// latest version was never looked up or was last looked up before checkedBefore, oldest first
+func (m *ServicePackageModel) GetUncheckedVersions(repositoryID int64, checkedBefore time.Time, limit int) ([]*types.ServicePackage, error) {
+	rows, err := m.db.Query(`
+		SELECT DISTINCT p.ecosystem, p.name, v.checked_at
+		FROM service_dependencies_pkg p
+		JOIN microservices s ON s.id = p.service_id
+		LEFT JOIN package_versions v ON v.ecosystem = p.ecosystem AND v.name = p.name
+		WHERE s.repository_id = ? AND (v.checked_at IS NULL OR v.checked_at < ?)
+		ORDER BY v.checked_at IS NOT NULL, v.checked_at, p.name
+		LIMIT ?
+	`, repositoryID, checkedBefore, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get packages to look up: %w", err)
+	}
+	defer rows.Close()
+
+	packages := []*types.ServicePackage{}
+	for rows.Next() {
+		pkg := &types.ServicePackage{}
+		if err := rows.Scan(&pkg.Ecosystem, &pkg.Name, &pkg.LatestCheckedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan package to look up: %w", err)
+		}
+		packages = append(packages, pkg)
+	}
+	return packages, rows.Err()
+}
+
+// SetLatestVersion records the result of looking up a package's latest version; a failed lookup
+// keeps the version found before
+func (m *ServicePackageModel) SetLatestVersion(ecosystem, name, version, lookupError string) error {
+	_, err := m.db.Exec(`
+		INSERT INTO package_versions (ecosystem, name, latest_version, error, checked_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(ecosystem, name) DO UPDATE SET
+			latest_version = CASE WHEN excluded.latest_version != '' THEN excluded.latest_version ELSE package_versions.latest_version END,
+			error = excluded.error, checked_at = excluded.checked_at
+	`, ecosystem, name, version, lookupError, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to store latest version of %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,308 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"dev-dashboard/pkg/types"
+)
+
+// ServiceSummaryModel maintains service_summaries: what the services list shows about each service,
+// gathered from actions, deployments and the GitHub data last fetched for it, so the list is read
+// with one query instead of several per service
+type ServiceSummaryModel struct {
+	db *sql.DB
+}
+
+func NewServiceSummaryModel(db *sql.DB) *ServiceSummaryModel {
+	return &ServiceSummaryModel{db: db}
+}
+
+// Refresh recomputes the last build, last deployment and deployed environments of services from the
+// actions and deployments tables, creating their summaries if needed; nil refreshes every service.
+// Pull request counts and ticket keys are kept.
+func (m *ServiceSummaryModel) Refresh(serviceIDs []int64) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if serviceIDs == nil {
+		if serviceIDs, err = allServiceIDs(tx); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	for _, serviceID := range serviceIDs {
+		lastBuild, err := latestServiceAction(tx, serviceID, types.BuildAction)
+		if err != nil {
+			return err
+		}
+		lastDeployment, err := latestServiceAction(tx, serviceID, types.DeploymentAction)
+		if err != nil {
+			return err
+		}
+		environments, err := serviceEnvironments(tx, serviceID)
+		if err != nil {
+			return err
+		}
+		data, err := json.Marshal(environments)
+		if err != nil {
+			return fmt.Errorf("failed to encode environments of service %d: %w", serviceID, err)
+		}
+
+		// The service may have been deleted since its IDs were read
+		_, err = tx.Exec(`
+			INSERT INTO service_summaries (service_id, last_build, last_deployment, environments, updated_at)
+			SELECT id, ?, ?, ?, ? FROM microservices WHERE id = ?
+			ON CONFLICT(service_id) DO UPDATE SET
+				last_build = excluded.last_build, last_deployment = excluded.last_deployment,
+				environments = excluded.environments, updated_at = excluded.updated_at
+		`, lastBuild, lastDeployment, string(data), now, serviceID)
+		if err != nil {
+			return fmt.Errorf("failed to store summary of service %d: %w", serviceID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func allServiceIDs(tx *sql.Tx) ([]int64, error) {
+	rows, err := tx.Query(`SELECT id FROM microservices`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get services: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan service: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// latestServiceAction returns the JSON of a service's most recently started action of a type, or
+// nil when it has none
+func latestServiceAction(tx *sql.Tx, serviceID int64, actionType types.ActionType) (*string, error) {
+	action := &types.Action{}
+	var buildHash sql.NullString
+	err := tx.QueryRow(`
+		SELECT id, repository_id, service_id, resource_id, type, status, conclusion, workflow_run_id, commit_sha, branch, build_hash, started_at, completed_at, created_at, updated_at
+		FROM actions
+		WHERE service_id = ? AND type = ?
+		ORDER BY started_at DESC, id DESC
+		LIMIT 1
+	`, serviceID, actionType).Scan(
+		&action.ID,
+		&action.RepositoryID,
+		&action.ServiceID,
+		&action.ResourceID,
+		&action.Type,
+		&action.Status,
+		&action.Conclusion,
+		&action.WorkflowRunID,
+		&action.Commit,
+		&action.Branch,
+		&buildHash,
+		&action.StartedAt,
+		&action.CompletedAt,
+		&action.CreatedAt,
+		&action.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last %s of service %d: %w", actionType, serviceID, err)
+	}
+	action.BuildHash = buildHash.String
+
+	data, err := json.Marshal(action)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode last %s of service %d: %w", actionType, serviceID, err)
+	}
+	value := string(data)
+	return &value, nil
+}
+
+// serviceEnvironments returns the most recently updated deployment of a service in each
+// environment, by environment name
+func serviceEnvironments(tx *sql.Tx, serviceID int64) ([]types.ServiceEnvironmentSummary, error) {
+	rows, err := tx.Query(`
+		SELECT environment, region, tag, updated_at
+		FROM deployments
+		WHERE service_id = ?
+		ORDER BY updated_at DESC, id DESC
+	`, serviceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deployments of service %d: %w", serviceID, err)
+	}
+	defer rows.Close()
+
+	environments := []types.ServiceEnvironmentSummary{}
+	index := make(map[string]int)
+	for rows.Next() {
+		var deployment types.ServiceEnvironmentSummary
+		if err := rows.Scan(&deployment.Environment, &deployment.Region, &deployment.Tag, &deployment.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan deployment of service %d: %w", serviceID, err)
+		}
+		if i, ok := index[deployment.Environment]; ok {
+			environments[i].Targets++
+			continue
+		}
+		deployment.Targets = 1
+		index[deployment.Environment] = len(environments)
+		environments = append(environments, deployment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get deployments of service %d: %w", serviceID, err)
+	}
+
+	sort.Slice(environments, func(i, j int) bool {
+		return environments[i].Environment < environments[j].Environment
+	})
+	return environments, nil
+}
+
+// SetOpenPullRequests records the number of open pull requests last fetched for a service
+func (m *ServiceSummaryModel) SetOpenPullRequests(serviceID int64, count int) error {
+	_, err := m.db.Exec(`
+		INSERT INTO service_summaries (service_id, open_pull_requests, updated_at)
+		SELECT id, ?, NULL FROM microservices WHERE id = ?
+		ON CONFLICT(service_id) DO UPDATE SET open_pull_requests = excluded.open_pull_requests
+	`, count, serviceID)
+	if err != nil {
+		return fmt.Errorf("failed to store open pull requests of service %d: %w", serviceID, err)
+	}
+	return nil
+}
+
+// SetTicketKeys records the JIRA issue keys the commits last fetched for a service mention. Its open
+// task count is derived from them when summaries are read, so task changes need no refresh.
+func (m *ServiceSummaryModel) SetTicketKeys(serviceID int64, keys []string) error {
+	if keys == nil {
+		keys = []string{}
+	}
+	data, err := json.Marshal(keys)
+	if err != nil {
+		return fmt.Errorf("failed to encode ticket keys of service %d: %w", serviceID, err)
+	}
+
+	_, err = m.db.Exec(`
+		INSERT INTO service_summaries (service_id, ticket_keys, updated_at)
+		SELECT id, ?, NULL FROM microservices WHERE id = ?
+		ON CONFLICT(service_id) DO UPDATE SET ticket_keys = excluded.ticket_keys
+	`, string(data), serviceID)
+	if err != nil {
+		return fmt.Errorf("failed to store ticket keys of service %d: %w", serviceID, err)
+	}
+	return nil
+}
+
+// Rebuild drops every summary, including pull request counts and ticket keys, and summarizes every
+// service again from the actions and deployments tables
+func (m *ServiceSummaryModel) Rebuild() error {
+	if _, err := m.db.Exec(`DELETE FROM service_summaries`); err != nil {
+		return fmt.Errorf("failed to delete service summaries: %w", err)
+	}
+	return m.Refresh(nil)
+}
+
+// GetByRepositoryID returns the services of a monorepo (or of all monorepos when repositoryID is 0)
+// with their summaries, ordered like GetMicroservices, leaving out hidden services unless
+// includeHidden is set. Services not summarized yet have an empty summary.
+func (m *ServiceSummaryModel) GetByRepositoryID(repositoryID int64, includeHidden bool) ([]*types.ServiceSummary, error) {
+	rows, err := m.db.Query(`
+		SELECT m.id, m.repository_id, m.name, m.path, m.description, m.domain, m.is_hidden, m.primary_environment, m.owner, m.has_readme, m.image_name, m.suggested_image_name, m.created_at, m.updated_at,
+			s.last_build, s.last_deployment, s.environments, s.open_pull_requests, s.ticket_keys IS NOT NULL,
+			(SELECT COUNT(DISTINCT t.id) FROM tasks t JOIN json_each(s.ticket_keys) k ON t.jira_ticket_id = k.value WHERE t.status != 'completed'),
+			s.updated_at
+		FROM microservices m
+		JOIN repositories r ON r.id = m.repository_id
+		LEFT JOIN service_summaries s ON s.service_id = m.id
+		WHERE r.type = ?1 AND (?2 = 0 OR m.repository_id = ?2) AND (?3 OR m.is_hidden = 0)
+		ORDER BY r.created_at DESC, m.name
+	`, types.MonorepoType, repositoryID, includeHidden)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service summaries: %w", err)
+	}
+	defer rows.Close()
+
+	summaries := []*types.ServiceSummary{}
+	for rows.Next() {
+		summary := &types.ServiceSummary{}
+		service := &summary.Microservice
+		var lastBuild, lastDeployment, environments sql.NullString
+		var openPullRequests sql.NullInt64
+		var hasTicketKeys sql.NullBool
+		var openTasks int
+		var summarizedAt sql.NullTime
+		err := rows.Scan(
+			&service.ID,
+			&service.RepositoryID,
+			&service.Name,
+			&service.Path,
+			&service.Description,
+			&service.Domain,
+			&service.IsHidden,
+			&service.PrimaryEnvironment,
+			&service.Owner,
+			&service.HasReadme,
+			&service.ImageName,
+			&service.SuggestedImageName,
+			&service.CreatedAt,
+			&service.UpdatedAt,
+			&lastBuild,
+			&lastDeployment,
+			&environments,
+			&openPullRequests,
+			&hasTicketKeys,
+			&openTasks,
+			&summarizedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan service summary: %w", err)
+		}
+
+		if lastBuild.Valid {
+			if err := json.Unmarshal([]byte(lastBuild.String), &summary.LastBuild); err != nil {
+				return nil, fmt.Errorf("failed to decode last build of service %d: %w", service.ID, err)
+			}
+		}
+		if lastDeployment.Valid {
+			if err := json.Unmarshal([]byte(lastDeployment.String), &summary.LastDeployment); err != nil {
+				return nil, fmt.Errorf("failed to decode last deployment of service %d: %w", service.ID, err)
+			}
+		}
+		summary.Environments = []types.ServiceEnvironmentSummary{}
+		if environments.Valid {
+			if err := json.Unmarshal([]byte(environments.String), &summary.Environments); err != nil {
+				return nil, fmt.Errorf("failed to decode environments of service %d: %w", service.ID, err)
+			}
+		}
+		if openPullRequests.Valid {
+			count := int(openPullRequests.Int64)
+			summary.OpenPullRequests = &count
+		}
+		if hasTicketKeys.Bool {
+			summary.OpenTasks = &openTasks
+		}
+		if summarizedAt.Valid {
+			summary.SummarizedAt = &summarizedAt.Time
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, rows.Err()
+}
@@ -0,0 +1,119 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// StatsDateFormat is the layout used for snapshot dates
+const StatsDateFormat = "2006-01-02"
+
+// statsMetricColumns maps trend metric names to their stats_snapshots column
+var statsMetricColumns = map[string]string{
+	"repositories":         "repositories",
+	"microservices":        "microservices",
+	"kubernetes_resources": "kubernetes_resources",
+	"open_tasks":           "open_tasks",
+	"failing_builds":       "failing_builds",
+}
+
+type StatsSnapshotModel struct {
+	db *sql.DB
+}
+
+func NewStatsSnapshotModel(db *sql.DB) *StatsSnapshotModel {
+	return &StatsSnapshotModel{db: db}
+}
+
+// Capture records the current counts for the given date. It is idempotent per date:
+// an existing snapshot for the day is left untouched.
+func (m *StatsSnapshotModel) Capture(date time.Time) error {
+	query := `
+		INSERT OR IGNORE INTO stats_snapshots (snapshot_date, repositories, microservices, kubernetes_resources, open_tasks, failing_builds)
+		SELECT ?,
+			(SELECT COUNT(*) FROM repositories),
+			(SELECT COUNT(*) FROM microservices WHERE is_hidden = 0),
+			(SELECT COUNT(*) FROM kubernetes_resources),
+			(SELECT COUNT(*) FROM tasks WHERE status != 'completed'),
+			(SELECT COUNT(*) FROM actions a
+				WHERE a.type = 'build' AND a.status = 'failure'
+				AND a.started_at = (
+					SELECT MAX(b.started_at) FROM actions b
+					WHERE b.type = 'build'
+					AND b.repository_id = a.repository_id
+					AND IFNULL(b.service_id, 0) = IFNULL(a.service_id, 0)
+				))
+	`
+
+	_, err := m.db.Exec(query, date.Format(StatsDateFormat))
+	if err != nil {
+		return fmt.Errorf("failed to capture stats snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// HasSnapshot reports whether a snapshot exists for the given date
+func (m *StatsSnapshotModel) HasSnapshot(date time.Time) (bool, error) {
+	var count int
+	err := m.db.QueryRow(`SELECT COUNT(*) FROM stats_snapshots WHERE snapshot_date = ?`, date.Format(StatsDateFormat)).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check stats snapshot: %w", err)
+	}
+	return count > 0, nil
+}
+
+// GetMetricSince returns the values of a metric keyed by snapshot date, starting from the given date
+func (m *StatsSnapshotModel) GetMetricSince(metric string, since time.Time) (map[string]int64, error) {
+	column, ok := statsMetricColumns[metric]
+	if !ok {
+		return nil, fmt.Errorf("unknown stats metric: %s", metric)
+	}
+
+	query := fmt.Sprintf(`SELECT snapshot_date, %s FROM stats_snapshots WHERE snapshot_date >= ? ORDER BY snapshot_date ASC`, column)
+
+	rows, err := m.db.Query(query, since.Format(StatsDateFormat))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stats snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	values := make(map[string]int64)
+	for rows.Next() {
+		var date string
+		var value int64
+		if err := rows.Scan(&date, &value); err != nil {
+			return nil, fmt.Errorf("failed to scan stats snapshot: %w", err)
+		}
+		values[normalizeSnapshotDate(date)] = value
+	}
+
+	return values, nil
+}
+
+// GetFirstSnapshotDate returns the date of the earliest snapshot, or nil if none exist yet
+func (m *StatsSnapshotModel) GetFirstSnapshotDate() (*time.Time, error) {
+	var date sql.NullString
+	err := m.db.QueryRow(`SELECT MIN(snapshot_date) FROM stats_snapshots`).Scan(&date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get first stats snapshot: %w", err)
+	}
+	if !date.Valid {
+		return nil, nil
+	}
+
+	first, err := time.ParseInLocation(StatsDateFormat, normalizeSnapshotDate(date.String), time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse stats snapshot date: %w", err)
+	}
+	return &first, nil
+}
+
+// normalizeSnapshotDate trims any time component the driver adds when reading DATE columns
+func normalizeSnapshotDate(date string) string {
+	if len(date) > len(StatsDateFormat) {
+		return date[:len(StatsDateFormat)]
+	}
+	return date
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"dev-dashboard/pkg/types"
+)
+
+type SyncLogModel struct {
+	db *sql.DB
+}
+
+func NewSyncLogModel(db *sql.DB) *SyncLogModel {
+	return &SyncLogModel{db: db}
+}
+
+func (m *SyncLogModel) Create(log *types.SyncLog) error {
+	query := `
+		INSERT INTO sync_logs (repository_id, level, message, created_at)
+		VALUES (?, ?, ?, ?)
+	`
+	log.CreatedAt = time.Now()
+
+	result, err := m.db.Exec(query, log.RepositoryID, log.Level, log.Message, log.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create sync log: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get sync log ID: %w", err)
+	}
+
+	log.ID = id
+	return nil
+}
+
+func (m *SyncLogModel) GetByRepositoryID(repositoryID int64, limit int) ([]*types.SyncLog, error) {
+	query := `
+		SELECT id, repository_id, level, message, created_at
+		FROM sync_logs
+		WHERE repository_id = ?
+		ORDER BY created_at DESC, id DESC
+		LIMIT ?
+	`
+
+	rows, err := m.db.Query(query, repositoryID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sync logs: %w", err)
+	}
+	defer rows.Close()
+
+	var logs []*types.SyncLog
+	for rows.Next() {
+		log := &types.SyncLog{}
+		err := rows.Scan(
+			&log.ID,
+			&log.RepositoryID,
+			&log.Level,
+			&log.Message,
+			&log.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan sync log: %w", err)
+		}
+		logs = append(logs, log)
+	}
+
+	return logs, nil
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -16,10 +17,29 @@ func NewTaskModel(db *sql.DB) *TaskModel {
 	return &TaskModel{db: db}
 }
 
+// jiraLabels scans the JSON array of a task's JIRA labels; NULL scans as no labels
+type jiraLabels []string
+
+func (l *jiraLabels) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*l = nil
+		return nil
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return fmt.Errorf("unexpected JIRA labels value %T", value)
+	}
+	return json.Unmarshal(data, (*[]string)(l))
+}
+
 func (m *TaskModel) Create(task *types.Task) error {
 	query := `
-		INSERT INTO tasks (project_id, jira_ticket_id, jira_title, title, description, scheduled_date, deadline, status, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO tasks (project_id, jira_ticket_id, jira_title, title, description, scheduled_date, deadline, status, priority, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 	now := time.Now()
 	task.CreatedAt = now
@@ -27,12 +47,15 @@ func (m *TaskModel) Create(task *types.Task) error {
 	if task.Status == "" {
 		task.Status = types.TaskPending
 	}
+	if task.Priority == "" {
+		task.Priority = types.TaskPriorityMedium
+	}
 
 	fmt.Printf("Executing query: %s\n", query)
 	fmt.Printf("With values: ProjectID=%d, JiraTicketID=%s, JiraTitle=%s, Title=%s, Description=%s, ScheduledDate=%v, Deadline=%v, Status=%s, CreatedAt=%v, UpdatedAt=%v\n", 
 		task.ProjectID, task.JiraTicketID, task.JiraTitle, task.Title, task.Description, task.ScheduledDate, task.Deadline, task.Status, task.CreatedAt, task.UpdatedAt)
 
-	result, err := m.db.Exec(query, task.ProjectID, task.JiraTicketID, task.JiraTitle, task.Title, task.Description, task.ScheduledDate, task.Deadline, task.Status, task.CreatedAt, task.UpdatedAt)
+	result, err := m.db.Exec(query, task.ProjectID, task.JiraTicketID, task.JiraTitle, task.Title, task.Description, task.ScheduledDate, task.Deadline, task.Status, task.Priority, task.CreatedAt, task.UpdatedAt)
 	if err != nil {
 		fmt.Printf("Database error: %v\n", err)
 		return fmt.Errorf("failed to create task: %w", err)
@@ -51,7 +74,8 @@ func (m *TaskModel) Create(task *types.Task) error {
 
 func (m *TaskModel) GetByID(id int64) (*types.Task, error) {
 	query := `
-		SELECT id, project_id, jira_ticket_id, jira_title, title, description, scheduled_date, deadline, status, created_at, updated_at
+		SELECT id, project_id, jira_ticket_id, jira_title, jira_status, jira_assignee, COALESCE(jira_assignee_name, ''), jira_due_date, COALESCE(jira_sprint, ''), jira_labels, COALESCE(jira_parent_key, ''), title, description, scheduled_date, deadline, status, priority, created_at, updated_at,
+			` + checklistCountColumns + `
 		FROM tasks
 		WHERE id = ?
 	`
@@ -62,24 +86,36 @@ func (m *TaskModel) GetByID(id int64) (*types.Task, error) {
 		&task.ProjectID,
 		&task.JiraTicketID,
 		&task.JiraTitle,
+		&task.JiraStatus,
+		&task.JiraAssignee,
+		&task.JiraAssigneeName,
+		&task.JiraDueDate,
+		&task.JiraSprint,
+		(*jiraLabels)(&task.JiraLabels),
+		&task.JiraParentKey,
 		&task.Title,
 		&task.Description,
 		&task.ScheduledDate,
 		&task.Deadline,
 		&task.Status,
+		&task.Priority,
 		&task.CreatedAt,
 		&task.UpdatedAt,
+		&task.ChecklistDone,
+		&task.ChecklistTotal,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get task: %w", err)
 	}
+	setChecklistCompletion(task)
 
 	return task, nil
 }
 
 func (m *TaskModel) GetByProjectID(projectID int64) ([]*types.Task, error) {
 	query := `
-		SELECT id, project_id, jira_ticket_id, jira_title, title, description, scheduled_date, deadline, status, created_at, updated_at
+		SELECT id, project_id, jira_ticket_id, jira_title, jira_status, jira_assignee, COALESCE(jira_assignee_name, ''), jira_due_date, COALESCE(jira_sprint, ''), jira_labels, COALESCE(jira_parent_key, ''), title, description, scheduled_date, deadline, status, priority, created_at, updated_at,
+			` + checklistCountColumns + `
 		FROM tasks
 		WHERE project_id = ?
 		ORDER BY 
@@ -102,17 +138,28 @@ func (m *TaskModel) GetByProjectID(projectID int64) ([]*types.Task, error) {
 			&task.ProjectID,
 			&task.JiraTicketID,
 			&task.JiraTitle,
+			&task.JiraStatus,
+			&task.JiraAssignee,
+			&task.JiraAssigneeName,
+			&task.JiraDueDate,
+			&task.JiraSprint,
+			(*jiraLabels)(&task.JiraLabels),
+			&task.JiraParentKey,
 			&task.Title,
 			&task.Description,
 			&task.ScheduledDate,
 			&task.Deadline,
 			&task.Status,
+			&task.Priority,
 			&task.CreatedAt,
 			&task.UpdatedAt,
+			&task.ChecklistDone,
+			&task.ChecklistTotal,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan task: %w", err)
 		}
+		setChecklistCompletion(task)
 		tasks = append(tasks, task)
 	}
 
@@ -121,7 +168,7 @@ func (m *TaskModel) GetByProjectID(projectID int64) ([]*types.Task, error) {
 
 func (m *TaskModel) GetAllWithProjects() ([]*types.TaskWithProject, error) {
 	query := `
-		SELECT t.id, t.project_id, t.jira_ticket_id, t.jira_title, t.title, t.description, t.scheduled_date, t.deadline, t.status, t.created_at, t.updated_at, p.name
+		SELECT t.id, t.project_id, t.jira_ticket_id, t.jira_title, t.jira_status, t.jira_assignee, COALESCE(t.jira_assignee_name, ''), t.jira_due_date, COALESCE(t.jira_sprint, ''), t.jira_labels, COALESCE(t.jira_parent_key, ''), t.title, t.description, t.scheduled_date, t.deadline, t.status, t.priority, t.created_at, t.updated_at, p.name
 		FROM tasks t
 		JOIN projects p ON t.project_id = p.id
 		ORDER BY t.deadline ASC
@@ -141,11 +188,19 @@ func (m *TaskModel) GetAllWithProjects() ([]*types.TaskWithProject, error) {
 			&task.ProjectID,
 			&task.JiraTicketID,
 			&task.JiraTitle,
+			&task.JiraStatus,
+			&task.JiraAssignee,
+			&task.JiraAssigneeName,
+			&task.JiraDueDate,
+			&task.JiraSprint,
+			(*jiraLabels)(&task.JiraLabels),
+			&task.JiraParentKey,
 			&task.Title,
 			&task.Description,
 			&task.ScheduledDate,
 			&task.Deadline,
 			&task.Status,
+			&task.Priority,
 			&task.CreatedAt,
 			&task.UpdatedAt,
 			&task.ProjectName,
@@ -161,7 +216,7 @@ func (m *TaskModel) GetAllWithProjects() ([]*types.TaskWithProject, error) {
 
 func (m *TaskModel) GetTasksInDateRange(startDate, endDate time.Time) ([]*types.TaskWithProject, error) {
 	query := `
-		SELECT t.id, t.project_id, t.jira_ticket_id, t.jira_title, t.title, t.description, t.scheduled_date, t.deadline, t.status, t.created_at, t.updated_at, p.name
+		SELECT t.id, t.project_id, t.jira_ticket_id, t.jira_title, t.jira_status, t.jira_assignee, COALESCE(t.jira_assignee_name, ''), t.jira_due_date, COALESCE(t.jira_sprint, ''), t.jira_labels, COALESCE(t.jira_parent_key, ''), t.title, t.description, t.scheduled_date, t.deadline, t.status, t.priority, t.created_at, t.updated_at, p.name
 		FROM tasks t
 		JOIN projects p ON t.project_id = p.id
 		WHERE t.deadline BETWEEN ? AND ?
@@ -182,11 +237,19 @@ func (m *TaskModel) GetTasksInDateRange(startDate, endDate time.Time) ([]*types.
 			&task.ProjectID,
 			&task.JiraTicketID,
 			&task.JiraTitle,
+			&task.JiraStatus,
+			&task.JiraAssignee,
+			&task.JiraAssigneeName,
+			&task.JiraDueDate,
+			&task.JiraSprint,
+			(*jiraLabels)(&task.JiraLabels),
+			&task.JiraParentKey,
 			&task.Title,
 			&task.Description,
 			&task.ScheduledDate,
 			&task.Deadline,
 			&task.Status,
+			&task.Priority,
 			&task.CreatedAt,
 			&task.UpdatedAt,
 			&task.ProjectName,
@@ -203,12 +266,15 @@ func (m *TaskModel) GetTasksInDateRange(startDate, endDate time.Time) ([]*types.
 func (m *TaskModel) Update(task *types.Task) error {
 	query := `
 		UPDATE tasks
-		SET project_id = ?, jira_ticket_id = ?, jira_title = ?, title = ?, description = ?, scheduled_date = ?, deadline = ?, status = ?, updated_at = ?
+		SET project_id = ?, jira_ticket_id = ?, jira_title = ?, title = ?, description = ?, scheduled_date = ?, deadline = ?, status = ?, priority = ?, updated_at = ?
 		WHERE id = ?
 	`
 	
+	if task.Priority == "" {
+		task.Priority = types.TaskPriorityMedium
+	}
 	task.UpdatedAt = time.Now()
-	_, err := m.db.Exec(query, task.ProjectID, task.JiraTicketID, task.JiraTitle, task.Title, task.Description, task.ScheduledDate, task.Deadline, task.Status, task.UpdatedAt, task.ID)
+	_, err := m.db.Exec(query, task.ProjectID, task.JiraTicketID, task.JiraTitle, task.Title, task.Description, task.ScheduledDate, task.Deadline, task.Status, task.Priority, task.UpdatedAt, task.ID)
 	if err != nil {
 		return fmt.Errorf("failed to update task: %w", err)
 	}
@@ -248,9 +314,96 @@ func (m *TaskModel) UpdateJiraTitle(id int64, jiraTitle string) error {
 	return nil
 }
 
+// GetWithJiraTickets returns every task linked to a JIRA ticket, ordered by ticket key
+func (m *TaskModel) GetWithJiraTickets() ([]*types.Task, error) {
+	query := `
+		SELECT id, project_id, jira_ticket_id, jira_title, jira_status, jira_assignee, COALESCE(jira_assignee_name, ''), jira_due_date, COALESCE(jira_sprint, ''), jira_labels, COALESCE(jira_parent_key, ''), title, description, scheduled_date, deadline, status, priority, created_at, updated_at
+		FROM tasks
+		WHERE jira_ticket_id != ''
+		ORDER BY jira_ticket_id, id
+	`
+
+	rows, err := m.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tasks with JIRA tickets: %w", err)
+	}
+	defer rows.Close()
+
+	tasks := []*types.Task{}
+	for rows.Next() {
+		task := &types.Task{}
+		err := rows.Scan(
+			&task.ID,
+			&task.ProjectID,
+			&task.JiraTicketID,
+			&task.JiraTitle,
+			&task.JiraStatus,
+			&task.JiraAssignee,
+			&task.JiraAssigneeName,
+			&task.JiraDueDate,
+			&task.JiraSprint,
+			(*jiraLabels)(&task.JiraLabels),
+			&task.JiraParentKey,
+			&task.Title,
+			&task.Description,
+			&task.ScheduledDate,
+			&task.Deadline,
+			&task.Status,
+			&task.Priority,
+			&task.CreatedAt,
+			&task.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan task: %w", err)
+		}
+		tasks = append(tasks, task)
+	}
+
+	return tasks, nil
+}
+
+// UpdateJiraFields stores the ticket fields of a task: its summary, status, assignee and card details
+func (m *TaskModel) UpdateJiraFields(task *types.Task) error {
+	labels, err := json.Marshal(task.JiraLabels)
+	if err != nil {
+		return fmt.Errorf("failed to encode JIRA labels: %w", err)
+	}
+
+	query := `
+		UPDATE tasks
+		SET jira_title = ?, jira_status = ?, jira_assignee = ?, jira_assignee_name = ?, jira_due_date = ?,
+			jira_sprint = ?, jira_labels = ?, jira_parent_key = ?, updated_at = ?
+		WHERE id = ?
+	`
+
+	_, err = m.db.Exec(query, task.JiraTitle, task.JiraStatus, task.JiraAssignee, task.JiraAssigneeName, task.JiraDueDate,
+		task.JiraSprint, string(labels), task.JiraParentKey, time.Now(), task.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update JIRA fields: %w", err)
+	}
+
+	return nil
+}
+
+// FillDeadline sets the deadline of a task that has none; a deadline set meanwhile is kept
+func (m *TaskModel) FillDeadline(id int64, deadline time.Time) error {
+	query := `
+		UPDATE tasks
+		SET deadline = ?, updated_at = ?
+		WHERE id = ? AND deadline IS NULL
+	`
+
+	_, err := m.db.Exec(query, deadline, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to fill task deadline: %w", err)
+	}
+
+	return nil
+}
+
 func (m *TaskModel) GetTasksGroupedByScheduledDate() ([]*types.TaskWithProject, error) {
 	query := `
-		SELECT t.id, t.project_id, t.jira_ticket_id, t.jira_title, t.title, t.description, t.scheduled_date, t.deadline, t.status, t.created_at, t.updated_at, p.name
+		SELECT t.id, t.project_id, t.jira_ticket_id, t.jira_title, t.jira_status, t.jira_assignee, COALESCE(t.jira_assignee_name, ''), t.jira_due_date, COALESCE(t.jira_sprint, ''), t.jira_labels, COALESCE(t.jira_parent_key, ''), t.title, t.description, t.scheduled_date, t.deadline, t.status, t.priority, t.created_at, t.updated_at, p.name
 		FROM tasks t
 		JOIN projects p ON t.project_id = p.id
 		ORDER BY 
@@ -273,11 +426,19 @@ func (m *TaskModel) GetTasksGroupedByScheduledDate() ([]*types.TaskWithProject,
 			&task.ProjectID,
 			&task.JiraTicketID,
 			&task.JiraTitle,
+			&task.JiraStatus,
+			&task.JiraAssignee,
+			&task.JiraAssigneeName,
+			&task.JiraDueDate,
+			&task.JiraSprint,
+			(*jiraLabels)(&task.JiraLabels),
+			&task.JiraParentKey,
 			&task.Title,
 			&task.Description,
 			&task.ScheduledDate,
 			&task.Deadline,
 			&task.Status,
+			&task.Priority,
 			&task.CreatedAt,
 			&task.UpdatedAt,
 			&task.ProjectName,
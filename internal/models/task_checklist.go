@@ -0,0 +1,146 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"dev-dashboard/pkg/types"
+)
+
+// TaskChecklistModel stores the checklist items of tasks, ordered by position within each task
+type TaskChecklistModel struct {
+	db *sql.DB
+}
+
+func NewTaskChecklistModel(db *sql.DB) *TaskChecklistModel {
+	return &TaskChecklistModel{db: db}
+}
+
+// checklistCountColumns selects the done and total checklist items of the task aliased tasks
+const checklistCountColumns = `(SELECT COUNT(*) FROM task_checklist_items c WHERE c.task_id = tasks.id AND c.done),
+	(SELECT COUNT(*) FROM task_checklist_items c WHERE c.task_id = tasks.id)`
+
+// setChecklistCompletion computes the task's completion ratio from its checklist counts
+func setChecklistCompletion(task *types.Task) {
+	task.ChecklistCompletion = 0
+	if task.ChecklistTotal > 0 {
+		task.ChecklistCompletion = float64(task.ChecklistDone) / float64(task.ChecklistTotal)
+	}
+}
+
+const checklistItemColumns = `id, task_id, text, done, position, created_at, updated_at`
+
+func scanChecklistItem(row interface{ Scan(...interface{}) error }) (*types.TaskChecklistItem, error) {
+	item := &types.TaskChecklistItem{}
+	err := row.Scan(&item.ID, &item.TaskID, &item.Text, &item.Done, &item.Position, &item.CreatedAt, &item.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// GetByTaskID returns a task's checklist items in order
+func (m *TaskChecklistModel) GetByTaskID(taskID int64) ([]*types.TaskChecklistItem, error) {
+	rows, err := m.db.Query(`SELECT `+checklistItemColumns+` FROM task_checklist_items WHERE task_id = ? ORDER BY position, id`, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query checklist items: %w", err)
+	}
+	defer rows.Close()
+
+	items := []*types.TaskChecklistItem{}
+	for rows.Next() {
+		item, err := scanChecklistItem(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan checklist item: %w", err)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// GetByID returns a checklist item, or nil when it doesn't exist
+func (m *TaskChecklistModel) GetByID(id int64) (*types.TaskChecklistItem, error) {
+	item, err := scanChecklistItem(m.db.QueryRow(`SELECT `+checklistItemColumns+` FROM task_checklist_items WHERE id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get checklist item: %w", err)
+	}
+	return item, nil
+}
+
+// Add appends an item to the end of a task's checklist and sets its ID and position
+func (m *TaskChecklistModel) Add(item *types.TaskChecklistItem) error {
+	now := time.Now()
+	item.CreatedAt = now
+	item.UpdatedAt = now
+
+	result, err := m.db.Exec(`INSERT INTO task_checklist_items (task_id, text, done, position, created_at, updated_at)
+		VALUES (?, ?, ?, (SELECT COALESCE(MAX(position) + 1, 0) FROM task_checklist_items WHERE task_id = ?), ?, ?)`,
+		item.TaskID, item.Text, item.Done, item.TaskID, item.CreatedAt, item.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to add checklist item: %w", err)
+	}
+	if item.ID, err = result.LastInsertId(); err != nil {
+		return fmt.Errorf("failed to get checklist item ID: %w", err)
+	}
+	return m.db.QueryRow(`SELECT position FROM task_checklist_items WHERE id = ?`, item.ID).Scan(&item.Position)
+}
+
+// Toggle flips whether an item is done and returns the updated item, or nil when it doesn't exist
+func (m *TaskChecklistModel) Toggle(id int64) (*types.TaskChecklistItem, error) {
+	_, err := m.db.Exec(`UPDATE task_checklist_items SET done = NOT done, updated_at = ? WHERE id = ?`, time.Now(), id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to toggle checklist item: %w", err)
+	}
+	return m.GetByID(id)
+}
+
+// Reorder sets the order of a task's checklist to itemIDs, which must list each of its items once
+func (m *TaskChecklistModel) Reorder(taskID int64, itemIDs []int64) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var count int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM task_checklist_items WHERE task_id = ?`, taskID).Scan(&count); err != nil {
+		return fmt.Errorf("failed to count checklist items: %w", err)
+	}
+	if count != len(itemIDs) {
+		return fmt.Errorf("task %d has %d checklist items, got %d", taskID, count, len(itemIDs))
+	}
+
+	seen := make(map[int64]bool, len(itemIDs))
+	now := time.Now()
+	for position, id := range itemIDs {
+		if seen[id] {
+			return fmt.Errorf("checklist item %d is listed twice", id)
+		}
+		seen[id] = true
+
+		result, err := tx.Exec(`UPDATE task_checklist_items SET position = ?, updated_at = ? WHERE id = ? AND task_id = ?`, position, now, id, taskID)
+		if err != nil {
+			return fmt.Errorf("failed to reorder checklist item: %w", err)
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			return fmt.Errorf("checklist item %d doesn't belong to task %d", id, taskID)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a checklist item; the positions of the remaining items keep their order
+func (m *TaskChecklistModel) Delete(id int64) error {
+	if _, err := m.db.Exec(`DELETE FROM task_checklist_items WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete checklist item: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package models_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"dev-dashboard/internal/models"
+	"dev-dashboard/internal/testsupport"
+	"dev-dashboard/pkg/types"
+)
+
+func day(year int, month time.Month, d int) *time.Time {
+	date := time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+	return &date
+}
+
+func withDeadline(deadline *time.Time) func(*types.Task) {
+	return func(task *types.Task) { task.Deadline = deadline }
+}
+
+func withScheduledDate(date *time.Time) func(*types.Task) {
+	return func(task *types.Task) { task.ScheduledDate = date }
+}
+
+func taskTitles(tasks []*types.TaskWithProject) []string {
+	titles := make([]string, len(tasks))
+	for i, task := range tasks {
+		titles[i] = task.Title
+	}
+	return titles
+}
+
+func TestGetTasksInDateRangeIncludesBounds(t *testing.T) {
+	db := testsupport.NewTestDB(t)
+	project := testsupport.Project(t, db)
+	start, end := day(2024, time.March, 4), day(2024, time.March, 10)
+
+	before := testsupport.Task(t, db, project.ID, withDeadline(day(2024, time.March, 3)))
+	first := testsupport.Task(t, db, project.ID, withDeadline(start))
+	middle := testsupport.Task(t, db, project.ID, withDeadline(day(2024, time.March, 7)))
+	last := testsupport.Task(t, db, project.ID, withDeadline(end))
+	after := testsupport.Task(t, db, project.ID, withDeadline(day(2024, time.March, 11)))
+	undated := testsupport.Task(t, db, project.ID)
+
+	tasks, err := models.NewTaskModel(db).GetTasksInDateRange(*start, *end)
+	if err != nil {
+		t.Fatalf("GetTasksInDateRange: %v", err)
+	}
+	got := taskTitles(tasks)
+	if want := []string{first.Title, middle.Title, last.Title}; !slices.Equal(got, want) {
+		t.Errorf("got tasks %v, want %v (not %s, %s or %s)", got, want, before.Title, after.Title, undated.Title)
+	}
+	for _, task := range tasks {
+		if task.ProjectName != project.Name {
+			t.Errorf("task %s has project %q, want %q", task.Title, task.ProjectName, project.Name)
+		}
+	}
+}
+
+func TestGetTasksGroupedByScheduledDateOrdersUnscheduledLast(t *testing.T) {
+	db := testsupport.NewTestDB(t)
+	project := testsupport.Project(t, db)
+
+	unscheduled := testsupport.Task(t, db, project.ID, withScheduledDate(nil))
+	later := testsupport.Task(t, db, project.ID, withScheduledDate(day(2024, time.March, 9)))
+	earlier := testsupport.Task(t, db, project.ID, withScheduledDate(day(2024, time.March, 2)))
+
+	tasks, err := models.NewTaskModel(db).GetTasksGroupedByScheduledDate()
+	if err != nil {
+		t.Fatalf("GetTasksGroupedByScheduledDate: %v", err)
+	}
+	if got, want := taskTitles(tasks), []string{earlier.Title, later.Title, unscheduled.Title}; !slices.Equal(got, want) {
+		t.Errorf("got tasks %v, want %v", got, want)
+	}
+}
+
+func TestFillDeadlineOnlySetsMissingDeadlines(t *testing.T) {
+	db := testsupport.NewTestDB(t)
+	project := testsupport.Project(t, db)
+	model := models.NewTaskModel(db)
+
+	open := testsupport.Task(t, db, project.ID)
+	set := testsupport.Task(t, db, project.ID, withDeadline(day(2024, time.March, 1)))
+	for _, task := range []*types.Task{open, set} {
+		if err := model.FillDeadline(task.ID, *day(2024, time.April, 1)); err != nil {
+			t.Fatalf("FillDeadline: %v", err)
+		}
+	}
+
+	for task, want := range map[*types.Task]*time.Time{open: day(2024, time.April, 1), set: day(2024, time.March, 1)} {
+		stored, err := model.GetByID(task.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if stored.Deadline == nil || !stored.Deadline.Equal(*want) {
+			t.Errorf("task %s has deadline %v, want %v", task.Title, stored.Deadline, want)
+		}
+	}
+}
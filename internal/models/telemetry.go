@@ -0,0 +1,81 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+
+	"dev-dashboard/pkg/types"
+)
+
+// TelemetryModel stores how often each feature was used, for opt-in telemetry
+type TelemetryModel struct {
+	db *sql.DB
+}
+
+func NewTelemetryModel(db *sql.DB) *TelemetryModel {
+	return &TelemetryModel{db: db}
+}
+
+// Increment counts one use of a feature
+func (m *TelemetryModel) Increment(feature string) error {
+	_, err := m.db.Exec(`
+		INSERT INTO telemetry_counters (feature, count, last_used_at)
+		VALUES (?, 1, CURRENT_TIMESTAMP)
+		ON CONFLICT(feature) DO UPDATE SET count = count + 1, last_used_at = CURRENT_TIMESTAMP
+	`, feature)
+	if err != nil {
+		return fmt.Errorf("failed to count feature use: %w", err)
+	}
+	return nil
+}
+
+// GetAll returns the counters of all features used so far, ordered by feature
+func (m *TelemetryModel) GetAll() ([]types.TelemetryCounter, error) {
+	rows, err := m.db.Query(`
+		SELECT feature, count, count - flushed_count, last_used_at
+		FROM telemetry_counters
+		ORDER BY feature
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get telemetry counters: %w", err)
+	}
+	defer rows.Close()
+
+	counters := []types.TelemetryCounter{}
+	for rows.Next() {
+		var counter types.TelemetryCounter
+		if err := rows.Scan(&counter.Feature, &counter.Count, &counter.Pending, &counter.LastUsedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan telemetry counter: %w", err)
+		}
+		counters = append(counters, counter)
+	}
+	return counters, rows.Err()
+}
+
+// MarkFlushed records that the given number of uses of each feature were sent. Uses counted
+// while the flush was in flight stay pending.
+func (m *TelemetryModel) MarkFlushed(sent map[string]int64) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for feature, count := range sent {
+		_, err := tx.Exec(`
+			UPDATE telemetry_counters SET flushed_count = MIN(count, flushed_count + ?) WHERE feature = ?
+		`, count, feature)
+		if err != nil {
+			return fmt.Errorf("failed to mark telemetry flushed: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// Clear deletes all counters
+func (m *TelemetryModel) Clear() error {
+	if _, err := m.db.Exec("DELETE FROM telemetry_counters"); err != nil {
+		return fmt.Errorf("failed to clear telemetry counters: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"dev-dashboard/pkg/types"
+)
+
+// weightedMinutesExpr applies GitHub's runner multipliers to the recorded billable milliseconds
+const weightedMinutesExpr = `(SUM(u.ubuntu_ms + u.other_ms) + 2.0 * SUM(u.windows_ms) + 10.0 * SUM(u.macos_ms)) / 60000.0`
+
+const billableMinutesExpr = `SUM(u.ubuntu_ms + u.macos_ms + u.windows_ms + u.other_ms) / 60000.0`
+
+type ActionsUsageModel struct {
+	db *sql.DB
+}
+
+func NewActionsUsageModel(db *sql.DB) *ActionsUsageModel {
+	return &ActionsUsageModel{db: db}
+}
+
+// GetRecordedRunIDs returns the workflow runs of a repository whose usage is already stored
+func (m *ActionsUsageModel) GetRecordedRunIDs(repositoryID int64) (map[int64]bool, error) {
+	rows, err := m.db.Query(`SELECT workflow_run_id FROM actions_usage WHERE repository_id = ?`, repositoryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query recorded runs: %w", err)
+	}
+	defer rows.Close()
+
+	recorded := make(map[int64]bool)
+	for rows.Next() {
+		var runID int64
+		if err := rows.Scan(&runID); err != nil {
+			return nil, fmt.Errorf("failed to scan recorded run: %w", err)
+		}
+		recorded[runID] = true
+	}
+
+	return recorded, nil
+}
+
+func (m *ActionsUsageModel) Create(usage *types.ActionsRunUsage) error {
+	query := `
+		INSERT OR IGNORE INTO actions_usage
+		(repository_id, workflow_id, workflow_name, workflow_run_id, run_started_at, run_duration_ms, ubuntu_ms, macos_ms, windows_ms, other_ms, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := m.db.Exec(query,
+		usage.RepositoryID,
+		usage.WorkflowID,
+		usage.WorkflowName,
+		usage.WorkflowRunID,
+		usage.RunStartedAt,
+		usage.RunDurationMS,
+		usage.UbuntuMS,
+		usage.MacOSMS,
+		usage.WindowsMS,
+		usage.OtherMS,
+		time.Now(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create actions usage: %w", err)
+	}
+
+	return nil
+}
+
+// GetSummary aggregates usage of runs started since the given time, listing the topWorkflows most expensive workflows
+func (m *ActionsUsageModel) GetSummary(since time.Time, topWorkflows int) (*types.ActionsUsageSummary, error) {
+	summary := &types.ActionsUsageSummary{
+		Since:        since,
+		Repositories: []types.ActionsUsageBreakdown{},
+		TopWorkflows: []types.ActionsUsageBreakdown{},
+	}
+
+	query := fmt.Sprintf(`
+		SELECT COUNT(*), COALESCE(%s, 0), COALESCE(%s, 0)
+		FROM actions_usage u
+		WHERE u.run_started_at >= ?
+	`, billableMinutesExpr, weightedMinutesExpr)
+	err := m.db.QueryRow(query, since).Scan(&summary.Runs, &summary.BillableMinutes, &summary.WeightedMinutes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get actions usage totals: %w", err)
+	}
+
+	query = fmt.Sprintf(`
+		SELECT u.repository_id, r.name, COUNT(*), %s, %s
+		FROM actions_usage u
+		JOIN repositories r ON u.repository_id = r.id
+		WHERE u.run_started_at >= ?
+		GROUP BY u.repository_id, r.name
+		ORDER BY 5 DESC
+	`, billableMinutesExpr, weightedMinutesExpr)
+	summary.Repositories, err = m.queryBreakdown(query, false, since)
+	if err != nil {
+		return nil, err
+	}
+
+	query = fmt.Sprintf(`
+		SELECT u.repository_id, r.name, u.workflow_name, COUNT(*), %s, %s
+		FROM actions_usage u
+		JOIN repositories r ON u.repository_id = r.id
+		WHERE u.run_started_at >= ?
+		GROUP BY u.repository_id, r.name, u.workflow_id, u.workflow_name
+		ORDER BY 6 DESC
+		LIMIT ?
+	`, billableMinutesExpr, weightedMinutesExpr)
+	summary.TopWorkflows, err = m.queryBreakdown(query, true, since, topWorkflows)
+	if err != nil {
+		return nil, err
+	}
+
+	return summary, nil
+}
+
+func (m *ActionsUsageModel) queryBreakdown(query string, withWorkflow bool, args ...interface{}) ([]types.ActionsUsageBreakdown, error) {
+	rows, err := m.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query actions usage: %w", err)
+	}
+	defer rows.Close()
+
+	breakdowns := []types.ActionsUsageBreakdown{}
+	for rows.Next() {
+		var b types.ActionsUsageBreakdown
+		dest := []interface{}{&b.RepositoryID, &b.RepositoryName}
+		if withWorkflow {
+			dest = append(dest, &b.WorkflowName)
+		}
+		dest = append(dest, &b.Runs, &b.BillableMinutes, &b.WeightedMinutes)
+
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan actions usage: %w", err)
+		}
+		breakdowns = append(breakdowns, b)
+	}
+
+	return breakdowns, nil
+}
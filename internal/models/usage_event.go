@@ -0,0 +1,81 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"dev-dashboard/pkg/types"
+)
+
+// usageEventDedupeWindow keeps refreshes of an open page from counting as new views
+const usageEventDedupeWindow = time.Minute
+
+type UsageEventModel struct {
+	db *sql.DB
+}
+
+func NewUsageEventModel(db *sql.DB) *UsageEventModel {
+	return &UsageEventModel{db: db}
+}
+
+// Record stores a view unless the same view was already recorded within the dedupe window
+func (m *UsageEventModel) Record(event types.UsageEventType, serviceID *int64) error {
+	query := `
+		INSERT INTO usage_events (event, service_id, created_at)
+		SELECT ?, ?, ?
+		WHERE NOT EXISTS (
+			SELECT 1 FROM usage_events
+			WHERE event = ? AND service_id IS ? AND created_at >= ?
+		)
+	`
+	now := time.Now()
+
+	_, err := m.db.Exec(query, event, serviceID, now, event, serviceID, now.Add(-usageEventDedupeWindow))
+	if err != nil {
+		return fmt.Errorf("failed to record usage event: %w", err)
+	}
+	return nil
+}
+
+// GetSince returns the events recorded since the given time, oldest first
+func (m *UsageEventModel) GetSince(since time.Time) ([]*types.UsageEvent, error) {
+	query := `
+		SELECT id, event, service_id, created_at
+		FROM usage_events
+		WHERE created_at >= ?
+		ORDER BY created_at, id
+	`
+
+	rows, err := m.db.Query(query, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query usage events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []*types.UsageEvent{}
+	for rows.Next() {
+		event := &types.UsageEvent{}
+		if err := rows.Scan(&event.ID, &event.Event, &event.ServiceID, &event.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan usage event: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+// Clear deletes all usage events and vacuums the database so the deleted rows don't linger in
+// free pages of the database file
+func (m *UsageEventModel) Clear() error {
+	if _, err := m.db.Exec("DELETE FROM usage_events"); err != nil {
+		return fmt.Errorf("failed to delete usage events: %w", err)
+	}
+	if _, err := m.db.Exec("DELETE FROM sqlite_sequence WHERE name = 'usage_events'"); err != nil {
+		return fmt.Errorf("failed to reset usage event IDs: %w", err)
+	}
+	if _, err := m.db.Exec("VACUUM"); err != nil {
+		return fmt.Errorf("failed to vacuum database: %w", err)
+	}
+	return nil
+}
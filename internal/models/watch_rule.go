@@ -0,0 +1,268 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"dev-dashboard/pkg/types"
+)
+
+// watchEvaluationsKept is how many evaluations of each watch rule are kept in its log
+const watchEvaluationsKept = 100
+
+// WatchRuleModel stores watch rules, the state their conditions were in at the last evaluation
+// and a log of their recent evaluations
+type WatchRuleModel struct {
+	db *sql.DB
+}
+
+func NewWatchRuleModel(db *sql.DB) *WatchRuleModel {
+	return &WatchRuleModel{db: db}
+}
+
+const watchRuleColumns = `id, name, enabled, scope, service_ids, scope_field, scope_value, environments, conditions,
+	last_evaluated_at, last_triggered_at, created_at`
+
+func scanWatchRule(row interface{ Scan(...interface{}) error }) (*types.WatchRule, error) {
+	rule := &types.WatchRule{}
+	var serviceIDs, environments, conditions string
+	err := row.Scan(&rule.ID, &rule.Name, &rule.Enabled, &rule.Scope, &serviceIDs, &rule.ScopeField, &rule.ScopeValue,
+		&environments, &conditions, &rule.LastEvaluatedAt, &rule.LastTriggeredAt, &rule.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(serviceIDs), &rule.ServiceIDs); err != nil {
+		return nil, fmt.Errorf("invalid service IDs of watch rule %d: %w", rule.ID, err)
+	}
+	if err := json.Unmarshal([]byte(environments), &rule.Environments); err != nil {
+		return nil, fmt.Errorf("invalid environments of watch rule %d: %w", rule.ID, err)
+	}
+	if err := json.Unmarshal([]byte(conditions), &rule.Conditions); err != nil {
+		return nil, fmt.Errorf("invalid conditions of watch rule %d: %w", rule.ID, err)
+	}
+	return rule, nil
+}
+
+// encodeWatchRule returns the JSON columns of a rule
+func encodeWatchRule(rule *types.WatchRule) (serviceIDs, environments, conditions string, err error) {
+	ids := rule.ServiceIDs
+	if ids == nil {
+		ids = []int64{}
+	}
+	envs := rule.Environments
+	if envs == nil {
+		envs = []string{}
+	}
+	encoded := make([][]byte, 3)
+	for i, value := range []interface{}{ids, envs, rule.Conditions} {
+		if encoded[i], err = json.Marshal(value); err != nil {
+			return "", "", "", fmt.Errorf("failed to encode watch rule: %w", err)
+		}
+	}
+	return string(encoded[0]), string(encoded[1]), string(encoded[2]), nil
+}
+
+// Create stores a rule and sets its ID and creation time
+func (m *WatchRuleModel) Create(rule *types.WatchRule) error {
+	serviceIDs, environments, conditions, err := encodeWatchRule(rule)
+	if err != nil {
+		return err
+	}
+	rule.CreatedAt = time.Now()
+	result, err := m.db.Exec(`
+		INSERT INTO watch_rules (name, enabled, scope, service_ids, scope_field, scope_value, environments, conditions, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, rule.Name, rule.Enabled, rule.Scope, serviceIDs, rule.ScopeField, rule.ScopeValue, environments, conditions, rule.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create watch rule: %w", err)
+	}
+	rule.ID, err = result.LastInsertId()
+	return err
+}
+
+// Update changes a rule's name, scope, environments and conditions. The state its conditions were
+// in is forgotten, so conditions that hold are reported again at the next evaluation.
+func (m *WatchRuleModel) Update(rule *types.WatchRule) error {
+	serviceIDs, environments, conditions, err := encodeWatchRule(rule)
+	if err != nil {
+		return err
+	}
+	result, err := m.db.Exec(`
+		UPDATE watch_rules
+		SET name = ?, enabled = ?, scope = ?, service_ids = ?, scope_field = ?, scope_value = ?, environments = ?,
+			conditions = ?, active_keys = '[]'
+		WHERE id = ?
+	`, rule.Name, rule.Enabled, rule.Scope, serviceIDs, rule.ScopeField, rule.ScopeValue, environments, conditions, rule.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update watch rule: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("watch rule %d not found", rule.ID)
+	}
+	return nil
+}
+
+// SetEnabled turns a rule on or off. A rule turned back on only reports what happens from then on.
+func (m *WatchRuleModel) SetEnabled(id int64, enabled bool) error {
+	query := `UPDATE watch_rules SET enabled = ? WHERE id = ?`
+	args := []interface{}{enabled, id}
+	if enabled {
+		query = `UPDATE watch_rules SET enabled = ?, last_evaluated_at = ? WHERE id = ?`
+		args = []interface{}{enabled, time.Now(), id}
+	}
+	result, err := m.db.Exec(query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update watch rule: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("watch rule %d not found", id)
+	}
+	return nil
+}
+
+// Delete removes a rule along with its evaluation log
+func (m *WatchRuleModel) Delete(id int64) error {
+	if _, err := m.db.Exec(`DELETE FROM watch_rules WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete watch rule: %w", err)
+	}
+	return nil
+}
+
+// GetByID returns a rule
+func (m *WatchRuleModel) GetByID(id int64) (*types.WatchRule, error) {
+	rule, err := scanWatchRule(m.db.QueryRow(`SELECT `+watchRuleColumns+` FROM watch_rules WHERE id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("watch rule %d not found", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get watch rule: %w", err)
+	}
+	return rule, nil
+}
+
+// GetAll returns every rule, in the order they were created
+func (m *WatchRuleModel) GetAll() ([]*types.WatchRule, error) {
+	rows, err := m.db.Query(`SELECT ` + watchRuleColumns + ` FROM watch_rules ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query watch rules: %w", err)
+	}
+	defer rows.Close()
+
+	rules := []*types.WatchRule{}
+	for rows.Next() {
+		rule, err := scanWatchRule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan watch rule: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
+
+// GetActiveKeys returns the keys of the state conditions that held at a rule's last evaluation
+func (m *WatchRuleModel) GetActiveKeys(id int64) (map[string]bool, error) {
+	var value string
+	if err := m.db.QueryRow(`SELECT active_keys FROM watch_rules WHERE id = ?`, id).Scan(&value); err != nil {
+		return nil, fmt.Errorf("failed to get watch rule state: %w", err)
+	}
+	var keys []string
+	if err := json.Unmarshal([]byte(value), &keys); err != nil {
+		return nil, fmt.Errorf("invalid state of watch rule %d: %w", id, err)
+	}
+	active := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		active[key] = true
+	}
+	return active, nil
+}
+
+// RecordEvaluation stores the outcome of evaluating a rule: its evaluation time, the state
+// conditions that held, when it last fired, and the log entry. A failed evaluation is only logged.
+// Only the most recent watchEvaluationsKept entries of the rule are kept.
+func (m *WatchRuleModel) RecordEvaluation(evaluation *types.WatchEvaluation, activeKeys []string) error {
+	if activeKeys == nil {
+		activeKeys = []string{}
+	}
+	keys, err := json.Marshal(activeKeys)
+	if err != nil {
+		return fmt.Errorf("failed to encode watch rule state: %w", err)
+	}
+	outcomes, err := json.Marshal(evaluation.Outcomes)
+	if err != nil {
+		return fmt.Errorf("failed to encode watch rule outcomes: %w", err)
+	}
+
+	tx, err := m.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// A failed evaluation leaves the window and state alone so the next one catches up
+	if evaluation.Error == "" {
+		_, err := tx.Exec(`
+			UPDATE watch_rules
+			SET last_evaluated_at = ?1, active_keys = ?2,
+				last_triggered_at = CASE WHEN ?3 > 0 THEN ?1 ELSE last_triggered_at END
+			WHERE id = ?4
+		`, evaluation.EvaluatedAt, string(keys), evaluation.Fired, evaluation.RuleID)
+		if err != nil {
+			return fmt.Errorf("failed to update watch rule: %w", err)
+		}
+	}
+
+	result, err := tx.Exec(`
+		INSERT INTO watch_rule_evaluations (rule_id, evaluated_at, services, fired, outcomes, error)
+		VALUES (?, ?, ?, ?, ?, NULLIF(?, ''))
+	`, evaluation.RuleID, evaluation.EvaluatedAt, evaluation.Services, evaluation.Fired, string(outcomes), evaluation.Error)
+	if err != nil {
+		return fmt.Errorf("failed to record watch rule evaluation: %w", err)
+	}
+	if evaluation.ID, err = result.LastInsertId(); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		DELETE FROM watch_rule_evaluations
+		WHERE rule_id = ?1 AND id NOT IN (
+			SELECT id FROM watch_rule_evaluations WHERE rule_id = ?1 ORDER BY evaluated_at DESC, id DESC LIMIT ?2
+		)
+	`, evaluation.RuleID, watchEvaluationsKept)
+	if err != nil {
+		return fmt.Errorf("failed to prune watch rule evaluations: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// GetEvaluations returns the most recent evaluations of a rule, newest first
+func (m *WatchRuleModel) GetEvaluations(ruleID int64, limit int) ([]*types.WatchEvaluation, error) {
+	rows, err := m.db.Query(`
+		SELECT id, rule_id, evaluated_at, services, fired, outcomes, COALESCE(error, '')
+		FROM watch_rule_evaluations
+		WHERE rule_id = ?
+		ORDER BY evaluated_at DESC, id DESC
+		LIMIT ?
+	`, ruleID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query watch rule evaluations: %w", err)
+	}
+	defer rows.Close()
+
+	evaluations := []*types.WatchEvaluation{}
+	for rows.Next() {
+		evaluation := &types.WatchEvaluation{}
+		var outcomes string
+		if err := rows.Scan(&evaluation.ID, &evaluation.RuleID, &evaluation.EvaluatedAt, &evaluation.Services,
+			&evaluation.Fired, &outcomes, &evaluation.Error); err != nil {
+			return nil, fmt.Errorf("failed to scan watch rule evaluation: %w", err)
+		}
+		if err := json.Unmarshal([]byte(outcomes), &evaluation.Outcomes); err != nil {
+			return nil, fmt.Errorf("invalid outcomes of watch rule evaluation %d: %w", evaluation.ID, err)
+		}
+		evaluations = append(evaluations, evaluation)
+	}
+	return evaluations, rows.Err()
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"dev-dashboard/pkg/types"
+)
+
+type WebhookModel struct {
+	db *sql.DB
+}
+
+func NewWebhookModel(db *sql.DB) *WebhookModel {
+	return &WebhookModel{db: db}
+}
+
+// Upsert records the webhook installed on a repository, replacing any previous one
+func (m *WebhookModel) Upsert(webhook *types.RepositoryWebhook) error {
+	query := `
+		INSERT OR REPLACE INTO repository_webhooks (repository_id, hook_id, target_url, secret, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`
+	webhook.CreatedAt = time.Now()
+
+	_, err := m.db.Exec(query, webhook.RepositoryID, webhook.HookID, webhook.TargetURL, webhook.Secret, webhook.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save webhook: %w", err)
+	}
+
+	return nil
+}
+
+// GetByRepositoryID returns the webhook recorded for a repository, or nil if none is installed
+func (m *WebhookModel) GetByRepositoryID(repositoryID int64) (*types.RepositoryWebhook, error) {
+	query := `
+		SELECT repository_id, hook_id, target_url, secret, created_at
+		FROM repository_webhooks
+		WHERE repository_id = ?
+	`
+
+	webhook := &types.RepositoryWebhook{}
+	err := m.db.QueryRow(query, repositoryID).Scan(
+		&webhook.RepositoryID,
+		&webhook.HookID,
+		&webhook.TargetURL,
+		&webhook.Secret,
+		&webhook.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+
+	return webhook, nil
+}
+
+func (m *WebhookModel) Delete(repositoryID int64) error {
+	_, err := m.db.Exec(`DELETE FROM repository_webhooks WHERE repository_id = ?`, repositoryID)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+// Package packages reads the direct dependencies of a service from its go.mod or package.json and
+// looks up their latest versions in the Go module proxy and the npm registry.
+package packages
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Ecosystem is the package manager a dependency belongs to
+type Ecosystem string
+
+const (
+	EcosystemGo  Ecosystem = "go"
+	EcosystemNPM Ecosystem = "npm"
+)
+
+// Manifests maps the manifest file names read from a service directory to their ecosystem
+var Manifests = map[string]Ecosystem{
+	"go.mod":       EcosystemGo,
+	"package.json": EcosystemNPM,
+}
+
+// Dependency is a direct dependency declared in a manifest. Version is as written: a module
+// version for Go, a version range such as ^4.17.1 for npm.
+type Dependency struct {
+	Name    string
+	Version string
+	Dev     bool // a devDependency of a package.json
+}
+
+// Parse reads the direct dependencies of a manifest of the given ecosystem
+func Parse(ecosystem Ecosystem, content string) ([]Dependency, error) {
+	switch ecosystem {
+	case EcosystemGo:
+		return ParseGoMod(content)
+	case EcosystemNPM:
+		return ParsePackageJSON(content)
+	}
+	return nil, fmt.Errorf("unknown package ecosystem %q", ecosystem)
+}
+
+// ParseGoMod reads the require directives of a go.mod, leaving out requirements marked
+// "// indirect"
+func ParseGoMod(content string) ([]Dependency, error) {
+	var deps []Dependency
+	inBlock := false
+	for i, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		indirect := false
+		if comment := strings.Index(line, "//"); comment >= 0 {
+			indirect = strings.HasPrefix(strings.TrimSpace(line[comment+2:]), "indirect")
+			line = strings.TrimSpace(line[:comment])
+		}
+		if line == "" {
+			continue
+		}
+
+		switch {
+		case inBlock && line == ")":
+			inBlock = false
+			continue
+		case inBlock:
+		case line == "require (" || line == "require(":
+			inBlock = true
+			continue
+		case strings.HasPrefix(line, "require "):
+			line = strings.TrimSpace(strings.TrimPrefix(line, "require "))
+		default:
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("go.mod line %d: malformed requirement %q", i+1, line)
+		}
+		if !indirect {
+			deps = append(deps, Dependency{Name: strings.Trim(fields[0], `"`), Version: fields[1]})
+		}
+	}
+	if inBlock {
+		return nil, fmt.Errorf("go.mod: unterminated require block")
+	}
+	return deps, nil
+}
+
+// ParsePackageJSON reads the dependencies and devDependencies of a package.json, ordered by name
+func ParsePackageJSON(content string) ([]Dependency, error) {
+	var manifest struct {
+		Dependencies    map[string]string `json:"dependencies"`
+		DevDependencies map[string]string `json:"devDependencies"`
+	}
+	if err := json.Unmarshal([]byte(content), &manifest); err != nil {
+		return nil, fmt.Errorf("package.json: %w", err)
+	}
+
+	var deps []Dependency
+	for _, group := range []struct {
+		versions map[string]string
+		dev      bool
+	}{{manifest.Dependencies, false}, {manifest.DevDependencies, true}} {
+		names := make([]string, 0, len(group.versions))
+		for name := range group.versions {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			deps = append(deps, Dependency{Name: name, Version: group.versions[name], Dev: group.dev})
+		}
+	}
+	return deps, nil
+}
@@ -0,0 +1,184 @@
+package packages
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	DefaultGoProxyURL     = "https://proxy.golang.org"
+	DefaultNPMRegistryURL = "https://registry.npmjs.org"
+
+	// maxMajorProbes bounds how many newer major version paths (module/v2, module/v3, ...) are
+	// tried for a Go module
+	maxMajorProbes = 5
+)
+
+// ErrPackageNotFound is returned when the registry doesn't know a package, e.g. a private module
+var ErrPackageNotFound = errors.New("package not found")
+
+// Limiter paces registry requests
+type Limiter interface {
+	Wait(ctx context.Context) error
+}
+
+// Registry looks up the latest versions of packages
+type Registry struct {
+	GoProxyURL     string
+	NPMRegistryURL string
+	client         *http.Client
+	limiter        Limiter
+}
+
+// NewRegistry creates a registry client for the public Go module proxy and npm registry whose
+// requests wait for limiter
+func NewRegistry(limiter Limiter) *Registry {
+	return &Registry{
+		GoProxyURL:     DefaultGoProxyURL,
+		NPMRegistryURL: DefaultNPMRegistryURL,
+		client:         &http.Client{Timeout: 15 * time.Second},
+		limiter:        limiter,
+	}
+}
+
+// Latest returns the latest version of a package. For Go modules, newer major versions published
+// under a /vN module path count as well, so a service on v1 sees the v3 release.
+func (r *Registry) Latest(ctx context.Context, ecosystem Ecosystem, name string) (string, error) {
+	switch ecosystem {
+	case EcosystemGo:
+		return r.latestGo(ctx, name)
+	case EcosystemNPM:
+		var tags map[string]string
+		if err := r.get(ctx, fmt.Sprintf("%s/-/package/%s/dist-tags", r.NPMRegistryURL, url.PathEscape(name)), &tags); err != nil {
+			return "", err
+		}
+		if tags["latest"] == "" {
+			return "", fmt.Errorf("%w: %s has no latest tag", ErrPackageNotFound, name)
+		}
+		return tags["latest"], nil
+	}
+	return "", fmt.Errorf("unknown package ecosystem %q", ecosystem)
+}
+
+func (r *Registry) latestGo(ctx context.Context, module string) (string, error) {
+	latest, err := r.goModuleLatest(ctx, module)
+	if err != nil {
+		return "", err
+	}
+	if strings.HasPrefix(module, "gopkg.in/") {
+		return latest, nil
+	}
+
+	base, major := splitModuleMajor(module)
+	for next := major + 1; next <= major+maxMajorProbes; next++ {
+		version, err := r.goModuleLatest(ctx, fmt.Sprintf("%s/v%d", base, next))
+		if errors.Is(err, ErrPackageNotFound) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		latest = version
+	}
+	return latest, nil
+}
+
+func (r *Registry) goModuleLatest(ctx context.Context, module string) (string, error) {
+	var info struct {
+		Version string
+	}
+	if err := r.get(ctx, fmt.Sprintf("%s/%s/@latest", r.GoProxyURL, escapeModulePath(module)), &info); err != nil {
+		return "", err
+	}
+	return info.Version, nil
+}
+
+func (r *Registry) get(ctx context.Context, requestURL string, v interface{}) error {
+	if r.limiter != nil {
+		if err := r.limiter.Wait(ctx); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to query %s: %w", requestURL, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return fmt.Errorf("%w: %s", ErrPackageNotFound, requestURL)
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("failed to query %s: %s", requestURL, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", requestURL, err)
+	}
+	return nil
+}
+
+// escapeModulePath escapes a module path for the module proxy protocol, which writes upper case
+// letters as '!' followed by the lower case letter
+func escapeModulePath(module string) string {
+	var b strings.Builder
+	for _, r := range module {
+		if unicode.IsUpper(r) {
+			b.WriteByte('!')
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// splitModuleMajor splits the /vN suffix off a module path, returning major version 1 for paths
+// without one
+func splitModuleMajor(module string) (string, int) {
+	if i := strings.LastIndex(module, "/v"); i > 0 {
+		if major, err := strconv.Atoi(module[i+2:]); err == nil && major >= 2 {
+			return module[:i], major
+		}
+	}
+	return module, 1
+}
+
+// Major returns the major version of a version or simple version range such as v1.2.3, ^4.17.1,
+// ~2.0 or 1.x. Ranges with alternatives, tags and URLs have no major version.
+func Major(version string) (int, bool) {
+	version = strings.TrimSpace(version)
+	if version == "" || strings.ContainsAny(version, "|:/ ") {
+		return 0, false
+	}
+	version = strings.TrimLeft(version, "v^~=>")
+	end := 0
+	for end < len(version) && version[end] >= '0' && version[end] <= '9' {
+		end++
+	}
+	major, err := strconv.Atoi(version[:end])
+	if err != nil {
+		return 0, false
+	}
+	return major, true
+}
+
+// MajorBehind reports whether latest is at least one major version ahead of current
+func MajorBehind(current, latest string) bool {
+	currentMajor, ok := Major(current)
+	if !ok {
+		return false
+	}
+	latestMajor, ok := Major(latest)
+	return ok && latestMajor > currentMajor
+}
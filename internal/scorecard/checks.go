@@ -0,0 +1,121 @@
+package scorecard
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"dev-dashboard/pkg/types"
+)
+
+func init() {
+	Register(Check{
+		ID:          "has_readme",
+		Name:        "Has a README",
+		Description: "The service directory contains a README",
+		Evaluate: func(input *Input, _ float64) (types.ScorecardStatus, string) {
+			switch {
+			case input.Service.HasReadme == nil:
+				return types.ScorecardUnknown, "Not known until the next discovery lists the service directory"
+			case *input.Service.HasReadme:
+				return types.ScorecardPass, "README found in " + input.Service.Path
+			default:
+				return types.ScorecardFail, "No README in " + input.Service.Path
+			}
+		},
+	})
+
+	Register(Check{
+		ID:          "has_owner",
+		Name:        "Has an owner",
+		Description: "An owning team or person is set for the service",
+		Evaluate: func(input *Input, _ float64) (types.ScorecardStatus, string) {
+			if input.Service.Owner == "" {
+				return types.ScorecardFail, "No owner set"
+			}
+			return types.ScorecardPass, "Owned by " + input.Service.Owner
+		},
+	})
+
+	Register(Check{
+		ID:               "recent_primary_deploy",
+		Name:             "Recently deployed",
+		Description:      "The primary environment was deployed to within the threshold",
+		ThresholdUnit:    "days",
+		DefaultThreshold: 14,
+		Evaluate: func(input *Input, threshold float64) (types.ScorecardStatus, string) {
+			environment := input.PrimaryEnvironment
+			if environment == "" {
+				environment = "production"
+			}
+			if input.LastPrimaryDeployAt == nil {
+				return types.ScorecardFail, fmt.Sprintf("Never deployed to %s", environment)
+			}
+
+			age := input.Now.Sub(*input.LastPrimaryDeployAt)
+			detail := fmt.Sprintf("Last deployed to %s %s ago", environment, formatDays(age))
+			if age > days(threshold) {
+				return types.ScorecardFail, detail
+			}
+			return types.ScorecardPass, detail
+		},
+	})
+
+	Register(Check{
+		ID:               "build_success_rate",
+		Name:             "Builds succeed",
+		Description:      "The build success rate over the last 30 days is at least the threshold",
+		ThresholdUnit:    "percent",
+		DefaultThreshold: 90,
+		Evaluate: func(input *Input, threshold float64) (types.ScorecardStatus, string) {
+			if input.BuildSuccessRate == nil {
+				return types.ScorecardUnknown, "No completed builds in the last " + formatDays(input.BuildWindow)
+			}
+
+			rate := *input.BuildSuccessRate * 100
+			detail := fmt.Sprintf("%.0f%% of %d builds succeeded", rate, input.BuildRuns)
+			if rate < threshold {
+				return types.ScorecardFail, detail
+			}
+			return types.ScorecardPass, detail
+		},
+	})
+
+	Register(Check{
+		ID:               "no_stale_pull_requests",
+		Name:             "No stale pull requests",
+		Description:      "No open pull request is older than the threshold",
+		ThresholdUnit:    "days",
+		DefaultThreshold: 30,
+		Evaluate: func(input *Input, threshold float64) (types.ScorecardStatus, string) {
+			if input.OpenPullRequests == nil {
+				return types.ScorecardUnknown, "Pull requests haven't been loaded since the app started"
+			}
+
+			var stale []string
+			for _, pr := range input.OpenPullRequests {
+				if input.Now.Sub(pr.CreatedAt) > days(threshold) {
+					stale = append(stale, fmt.Sprintf("#%d", pr.Number))
+				}
+			}
+			if len(stale) > 0 {
+				return types.ScorecardFail, fmt.Sprintf("%d open pull requests older than %s: %s",
+					len(stale), formatDays(days(threshold)), strings.Join(stale, ", "))
+			}
+			return types.ScorecardPass, fmt.Sprintf("%d open pull requests, none older than %s",
+				len(input.OpenPullRequests), formatDays(days(threshold)))
+		},
+	})
+}
+
+func days(n float64) time.Duration {
+	return time.Duration(n * float64(24*time.Hour))
+}
+
+func formatDays(d time.Duration) string {
+	n := int(d / (24 * time.Hour))
+	if n == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", n)
+}
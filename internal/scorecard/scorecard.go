@@ -0,0 +1,135 @@
+// Package scorecard grades services on a checklist of hygiene checks computed from local data
+package scorecard
+
+import (
+	"fmt"
+	"time"
+
+	"dev-dashboard/pkg/types"
+)
+
+// Input is the local data a service is scored on
+type Input struct {
+	Service *types.Microservice
+	Now     time.Time
+
+	PrimaryEnvironment string // "" when environments named like production are primary
+	// LastPrimaryDeployAt is when the deployment history last saw the primary environment change,
+	// nil if it never did
+	LastPrimaryDeployAt *time.Time
+
+	BuildWindow      time.Duration
+	BuildRuns        int
+	BuildSuccessRate *float64 // nil without completed builds in the window
+
+	// OpenPullRequests is nil when the service's pull requests haven't been fetched since startup
+	OpenPullRequests []*types.PullRequest
+}
+
+// Check is a scorecard check. Evaluate gets the configured threshold, or DefaultThreshold.
+type Check struct {
+	ID          string
+	Name        string
+	Description string
+	// ThresholdUnit is empty for checks without a threshold
+	ThresholdUnit    string
+	DefaultThreshold float64
+	Evaluate         func(input *Input, threshold float64) (types.ScorecardStatus, string)
+}
+
+var registry []Check
+
+// Register adds a check to the registry. Checks run and are listed in registration order.
+func Register(check Check) {
+	if _, exists := Lookup(check.ID); exists {
+		panic(fmt.Sprintf("scorecard check %q registered twice", check.ID))
+	}
+	registry = append(registry, check)
+}
+
+// Checks returns every registered check
+func Checks() []Check {
+	return append([]Check(nil), registry...)
+}
+
+// Lookup returns the registered check with the given ID
+func Lookup(id string) (Check, bool) {
+	for _, check := range registry {
+		if check.ID == id {
+			return check, true
+		}
+	}
+	return Check{}, false
+}
+
+// Config selects the checks that run and overrides their thresholds
+type Config struct {
+	Disabled   map[string]bool
+	Thresholds map[string]float64
+}
+
+// Threshold returns the check's configured threshold, or its default
+func (c Config) Threshold(check Check) float64 {
+	if threshold, ok := c.Thresholds[check.ID]; ok {
+		return threshold
+	}
+	return check.DefaultThreshold
+}
+
+// Evaluate runs the enabled checks on a service and grades it on the checks whose outcome is known
+func Evaluate(input *Input, config Config) *types.ServiceScorecard {
+	scorecard := &types.ServiceScorecard{
+		ServiceID:   input.Service.ID,
+		ServiceName: input.Service.Name,
+		EvaluatedAt: input.Now,
+		Results:     []types.ScorecardResult{},
+	}
+
+	for _, check := range registry {
+		if config.Disabled[check.ID] {
+			continue
+		}
+		status, detail := check.Evaluate(input, config.Threshold(check))
+		scorecard.Results = append(scorecard.Results, types.ScorecardResult{
+			CheckID: check.ID,
+			Name:    check.Name,
+			Status:  status,
+			Detail:  detail,
+		})
+	}
+
+	scorecard.Score, scorecard.Grade = Grade(scorecard.Results)
+	return scorecard
+}
+
+// Grade scores results as the share of known checks that passed and maps it onto A (at least 90%),
+// B (75%), C (60%), D (40%) or F. Without known checks there is no score and the grade is "-".
+func Grade(results []types.ScorecardResult) (*float64, string) {
+	passed, known := 0, 0
+	for _, result := range results {
+		switch result.Status {
+		case types.ScorecardPass:
+			passed++
+			known++
+		case types.ScorecardFail:
+			known++
+		}
+	}
+	if known == 0 {
+		return nil, "-"
+	}
+
+	score := float64(passed) / float64(known)
+	switch {
+	case score >= 0.9:
+		return &score, "A"
+	case score >= 0.75:
+		return &score, "B"
+	case score >= 0.6:
+		return &score, "C"
+	case score >= 0.4:
+		return &score, "D"
+	default:
+		return &score, "F"
+	}
+}
@@ -0,0 +1,135 @@
+package sync
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"dev-dashboard/internal/github"
+	"dev-dashboard/pkg/types"
+)
+
+const (
+	// DefaultBranchPattern stands for the repository's default branch in a branch filter, or main
+	// and master while the default branch isn't known yet
+	DefaultBranchPattern = "{default}"
+	// AllBranchesPattern lets a branch filter through every branch
+	AllBranchesPattern = "*"
+	// DefaultWorkflowBranches is the branch filter of repositories that don't set one, so feature
+	// branch CI runs don't drown out the health of the branches that ship
+	DefaultWorkflowBranches = DefaultBranchPattern + ",release/*"
+)
+
+// BranchFilter decides which branches' workflow runs are recorded. Patterns are branch names or
+// path.Match globs, where * doesn't cross a slash: release/* matches release/1.2 but not
+// release/1.2/hotfix.
+type BranchFilter struct {
+	patterns []string
+	all      bool
+}
+
+// ParseBranchFilter parses a comma-separated list of branch patterns; an empty value is
+// DefaultWorkflowBranches
+func ParseBranchFilter(value string) (*BranchFilter, error) {
+	if strings.TrimSpace(value) == "" {
+		value = DefaultWorkflowBranches
+	}
+	filter := &BranchFilter{}
+	for _, pattern := range strings.Split(value, ",") {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		if pattern == AllBranchesPattern {
+			filter.all = true
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid branch pattern %q: %w", pattern, err)
+		}
+		filter.patterns = append(filter.patterns, pattern)
+	}
+	if len(filter.patterns) == 0 {
+		return nil, fmt.Errorf("branch filter has no patterns")
+	}
+	return filter, nil
+}
+
+// Match reports whether the filter lets a branch through
+func (f *BranchFilter) Match(branch, defaultBranch string) bool {
+	if f.all {
+		return true
+	}
+	for _, pattern := range f.patterns {
+		if pattern == DefaultBranchPattern {
+			if branch == defaultBranch || (defaultBranch == "" && (branch == "main" || branch == "master")) {
+				return true
+			}
+			continue
+		}
+		if ok, _ := path.Match(pattern, branch); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// QueryBranches returns the branches whose runs can be asked for by name, and whether the filter
+// has globs whose runs have to be listed unfiltered and matched here
+func (f *BranchFilter) QueryBranches(defaultBranch string) ([]string, bool) {
+	if f.all {
+		return nil, true
+	}
+	var branches []string
+	add := func(names ...string) {
+		for _, name := range names {
+			if !slices.Contains(branches, name) {
+				branches = append(branches, name)
+			}
+		}
+	}
+	globs := false
+	for _, pattern := range f.patterns {
+		switch {
+		case pattern == DefaultBranchPattern && defaultBranch == "":
+			add("main", "master")
+		case pattern == DefaultBranchPattern:
+			add(defaultBranch)
+		case strings.ContainsAny(pattern, `*?[\`):
+			globs = true
+		default:
+			add(pattern)
+		}
+	}
+	return branches, globs
+}
+
+// workflowRunsPerRequest is how many of the latest runs of a workflow are asked for per branch
+const workflowRunsPerRequest = 50
+
+// getFilteredWorkflowRuns returns the latest runs of a workflow on the branches a filter lets
+// through. Branches the filter names are asked for one by one, so busy feature branches can't push
+// their runs out of the page; glob patterns are matched against the latest runs of all branches.
+func (s *Service) getFilteredWorkflowRuns(repo *types.Repository, owner, repoName string, workflowID int64, filter *BranchFilter) ([]github.WorkflowRun, error) {
+	branches, globs := filter.QueryBranches(repo.DefaultBranch)
+	if globs {
+		branches = append(branches, "")
+	}
+
+	var runs []github.WorkflowRun
+	seen := make(map[int64]bool)
+	for _, branch := range branches {
+		branchRuns, err := s.githubClient.GetBranchWorkflowRuns(s.requestContext(repo.ID), owner, repoName, workflowID, branch, workflowRunsPerRequest)
+		if err != nil {
+			return nil, err
+		}
+		for _, run := range branchRuns {
+			if seen[run.ID] || !filter.Match(run.Branch, repo.DefaultBranch) {
+				continue
+			}
+			seen[run.ID] = true
+			runs = append(runs, run)
+		}
+	}
+	return runs, nil
+}
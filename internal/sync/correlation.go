@@ -0,0 +1,80 @@
+package sync
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"dev-dashboard/pkg/types"
+)
+
+// DefaultCorrelationRetryWindow is how long sync keeps trying to match a deployment's tag with a
+// monorepo commit after first falling back to the kubernetes repository's commit
+const DefaultCorrelationRetryWindow = 24 * time.Hour
+
+// SetCorrelationRetryWindow changes how long uncorrelated deployments are retried; 0 stops retrying
+func (s *Service) SetCorrelationRetryWindow(window time.Duration) {
+	s.correlationRetryWindow.Store(int64(window))
+}
+
+// retryCorrelations tries again to match the tags of a kubernetes repository's uncorrelated
+// deployments with monorepo commits, e.g. when the deploy pull request merged before the monorepo
+// commit it references was visible. It runs when the deployment scan is skipped because the
+// kustomization tree is unchanged, since a scan correlates every deployment it finds anyway.
+// Deployments still uncorrelated past the retry window are abandoned.
+func (s *Service) retryCorrelations(repo *types.Repository, results *phaseResults) {
+	deployments, err := s.deploymentModel.GetUncorrelated(repo.ID)
+	if err != nil {
+		log.Printf("Failed to get uncorrelated deployments of %s: %v", repo.Name, err)
+		return
+	}
+
+	window := time.Duration(s.correlationRetryWindow.Load())
+	now := time.Now()
+	// A service's targets usually share a tag, which is looked up once
+	type lookup struct {
+		serviceID int64
+		tag       string
+	}
+	found := make(map[lookup]string)
+	for _, deployment := range deployments {
+		if deployment.UncorrelatedSince == nil || now.Sub(*deployment.UncorrelatedSince) >= window {
+			results.store(func(m *phaseModels) error {
+				if err := m.deployments.AbandonCorrelation(deployment.ID); err != nil {
+					log.Printf("Failed to abandon correlation of deployment %d: %v", deployment.ID, err)
+					return nil
+				}
+				results.announce(func() {
+					s.logSync(repo.ID, types.SyncLogWarning, fmt.Sprintf("No monorepo commit matched tag %s of %s (%s/%s) within %s; keeping kubernetes commit %s",
+						deployment.Tag, deployment.Path, deployment.Environment, deployment.Region, window, deployment.CommitSHA))
+				})
+				return nil
+			})
+			continue
+		}
+
+		key := lookup{deployment.ServiceID, deployment.Tag}
+		commitSHA, ok := found[key]
+		if !ok {
+			commitSHA = s.correlateTagWithCommit(repo.ID, deployment.ServiceID, deployment.Tag)
+			found[key] = commitSHA
+		}
+		if commitSHA == "" {
+			continue
+		}
+
+		results.store(func(m *phaseModels) error {
+			correlated, err := m.deployments.CorrelateCommit(deployment.ID, commitSHA)
+			if err != nil {
+				log.Printf("Failed to correlate deployment %d: %v", deployment.ID, err)
+				return nil
+			}
+			if correlated {
+				s.changes.mark(types.EntityDeployments, repo.ID)
+				log.Printf("Correlated tag %s of %s (%s/%s) with commit %s on retry", deployment.Tag, deployment.Path,
+					deployment.Environment, deployment.Region, commitSHA)
+			}
+			return nil
+		})
+	}
+}
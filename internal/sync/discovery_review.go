@@ -0,0 +1,209 @@
+package sync
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"dev-dashboard/internal/vcs"
+	"dev-dashboard/pkg/types"
+)
+
+// DefaultDiscoveryReviewWindow is how long discovery changes of a repository in review mode wait
+// for review before they're applied or expired, unless configured otherwise
+const DefaultDiscoveryReviewWindow = 72 * time.Hour
+
+// SetDiscoveryReviewWindow changes how long discovery changes wait for review, 0 meaning forever,
+// and whether changes left unreviewed that long are applied rather than expired
+func (s *Service) SetDiscoveryReviewWindow(window time.Duration, autoApply bool) {
+	s.discoveryReviewWindow.Store(int64(window))
+	s.discoveryReviewAutoApply.Store(autoApply)
+}
+
+// DiffDiscoveredServices compares a repository's services with the ones discovery found. Services
+// found under the same name and path are kept with their discovered description, domain and
+// README flag; the others become changes. An existing service found under the same name at another
+// path, or at the same path under another name, is a rename. Hidden services are never removed.
+// kept lists every existing service, so storing it changes nothing but the kept services' details.
+func DiffDiscoveredServices(existing []*types.Microservice, discovered []types.Microservice) ([]types.Microservice, []types.DiscoveryChange) {
+	kept := make([]types.Microservice, 0, len(existing))
+	matched := make(map[int64]bool)
+	var unmatched []types.Microservice
+	for _, service := range discovered {
+		found := false
+		for _, current := range existing {
+			if !matched[current.ID] && current.Name == service.Name && current.Path == service.Path {
+				matched[current.ID] = true
+				service.ID = current.ID
+				kept = append(kept, service)
+				found = true
+				break
+			}
+		}
+		if !found {
+			unmatched = append(unmatched, service)
+		}
+	}
+
+	renamedFrom := make(map[int]*types.Microservice)
+	for _, sameName := range []bool{true, false} {
+		for i, service := range unmatched {
+			if renamedFrom[i] != nil {
+				continue
+			}
+			for _, current := range existing {
+				if matched[current.ID] {
+					continue
+				}
+				if (sameName && current.Name == service.Name) || (!sameName && current.Path == service.Path) {
+					matched[current.ID] = true
+					renamedFrom[i] = current
+					break
+				}
+			}
+		}
+	}
+
+	var changes []types.DiscoveryChange
+	for i, service := range unmatched {
+		change := types.DiscoveryChange{
+			RepositoryID: service.RepositoryID,
+			Kind:         types.DiscoveryAdd,
+			Name:         service.Name,
+			Path:         service.Path,
+			Description:  service.Description,
+			Domain:       service.Domain,
+			HasReadme:    service.HasReadme,
+		}
+		if current := renamedFrom[i]; current != nil {
+			id := current.ID
+			change.Kind = types.DiscoveryRename
+			change.ServiceID = &id
+			change.OldName = current.Name
+			change.OldPath = current.Path
+		}
+		changes = append(changes, change)
+	}
+
+	for _, current := range existing {
+		if matched[current.ID] {
+			continue
+		}
+		if !current.IsHidden {
+			id := current.ID
+			changes = append(changes, types.DiscoveryChange{
+				RepositoryID: current.RepositoryID,
+				Kind:         types.DiscoveryRemove,
+				ServiceID:    &id,
+				Name:         current.Name,
+				Path:         current.Path,
+				Description:  current.Description,
+				Domain:       current.Domain,
+				HasReadme:    current.HasReadme,
+			})
+		}
+	}
+
+	// Renamed, removed and hidden services stay as they are until their change is applied
+	for _, current := range existing {
+		isKept := false
+		for _, service := range kept {
+			if service.ID == current.ID {
+				isKept = true
+				break
+			}
+		}
+		if !isKept {
+			kept = append(kept, *current)
+		}
+	}
+
+	return kept, changes
+}
+
+// reviewDiscoveredServices stores discovered services of a repository in review mode: known
+// services get their details refreshed, while adds, removals and renames are recorded for review
+// and announced with a notification. Changes left unreviewed past the review window are applied
+// or expired. It stores through m, as a write of the services phase.
+func (s *Service) reviewDiscoveredServices(repo *types.Repository, discovered []types.Microservice, m *phaseModels, results *phaseResults) error {
+	for i := range discovered {
+		cleaned, err := vcs.CleanRepoPath(discovered[i].Path)
+		if err != nil {
+			return fmt.Errorf("invalid path of service %s: %w", discovered[i].Name, err)
+		}
+		discovered[i].Path = cleaned
+	}
+
+	existing, err := m.services.GetByRepositoryID(repo.ID, true)
+	if err != nil {
+		return err
+	}
+
+	kept, changes := DiffDiscoveredServices(existing, discovered)
+	changed, err := m.services.UpsertServicesPreserveID(repo.ID, kept)
+	if err != nil {
+		return fmt.Errorf("failed to upsert microservices: %w", err)
+	}
+
+	added, err := m.discoveryChanges.Sync(repo.ID, changes)
+	if err != nil {
+		return err
+	}
+	if added > 0 {
+		results.announce(func() {
+			s.logSync(repo.ID, types.SyncLogInfo, fmt.Sprintf("Discovery found %d service changes waiting for review", added))
+			s.notify(repo.ID, "discovery_review", fmt.Sprintf("Service changes in %s need review", repo.Name),
+				fmt.Sprintf("Discovery found %d new service adds, removals or renames. Review them on the Repositories page.", added))
+		})
+	}
+
+	applied, err := s.resolveOverdueDiscoveryChanges(repo, m, results)
+	if err != nil {
+		return err
+	}
+	if changed || applied {
+		s.changes.mark(types.EntityServices, repo.ID)
+	}
+	return nil
+}
+
+// resolveOverdueDiscoveryChanges applies or expires the changes of a repository left pending past
+// the review window, and reports whether any service changed
+func (s *Service) resolveOverdueDiscoveryChanges(repo *types.Repository, m *phaseModels, results *phaseResults) (bool, error) {
+	window := time.Duration(s.discoveryReviewWindow.Load())
+	if window <= 0 {
+		return false, nil
+	}
+	before := time.Now().Add(-window)
+
+	if !s.discoveryReviewAutoApply.Load() {
+		expired, err := m.discoveryChanges.ExpirePendingBefore(repo.ID, before)
+		if err != nil {
+			return false, err
+		}
+		if expired > 0 {
+			results.announce(func() {
+				s.logSync(repo.ID, types.SyncLogInfo, fmt.Sprintf("%d service changes expired without review", expired))
+			})
+		}
+		return false, nil
+	}
+
+	overdue, err := m.discoveryChanges.GetPendingBefore(repo.ID, before)
+	if err != nil || len(overdue) == 0 {
+		return false, err
+	}
+	decisions := make([]types.DiscoveryChangeDecision, len(overdue))
+	for i, change := range overdue {
+		decisions[i] = types.DiscoveryChangeDecision{ID: change.ID, Accept: true}
+	}
+	changed, err := m.discoveryChanges.Apply(repo.ID, decisions)
+	if err != nil {
+		return false, fmt.Errorf("failed to apply overdue discovery changes: %w", err)
+	}
+	log.Printf("Applied %d unreviewed service changes of %s", len(overdue), repo.Name)
+	results.announce(func() {
+		s.logSync(repo.ID, types.SyncLogInfo, fmt.Sprintf("Applied %d service changes left unreviewed", len(overdue)))
+	})
+	return changed, nil
+}
@@ -0,0 +1,164 @@
+package sync
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"dev-dashboard/internal/github"
+	"dev-dashboard/internal/vcs"
+)
+
+const (
+	discoveryScriptTimeout   = 60 * time.Second
+	discoveryScriptMaxOutput = 1 << 20 // 1 MiB
+	// How long to wait for the output of a script that exited or was killed; children it forked
+	// may hold it open
+	discoveryScriptWaitDelay = 5 * time.Second
+)
+
+// discoveredService is the JSON shape a discovery script must print for each service
+type discoveredService struct {
+	Name        string `json:"name"`
+	Path        string `json:"path"`
+	Description string `json:"description"`
+}
+
+// runDiscoveryScript executes a repository's discovery script and parses the services it prints.
+// The script is run directly (never through a shell) with a minimal environment, a temporary
+// working directory and a timeout. Its stderr is returned alongside the result for logging.
+func runDiscoveryScript(ctx context.Context, script, repoURL, token, serviceLocation string) ([]github.ServiceInfo, string, error) {
+	if !filepath.IsAbs(script) {
+		return nil, "", fmt.Errorf("discovery script path must be absolute: %s", script)
+	}
+	info, err := os.Stat(script)
+	if err != nil {
+		return nil, "", fmt.Errorf("discovery script not found: %w", err)
+	}
+	if info.IsDir() || info.Mode()&0111 == 0 {
+		return nil, "", fmt.Errorf("discovery script is not executable: %s", script)
+	}
+
+	workDir, err := os.MkdirTemp("", "dev-dashboard-discovery-")
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create discovery working directory: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	ctx, cancel := context.WithTimeout(ctx, discoveryScriptTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, script)
+	cmd.Dir = workDir
+	cmd.Env = []string{
+		"PATH=" + os.Getenv("PATH"),
+		"HOME=" + workDir,
+		"DEV_DASHBOARD_REPO_URL=" + repoURL,
+		"DEV_DASHBOARD_GITHUB_TOKEN=" + token,
+		"DEV_DASHBOARD_SERVICE_LOCATION=" + serviceLocation,
+	}
+	killProcessGroupOnCancel(cmd)
+	cmd.WaitDelay = discoveryScriptWaitDelay
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &limitedWriter{w: &stdout, remaining: discoveryScriptMaxOutput}
+	cmd.Stderr = &limitedWriter{w: &stderr, remaining: discoveryScriptMaxOutput}
+
+	// A script that succeeded but left a child holding its output has printed all it will
+	if err := cmd.Run(); err != nil && !errors.Is(err, exec.ErrWaitDelay) {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, stderr.String(), fmt.Errorf("discovery script timed out after %s", discoveryScriptTimeout)
+		}
+		return nil, stderr.String(), fmt.Errorf("discovery script failed: %w", err)
+	}
+
+	services, err := parseDiscoveryOutput(stdout.Bytes())
+	if err != nil {
+		return nil, stderr.String(), err
+	}
+
+	root := serviceLocation
+	if root == "" {
+		root = "services"
+	}
+	for i := range services {
+		services[i].Domain = github.ServiceDomain(root, services[i].Path)
+	}
+
+	return services, stderr.String(), nil
+}
+
+// parseDiscoveryOutput strictly validates the JSON printed by a discovery script
+func parseDiscoveryOutput(output []byte) ([]github.ServiceInfo, error) {
+	decoder := json.NewDecoder(bytes.NewReader(output))
+	decoder.DisallowUnknownFields()
+
+	var discovered []discoveredService
+	if err := decoder.Decode(&discovered); err != nil {
+		return nil, fmt.Errorf("invalid discovery script output: %w", err)
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("invalid discovery script output: unexpected data after JSON array")
+	}
+	if discovered == nil {
+		return nil, fmt.Errorf("invalid discovery script output: expected a JSON array")
+	}
+
+	names := make(map[string]bool)
+	services := make([]github.ServiceInfo, 0, len(discovered))
+	for i, service := range discovered {
+		name := strings.TrimSpace(service.Name)
+		if name == "" {
+			return nil, fmt.Errorf("invalid discovery script output: entry %d has no name", i)
+		}
+		servicePath, err := vcs.CleanRepoPath(service.Path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid discovery script output: service %s has an invalid path %q", name, service.Path)
+		}
+		if servicePath == "" {
+			return nil, fmt.Errorf("invalid discovery script output: service %s has no path", name)
+		}
+		if names[name] {
+			return nil, fmt.Errorf("invalid discovery script output: duplicate service name %s", name)
+		}
+		names[name] = true
+
+		services = append(services, github.ServiceInfo{
+			Name:        name,
+			Path:        servicePath,
+			Description: service.Description,
+		})
+	}
+
+	return services, nil
+}
+
+// limitedWriter discards anything written past its limit so a misbehaving script can't exhaust memory
+type limitedWriter struct {
+	w         io.Writer
+	remaining int
+}
+
+func (l *limitedWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	if l.remaining <= 0 {
+		return n, nil
+	}
+	if len(p) > l.remaining {
+		p = p[:l.remaining]
+	}
+	written, err := l.w.Write(p)
+	l.remaining -= written
+	if err != nil {
+		return written, err
+	}
+	return n, nil
+}
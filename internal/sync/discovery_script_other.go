@@ -0,0 +1,9 @@
+//go:build !unix
+
+package sync
+
+import "os/exec"
+
+// killProcessGroupOnCancel leaves cmd to be killed on its own, as there's no process group to
+// kill; the wait delay still bounds how long its children can hold its output
+func killProcessGroupOnCancel(cmd *exec.Cmd) {}
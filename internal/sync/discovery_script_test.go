@@ -0,0 +1,29 @@
+//go:build unix
+
+package sync
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCancelledDiscoveryScriptKillsItsChildren(t *testing.T) {
+	script := filepath.Join(t.TempDir(), "discover.sh")
+	// The background sleep holds the script's output open after the script is killed
+	if err := os.WriteFile(script, []byte("#!/bin/sh\nsleep 30 &\nsleep 30\n"), 0755); err != nil {
+		t.Fatalf("failed to write script: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	started := time.Now()
+	if _, _, err := runDiscoveryScript(ctx, script, "https://github.com/acme/repo", "", ""); err == nil {
+		t.Fatal("a cancelled discovery script succeeded")
+	}
+	if elapsed := time.Since(started); elapsed >= discoveryScriptWaitDelay {
+		t.Errorf("the cancelled script returned after %s, want its children killed with it", elapsed)
+	}
+}
@@ -0,0 +1,44 @@
+package sync
+
+import (
+	"log"
+
+	"dev-dashboard/internal/github"
+	"dev-dashboard/pkg/types"
+)
+
+// storeEnvVarSnapshot records the environment variables a scanned overlay sets for a service's
+// Deployment. Overlays scanned without env var snapshots, or whose Deployment wasn't found, leave
+// the stored snapshot alone.
+func (s *Service) storeEnvVarSnapshot(serviceID int64, deploy github.KustomizationDeployment, m *phaseModels) error {
+	if s.envVarSnapshotModel == nil || deploy.EnvFingerprint == "" {
+		return nil
+	}
+
+	snapshot := &types.EnvVarSnapshot{
+		ServiceID:   serviceID,
+		Environment: deploy.Environment,
+		Region:      deploy.Region,
+		Path:        deploy.Path,
+		Fingerprint: deploy.EnvFingerprint,
+	}
+	for _, v := range deploy.Env {
+		snapshot.Variables = append(snapshot.Variables, types.EnvVar{
+			Container: v.Container,
+			Name:      v.Name,
+			Value:     v.Value,
+			ValueFrom: v.ValueFrom,
+			Secret:    v.Secret,
+			Digest:    v.Digest,
+		})
+	}
+
+	stored, err := m.envVarSnapshots.Upsert(snapshot)
+	if err != nil {
+		return err
+	}
+	if stored {
+		log.Printf("Stored %d environment variables of service %d in %s/%s", len(snapshot.Variables), serviceID, deploy.Environment, deploy.Region)
+	}
+	return nil
+}
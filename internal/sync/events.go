@@ -0,0 +1,67 @@
+package sync
+
+import (
+	"sort"
+	gosync "sync"
+
+	"dev-dashboard/pkg/types"
+)
+
+// DataChangedEventName is the event emitted after a sync cycle that changed data
+const DataChangedEventName = "data:changed"
+
+// changeSet collects the entity types and repositories changed during a sync cycle
+type changeSet struct {
+	mu      gosync.Mutex
+	changes map[types.EntityType]map[int64]bool
+}
+
+func newChangeSet() *changeSet {
+	return &changeSet{changes: make(map[types.EntityType]map[int64]bool)}
+}
+
+func (c *changeSet) mark(entity types.EntityType, repositoryID int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.changes[entity] == nil {
+		c.changes[entity] = make(map[int64]bool)
+	}
+	c.changes[entity][repositoryID] = true
+}
+
+// flush returns the collected changes as an event and resets the set; it returns nil if nothing changed
+func (c *changeSet) flush() *types.DataChangedEvent {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.changes) == 0 {
+		return nil
+	}
+
+	event := &types.DataChangedEvent{Changes: make(map[types.EntityType][]int64)}
+	allRepos := make(map[int64]bool)
+	for entity, repos := range c.changes {
+		event.Entities = append(event.Entities, entity)
+		for repoID := range repos {
+			event.Changes[entity] = append(event.Changes[entity], repoID)
+			allRepos[repoID] = true
+		}
+		sort.Slice(event.Changes[entity], func(i, j int) bool { return event.Changes[entity][i] < event.Changes[entity][j] })
+	}
+	for repoID := range allRepos {
+		event.RepositoryIDs = append(event.RepositoryIDs, repoID)
+	}
+	sort.Slice(event.Entities, func(i, j int) bool { return event.Entities[i] < event.Entities[j] })
+	sort.Slice(event.RepositoryIDs, func(i, j int) bool { return event.RepositoryIDs[i] < event.RepositoryIDs[j] })
+
+	c.changes = make(map[types.EntityType]map[int64]bool)
+	return event
+}
+
+// emitChanges reports the changes collected so far to the data changed callback
+func (s *Service) emitChanges() {
+	event := s.changes.flush()
+	if event == nil || s.onDataChanged == nil {
+		return
+	}
+	s.onDataChanged(*event)
+}
@@ -0,0 +1,23 @@
+package sync
+
+import (
+	"fmt"
+
+	"dev-dashboard/pkg/types"
+)
+
+// SetFirstDeployNotifications turns the first_deploy notification on or off
+func (s *Service) SetFirstDeployNotifications(enabled bool) {
+	s.firstDeployNotifications.Store(enabled)
+}
+
+// notifyFirstDeploy raises a first_deploy notification when the history entry a sync just recorded
+// for a deployment is the service's first in its environment and region
+func (s *Service) notifyFirstDeploy(repo *types.Repository, serviceName string, deployment *types.Deployment) {
+	if !deployment.FirstObservation || !s.firstDeployNotifications.Load() {
+		return
+	}
+	s.notifyEnvironment(repo.ID, deployment.Environment, "first_deploy",
+		fmt.Sprintf("%s is now live in %s", serviceName, deployment.Environment),
+		fmt.Sprintf("%s was deployed to %s (%s) for the first time with %s", serviceName, deployment.Environment, deployment.Region, deployment.Tag))
+}
@@ -0,0 +1,23 @@
+package sync
+
+import (
+	"fmt"
+
+	"dev-dashboard/pkg/types"
+)
+
+// notifyFreezeViolation raises a freeze_violation notification when the history entry a sync just
+// recorded for a deployment fell into a freeze window
+func (s *Service) notifyFreezeViolation(repo *types.Repository, serviceName string, deployment *types.Deployment) {
+	window := deployment.FreezeWindow
+	if window == nil {
+		return
+	}
+	reason := window.Reason
+	if reason == "" {
+		reason = "freeze window " + window.EnvironmentPattern
+	}
+	s.notifyEnvironment(repo.ID, deployment.Environment, "freeze_violation",
+		fmt.Sprintf("%s deployed to %s during a freeze", serviceName, deployment.Environment),
+		fmt.Sprintf("%s (%s) moved to %s during %s", deployment.Path, deployment.Region, deployment.Tag, reason))
+}
@@ -0,0 +1,71 @@
+package sync
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"dev-dashboard/internal/models"
+	"dev-dashboard/internal/testsupport"
+	"dev-dashboard/pkg/types"
+)
+
+// kustomization returns a kustomization.yaml setting the service's image to tag
+func kustomization(serviceName, tag string) string {
+	return fmt.Sprintf("images:\n- name: registry.example.com/%s\n  newTag: %s\n", serviceName, tag)
+}
+
+// scanDeployments runs a deployment scan of the kubernetes repository with fresh GitHub responses
+// and stores what it found
+func scanDeployments(t *testing.T, service *Service, repo *types.Repository) {
+	t.Helper()
+	service.githubClient.ResetRequestCache()
+	owner, name, _ := strings.Cut(repositoryFullName(repo), "/")
+	results := &phaseResults{}
+	if err := service.syncDeployments(repo, owner, name, results); err != nil {
+		t.Fatalf("syncDeployments: %v", err)
+	}
+	if err := results.write(service.phaseModels); err != nil {
+		t.Fatalf("failed to store the deployments: %v", err)
+	}
+	results.raise()
+}
+
+func TestFirstScanDoesNotFlagFreezeViolations(t *testing.T) {
+	fake := newFakeGitHub()
+	service, db := newTestService(t, fake)
+	api := testsupport.Service(t, db, testsupport.Repository(t, db).ID)
+	k8s := testsupport.KubernetesRepository(t, db)
+	testsupport.FreezeWindow(t, db, "dev")
+	overlay := "services/" + api.Name + "/overlays/dev/us-east-1/kustomization.yaml"
+	freezeWindows := models.NewFreezeWindowModel(db)
+
+	// Everything the first scan finds was deployed before the dashboard looked, freeze or not
+	fake.handleContents(k8s, map[string]string{overlay: kustomization(api.Name, strings.Repeat("a", 40))})
+	scanDeployments(t, service, k8s)
+	if n := len(notificationsOfType(t, db, "freeze_violation")); n != 0 {
+		t.Errorf("the first scan raised %d freeze violation notifications, want none", n)
+	}
+	violations, err := freezeWindows.GetViolations(time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("GetViolations: %v", err)
+	}
+	if len(violations) != 0 {
+		t.Errorf("the first scan flagged %d freeze violations, want none", len(violations))
+	}
+
+	// A deploy seen by a later scan is one made during the freeze
+	fake.handleContents(k8s, map[string]string{overlay: kustomization(api.Name, strings.Repeat("b", 40))})
+	scanDeployments(t, service, k8s)
+	if n := len(notificationsOfType(t, db, "freeze_violation")); n != 1 {
+		t.Errorf("a deploy during the freeze raised %d freeze violation notifications, want 1", n)
+	}
+	violations, err = freezeWindows.GetViolations(time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("GetViolations: %v", err)
+	}
+	if len(violations) != 1 || violations[0].Entry.Tag != strings.Repeat("b", 40) {
+		t.Errorf("got %d freeze violations, want the deploy during the freeze", len(violations))
+	}
+}
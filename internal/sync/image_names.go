@@ -0,0 +1,75 @@
+package sync
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"strings"
+
+	"dev-dashboard/internal/github"
+	"dev-dashboard/internal/kubernetes"
+	"dev-dashboard/pkg/types"
+)
+
+// reportUnmatchedImages logs a warning for each service directory whose kustomizations list images
+// but none the service's, with the images found as the suggestion list. The most likely image is
+// recorded as the suggested image name of services that don't have one set.
+func (s *Service) reportUnmatchedImages(repo *types.Repository, scanResults []github.KustomizationFileResult, services []*types.Microservice, results *phaseResults) {
+	unmatched := make(map[string][]string) // service directory -> images of its kustomizations
+	files := make(map[string]int)
+	for _, result := range scanResults {
+		if result.SkipReason != github.SkipNoServiceImage || len(result.Images) == 0 {
+			continue
+		}
+		files[result.ServiceName]++
+		for _, image := range result.Images {
+			if !slices.Contains(unmatched[result.ServiceName], image) {
+				unmatched[result.ServiceName] = append(unmatched[result.ServiceName], image)
+			}
+		}
+	}
+
+	serviceDirs := make([]string, 0, len(unmatched))
+	for serviceDir := range unmatched {
+		serviceDirs = append(serviceDirs, serviceDir)
+	}
+	sort.Strings(serviceDirs)
+
+	for _, serviceDir := range serviceDirs {
+		service := MatchDeploymentService(services, serviceDir)
+		if service == nil {
+			continue
+		}
+		images := unmatched[serviceDir]
+		suggestion := kubernetes.SuggestImageName(serviceDir, images)
+
+		message := fmt.Sprintf("No image in %d kustomization(s) of %s matches service %s", files[serviceDir], serviceDir, service.Name)
+		if service.ImageName != "" {
+			message += fmt.Sprintf(" (image name %s)", service.ImageName)
+		}
+		message += fmt.Sprintf("; images found: %s. ", strings.Join(images, ", "))
+		if suggestion != "" {
+			message += fmt.Sprintf("Suggested image name: %s", suggestion)
+		} else {
+			message += "Set the service's image name to the one it's deployed as"
+		}
+		log.Print(message)
+		s.logSync(repo.ID, types.SyncLogWarning, message)
+
+		if service.ImageName != "" || suggestion == "" {
+			continue
+		}
+		results.store(func(m *phaseModels) error {
+			changed, err := m.services.SetSuggestedImageName(service.ID, suggestion)
+			if err != nil {
+				log.Printf("Failed to record suggested image name of %s: %v", service.Name, err)
+				return nil
+			}
+			if changed {
+				s.changes.mark(types.EntityServices, service.RepositoryID)
+			}
+			return nil
+		})
+	}
+}
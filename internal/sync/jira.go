@@ -0,0 +1,286 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"strings"
+	gosync "sync"
+	"sync/atomic"
+	"time"
+
+	"dev-dashboard/internal/jira"
+	"dev-dashboard/internal/models"
+	"dev-dashboard/pkg/types"
+)
+
+// DefaultJiraPollInterval is how often linked JIRA tickets are polled unless configured otherwise
+const DefaultJiraPollInterval = 15 * time.Minute
+
+// jiraMaxRequestsPerPass bounds the JIRA requests of one scheduled poll. Tickets that don't fit are
+// polled first on the next pass.
+const jiraMaxRequestsPerPass = 10
+
+// JiraPoller keeps the JIRA title, status and assignee of tasks up to date in the background. It
+// runs alongside the sync service so tickets are polled even without a GitHub token.
+type JiraPoller struct {
+	taskModel     *models.TaskModel
+	notifier      *Notifier
+	jiraClient    func() *jira.Client
+	onDataChanged func(types.DataChangedEvent)
+	interval      atomic.Int64
+	enabled       atomic.Bool
+	fillDeadlines atomic.Bool
+
+	// mu serializes passes so a manual refresh and a scheduled poll don't notify twice
+	mu      gosync.Mutex
+	cursor  int
+	retryAt time.Time
+	me      struct {
+		client *jira.Client
+		id     string
+	}
+
+	ctx        context.Context
+	cancelFunc context.CancelFunc
+}
+
+// NewJiraPoller creates a poller that asks jiraClient for the currently configured client on every
+// pass, so configuration changes apply without restarting it
+func NewJiraPoller(taskModel *models.TaskModel, notifier *Notifier, jiraClient func() *jira.Client, onDataChanged func(types.DataChangedEvent)) *JiraPoller {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	poller := &JiraPoller{
+		taskModel:     taskModel,
+		notifier:      notifier,
+		jiraClient:    jiraClient,
+		onDataChanged: onDataChanged,
+		ctx:           ctx,
+		cancelFunc:    cancel,
+	}
+	poller.SetInterval(DefaultJiraPollInterval)
+	poller.SetEnabled(true)
+	return poller
+}
+
+// SetInterval changes how often tickets are polled, starting after the next poll
+func (p *JiraPoller) SetInterval(interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultJiraPollInterval
+	}
+	p.interval.Store(int64(interval))
+}
+
+// SetEnabled turns scheduled polling on or off; Refresh works either way
+func (p *JiraPoller) SetEnabled(enabled bool) {
+	p.enabled.Store(enabled)
+}
+
+// SetFillDeadlines controls whether a task without a deadline takes its ticket's due date
+func (p *JiraPoller) SetFillDeadlines(fill bool) {
+	p.fillDeadlines.Store(fill)
+}
+
+func (p *JiraPoller) Start() {
+	go func() {
+		for {
+			if p.enabled.Load() {
+				if _, err := p.poll(jiraMaxRequestsPerPass); err != nil {
+					log.Printf("Failed to poll JIRA tickets: %v", err)
+				}
+			}
+
+			select {
+			case <-p.ctx.Done():
+				return
+			case <-time.After(time.Duration(p.interval.Load())):
+			}
+		}
+	}()
+}
+
+func (p *JiraPoller) Stop() {
+	p.cancelFunc()
+}
+
+// Refresh polls every linked ticket now, regardless of the per-pass request bound, and returns
+// the number of tasks that changed
+func (p *JiraPoller) Refresh() (int, error) {
+	return p.poll(0)
+}
+
+// poll refreshes tasks' tickets with batched JQL searches, making at most maxRequests requests
+// (0 for no bound), and notifies when a ticket is done or is reassigned away from the current user
+func (p *JiraPoller) poll(maxRequests int) (int, error) {
+	if p.taskModel == nil || p.jiraClient == nil {
+		return 0, nil
+	}
+	client := p.jiraClient()
+	if client == nil {
+		return 0, fmt.Errorf("JIRA client not configured")
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if wait := time.Until(p.retryAt); wait > 0 {
+		return 0, fmt.Errorf("JIRA rate limit reached, retry in %s", wait.Round(time.Second))
+	}
+
+	tasks, err := p.taskModel.GetWithJiraTickets()
+	if err != nil {
+		return 0, err
+	}
+	if len(tasks) == 0 {
+		return 0, nil
+	}
+
+	tasksByKey := make(map[string][]*types.Task)
+	for _, task := range tasks {
+		key := strings.ToUpper(strings.TrimSpace(task.JiraTicketID))
+		tasksByKey[key] = append(tasksByKey[key], task)
+	}
+	keys := make([]string, 0, len(tasksByKey))
+	for key := range tasksByKey {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	requests := 0
+	if p.me.client != client {
+		// Only needed to notice reassignments, so a failure doesn't stop the poll
+		requests++
+		if me, err := client.GetMyself(); err != nil {
+			if p.rateLimited(err) {
+				return 0, err
+			}
+			log.Printf("Failed to get current JIRA user: %v", err)
+		} else {
+			p.me.client = client
+			p.me.id = me.ID()
+		}
+	}
+
+	if maxRequests > 0 {
+		// Continue where the previous pass stopped so every ticket is polled eventually
+		start := p.cursor % len(keys)
+		keys = append(keys[start:], keys[:start]...)
+		if limit := (maxRequests - requests) * jira.MaxKeysPerSearch; len(keys) > limit {
+			log.Printf("Polling %d of %d JIRA tickets this pass", limit, len(keys))
+			keys = keys[:limit]
+		}
+		p.cursor = (start + len(keys)) % len(tasksByKey)
+	}
+
+	issues, err := client.SearchIssuesByKeys(keys)
+	if err != nil {
+		p.rateLimited(err)
+	}
+
+	changed := 0
+	for _, issue := range issues {
+		for _, task := range tasksByKey[strings.ToUpper(issue.Key)] {
+			if p.applyIssue(client, task, issue) {
+				changed++
+			}
+		}
+	}
+
+	if changed > 0 {
+		log.Printf("Updated %d tasks from JIRA", changed)
+		if p.onDataChanged != nil {
+			// Tasks don't belong to a repository
+			p.onDataChanged(types.DataChangedEvent{
+				Entities:      []types.EntityType{types.EntityTasks},
+				RepositoryIDs: []int64{0},
+				Changes:       map[types.EntityType][]int64{types.EntityTasks: {0}},
+			})
+		}
+	}
+
+	return changed, err
+}
+
+// rateLimited reports whether err is a JIRA rate limit and, if so, holds off polling until JIRA
+// allows requests again
+func (p *JiraPoller) rateLimited(err error) bool {
+	var rateLimitErr *jira.RateLimitError
+	if !errors.As(err, &rateLimitErr) {
+		return false
+	}
+	p.retryAt = time.Now().Add(rateLimitErr.RetryAfter)
+	log.Printf("JIRA rate limit reached, pausing polls for %s", rateLimitErr.RetryAfter)
+	return true
+}
+
+// SetJiraFields copies the fields of a ticket a task keeps onto the task
+func SetJiraFields(task *types.Task, client *jira.Client, issue *jira.Issue) {
+	card := client.CardFields(issue)
+	task.JiraTitle = issue.Fields.Summary
+	task.JiraStatus = issue.Fields.Status.Name
+	task.JiraAssignee = issue.Fields.Assignee.ID()
+	task.JiraAssigneeName = card.AssigneeName
+	task.JiraDueDate = card.DueDate
+	task.JiraSprint = card.Sprint
+	task.JiraLabels = card.Labels
+	task.JiraParentKey = card.ParentKey
+}
+
+// jiraFieldsEqual reports whether two tasks keep the same ticket fields
+func jiraFieldsEqual(a, b *types.Task) bool {
+	sameDueDate := a.JiraDueDate == nil && b.JiraDueDate == nil ||
+		a.JiraDueDate != nil && b.JiraDueDate != nil && a.JiraDueDate.Equal(*b.JiraDueDate)
+	return a.JiraTitle == b.JiraTitle && a.JiraStatus == b.JiraStatus && a.JiraAssignee == b.JiraAssignee &&
+		a.JiraAssigneeName == b.JiraAssigneeName && sameDueDate && a.JiraSprint == b.JiraSprint &&
+		slices.Equal(a.JiraLabels, b.JiraLabels) && a.JiraParentKey == b.JiraParentKey
+}
+
+// applyIssue stores the ticket's fields on the task if they changed, and fills the task's deadline
+// from the ticket's due date when configured to. The first poll of a task only records the
+// ticket's state; later polls notify about transitions to done and reassignments.
+func (p *JiraPoller) applyIssue(client *jira.Client, task *types.Task, issue jira.Issue) bool {
+	updated := *task
+	SetJiraFields(&updated, client, &issue)
+	fillDeadline := p.fillDeadlines.Load() && task.Deadline == nil && updated.JiraDueDate != nil
+	if jiraFieldsEqual(task, &updated) && !fillDeadline {
+		return false
+	}
+
+	if err := p.taskModel.UpdateJiraFields(&updated); err != nil {
+		log.Printf("Failed to update task %d from JIRA: %v", task.ID, err)
+		return false
+	}
+	if fillDeadline {
+		if err := p.taskModel.FillDeadline(task.ID, *updated.JiraDueDate); err != nil {
+			log.Printf("Failed to fill the deadline of task %d from JIRA: %v", task.ID, err)
+		} else {
+			updated.Deadline = updated.JiraDueDate
+		}
+	}
+
+	status, assignee := updated.JiraStatus, updated.JiraAssignee
+	if task.JiraStatus != "" && status != task.JiraStatus && issue.Fields.Status.StatusCategory.Key == "done" {
+		p.notify("jira_ticket_done", fmt.Sprintf("%s is %s", issue.Key, status),
+			fmt.Sprintf("%s moved from %s to %s. Task: %s", issue.Key, task.JiraStatus, status, task.Title))
+	}
+
+	me := p.me.id
+	if me != "" && task.JiraAssignee == me && assignee != me {
+		assignedTo := "unassigned"
+		if issue.Fields.Assignee != nil {
+			assignedTo = "assigned to " + issue.Fields.Assignee.DisplayName
+		}
+		p.notify("jira_ticket_reassigned", fmt.Sprintf("%s was reassigned", issue.Key),
+			fmt.Sprintf("%s is no longer assigned to you; it is now %s. Task: %s", issue.Key, assignedTo, task.Title))
+	}
+
+	*task = updated
+	return true
+}
+
+func (p *JiraPoller) notify(notificationType, title, message string) {
+	p.notifier.Notify(&types.Notification{Type: notificationType, Title: title, Message: message})
+}
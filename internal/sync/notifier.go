@@ -0,0 +1,169 @@
+package sync
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"dev-dashboard/internal/models"
+	"dev-dashboard/pkg/types"
+)
+
+// QuietWindow is a daily span of local time, e.g. 22:00-07:00. Windows past midnight end on the
+// next day.
+type QuietWindow struct {
+	Start time.Duration // since midnight
+	End   time.Duration
+}
+
+// ParseQuietWindow parses "HH:MM-HH:MM". An empty string or "off" means no quiet hours (nil).
+func ParseQuietWindow(value string) (*QuietWindow, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || strings.EqualFold(value, "off") {
+		return nil, nil
+	}
+
+	startValue, endValue, ok := strings.Cut(value, "-")
+	if !ok {
+		return nil, fmt.Errorf("invalid quiet hours %q, expected HH:MM-HH:MM", value)
+	}
+	start, err := parseClock(startValue)
+	if err != nil {
+		return nil, fmt.Errorf("invalid quiet hours %q: %w", value, err)
+	}
+	end, err := parseClock(endValue)
+	if err != nil {
+		return nil, fmt.Errorf("invalid quiet hours %q: %w", value, err)
+	}
+	if start == end {
+		return nil, fmt.Errorf("invalid quiet hours %q: start and end are the same", value)
+	}
+	return &QuietWindow{Start: start, End: end}, nil
+}
+
+func parseClock(value string) (time.Duration, error) {
+	clock, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a HH:MM time", strings.TrimSpace(value))
+	}
+	return time.Duration(clock.Hour())*time.Hour + time.Duration(clock.Minute())*time.Minute, nil
+}
+
+// ParseEnvironmentQuietWindows parses per-environment windows such as "dev=20:00-09:00,prd=off".
+// Environments mapped to nil have no quiet hours.
+func ParseEnvironmentQuietWindows(value string) (map[string]*QuietWindow, error) {
+	windows := make(map[string]*QuietWindow)
+	for _, entry := range strings.Split(value, ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		environment, windowValue, ok := strings.Cut(entry, "=")
+		environment = strings.ToLower(strings.TrimSpace(environment))
+		if !ok || environment == "" {
+			return nil, fmt.Errorf("invalid environment quiet hours %q, expected env=HH:MM-HH:MM", strings.TrimSpace(entry))
+		}
+		window, err := ParseQuietWindow(windowValue)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", environment, err)
+		}
+		windows[environment] = window
+	}
+	return windows, nil
+}
+
+// clock returns the wall clock time of t as time since midnight
+func clock(t time.Time) time.Duration {
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+}
+
+// Contains reports whether t falls in the window
+func (w *QuietWindow) Contains(t time.Time) bool {
+	c := clock(t)
+	if w.Start < w.End {
+		return c >= w.Start && c < w.End
+	}
+	return c >= w.Start || c < w.End
+}
+
+// NextEnd returns the first end of the window after t
+func (w *QuietWindow) NextEnd(t time.Time) time.Time {
+	year, month, day := t.Date()
+	end := time.Date(year, month, day, int(w.End/time.Hour), int(w.End%time.Hour/time.Minute), 0, 0, t.Location())
+	if !end.After(t) {
+		end = end.AddDate(0, 0, 1)
+	}
+	return end
+}
+
+// QuietHours decides which notifications are held back. Notifications for an environment use
+// that environment's window when one is configured and the global window otherwise.
+type QuietHours struct {
+	Window             *QuietWindow
+	Environments       map[string]*QuietWindow
+	ExemptEnvironments map[string]bool
+	ExemptTypes        map[string]bool
+	// Suppress drops held back notifications instead of queuing them until the window ends
+	Suppress bool
+}
+
+// windowFor returns the window that applies to notifications about environment
+func (q *QuietHours) windowFor(environment string) *QuietWindow {
+	if environment != "" {
+		if window, ok := q.Environments[strings.ToLower(environment)]; ok {
+			return window
+		}
+	}
+	return q.Window
+}
+
+// Hold reports whether the notification falls in quiet hours at now and, if so, when the window
+// ends. Exempt environments and notification types are never held.
+func (q *QuietHours) Hold(notification *types.Notification, now time.Time) (time.Time, bool) {
+	if q == nil || q.ExemptTypes[notification.Type] || q.ExemptEnvironments[strings.ToLower(notification.Environment)] {
+		return time.Time{}, false
+	}
+	window := q.windowFor(notification.Environment)
+	if window == nil || !window.Contains(now) {
+		return time.Time{}, false
+	}
+	return window.NextEnd(now), true
+}
+
+// Notifier stores notifications raised by background work, holding back those that fall in the
+// configured quiet hours
+type Notifier struct {
+	notificationModel *models.NotificationModel
+	quietHours        atomic.Pointer[QuietHours]
+}
+
+func NewNotifier(notificationModel *models.NotificationModel) *Notifier {
+	return &Notifier{notificationModel: notificationModel}
+}
+
+// SetQuietHours replaces the quiet hours; nil turns them off
+func (n *Notifier) SetQuietHours(quietHours *QuietHours) {
+	n.quietHours.Store(quietHours)
+}
+
+// Notify stores the notification, queued until the end of quiet hours or dropped when quiet hours
+// suppress notifications. Failures are only logged.
+func (n *Notifier) Notify(notification *types.Notification) {
+	if n == nil || n.notificationModel == nil {
+		return
+	}
+
+	quietHours := n.quietHours.Load()
+	if deliverAt, held := quietHours.Hold(notification, time.Now()); held {
+		if quietHours.Suppress {
+			log.Printf("Suppressed %s notification during quiet hours: %s", notification.Type, notification.Title)
+			return
+		}
+		notification.DeliverAt = &deliverAt
+	}
+
+	if err := n.notificationModel.Create(notification); err != nil {
+		log.Printf("Failed to create notification: %v", err)
+	}
+}
@@ -0,0 +1,174 @@
+package sync
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"dev-dashboard/internal/github"
+	"dev-dashboard/internal/packages"
+	"dev-dashboard/pkg/types"
+)
+
+const (
+	// packageVersionRefreshInterval is how long a looked up latest version is trusted before it's
+	// looked up again
+	packageVersionRefreshInterval = 24 * time.Hour
+	// packageVersionLookupsPerPass bounds the registry lookups of one repository sync, so a large
+	// repository's packages are looked up over several passes
+	packageVersionLookupsPerPass = 100
+	// packageRegistryRequestsPerSecond paces requests to the Go module proxy and npm registry
+	packageRegistryRequestsPerSecond = 5
+)
+
+// SetPackageVersionLookup turns looking up the latest versions of services' packages on or off
+func (s *Service) SetPackageVersionLookup(enabled bool) {
+	s.packageVersionLookup.Store(enabled)
+}
+
+// syncPackages reads the direct dependencies of the services of a repository that collects
+// packages from their go.mod and package.json. A manifest is only read and parsed again when its
+// blob SHA changed. With version lookups on, the latest versions of the packages are looked up
+// afterwards; lookup failures are recorded per package and don't fail the phase.
+func (s *Service) syncPackages(repo *types.Repository, owner, repoName string, results *phaseResults) error {
+	if !repo.CollectPackages || s.servicePackageModel == nil {
+		return nil
+	}
+	services, err := s.microserviceModel.GetByRepositoryID(repo.ID, true)
+	if err != nil {
+		return fmt.Errorf("failed to get services of %s: %w", repo.Name, err)
+	}
+
+	var errs []error
+	changed := false
+	for _, service := range services {
+		serviceChanged, err := s.syncServicePackages(service, owner, repoName, results)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to read packages of %s: %w", service.Name, err))
+		}
+		changed = changed || serviceChanged
+	}
+
+	if s.packageVersionLookup.Load() && s.lookUpPackageVersions(repo, results) {
+		changed = true
+	}
+	if changed {
+		s.changes.mark(types.EntityPackages, repo.ID)
+	}
+	return errors.Join(errs...)
+}
+
+// syncServicePackages re-reads the manifests in a service's directory whose blob SHA changed and
+// forgets the ones that are gone. It reports whether anything changed.
+func (s *Service) syncServicePackages(service *types.Microservice, owner, repoName string, results *phaseResults) (bool, error) {
+	files, err := s.githubClient.ListDirectoryFiles(s.requestContext(service.RepositoryID), owner, repoName, service.Path)
+	if err != nil {
+		return false, err
+	}
+	stored, err := s.servicePackageModel.GetManifests(service.ID)
+	if err != nil {
+		return false, err
+	}
+	storedSHA := make(map[string]string, len(stored))
+	for _, manifest := range stored {
+		storedSHA[manifest.Path] = manifest.BlobSHA
+	}
+
+	changed := false
+	present := []string{}
+	for _, file := range files {
+		ecosystem, ok := packages.Manifests[file.Name]
+		if !ok {
+			continue
+		}
+		present = append(present, file.Path)
+		if storedSHA[file.Path] == file.SHA {
+			continue
+		}
+		if err := s.storeManifest(service, file, ecosystem, owner, repoName, results); err != nil {
+			return changed, err
+		}
+		changed = true
+	}
+
+	if len(present) < len(stored) {
+		changed = true
+	}
+	results.store(func(m *phaseModels) error {
+		return m.servicePackages.DeleteOtherManifests(service.ID, present)
+	})
+	return changed, nil
+}
+
+// storeManifest parses a manifest and replaces the packages stored for it. A manifest that can't
+// be parsed is stored with the error and no packages, so it isn't read again until it changes.
+func (s *Service) storeManifest(service *types.Microservice, file github.RepositoryFile, ecosystem packages.Ecosystem, owner, repoName string, results *phaseResults) error {
+	content, err := s.githubClient.GetFileText(s.requestContext(service.RepositoryID), owner, repoName, file.Path)
+	if err != nil {
+		return err
+	}
+
+	manifest := &types.ServicePackageManifest{
+		ServiceID: service.ID,
+		Path:      file.Path,
+		Ecosystem: string(ecosystem),
+		BlobSHA:   file.SHA,
+		ParsedAt:  time.Now(),
+	}
+	var servicePackages []*types.ServicePackage
+	deps, err := packages.Parse(ecosystem, content)
+	if err != nil {
+		log.Printf("Failed to parse %s of service %s: %v", file.Path, service.Name, err)
+		manifest.ParseError = err.Error()
+	}
+	for _, dep := range deps {
+		servicePackages = append(servicePackages, &types.ServicePackage{Name: dep.Name, Version: dep.Version, Dev: dep.Dev})
+	}
+
+	results.store(func(m *phaseModels) error {
+		if err := m.servicePackages.ReplaceManifest(manifest, servicePackages); err != nil {
+			return err
+		}
+		log.Printf("Stored %d packages of service %s from %s", len(servicePackages), service.Name, file.Path)
+		return nil
+	})
+	return nil
+}
+
+// lookUpPackageVersions looks up the latest versions of a repository's packages that weren't
+// looked up within the refresh interval, and reports whether any were looked up
+func (s *Service) lookUpPackageVersions(repo *types.Repository, results *phaseResults) bool {
+	unchecked, err := s.servicePackageModel.GetUncheckedVersions(repo.ID, time.Now().Add(-packageVersionRefreshInterval), packageVersionLookupsPerPass)
+	if err != nil {
+		log.Printf("Failed to get packages of %s to look up: %v", repo.Name, err)
+		return false
+	}
+
+	lookedUp := 0
+	for _, pkg := range unchecked {
+		ctx := s.requestContext(repo.ID)
+		latest, err := s.packageRegistry.Latest(ctx, packages.Ecosystem(pkg.Ecosystem), pkg.Name)
+		if ctx.Err() != nil {
+			break
+		}
+		lookupError := ""
+		if err != nil {
+			lookupError = err.Error()
+			if !errors.Is(err, packages.ErrPackageNotFound) {
+				log.Printf("Failed to look up the latest version of %s: %v", pkg.Name, err)
+			}
+		}
+		results.store(func(m *phaseModels) error {
+			if err := m.servicePackages.SetLatestVersion(pkg.Ecosystem, pkg.Name, latest, lookupError); err != nil {
+				log.Printf("%v", err)
+			}
+			return nil
+		})
+		lookedUp++
+	}
+	if lookedUp > 0 {
+		log.Printf("Looked up the latest versions of %d packages of %s", lookedUp, repo.Name)
+	}
+	return lookedUp > 0
+}
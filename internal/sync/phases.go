@@ -0,0 +1,323 @@
+package sync
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	gosync "sync"
+	"time"
+
+	"dev-dashboard/internal/database"
+	"dev-dashboard/internal/models"
+	"dev-dashboard/pkg/types"
+)
+
+// checkpointResumeWindow is how long the checkpoint of an interrupted pass is resumed from. Older
+// checkpoints are discarded and the next pass runs every phase.
+const checkpointResumeWindow = time.Hour
+
+// syncPhase is a step of a repository sync. A required phase that fails ends the pass; any other
+// failure is logged and the pass goes on. A phase makes its requests first and collects what it
+// found, which is stored once they're done; a phase interrupted half way stores nothing and is
+// simply run again.
+type syncPhase struct {
+	name     types.SyncPhase
+	required bool
+	run      func(results *phaseResults) error
+}
+
+// phaseResults collects what a phase found, to be stored when its requests are done
+type phaseResults struct {
+	writes        []func(m *phaseModels) error
+	announcements []func()
+}
+
+// store adds a write of what the phase found. Writes run in order in the phase's transaction, so
+// they must not make requests, and read and write through m only.
+func (r *phaseResults) store(write func(m *phaseModels) error) {
+	r.writes = append(r.writes, write)
+}
+
+// announce adds a notification or sync log entry about what the phase stored, raised once it's
+// committed
+func (r *phaseResults) announce(raise func()) {
+	r.announcements = append(r.announcements, raise)
+}
+
+// write runs every write, even after one failed, and returns their errors
+func (r *phaseResults) write(m *phaseModels) error {
+	var errs []error
+	for _, write := range r.writes {
+		if err := write(m); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// raise raises the announcements of the stored results
+func (r *phaseResults) raise() {
+	for _, raise := range r.announcements {
+		raise()
+	}
+}
+
+// phaseModels are the models phases store what they found with. With transactions they're built
+// on the transactions' connection and only used while a phase's transaction is open, so nothing
+// else ends up in it; without, they're the service's own.
+type phaseModels struct {
+	repositories     *models.RepositoryModel
+	services         *models.MicroserviceModel
+	resources        *models.KubernetesResourceModel
+	actions          *models.ActionModel
+	deployments      *models.DeploymentModel
+	approvals        *models.PendingApprovalModel
+	usage            *models.ActionsUsageModel
+	discoveryChanges *models.DiscoveryChangeModel
+	envVarSnapshots  *models.EnvVarSnapshotModel
+	securityAlerts   *models.SecurityAlertModel
+	servicePackages  *models.ServicePackageModel
+}
+
+func newPhaseModels(conn *sql.DB) *phaseModels {
+	return &phaseModels{
+		repositories:     models.NewRepositoryModel(conn),
+		services:         models.NewMicroserviceModel(conn),
+		resources:        models.NewKubernetesResourceModel(conn),
+		actions:          models.NewActionModel(conn),
+		deployments:      models.NewDeploymentModel(conn),
+		approvals:        models.NewPendingApprovalModel(conn),
+		usage:            models.NewActionsUsageModel(conn),
+		discoveryChanges: models.NewDiscoveryChangeModel(conn),
+		envVarSnapshots:  models.NewEnvVarSnapshotModel(conn),
+		securityAlerts:   models.NewSecurityAlertModel(conn),
+		servicePackages:  models.NewServicePackageModel(conn),
+	}
+}
+
+// ownPhaseModels returns the service's own models, for phases storing their results as they go
+func (s *Service) ownPhaseModels() *phaseModels {
+	return &phaseModels{
+		repositories:     s.repoModel,
+		services:         s.microserviceModel,
+		resources:        s.kubernetesModel,
+		actions:          s.actionModel,
+		deployments:      s.deploymentModel,
+		approvals:        s.approvalModel,
+		usage:            s.usageModel,
+		discoveryChanges: s.discoveryChangeModel,
+		envVarSnapshots:  s.envVarSnapshotModel,
+		securityAlerts:   s.securityAlertModel,
+		servicePackages:  s.servicePackageModel,
+	}
+}
+
+// errSyncIncomplete is returned when a pass ran to its end but not every phase succeeded
+var errSyncIncomplete = errors.New("sync incomplete")
+
+// ErrSyncInProgress is returned when a sync of the repository is already running, e.g. the
+// scheduled pass or a manual sync clicked twice
+var ErrSyncInProgress = errors.New("sync already running")
+
+// runPhases runs a repository's sync phases in order, checkpointing each completed phase in the
+// repository's sync_state so a pass interrupted by quitting the app or by the watchdog resumes where
+// it stopped. What a phase found is stored in a transaction that commits together with the
+// checkpoint of its outcome, so what's stored always matches the checkpoint, while its requests
+// run before the transaction begins. The checkpoint is cleared when the pass ends, and the last
+// sync time is only updated when every phase completed. The caller must have claimed the repository.
+func (s *Service) runPhases(repo *types.Repository, phases []syncPhase) error {
+	state := repo.SyncState
+	if state != nil && time.Since(state.StartedAt) < checkpointResumeWindow {
+		log.Printf("Resuming interrupted sync of %s after phases %v", repo.Name, state.Completed)
+		s.logSync(repo.ID, types.SyncLogInfo, fmt.Sprintf("Resuming an interrupted sync; skipping completed phases: %s", joinPhases(state.Completed)))
+		state.Failed = nil
+	} else {
+		state = &types.SyncState{StartedAt: time.Now(), Completed: []types.SyncPhase{}}
+	}
+
+	for _, phase := range phases {
+		if state.Done(phase.name) {
+			continue
+		}
+
+		// So the sync status shows the phase running
+		state.Phase = phase.name
+		s.checkpoint(repo, state)
+		s.heartbeat(repo, string(phase.name))
+		results := &phaseResults{}
+		err := phase.run(results)
+
+		// Leave the checkpoint for the next pass when the app is quitting or the pass was cancelled
+		if ctx := s.requestContext(repo.ID); ctx.Err() != nil {
+			return fmt.Errorf("sync of %s interrupted in phase %s: %w", repo.Name, phase.name, ctx.Err())
+		}
+		if err := s.endPhase(repo, state, phase.name, results, err); err != nil {
+			if phase.required {
+				s.finishPass(repo, state)
+				return err
+			}
+			log.Printf("Sync phase %s failed for %s: %v", phase.name, repo.Name, err)
+		}
+	}
+
+	s.finishPass(repo, state)
+	if len(state.Failed) > 0 {
+		return fmt.Errorf("%w: phases %s failed", errSyncIncomplete, joinPhases(state.Failed))
+	}
+	return nil
+}
+
+// endPhase stores what a phase that ran to its end found, failed or not, and the checkpoint of its
+// outcome in one transaction, then raises the phase's announcements. A phase whose writes failed
+// counts as failed. It returns the phase's error, or the store's; when the transaction can't
+// commit nothing the phase found is kept and it counts as failed.
+func (s *Service) endPhase(repo *types.Repository, state *types.SyncState, phase types.SyncPhase, results *phaseResults, err error) error {
+	tx, beginErr := s.beginPhase()
+	if beginErr != nil {
+		state.Failed = append(state.Failed, phase)
+		s.checkpoint(repo, state)
+		return errors.Join(err, beginErr)
+	}
+
+	if writeErr := results.write(s.phaseModels); writeErr != nil {
+		err = errors.Join(err, writeErr)
+	}
+	if err != nil {
+		state.Failed = append(state.Failed, phase)
+	} else {
+		state.Completed = append(state.Completed, phase)
+	}
+	if checkpointErr := s.phaseModels.repositories.SetSyncState(repo.ID, state); checkpointErr != nil {
+		log.Printf("Failed to checkpoint sync of %s: %v", repo.Name, checkpointErr)
+	}
+	repo.SyncState = state
+
+	if tx != nil {
+		if commitErr := tx.Commit(); commitErr != nil {
+			if err == nil {
+				state.Completed = state.Completed[:len(state.Completed)-1]
+				state.Failed = append(state.Failed, phase)
+				err = fmt.Errorf("failed to store sync phase %s: %w", phase, commitErr)
+			}
+			s.checkpoint(repo, state)
+			return err
+		}
+	}
+	results.raise()
+	return err
+}
+
+// beginPhase begins the transaction a phase's results are stored in, or returns nil without
+// transactions
+func (s *Service) beginPhase() (*database.WriterTx, error) {
+	if s.transactions == nil {
+		return nil, nil
+	}
+	tx, err := s.transactions.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin storing sync phase: %w", err)
+	}
+	return tx, nil
+}
+
+// checkpoint stores the state of the pass in progress; a failure only costs the ability to resume
+func (s *Service) checkpoint(repo *types.Repository, state *types.SyncState) {
+	if err := s.repoModel.SetSyncState(repo.ID, state); err != nil {
+		log.Printf("Failed to checkpoint sync of %s: %v", repo.Name, err)
+	}
+	repo.SyncState = state
+}
+
+// finishPass clears the checkpoint of a pass that ran to its end and records the sync time when
+// every phase completed
+func (s *Service) finishPass(repo *types.Repository, state *types.SyncState) {
+	if err := s.repoModel.SetSyncState(repo.ID, nil); err != nil {
+		log.Printf("Failed to clear sync checkpoint of %s: %v", repo.Name, err)
+	}
+	repo.SyncState = nil
+
+	if len(state.Failed) > 0 {
+		return
+	}
+	if err := s.repoModel.UpdateLastSync(repo.ID); err != nil {
+		log.Printf("Failed to update last sync time for repository %s: %v", repo.Name, err)
+	}
+}
+
+// Syncing reports whether a pass over the repository is running in this session
+func (s *Service) Syncing(repositoryID int64) bool {
+	return s.syncing.has(repositoryID)
+}
+
+// WithRepositoryClaimed runs fn with the repository claimed as though it were syncing, so work
+// outside the sync, e.g. rediscovering its services, doesn't write what a sync of it is writing.
+// It returns ErrSyncInProgress without running fn when the repository is syncing.
+func (s *Service) WithRepositoryClaimed(ctx context.Context, repositoryID int64, fn func() error) error {
+	release, err := s.claim(ctx, repositoryID)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return fn()
+}
+
+func joinPhases(phases []types.SyncPhase) string {
+	names := make([]string, len(phases))
+	for i, phase := range phases {
+		names[i] = string(phase)
+	}
+	return strings.Join(names, ", ")
+}
+
+// claim marks a repository as syncing under ctx until the returned function is called, or returns
+// ErrSyncInProgress when another sync of it is running. Every way into syncRepository claims the
+// repository first, so two syncs never write its deployments and runs at the same time. The sync's
+// requests run under ctx: the pass's for scheduled syncs, the service's for manual ones.
+func (s *Service) claim(ctx context.Context, repositoryID int64) (func(), error) {
+	if !s.syncing.start(ctx, repositoryID) {
+		return nil, fmt.Errorf("repository %d: %w", repositoryID, ErrSyncInProgress)
+	}
+	return func() { s.syncing.finish(repositoryID) }, nil
+}
+
+// syncingSet holds the repositories being synced with the contexts their syncs run under, so a
+// manual sync doesn't run over a scheduled one
+type syncingSet struct {
+	mu    gosync.Mutex
+	repos map[int64]context.Context
+}
+
+func newSyncingSet() *syncingSet {
+	return &syncingSet{repos: make(map[int64]context.Context)}
+}
+
+// start marks a repository as syncing under ctx, returning false when it already is
+func (s *syncingSet) start(ctx context.Context, repositoryID int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.repos[repositoryID]; ok {
+		return false
+	}
+	s.repos[repositoryID] = ctx
+	return true
+}
+
+func (s *syncingSet) finish(repositoryID int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.repos, repositoryID)
+}
+
+func (s *syncingSet) has(repositoryID int64) bool {
+	return s.context(repositoryID) != nil
+}
+
+// context returns the context the repository's sync runs under, or nil when it isn't syncing
+func (s *syncingSet) context(repositoryID int64) context.Context {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.repos[repositoryID]
+}
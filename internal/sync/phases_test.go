@@ -0,0 +1,224 @@
+package sync
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"dev-dashboard/internal/database"
+	"dev-dashboard/internal/models"
+	"dev-dashboard/internal/testsupport"
+	"dev-dashboard/pkg/types"
+)
+
+// phaseFixtures returns a database with a monorepo and a kubernetes repository deploying two of
+// its services, alpha at a commit and beta at tag
+func phaseFixtures(t *testing.T, fake *fakeGitHub, tag string) (db *database.DB, mono, k8s *types.Repository) {
+	t.Helper()
+	db = testsupport.NewTestDatabase(t)
+	mono = testsupport.Repository(t, db.GetConn())
+	alpha := testsupport.Service(t, db.GetConn(), mono.ID, func(service *types.Microservice) { service.Name = "alpha" })
+	beta := testsupport.Service(t, db.GetConn(), mono.ID, func(service *types.Microservice) { service.Name = "beta" })
+	k8s = testsupport.KubernetesRepository(t, db.GetConn())
+
+	fake.handleRepository(k8s)
+	fake.handleContents(k8s, map[string]string{
+		overlayPath(alpha.Name): kustomization(alpha.Name, strings.Repeat("a", 40)),
+		overlayPath(beta.Name):  kustomization(beta.Name, tag),
+	})
+	return db, mono, k8s
+}
+
+func overlayPath(serviceName string) string {
+	return "services/" + serviceName + "/overlays/dev/us-east-1/kustomization.yaml"
+}
+
+// deploymentCount returns how many deployments are stored for the repository
+func deploymentCount(t *testing.T, db *sql.DB, repositoryID int64) int {
+	t.Helper()
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM deployments WHERE kubernetes_repo_id = ?", repositoryID).Scan(&count); err != nil {
+		t.Fatalf("failed to count deployments: %v", err)
+	}
+	return count
+}
+
+// storedSyncState returns the repository's stored checkpoint
+func storedSyncState(t *testing.T, db *sql.DB, repositoryID int64) *types.SyncState {
+	t.Helper()
+	repo, err := models.NewRepositoryModel(db).GetByID(repositoryID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	return repo.SyncState
+}
+
+// syncUntilStopped syncs the repository, stops the service once started is signalled and returns
+// the sync's error
+func syncUntilStopped(t *testing.T, service *Service, repositoryID int64, started <-chan struct{}) error {
+	t.Helper()
+	done := make(chan error, 1)
+	go func() { done <- service.SyncRepository(repositoryID) }()
+	waitFor(t, started, "the sync to call GitHub")
+	service.Stop()
+
+	finished := make(chan struct{})
+	var err error
+	go func() {
+		err = <-done
+		close(finished)
+	}()
+	waitFor(t, finished, "the stopped sync to return")
+	return err
+}
+
+func TestStoppedSyncResumesAfterCompletedPhases(t *testing.T) {
+	fake := newFakeGitHub()
+	db, _, k8s := phaseFixtures(t, fake, strings.Repeat("b", 40))
+
+	// The resources phase hangs until the app quits
+	var slow atomic.Bool
+	slow.Store(true)
+	started := make(chan struct{}, 1)
+	fake.handle(repositoryPath(k8s)+"/contents/k8s", slowUntilCancelled(&slow, started, respondWith(http.StatusNotFound)))
+
+	err := syncUntilStopped(t, newServiceOn(t, fake, db), k8s.ID, started)
+	if err == nil || !strings.Contains(err.Error(), "interrupted in phase resources") {
+		t.Fatalf("got error %v, want the sync interrupted in the resources phase", err)
+	}
+	if n := deploymentCount(t, db.GetConn(), k8s.ID); n != 2 {
+		t.Errorf("got %d deployments, want the completed deployments phase's 2", n)
+	}
+	state := storedSyncState(t, db.GetConn(), k8s.ID)
+	if state == nil || !slices.Equal(state.Completed, []types.SyncPhase{types.SyncPhaseDeployments}) || state.Phase != types.SyncPhaseResources {
+		t.Fatalf("got checkpoint %+v, want deployments completed and resources running", state)
+	}
+
+	// The app restarts and the next sync picks up at the resources phase
+	slow.Store(false)
+	scans := fake.requestCount(repositoryPath(k8s) + "/contents/" + overlayPath("alpha"))
+	if err := newServiceOn(t, fake, db).SyncRepository(k8s.ID); err != nil && !errors.Is(err, errSyncIncomplete) {
+		t.Fatalf("SyncRepository: %v", err)
+	}
+	if n := fake.requestCount(repositoryPath(k8s)+"/contents/"+overlayPath("alpha")) - scans; n != 0 {
+		t.Errorf("the resumed sync read the kustomization %d times, want the completed phase skipped", n)
+	}
+	if n := fake.requestCount(repositoryPath(k8s) + "/contents/k8s"); n != 2 {
+		t.Errorf("resources looked up %d times, want again after the restart", n)
+	}
+	if state := storedSyncState(t, db.GetConn(), k8s.ID); state != nil {
+		t.Errorf("got checkpoint %+v after the sync ended, want it cleared", state)
+	}
+}
+
+func TestStoppedSyncStoresNothingOfItsPhase(t *testing.T) {
+	fake := newFakeGitHub()
+	// Beta's tag isn't a commit, so its deployment waits on the monorepo's commits while alpha's
+	// has already been found
+	db, mono, k8s := phaseFixtures(t, fake, "v1.2.3")
+	var slow atomic.Bool
+	slow.Store(true)
+	started := make(chan struct{}, 1)
+	fake.handle(repositoryPath(mono)+"/commits", slowUntilCancelled(&slow, started, respondWith(http.StatusNotFound)))
+
+	if err := syncUntilStopped(t, newServiceOn(t, fake, db), k8s.ID, started); err == nil {
+		t.Fatal("the stopped sync reported success")
+	}
+	if n := deploymentCount(t, db.GetConn(), k8s.ID); n != 0 {
+		t.Errorf("got %d deployments, want nothing of the interrupted phase stored", n)
+	}
+	state := storedSyncState(t, db.GetConn(), k8s.ID)
+	if state == nil || len(state.Completed) != 0 || state.Phase != types.SyncPhaseDeployments {
+		t.Fatalf("got checkpoint %+v, want the deployments phase running and nothing completed", state)
+	}
+
+	// After the restart the phase runs again from the start
+	slow.Store(false)
+	if err := newServiceOn(t, fake, db).SyncRepository(k8s.ID); err != nil && !errors.Is(err, errSyncIncomplete) {
+		t.Fatalf("SyncRepository: %v", err)
+	}
+	if n := deploymentCount(t, db.GetConn(), k8s.ID); n != 2 {
+		t.Errorf("got %d deployments after resuming, want 2", n)
+	}
+	if state := storedSyncState(t, db.GetConn(), k8s.ID); state != nil {
+		t.Errorf("got checkpoint %+v after the sync ended, want it cleared", state)
+	}
+}
+
+func TestAppWritesWhileAPhaseWaitsOnGitHub(t *testing.T) {
+	fake := newFakeGitHub()
+	db, mono, k8s := phaseFixtures(t, fake, "v1.2.3")
+	var slow atomic.Bool
+	slow.Store(true)
+	started := make(chan struct{}, 1)
+	fake.handle(repositoryPath(mono)+"/commits", slowUntilCancelled(&slow, started, respondWith(http.StatusNotFound)))
+	service := newServiceOn(t, fake, db)
+
+	done := make(chan error, 1)
+	go func() { done <- service.SyncRepository(k8s.ID) }()
+	waitFor(t, started, "the sync to call GitHub")
+
+	// No transaction is open while the phase makes its requests, so the app's writes go through
+	if err := models.NewConfigModel(db.GetConn()).Set("timezone", "UTC"); err != nil {
+		t.Errorf("writing while the sync waits on GitHub: %v", err)
+	}
+	if err := models.NewNotificationModel(db.GetConn()).Create(&types.Notification{Type: "test", Title: "written meanwhile"}); err != nil {
+		t.Errorf("notifying while the sync waits on GitHub: %v", err)
+	}
+
+	slow.Store(false)
+	service.Stop()
+	finished := make(chan struct{})
+	go func() {
+		<-done
+		close(finished)
+	}()
+	waitFor(t, finished, "the stopped sync to return")
+	if n := len(notificationsOfType(t, db.GetConn(), "test")); n != 1 {
+		t.Errorf("got %d notifications written during the sync, want 1 kept after it was stopped", n)
+	}
+}
+
+func TestConcurrentManualSyncsRunOnce(t *testing.T) {
+	fake := newFakeGitHub()
+	service, db := newTestService(t, fake)
+	repo := testsupport.Repository(t, db)
+
+	// The lookup holds the first sync until the other has returned
+	release := make(chan struct{})
+	fake.handle(repositoryPath(repo), func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		respondWith(http.StatusUnauthorized)(w, r)
+	})
+
+	results := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() { results <- service.SyncRepository(repo.ID) }()
+	}
+	var first error
+	select {
+	case first = <-results:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a sync to return")
+	}
+	close(release)
+	second := <-results
+
+	if !errors.Is(first, ErrSyncInProgress) {
+		t.Errorf("the sync that returned first got %v, want ErrSyncInProgress", first)
+	}
+	if errors.Is(second, ErrSyncInProgress) {
+		t.Error("both syncs were turned away")
+	}
+	if n := fake.requestCount(repositoryPath(repo)); n != 1 {
+		t.Errorf("repository looked up %d times, want by one sync", n)
+	}
+	if service.Syncing(repo.ID) {
+		t.Error("the repository is still marked as syncing")
+	}
+}
@@ -0,0 +1,192 @@
+package sync
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"dev-dashboard/internal/github"
+	"dev-dashboard/internal/vcs"
+	"dev-dashboard/pkg/types"
+)
+
+// unreachableAfter is the number of sync passes in a row GitHub must answer 404 before a
+// repository is marked unreachable and left out of scheduled syncs
+const unreachableAfter = 3
+
+// maxAccessRetryDelay caps the backoff of repositories GitHub keeps answering 403 for
+const maxAccessRetryDelay = 24 * time.Hour
+
+// resolveRepository looks the repository up on GitHub before syncing it. A renamed or transferred
+// repository gets its stored URL updated and is synced under its new owner and name; repeated 404s
+// mark it unreachable. It also records the default branch.
+//
+// The lookup doubles as a cheap connectivity check: its outcome is recorded as the repository's
+// access state, and when GitHub answers 401, 403 or 404 the reason is stored as the repository's
+// last sync error and the sync stops before any scanning.
+func (s *Service) resolveRepository(repo *types.Repository, owner, repoName string) (string, string, error) {
+	repository, err := s.githubClient.GetRepository(s.requestContext(repo.ID), owner, repoName)
+	if errors.Is(err, github.ErrRepositoryNotFound) {
+		s.recordNotFound(repo)
+	}
+	state, httpStatus, reason := s.repositoryAccess(err, owner, repoName)
+	if state != "" {
+		s.recordAccess(repo, state, httpStatus)
+	}
+	if reason != "" {
+		s.setLastSyncError(repo, reason)
+		return "", "", fmt.Errorf("%s: %w", reason, err)
+	}
+	if err != nil {
+		// Rate limits and network errors say nothing about access; the next pass retries
+		return "", "", err
+	}
+	s.setLastSyncError(repo, "")
+
+	if err := s.repoModel.MarkReachable(repo.ID); err != nil {
+		log.Printf("Failed to mark repository %s reachable: %v", repo.Name, err)
+	}
+
+	// The build matrix only looks at builds on the default branch
+	if err := s.repoModel.UpdateDefaultBranch(repo.ID, repository.GetDefaultBranch()); err != nil {
+		log.Printf("Failed to update default branch for %s: %v", repo.Name, err)
+	}
+
+	fullName := repository.GetFullName()
+	if fullName == "" || strings.EqualFold(fullName, owner+"/"+repoName) {
+		return owner, repoName, nil
+	}
+
+	newOwner, newName, _ := strings.Cut(fullName, "/")
+	s.updateMovedRepository(repo, fullName)
+	return newOwner, newName, nil
+}
+
+// repositoryAccess classifies the outcome of a repository lookup as an access state with its HTTP
+// status and, for failures, the reason. The state is empty for errors that say nothing about access,
+// e.g. a network error or a rate limit.
+func (s *Service) repositoryAccess(err error, owner, repoName string) (types.RepositoryAccess, int, string) {
+	switch {
+	case err == nil:
+		return types.AccessOK, http.StatusOK, ""
+	case errors.Is(err, github.ErrUnauthorized):
+		return types.AccessTokenMissing, http.StatusUnauthorized, "GitHub rejected the token (401): it was revoked or has expired"
+	case s.githubToken == "" && (errors.Is(err, github.ErrRepositoryNotFound) || errors.Is(err, github.ErrForbidden)):
+		return types.AccessTokenMissing, github.StatusCode(err),
+			fmt.Sprintf("%s/%s isn't public and no GitHub token is configured", owner, repoName)
+	case errors.Is(err, github.ErrRepositoryNotFound):
+		return types.AccessNotFound, http.StatusNotFound,
+			fmt.Sprintf("GitHub returned 404 for %s/%s: the repository was deleted, or the token can't see it", owner, repoName)
+	case errors.Is(err, github.ErrForbidden):
+		return types.AccessForbidden, http.StatusForbidden,
+			fmt.Sprintf("GitHub denied access to %s/%s (403): the token lacks the repo scope or SSO authorization for the organization", owner, repoName)
+	}
+	return "", 0, ""
+}
+
+// recordAccess stores the repository's access state. Forbidden repositories are left out of
+// scheduled syncs for the sync interval, doubling with every forbidden lookup in a row up to a day;
+// any other outcome clears the backoff.
+func (s *Service) recordAccess(repo *types.Repository, state types.RepositoryAccess, httpStatus int) {
+	failures := 0
+	var retryAt *time.Time
+	if state == types.AccessForbidden {
+		failures = repo.AccessFailures + 1
+		next := time.Now().Add(accessRetryDelay(s.syncInterval, failures))
+		retryAt = &next
+		log.Printf("Access to repository %s is forbidden, retrying after %s", repo.Name, next.Format(time.RFC3339))
+	}
+
+	if err := s.repoModel.UpdateAccess(repo.ID, state, httpStatus, failures, retryAt); err != nil {
+		log.Printf("Failed to update access state of repository %s: %v", repo.Name, err)
+		return
+	}
+	repo.AccessState, repo.AccessStatus, repo.AccessFailures, repo.AccessRetryAt = state, httpStatus, failures, retryAt
+}
+
+// accessRetryDelay doubles the sync interval for every forbidden lookup in a row after the first,
+// up to maxAccessRetryDelay
+func accessRetryDelay(interval time.Duration, failures int) time.Duration {
+	delay := interval
+	for i := 1; i < failures && delay < maxAccessRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxAccessRetryDelay)
+}
+
+// setLastSyncError stores the repository's connectivity failure, writing a sync log entry only when
+// the reason changes so a revoked token doesn't add one every pass
+func (s *Service) setLastSyncError(repo *types.Repository, reason string) {
+	if reason == repo.LastSyncError {
+		return
+	}
+	if err := s.repoModel.SetLastSyncError(repo.ID, reason); err != nil {
+		log.Printf("Failed to set sync error for repository %s: %v", repo.Name, err)
+		return
+	}
+	if reason != "" {
+		s.logSync(repo.ID, types.SyncLogError, reason)
+	} else {
+		s.logSync(repo.ID, types.SyncLogInfo, fmt.Sprintf("GitHub is reachable again; cleared: %s", repo.LastSyncError))
+	}
+	repo.LastSyncError = reason
+}
+
+// updateMovedRepository points a repository that was renamed or transferred on GitHub at its new URL
+func (s *Service) updateMovedRepository(repo *types.Repository, fullName string) {
+	parsed, err := vcs.ParseRepositoryURL(repo.URL)
+	if err != nil {
+		return
+	}
+	oldURL := repo.URL
+	newURL := fmt.Sprintf("https://%s/%s", parsed.Host, fullName)
+
+	if err := s.repoModel.UpdateURL(repo.ID, newURL); err != nil {
+		// Most likely the new URL is already configured as another repository
+		log.Printf("Failed to update URL of moved repository %s: %v", repo.Name, err)
+		s.notify(repo.ID, "repository_moved", fmt.Sprintf("%s moved to %s", repo.Name, fullName),
+			fmt.Sprintf("GitHub redirects %s to %s, but the URL couldn't be updated: %v", oldURL, newURL, err))
+		return
+	}
+	repo.URL = newURL
+
+	log.Printf("Repository %s moved on GitHub, updated URL from %s to %s", repo.Name, oldURL, newURL)
+	s.logSync(repo.ID, types.SyncLogInfo, fmt.Sprintf("Repository moved on GitHub, URL updated from %s to %s", oldURL, newURL))
+	s.notify(repo.ID, "repository_moved", fmt.Sprintf("%s moved to %s", repo.Name, fullName),
+		fmt.Sprintf("The repository was renamed or transferred on GitHub. Its URL was updated from %s to %s.", oldURL, newURL))
+	s.audit(repo.ID, "repository_url_updated", fmt.Sprintf("%s: %s -> %s", repo.Name, oldURL, newURL))
+}
+
+// recordNotFound counts a 404 and marks the repository unreachable once it has persisted for
+// unreachableAfter passes, notifying the user once
+func (s *Service) recordNotFound(repo *types.Repository) {
+	count, err := s.repoModel.RecordNotFound(repo.ID)
+	if err != nil {
+		log.Printf("Failed to record 404 for repository %s: %v", repo.Name, err)
+		return
+	}
+	if count != unreachableAfter {
+		return
+	}
+
+	if err := s.repoModel.UpdateStatus(repo.ID, types.RepositoryUnreachable); err != nil {
+		log.Printf("Failed to mark repository %s unreachable: %v", repo.Name, err)
+		return
+	}
+	s.logSync(repo.ID, types.SyncLogError, fmt.Sprintf("GitHub returned 404 for %d syncs in a row; scheduled syncs are paused", count))
+	s.notify(repo.ID, "repository_unreachable", fmt.Sprintf("%s is unreachable", repo.Name),
+		fmt.Sprintf("GitHub returned 404 for %s on the last %d syncs. Fix its URL or archive it on the Repositories page.", repo.URL, count))
+}
+
+func (s *Service) audit(repositoryID int64, action, detail string) {
+	if s.auditModel == nil {
+		return
+	}
+	entry := &types.AuditEntry{Action: action, RepositoryID: &repositoryID, Detail: detail, Success: true}
+	if err := s.auditModel.Create(entry); err != nil {
+		log.Printf("Failed to write audit log entry for %s: %v", action, err)
+	}
+}
@@ -0,0 +1,129 @@
+package sync
+
+import (
+	"database/sql"
+	"net/http"
+	"strings"
+	"testing"
+
+	"dev-dashboard/internal/models"
+	"dev-dashboard/internal/testsupport"
+	"dev-dashboard/pkg/types"
+)
+
+// storedRepository reads the repository back from the database
+func storedRepository(t *testing.T, db *sql.DB, id int64) *types.Repository {
+	t.Helper()
+	repo, err := models.NewRepositoryModel(db).GetByID(id)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	return repo
+}
+
+func TestRevokedTokenStopsSyncBeforeScanning(t *testing.T) {
+	fake := newFakeGitHub()
+	service, db := newTestService(t, fake)
+	repo := testsupport.Repository(t, db)
+	fake.handle(repositoryPath(repo), respondWith(http.StatusUnauthorized))
+
+	for i := 0; i < 2; i++ {
+		err := service.SyncRepository(repo.ID)
+		if err == nil || !strings.Contains(err.Error(), "401") {
+			t.Fatalf("sync %d got error %v, want the rejected token", i+1, err)
+		}
+	}
+	if len(fake.requests) != 2 {
+		t.Errorf("made requests %v, want only the lookup of each sync", fake.requests)
+	}
+
+	stored := storedRepository(t, db, repo.ID)
+	if stored.AccessState != types.AccessTokenMissing || stored.AccessStatus != http.StatusUnauthorized {
+		t.Errorf("got access %s (%d), want %s (401)", stored.AccessState, stored.AccessStatus, types.AccessTokenMissing)
+	}
+	if !strings.Contains(stored.LastSyncError, "revoked or has expired") {
+		t.Errorf("got last sync error %q, want the token rejected", stored.LastSyncError)
+	}
+	if stored.LastSyncAt != nil {
+		t.Errorf("last synced at %v, want never", stored.LastSyncAt)
+	}
+
+	logs, err := models.NewSyncLogModel(db).GetByRepositoryID(repo.ID, 10)
+	if err != nil {
+		t.Fatalf("GetByRepositoryID: %v", err)
+	}
+	if len(logs) != 1 || logs[0].Level != types.SyncLogError {
+		t.Errorf("got sync logs %v, want one error however often the token is rejected", logs)
+	}
+}
+
+func TestRenamedRepositoryIsSyncedUnderItsNewName(t *testing.T) {
+	fake := newFakeGitHub()
+	service, db := newTestService(t, fake)
+	repo := testsupport.Repository(t, db)
+	// GitHub redirects the old name and answers with the new one
+	fake.handle(repositoryPath(repo), repositoryFound("acme-platform/renamed"))
+	fake.handle("/repos/acme-platform/renamed", repositoryFound("acme-platform/renamed"))
+
+	service.SyncRepository(repo.ID)
+	stored := storedRepository(t, db, repo.ID)
+	if stored.URL != "https://github.com/acme-platform/renamed" {
+		t.Errorf("got URL %s, want the new name's", stored.URL)
+	}
+	if stored.LastSyncError != "" || stored.AccessState != types.AccessOK {
+		t.Errorf("got access %s and last sync error %q, want the repository reachable", stored.AccessState, stored.LastSyncError)
+	}
+	if n := fake.requestCount("/repos/acme-platform/renamed/actions/workflows"); n != 1 {
+		t.Errorf("workflows listed %d times under the new name, want the sync to go on under it", n)
+	}
+	if n := fake.requestCount(repositoryPath(repo) + "/actions/workflows"); n != 0 {
+		t.Errorf("workflows listed %d times under the old name, want none", n)
+	}
+	if n := len(notificationsOfType(t, db, "repository_moved")); n != 1 {
+		t.Errorf("got %d repository_moved notifications, want 1", n)
+	}
+
+	// The next sync looks the repository up under its new name
+	service.SyncRepository(repo.ID)
+	if n := fake.requestCount(repositoryPath(repo)); n != 1 {
+		t.Errorf("the old name was looked up %d times, want only by the first sync", n)
+	}
+	if n := len(notificationsOfType(t, db, "repository_moved")); n != 1 {
+		t.Errorf("got %d repository_moved notifications after syncing again, want still 1", n)
+	}
+}
+
+func TestManualSyncOnlyRepositoryIsLeftOutOfPasses(t *testing.T) {
+	fake := newFakeGitHub()
+	service, db := newTestService(t, fake)
+	scheduled := testsupport.Repository(t, db)
+	manual := testsupport.Repository(t, db)
+	if err := models.NewRepositoryModel(db).SetManualSyncOnly(manual.ID, true); err != nil {
+		t.Fatalf("SetManualSyncOnly: %v", err)
+	}
+	for _, repo := range []*types.Repository{scheduled, manual} {
+		fake.handleRepository(repo)
+		fake.handle(repositoryPath(repo)+"/actions/workflows", respondJSON(map[string]interface{}{"total_count": 0, "workflows": []string{}}))
+	}
+
+	service.syncAll()
+	if n := fake.requestCount(repositoryPath(scheduled)); n != 1 {
+		t.Errorf("the pass looked up the scheduled repository %d times, want once", n)
+	}
+	if n := fake.requestCount(repositoryPath(manual)); n != 0 {
+		t.Errorf("the pass looked up the manual sync only repository %d times, want never", n)
+	}
+	if stored := storedRepository(t, db, manual.ID); stored.LastSyncAt != nil {
+		t.Errorf("the manual sync only repository was synced at %v by the pass", stored.LastSyncAt)
+	}
+
+	if err := service.SyncRepository(manual.ID); err != nil {
+		t.Fatalf("SyncRepository: %v", err)
+	}
+	if n := fake.requestCount(repositoryPath(manual)); n != 1 {
+		t.Errorf("the manual sync looked up the repository %d times, want once", n)
+	}
+	if stored := storedRepository(t, db, manual.ID); stored.LastSyncAt == nil {
+		t.Error("the manual sync didn't complete")
+	}
+}
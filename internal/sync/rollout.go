@@ -0,0 +1,181 @@
+package sync
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"dev-dashboard/pkg/types"
+)
+
+// DefaultRolloutStuckAfter is how long an incomplete rollout may go without a namespace moving to
+// its tag before it's reported as stuck, unless configured otherwise
+const DefaultRolloutStuckAfter = time.Hour
+
+// rolloutRateTransitions is how many of a rollout's most recent transitions estimate its pace
+const rolloutRateTransitions = 5
+
+// ComputeRolloutProgress reports how far the newest tag in an environment has rolled out across
+// the service's deployment targets (region and namespace). A target's transition time is when the
+// deployment history last saw it change to its current tag; the newest tag is the one whose first
+// transition is most recent.
+func ComputeRolloutProgress(serviceID int64, environment string, deployments []*types.Deployment, history []*types.DeploymentHistoryEntry, now time.Time, stuckAfter time.Duration) (*types.RolloutProgress, error) {
+	type target struct {
+		deployment *types.Deployment
+		arrivedAt  time.Time
+	}
+
+	targetKey := func(region, namespace string) string { return region + "/" + namespace }
+
+	var targets []*target
+	byKey := make(map[string]*target)
+	for _, deployment := range deployments {
+		if deployment.Environment != environment {
+			continue
+		}
+		t := &target{deployment: deployment}
+		targets = append(targets, t)
+		byKey[targetKey(deployment.Region, deployment.Namespace)] = t
+	}
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("service %d has no deployments in %s", serviceID, environment)
+	}
+
+	// History is oldest first, so the last matching entry is when the current tag arrived
+	for _, entry := range history {
+		if entry.Environment != environment {
+			continue
+		}
+		if t, ok := byKey[targetKey(entry.Region, entry.Namespace)]; ok && entry.Tag == t.deployment.Tag {
+			t.arrivedAt = entry.ObservedAt
+		}
+	}
+
+	// A tag's rollout started when the first target still on it switched to it
+	startedAt := make(map[string]time.Time)
+	for _, t := range targets {
+		if start, ok := startedAt[t.deployment.Tag]; !ok || t.arrivedAt.Before(start) {
+			startedAt[t.deployment.Tag] = t.arrivedAt
+		}
+	}
+	var newest *types.Deployment
+	for _, t := range targets {
+		if newest == nil || startedAt[t.deployment.Tag].After(startedAt[newest.Tag]) {
+			newest = t.deployment
+		}
+	}
+
+	progress := &types.RolloutProgress{
+		ServiceID:    serviceID,
+		Environment:  environment,
+		Tag:          newest.Tag,
+		CommitSHA:    newest.CommitSHA,
+		TotalTargets: len(targets),
+		Stragglers:   []types.RolloutStraggler{},
+	}
+
+	var transitions []time.Time
+	for _, t := range targets {
+		if t.deployment.Tag == newest.Tag {
+			progress.UpdatedTargets++
+			if !t.arrivedAt.IsZero() {
+				transitions = append(transitions, t.arrivedAt)
+			}
+			continue
+		}
+
+		straggler := types.RolloutStraggler{
+			Region:    t.deployment.Region,
+			Namespace: t.deployment.Namespace,
+			Tag:       t.deployment.Tag,
+		}
+		if !t.arrivedAt.IsZero() {
+			since := t.arrivedAt
+			straggler.Since = &since
+		}
+		progress.Stragglers = append(progress.Stragglers, straggler)
+	}
+	progress.Complete = len(progress.Stragglers) == 0
+
+	if len(transitions) == 0 {
+		return progress, nil
+	}
+	sort.Slice(transitions, func(i, j int) bool { return transitions[i].Before(transitions[j]) })
+	started, last := transitions[0], transitions[len(transitions)-1]
+	progress.StartedAt = &started
+	progress.LastTransitionAt = &last
+
+	if progress.Complete {
+		return progress, nil
+	}
+
+	// Extrapolate the remaining targets at the pace of the most recent transitions
+	recent := transitions[max(0, len(transitions)-rolloutRateTransitions):]
+	if len(recent) >= 2 {
+		perTarget := recent[len(recent)-1].Sub(recent[0]) / time.Duration(len(recent)-1)
+		estimate := last.Add(perTarget * time.Duration(len(progress.Stragglers)))
+		progress.EstimatedCompletionAt = &estimate
+	}
+
+	progress.Stuck = stuckAfter > 0 && now.Sub(last) >= stuckAfter
+	return progress, nil
+}
+
+// SetRolloutStuckAfter changes how long an incomplete rollout may stall before it's reported;
+// 0 turns the check off
+func (s *Service) SetRolloutStuckAfter(stuckAfter time.Duration) {
+	s.rolloutStuckAfter.Store(int64(stuckAfter))
+}
+
+// checkStuckRollouts notifies once about every rollout that stalled before reaching all of an
+// environment's namespaces. It runs after each sync cycle, on the history collected so far.
+func (s *Service) checkStuckRollouts() {
+	stuckAfter := time.Duration(s.rolloutStuckAfter.Load())
+	if stuckAfter <= 0 {
+		return
+	}
+
+	services, err := s.microserviceModel.GetAll()
+	if err != nil {
+		log.Printf("Failed to get services for rollout check: %v", err)
+		return
+	}
+
+	now := time.Now()
+	stuck := make(map[string]bool)
+	for _, service := range services {
+		deployments, err := s.deploymentModel.GetByServiceID(service.ID)
+		if err != nil || len(deployments) == 0 {
+			continue
+		}
+		history, err := s.deploymentModel.GetHistoryByServiceID(service.ID, time.Time{})
+		if err != nil {
+			log.Printf("Failed to get deployment history of %s: %v", service.Name, err)
+			continue
+		}
+
+		environments := make(map[string]bool)
+		for _, deployment := range deployments {
+			environments[deployment.Environment] = true
+		}
+		for environment := range environments {
+			progress, err := ComputeRolloutProgress(service.ID, environment, deployments, history, now, stuckAfter)
+			if err != nil || !progress.Stuck {
+				continue
+			}
+
+			key := fmt.Sprintf("%d/%s/%s", service.ID, environment, progress.Tag)
+			stuck[key] = true
+			if s.stuckRollouts[key] {
+				continue
+			}
+			s.notifyEnvironment(service.RepositoryID, environment, "rollout_stuck",
+				fmt.Sprintf("Rollout of %s to %s is stuck", service.Name, environment),
+				fmt.Sprintf("%d/%d namespaces are on %s; no namespace has moved since %s", progress.UpdatedTargets, progress.TotalTargets, progress.Tag, progress.LastTransitionAt.Format(time.RFC822)))
+		}
+	}
+
+	// Forget rollouts that completed or moved on, so a later stall is reported again
+	s.stuckRollouts = stuck
+}
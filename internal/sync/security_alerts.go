@@ -0,0 +1,120 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"dev-dashboard/internal/github"
+	"dev-dashboard/pkg/types"
+)
+
+const (
+	// securityAlertsRefreshInterval is how often a repository's security alerts are read again;
+	// alerts change far less often than the sync runs
+	securityAlertsRefreshInterval = time.Hour
+	// securityAlertsTopN is how many alerts are kept per repository and source
+	securityAlertsTopN = 5
+)
+
+// securityAlertSeverityRank orders severities from most to least severe
+var securityAlertSeverityRank = map[string]int{"critical": 0, "high": 1, "medium": 2, "low": 3}
+
+// syncSecurityAlerts records a repository's open Dependabot and code scanning alerts, at most once
+// per refresh interval. A source that isn't available (disabled, missing token scope, or an
+// Enterprise Server without the endpoint) is recorded as unavailable rather than failing the phase.
+func (s *Service) syncSecurityAlerts(repo *types.Repository, owner, repoName string, results *phaseResults) error {
+	if s.securityAlertModel == nil {
+		return nil
+	}
+	existing, err := s.securityAlertModel.GetByRepositoryID(repo.ID)
+	if err != nil {
+		return err
+	}
+	checkedAt := make(map[types.SecurityAlertSource]time.Time)
+	for _, report := range existing {
+		checkedAt[report.Source] = report.CheckedAt
+	}
+
+	sources := []struct {
+		source types.SecurityAlertSource
+		list   func(ctx context.Context, owner, repo string) ([]github.SecurityAlert, error)
+	}{
+		{types.SecurityAlertDependabot, s.githubClient.ListDependabotAlerts},
+		{types.SecurityAlertCodeScanning, s.githubClient.ListCodeScanningAlerts},
+	}
+	var errs []error
+	for _, source := range sources {
+		if time.Since(checkedAt[source.source]) < securityAlertsRefreshInterval {
+			continue
+		}
+
+		report := &types.SecurityAlertReport{RepositoryID: repo.ID, Source: source.source, Available: true, CheckedAt: time.Now()}
+		alerts, err := source.list(s.requestContext(repo.ID), owner, repoName)
+		if errors.Is(err, github.ErrSecurityAlertsUnavailable) {
+			log.Printf("Security alerts from %s not available for %s: %v", source.source, repo.Name, err)
+			report.Available = false
+			report.Detail = err.Error()
+		} else if err != nil {
+			errs = append(errs, fmt.Errorf("failed to get %s alerts of %s: %w", source.source, repo.Name, err))
+			continue
+		} else {
+			summarizeSecurityAlerts(report, alerts)
+		}
+
+		results.store(func(m *phaseModels) error {
+			if err := m.securityAlerts.Upsert(report); err != nil {
+				return err
+			}
+			s.changes.mark(types.EntitySecurity, repo.ID)
+			return nil
+		})
+	}
+	return errors.Join(errs...)
+}
+
+// summarizeSecurityAlerts counts alerts by severity and keeps the most severe, newest first
+func summarizeSecurityAlerts(report *types.SecurityAlertReport, alerts []github.SecurityAlert) {
+	sort.SliceStable(alerts, func(i, j int) bool {
+		rankI, rankJ := securityAlertRank(alerts[i].Severity), securityAlertRank(alerts[j].Severity)
+		if rankI != rankJ {
+			return rankI < rankJ
+		}
+		return alerts[i].CreatedAt.After(alerts[j].CreatedAt)
+	})
+
+	report.TopAlerts = []types.SecurityAlert{}
+	for _, alert := range alerts {
+		switch securityAlertRank(alert.Severity) {
+		case 0:
+			report.Critical++
+		case 1:
+			report.High++
+		case 2:
+			report.Medium++
+		default:
+			report.Low++
+		}
+		if len(report.TopAlerts) < securityAlertsTopN {
+			report.TopAlerts = append(report.TopAlerts, types.SecurityAlert{
+				Number:    alert.Number,
+				Severity:  alert.Severity,
+				Summary:   alert.Summary,
+				Location:  alert.Location,
+				URL:       alert.HTMLURL,
+				CreatedAt: alert.CreatedAt,
+			})
+		}
+	}
+}
+
+// securityAlertRank is the rank of a severity; unknown severities count as low
+func securityAlertRank(severity string) int {
+	if rank, ok := securityAlertSeverityRank[severity]; ok {
+		return rank
+	}
+	return securityAlertSeverityRank["low"]
+}
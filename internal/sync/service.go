@@ -2,16 +2,20 @@ package sync
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
-	"net/url"
 	"regexp"
 	"strings"
+	"sync/atomic"
 	"time"
 
+	"dev-dashboard/internal/database"
 	"dev-dashboard/internal/github"
 	"dev-dashboard/internal/kubernetes"
 	"dev-dashboard/internal/models"
+	"dev-dashboard/internal/packages"
+	"dev-dashboard/internal/vcs"
 	"dev-dashboard/pkg/types"
 	
 	goGithub "github.com/google/go-github/v57/github"
@@ -24,8 +28,36 @@ type Service struct {
 	kubernetesModel    *models.KubernetesResourceModel
 	actionModel        *models.ActionModel
 	deploymentModel    *models.DeploymentModel
+	statsModel         *models.StatsSnapshotModel
+	syncLogModel       *models.SyncLogModel
+	notifier           *Notifier
+	approvalModel      *models.PendingApprovalModel
+	usageModel         *models.ActionsUsageModel
+	auditModel         *models.AuditLogModel
+	discoveryChangeModel *models.DiscoveryChangeModel
+	envVarSnapshotModel *models.EnvVarSnapshotModel
+	securityAlertModel *models.SecurityAlertModel
+	servicePackageModel *models.ServicePackageModel
+	transactions       *database.Writer
+	phaseModels        *phaseModels
+	packageRegistry    *packages.Registry
+	packageVersionLookup atomic.Bool
+	collectUsage       atomic.Bool
+	onDataChanged      func(types.DataChangedEvent)
+	onSyncComplete     func()
+	changes            *changeSet
+	githubToken        string
 	kubernetesScanner  *kubernetes.Scanner
 	syncInterval       time.Duration
+	rolloutStuckAfter  atomic.Int64
+	tagPrefixes        atomic.Pointer[[]string]
+	discoveryReviewWindow atomic.Int64
+	discoveryReviewAutoApply atomic.Bool
+	correlationRetryWindow atomic.Int64
+	firstDeployNotifications atomic.Bool
+	stuckRollouts      map[string]bool // rollouts already reported as stuck, touched by checkStuckRollouts only
+	syncing            *syncingSet
+	watchdog           *watchdog
 	ctx                context.Context
 	cancelFunc         context.CancelFunc
 }
@@ -33,33 +65,149 @@ type Service struct {
 type Config struct {
 	GitHubToken       string
 	GitHubEnterpriseURL string
+	// GitHubClientOptions are applied to the GitHub client, e.g. an API base URL override
+	GitHubClientOptions []github.Option
 	SyncInterval      time.Duration
+	DescriptionSources []github.DescriptionSource
+	CollectActionsUsage bool
+	// DomainFolders makes built-in discovery look for services inside domain folders under the root
+	DomainFolders bool
+	// StuckPassMultiple is how many times its usual duration a sync pass may run before the watchdog
+	// cancels it; 0 disables the watchdog
+	StuckPassMultiple float64
+	// RolloutStuckAfter is how long an incomplete rollout may stall before a notification; 0 disables
+	RolloutStuckAfter time.Duration
+	// TagPrefixes are stripped from deployment tags before parsing them as semver
+	TagPrefixes []string
+	// FluxVersionFields are the fields the deployment scan reads versions from in Flux resources
+	FluxVersionFields github.FluxVersionFields
+	// CommitAnnotations are the kustomization annotation and label keys a deployment's source commit
+	// is read from, bypassing tag correlation
+	CommitAnnotations []string
+	// EnvVarSnapshots makes the deployment scan record the environment variables of each overlay's Deployment
+	EnvVarSnapshots bool
+	// DiscoveryReviewWindow is how long discovery changes of repositories in review mode wait for
+	// review; 0 keeps them pending until reviewed
+	DiscoveryReviewWindow time.Duration
+	// DiscoveryReviewAutoApply applies changes left unreviewed past the window instead of expiring them
+	DiscoveryReviewAutoApply bool
+	// CorrelationRetryWindow is how long deployments whose tag matched no monorepo commit are
+	// correlated again on later syncs; 0 doesn't retry
+	CorrelationRetryWindow time.Duration
+	// FirstDeployNotifications raises a notification when a service is first deployed to an
+	// environment and region
+	FirstDeployNotifications bool
+	// PackageVersionLookup looks up the latest versions of the packages of repositories that
+	// collect packages in the Go module proxy and the npm registry
+	PackageVersionLookup bool
+	// Transactions stores what each sync phase found in a transaction that commits together with
+	// the phase's checkpoint, through models the service builds on its connection. Without it phases
+	// store their results through the service's models as they go.
+	Transactions *database.Writer
+	// OnSyncComplete is called at the end of every sync pass over all repositories
+	OnSyncComplete func()
+	// OnDataChanged is called after a sync cycle that changed data, e.g. to notify the frontend
+	OnDataChanged func(types.DataChangedEvent)
 }
 
-func NewService(config Config, repoModel *models.RepositoryModel, microserviceModel *models.MicroserviceModel, kubernetesModel *models.KubernetesResourceModel, actionModel *models.ActionModel, deploymentModel *models.DeploymentModel) *Service {
+func NewService(config Config, repoModel *models.RepositoryModel, microserviceModel *models.MicroserviceModel, kubernetesModel *models.KubernetesResourceModel, actionModel *models.ActionModel, deploymentModel *models.DeploymentModel, statsModel *models.StatsSnapshotModel, syncLogModel *models.SyncLogModel, notifier *Notifier, approvalModel *models.PendingApprovalModel, usageModel *models.ActionsUsageModel, auditModel *models.AuditLogModel, discoveryChangeModel *models.DiscoveryChangeModel, envVarSnapshotModel *models.EnvVarSnapshotModel, securityAlertModel *models.SecurityAlertModel, servicePackageModel *models.ServicePackageModel) *Service {
 	ctx, cancel := context.WithCancel(context.Background())
 	
-	return &Service{
-		githubClient:       github.NewClientWithBaseURL(config.GitHubToken, config.GitHubEnterpriseURL),
+	githubClient := github.NewClientWithBaseURL(config.GitHubToken, config.GitHubEnterpriseURL, config.GitHubClientOptions...)
+	githubClient.SetDescriptionSources(config.DescriptionSources)
+	githubClient.SetDomainFolders(config.DomainFolders)
+	githubClient.SetFluxVersionFields(config.FluxVersionFields)
+	githubClient.SetCommitAnnotations(config.CommitAnnotations)
+	githubClient.SetEnvVarSnapshots(config.EnvVarSnapshots)
+	
+	service := &Service{
+		githubClient:       githubClient,
 		repoModel:         repoModel,
 		microserviceModel: microserviceModel,
 		kubernetesModel:   kubernetesModel,
 		actionModel:       actionModel,
 		deploymentModel:   deploymentModel,
+		statsModel:        statsModel,
+		syncLogModel:      syncLogModel,
+		notifier:          notifier,
+		approvalModel:     approvalModel,
+		usageModel:        usageModel,
+		auditModel:        auditModel,
+		discoveryChangeModel: discoveryChangeModel,
+		envVarSnapshotModel: envVarSnapshotModel,
+		securityAlertModel: securityAlertModel,
+		servicePackageModel: servicePackageModel,
+		transactions:      config.Transactions,
+		packageRegistry:   packages.NewRegistry(github.NewRateLimiter(packageRegistryRequestsPerSecond)),
+		onDataChanged:     config.OnDataChanged,
+		onSyncComplete:    config.OnSyncComplete,
+		changes:           newChangeSet(),
+		githubToken:       config.GitHubToken,
 		kubernetesScanner: kubernetes.NewScanner(),
 		syncInterval:      config.SyncInterval,
+		stuckRollouts:     make(map[string]bool),
+		syncing:           newSyncingSet(),
+		watchdog:          newWatchdog(),
 		ctx:               ctx,
 		cancelFunc:        cancel,
 	}
+	service.phaseModels = service.ownPhaseModels()
+	if config.Transactions != nil {
+		service.phaseModels = newPhaseModels(config.Transactions.Conn())
+	}
+	service.SetRolloutStuckAfter(config.RolloutStuckAfter)
+	service.SetStuckPassMultiple(config.StuckPassMultiple)
+	service.SetTagPrefixes(config.TagPrefixes)
+	service.SetDiscoveryReviewWindow(config.DiscoveryReviewWindow, config.DiscoveryReviewAutoApply)
+	service.SetCorrelationRetryWindow(config.CorrelationRetryWindow)
+	service.SetFirstDeployNotifications(config.FirstDeployNotifications)
+	service.SetPackageVersionLookup(config.PackageVersionLookup)
+	service.SetCollectActionsUsage(config.CollectActionsUsage)
+	return service
+}
+
+// SetTagPrefixes changes the prefixes stripped from deployment tags before parsing them as semver
+func (s *Service) SetTagPrefixes(prefixes []string) {
+	s.tagPrefixes.Store(&prefixes)
+}
+
+// SetDescriptionSources changes the files discovery describes services from, in the order tried
+func (s *Service) SetDescriptionSources(sources []github.DescriptionSource) {
+	s.githubClient.SetDescriptionSources(sources)
+}
+
+// SetDomainFolders makes built-in discovery look for services inside domain folders under the root,
+// or directly under it
+func (s *Service) SetDomainFolders(enabled bool) {
+	s.githubClient.SetDomainFolders(enabled)
+}
+
+// SetFluxVersionFields changes the fields the deployment scan reads versions from in Flux resources
+func (s *Service) SetFluxVersionFields(fields github.FluxVersionFields) {
+	s.githubClient.SetFluxVersionFields(fields)
+}
+
+// SetCommitAnnotations changes the kustomization keys the deployment scan reads source commits from
+func (s *Service) SetCommitAnnotations(keys []string) {
+	s.githubClient.SetCommitAnnotations(keys)
+}
+
+// SetEnvVarSnapshots turns recording the environment variables of each overlay's Deployment on or off
+func (s *Service) SetEnvVarSnapshots(enabled bool) {
+	s.githubClient.SetEnvVarSnapshots(enabled)
 }
 
 func (s *Service) Start() {
+	go s.watchPasses()
 	go func() {
 		ticker := time.NewTicker(s.syncInterval)
 		defer ticker.Stop()
 
 		// Initial sync
 		s.syncAll()
+		s.snapshotStats()
+		s.checkStuckRollouts()
+		s.syncComplete()
 
 		for {
 			select {
@@ -67,31 +215,100 @@ func (s *Service) Start() {
 				return
 			case <-ticker.C:
 				s.syncAll()
+				s.snapshotStats()
+				s.checkStuckRollouts()
+				s.syncComplete()
 			}
 		}
 	}()
 }
 
+func (s *Service) syncComplete() {
+	if s.onSyncComplete != nil {
+		s.onSyncComplete()
+	}
+}
+
 func (s *Service) Stop() {
 	s.cancelFunc()
 }
 
+// SyncRepository syncs a repository on demand. It returns an error wrapping ErrSyncInProgress,
+// without waiting, when the repository is already syncing.
 func (s *Service) SyncRepository(repositoryID int64) error {
+	release, err := s.claim(s.ctx, repositoryID)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return s.syncClaimed(repositoryID)
+}
+
+// syncClaimed syncs a repository the caller claimed, as a cycle of its own
+func (s *Service) syncClaimed(repositoryID int64) error {
+	defer s.emitChanges()
+	defer s.beginRequestCache()()
+	return s.syncRepository(repositoryID)
+}
+
+// beginRequestCache starts a cycle of the GitHub client's request cache, so the cycle never sees
+// responses fetched before it. The returned function logs how many requests the cache served and
+// empties it again.
+func (s *Service) beginRequestCache() func() {
+	if s.githubClient == nil {
+		return func() {}
+	}
+	s.githubClient.ResetRequestCache()
+	return func() {
+		hits, misses := s.githubClient.ResetRequestCache()
+		if hits+misses > 0 {
+			log.Printf("GitHub request cache served %d of %d contents and commit list requests", hits, hits+misses)
+		}
+	}
+}
+
+func (s *Service) syncRepository(repositoryID int64) error {
 	repo, err := s.repoModel.GetByID(repositoryID)
 	if err != nil {
 		return fmt.Errorf("failed to get repository: %w", err)
 	}
 
-	owner, repoName, err := parseGitHubURL(repo.URL)
+	if repo.Status == types.RepositoryArchived {
+		return fmt.Errorf("repository %s is archived", repo.Name)
+	}
+
+	owner, repoName, err := vcs.ParseGitHubURL(repo.URL)
 	if err != nil {
 		return fmt.Errorf("invalid repository URL: %w", err)
 	}
 
+	s.heartbeat(repo, "lookup")
+	owner, repoName, err = s.resolveRepository(repo, owner, repoName)
+	if err != nil {
+		return err
+	}
+
+	runs := syncPhase{types.SyncPhaseRuns, false, func(results *phaseResults) error {
+		return s.syncWorkflowRuns(repo, owner, repoName, results)
+	}}
+	security := syncPhase{types.SyncPhaseSecurity, false, func(results *phaseResults) error {
+		return s.syncSecurityAlerts(repo, owner, repoName, results)
+	}}
 	switch repo.Type {
 	case types.MonorepoType:
-		return s.syncMonorepo(repo, owner, repoName)
+		return s.runPhases(repo, []syncPhase{
+			{types.SyncPhaseServices, true, func(results *phaseResults) error { return s.syncServices(repo, owner, repoName, results) }},
+			runs,
+			security,
+			{types.SyncPhasePackages, false, func(results *phaseResults) error { return s.syncPackages(repo, owner, repoName, results) }},
+		})
 	case types.KubernetesType:
-		return s.syncKubernetesRepo(repo, owner, repoName)
+		return s.runPhases(repo, []syncPhase{
+			{types.SyncPhaseDeployments, false, func(results *phaseResults) error { return s.syncDeployments(repo, owner, repoName, results) }},
+			{types.SyncPhaseResources, true, func(results *phaseResults) error { return s.syncResources(repo, owner, repoName, results) }},
+			runs,
+			security,
+		})
 	default:
 		return fmt.Errorf("unknown repository type: %s", repo.Type)
 	}
@@ -104,31 +321,96 @@ func (s *Service) syncAll() {
 		return
 	}
 
+	defer s.emitChanges()
+	defer s.beginRequestCache()()
+	pass := s.beginPass()
+	defer s.endPass(pass)
+
 	for _, repo := range repositories {
-		if err := s.SyncRepository(repo.ID); err != nil {
-			log.Printf("Failed to sync repository %s: %v", repo.Name, err)
+		// The watchdog cancelled the pass; the next one starts on schedule
+		if pass.ctx.Err() != nil {
+			log.Printf("Sync pass cancelled, skipping the remaining repositories")
+			break
+		}
+		// Unreachable repositories are retried by manual syncs only
+		if repo.Status == types.RepositoryUnreachable || repo.Status == types.RepositoryArchived {
+			continue
+		}
+		// So are forbidden repositories backing off
+		if repo.AccessRetryAt != nil && time.Now().Before(*repo.AccessRetryAt) {
+			continue
+		}
+		// And repositories the user only syncs on demand
+		if repo.ManualSyncOnly {
+			continue
+		}
+		// And repositories a manual sync is syncing right now
+		release, err := s.claim(pass.ctx, repo.ID)
+		if err != nil {
+			log.Printf("Skipping %s, it's already syncing", repo.Name)
 			continue
 		}
 
-		if err := s.repoModel.UpdateLastSync(repo.ID); err != nil {
-			log.Printf("Failed to update last sync time for repository %s: %v", repo.Name, err)
+		err = s.syncRepository(repo.ID)
+		release()
+		if err != nil {
+			log.Printf("Failed to sync repository %s: %v", repo.Name, err)
 		}
 	}
 }
 
-func (s *Service) syncMonorepo(repo *types.Repository, owner, repoName string) error {
+// snapshotStats records today's workspace stats if they haven't been captured yet.
+// Days the app wasn't running simply have no snapshot.
+func (s *Service) snapshotStats() {
+	if s.statsModel == nil {
+		return
+	}
+
+	today := time.Now()
+	exists, err := s.statsModel.HasSnapshot(today)
+	if err != nil {
+		log.Printf("Failed to check stats snapshot: %v", err)
+		return
+	}
+	if exists {
+		return
+	}
+
+	if err := s.statsModel.Capture(today); err != nil {
+		log.Printf("Failed to capture stats snapshot: %v", err)
+	}
+}
+
+// syncServices discovers a monorepo's services and stores them
+func (s *Service) syncServices(repo *types.Repository, owner, repoName string, results *phaseResults) error {
 	var services []github.ServiceInfo
 	var err error
+	usedScript := false
 
-	// Use GitHub API client for service discovery
-	if s.githubClient != nil {
-		services, err = s.githubClient.DiscoverMicroservices(s.ctx, owner, repoName)
-	} else {
-		return fmt.Errorf("no GitHub client available")
+	// Prefer the repository's discovery script when one is configured
+	if repo.DiscoveryScript != "" {
+		services, err = s.discoverWithScript(repo)
+		if err != nil {
+			log.Printf("Discovery script failed for %s, falling back to built-in discovery: %v", repo.Name, err)
+			s.logSync(repo.ID, types.SyncLogError, fmt.Sprintf("Discovery script failed: %v", err))
+			s.notify(repo.ID, "discovery_script_failed", fmt.Sprintf("Discovery script failed for %s", repo.Name),
+				fmt.Sprintf("%v. Built-in discovery was used instead.", err))
+		} else {
+			usedScript = true
+		}
 	}
 
-	if err != nil {
-		return fmt.Errorf("failed to discover microservices: %w", err)
+	// Use GitHub API client for service discovery
+	if !usedScript {
+		if s.githubClient != nil {
+			services, err = s.githubClient.DiscoverMicroservices(s.requestContext(repo.ID), owner, repoName)
+		} else {
+			return fmt.Errorf("no GitHub client available")
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to discover microservices: %w", err)
+		}
 	}
 
 	// If no services discovered but we have specific service info, create one
@@ -148,96 +430,277 @@ func (s *Service) syncMonorepo(repo *types.Repository, owner, repoName string) e
 			Name:         service.Name,
 			Path:         service.Path,
 			Description:  service.Description,
+			Domain:       service.Domain,
+			HasReadme:    service.HasReadme,
 		})
 	}
 
-	// Upsert microservices preserving existing IDs
-	if err := s.microserviceModel.UpsertServicesPreserveID(repo.ID, microservices); err != nil {
-		return fmt.Errorf("failed to upsert microservices: %w", err)
+	results.store(func(m *phaseModels) error {
+		// In review mode adds, removals and renames wait for approval instead of being applied
+		if repo.DiscoveryReview && s.discoveryChangeModel != nil {
+			return s.reviewDiscoveredServices(repo, microservices, m, results)
+		}
+		if s.discoveryChangeModel != nil {
+			if err := m.discoveryChanges.DeleteByRepository(repo.ID); err != nil {
+				log.Printf("Failed to clear discovery changes of %s: %v", repo.Name, err)
+			}
+		}
+
+		// Upsert microservices preserving existing IDs
+		changed, err := m.services.UpsertServicesPreserveID(repo.ID, microservices)
+		if err != nil {
+			return fmt.Errorf("failed to upsert microservices: %w", err)
+		}
+		if changed {
+			s.changes.mark(types.EntityServices, repo.ID)
+		}
+		return nil
+	})
+
+	return nil
+}
+
+// kustomizationTreeUnchanged fetches the tree SHA of the kustomization scan root and reports whether
+// it matches the SHA recorded at the last full scan. Any lookup failure is treated as changed.
+func (s *Service) kustomizationTreeUnchanged(repo *types.Repository, owner, repoName string) (string, bool) {
+	if s.githubClient == nil {
+		return "", false
+	}
+
+	treeSHA, err := s.githubClient.GetTreeSHA(s.requestContext(repo.ID), owner, repoName, github.KustomizationScanPath(repo.ServiceLocation))
+	if err != nil {
+		log.Printf("Failed to get scan tree SHA for %s, doing a full scan: %v", repo.Name, err)
+		return "", false
+	}
+
+	lastSHA, err := s.repoModel.GetScanTreeSHA(repo.ID)
+	if err != nil {
+		log.Printf("Failed to get last scan tree SHA for %s: %v", repo.Name, err)
+		return treeSHA, false
+	}
+
+	return treeSHA, lastSHA != "" && lastSHA == treeSHA
+}
+
+// ResyncRepository forgets the cached scan tree SHA and any checkpoint of an interrupted pass so
+// the sync runs every phase and does a full deployment scan. Like SyncRepository it returns
+// ErrSyncInProgress when the repository is already syncing, leaving that sync's checkpoint alone.
+func (s *Service) ResyncRepository(repositoryID int64) error {
+	release, err := s.claim(s.ctx, repositoryID)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	if err := s.repoModel.UpdateScanTreeSHA(repositoryID, ""); err != nil {
+		return err
+	}
+	if err := s.repoModel.SetSyncState(repositoryID, nil); err != nil {
+		return err
+	}
+	return s.syncClaimed(repositoryID)
+}
+
+// discoverWithScript runs the repository's discovery script, recording its stderr in the sync logs
+func (s *Service) discoverWithScript(repo *types.Repository) ([]github.ServiceInfo, error) {
+	services, stderr, err := runDiscoveryScript(s.requestContext(repo.ID), repo.DiscoveryScript, repo.URL, s.githubToken, repo.ServiceLocation)
+	if stderr = strings.TrimSpace(stderr); stderr != "" {
+		s.logSync(repo.ID, types.SyncLogInfo, fmt.Sprintf("Discovery script stderr:\n%s", stderr))
 	}
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Discovery script found %d services for %s", len(services), repo.Name)
+	return services, nil
+}
 
-	// Sync workflow runs for build and deployment actions
-	if err := s.syncWorkflowRuns(repo, owner, repoName); err != nil {
-		log.Printf("Failed to sync workflow runs for %s: %v", repo.Name, err)
+func (s *Service) logSync(repositoryID int64, level types.SyncLogLevel, message string) {
+	if s.syncLogModel == nil {
+		return
+	}
+	entry := &types.SyncLog{RepositoryID: &repositoryID, Level: level, Message: message}
+	if err := s.syncLogModel.Create(entry); err != nil {
+		log.Printf("Failed to write sync log: %v", err)
 	}
+}
+
+func (s *Service) notify(repositoryID int64, notificationType, title, message string) {
+	s.notifyEnvironment(repositoryID, "", notificationType, title, message)
+}
+
+// notifyEnvironment raises a notification about one environment, subject to its quiet hours
+func (s *Service) notifyEnvironment(repositoryID int64, environment, notificationType, title, message string) {
+	s.notifier.Notify(&types.Notification{RepositoryID: &repositoryID, Type: notificationType, Title: title, Message: message, Environment: environment})
+}
 
+// MatchDeploymentService finds the service a kustomization's service directory belongs to.
+// Names match when either contains the other, ignoring case.
+func MatchDeploymentService(services []*types.Microservice, serviceName string) *types.Microservice {
+	for _, service := range services {
+		if strings.Contains(strings.ToLower(service.Name), strings.ToLower(serviceName)) ||
+			strings.Contains(strings.ToLower(serviceName), strings.ToLower(service.Name)) {
+			return service
+		}
+	}
 	return nil
 }
 
-func (s *Service) syncKubernetesRepo(repo *types.Repository, owner, repoName string) error {
+// syncDeployments scans a kubernetes repository's kustomization files for deployments and stores them.
+// It fails when the scan fails or any deployment couldn't be stored.
+func (s *Service) syncDeployments(repo *types.Repository, owner, repoName string, results *phaseResults) error {
+	// Skip the kustomization walk entirely when the scan root's tree is unchanged since the last full scan
+	treeSHA, unchanged := s.kustomizationTreeUnchanged(repo, owner, repoName)
+
 	// Scan for real deployment data using GitHub API
-	if s.githubClient != nil {
+	if s.githubClient != nil && unchanged {
+		log.Printf("Kustomization tree for %s unchanged (%s), skipping deployment scan", repo.Name, treeSHA)
+		s.retryCorrelations(repo, results)
+	} else if s.githubClient != nil {
 		log.Printf("Scanning kustomization files for Kubernetes repo: %s", repo.Name)
 		
+		// Get all microservices to match images and deployments with
+		allServices, err := s.microserviceModel.GetAll()
+		if err != nil {
+			return fmt.Errorf("failed to get services for deployment matching: %w", err)
+		}
+		s.githubClient.SetImageNames(kubernetes.NewImageNames(allServices))
+
 		// Use GitHub API to scan for kustomization.yaml files with root path
 		rootPath := repo.ServiceLocation // Use service_location as root path for Kubernetes repos
-		kustomizationDeployments, err := s.githubClient.ScanKustomizationFilesInPath(s.ctx, owner, repoName, rootPath)
+		scanResults, err := s.githubClient.ScanKustomizationFilesVerbose(s.requestContext(repo.ID), owner, repoName, rootPath)
 		if err != nil {
-			log.Printf("Failed to scan kustomization files in %s: %v", repo.Name, err)
+			return fmt.Errorf("failed to scan kustomization files: %w", err)
 		} else {
+			kustomizationDeployments := github.KustomizationDeployments(scanResults)
 			log.Printf("Found %d kustomization deployments in %s", len(kustomizationDeployments), repo.Name)
-			
-			// Get all microservices to match with deployments
-			allServices, err := s.microserviceModel.GetAll()
+			s.reportUnmatchedImages(repo, scanResults, allServices, results)
+
+			// The first scan of a repository records everything already deployed in it, which
+			// isn't services going live or deploying during a freeze
+			hasHistory, err := s.deploymentModel.HasHistory(repo.ID)
 			if err != nil {
-				log.Printf("Failed to get services for deployment matching: %v", err)
-			} else {
-				// Convert GitHub API results to deployment records
-				for _, kustomDeploy := range kustomizationDeployments {
-					// Find matching service by name
-					var serviceID int64
-					for _, service := range allServices {
-						if strings.Contains(strings.ToLower(service.Name), strings.ToLower(kustomDeploy.ServiceName)) ||
-						   strings.Contains(strings.ToLower(kustomDeploy.ServiceName), strings.ToLower(service.Name)) {
-							serviceID = service.ID
-							break
-						}
-					}
-					
-					if serviceID == 0 {
-						log.Printf("No matching service found for %s, skipping", kustomDeploy.ServiceName)
-						continue
-					}
-					
-					// Try to correlate tag with actual monorepo commit
-					var commitSHA string
-					// Check if tag is already a commit SHA (40 hex characters)
-					if len(kustomDeploy.Tag) == 40 && isHexString(kustomDeploy.Tag) {
-						// Tag is likely a commit SHA, use it directly
-						commitSHA = kustomDeploy.Tag
-						log.Printf("Using tag as commit SHA for service %s: %s", kustomDeploy.ServiceName, kustomDeploy.Tag)
-					} else {
-						// Try to correlate tag with actual monorepo commit
-						commitSHA = s.correlateTagWithCommit(serviceID, kustomDeploy.Tag)
-						if commitSHA == "" {
-							commitSHA = kustomDeploy.CommitSHA // Fallback to k8s repo commit
-						}
-					}
+				log.Printf("Failed to check deployment history of %s: %v", repo.Name, err)
+			}
+			seeding := err != nil || !hasHistory
 
-					deployment := &types.Deployment{
-						ServiceID:        serviceID,
-						KubernetesRepoID: repo.ID,
-						CommitSHA:        commitSHA,
-						Environment:      kustomDeploy.Environment,
-						Region:           kustomDeploy.Region,
-						Namespace:        kustomDeploy.Namespace,
-						Tag:              kustomDeploy.Tag,
-						Path:             kustomDeploy.Path,
-					}
-					
-					if err := s.deploymentModel.Upsert(deployment); err != nil {
-						log.Printf("Failed to upsert deployment: %v", err)
-					} else {
-						log.Printf("Upserted deployment for service %s (%d) in %s/%s with tag %s", 
-							kustomDeploy.ServiceName, serviceID, kustomDeploy.Environment, kustomDeploy.Region, kustomDeploy.Tag)
+			// Convert GitHub API results to deployment records
+			var scanned []scannedDeployment
+			for _, kustomDeploy := range kustomizationDeployments {
+				// Find matching service by name
+				var serviceID int64
+				if service := MatchDeploymentService(allServices, kustomDeploy.ServiceName); service != nil {
+					serviceID = service.ID
+				}
+				
+				if serviceID == 0 {
+					log.Printf("No matching service found for %s, skipping", kustomDeploy.ServiceName)
+					continue
+				}
+				
+				// Try to correlate tag with actual monorepo commit
+				var commitSHA string
+				correlation := types.CorrelationCorrelated
+				var uncorrelatedSince *time.Time
+				// A commit the overlay names in an annotation or label is taken as is
+				if kustomDeploy.AnnotatedCommit != "" {
+					commitSHA = kustomDeploy.AnnotatedCommit
+					correlation = types.CorrelationAnnotated
+					log.Printf("Using annotated commit for service %s: %s", kustomDeploy.ServiceName, commitSHA)
+				} else if len(kustomDeploy.Tag) == 40 && isHexString(kustomDeploy.Tag) {
+					// Tag is likely a commit SHA, use it directly
+					commitSHA = kustomDeploy.Tag
+					log.Printf("Using tag as commit SHA for service %s: %s", kustomDeploy.ServiceName, kustomDeploy.Tag)
+				} else {
+					// Try to correlate tag with actual monorepo commit
+					commitSHA = s.correlateTagWithCommit(repo.ID, serviceID, kustomDeploy.Tag)
+					if commitSHA == "" {
+						// Fallback to k8s repo commit; later syncs retry until the retry window passes
+						commitSHA = kustomDeploy.CommitSHA
+						correlation = types.CorrelationUncorrelated
+						now := time.Now()
+						uncorrelatedSince = &now
 					}
 				}
+
+				scanned = append(scanned, scannedDeployment{kustomDeploy, &types.Deployment{
+					ServiceID:        serviceID,
+					KubernetesRepoID: repo.ID,
+					CommitSHA:        commitSHA,
+					Environment:      kustomDeploy.Environment,
+					Region:           kustomDeploy.Region,
+					Namespace:        kustomDeploy.Namespace,
+					Tag:              kustomDeploy.Tag,
+					ImageRepository:  kustomDeploy.ImageRepository,
+					Registry:         kustomDeploy.Registry,
+					Path:             kustomDeploy.Path,
+					Version:          vcs.ParseTagVersion(kustomDeploy.Tag, *s.tagPrefixes.Load()),
+					CorrelationStatus: correlation,
+					UncorrelatedSince: uncorrelatedSince,
+					Seeding:          seeding,
+				}})
 			}
+
+			results.store(func(m *phaseModels) error {
+				return s.storeDeployments(repo, scanned, treeSHA, m, results)
+			})
 		}
 	} else {
-		log.Printf("No GitHub client available for scanning %s", repo.Name)
+		return fmt.Errorf("no GitHub client available")
+	}
+
+	return nil
+}
+
+// scannedDeployment is a deployment the scan found, with the kustomization it was found in
+type scannedDeployment struct {
+	kustomization github.KustomizationDeployment
+	deployment    *types.Deployment
+}
+
+// storeDeployments stores the deployments a scan of the kustomization tree at treeSHA found, and
+// announces freeze violations and first deploys among them. The tree is only remembered once every
+// deployment in it was stored.
+func (s *Service) storeDeployments(repo *types.Repository, scanned []scannedDeployment, treeSHA string, m *phaseModels, results *phaseResults) error {
+	scanComplete := true
+	for _, found := range scanned {
+		kustomDeploy, deployment := found.kustomization, found.deployment
+		if changed, err := m.deployments.Upsert(deployment); err != nil {
+			log.Printf("Failed to upsert deployment: %v", err)
+			scanComplete = false
+		} else {
+			if changed {
+				s.changes.mark(types.EntityDeployments, repo.ID)
+				if !deployment.Seeding {
+					results.announce(func() {
+						s.notifyFreezeViolation(repo, kustomDeploy.ServiceName, deployment)
+						s.notifyFirstDeploy(repo, kustomDeploy.ServiceName, deployment)
+					})
+				}
+			}
+			log.Printf("Upserted deployment for service %s (%d) in %s/%s with tag %s", 
+				kustomDeploy.ServiceName, deployment.ServiceID, kustomDeploy.Environment, kustomDeploy.Region, kustomDeploy.Tag)
+		}
+
+		if err := s.storeEnvVarSnapshot(deployment.ServiceID, kustomDeploy, m); err != nil {
+			log.Printf("Failed to store environment variables of %s: %v", kustomDeploy.ServiceName, err)
+			scanComplete = false
+		}
+	}
+
+	if !scanComplete {
+		return fmt.Errorf("failed to store some deployments")
+	}
+	if treeSHA != "" {
+		if err := m.repositories.UpdateScanTreeSHA(repo.ID, treeSHA); err != nil {
+			log.Printf("Failed to record scan tree SHA for %s: %v", repo.Name, err)
+		}
 	}
+	return nil
+}
 
+// syncResources discovers the resources of a kubernetes repository and stores them
+func (s *Service) syncResources(repo *types.Repository, owner, repoName string, results *phaseResults) error {
 	// Discover Kubernetes resources
 	rootPath := repo.ServiceLocation // Use service_location as root path for Kubernetes repos too
 	if rootPath == "" {
@@ -246,7 +709,7 @@ func (s *Service) syncKubernetesRepo(repo *types.Repository, owner, repoName str
 		log.Printf("Using root path '%s' for Kubernetes repository %s", rootPath, repo.Name)
 	}
 	
-	resources, err := s.githubClient.DiscoverKubernetesResourcesInPath(s.ctx, owner, repoName, rootPath)
+	resources, err := s.githubClient.DiscoverKubernetesResourcesInPath(s.requestContext(repo.ID), owner, repoName, rootPath)
 	if err != nil {
 		return fmt.Errorf("failed to discover kubernetes resources: %w", err)
 	}
@@ -264,36 +727,51 @@ func (s *Service) syncKubernetesRepo(repo *types.Repository, owner, repoName str
 	}
 
 	// Upsert Kubernetes resources
-	if err := s.kubernetesModel.UpsertResources(repo.ID, kubernetesResources); err != nil {
-		return fmt.Errorf("failed to upsert kubernetes resources: %w", err)
-	}
-
-	// Sync workflow runs for deployment actions
-	if err := s.syncWorkflowRuns(repo, owner, repoName); err != nil {
-		log.Printf("Failed to sync workflow runs for %s: %v", repo.Name, err)
-	}
+	results.store(func(m *phaseModels) error {
+		if err := m.resources.UpsertResources(repo.ID, kubernetesResources); err != nil {
+			return fmt.Errorf("failed to upsert kubernetes resources: %w", err)
+		}
+		s.changes.mark(types.EntityResources, repo.ID)
+		return nil
+	})
 
 	return nil
 }
 
-func (s *Service) syncWorkflowRuns(repo *types.Repository, owner, repoName string) error {
+func (s *Service) syncWorkflowRuns(repo *types.Repository, owner, repoName string, results *phaseResults) error {
 	// Get all workflows
-	workflows, err := s.githubClient.ListWorkflows(s.ctx, owner, repoName)
+	workflows, err := s.githubClient.ListWorkflows(s.requestContext(repo.ID), owner, repoName)
 	if err != nil {
 		return fmt.Errorf("failed to list workflows: %w", err)
 	}
 
+	filter, err := ParseBranchFilter(repo.WorkflowBranches)
+	if err != nil {
+		log.Printf("Ignoring the workflow branch filter of %s: %v", repo.Name, err)
+		filter, _ = ParseBranchFilter("")
+	}
+
 	var actions []types.Action
+	var waitingRuns []github.WorkflowRun
+	var completedRuns []completedWorkflowRun
 	
 	for _, workflow := range workflows {
-		// Get recent workflow runs
-		runs, err := s.githubClient.GetWorkflowRuns(s.ctx, owner, repoName, workflow.GetID(), 50)
+		// Get recent workflow runs on the branches the repository's filter lets through
+		runs, err := s.getFilteredWorkflowRuns(repo, owner, repoName, workflow.GetID(), filter)
 		if err != nil {
 			log.Printf("Failed to get workflow runs for %s: %v", workflow.GetName(), err)
 			continue
 		}
 
 		for _, run := range runs {
+			// Runs waiting on environment protection rules can belong to any workflow
+			if run.Status == "waiting" {
+				waitingRuns = append(waitingRuns, run)
+			}
+			if run.Status == "completed" {
+				completedRuns = append(completedRuns, completedWorkflowRun{workflow: workflow, run: run})
+			}
+
 			actionType := s.determineActionType(workflow.GetName())
 			if actionType == "" {
 				continue // Skip non-build/deploy workflows
@@ -303,6 +781,7 @@ func (s *Service) syncWorkflowRuns(repo *types.Repository, owner, repoName strin
 				RepositoryID:  repo.ID,
 				Type:          types.ActionType(actionType),
 				Status:        run.Status,
+				Conclusion:    run.Conclusion,
 				WorkflowRunID: run.ID,
 				Commit:        run.Commit,
 				Branch:        run.Branch,
@@ -328,14 +807,140 @@ func (s *Service) syncWorkflowRuns(repo *types.Repository, owner, repoName strin
 	}
 
 	if len(actions) > 0 {
-		if err := s.actionModel.UpsertActions(actions); err != nil {
-			return fmt.Errorf("failed to upsert actions: %w", err)
-		}
+		results.store(func(m *phaseModels) error {
+			if err := m.actions.UpsertActions(actions); err != nil {
+				return fmt.Errorf("failed to upsert actions: %w", err)
+			}
+			s.changes.mark(types.EntityActions, repo.ID)
+			return nil
+		})
+	}
+
+	s.syncPendingApprovals(repo, owner, repoName, waitingRuns, results)
+
+	if s.collectUsage.Load() {
+		s.syncActionsUsage(repo, owner, repoName, completedRuns, results)
 	}
 
 	return nil
 }
 
+// SetCollectActionsUsage turns recording the billable time of completed workflow runs on or off
+func (s *Service) SetCollectActionsUsage(enabled bool) {
+	s.collectUsage.Store(enabled)
+}
+
+// maxUsageFetchesPerSync bounds the extra timing requests made for a repository in one sync cycle
+const maxUsageFetchesPerSync = 100
+
+type completedWorkflowRun struct {
+	workflow *goGithub.Workflow
+	run      github.WorkflowRun
+}
+
+// syncActionsUsage records the billable time of completed runs that haven't been recorded yet.
+// Collection stops for the cycle as soon as the token or instance turns out not to support it.
+func (s *Service) syncActionsUsage(repo *types.Repository, owner, repoName string, completedRuns []completedWorkflowRun, results *phaseResults) {
+	if s.usageModel == nil {
+		return
+	}
+
+	recorded, err := s.usageModel.GetRecordedRunIDs(repo.ID)
+	if err != nil {
+		log.Printf("Failed to get recorded Actions usage for %s: %v", repo.Name, err)
+		return
+	}
+
+	fetched := 0
+	for _, completed := range completedRuns {
+		if recorded[completed.run.ID] {
+			continue
+		}
+		if fetched >= maxUsageFetchesPerSync {
+			break
+		}
+		fetched++
+
+		usage, err := s.githubClient.GetWorkflowRunUsage(s.requestContext(repo.ID), owner, repoName, completed.run.ID)
+		if errors.Is(err, github.ErrUsageUnavailable) {
+			log.Printf("Actions usage not available for %s, skipping: %v", repo.Name, err)
+			return
+		}
+		if err != nil {
+			log.Printf("Failed to get usage for run %d in %s: %v", completed.run.ID, repo.Name, err)
+			continue
+		}
+
+		runUsage := &types.ActionsRunUsage{
+			RepositoryID:  repo.ID,
+			WorkflowID:    completed.workflow.GetID(),
+			WorkflowName:  completed.workflow.GetName(),
+			WorkflowRunID: completed.run.ID,
+			RunStartedAt:  completed.run.StartedAt,
+			RunDurationMS: usage.RunDurationMS,
+		}
+		for runnerOS, ms := range usage.BillableMS {
+			switch runnerOS {
+			case "UBUNTU":
+				runUsage.UbuntuMS += ms
+			case "MACOS":
+				runUsage.MacOSMS += ms
+			case "WINDOWS":
+				runUsage.WindowsMS += ms
+			default:
+				runUsage.OtherMS += ms
+			}
+		}
+
+		results.store(func(m *phaseModels) error {
+			if err := m.usage.Create(runUsage); err != nil {
+				log.Printf("Failed to store usage for run %d in %s: %v", runUsage.WorkflowRunID, repo.Name, err)
+			}
+			return nil
+		})
+	}
+}
+
+// syncPendingApprovals records which environments the waiting workflow runs need approval for
+func (s *Service) syncPendingApprovals(repo *types.Repository, owner, repoName string, waitingRuns []github.WorkflowRun, results *phaseResults) {
+	if s.approvalModel == nil {
+		return
+	}
+
+	var approvals []types.PendingApproval
+	for _, run := range waitingRuns {
+		pending, err := s.githubClient.GetPendingDeployments(s.requestContext(repo.ID), owner, repoName, run.ID)
+		if err != nil {
+			log.Printf("Failed to get pending deployments for run %d in %s: %v", run.ID, repo.Name, err)
+			continue
+		}
+
+		for _, deployment := range pending {
+			waitingSince := run.StartedAt
+			if deployment.WaitTimerStartedAt != nil {
+				waitingSince = *deployment.WaitTimerStartedAt
+			}
+
+			approvals = append(approvals, types.PendingApproval{
+				RepositoryID:    repo.ID,
+				WorkflowRunID:   run.ID,
+				EnvironmentID:   deployment.EnvironmentID,
+				EnvironmentName: deployment.EnvironmentName,
+				Reviewers:       deployment.Reviewers,
+				WaitingSince:    waitingSince,
+				CanApprove:      deployment.CurrentUserCanApprove,
+			})
+		}
+	}
+
+	results.store(func(m *phaseModels) error {
+		if err := m.approvals.ReplaceForRepository(repo.ID, approvals); err != nil {
+			log.Printf("Failed to store pending approvals for %s: %v", repo.Name, err)
+		}
+		return nil
+	})
+}
+
 func (s *Service) determineActionType(workflowName string) string {
 	workflowName = strings.ToLower(workflowName)
 	
@@ -351,7 +956,7 @@ func (s *Service) determineActionType(workflowName string) string {
 }
 
 func (s *Service) matchWorkflowToService(repositoryID int64, workflowName, branch string) int64 {
-	services, err := s.microserviceModel.GetByRepositoryID(repositoryID)
+	services, err := s.microserviceModel.GetByRepositoryID(repositoryID, true)
 	if err != nil {
 		return 0
 	}
@@ -391,33 +996,9 @@ func (s *Service) matchWorkflowToResource(repositoryID int64, workflowName strin
 	return 0
 }
 
-func parseGitHubURL(repoURL string) (owner, repo string, err error) {
-	u, err := url.Parse(repoURL)
-	if err != nil {
-		return "", "", err
-	}
-
-	// Handle HTTPS URLs only
-	var pathStr string
-	if u.Host == "github.com" {
-		pathStr = u.Path
-	} else {
-		return "", "", fmt.Errorf("only HTTPS GitHub URLs are supported")
-	}
-
-	pathStr = strings.TrimPrefix(pathStr, "/")
-	pathStr = strings.TrimSuffix(pathStr, ".git")
-	
-	parts := strings.Split(pathStr, "/")
-	if len(parts) != 2 {
-		return "", "", fmt.Errorf("invalid repository path")
-	}
-
-	return parts[0], parts[1], nil
-}
-
-// correlateTagWithCommit attempts to find the monorepo commit that corresponds to a deployment tag
-func (s *Service) correlateTagWithCommit(serviceID int64, tag string) string {
+// correlateTagWithCommit attempts to find the monorepo commit that corresponds to a deployment tag.
+// syncingID is the kubernetes repository being synced, whose context the lookups run under.
+func (s *Service) correlateTagWithCommit(syncingID, serviceID int64, tag string) string {
 	// Get the service to find its monorepo
 	service, err := s.microserviceModel.GetByID(serviceID)
 	if err != nil {
@@ -438,7 +1019,7 @@ func (s *Service) correlateTagWithCommit(serviceID int64, tag string) string {
 	}
 
 	// Parse GitHub URL to get owner and repo name
-	owner, repoName, err := parseGitHubURL(repo.URL)
+	owner, repoName, err := vcs.ParseGitHubURL(repo.URL)
 	if err != nil {
 		log.Printf("Failed to parse repo URL %s: %v", repo.URL, err)
 		return ""
@@ -454,7 +1035,7 @@ func (s *Service) correlateTagWithCommit(serviceID int64, tag string) string {
 			ListOptions: goGithub.ListOptions{PerPage: 50},
 		}
 
-		commits, _, err := s.githubClient.GetGitHubClient().Repositories.ListCommits(s.ctx, owner, repoName, commitOpts)
+		commits, err := s.githubClient.ListCommits(s.requestContext(syncingID), owner, repoName, commitOpts)
 		if err != nil {
 			log.Printf("Failed to get commits for service %s: %v", service.Name, err)
 			return ""
@@ -486,7 +1067,7 @@ func (s *Service) correlateTagWithCommit(serviceID int64, tag string) string {
 		}
 
 		// Try to find Git tags in the repository that match
-		tags, _, err := s.githubClient.GetGitHubClient().Repositories.ListTags(s.ctx, owner, repoName, nil)
+		tags, _, err := s.githubClient.GetGitHubClient().Repositories.ListTags(s.requestContext(syncingID), owner, repoName, nil)
 		if err == nil {
 			for _, gitTag := range tags {
 				if gitTag.Name != nil && gitTag.Commit != nil && gitTag.Commit.SHA != nil {
@@ -0,0 +1,201 @@
+package sync
+
+import (
+	"crypto/sha1"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sort"
+	"strings"
+	gosync "sync"
+	"testing"
+
+	"dev-dashboard/internal/database"
+	"dev-dashboard/internal/github"
+	"dev-dashboard/internal/models"
+	"dev-dashboard/internal/testsupport"
+	"dev-dashboard/pkg/types"
+)
+
+// fakeGitHub stands in for the GitHub API: requests are answered by the handler registered for
+// their path, or with a 404. Handlers run under the request's context, so a slow one can block
+// until the sync is cancelled.
+type fakeGitHub struct {
+	mu       gosync.Mutex
+	routes   map[string]http.HandlerFunc
+	requests []string
+}
+
+func newFakeGitHub() *fakeGitHub {
+	return &fakeGitHub{routes: make(map[string]http.HandlerFunc)}
+}
+
+// handle answers requests for path, e.g. /repos/acme/api
+func (f *fakeGitHub) handle(path string, handler http.HandlerFunc) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.routes[path] = handler
+}
+
+// handleRepository answers lookups of the repository, found under its owner and name
+func (f *fakeGitHub) handleRepository(repo *types.Repository) {
+	f.handle(repositoryPath(repo), repositoryFound(repositoryFullName(repo)))
+}
+
+func (f *fakeGitHub) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.mu.Lock()
+	f.requests = append(f.requests, req.URL.Path)
+	handler, ok := f.routes[req.URL.Path]
+	f.mu.Unlock()
+	if !ok {
+		handler = func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, `{"message":"Not Found"}`, http.StatusNotFound)
+		}
+	}
+
+	recorder := httptest.NewRecorder()
+	handler(recorder, req)
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
+	response := recorder.Result()
+	response.Request = req
+	return response, nil
+}
+
+// requestCount returns how often path was requested
+func (f *fakeGitHub) requestCount(path string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	count := 0
+	for _, requested := range f.requests {
+		if requested == path {
+			count++
+		}
+	}
+	return count
+}
+
+func repositoryFullName(repo *types.Repository) string {
+	return strings.TrimPrefix(repo.URL, "https://github.com/")
+}
+
+func repositoryPath(repo *types.Repository) string {
+	return "/repos/" + repositoryFullName(repo)
+}
+
+// repositoryFound answers a repository lookup with the repository's full name
+func repositoryFound(fullName string) http.HandlerFunc {
+	return respondJSON(map[string]string{"full_name": fullName, "default_branch": "main"})
+}
+
+// handleContents serves files, keyed by their path in the repository, through the contents API,
+// along with listings of the directories above them. Serving them again replaces their content.
+func (f *fakeGitHub) handleContents(repo *types.Repository, files map[string]string) {
+	listings := make(map[string][]map[string]string)
+	for filePath, content := range files {
+		f.handle(repositoryPath(repo)+"/contents/"+filePath, respondJSON(map[string]string{
+			"type":     "file",
+			"name":     path.Base(filePath),
+			"path":     filePath,
+			"sha":      fmt.Sprintf("%x", sha1.Sum([]byte(content))),
+			"encoding": "base64",
+			"content":  base64.StdEncoding.EncodeToString([]byte(content)),
+		}))
+		entryType := "file"
+		for child, dir := filePath, path.Dir(filePath); ; child, dir = dir, path.Dir(dir) {
+			listings[dir] = append(listings[dir], map[string]string{"type": entryType, "name": path.Base(child), "path": child})
+			if dir == "." {
+				break
+			}
+			entryType = "dir"
+		}
+	}
+	for dir, entries := range listings {
+		// Listed in path order, as GitHub does
+		sort.Slice(entries, func(i, j int) bool { return entries[i]["path"] < entries[j]["path"] })
+		// Directories above several files are listed once
+		seen := make(map[string]bool)
+		var unique []map[string]string
+		for _, entry := range entries {
+			if !seen[entry["path"]] {
+				seen[entry["path"]] = true
+				unique = append(unique, entry)
+			}
+		}
+		if dir == "." {
+			dir = ""
+		}
+		f.handle(strings.TrimSuffix(repositoryPath(repo)+"/contents/"+dir, "/"), respondJSON(unique))
+	}
+}
+
+// respondJSON answers with body encoded as JSON
+func respondJSON(body interface{}) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(body)
+	}
+}
+
+// respondWith answers with status and a GitHub error message
+func respondWith(status int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, fmt.Sprintf(`{"message":%q}`, http.StatusText(status)), status)
+	}
+}
+
+// newTestService returns a sync service on a fresh test database whose GitHub requests go to fake
+func newTestService(t *testing.T, fake *fakeGitHub) (*Service, *sql.DB) {
+	t.Helper()
+	db := testsupport.NewTestDatabase(t)
+	return newServiceOn(t, fake, db), db.GetConn()
+}
+
+// newServiceOn returns a sync service on db, as the app builds it: its phases store their results
+// through a Writer, in a transaction each. A second one on the same database stands in for the app
+// restarted.
+func newServiceOn(t *testing.T, fake *fakeGitHub, db *database.DB) *Service {
+	t.Helper()
+	writer, err := db.OpenWriter()
+	if err != nil {
+		t.Fatalf("OpenWriter: %v", err)
+	}
+	conn := db.GetConn()
+	config := Config{
+		GitHubToken: "test-token",
+		GitHubClientOptions: []github.Option{
+			github.WithHTTPClient(&http.Client{Transport: fake}),
+			github.WithBaseURL("https://github.test/"),
+		},
+		Transactions: writer,
+	}
+	service := NewService(config,
+		models.NewRepositoryModel(conn), models.NewMicroserviceModel(conn), models.NewKubernetesResourceModel(conn),
+		models.NewActionModel(conn), models.NewDeploymentModel(conn), models.NewStatsSnapshotModel(conn),
+		models.NewSyncLogModel(conn), NewNotifier(models.NewNotificationModel(conn)), models.NewPendingApprovalModel(conn),
+		models.NewActionsUsageModel(conn), models.NewAuditLogModel(conn), models.NewDiscoveryChangeModel(conn),
+		models.NewEnvVarSnapshotModel(conn), models.NewSecurityAlertModel(conn), models.NewServicePackageModel(conn))
+	t.Cleanup(service.Stop)
+	return service
+}
+
+// notificationsOfType returns the stored notifications of a type
+func notificationsOfType(t *testing.T, db *sql.DB, notificationType string) []*types.Notification {
+	t.Helper()
+	notifications, err := models.NewNotificationModel(db).GetRecent(false, 100)
+	if err != nil {
+		t.Fatalf("GetRecent: %v", err)
+	}
+	var matching []*types.Notification
+	for _, notification := range notifications {
+		if notification.Type == notificationType {
+			matching = append(matching, notification)
+		}
+	}
+	return matching
+}
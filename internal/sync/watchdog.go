@@ -0,0 +1,191 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	gosync "sync"
+	"sync/atomic"
+	"time"
+
+	"dev-dashboard/pkg/types"
+)
+
+// DefaultStuckPassMultiple is how many times longer than usual a sync pass may run before the
+// watchdog cancels it
+const DefaultStuckPassMultiple = 3.0
+
+const (
+	// watchdogCheckInterval is how often the watchdog looks at the running pass
+	watchdogCheckInterval = 30 * time.Second
+	// minStuckPassAfter is the least a pass runs before it counts as stuck, however fast passes
+	// usually are; it's also the limit until a pass has completed
+	minStuckPassAfter = 10 * time.Minute
+	// passHistorySize is how many completed passes the usual duration is taken from
+	passHistorySize = 10
+)
+
+// syncPass is a background sync pass. The GitHub calls of the repositories it syncs use its
+// context, which the watchdog cancels when the pass is stuck.
+type syncPass struct {
+	ctx       context.Context
+	cancel    context.CancelFunc
+	startedAt time.Time
+	heartbeat atomic.Pointer[passHeartbeat]
+	stuck     atomic.Pointer[types.SyncLog] // set when the watchdog cancels the pass, written once it ends
+}
+
+// passHeartbeat is the last sign of progress of a pass
+type passHeartbeat struct {
+	at           time.Time
+	repositoryID int64 // 0 before the first repository
+	repository   string
+	step         string
+}
+
+// watchdog watches the background sync passes and remembers how long they take
+type watchdog struct {
+	multiple      atomic.Uint64 // math.Float64bits of the stuck multiple; 0 turns the watchdog off
+	checkInterval time.Duration
+	minStuckAfter time.Duration
+	pass          atomic.Pointer[syncPass]
+
+	mu        gosync.Mutex
+	durations []time.Duration // of the last completed passes, oldest first
+}
+
+func newWatchdog() *watchdog {
+	return &watchdog{checkInterval: watchdogCheckInterval, minStuckAfter: minStuckPassAfter}
+}
+
+// SetStuckPassMultiple changes how many times its usual duration a sync pass may run before the
+// watchdog cancels it; 0 turns the watchdog off
+func (s *Service) SetStuckPassMultiple(multiple float64) {
+	s.watchdog.multiple.Store(math.Float64bits(multiple))
+}
+
+// stuckAfter is how long a pass may run: the configured multiple of the median of the last
+// completed passes, at least minStuckAfter. It's 0 when the watchdog is off.
+func (w *watchdog) stuckAfter() time.Duration {
+	multiple := math.Float64frombits(w.multiple.Load())
+	if multiple <= 0 {
+		return 0
+	}
+
+	w.mu.Lock()
+	durations := append([]time.Duration(nil), w.durations...)
+	w.mu.Unlock()
+	if len(durations) == 0 {
+		return w.minStuckAfter
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	limit := time.Duration(float64(durations[len(durations)/2]) * multiple)
+	if limit < w.minStuckAfter {
+		return w.minStuckAfter
+	}
+	return limit
+}
+
+func (w *watchdog) recordDuration(duration time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.durations = append(w.durations, duration)
+	if len(w.durations) > passHistorySize {
+		w.durations = w.durations[len(w.durations)-passHistorySize:]
+	}
+}
+
+// beginPass starts watching a background sync pass
+func (s *Service) beginPass() *syncPass {
+	ctx, cancel := context.WithCancel(s.ctx)
+	pass := &syncPass{ctx: ctx, cancel: cancel, startedAt: time.Now()}
+	pass.heartbeat.Store(&passHeartbeat{at: pass.startedAt, step: "starting"})
+	s.watchdog.pass.Store(pass)
+	return pass
+}
+
+// endPass stops watching a pass. Only passes that ran to completion count towards the usual
+// duration. A pass the watchdog cancelled is logged and notified only now, once it has stopped.
+func (s *Service) endPass(pass *syncPass) {
+	if pass.ctx.Err() == nil {
+		s.watchdog.recordDuration(time.Since(pass.startedAt))
+	}
+	pass.cancel()
+	s.watchdog.pass.CompareAndSwap(pass, nil)
+
+	if entry := pass.stuck.Load(); entry != nil {
+		if s.syncLogModel != nil {
+			if err := s.syncLogModel.Create(entry); err != nil {
+				log.Printf("Failed to write sync log: %v", err)
+			}
+		}
+		s.notifier.Notify(&types.Notification{RepositoryID: entry.RepositoryID, Type: "sync_stuck", Title: "Sync pass stuck and cancelled", Message: entry.Message})
+	}
+}
+
+// requestContext is the context the sync of a repository runs under, the one it was claimed with:
+// the pass's when the pass syncs it, so the watchdog can cancel it, and the service's for manual
+// syncs, which a stuck pass doesn't take down with it
+func (s *Service) requestContext(repositoryID int64) context.Context {
+	if ctx := s.syncing.context(repositoryID); ctx != nil {
+		return ctx
+	}
+	return s.ctx
+}
+
+// heartbeat records progress of the running pass: the repository and step it's on. Manual syncs
+// running alongside the pass aren't its progress.
+func (s *Service) heartbeat(repo *types.Repository, step string) {
+	if pass := s.watchdog.pass.Load(); pass != nil && s.syncing.context(repo.ID) == pass.ctx {
+		pass.heartbeat.Store(&passHeartbeat{at: time.Now(), repositoryID: repo.ID, repository: repo.Name, step: step})
+	}
+}
+
+// watchPasses checks the running pass every check interval until the service stops
+func (s *Service) watchPasses() {
+	ticker := time.NewTicker(s.watchdog.checkInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.checkStuckPass(now)
+		}
+	}
+}
+
+// checkStuckPass cancels the running pass when it has run past stuckAfter, noting where it got
+// stuck for endPass to log and notify. The pass then winds down and the next one starts on
+// schedule. It reports whether it cancelled the pass.
+func (s *Service) checkStuckPass(now time.Time) bool {
+	pass := s.watchdog.pass.Load()
+	if pass == nil || pass.ctx.Err() != nil {
+		return false
+	}
+	limit := s.watchdog.stuckAfter()
+	elapsed := now.Sub(pass.startedAt)
+	if limit <= 0 || elapsed < limit {
+		return false
+	}
+
+	heartbeat := pass.heartbeat.Load()
+	where := heartbeat.step
+	if heartbeat.repository != "" {
+		where = fmt.Sprintf("%s of %s", heartbeat.step, heartbeat.repository)
+	}
+	message := fmt.Sprintf("Sync pass stuck and cancelled after %s (limit %s); last progress %s ago, in %s",
+		elapsed.Round(time.Second), limit.Round(time.Second), now.Sub(heartbeat.at).Round(time.Second), where)
+	log.Print(message)
+
+	var repositoryID *int64
+	if heartbeat.repositoryID != 0 {
+		repositoryID = &heartbeat.repositoryID
+	}
+	// Noted before cancelling, so the pass can't end without it
+	pass.stuck.Store(&types.SyncLog{RepositoryID: repositoryID, Level: types.SyncLogError, Message: message})
+	pass.cancel()
+	return true
+}
@@ -0,0 +1,131 @@
+package sync
+
+import (
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"dev-dashboard/internal/models"
+	"dev-dashboard/internal/testsupport"
+)
+
+// slowUntilCancelled answers like next, except while slow is set: then it signals started and
+// hangs until the request is cancelled, like a GitHub call during a network flap
+func slowUntilCancelled(slow *atomic.Bool, started chan<- struct{}, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !slow.Load() {
+			next(w, r)
+			return
+		}
+		started <- struct{}{}
+		<-r.Context().Done()
+	}
+}
+
+func waitFor(t *testing.T, ch <-chan struct{}, what string) {
+	t.Helper()
+	select {
+	case <-ch:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for %s", what)
+	}
+}
+
+func TestWatchdogCancelsStuckPassAndNextPassRuns(t *testing.T) {
+	fake := newFakeGitHub()
+	service, db := newTestService(t, fake)
+	service.SetStuckPassMultiple(DefaultStuckPassMultiple)
+	repo := testsupport.Repository(t, db)
+
+	var slow atomic.Bool
+	slow.Store(true)
+	started := make(chan struct{}, 1)
+	fake.handle(repositoryPath(repo), slowUntilCancelled(&slow, started, repositoryFound(repositoryFullName(repo))))
+
+	passDone := make(chan struct{})
+	go func() {
+		service.syncAll()
+		close(passDone)
+	}()
+	waitFor(t, started, "the pass to call GitHub")
+
+	if service.checkStuckPass(time.Now()) {
+		t.Fatal("a pass that just started shouldn't count as stuck")
+	}
+	if !service.checkStuckPass(time.Now().Add(minStuckPassAfter)) {
+		t.Fatal("the watchdog didn't cancel the stuck pass")
+	}
+	waitFor(t, passDone, "the cancelled pass to end")
+
+	logs, err := models.NewSyncLogModel(db).GetByRepositoryID(repo.ID, 10)
+	if err != nil {
+		t.Fatalf("GetByRepositoryID: %v", err)
+	}
+	if len(logs) == 0 || !strings.Contains(logs[0].Message, "stuck and cancelled") {
+		t.Errorf("got sync logs %v, want a stuck and cancelled entry for the repository", logs)
+	}
+	if n := len(notificationsOfType(t, db, "sync_stuck")); n != 1 {
+		t.Errorf("got %d sync_stuck notifications, want 1", n)
+	}
+
+	// The network recovers and the next pass runs to completion
+	slow.Store(false)
+	service.syncAll()
+	if n := fake.requestCount(repositoryPath(repo)); n != 2 {
+		t.Errorf("repository looked up %d times, want once per pass", n)
+	}
+	if service.watchdog.pass.Load() != nil {
+		t.Error("the next pass should have ended")
+	}
+	if len(service.watchdog.durations) != 1 {
+		t.Errorf("recorded %d pass durations, want only the completed pass's", len(service.watchdog.durations))
+	}
+}
+
+func TestWatchdogSparesManualSyncs(t *testing.T) {
+	fake := newFakeGitHub()
+	service, db := newTestService(t, fake)
+	service.SetStuckPassMultiple(DefaultStuckPassMultiple)
+	scheduled := testsupport.Repository(t, db)
+	manual := testsupport.Repository(t, db)
+	// Left out of passes, so only the manual sync syncs it
+	if err := models.NewRepositoryModel(db).SetManualSyncOnly(manual.ID, true); err != nil {
+		t.Fatalf("SetManualSyncOnly: %v", err)
+	}
+
+	var slow atomic.Bool
+	slow.Store(true)
+	passStarted := make(chan struct{}, 1)
+	fake.handle(repositoryPath(scheduled), slowUntilCancelled(&slow, passStarted, nil))
+
+	// The manual sync's lookup waits for release, then reports whether it was cancelled
+	manualStarted, release := make(chan struct{}), make(chan struct{})
+	manualCancelled := make(chan bool, 1)
+	fake.handle(repositoryPath(manual), func(w http.ResponseWriter, r *http.Request) {
+		close(manualStarted)
+		<-release
+		manualCancelled <- r.Context().Err() != nil
+		respondWith(http.StatusUnauthorized)(w, r)
+	})
+
+	passDone := make(chan struct{})
+	go func() {
+		service.syncAll()
+		close(passDone)
+	}()
+	waitFor(t, passStarted, "the pass to call GitHub")
+	go service.SyncRepository(manual.ID)
+	waitFor(t, manualStarted, "the manual sync to call GitHub")
+
+	if !service.checkStuckPass(time.Now().Add(minStuckPassAfter)) {
+		t.Fatal("the watchdog didn't cancel the stuck pass")
+	}
+	waitFor(t, passDone, "the cancelled pass to end")
+
+	close(release)
+	if <-manualCancelled {
+		t.Error("cancelling the stuck pass cancelled a manual sync running alongside it")
+	}
+}
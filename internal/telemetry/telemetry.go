@@ -0,0 +1,115 @@
+// Package telemetry holds the opt-in feature usage counts and sends them to a configured endpoint.
+// Only the feature names listed here and how often each was used are ever collected: no
+// identifiers, names, repository contents or timestamps of individual uses.
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Feature is a feature whose use is counted
+type Feature string
+
+const (
+	QuickAdd             Feature = "quick_add"
+	DeploymentBlame      Feature = "deployment_blame"
+	DeploymentFileDiff   Feature = "deployment_file_diff"
+	EnvVarDiff           Feature = "env_var_diff"
+	EnvironmentReport    Feature = "environment_report"
+	ServiceReport        Feature = "service_report"
+	BuildMatrix          Feature = "build_matrix"
+	CommitImpact         Feature = "commit_impact"
+	ManualSync           Feature = "manual_sync"
+	PresentationMode     Feature = "presentation_mode"
+	SettingsExport       Feature = "settings_export"
+	SettingsImport       Feature = "settings_import"
+	ServiceCatalogExport Feature = "service_catalog_export"
+	ServiceCatalogImport Feature = "service_catalog_import"
+	WatchRuleCreated     Feature = "watch_rule_created"
+	QuickCapture         Feature = "quick_capture"
+	CommitDetail         Feature = "commit_detail"
+)
+
+// Features are all counted features; nothing else is recorded or sent
+var Features = []Feature{
+	QuickAdd, DeploymentBlame, DeploymentFileDiff, EnvVarDiff, EnvironmentReport, ServiceReport, BuildMatrix,
+	CommitImpact, ManualSync, PresentationMode, SettingsExport, SettingsImport, ServiceCatalogExport,
+	ServiceCatalogImport, WatchRuleCreated, QuickCapture, CommitDetail,
+}
+
+// Known reports whether a feature is one of Features
+func Known(feature Feature) bool {
+	for _, known := range Features {
+		if feature == known {
+			return true
+		}
+	}
+	return false
+}
+
+// PayloadSchema is the version of the payload format
+const PayloadSchema = 1
+
+// Payload is exactly what a flush sends: the uses of each feature since the last flush
+type Payload struct {
+	Schema int               `json:"schema"`
+	Counts map[Feature]int64 `json:"counts"`
+}
+
+// NewPayload builds the payload of counts, leaving out unknown features and features not used
+func NewPayload(counts map[Feature]int64) Payload {
+	payload := Payload{Schema: PayloadSchema, Counts: make(map[Feature]int64)}
+	for feature, count := range counts {
+		if count > 0 && Known(feature) {
+			payload.Counts[feature] = count
+		}
+	}
+	return payload
+}
+
+// Empty reports whether the payload has nothing to send
+func (p Payload) Empty() bool {
+	return len(p.Counts) == 0
+}
+
+// JSON returns the payload as sent. Maps are encoded with sorted keys, so the preview is stable.
+func (p Payload) JSON() ([]byte, error) {
+	return json.MarshalIndent(p, "", "  ")
+}
+
+// ValidateEndpoint checks that an endpoint is an absolute http or https URL
+func ValidateEndpoint(endpoint string) error {
+	parsed, err := url.Parse(endpoint)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("telemetry endpoint must be an http or https URL, got %q", endpoint)
+	}
+	return nil
+}
+
+// Send posts a payload to an endpoint as JSON. Any 2xx response counts as delivered.
+func Send(ctx context.Context, client *http.Client, endpoint string, payload Payload) error {
+	body, err := payload.JSON()
+	if err != nil {
+		return fmt.Errorf("failed to encode telemetry: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create telemetry request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send telemetry: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("telemetry endpoint answered %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+// Package testsupport provides an in-memory database and fixture builders for tests of the models
+// and of the code built on them.
+package testsupport
+
+import (
+	"database/sql"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"dev-dashboard/internal/database"
+	"dev-dashboard/internal/models"
+	"dev-dashboard/pkg/types"
+)
+
+// fixtureCount keeps fixture names and URLs unique across a test binary
+var fixtureCount atomic.Int64
+
+func next() int64 {
+	return fixtureCount.Add(1)
+}
+
+// NewTestDB returns a connection to a fresh in-memory database with the full schema and every
+// migration applied, closed when the test ends
+func NewTestDB(t testing.TB) *sql.DB {
+	t.Helper()
+	return NewTestDatabase(t).GetConn()
+}
+
+// NewTestDatabase is NewTestDB for tests that need the database itself, e.g. to count its queries
+func NewTestDatabase(t testing.TB) *database.DB {
+	t.Helper()
+	db, err := database.NewMemoryDB()
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.Migrate(); err != nil {
+		t.Fatalf("failed to migrate test database: %v", err)
+	}
+	return db
+}
+
+// Repository creates a monorepo with a unique name and URL. Options adjust it before it is stored.
+func Repository(t testing.TB, db *sql.DB, options ...func(*types.Repository)) *types.Repository {
+	t.Helper()
+	n := next()
+	repo := &types.Repository{
+		Name:   fmt.Sprintf("repo-%d", n),
+		URL:    fmt.Sprintf("https://github.com/acme/repo-%d", n),
+		Type:   types.MonorepoType,
+		Status: types.RepositoryActive,
+	}
+	for _, option := range options {
+		option(repo)
+	}
+	if err := models.NewRepositoryModel(db).Create(repo); err != nil {
+		t.Fatalf("failed to create repository fixture: %v", err)
+	}
+	return repo
+}
+
+// KubernetesRepository creates a kubernetes repository with a unique name and URL
+func KubernetesRepository(t testing.TB, db *sql.DB, options ...func(*types.Repository)) *types.Repository {
+	t.Helper()
+	options = append([]func(*types.Repository){func(repo *types.Repository) {
+		repo.Type = types.KubernetesType
+	}}, options...)
+	return Repository(t, db, options...)
+}
+
+// Service creates a service in the repository, named uniquely and located under services/
+func Service(t testing.TB, db *sql.DB, repositoryID int64, options ...func(*types.Microservice)) *types.Microservice {
+	t.Helper()
+	name := fmt.Sprintf("service-%d", next())
+	service := &types.Microservice{
+		RepositoryID: repositoryID,
+		Name:         name,
+		Path:         "services/" + name,
+	}
+	for _, option := range options {
+		option(service)
+	}
+	if err := models.NewMicroserviceModel(db).Create(service); err != nil {
+		t.Fatalf("failed to create service fixture: %v", err)
+	}
+	return service
+}
+
+// Deployment creates a deployment of the service to dev in the kubernetes repository
+func Deployment(t testing.TB, db *sql.DB, serviceID, kubernetesRepoID int64, options ...func(*types.Deployment)) *types.Deployment {
+	t.Helper()
+	n := next()
+	deployment := &types.Deployment{
+		ServiceID:        serviceID,
+		KubernetesRepoID: kubernetesRepoID,
+		CommitSHA:        fmt.Sprintf("%040x", n),
+		Environment:      "dev",
+		Region:           "us-east-1",
+		Namespace:        "default",
+		Tag:              fmt.Sprintf("v1.0.%d", n),
+		Path:             fmt.Sprintf("overlays/dev/%d", n),
+	}
+	for _, option := range options {
+		option(deployment)
+	}
+	if err := models.NewDeploymentModel(db).Create(deployment); err != nil {
+		t.Fatalf("failed to create deployment fixture: %v", err)
+	}
+	return deployment
+}
+
+// Project creates a project with a unique name
+func Project(t testing.TB, db *sql.DB, options ...func(*types.Project)) *types.Project {
+	t.Helper()
+	project := &types.Project{Name: fmt.Sprintf("project-%d", next())}
+	for _, option := range options {
+		option(project)
+	}
+	if err := models.NewProjectModel(db).Create(project); err != nil {
+		t.Fatalf("failed to create project fixture: %v", err)
+	}
+	return project
+}
+
+// Task creates a pending task in the project with a unique JIRA ticket, scheduled for today
+func Task(t testing.TB, db *sql.DB, projectID int64, options ...func(*types.Task)) *types.Task {
+	t.Helper()
+	n := next()
+	today := time.Now().Truncate(24 * time.Hour)
+	task := &types.Task{
+		ProjectID:     projectID,
+		JiraTicketID:  fmt.Sprintf("TEST-%d", n),
+		Title:         fmt.Sprintf("task-%d", n),
+		ScheduledDate: &today,
+		Status:        types.TaskPending,
+	}
+	for _, option := range options {
+		option(task)
+	}
+	if err := models.NewTaskModel(db).Create(task); err != nil {
+		t.Fatalf("failed to create task fixture: %v", err)
+	}
+	return task
+}
+
+// FreezeWindow creates a one-off freeze window of environments matching the pattern, covering the
+// hour before and after now
+func FreezeWindow(t testing.TB, db *sql.DB, environmentPattern string, options ...func(*types.FreezeWindow)) *types.FreezeWindow {
+	t.Helper()
+	startsAt, endsAt := time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+	window := &types.FreezeWindow{
+		EnvironmentPattern: environmentPattern,
+		StartsAt:           &startsAt,
+		EndsAt:             &endsAt,
+		Reason:             fmt.Sprintf("freeze-%d", next()),
+	}
+	for _, option := range options {
+		option(window)
+	}
+	if err := models.NewFreezeWindowModel(db).Create(window); err != nil {
+		t.Fatalf("failed to create freeze window fixture: %v", err)
+	}
+	return window
+}
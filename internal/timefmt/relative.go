@@ -0,0 +1,57 @@
+// Package timefmt formats timestamps for display
+package timefmt
+
+import (
+	"fmt"
+	"time"
+)
+
+// Relative describes t as seen at now, in loc: "just now", "5m ago" and "3h ago" within the last
+// day, then "yesterday 14:02" on the previous calendar day, "4d ago" within a week and the date
+// after that. Times ahead of now by up to a minute (clock skew) are "just now" too.
+func Relative(t, now time.Time, loc *time.Location) string {
+	if loc == nil {
+		loc = time.Local
+	}
+	t = t.In(loc)
+	now = now.In(loc)
+
+	elapsed := now.Sub(t)
+	switch {
+	case elapsed < -time.Minute:
+		return t.Format(dateLayout(t, now))
+	case elapsed < time.Minute:
+		return "just now"
+	case elapsed < time.Hour:
+		return fmt.Sprintf("%dm ago", int(elapsed/time.Minute))
+	case elapsed < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(elapsed/time.Hour))
+	}
+
+	days := calendarDays(t, now)
+	switch {
+	case days <= 1:
+		return "yesterday " + t.Format("15:04")
+	case days < 7:
+		return fmt.Sprintf("%dd ago", days)
+	}
+	return t.Format(dateLayout(t, now))
+}
+
+// calendarDays counts the midnights between t and now, both in the same location
+func calendarDays(t, now time.Time) int {
+	ty, tm, td := t.Date()
+	ny, nm, nd := now.Date()
+	// Noon avoids counting a 23 or 25 hour day across a DST change as a different number of days
+	from := time.Date(ty, tm, td, 12, 0, 0, 0, time.UTC)
+	to := time.Date(ny, nm, nd, 12, 0, 0, 0, time.UTC)
+	return int(to.Sub(from) / (24 * time.Hour))
+}
+
+// dateLayout leaves the year out for dates in the current year
+func dateLayout(t, now time.Time) string {
+	if t.Year() == now.Year() {
+		return "Jan 2 15:04"
+	}
+	return "Jan 2, 2006"
+}
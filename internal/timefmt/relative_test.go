@@ -0,0 +1,57 @@
+package timefmt
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRelative(t *testing.T) {
+	now := time.Date(2024, time.March, 5, 15, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		ago  time.Duration
+		want string
+	}{
+		{"now", 0, "just now"},
+		{"59 seconds", 59 * time.Second, "just now"},
+		{"60 seconds", 60 * time.Second, "1m ago"},
+		{"59 minutes", 59 * time.Minute, "59m ago"},
+		{"just under an hour", time.Hour - time.Second, "59m ago"},
+		{"an hour", time.Hour, "1h ago"},
+		{"just under a day, on the previous day", 23*time.Hour + 59*time.Minute, "23h ago"},
+		{"24 hours", 24 * time.Hour, "yesterday 15:00"},
+		{"previous day", 30 * time.Hour, "yesterday 09:00"},
+		{"two days", 48 * time.Hour, "2d ago"},
+		{"six days", 6 * 24 * time.Hour, "6d ago"},
+		{"a week", 7 * 24 * time.Hour, "Feb 27 15:00"},
+		{"previous year", 80 * 24 * time.Hour, "Dec 16, 2023"},
+
+		// Clock skew of up to a minute, then the date of the future time
+		{"30 seconds ahead", -30 * time.Second, "just now"},
+		{"a minute ahead", -time.Minute, "just now"},
+		{"two minutes ahead", -2 * time.Minute, "Mar 5 15:02"},
+		{"later this year", -300 * 24 * time.Hour, "Dec 30 15:00"},
+		{"next year", -400 * 24 * time.Hour, "Apr 9, 2025"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := Relative(now.Add(-test.ago), now, time.UTC); got != test.want {
+				t.Errorf("Relative(now - %s) = %q, want %q", test.ago, got, test.want)
+			}
+		})
+	}
+}
+
+func TestRelativeUsesTheLocationsCalendar(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+	now := time.Date(2024, time.March, 5, 1, 0, 0, 0, tokyo)
+
+	// 26 hours earlier is the previous day in UTC but two days back in Tokyo
+	then := now.Add(-26 * time.Hour)
+	if got := Relative(then, now, tokyo); got != "2d ago" {
+		t.Errorf("in Tokyo got %q, want \"2d ago\"", got)
+	}
+	if got := Relative(then, now, time.UTC); got != "yesterday 14:00" {
+		t.Errorf("in UTC got %q, want \"yesterday 14:00\"", got)
+	}
+}
@@ -0,0 +1,93 @@
+package vcs
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// MatchPathGlob reports whether a slash-separated repository file path matches pattern. "*" and
+// "?" match within one path segment and "**" matches any number of segments. Like .gitignore, a
+// pattern ending in "/" matches everything under that directory, and a pattern with no other "/"
+// matches at any depth, so "openapi.yaml" and "migrations/" match "api/openapi.yaml" and
+// "db/migrations/001.sql". A leading "/" anchors the pattern at the root.
+func MatchPathGlob(pattern, filePath string) bool {
+	segments := globSegments(pattern)
+	if segments == nil {
+		return false
+	}
+	return matchSegments(segments, strings.Split(strings.Trim(filePath, "/"), "/"))
+}
+
+// MatchPathGlobs reports whether filePath matches any of patterns and none of the negated ones,
+// those starting with "!". Unlike .gitignore the order doesn't matter, so "config/**" and
+// "!config/README.md" match every file under config but its README whichever comes first.
+func MatchPathGlobs(patterns []string, filePath string) bool {
+	matched := false
+	for _, pattern := range patterns {
+		if negated, ok := strings.CutPrefix(pattern, "!"); ok {
+			if MatchPathGlob(negated, filePath) {
+				return false
+			}
+		} else if MatchPathGlob(pattern, filePath) {
+			matched = true
+		}
+	}
+	return matched
+}
+
+// ValidatePathGlob returns an error for patterns MatchPathGlobs can't use
+func ValidatePathGlob(pattern string) error {
+	segments := globSegments(strings.TrimPrefix(strings.TrimSpace(pattern), "!"))
+	if segments == nil {
+		return fmt.Errorf("empty path pattern")
+	}
+	for _, segment := range segments {
+		if _, err := path.Match(segment, ""); err != nil {
+			return fmt.Errorf("invalid path pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// globSegments expands the .gitignore-style shorthands of pattern into path segments
+func globSegments(pattern string) []string {
+	pattern = strings.TrimSpace(pattern)
+	anchored := strings.HasPrefix(pattern, "/")
+	directory := strings.HasSuffix(pattern, "/")
+	pattern = strings.Trim(pattern, "/")
+	if pattern == "" {
+		return nil
+	}
+
+	segments := strings.Split(pattern, "/")
+	if !anchored && len(segments) == 1 {
+		segments = append([]string{"**"}, segments...)
+	}
+	if directory {
+		// Anything under the directory, but not a file with the directory's name
+		segments = append(segments, "**", "*")
+	}
+	return segments
+}
+
+func matchSegments(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchSegments(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	if ok, err := path.Match(pattern[0], segments[0]); err != nil || !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], segments[1:])
+}
@@ -0,0 +1,87 @@
+package vcs
+
+import "testing"
+
+func TestMatchPathGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"api/openapi.yaml", "api/openapi.yaml", true},
+		{"api/openapi.yaml", "v2/api/openapi.yaml", false},
+		{"api/*.yaml", "api/openapi.yaml", true},
+		{"api/*.yaml", "api/v2/openapi.yaml", false},
+		{"api/?.go", "api/a.go", true},
+		{"api/?.go", "api/ab.go", false},
+
+		// A pattern without another slash matches at any depth
+		{"openapi.yaml", "openapi.yaml", true},
+		{"openapi.yaml", "services/api/openapi.yaml", true},
+		{"*.sql", "db/migrations/001.sql", true},
+
+		// ** matches any number of segments, none included
+		{"**/secrets.yaml", "secrets.yaml", true},
+		{"**/secrets.yaml", "deploy/prod/secrets.yaml", true},
+		{"deploy/**/values.yaml", "deploy/values.yaml", true},
+		{"deploy/**/values.yaml", "deploy/prod/eu/values.yaml", true},
+		{"deploy/**/values.yaml", "other/prod/values.yaml", false},
+		{"deploy/**", "deploy/prod/eu/values.yaml", true},
+		{"deploy/**", "deployments/values.yaml", false},
+
+		// A trailing slash matches everything under the directory but not a file of its name
+		{"migrations/", "db/migrations/001.sql", true},
+		{"migrations/", "db/migrations/2024/001.sql", true},
+		{"migrations/", "db/migrations", false},
+
+		// A leading slash anchors at the root
+		{"/openapi.yaml", "openapi.yaml", true},
+		{"/openapi.yaml", "api/openapi.yaml", false},
+
+		{"", "openapi.yaml", false},
+		{"/", "openapi.yaml", false},
+		{"api/[", "api/[", false},
+	}
+	for _, test := range tests {
+		if got := MatchPathGlob(test.pattern, test.path); got != test.want {
+			t.Errorf("MatchPathGlob(%q, %q) = %v, want %v", test.pattern, test.path, got, test.want)
+		}
+	}
+}
+
+func TestMatchPathGlobsNegation(t *testing.T) {
+	patterns := []string{"!config/README.md", "config/**", "*.pem", "!testdata/"}
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"config/app.yaml", true},
+		{"config/nested/app.yaml", true},
+		{"config/README.md", false},
+		{"certs/server.pem", true},
+		{"testdata/server.pem", false},
+		{"src/main.go", false},
+	}
+	for _, test := range tests {
+		if got := MatchPathGlobs(patterns, test.path); got != test.want {
+			t.Errorf("MatchPathGlobs(%q, %q) = %v, want %v", patterns, test.path, got, test.want)
+		}
+	}
+
+	if MatchPathGlobs([]string{"!config/README.md"}, "src/main.go") {
+		t.Error("negated patterns alone shouldn't match anything")
+	}
+}
+
+func TestValidatePathGlob(t *testing.T) {
+	for _, pattern := range []string{"openapi.yaml", "deploy/**/values.yaml", "migrations/", "!config/README.md"} {
+		if err := ValidatePathGlob(pattern); err != nil {
+			t.Errorf("ValidatePathGlob(%q): %v", pattern, err)
+		}
+	}
+	for _, pattern := range []string{"", " / ", "!", "api/["} {
+		if err := ValidatePathGlob(pattern); err == nil {
+			t.Errorf("ValidatePathGlob(%q) accepted an unusable pattern", pattern)
+		}
+	}
+}
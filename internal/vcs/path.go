@@ -0,0 +1,44 @@
+package vcs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CleanRepoPath canonicalizes a path relative to the repository root, so "/services/", "./services"
+// and "services//" all become "services". Leading "./" and "/", "." segments, duplicate slashes
+// and trailing slashes are dropped, and the repository root is "". Paths with ".." segments are
+// rejected since they could point outside the repository.
+func CleanRepoPath(p string) (string, error) {
+	var segments []string
+	for _, segment := range strings.Split(strings.TrimSpace(p), "/") {
+		switch segment {
+		case "", ".":
+			continue
+		case "..":
+			return "", fmt.Errorf("path %q must not contain \"..\"", p)
+		}
+		segments = append(segments, segment)
+	}
+	return strings.Join(segments, "/"), nil
+}
+
+// CleanServiceLocation canonicalizes a repository's service location like CleanRepoPath. An empty
+// location means the default folder, so a location naming the repository root ("/", "./") is kept
+// as ".".
+func CleanServiceLocation(location string) (string, error) {
+	cleaned, err := CleanRepoPath(location)
+	if err != nil || cleaned != "" || strings.TrimSpace(location) == "" {
+		return cleaned, err
+	}
+	return ".", nil
+}
+
+// PathWithin reports whether a file path is dir or lies beneath it. Both are repository-relative
+// paths in the form CleanRepoPath returns; every path is within the repository root "".
+func PathWithin(filePath, dir string) bool {
+	if dir == "" {
+		return true
+	}
+	return filePath == dir || strings.HasPrefix(filePath, dir+"/")
+}
@@ -0,0 +1,133 @@
+package vcs
+
+import (
+	"strconv"
+	"strings"
+
+	"dev-dashboard/pkg/types"
+)
+
+// DefaultTagPrefixes are stripped from deployment tags before parsing them as semver
+var DefaultTagPrefixes = []string{"v"}
+
+// ParseTagVersion parses a deployment tag as a semantic version (MAJOR.MINOR.PATCH with optional
+// -prerelease and +build parts) after stripping the longest matching prefix. Tags that aren't
+// semver, such as commit SHAs or "latest", return nil.
+func ParseTagVersion(tag string, prefixes []string) *types.TagVersion {
+	tag = strings.TrimSpace(tag)
+	longest := ""
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(tag, prefix) && len(prefix) > len(longest) {
+			longest = prefix
+		}
+	}
+	tag = strings.TrimPrefix(tag, longest)
+
+	version := &types.TagVersion{}
+	tag, version.Build, _ = strings.Cut(tag, "+")
+	tag, version.Prerelease, _ = strings.Cut(tag, "-")
+
+	parts := strings.Split(tag, ".")
+	if len(parts) != 3 {
+		return nil
+	}
+	numbers := make([]int, 3)
+	for i, part := range parts {
+		n, ok := parseNumericIdentifier(part)
+		if !ok {
+			return nil
+		}
+		numbers[i] = n
+	}
+	version.Major, version.Minor, version.Patch = numbers[0], numbers[1], numbers[2]
+
+	if !validIdentifiers(version.Prerelease) || !validIdentifiers(version.Build) {
+		return nil
+	}
+	return version
+}
+
+// parseNumericIdentifier parses a version number, which semver doesn't allow leading zeros in
+func parseNumericIdentifier(value string) (int, bool) {
+	if value == "" || (len(value) > 1 && value[0] == '0') {
+		return 0, false
+	}
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return 0, false
+		}
+	}
+	n, err := strconv.Atoi(value)
+	return n, err == nil
+}
+
+// validIdentifiers checks dot-separated prerelease or build identifiers; empty means none
+func validIdentifiers(value string) bool {
+	if value == "" {
+		return true
+	}
+	for _, identifier := range strings.Split(value, ".") {
+		if identifier == "" {
+			return false
+		}
+		for _, r := range identifier {
+			if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '-') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// CompareTagVersions returns -1, 0 or 1 as a is lower than, equal to or higher than b in semver
+// precedence. Build metadata is ignored; a prerelease is lower than its release.
+func CompareTagVersions(a, b *types.TagVersion) int {
+	for _, pair := range [][2]int{{a.Major, b.Major}, {a.Minor, b.Minor}, {a.Patch, b.Patch}} {
+		if pair[0] != pair[1] {
+			return compareInts(pair[0], pair[1])
+		}
+	}
+
+	switch {
+	case a.Prerelease == b.Prerelease:
+		return 0
+	case a.Prerelease == "":
+		return 1
+	case b.Prerelease == "":
+		return -1
+	}
+
+	aIDs := strings.Split(a.Prerelease, ".")
+	bIDs := strings.Split(b.Prerelease, ".")
+	for i := 0; i < len(aIDs) && i < len(bIDs); i++ {
+		if c := compareIdentifiers(aIDs[i], bIDs[i]); c != 0 {
+			return c
+		}
+	}
+	return compareInts(len(aIDs), len(bIDs))
+}
+
+// compareIdentifiers orders numeric prerelease identifiers numerically and below alphanumeric ones
+func compareIdentifiers(a, b string) int {
+	aNumber, aNumeric := parseNumericIdentifier(a)
+	bNumber, bNumeric := parseNumericIdentifier(b)
+	switch {
+	case aNumeric && bNumeric:
+		return compareInts(aNumber, bNumber)
+	case aNumeric:
+		return -1
+	case bNumeric:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
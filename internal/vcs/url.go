@@ -0,0 +1,126 @@
+package vcs
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+type Provider string
+
+const (
+	GitHub    Provider = "github"
+	GitLab    Provider = "gitlab"
+	Bitbucket Provider = "bitbucket"
+)
+
+// RepositoryURL is a parsed repository URL. For GitLab, Owner is the full group path,
+// which can contain slashes for nested groups.
+type RepositoryURL struct {
+	Provider Provider
+	Host     string
+	Owner    string
+	Repo     string
+}
+
+// FullName returns "owner/repo", or "group/subgroup/project" on GitLab
+func (r *RepositoryURL) FullName() string {
+	return r.Owner + "/" + r.Repo
+}
+
+// DetectProvider guesses the provider from a host name. Hosts that aren't recognisably GitLab or
+// Bitbucket are assumed to be GitHub Enterprise.
+func DetectProvider(host string) Provider {
+	host = strings.ToLower(host)
+	switch {
+	case host == "gitlab.com" || strings.HasPrefix(host, "gitlab."):
+		return GitLab
+	case host == "bitbucket.org":
+		return Bitbucket
+	default:
+		return GitHub
+	}
+}
+
+// ParseRepositoryURL extracts the provider, owner and repository name from a repository URL such
+// as https://github.com/owner/repo, https://github.example.com/owner/repo.git,
+// https://gitlab.com/group/subgroup/project or https://bitbucket.org/workspace/repo, or from an
+// SSH remote like ssh://git@github.com/owner/repo.git or git@github.com:owner/repo.git. Trailing
+// paths like /tree/main (or /-/tree/main on GitLab) are ignored.
+func ParseRepositoryURL(repoURL string) (*RepositoryURL, error) {
+	repoURL = strings.TrimSpace(repoURL)
+	if repoURL == "" {
+		return nil, fmt.Errorf("repository URL is empty")
+	}
+
+	// scp-style remotes are SSH URLs with the path after a colon
+	if !strings.Contains(repoURL, "://") {
+		if userHost, repoPath, ok := strings.Cut(repoURL, ":"); ok && strings.Contains(userHost, "@") {
+			repoURL = "ssh://" + userHost + "/" + repoPath
+		}
+	}
+
+	u, err := url.Parse(repoURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid repository URL: %w", err)
+	}
+	if (u.Scheme != "https" && u.Scheme != "ssh") || u.Host == "" {
+		return nil, fmt.Errorf("only HTTPS and SSH URLs are supported")
+	}
+	// The host the repository is browsed and called on; an SSH port doesn't apply to either
+	host := u.Host
+	if u.Scheme == "ssh" {
+		host = u.Hostname()
+	}
+
+	var parts []string
+	for _, part := range strings.Split(u.Path, "/") {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	parsed := &RepositoryURL{
+		Provider: DetectProvider(u.Hostname()),
+		Host:     strings.ToLower(host),
+	}
+
+	if parsed.Provider == GitLab {
+		// GitLab separates the project path from pages within it with "/-/"
+		for i, part := range parts {
+			if part == "-" {
+				parts = parts[:i]
+				break
+			}
+		}
+		if len(parts) < 2 {
+			return nil, fmt.Errorf("invalid repository path %q", u.Path)
+		}
+		parsed.Owner = strings.Join(parts[:len(parts)-1], "/")
+		parsed.Repo = strings.TrimSuffix(parts[len(parts)-1], ".git")
+	} else {
+		if len(parts) < 2 {
+			return nil, fmt.Errorf("invalid repository path %q", u.Path)
+		}
+		parsed.Owner = parts[0]
+		parsed.Repo = strings.TrimSuffix(parts[1], ".git")
+	}
+
+	if parsed.Repo == "" {
+		return nil, fmt.Errorf("invalid repository path %q", u.Path)
+	}
+	return parsed, nil
+}
+
+// ParseGitHubURL returns the owner and name of a GitHub or GitHub Enterprise repository,
+// rejecting URLs of other providers
+func ParseGitHubURL(repoURL string) (owner, repo string, err error) {
+	parsed, err := ParseRepositoryURL(repoURL)
+	if err != nil {
+		return "", "", err
+	}
+	if parsed.Provider != GitHub {
+		return "", "", fmt.Errorf("%s repositories are not supported yet", parsed.Provider)
+	}
+	return parsed.Owner, parsed.Repo, nil
+}
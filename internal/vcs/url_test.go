@@ -0,0 +1,63 @@
+package vcs
+
+import "testing"
+
+func TestParseRepositoryURL(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		want RepositoryURL
+	}{
+		{"https", "https://github.com/acme/api", RepositoryURL{GitHub, "github.com", "acme", "api"}},
+		{"https with .git", "https://github.com/acme/api.git", RepositoryURL{GitHub, "github.com", "acme", "api"}},
+		{"trailing slash", "https://github.com/acme/api/", RepositoryURL{GitHub, "github.com", "acme", "api"}},
+		{"page within the repository", "https://github.com/acme/api/tree/main/cmd", RepositoryURL{GitHub, "github.com", "acme", "api"}},
+		{"surrounding whitespace", "  https://github.com/acme/api\n", RepositoryURL{GitHub, "github.com", "acme", "api"}},
+		{"ssh", "ssh://git@github.com/acme/api.git", RepositoryURL{GitHub, "github.com", "acme", "api"}},
+		{"ssh with port", "ssh://git@github.example.com:2222/acme/api.git", RepositoryURL{GitHub, "github.example.com", "acme", "api"}},
+		{"scp-style", "git@github.com:acme/api.git", RepositoryURL{GitHub, "github.com", "acme", "api"}},
+		{"scp-style without .git", "git@github.com:acme/api", RepositoryURL{GitHub, "github.com", "acme", "api"}},
+		{"enterprise host", "https://GitHub.Example.com/acme/api.git", RepositoryURL{GitHub, "github.example.com", "acme", "api"}},
+		{"gitlab", "https://gitlab.com/acme/api", RepositoryURL{GitLab, "gitlab.com", "acme", "api"}},
+		{"gitlab nested groups", "https://gitlab.com/acme/platform/backend/api.git", RepositoryURL{GitLab, "gitlab.com", "acme/platform/backend", "api"}},
+		{"gitlab page within the project", "https://gitlab.com/acme/platform/api/-/tree/main", RepositoryURL{GitLab, "gitlab.com", "acme/platform", "api"}},
+		{"gitlab self-hosted over scp-style ssh", "git@gitlab.example.com:acme/platform/api.git", RepositoryURL{GitLab, "gitlab.example.com", "acme/platform", "api"}},
+		{"bitbucket", "https://bitbucket.org/acme/api", RepositoryURL{Bitbucket, "bitbucket.org", "acme", "api"}},
+		{"bitbucket over ssh", "git@bitbucket.org:acme/api.git", RepositoryURL{Bitbucket, "bitbucket.org", "acme", "api"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ParseRepositoryURL(test.url)
+			if err != nil {
+				t.Fatalf("ParseRepositoryURL(%q): %v", test.url, err)
+			}
+			if *got != test.want {
+				t.Errorf("ParseRepositoryURL(%q) = %+v, want %+v", test.url, *got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseRepositoryURLRejectsInvalidURLs(t *testing.T) {
+	for _, url := range []string{
+		"",
+		"http://github.com/acme/api",
+		"github.com/acme/api",
+		"https://github.com/acme",
+		"https://github.com/acme/.git",
+		"git@github.com:acme",
+		"https://gitlab.com/acme/-/tree/main",
+	} {
+		if got, err := ParseRepositoryURL(url); err == nil {
+			t.Errorf("ParseRepositoryURL(%q) = %+v, want an error", url, *got)
+		}
+	}
+}
+
+func TestParseGitHubURLRejectsOtherProviders(t *testing.T) {
+	for _, url := range []string{"https://gitlab.com/acme/api", "git@bitbucket.org:acme/api.git"} {
+		if _, _, err := ParseGitHubURL(url); err == nil {
+			t.Errorf("ParseGitHubURL(%q) accepted a repository that isn't on GitHub", url)
+		}
+	}
+}
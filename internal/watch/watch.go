@@ -0,0 +1,197 @@
+// Package watch evaluates watch rules: which services and environments a rule scopes, and whether
+// its conditions fire for them. It only works on data it's handed, so it makes no requests.
+package watch
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"dev-dashboard/pkg/types"
+)
+
+// ServiceState is what rules are evaluated against for one service
+type ServiceState struct {
+	Service *types.Microservice // with its custom fields
+	// Environments the service is deployed to, in promotion order
+	Environments []string
+	// LastDeployAt is when each environment last started running another tag
+	LastDeployAt map[string]time.Time
+	// History is the service's deployment history, at least over the window being evaluated
+	History []*types.DeploymentHistoryEntry
+	// Deploys are the service's completed deployment runs, at least over the window being evaluated
+	Deploys []*types.Action
+	// Drift is how far each environment is behind the one before it in the promotion order; nil
+	// when no rule needs it
+	Drift map[string]*types.DeploymentDrift
+}
+
+// Result is the outcome of evaluating a rule
+type Result struct {
+	Outcomes []types.WatchOutcome
+	// Active lists the keys of the state conditions that hold, which fire again once they stop
+	// holding and hold once more
+	Active []string
+}
+
+// Fired returns the outcomes that fired
+func (r *Result) Fired() []types.WatchOutcome {
+	var fired []types.WatchOutcome
+	for _, outcome := range r.Outcomes {
+		if outcome.Fired {
+			fired = append(fired, outcome)
+		}
+	}
+	return fired
+}
+
+// InScope reports whether a rule applies to a service
+func InScope(rule *types.WatchRule, service *types.Microservice) bool {
+	switch rule.Scope {
+	case types.WatchScopeAll:
+		return true
+	case types.WatchScopeServices:
+		for _, id := range rule.ServiceIDs {
+			if id == service.ID {
+				return true
+			}
+		}
+	case types.WatchScopeCustomField:
+		return service.CustomFields[rule.ScopeField] == rule.ScopeValue
+	case types.WatchScopeDomain:
+		return strings.EqualFold(service.Domain, rule.ScopeValue)
+	}
+	return false
+}
+
+// Watches reports whether a rule watches an environment
+func Watches(rule *types.WatchRule, environment string) bool {
+	if len(rule.Environments) == 0 {
+		return true
+	}
+	for _, watched := range rule.Environments {
+		if strings.EqualFold(watched, environment) {
+			return true
+		}
+	}
+	return false
+}
+
+// Evaluate evaluates a rule against the services in its scope. Event conditions look at what
+// happened after since and up to now; state conditions fire when they hold and their key isn't
+// among the active ones of the previous evaluation.
+func Evaluate(rule *types.WatchRule, states []*ServiceState, active map[string]bool, since, now time.Time) *Result {
+	result := &Result{}
+	for _, state := range states {
+		service := state.Service
+		outcome := func(environment, condition string, fired bool, detail string) {
+			result.Outcomes = append(result.Outcomes, types.WatchOutcome{
+				ServiceID:   service.ID,
+				ServiceName: service.Name,
+				Environment: environment,
+				Condition:   condition,
+				Fired:       fired,
+				Detail:      detail,
+			})
+		}
+		holding := func(environment, condition string, holds bool, detail string) {
+			key := fmt.Sprintf("%s|%d|%s", condition, service.ID, environment)
+			if holds {
+				result.Active = append(result.Active, key)
+				if active[key] {
+					detail += ", already reported"
+				}
+			}
+			outcome(environment, condition, holds && !active[key], detail)
+		}
+
+		var environments []string
+		for _, environment := range state.Environments {
+			if Watches(rule, environment) {
+				environments = append(environments, environment)
+			}
+		}
+		if len(environments) == 0 && len(rule.Environments) > 0 {
+			outcome("", "", false, fmt.Sprintf("Not deployed to %s", strings.Join(rule.Environments, ", ")))
+		}
+
+		for _, condition := range rule.Conditions {
+			if condition.Type == types.WatchDeployFailed {
+				fired, detail := deployFailed(state.Deploys, since, now)
+				outcome("", condition.Type, fired, detail)
+				continue
+			}
+
+			for _, environment := range environments {
+				switch condition.Type {
+				case types.WatchTagChanged:
+					fired, detail := tagChanged(state.History, environment, since, now)
+					outcome(environment, condition.Type, fired, detail)
+				case types.WatchNoDeploy:
+					deployedAt, ok := state.LastDeployAt[environment]
+					if !ok {
+						outcome(environment, condition.Type, false, fmt.Sprintf("No deployment to %s recorded", environment))
+						continue
+					}
+					age := now.Sub(deployedAt)
+					days := int(age / (24 * time.Hour))
+					holding(environment, condition.Type, age >= time.Duration(condition.Threshold)*24*time.Hour,
+						fmt.Sprintf("%s last deployed %d days ago (threshold %d)", environment, days, condition.Threshold))
+				case types.WatchDrift:
+					drift := state.Drift[environment]
+					switch {
+					case drift == nil:
+						outcome(environment, condition.Type, false, fmt.Sprintf("%s is first in the promotion order", environment))
+					case drift.Method != types.DriftByCommits:
+						outcome(environment, condition.Type, false, drift.Summary)
+					default:
+						holding(environment, condition.Type, drift.CommitsBehind > condition.Threshold,
+							fmt.Sprintf("%s (threshold %d)", drift.Summary, condition.Threshold))
+					}
+				}
+			}
+		}
+	}
+	sort.Strings(result.Active)
+	return result
+}
+
+// tagChanged reports the tags an environment started running after since
+func tagChanged(history []*types.DeploymentHistoryEntry, environment string, since, now time.Time) (bool, string) {
+	var tags []string
+	seen := make(map[string]bool)
+	for _, entry := range history {
+		if entry.Environment != environment || !entry.ObservedAt.After(since) || entry.ObservedAt.After(now) {
+			continue
+		}
+		if !seen[entry.Tag] {
+			seen[entry.Tag] = true
+			tags = append(tags, entry.Tag)
+		}
+	}
+	if len(tags) == 0 {
+		return false, fmt.Sprintf("No new tag in %s", environment)
+	}
+	return true, fmt.Sprintf("%s now runs %s", environment, strings.Join(tags, ", "))
+}
+
+// deployFailed reports the deployment runs that failed after since
+func deployFailed(deploys []*types.Action, since, now time.Time) (bool, string) {
+	var failed []string
+	for _, action := range deploys {
+		if action.Conclusion != "failure" || action.CompletedAt == nil ||
+			!action.CompletedAt.After(since) || action.CompletedAt.After(now) {
+			continue
+		}
+		commit := action.Commit
+		if len(commit) > 7 {
+			commit = commit[:7]
+		}
+		failed = append(failed, fmt.Sprintf("%s on %s", commit, action.Branch))
+	}
+	if len(failed) == 0 {
+		return false, "No failed deployment runs"
+	}
+	return true, fmt.Sprintf("Deployment failed: %s", strings.Join(failed, "; "))
+}
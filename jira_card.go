@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+
+	"dev-dashboard/internal/jira"
+	"dev-dashboard/internal/sync"
+	"dev-dashboard/pkg/types"
+)
+
+const (
+	// jiraSprintFieldKey is the ID of the custom field tickets' sprints are read from; empty uses
+	// jira.DefaultSprintField, the field JIRA Cloud uses
+	jiraSprintFieldKey = "jira_sprint_field"
+	// jiraEpicLinkFieldKey is the ID of the epic link custom field of instances that link epics
+	// through one instead of the parent field; empty reads epics from the parent only
+	jiraEpicLinkFieldKey = "jira_epic_link_field"
+	// jiraDueDateFillsDeadlineKey fills the deadline of a task without one from its ticket's due
+	// date when "true"
+	jiraDueDateFillsDeadlineKey = "jira_due_date_fills_deadline"
+)
+
+var jiraCustomFieldID = regexp.MustCompile(`^customfield_\d+$`)
+
+// validateJiraCustomField checks a custom field ID config value, e.g. customfield_10020
+func validateJiraCustomField(key, value string) error {
+	if value != "" && !jiraCustomFieldID.MatchString(value) {
+		return fmt.Errorf("%s must be a custom field ID like customfield_10020, got %q", key, value)
+	}
+	return nil
+}
+
+// applyJiraCustomFields configures the custom fields a JIRA client reads sprints and epics from
+func (a *App) applyJiraCustomFields(client *jira.Client) {
+	var sprintField, epicLinkField string
+	if config, err := a.configModel.Get(jiraSprintFieldKey); err == nil && config != nil {
+		sprintField = config.Value
+	}
+	if config, err := a.configModel.Get(jiraEpicLinkFieldKey); err == nil && config != nil {
+		epicLinkField = config.Value
+	}
+	client.SetCustomFields(sprintField, epicLinkField)
+}
+
+// jiraDueDateFillsDeadline reports whether tasks without a deadline take their ticket's due date
+func (a *App) jiraDueDateFillsDeadline() bool {
+	if a.configModel != nil {
+		if config, err := a.configModel.Get(jiraDueDateFillsDeadlineKey); err == nil && config != nil {
+			return config.Value == "true"
+		}
+	}
+	return false
+}
+
+// setTaskJiraFields fetches a task's ticket and copies its fields onto the task, filling its
+// deadline from the ticket's due date when configured to
+func (a *App) setTaskJiraFields(task *types.Task) error {
+	issue, err := a.jiraClient.GetIssue(task.JiraTicketID)
+	if err != nil {
+		return err
+	}
+	sync.SetJiraFields(task, a.jiraClient, issue)
+	if task.Deadline == nil && task.JiraDueDate != nil && a.jiraDueDateFillsDeadline() {
+		deadline := *task.JiraDueDate
+		task.Deadline = &deadline
+	}
+	return nil
+}
+
+// refreshTaskJiraFields fetches a stored task's ticket and stores its fields on the task
+func (a *App) refreshTaskJiraFields(taskID int64, ticketID string) error {
+	task, err := a.taskModel.GetByID(taskID)
+	if err != nil {
+		return err
+	}
+	task.JiraTicketID = ticketID
+	if err := a.setTaskJiraFields(task); err != nil {
+		log.Printf("Failed to fetch JIRA ticket %s: %v", ticketID, err)
+		return err
+	}
+	if err := a.taskModel.UpdateJiraFields(task); err != nil {
+		return err
+	}
+	if task.Deadline != nil {
+		return a.taskModel.FillDeadline(task.ID, *task.Deadline)
+	}
+	return nil
+}
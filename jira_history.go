@@ -0,0 +1,135 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"dev-dashboard/internal/jira"
+	"dev-dashboard/pkg/types"
+)
+
+// jiraHistoryTTL is how long a ticket's changelog is reused before JIRA is asked again
+const jiraHistoryTTL = 10 * time.Minute
+
+// jiraHistoryCache keeps the changelogs of JIRA tickets by key for jiraHistoryTTL
+type jiraHistoryCache struct {
+	mu      sync.Mutex
+	entries map[string]jiraHistoryEntry
+}
+
+type jiraHistoryEntry struct {
+	changelog *jira.IssueChangelog
+	fetchedAt time.Time
+}
+
+func newJiraHistoryCache() *jiraHistoryCache {
+	return &jiraHistoryCache{entries: make(map[string]jiraHistoryEntry)}
+}
+
+func (c *jiraHistoryCache) get(key string) (jiraHistoryEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok || time.Since(entry.fetchedAt) > jiraHistoryTTL {
+		return jiraHistoryEntry{}, false
+	}
+	return entry, true
+}
+
+func (c *jiraHistoryCache) put(key string, changelog *jira.IssueChangelog) jiraHistoryEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry := jiraHistoryEntry{changelog: changelog, fetchedAt: time.Now()}
+	c.entries[key] = entry
+	return entry
+}
+
+// GetTaskJiraHistory returns the status transitions of a task's JIRA ticket and how long it spent in
+// each status
+func (a *App) GetTaskJiraHistory(taskID int64) (*types.JiraStatusHistory, error) {
+	if a.taskModel == nil {
+		return nil, fmt.Errorf("task model not initialized")
+	}
+	task, err := a.taskModel.GetByID(taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task.JiraTicketID == "" {
+		return nil, fmt.Errorf("task %d isn't linked to a JIRA ticket", taskID)
+	}
+
+	entry, ok := a.jiraHistoryCache.get(task.JiraTicketID)
+	if !ok {
+		if a.jiraClient == nil {
+			return nil, fmt.Errorf("JIRA client not configured")
+		}
+		changelog, err := a.jiraClient.GetIssueChangelog(task.JiraTicketID)
+		if err != nil {
+			return nil, err
+		}
+		entry = a.jiraHistoryCache.put(task.JiraTicketID, changelog)
+	}
+
+	history := buildJiraStatusHistory(entry.changelog, time.Now())
+	history.TaskID = taskID
+	history.FetchedAt = entry.fetchedAt
+	clock := a.displayClock()
+	for _, transition := range history.Transitions {
+		transition.AtRelative = clock.relative(transition.At)
+	}
+	return history, nil
+}
+
+// buildJiraStatusHistory converts a changelog and sums the time spent in each status up to now. The
+// status before the first transition is the one it left; without transitions, the ticket has been
+// in its current status since it was created.
+func buildJiraStatusHistory(changelog *jira.IssueChangelog, now time.Time) *types.JiraStatusHistory {
+	history := &types.JiraStatusHistory{
+		IssueKey:     changelog.Key,
+		Status:       changelog.Status,
+		Created:      changelog.Created,
+		Transitions:  []*types.JiraStatusTransition{},
+		TimeInStatus: []*types.JiraStatusDuration{},
+	}
+
+	durations := make(map[string]*types.JiraStatusDuration)
+	addTime := func(status string, from, to time.Time) {
+		if status == "" {
+			return
+		}
+		duration, ok := durations[status]
+		if !ok {
+			duration = &types.JiraStatusDuration{Status: status}
+			durations[status] = duration
+			history.TimeInStatus = append(history.TimeInStatus, duration)
+		}
+		if !from.IsZero() && to.After(from) {
+			duration.Seconds += int64(to.Sub(from).Seconds())
+		}
+	}
+
+	status, since := changelog.Status, changelog.Created
+	if len(changelog.Transitions) > 0 {
+		status = changelog.Transitions[0].From
+	}
+	for _, transition := range changelog.Transitions {
+		author := ""
+		if transition.Author != nil {
+			author = transition.Author.DisplayName
+		}
+		history.Transitions = append(history.Transitions, &types.JiraStatusTransition{
+			From:   transition.From,
+			To:     transition.To,
+			At:     transition.At,
+			Author: author,
+		})
+		addTime(status, since, transition.At)
+		status, since = transition.To, transition.At
+	}
+	addTime(status, since, now)
+	if current := durations[status]; current != nil {
+		current.Current = true
+	}
+	return history
+}
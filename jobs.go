@@ -0,0 +1,393 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"dev-dashboard/pkg/types"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const (
+	// jobProgressEventName is emitted with the job when a running job reports progress
+	jobProgressEventName = "job:progress"
+	// jobFinishedEventName is emitted with the job when a job succeeded, failed or was cancelled
+	jobFinishedEventName = "job:finished"
+
+	// acknowledgedJobRetention is how long acknowledged jobs are kept before startup deletes them
+	acknowledgedJobRetention = 30 * 24 * time.Hour
+
+	// usageExportBatch is how many usage events are written between cancellation checks
+	usageExportBatch = 500
+)
+
+// jobReporter reports the progress of a running job as a percentage and a short message
+type jobReporter func(progress int, message string)
+
+// jobOutcome is what a job produced: a JSON-encodable result, and the file it wrote if any
+type jobOutcome struct {
+	Result   interface{}
+	FilePath string
+}
+
+// jobFunc does a job's work. It must return promptly once ctx is cancelled.
+type jobFunc func(ctx context.Context, report jobReporter) (*jobOutcome, error)
+
+// jobKinds prepare the work of each kind of job from its parameters; invalid parameters are
+// rejected by StartJob before a job is created. Preparing returns no work when the user cancelled a
+// dialog it opened.
+var jobKinds = map[string]func(a *App, params map[string]interface{}) (jobFunc, error){
+	types.JobRediscoverServices: (*App).rediscoverServicesJob,
+	types.JobExportUsageData:    (*App).exportUsageDataJob,
+	types.JobPruneWorkflowRuns:  (*App).pruneWorkflowRunsJob,
+}
+
+// jobRunner keeps the cancel functions of the jobs running in this process
+type jobRunner struct {
+	mu      sync.Mutex
+	cancels map[int64]context.CancelFunc
+}
+
+func newJobRunner() *jobRunner {
+	return &jobRunner{cancels: make(map[int64]context.CancelFunc)}
+}
+
+func (r *jobRunner) add(id int64, cancel context.CancelFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cancels[id] = cancel
+}
+
+func (r *jobRunner) remove(id int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.cancels, id)
+}
+
+func (r *jobRunner) cancel(id int64) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cancel, ok := r.cancels[id]
+	if ok {
+		cancel()
+	}
+	return ok
+}
+
+// cleanUpJobs fails the jobs a previous run of the app left running and deletes the ones
+// acknowledged long ago
+func (a *App) cleanUpJobs() {
+	if n, err := a.jobModel.FailInterrupted(); err != nil {
+		log.Printf("Failed to mark interrupted jobs: %v", err)
+	} else if n > 0 {
+		log.Printf("Marked %d interrupted jobs as failed", n)
+	}
+	if _, err := a.jobModel.DeleteAcknowledgedBefore(time.Now().Add(-acknowledgedJobRetention)); err != nil {
+		log.Printf("Failed to delete acknowledged jobs: %v", err)
+	}
+}
+
+// StartJob starts a job of the given kind in the background and returns its ID, or 0 when the user
+// cancelled a dialog the job opened. Progress and the outcome are stored on the job and emitted as
+// job:progress and job:finished events.
+func (a *App) StartJob(kind string, params map[string]interface{}) (int64, error) {
+	if a.jobModel == nil {
+		return 0, fmt.Errorf("job model not initialized")
+	}
+	prepare, ok := jobKinds[kind]
+	if !ok {
+		return 0, fmt.Errorf("unknown job kind %q", kind)
+	}
+	if params == nil {
+		params = map[string]interface{}{}
+	}
+	run, err := prepare(a, params)
+	if err != nil || run == nil {
+		return 0, err
+	}
+	encoded, err := json.Marshal(params)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode job parameters: %w", err)
+	}
+
+	job := &types.Job{Kind: kind, Params: string(encoded)}
+	if err := a.jobModel.Create(job); err != nil {
+		return 0, err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	a.jobs.add(job.ID, cancel)
+	go a.runJob(ctx, cancel, job.ID, run)
+	return job.ID, nil
+}
+
+// runJob runs a job's work and records its outcome
+func (a *App) runJob(ctx context.Context, cancel context.CancelFunc, id int64, run jobFunc) {
+	defer cancel()
+	defer a.jobs.remove(id)
+
+	// Only changes of the percentage are stored and emitted, so jobs can report as often as they like
+	lastProgress := -1
+	report := func(progress int, message string) {
+		progress = max(0, min(progress, 100))
+		if progress == lastProgress {
+			return
+		}
+		lastProgress = progress
+		if err := a.jobModel.UpdateProgress(id, progress, message); err != nil {
+			log.Printf("Failed to update progress of job %d: %v", id, err)
+			return
+		}
+		a.emitJobEvent(jobProgressEventName, id)
+	}
+
+	outcome, err := run(ctx, report)
+	status, result, filePath, errMsg := types.JobSucceeded, "", "", ""
+	switch {
+	case err != nil && ctx.Err() != nil:
+		status, errMsg = types.JobCancelled, "cancelled"
+	case err != nil:
+		status, errMsg = types.JobFailed, err.Error()
+	case outcome != nil:
+		filePath = outcome.FilePath
+		if outcome.Result != nil {
+			encoded, err := json.Marshal(outcome.Result)
+			if err != nil {
+				status, errMsg = types.JobFailed, fmt.Sprintf("failed to encode job result: %v", err)
+			} else {
+				result = string(encoded)
+			}
+		}
+	}
+	if err := a.jobModel.Finish(id, status, result, filePath, errMsg); err != nil {
+		log.Printf("Failed to record outcome of job %d: %v", id, err)
+	}
+	log.Printf("Job %d %s", id, status)
+	a.emitJobEvent(jobFinishedEventName, id)
+}
+
+func (a *App) emitJobEvent(name string, id int64) {
+	if a.ctx == nil {
+		return
+	}
+	job, err := a.jobModel.GetByID(id)
+	if err != nil {
+		log.Printf("Failed to get job %d: %v", id, err)
+		return
+	}
+	runtime.EventsEmit(a.ctx, name, job)
+}
+
+// GetJob returns a job with its progress and outcome
+func (a *App) GetJob(id int64) (*types.Job, error) {
+	if a.jobModel == nil {
+		return nil, fmt.Errorf("job model not initialized")
+	}
+	return a.jobModel.GetByID(id)
+}
+
+// GetJobs returns the running jobs and the finished ones that haven't been acknowledged, newest first
+func (a *App) GetJobs() ([]*types.Job, error) {
+	if a.jobModel == nil {
+		return []*types.Job{}, nil
+	}
+	return a.jobModel.GetUnacknowledged()
+}
+
+// CancelJob asks a running job to stop. The job is marked cancelled once its work has stopped,
+// which is announced with a job:finished event.
+func (a *App) CancelJob(id int64) error {
+	if a.jobModel == nil {
+		return fmt.Errorf("job model not initialized")
+	}
+	if a.jobs.cancel(id) {
+		return nil
+	}
+	job, err := a.jobModel.GetByID(id)
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("job %d isn't running (%s)", id, job.Status)
+}
+
+// AcknowledgeJob dismisses a finished job so GetJobs no longer lists it
+func (a *App) AcknowledgeJob(id int64) error {
+	if a.jobModel == nil {
+		return fmt.Errorf("job model not initialized")
+	}
+	return a.jobModel.Acknowledge(id)
+}
+
+// jobParamString returns a string parameter, or an error when a required one is missing
+func jobParamString(params map[string]interface{}, name string, required bool) (string, error) {
+	value, ok := params[name]
+	if !ok || value == nil {
+		if required {
+			return "", fmt.Errorf("parameter %q is required", name)
+		}
+		return "", nil
+	}
+	s, ok := value.(string)
+	if !ok || (required && s == "") {
+		return "", fmt.Errorf("parameter %q must be a non-empty string", name)
+	}
+	return s, nil
+}
+
+// jobParamID returns an ID parameter, or 0 when it's missing. Numbers arrive from the frontend as
+// float64.
+func jobParamID(params map[string]interface{}, name string) (int64, error) {
+	value, ok := params[name]
+	if !ok || value == nil {
+		return 0, nil
+	}
+	number, ok := value.(float64)
+	if !ok || number < 0 || number != float64(int64(number)) {
+		return 0, fmt.Errorf("parameter %q must be an ID", name)
+	}
+	return int64(number), nil
+}
+
+// rediscoverServicesResult is the result of a rediscover_services job
+type rediscoverServicesResult struct {
+	Repositories int                         `json:"repositories"`
+	Services     int                         `json:"services"`
+	Failed       []string                    `json:"failed"` // "<repository>: <error>"
+	Changes      []*types.ServiceRediscovery `json:"changes"`
+}
+
+// rediscoverServicesJob rediscovers the services of every monorepo with the configured GitHub
+// token, or of the one given by repository_id. A repository failing doesn't stop the others; the
+// job only fails when all of them did.
+func (a *App) rediscoverServicesJob(params map[string]interface{}) (jobFunc, error) {
+	if a.repoModel == nil || a.serviceModel == nil {
+		return nil, fmt.Errorf("repository model not initialized")
+	}
+	repositoryID, err := jobParamID(params, "repository_id")
+	if err != nil {
+		return nil, err
+	}
+
+	var repos []*types.Repository
+	if repositoryID != 0 {
+		repo, err := a.repoModel.GetByID(repositoryID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get repository: %w", err)
+		}
+		if repo.Type != types.MonorepoType {
+			return nil, fmt.Errorf("repository is not a monorepo")
+		}
+		repos = append(repos, repo)
+	} else if repos, err = a.rediscoverableMonorepos(); err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context, report jobReporter) (*jobOutcome, error) {
+		changes, err := a.rediscoverMonorepos(ctx, repos, report)
+		if err != nil {
+			return nil, err
+		}
+		result := &rediscoverServicesResult{Failed: []string{}, Changes: changes}
+		for _, change := range changes {
+			if change.Error != "" {
+				result.Failed = append(result.Failed, fmt.Sprintf("%s: %s", change.RepositoryName, change.Error))
+				continue
+			}
+			result.Repositories++
+			result.Services += change.Services
+		}
+		if len(repos) > 0 && result.Repositories == 0 {
+			return nil, fmt.Errorf("rediscovery failed for every repository, e.g. %s", result.Failed[0])
+		}
+		return &jobOutcome{Result: result}, nil
+	}, nil
+}
+
+// exportUsageDataResult is the result of an export_usage_data job
+type exportUsageDataResult struct {
+	Events int `json:"events"`
+}
+
+// exportUsageDataJob writes every recorded usage event as a JSON array to the file given by path,
+// the same data ExportUsageData returns. An empty path opens a save dialog. A failed or cancelled
+// export removes the partial file.
+func (a *App) exportUsageDataJob(params map[string]interface{}) (jobFunc, error) {
+	if a.usageEventModel == nil {
+		return nil, fmt.Errorf("usage event model not initialized")
+	}
+	path, err := jobParamString(params, "path", false)
+	if err != nil {
+		return nil, err
+	}
+	if path == "" {
+		path, err = runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+			Title:           "Export usage data",
+			DefaultFilename: fmt.Sprintf("usage-data-%s.json", time.Now().Format("2006-01-02")),
+			Filters:         []runtime.FileFilter{{DisplayName: "JSON (*.json)", Pattern: "*.json"}},
+		})
+		if err != nil || path == "" {
+			return nil, err
+		}
+		params["path"] = path
+	}
+
+	return func(ctx context.Context, report jobReporter) (outcome *jobOutcome, err error) {
+		report(0, "Reading usage events")
+		events, err := a.usageEventModel.GetSince(time.Time{})
+		if err != nil {
+			return nil, err
+		}
+
+		file, err := os.Create(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %s: %w", path, err)
+		}
+		defer func() {
+			if closeErr := file.Close(); err == nil && closeErr != nil {
+				err = fmt.Errorf("failed to write %s: %w", path, closeErr)
+			}
+			if err != nil {
+				os.Remove(path)
+			}
+		}()
+
+		if _, err := file.WriteString("["); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		for i, event := range events {
+			if i%usageExportBatch == 0 {
+				if err := ctx.Err(); err != nil {
+					return nil, err
+				}
+				report(i*100/len(events), fmt.Sprintf("Writing %d of %d events", i, len(events)))
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode usage event: %w", err)
+			}
+			separator := ",\n  "
+			if i == 0 {
+				separator = "\n  "
+			}
+			if _, err := file.WriteString(separator + string(data)); err != nil {
+				return nil, fmt.Errorf("failed to write %s: %w", path, err)
+			}
+		}
+		closing := "]\n"
+		if len(events) > 0 {
+			closing = "\n]\n"
+		}
+		if _, err := file.WriteString(closing); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return &jobOutcome{Result: exportUsageDataResult{Events: len(events)}, FilePath: path}, nil
+	}, nil
+}
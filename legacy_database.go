@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"dev-dashboard/internal/database"
+	"dev-dashboard/pkg/types"
+)
+
+// legacyDataDirs are the data directories earlier builds of the app kept their database in, under
+// the user's home directory
+var legacyDataDirs = []string{".gh-dashboard"}
+
+const (
+	// legacyDatabaseStage is the startup stage that waits for MigrateLegacyDatabase
+	legacyDatabaseStage = "legacy_database"
+	// Which database MigrateLegacyDatabase keeps when both hold data
+	keepLegacyDatabase  = "legacy"
+	keepCurrentDatabase = "current"
+)
+
+// findLegacyDatabase returns the database an earlier build left behind, or nil when there's none
+// or it was migrated already (its directory is renamed to .migrated then)
+func findLegacyDatabase() (*types.LegacyDatabase, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	currentPath, err := databasePath()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, dir := range legacyDataDirs {
+		path := filepath.Join(homeDir, dir, "database.db")
+		info, err := os.Stat(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		legacy := &types.LegacyDatabase{Path: path, SizeBytes: info.Size(), ModifiedAt: info.ModTime(), CurrentPath: currentPath}
+		if legacy.HasData, err = database.HasData(path); err != nil {
+			return nil, err
+		}
+		if legacy.CurrentHasData, err = database.HasData(currentPath); err != nil {
+			return nil, err
+		}
+		return legacy, nil
+	}
+	return nil, nil
+}
+
+// GetLegacyDatabase returns the database an earlier build of the app left under its old data
+// directory, or nil when there's none. While there is one, startup waits for MigrateLegacyDatabase
+// instead of opening the current database.
+func (a *App) GetLegacyDatabase() (*types.LegacyDatabase, error) {
+	return findLegacyDatabase()
+}
+
+// MigrateLegacyDatabase moves the database of an earlier build into the current data directory,
+// runs the migrations against it and renames the old directory to .migrated, then finishes
+// starting up. When both databases hold data, keep must say which one to use: "legacy" sets the
+// current database aside next to it, "current" leaves the old one in the renamed directory. They're
+// never merged.
+func (a *App) MigrateLegacyDatabase(keep string) error {
+	if a.db != nil || a.startupError == nil || a.startupError.Stage != legacyDatabaseStage {
+		return fmt.Errorf("no legacy database is waiting to be migrated")
+	}
+	legacy, err := findLegacyDatabase()
+	if err != nil {
+		return fmt.Errorf("failed to look for the legacy database: %w", err)
+	}
+	if legacy == nil {
+		return fmt.Errorf("no legacy database found")
+	}
+
+	switch keep {
+	case "":
+		if legacy.CurrentHasData {
+			return fmt.Errorf("both %s and %s hold data, choose which to keep", legacy.Path, legacy.CurrentPath)
+		}
+		keep = keepLegacyDatabase
+	case keepLegacyDatabase, keepCurrentDatabase:
+	default:
+		return fmt.Errorf("unknown database to keep %q, use %s or %s", keep, keepLegacyDatabase, keepCurrentDatabase)
+	}
+
+	legacyDir := filepath.Dir(legacy.Path)
+	migratedDir := legacyDir + ".migrated"
+	if _, err := os.Stat(migratedDir); err == nil {
+		return fmt.Errorf("%s already exists, move it away first", migratedDir)
+	}
+
+	if keep == keepLegacyDatabase {
+		if _, err := os.Stat(legacy.CurrentPath); err == nil {
+			replaced := fmt.Sprintf("%s.replaced-%s", legacy.CurrentPath, time.Now().Format("20060102-150405"))
+			if err := os.Rename(legacy.CurrentPath, replaced); err != nil {
+				return fmt.Errorf("failed to set the current database aside: %w", err)
+			}
+			// Its journals belong to the file set aside
+			for _, suffix := range []string{"-journal", "-wal", "-shm"} {
+				os.Remove(legacy.CurrentPath + suffix)
+			}
+			log.Printf("Set the current database aside as %s", replaced)
+		}
+		if err := database.CopyDatabase(legacy.Path, legacy.CurrentPath); err != nil {
+			return err
+		}
+		log.Printf("Copied the legacy database %s to %s", legacy.Path, legacy.CurrentPath)
+	}
+
+	if err := os.Rename(legacyDir, migratedDir); err != nil {
+		return fmt.Errorf("failed to rename %s: %w", legacyDir, err)
+	}
+	log.Printf("Renamed the legacy data directory to %s", migratedDir)
+
+	// Startup stopped before opening a database; opening it now runs the migrations
+	a.startupError = nil
+	a.startup(a.ctx)
+	if a.startupError != nil {
+		return fmt.Errorf("failed to open the migrated database: %s", a.startupError.Message)
+	}
+	return nil
+}
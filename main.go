@@ -2,6 +2,10 @@ package main
 
 import (
 	"embed"
+	"fmt"
+	"os"
+
+	"dev-dashboard/internal/database"
 
 	"github.com/wailsapp/wails/v2"
 	"github.com/wailsapp/wails/v2/pkg/options"
@@ -12,6 +16,12 @@ import (
 var assets embed.FS
 
 func main() {
+	for _, arg := range os.Args[1:] {
+		if arg == "--migrate-dry-run" || arg == "-migrate-dry-run" {
+			os.Exit(migrateDryRun())
+		}
+	}
+
 	// Create an instance of the app structure
 	app := NewApp()
 
@@ -46,3 +56,33 @@ func main() {
 		println("Error:", err.Error())
 	}
 }
+
+// migrateDryRun prints the migrations pending against the local database without applying them
+func migrateDryRun() int {
+	dbPath, err := databasePath()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to resolve database path: %v\n", err)
+		return 1
+	}
+
+	plans, err := database.PendingMigrations(dbPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to check migrations for %s: %v\n", dbPath, err)
+		return 1
+	}
+
+	if len(plans) == 0 {
+		fmt.Printf("No pending migrations for %s\n", dbPath)
+		return 0
+	}
+
+	fmt.Printf("%d pending migration(s) for %s:\n", len(plans), dbPath)
+	for _, plan := range plans {
+		if plan.Destructive {
+			fmt.Printf("  - %s (destructive, database will be backed up first)\n", plan.Name)
+		} else {
+			fmt.Printf("  - %s\n", plan.Name)
+		}
+	}
+	return 0
+}
@@ -9,27 +9,196 @@ const (
 	KubernetesType  RepositoryType = "kubernetes"
 )
 
+// RepositoryStatus tells the sync scheduler whether to sync a repository
+type RepositoryStatus string
+
+const (
+	RepositoryActive      RepositoryStatus = "active"
+	RepositoryUnreachable RepositoryStatus = "unreachable" // GitHub kept answering 404
+	RepositoryArchived    RepositoryStatus = "archived"
+)
+
+// RepositoryAccess is what GitHub answered the last time a sync looked the repository up
+type RepositoryAccess string
+
+const (
+	AccessOK           RepositoryAccess = "ok"
+	AccessForbidden    RepositoryAccess = "forbidden"     // 403 for lack of permission; rate limits don't count
+	AccessNotFound     RepositoryAccess = "not_found"     // 404: deleted, or invisible to the token
+	AccessTokenMissing RepositoryAccess = "token_missing" // no token configured, or GitHub rejected it with 401
+)
+
 type Repository struct {
-	ID              int64          `json:"id" db:"id"`
-	Name            string         `json:"name" db:"name"`
-	URL             string         `json:"url" db:"url"`
-	Type            RepositoryType `json:"type" db:"type"`
-	Description     string         `json:"description" db:"description"`
-	ServiceName     string         `json:"service_name,omitempty" db:"service_name"`
-	ServiceLocation string         `json:"service_location,omitempty" db:"service_location"`
-	CreatedAt       time.Time      `json:"created_at" db:"created_at"`
-	UpdatedAt       time.Time      `json:"updated_at" db:"updated_at"`
-	LastSyncAt      *time.Time     `json:"last_sync_at" db:"last_sync_at"`
+	ID              int64            `json:"id" db:"id"`
+	Name            string           `json:"name" db:"name"`
+	URL             string           `json:"url" db:"url"`
+	Type            RepositoryType   `json:"type" db:"type"`
+	Description     string           `json:"description" db:"description"`
+	ServiceName     string           `json:"service_name,omitempty" db:"service_name"`
+	ServiceLocation string           `json:"service_location,omitempty" db:"service_location"`
+	DiscoveryScript string           `json:"discovery_script,omitempty" db:"discovery_script"`
+	Status          RepositoryStatus `json:"status" db:"status"`
+	CreatedAt       time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time        `json:"updated_at" db:"updated_at"`
+	LastSyncAt      *time.Time       `json:"last_sync_at" db:"last_sync_at"`
+	LastSyncError   string           `json:"last_sync_error,omitempty" db:"last_sync_error"` // why the last connectivity check failed, empty when it passed
+	AccessState     RepositoryAccess `json:"access_state" db:"access_state"`
+	AccessStatus    int              `json:"access_status,omitempty" db:"access_status"` // HTTP status of the last lookup
+	AccessCheckedAt *time.Time       `json:"access_checked_at,omitempty" db:"access_checked_at"`
+	AccessFailures  int              `json:"access_failures" db:"access_failures"`           // forbidden lookups in a row
+	AccessRetryAt   *time.Time       `json:"access_retry_at,omitempty" db:"access_retry_at"` // scheduled syncs skip the repository until then
+	SyncState       *SyncState       `json:"sync_state,omitempty" db:"sync_state"`           // checkpoint of a pass in progress or interrupted
+	ManualSyncOnly  bool             `json:"manual_sync_only" db:"manual_sync_only"`         // scheduled syncs skip the repository; explicit syncs still run
+	DiscoveryReview bool             `json:"discovery_review" db:"discovery_review"`         // discovered service changes wait for review
+	IsFavorite      bool             `json:"is_favorite" db:"is_favorite"`                   // listed first and in the quick access list
+	CollectPackages bool             `json:"collect_packages" db:"collect_packages"`         // sync reads services' go.mod and package.json
+	DefaultBranch   string           `json:"default_branch,omitempty" db:"default_branch"` // as of the last sync, empty before the first
+	WorkflowBranches string          `json:"workflow_branches" db:"workflow_branches"`      // branch patterns whose workflow runs are synced, empty for the default
+	SecurityAlerts  *SecurityAlertCounts `json:"security_alerts,omitempty" db:"-"`            // open alert counts as of the last sync; nil before the first
+}
+
+// SyncPhase is a step of a repository sync: services and runs for monorepos, deployments,
+// resources and runs for kubernetes repositories
+type SyncPhase string
+
+const (
+	SyncPhaseServices    SyncPhase = "services"
+	SyncPhaseRuns        SyncPhase = "runs"
+	SyncPhaseDeployments SyncPhase = "deployments"
+	SyncPhaseResources   SyncPhase = "resources"
+	SyncPhaseSecurity    SyncPhase = "security_alerts"
+	SyncPhasePackages    SyncPhase = "packages"
+)
+
+// SyncOutcome is how a sync requested from the UI went when it didn't fail
+type SyncOutcome string
+
+const (
+	SyncOutcomeCompleted SyncOutcome = "completed"
+	// SyncOutcomeAlreadyRunning means the repository was already syncing, so nothing new was started
+	SyncOutcomeAlreadyRunning SyncOutcome = "already_running"
+)
+
+// SyncState is the checkpoint of a repository sync pass, stored as JSON in repositories.sync_state
+// while the pass runs. A pass interrupted by quitting the app leaves it behind to resume from.
+type SyncState struct {
+	StartedAt time.Time   `json:"started_at"`
+	Phase     SyncPhase   `json:"phase"` // phase running when the checkpoint was written
+	Completed []SyncPhase `json:"completed"`
+	Failed    []SyncPhase `json:"failed,omitempty"`
+}
+
+// Done reports whether the phase completed in this pass
+func (s *SyncState) Done(phase SyncPhase) bool {
+	for _, completed := range s.Completed {
+		if completed == phase {
+			return true
+		}
+	}
+	return false
 }
 
 type Microservice struct {
-	ID           int64     `json:"id" db:"id"`
-	RepositoryID int64     `json:"repository_id" db:"repository_id"`
-	Name         string    `json:"name" db:"name"`
-	Path         string    `json:"path" db:"path"`
-	Description  string    `json:"description" db:"description"`
-	CreatedAt    time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+	ID                 int64             `json:"id" db:"id"`
+	RepositoryID       int64             `json:"repository_id" db:"repository_id"`
+	Name               string            `json:"name" db:"name"`
+	Path               string            `json:"path" db:"path"`
+	Description        string            `json:"description" db:"description"`
+	Domain             string            `json:"domain" db:"domain"` // folder between the discovery root and the service, empty directly under the root
+	IsHidden           bool              `json:"is_hidden" db:"is_hidden"`
+	PrimaryEnvironment string            `json:"primary_environment" db:"primary_environment"` // overrides the primary_environment config key when set
+	Owner              string            `json:"owner" db:"owner"`
+	HasReadme          *bool             `json:"has_readme" db:"has_readme"` // nil until discovery has listed the service directory
+	ImageName          string            `json:"image_name" db:"image_name"` // image its kustomizations deploy; empty matches images by service name
+	SuggestedImageName string            `json:"suggested_image_name" db:"suggested_image_name"` // image the deployment scan found when none matched
+	CustomFields       map[string]string `json:"custom_fields,omitempty"`    // custom field values by field name
+	CreatedAt          time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time         `json:"updated_at" db:"updated_at"`
+}
+
+// MicroserviceWithRepository is a service with the name, URL and type of the repository it's in, so
+// lists of services across repositories don't have to look the repositories up
+type MicroserviceWithRepository struct {
+	Microservice
+	RepositoryName string `json:"repository_name"`
+	RepositoryURL  string `json:"repository_url"`
+	RepositoryType string `json:"repository_type"`
+}
+
+// ServiceChanges counts the services a discovery added, updated (description or domain) and removed
+type ServiceChanges struct {
+	Added   int `json:"added"`
+	Updated int `json:"updated"`
+	Removed int `json:"removed"`
+}
+
+// Changed reports whether the discovery changed any service
+func (c *ServiceChanges) Changed() bool {
+	return c.Added > 0 || c.Updated > 0 || c.Removed > 0
+}
+
+// ServiceRediscovery is the outcome of rediscovering the services of one monorepo. Error is set,
+// and the counts are zero, when its discovery failed.
+type ServiceRediscovery struct {
+	RepositoryID   int64  `json:"repository_id"`
+	RepositoryName string `json:"repository_name"`
+	Services       int    `json:"services"` // services discovered
+	Added          int    `json:"added"`
+	Updated        int    `json:"updated"`
+	Removed        int    `json:"removed"`
+	Error          string `json:"error,omitempty"`
+}
+
+// Custom field types, and the entity types custom fields are defined for
+const (
+	CustomFieldText = "text"
+	CustomFieldEnum = "enum"
+	CustomFieldBool = "bool"
+
+	CustomFieldEntityService = "service"
+)
+
+// CustomFieldDefinition is a user-defined field of an entity type, e.g. a "tier" enum on services
+type CustomFieldDefinition struct {
+	ID            int64     `json:"id" db:"id"`
+	EntityType    string    `json:"entity_type" db:"entity_type"`
+	Name          string    `json:"name" db:"name"`
+	Type          string    `json:"type" db:"type"`
+	AllowedValues []string  `json:"allowed_values" db:"allowed_values"` // enum fields only
+	ValueCount    int       `json:"value_count"`                        // entities with a value, deleted along with the field
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// CustomFieldFilter matches entities whose field Field (by name) is Value; bool fields use "true"
+// and "false". An empty Value matches entities without a value.
+type CustomFieldFilter struct {
+	Field string `json:"field"`
+	Value string `json:"value"`
+}
+
+// ValidationResult tells whether a repository can be accessed with the given credentials;
+// Error explains why not
+type ValidationResult struct {
+	Success bool   `json:"success"`
+	Error   string `json:"error"`
+}
+
+// DiscoveredService is a service found while adding a repository, before it is stored
+type DiscoveredService struct {
+	Name        string `json:"name"`
+	Path        string `json:"path"`
+	Description string `json:"description"`
+	Domain      string `json:"domain"`
+}
+
+// UngroupedDomain is the domain group of services directly under the discovery root
+const UngroupedDomain = "ungrouped"
+
+// ServiceDomainGroup is the services of one domain folder
+type ServiceDomainGroup struct {
+	Domain   string          `json:"domain"`
+	Services []*Microservice `json:"services"`
 }
 
 type KubernetesResource struct {
@@ -57,6 +226,7 @@ type Action struct {
 	ResourceID    *int64     `json:"resource_id" db:"resource_id"`
 	Type          ActionType `json:"type" db:"type"`
 	Status        string     `json:"status" db:"status"`
+	Conclusion    string     `json:"conclusion" db:"conclusion"`
 	WorkflowRunID int64      `json:"workflow_run_id" db:"workflow_run_id"`
 	Commit        string     `json:"commit" db:"commit_sha"`
 	Branch        string     `json:"branch" db:"branch"`
@@ -65,6 +235,9 @@ type Action struct {
 	CompletedAt   *time.Time `json:"completed_at" db:"completed_at"`
 	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
+	// Relative times in the configured time zone (e.g. "3h ago"), filled in by the API
+	StartedAtRelative   string `json:"started_at_relative,omitempty"`
+	CompletedAtRelative string `json:"completed_at_relative,omitempty"`
 }
 
 type ActionWithDetails struct {
@@ -73,6 +246,59 @@ type ActionWithDetails struct {
 	ResourceName *string `json:"resource_name,omitempty"`
 }
 
+// PendingApproval is a workflow run deployment waiting on required reviewers for an environment
+type PendingApproval struct {
+	ID              int64     `json:"id" db:"id"`
+	RepositoryID    int64     `json:"repository_id" db:"repository_id"`
+	WorkflowRunID   int64     `json:"workflow_run_id" db:"workflow_run_id"`
+	EnvironmentID   int64     `json:"environment_id" db:"environment_id"`
+	EnvironmentName string    `json:"environment_name" db:"environment_name"`
+	Reviewers       []string  `json:"reviewers" db:"reviewers"`
+	WaitingSince    time.Time `json:"waiting_since" db:"waiting_since"`
+	CanApprove      bool      `json:"can_approve" db:"can_approve"`
+	ActionID        *int64    `json:"action_id,omitempty"`
+	RepositoryName  string    `json:"repository_name,omitempty"`
+	ServiceName     *string   `json:"service_name,omitempty"`
+	CommitSHA       string    `json:"commit_sha,omitempty"`
+	Branch          string    `json:"branch,omitempty"`
+}
+
+// ActionsRunUsage is the recorded timing of a single completed workflow run
+type ActionsRunUsage struct {
+	RepositoryID  int64     `json:"repository_id" db:"repository_id"`
+	WorkflowID    int64     `json:"workflow_id" db:"workflow_id"`
+	WorkflowName  string    `json:"workflow_name" db:"workflow_name"`
+	WorkflowRunID int64     `json:"workflow_run_id" db:"workflow_run_id"`
+	RunStartedAt  time.Time `json:"run_started_at" db:"run_started_at"`
+	RunDurationMS int64     `json:"run_duration_ms" db:"run_duration_ms"`
+	UbuntuMS      int64     `json:"ubuntu_ms" db:"ubuntu_ms"`
+	MacOSMS       int64     `json:"macos_ms" db:"macos_ms"`
+	WindowsMS     int64     `json:"windows_ms" db:"windows_ms"`
+	OtherMS       int64     `json:"other_ms" db:"other_ms"`
+}
+
+// ActionsUsageBreakdown aggregates billable minutes for a repository or workflow.
+// WeightedMinutes applies GitHub's runner multipliers (Windows x2, macOS x10).
+type ActionsUsageBreakdown struct {
+	RepositoryID    int64   `json:"repository_id"`
+	RepositoryName  string  `json:"repository_name"`
+	WorkflowName    string  `json:"workflow_name,omitempty"`
+	Runs            int     `json:"runs"`
+	BillableMinutes float64 `json:"billable_minutes"`
+	WeightedMinutes float64 `json:"weighted_minutes"`
+}
+
+// ActionsUsageSummary is the Actions minutes usage over a window of days
+type ActionsUsageSummary struct {
+	Days            int                     `json:"days"`
+	Since           time.Time               `json:"since"`
+	Runs            int                     `json:"runs"`
+	BillableMinutes float64                 `json:"billable_minutes"`
+	WeightedMinutes float64                 `json:"weighted_minutes"`
+	Repositories    []ActionsUsageBreakdown `json:"repositories"`
+	TopWorkflows    []ActionsUsageBreakdown `json:"top_workflows"`
+}
+
 type TaskStatus string
 
 const (
@@ -81,6 +307,14 @@ const (
 	TaskCompleted  TaskStatus = "completed"
 )
 
+type TaskPriority string
+
+const (
+	TaskPriorityLow    TaskPriority = "low"
+	TaskPriorityMedium TaskPriority = "medium"
+	TaskPriorityHigh   TaskPriority = "high"
+)
+
 type Project struct {
 	ID          int64     `json:"id" db:"id"`
 	Name        string    `json:"name" db:"name"`
@@ -94,13 +328,103 @@ type Task struct {
 	ProjectID     int64      `json:"project_id" db:"project_id"`
 	JiraTicketID  string     `json:"jira_ticket_id" db:"jira_ticket_id"`
 	JiraTitle     string     `json:"jira_title" db:"jira_title"`
+	JiraStatus    string     `json:"jira_status" db:"jira_status"`
+	JiraAssignee  string     `json:"jira_assignee" db:"jira_assignee"` // JIRA account ID (Cloud) or user name (Server)
 	Title         string     `json:"title" db:"title"`
 	Description   string     `json:"description" db:"description"`
 	ScheduledDate *time.Time `json:"scheduled_date" db:"scheduled_date"`
 	Deadline      *time.Time `json:"deadline" db:"deadline"`
 	Status        TaskStatus `json:"status" db:"status"`
+	Priority      TaskPriority `json:"priority" db:"priority"`
 	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
+
+	// Details of the JIRA ticket shown on the task's card, refreshed along with its status
+	JiraAssigneeName string     `json:"jira_assignee_name" db:"jira_assignee_name"`
+	JiraDueDate      *time.Time `json:"jira_due_date" db:"jira_due_date"`
+	JiraSprint       string     `json:"jira_sprint" db:"jira_sprint"`
+	JiraLabels       []string   `json:"jira_labels" db:"jira_labels"`
+	JiraParentKey    string     `json:"jira_parent_key" db:"jira_parent_key"` // parent or epic ticket key
+
+	// Checklist progress, e.g. 3 of 5 items done; ChecklistCompletion is 0 without items
+	ChecklistDone       int     `json:"checklist_done"`
+	ChecklistTotal      int     `json:"checklist_total"`
+	ChecklistCompletion float64 `json:"checklist_completion"`
+}
+
+// TaskDetail is a task with everything its detail view shows, gathered in one call
+type TaskDetail struct {
+	Task          *Task                `json:"task"`
+	ProjectName   string               `json:"project_name"`
+	JiraURL       string               `json:"jira_url,omitempty"`        // the ticket in JIRA; empty without a ticket or a configured JIRA
+	JiraParentURL string               `json:"jira_parent_url,omitempty"` // the parent or epic ticket in JIRA
+	Tags          []string             `json:"tags"`                      // the ticket's JIRA labels
+	Checklist     []*TaskChecklistItem `json:"checklist"`
+}
+
+// TaskChecklistItem is a step of a task that can be checked off
+type TaskChecklistItem struct {
+	ID        int64     `json:"id" db:"id"`
+	TaskID    int64     `json:"task_id" db:"task_id"`
+	Text      string    `json:"text" db:"text"`
+	Done      bool      `json:"done" db:"done"`
+	Position  int       `json:"position" db:"position"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// QuickCaptureResult is what a quick capture line was read as. Task is nil when the line was
+// ambiguous; Suggestions then say which tokens to replace before capturing again.
+type QuickCaptureResult struct {
+	Task         *Task                     `json:"task,omitempty"`
+	ProjectID    int64                     `json:"project_id,omitempty"`
+	ProjectName  string                    `json:"project_name,omitempty"`
+	JiraTicketID string                    `json:"jira_ticket_id"`
+	Title        string                    `json:"title"`
+	Deadline     *time.Time                `json:"deadline,omitempty"`
+	Suggestions  []*QuickCaptureSuggestion `json:"suggestions"`
+}
+
+// QuickCaptureSuggestion is an ambiguous part of a quick capture line and the tokens it could be
+// replaced with, e.g. @pay with @payments and @payroll
+type QuickCaptureSuggestion struct {
+	Field   string               `json:"field"` // project
+	Token   string               `json:"token"` // as typed; empty when the line left the field out
+	Message string               `json:"message"`
+	Options []QuickCaptureOption `json:"options"`
+}
+
+// QuickCaptureOption is a token that resolves a quick capture suggestion
+type QuickCaptureOption struct {
+	Label string `json:"label"`
+	Token string `json:"token"`
+}
+
+// JiraStatusHistory is the status history of a task's JIRA ticket
+type JiraStatusHistory struct {
+	TaskID       int64                   `json:"task_id"`
+	IssueKey     string                  `json:"issue_key"`
+	Status       string                  `json:"status"`
+	Created      time.Time               `json:"created"`
+	Transitions  []*JiraStatusTransition `json:"transitions"`    // oldest first
+	TimeInStatus []*JiraStatusDuration   `json:"time_in_status"` // in the order statuses were first entered
+	FetchedAt    time.Time               `json:"fetched_at"`     // when the changelog was read from JIRA
+}
+
+// JiraStatusTransition is a change of a ticket's status
+type JiraStatusTransition struct {
+	From       string    `json:"from"`
+	To         string    `json:"to"`
+	At         time.Time `json:"at"`
+	AtRelative string    `json:"at_relative,omitempty"`
+	Author     string    `json:"author"` // display name
+}
+
+// JiraStatusDuration is how long a ticket has spent in a status, summed over every time it was in it
+type JiraStatusDuration struct {
+	Status  string `json:"status"`
+	Seconds int64  `json:"seconds"`
+	Current bool   `json:"current"` // the ticket is in the status now, so the time still grows
 }
 
 type TaskWithProject struct {
@@ -116,13 +440,43 @@ type PullRequest struct {
 	Author    string    `json:"author"`
 	Branch    string    `json:"branch"`
 	CreatedAt time.Time `json:"created_at"`
+	// Sensitive is set on open pull requests that change files matching the service's or
+	// repository's sensitive path patterns; SensitiveFiles lists those files
+	Sensitive      bool     `json:"sensitive"`
+	SensitiveFiles []string `json:"sensitive_files,omitempty"`
 }
 
 type Commit struct {
-	Hash    string    `json:"hash"`
-	Message string    `json:"message"`
-	Author  string    `json:"author"`
-	Date    time.Time `json:"date"`
+	Hash     string    `json:"hash"`
+	Message  string    `json:"message"`
+	Author   string    `json:"author"`
+	Date     time.Time `json:"date"`
+	PRNumber int       `json:"pr_number,omitempty"` // pull request that introduced the commit, 0 if unknown
+	// DateRelative is Date relative to now in the configured time zone (e.g. "yesterday 14:02")
+	DateRelative string `json:"date_relative,omitempty"`
+	// Annotations are the notes attached to the commit, newest first
+	Annotations []*Annotation `json:"annotations,omitempty"`
+	// Tickets are the JIRA issues the message mentions
+	Tickets []CommitTicket `json:"tickets,omitempty"`
+}
+
+// CommitTicket is a JIRA issue a commit message mentions; URL is empty without a JIRA URL configured
+type CommitTicket struct {
+	Key string `json:"key"`
+	URL string `json:"url,omitempty"`
+}
+
+// TicketCommit is a commit whose message mentions a JIRA issue, as recorded when the commit was
+// loaded
+type TicketCommit struct {
+	RepositoryID   int64     `json:"repository_id"`
+	RepositoryName string    `json:"repository_name"`
+	Hash           string    `json:"hash"`
+	Message        string    `json:"message"`
+	Author         string    `json:"author"`
+	Date           time.Time `json:"date"`
+	URL            string    `json:"url,omitempty"`
+	DateRelative   string    `json:"date_relative,omitempty"`
 }
 
 type Deployment struct {
@@ -133,32 +487,1430 @@ type Deployment struct {
 	Environment       string    `json:"environment" db:"environment"`
 	Region            string    `json:"region" db:"region"`
 	Namespace         string    `json:"namespace" db:"namespace"`
-	Tag               string    `json:"tag" db:"tag"`
+	Tag               string    `json:"tag" db:"tag"` // desired tag, committed to the kubernetes repository
+	ImageRepository   string    `json:"image_repository,omitempty" db:"image_repository"` // e.g. 123456789012.dkr.ecr.us-east-1.amazonaws.com/payments
+	Registry          string    `json:"registry,omitempty" db:"registry"`                 // registry host of ImageRepository, docker.io for Docker Hub
+	// ActualTag is what the cluster runs, recorded manually for now; it is Tag until one is recorded
+	ActualTag         string    `json:"actual_tag" db:"actual_tag"`
+	Synced            bool      `json:"synced"` // ActualTag is Tag
 	Path              string    `json:"path" db:"path"`
 	DiscoveredAt      time.Time `json:"discovered_at" db:"discovered_at"`
 	UpdatedAt         time.Time `json:"updated_at" db:"updated_at"`
+	Version           *TagVersion `json:"version,omitempty"` // parsed from Tag, nil when it isn't semver
+	// CorrelationStatus tells how CommitSHA was found from Tag; empty for deployments recorded before it was tracked
+	CorrelationStatus string     `json:"correlation_status" db:"correlation_status"`
+	UncorrelatedSince *time.Time `json:"uncorrelated_since,omitempty" db:"uncorrelated_since"` // when the current tag was first left uncorrelated
+	// FreezeWindow is the freeze window the history entry Upsert recorded fell into; not stored
+	FreezeWindow *FreezeWindow `json:"-" db:"-"`
+	// FirstObservation is set when the history entry Upsert recorded is the service's first in the
+	// environment and region; not stored
+	FirstObservation bool `json:"-" db:"-"`
+	// Seeding is set for deployments recorded by the first scan of their kubernetes repository, which
+	// are already running rather than deployed now, so Upsert doesn't check them against freeze
+	// windows; not stored
+	Seeding bool `json:"-" db:"-"`
+}
+
+// Correlation statuses of a deployment's commit
+const (
+	// CorrelationCorrelated: the tag is a commit SHA or matched a monorepo commit
+	CorrelationCorrelated = "correlated"
+	// CorrelationAnnotated: the kustomization names the commit in one of the commit annotation keys
+	CorrelationAnnotated = "annotated"
+	// CorrelationUncorrelated: no monorepo commit matched the tag yet, so CommitSHA is the kubernetes
+	// repository's commit; sync tries again until the retry window passes
+	CorrelationUncorrelated = "uncorrelated"
+	// CorrelationAbandoned: the tag was still uncorrelated when the retry window passed
+	CorrelationAbandoned = "abandoned"
+)
+
+// TagVersion is a semantic version parsed from a deployment tag such as v1.42.3-rc.1+build.7
+type TagVersion struct {
+	Major      int    `json:"major"`
+	Minor      int    `json:"minor"`
+	Patch      int    `json:"patch"`
+	Prerelease string `json:"prerelease,omitempty"`
+	Build      string `json:"build,omitempty"`
+}
+
+// DeploymentDiffLine is one line of a kustomization file diff
+type DeploymentDiffLine struct {
+	Type          string `json:"type"` // context, add or remove
+	OldLine       int    `json:"old_line,omitempty"`
+	NewLine       int    `json:"new_line,omitempty"`
+	Content       string `json:"content"`
+	IsImageChange bool   `json:"is_image_change"`
+}
+
+// DeploymentFileDiff compares a deployment's kustomization file with its previous version
+type DeploymentFileDiff struct {
+	DeploymentID    int64                `json:"deployment_id"`
+	Path            string               `json:"path"`
+	PreviousPath    string               `json:"previous_path,omitempty"`
+	CommitSHA       string               `json:"commit_sha"`
+	CurrentBlobSHA  string               `json:"current_blob_sha"`
+	PreviousBlobSHA string               `json:"previous_blob_sha,omitempty"`
+	CurrentContent  string               `json:"current_content"`
+	PreviousContent string               `json:"previous_content"`
+	IsNewFile       bool                 `json:"is_new_file"`
+	UnifiedDiff     string               `json:"unified_diff"`
+	Lines           []DeploymentDiffLine `json:"lines"`
+}
+
+type DeploymentHistoryEntry struct {
+	ID               int64     `json:"id" db:"id"`
+	ServiceID        int64     `json:"service_id" db:"service_id"`
+	KubernetesRepoID int64     `json:"kubernetes_repo_id" db:"kubernetes_repo_id"`
+	CommitSHA        string    `json:"commit_sha" db:"commit_sha"`
+	Environment      string    `json:"environment" db:"environment"`
+	Region           string    `json:"region" db:"region"`
+	Namespace        string    `json:"namespace" db:"namespace"`
+	Tag              string    `json:"tag" db:"tag"`
+	ObservedAt       time.Time `json:"observed_at" db:"observed_at"`
+	FreezeWindowID   *int64    `json:"freeze_window_id,omitempty" db:"freeze_window_id"` // the freeze window the entry fell into when it was recorded
+	// Annotations are the notes attached to the entry, newest first
+	Annotations []*Annotation `json:"annotations,omitempty"`
+}
+
+// FreezeWindow is a change freeze for the environments matching EnvironmentPattern (a path.Match
+// pattern such as prd or prd-*). A one-off window runs from StartsAt to EndsAt; a recurring one
+// repeats its Recurrence, e.g. "fri 16:00-mon 09:00" or "daily 18:00-08:00" in local time, limited
+// to StartsAt and EndsAt when they're set.
+type FreezeWindow struct {
+	ID                 int64      `json:"id" db:"id"`
+	EnvironmentPattern string     `json:"environment_pattern" db:"environment_pattern"`
+	StartsAt           *time.Time `json:"starts_at,omitempty" db:"starts_at"`
+	EndsAt             *time.Time `json:"ends_at,omitempty" db:"ends_at"`
+	Recurrence         string     `json:"recurrence" db:"recurrence"`
+	Reason             string     `json:"reason" db:"reason"`
+	CreatedAt          time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// FreezeViolation is a deployment history entry recorded inside a freeze window
+type FreezeViolation struct {
+	Entry        *DeploymentHistoryEntry `json:"entry"`
+	ServiceName  string                  `json:"service_name"`
+	FreezeWindow *FreezeWindow           `json:"freeze_window"`
+}
+
+// Entity types annotations can be attached to
+const (
+	AnnotationDeployment = "deployment_history" // a deployment history entry, by ID
+	AnnotationCommit     = "commit"             // a commit, by full SHA
+	AnnotationAction     = "action"             // a build or deployment run, by ID
+)
+
+// Annotation is a free-form note attached to a deployment history entry, commit or action, e.g.
+// "rolled back because of elevated 5xx". Deployment history is append-only, so the IDs it
+// references stay valid across syncs.
+type Annotation struct {
+	ID                int64     `json:"id"`
+	EntityType        string    `json:"entity_type"`
+	EntityID          string    `json:"entity_id"`
+	Text              string    `json:"text"`
+	CreatedAt         time.Time `json:"created_at"`
+	CreatedAtRelative string    `json:"created_at_relative,omitempty"`
+	// Set by GetRecentAnnotations: the annotated service when it's known, and a short description
+	// of the annotated entity (e.g. "prd/eu-west-1 v1.4.2")
+	ServiceID   int64  `json:"service_id,omitempty"`
+	ServiceName string `json:"service_name,omitempty"`
+	Subject     string `json:"subject,omitempty"`
 }
 
 type DeploymentOverview struct {
+	ID                   int64     `json:"id"`
 	CommitSHA            string    `json:"commit_sha"`
 	Environment          string    `json:"environment"`
 	Region               string    `json:"region"`
 	Namespace            string    `json:"namespace"`
-	Tag                  string    `json:"tag"`
+	Tag                  string    `json:"tag"` // desired tag
+	ImageRepository      string    `json:"image_repository,omitempty"`
+	Registry             string    `json:"registry,omitempty"`
+	ActualTag            string    `json:"actual_tag"`
+	Synced               bool      `json:"synced"` // false while the cluster hasn't caught up with Tag
 	UpdatedAt            time.Time `json:"updated_at"`
+	UpdatedAtRelative    string    `json:"updated_at_relative,omitempty"` // e.g. "3h ago" in the configured time zone
 	KubernetesRepoName   string    `json:"kubernetes_repo_name"`
+	Checks               *CommitChecks `json:"checks,omitempty"` // of CommitSHA in the service repository, nil until looked up
+	ChecksNotGreen       bool      `json:"checks_not_green"` // the deployed commit's checks failed or hadn't finished
+	CommitDate           *time.Time `json:"commit_date,omitempty"` // of CommitSHA, nil when it couldn't be looked up
+	Staleness            string    `json:"staleness"` // how old CommitDate is, one of the Staleness values
+}
+
+// Staleness of a deployment by the age of the commit it runs, against the
+// deployment_stale_warn_days and deployment_stale_alert_days thresholds
+const (
+	StalenessFresh   = "fresh"
+	StalenessAging   = "aging"
+	StalenessStale   = "stale"
+	StalenessUnknown = "unknown" // the commit date isn't known
+)
+
+// CommitChecks rolls up the commit statuses and check runs of a commit. State is success, failure,
+// pending, or none when nothing reported.
+type CommitChecks struct {
+	SHA          string    `json:"sha"`
+	State        string    `json:"state"`
+	Total        int       `json:"total"`
+	Succeeded    int       `json:"succeeded"`
+	Failed       int       `json:"failed"`
+	Pending      int       `json:"pending"`
+	FailedChecks []string  `json:"failed_checks,omitempty"`
+	CheckedAt    time.Time `json:"checked_at"`
+}
+
+
+// ImageRegistryUsage lists the services whose deployments pull their image from a registry
+type ImageRegistryUsage struct {
+	Registry    string                  `json:"registry"`
+	Deployments int                     `json:"deployments"`
+	Services    []*RegistryServiceUsage `json:"services"`
+}
+
+// RegistryServiceUsage is a service's image repository in a registry and the environments it's
+// deployed to from there
+type RegistryServiceUsage struct {
+	ServiceID       int64    `json:"service_id"`
+	ServiceName     string   `json:"service_name"`
+	ImageRepository string   `json:"image_repository"`
+	Environments    []string `json:"environments"`
+	Deployments     int      `json:"deployments"`
 }
 
 type DeploymentStatus struct {
-	Environment  string    `json:"environment"`
-	Region       string    `json:"region"`
-	Namespace    string    `json:"namespace"`
-	Tag          string    `json:"tag"`
-	IsDeployed   bool      `json:"is_deployed"`
-	DeployedAt   time.Time `json:"deployed_at"`
+	Environment        string     `json:"environment"`
+	Region             string     `json:"region"`
+	Namespace          string     `json:"namespace"`
+	Tag                string     `json:"tag"`
+	IsDeployed         bool       `json:"is_deployed"`
+	DeployedAt         *time.Time `json:"deployed_at"` // nil when not deployed
+	DeployedAtRelative string     `json:"deployed_at_relative,omitempty"`
+	Staleness          string     `json:"staleness,omitempty"` // of the commit when deployed, empty otherwise
+}
+
+// How a DeploymentDrift was measured: by the semver of both tags, by the commits between both
+// deployed commits, or not at all
+const (
+	DriftByVersion = "version"
+	DriftByCommits = "commits"
+	DriftUnknown   = "unknown"
+)
+
+// DeploymentDrift compares what a service runs in an environment with the environment before it in
+// the promotion order (environment_order), e.g. prd with stg. Deltas are positive when Environment
+// is behind ComparedTo: the version component that differs first, or the commits it lacks.
+type DeploymentDrift struct {
+	Environment     string      `json:"environment"`
+	ComparedTo      string      `json:"compared_to"`
+	Tag             string      `json:"tag"`
+	ComparedTag     string      `json:"compared_tag"`
+	Version         *TagVersion `json:"version,omitempty"`
+	ComparedVersion *TagVersion `json:"compared_version,omitempty"`
+	Method          string      `json:"method"`
+	MajorDelta      int         `json:"major_delta"`
+	MinorDelta      int         `json:"minor_delta"`
+	PatchDelta      int         `json:"patch_delta"`
+	CommitsBehind   int         `json:"commits_behind"`
+	CommitsAhead    int         `json:"commits_ahead"`
+	Summary         string      `json:"summary"` // e.g. "prd is 2 minor versions behind stg"
+}
+
+// DeploymentBlame traces what a service runs in an environment and region back to the commit the
+// tag was correlated with and the pull request that introduced that commit. What can't be traced is
+// left empty, with the reason in CommitUnknown or PRUnknown.
+type DeploymentBlame struct {
+	ServiceID     int64      `json:"service_id"`
+	Environment   string     `json:"environment"`
+	Region        string     `json:"region"`
+	Namespace     string     `json:"namespace"`
+	Tag           string     `json:"tag"`
+	CommitSHA     string     `json:"commit_sha"`
+	CommitMessage string     `json:"commit_message"` // first line
+	CommitAuthor  string     `json:"commit_author"`
+	CommittedAt   *time.Time `json:"committed_at,omitempty"`
+	CommitURL     string     `json:"commit_url"`
+	CommitUnknown string     `json:"commit_unknown,omitempty"`
+	PRNumber      int        `json:"pr_number"`
+	PRTitle       string     `json:"pr_title"`
+	PRAuthor      string     `json:"pr_author"` // GitHub login
+	PRURL         string     `json:"pr_url"`
+	PRMergedAt    *time.Time `json:"pr_merged_at,omitempty"`
+	PRUnknown     string     `json:"pr_unknown,omitempty"`
+}
+
+// EnvVar is an environment variable a container of a service's Deployment sets. Secret variables
+// come from a Secret or are named like credentials; their value and reference are never stored,
+// only a digest to tell that they changed.
+type EnvVar struct {
+	Container string `json:"container"`
+	Name      string `json:"name"`
+	Value     string `json:"value,omitempty"`
+	ValueFrom string `json:"value_from,omitempty"` // e.g. "configMapKeyRef app-config/LOG_LEVEL"
+	Secret    bool   `json:"secret"`
+	Digest    string `json:"digest,omitempty"`
+}
+
+// EnvVarSnapshot is the environment of a service's Deployment in one environment and region, as
+// its overlay's manifests set it
+type EnvVarSnapshot struct {
+	ServiceID   int64     `json:"service_id"`
+	Environment string    `json:"environment"`
+	Region      string    `json:"region"`
+	Path        string    `json:"path"`        // kustomization the variables were read from
+	Fingerprint string    `json:"fingerprint"` // changes with any manifest the overlay reads
+	Variables   []EnvVar  `json:"variables"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// EnvVarChange is a variable that differs between two environments
+type EnvVarChange struct {
+	Container string `json:"container"`
+	Name      string `json:"name"`
+	Change    string `json:"change"` // "added", "removed" or "changed", going from A to B
+	Secret    bool   `json:"secret"` // values are left empty
+	ValueA    string `json:"value_a,omitempty"`
+	ValueB    string `json:"value_b,omitempty"`
+}
+
+// EnvVarDiff compares the environment variables of a service in two environments
+type EnvVarDiff struct {
+	ServiceID    int64          `json:"service_id"`
+	EnvironmentA string         `json:"environment_a"`
+	RegionA      string         `json:"region_a"`
+	EnvironmentB string         `json:"environment_b"`
+	RegionB      string         `json:"region_b"`
+	Changes      []EnvVarChange `json:"changes"`
+	Unchanged    int            `json:"unchanged"`
+}
+
+// DeploymentRollup is a service's deployments in one environment and region, shown as one row
+// when all namespaces run the same tag. Tag is the tag most namespaces run; DivergentNamespaces
+// lists the namespaces running something else, which usually means a partial rollout.
+type DeploymentRollup struct {
+	Environment         string                `json:"environment"`
+	Region              string                `json:"region"`
+	IsPrimary           bool                  `json:"is_primary"` // the service's primary (live) environment
+	Tag                 string                `json:"tag"`
+	CommitSHA           string                `json:"commit_sha"`
+	UpdatedAt           time.Time             `json:"updated_at"`
+	NamespaceCount      int                   `json:"namespace_count"`
+	Namespaces          []string              `json:"namespaces"`
+	Diverged            bool                  `json:"diverged"`
+	DivergentNamespaces []string              `json:"divergent_namespaces"`
+	PendingSync         bool                  `json:"pending_sync"` // a namespace's actual tag isn't its desired tag yet
+	Deployments         []*DeploymentOverview `json:"deployments"`
+}
+
+// DeploymentOverviewFilter narrows a service's deployments grouped by environment. Empty fields
+// don't filter; Offset and Limit page the deployments of each environment.
+type DeploymentOverviewFilter struct {
+	Environment string `json:"environment"`
+	Region      string `json:"region"`
+	Offset      int    `json:"offset"`
+	Limit       int    `json:"limit"` // deployments per environment, 0 for all
+}
+
+// EnvironmentDeployments is a page of a service's deployments in one environment
+type EnvironmentDeployments struct {
+	Environment string                `json:"environment"`
+	IsPrimary   bool                  `json:"is_primary"` // the service's primary (live) environment
+	Total       int                   `json:"total"`      // deployments in the environment matching the filter
+	Regions     []string              `json:"regions"`    // of all matching deployments, not just the page
+	Deployments []*DeploymentOverview `json:"deployments"`
+}
+
+// RolloutProgress is how far the newest tag in an environment has rolled out across a service's
+// deployment targets (region and namespace), e.g. 7 of 12 namespaces on release-42
+type RolloutProgress struct {
+	ServiceID             int64              `json:"service_id"`
+	Environment           string             `json:"environment"`
+	Tag                   string             `json:"tag"`
+	CommitSHA             string             `json:"commit_sha"`
+	UpdatedTargets        int                `json:"updated_targets"`
+	TotalTargets          int                `json:"total_targets"`
+	Complete              bool               `json:"complete"`
+	StartedAt             *time.Time         `json:"started_at,omitempty"`
+	LastTransitionAt      *time.Time         `json:"last_transition_at,omitempty"`
+	EstimatedCompletionAt *time.Time         `json:"estimated_completion_at,omitempty"` // from the pace of recent transitions
+	Stuck                 bool               `json:"stuck"`                             // incomplete with no transition for the configured period
+	Stragglers            []RolloutStraggler `json:"stragglers"`
+}
+
+// RolloutStraggler is a deployment target still running an older tag during a rollout
+type RolloutStraggler struct {
+	Region    string     `json:"region"`
+	Namespace string     `json:"namespace"`
+	Tag       string     `json:"tag"`
+	Since     *time.Time `json:"since,omitempty"`
 }
 
 type CommitDeploymentStatus struct {
 	Commit        Commit             `json:"commit"`
 	Deployments   []DeploymentStatus `json:"deployments"`
-}
\ No newline at end of file
+}
+
+// ServiceDetailOptions selects the sections GetServiceDetail gathers; unrequested sections are skipped
+type ServiceDetailOptions struct {
+	PullRequests      bool `json:"pull_requests"`
+	Commits           bool `json:"commits"`
+	Deployments       bool `json:"deployments"`
+	CommitDeployments bool `json:"commit_deployments"`
+	Actions           bool `json:"actions"`
+	ActionsLimit      int  `json:"actions_limit"`
+	ForceRefresh      bool `json:"force_refresh"` // bypass cached GitHub data
+}
+
+// Report formats; service reports are markdown or json, environment comparisons markdown or csv
+const (
+	ReportMarkdown = "markdown"
+	ReportJSON     = "json"
+	ReportCSV      = "csv"
+)
+
+// ServiceReport is a self-contained snapshot of a service for handoffs and incident writeups.
+// Warnings name the sections that couldn't be loaded fresh.
+type ServiceReport struct {
+	GeneratedAt   time.Time             `json:"generated_at"`
+	GeneratedBy   string                `json:"generated_by"`
+	Service       *Microservice         `json:"service"`
+	Repository    string                `json:"repository"`
+	RepositoryURL string                `json:"repository_url"`
+	Commits       []*Commit             `json:"commits"`
+	PullRequests  []*PullRequest        `json:"open_pull_requests"`
+	Deployments   []*DeploymentOverview `json:"deployments"`
+	Actions       []*Action             `json:"actions"`
+	Annotations   []*Annotation         `json:"annotations,omitempty"` // on the service's deployments and reported commits
+	Warnings      []string              `json:"warnings,omitempty"`
+}
+
+// EnvironmentComparison lists the services whose deployments differ between a source environment
+// and the target it is promoted to (e.g. stg and prd), largest drift first
+type EnvironmentComparison struct {
+	GeneratedAt time.Time                   `json:"generated_at"`
+	GeneratedBy string                      `json:"generated_by"`
+	Source      string                      `json:"source"`
+	Target      string                      `json:"target"`
+	Services    []*EnvironmentComparisonRow `json:"services"`
+	Warnings    []string                    `json:"warnings,omitempty"`
+}
+
+// EnvironmentComparisonRow is one service of an EnvironmentComparison. Tags and deploy times are
+// empty for the environment the service isn't deployed to, and Drift is then nil.
+type EnvironmentComparisonRow struct {
+	ServiceID        int64            `json:"service_id"`
+	Service          string           `json:"service"`
+	Repository       string           `json:"repository"`
+	Owner            string           `json:"owner"`
+	SourceTag        string           `json:"source_tag"`
+	TargetTag        string           `json:"target_tag"`
+	SourceDeployedAt *time.Time       `json:"source_deployed_at,omitempty"`
+	TargetDeployedAt *time.Time       `json:"target_deployed_at,omitempty"`
+	Drift            *DeploymentDrift `json:"drift,omitempty"` // of the target compared to the source
+	// PromotionPullRequests are open pull requests in the kubernetes repository that change the
+	// target deployment's kustomization file
+	PromotionPullRequests []*PullRequest `json:"promotion_pull_requests"`
+}
+
+// RepositoryAccessReport groups the tracked repositories by what GitHub answered the last time
+// they were looked up
+type RepositoryAccessReport struct {
+	GeneratedAt     time.Time                `json:"generated_at"`
+	TokenConfigured bool                     `json:"token_configured"`
+	Counts          map[string]int           `json:"counts"`       // repositories by access state
+	Repositories    []*RepositoryAccessEntry `json:"repositories"` // repositories with access problems first
+}
+
+type RepositoryAccessEntry struct {
+	RepositoryID int64            `json:"repository_id"`
+	Name         string           `json:"name"`
+	URL          string           `json:"url"`
+	Status       RepositoryStatus `json:"status"`
+	State        RepositoryAccess `json:"state"`
+	HTTPStatus   int              `json:"http_status,omitempty"`
+	Reason       string           `json:"reason,omitempty"`
+	CheckedAt    *time.Time       `json:"checked_at,omitempty"`
+	RetryAt      *time.Time       `json:"retry_at,omitempty"`
+	LastSyncAt   *time.Time       `json:"last_sync_at,omitempty"` // last sync that completed
+}
+
+
+// RepositorySyncStatus is where the sync of a repository stands
+type RepositorySyncStatus struct {
+	RepositoryID    int64       `json:"repository_id"`
+	Name            string      `json:"name"`
+	Running         bool        `json:"running"`         // a pass is running now
+	Interrupted     bool        `json:"interrupted"`     // a pass was cut short, e.g. by quitting the app, and resumes with the next sync
+	Phase           SyncPhase   `json:"phase,omitempty"` // phase running, or running when the pass was interrupted
+	CompletedPhases []SyncPhase `json:"completed_phases"`
+	StartedAt       *time.Time  `json:"started_at,omitempty"`
+	LastSyncAt      *time.Time  `json:"last_sync_at,omitempty"` // last pass that completed every phase
+	LastSyncError   string      `json:"last_sync_error,omitempty"`
+}
+
+// SectionStatus describes how one section of a composite response was loaded.
+// Stale sections hold cached data because a fresh fetch failed or timed out.
+type SectionStatus struct {
+	Requested bool       `json:"requested"`
+	Stale     bool       `json:"stale"`
+	Error     string     `json:"error,omitempty"`
+	FetchedAt *time.Time `json:"fetched_at,omitempty"`
+}
+
+type PullRequestsSection struct {
+	Data   []*PullRequest `json:"data"`
+	Status SectionStatus  `json:"status"`
+}
+
+type CommitsSection struct {
+	Data   []*Commit     `json:"data"`
+	Status SectionStatus `json:"status"`
+}
+
+type DeploymentsSection struct {
+	Data   []*DeploymentOverview `json:"data"`
+	Status SectionStatus         `json:"status"`
+}
+
+type CommitDeploymentsSection struct {
+	Data   []*CommitDeploymentStatus `json:"data"`
+	Status SectionStatus             `json:"status"`
+}
+
+type ActionsSection struct {
+	Data   []*Action     `json:"data"`
+	Status SectionStatus `json:"status"`
+}
+
+// ServiceDetail is everything the service detail page shows, gathered in one call
+type ServiceDetail struct {
+	Service           *Microservice            `json:"service"`
+	Repository        *Repository              `json:"repository"`
+	PullRequests      PullRequestsSection      `json:"pull_requests"`
+	Commits           CommitsSection           `json:"commits"`
+	Deployments       DeploymentsSection       `json:"deployments"`
+	CommitDeployments CommitDeploymentsSection `json:"commit_deployments"`
+	Actions           ActionsSection           `json:"actions"`
+}
+
+// DeploymentScanFile is the outcome of scanning one kustomization file.
+// Outcome is "matched" when deployments were recorded for a service, otherwise "skipped" with a SkipReason.
+type DeploymentScanFile struct {
+	Path               string   `json:"path"`
+	Outcome            string   `json:"outcome"`
+	ServiceName        string   `json:"service_name,omitempty"`
+	Environment        string   `json:"environment,omitempty"`
+	Region             string   `json:"region,omitempty"`
+	Namespaces         []string `json:"namespaces,omitempty"`
+	Tag                string   `json:"tag,omitempty"`
+	// Source is the Flux field the tag was read from, empty for a kustomization's images list
+	Source             string   `json:"source,omitempty"`
+	// CommitAnnotation is the annotation or label key the kustomization names its source commit in,
+	// AnnotatedCommit that commit; both are empty when the tag is correlated instead
+	CommitAnnotation   string   `json:"commit_annotation,omitempty"`
+	AnnotatedCommit    string   `json:"annotated_commit,omitempty"`
+	MatchedServiceID   int64    `json:"matched_service_id,omitempty"`
+	MatchedServiceName string   `json:"matched_service_name,omitempty"`
+	SkipReason         string   `json:"skip_reason,omitempty"`
+	Detail             string   `json:"detail,omitempty"`
+	// Images lists the images of a kustomization none of which matched the service
+	Images             []string `json:"images,omitempty"`
+}
+
+// DeploymentScanDiagnostics explains what a kustomization scan of a kubernetes repository finds
+type DeploymentScanDiagnostics struct {
+	RepositoryID int64                `json:"repository_id"`
+	ScanPath     string               `json:"scan_path"`
+	FilesFound   int                  `json:"files_found"`
+	FilesMatched int                  `json:"files_matched"`
+	Files        []DeploymentScanFile `json:"files"`
+}
+
+// Where a deployment stands relative to a commit: running it, running a later commit that includes
+// it, running an earlier commit, or running a commit on another line of history
+const (
+	CommitPositionAt       = "at"
+	CommitPositionAhead    = "ahead"
+	CommitPositionBehind   = "behind"
+	CommitPositionDiverged = "diverged"
+	CommitPositionUnknown  = "unknown"
+)
+
+// CommitImpact is the release impact of a monorepo commit: the services whose paths it changed
+// and where each of them is deployed relative to it
+type CommitImpact struct {
+	RepositoryID int64                  `json:"repository_id"`
+	Commit       Commit                 `json:"commit"`
+	FilesChanged int                    `json:"files_changed"`
+	Services     []*ServiceCommitImpact `json:"services"`
+}
+
+// ServiceCommitImpact is one service changed by a commit
+type ServiceCommitImpact struct {
+	ServiceID   int64                     `json:"service_id"`
+	ServiceName string                    `json:"service_name"`
+	ServicePath string                    `json:"service_path"`
+	Files       []string                  `json:"files"`
+	Deployments []*CommitImpactDeployment `json:"deployments"`
+}
+
+// CommitImpactDeployment is a service deployment and its position relative to the commit.
+// AheadBy and BehindBy count the commits the deployed commit is ahead of and behind it.
+type CommitImpactDeployment struct {
+	Environment string    `json:"environment"`
+	Region      string    `json:"region"`
+	Namespace   string    `json:"namespace"`
+	Tag         string    `json:"tag"`
+	CommitSHA   string    `json:"commit_sha"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	Position    string    `json:"position"`
+	AheadBy     int       `json:"ahead_by"`
+	BehindBy    int       `json:"behind_by"`
+}
+
+// CommitDetail is what a commit changed inside a service's path. OtherFiles counts the files it
+// changed elsewhere in the repository. Truncated is set when patches were left out to keep a large
+// diff manageable; fetching with full set returns all of them.
+type CommitDetail struct {
+	ServiceID  int64             `json:"service_id"`
+	Commit     Commit            `json:"commit"`
+	Additions  int               `json:"additions"`
+	Deletions  int               `json:"deletions"`
+	Files      []*CommitFileDiff `json:"files"`
+	OtherFiles int               `json:"other_files"`
+	Truncated  bool              `json:"truncated"`
+}
+
+// CommitFileDiff is a file a commit changed. Patch is empty for binary files, files GitHub considers
+// too large to diff, and files whose patch was truncated (PatchTruncated).
+type CommitFileDiff struct {
+	Filename         string `json:"filename"`
+	PreviousFilename string `json:"previous_filename,omitempty"`
+	Status           string `json:"status"`
+	Additions        int    `json:"additions"`
+	Deletions        int    `json:"deletions"`
+	Patch            string `json:"patch"`
+	PatchTruncated   bool   `json:"patch_truncated,omitempty"`
+}
+
+// CommitLeadTime is the time a single commit took to reach production
+type CommitLeadTime struct {
+	Commit          Commit     `json:"commit"`
+	DeployedAt      *time.Time `json:"deployed_at"`
+	Environment     string     `json:"environment,omitempty"`
+	LeadTimeSeconds *int64     `json:"lead_time_seconds"`
+}
+
+// LeadTimeStats summarizes commit-to-production lead time for a service (DORA lead time for changes)
+type LeadTimeStats struct {
+	ServiceID             int64            `json:"service_id"`
+	Since                 time.Time        `json:"since"`
+	MedianLeadTimeSeconds *int64           `json:"median_lead_time_seconds"`
+	DeployedCount         int              `json:"deployed_count"`
+	OpenCount             int              `json:"open_count"`
+	Commits               []CommitLeadTime `json:"commits"`
+}
+
+// ActionReliability summarizes the outcomes of one type of action for a service.
+// SuccessRate and MeanTimeBetweenFailuresSeconds are nil when there isn't enough data.
+type ActionReliability struct {
+	Type                           ActionType `json:"type"`
+	Runs                           int        `json:"runs"` // runs counted in the success rate
+	Succeeded                      int        `json:"succeeded"`
+	Failed                         int        `json:"failed"`
+	Cancelled                      int        `json:"cancelled"`
+	SuccessRate                    *float64   `json:"success_rate"`
+	CurrentStreak                  int        `json:"current_streak"`
+	CurrentStreakOutcome           string     `json:"current_streak_outcome,omitempty"` // success or failure
+	MeanTimeBetweenFailuresSeconds *int64     `json:"mean_time_between_failures_seconds"`
+}
+
+// ReliabilityPoint is one day of a service's action outcomes; rates are nil for days without runs
+type ReliabilityPoint struct {
+	Date                  string   `json:"date"`
+	BuildRuns             int      `json:"build_runs"`
+	BuildSuccessRate      *float64 `json:"build_success_rate"`
+	DeploymentRuns        int      `json:"deployment_runs"`
+	DeploymentSuccessRate *float64 `json:"deployment_success_rate"`
+}
+
+// ServiceReliability is an SLO-style availability signal computed from a service's workflow history
+type ServiceReliability struct {
+	ServiceID        int64              `json:"service_id"`
+	Since            time.Time          `json:"since"`
+	ExcludeCancelled bool               `json:"exclude_cancelled"`
+	Build            ActionReliability  `json:"build"`
+	Deployment       ActionReliability  `json:"deployment"`
+	Daily            []ReliabilityPoint `json:"daily"`
+}
+
+// ScorecardStatus is the outcome of one scorecard check
+type ScorecardStatus string
+
+const (
+	ScorecardPass ScorecardStatus = "pass"
+	ScorecardFail ScorecardStatus = "fail"
+	// ScorecardUnknown means the local data needed for the check isn't available yet; it doesn't
+	// count towards the grade
+	ScorecardUnknown ScorecardStatus = "unknown"
+)
+
+// ScorecardCheck describes a scorecard check and how it is configured
+type ScorecardCheck struct {
+	ID               string  `json:"id"`
+	Name             string  `json:"name"`
+	Description      string  `json:"description"`
+	Enabled          bool    `json:"enabled"`
+	HasThreshold     bool    `json:"has_threshold"`
+	Threshold        float64 `json:"threshold"`
+	DefaultThreshold float64 `json:"default_threshold"`
+	ThresholdUnit    string  `json:"threshold_unit,omitempty"`
+}
+
+// ScorecardResult is the outcome of one check for a service
+type ScorecardResult struct {
+	CheckID string          `json:"check_id"`
+	Name    string          `json:"name"`
+	Status  ScorecardStatus `json:"status"`
+	Detail  string          `json:"detail"`
+}
+
+// ServiceScorecard is a service's hygiene checklist. Score is the share of known checks that
+// passed, nil when none are known; Grade is A to F, or "-" without a score.
+type ServiceScorecard struct {
+	ServiceID   int64             `json:"service_id"`
+	ServiceName string            `json:"service_name"`
+	Grade       string            `json:"grade"`
+	Score       *float64          `json:"score"`
+	EvaluatedAt time.Time         `json:"evaluated_at"`
+	Results     []ScorecardResult `json:"results"`
+}
+
+// ScorecardCheckSummary counts the outcomes of one check across services
+type ScorecardCheckSummary struct {
+	CheckID string `json:"check_id"`
+	Name    string `json:"name"`
+	Passed  int    `json:"passed"`
+	Failed  int    `json:"failed"`
+	Unknown int    `json:"unknown"`
+}
+
+// ScorecardSummary is the scorecards of all services, worst grade first
+type ScorecardSummary struct {
+	Scorecards  []ServiceScorecard      `json:"scorecards"`
+	GradeCounts map[string]int          `json:"grade_counts"`
+	Checks      []ScorecardCheckSummary `json:"checks"`
+}
+
+// Build matrix statuses of a service's latest default-branch build
+const (
+	BuildStatusSuccess   = "success"
+	BuildStatusFailure   = "failure"
+	BuildStatusCancelled = "cancelled"
+	BuildStatusRunning   = "running"
+	BuildStatusNoData    = "no_data"
+)
+
+// BuildMatrixEntry is the most recent build of a service on its repository's default branch.
+// Services without a matched build have status no_data and no run details.
+type BuildMatrixEntry struct {
+	ServiceID       int64      `json:"service_id"`
+	ServiceName     string     `json:"service_name"`
+	RepositoryID    int64      `json:"repository_id"`
+	RepositoryName  string     `json:"repository_name"`
+	Branch          string     `json:"branch"`
+	Status          string     `json:"status"`
+	Conclusion      string     `json:"conclusion"`
+	WorkflowRunID   int64      `json:"workflow_run_id,omitempty"`
+	Commit          string     `json:"commit,omitempty"`
+	StartedAt       *time.Time `json:"started_at,omitempty"`
+	CompletedAt     *time.Time `json:"completed_at,omitempty"`
+	DurationSeconds int64      `json:"duration_seconds,omitempty"`
+}
+
+// BuildMatrixRollup summarises a build matrix; Overall is red when any latest build failed,
+// green when at least one passed and none failed, and no_data otherwise
+type BuildMatrixRollup struct {
+	Overall   string `json:"overall"`
+	Total     int    `json:"total"`
+	Success   int    `json:"success"`
+	Failure   int    `json:"failure"`
+	Cancelled int    `json:"cancelled"`
+	Running   int    `json:"running"`
+	NoData    int    `json:"no_data"`
+}
+
+type BuildMatrix struct {
+	Entries []BuildMatrixEntry `json:"entries"`
+	Rollup  BuildMatrixRollup  `json:"rollup"`
+}
+
+// How the activity feed groups actions
+const (
+	ActivityGroupNone       = "none"
+	ActivityGroupRepository = "repo"
+	ActivityGroupService    = "service"
+	ActivityGroupType       = "type"
+)
+
+// ActivityGroup is a bucket of the activity feed, its actions newest first
+type ActivityGroup struct {
+	Key     string               `json:"key"`
+	Label   string               `json:"label"`
+	Actions []*ActionWithDetails `json:"actions"`
+}
+
+// ActivityFeed is the recent actions, either as one list newest first (Actions, when GroupBy is
+// none) or in groups ordered by their newest action
+type ActivityFeed struct {
+	GroupBy string               `json:"group_by"`
+	Actions []*ActionWithDetails `json:"actions"`
+	Groups  []*ActivityGroup     `json:"groups"`
+}
+
+// DashboardStats is the workspace overview shown on the dashboard
+type DashboardStats struct {
+	Repositories        int                  `json:"repositories"`
+	Microservices       int                  `json:"microservices"`
+	KubernetesResources int                  `json:"kubernetesResources"`
+	RecentActions       []*ActionWithDetails `json:"recentActions"`
+	BuildRollup         BuildMatrixRollup    `json:"buildRollup"`
+}
+
+// StatsTrendPoint is a single day in a stats trend; Value is nil for days without a snapshot
+type StatsTrendPoint struct {
+	Date  string `json:"date"`
+	Value *int64 `json:"value"`
+}
+
+type SyncLogLevel string
+
+const (
+	SyncLogInfo    SyncLogLevel = "info"
+	SyncLogWarning SyncLogLevel = "warning"
+	SyncLogError   SyncLogLevel = "error"
+)
+
+// SyncLog is a persisted log line produced while syncing a repository
+type SyncLog struct {
+	ID           int64        `json:"id" db:"id"`
+	RepositoryID *int64       `json:"repository_id" db:"repository_id"`
+	Level        SyncLogLevel `json:"level" db:"level"`
+	Message      string       `json:"message" db:"message"`
+	CreatedAt    time.Time    `json:"created_at" db:"created_at"`
+}
+
+// Notification is a user-facing alert raised by background work
+type Notification struct {
+	ID           int64      `json:"id" db:"id"`
+	RepositoryID *int64     `json:"repository_id" db:"repository_id"`
+	Type         string     `json:"type" db:"type"`
+	Title        string     `json:"title" db:"title"`
+	Message      string     `json:"message" db:"message"`
+	// Environment is set on notifications about one environment, for per-environment quiet hours
+	Environment  string     `json:"environment" db:"environment"`
+	IsRead       bool       `json:"is_read" db:"is_read"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+	// DeliverAt is when a notification queued during quiet hours is shown; nil when shown at once
+	DeliverAt    *time.Time `json:"deliver_at,omitempty" db:"deliver_at"`
+}
+
+// ServiceDeploymentCounts is the number of distinct deployment targets (region/namespace) a service
+// has in each environment. Environments lists the keys of Counts in display order.
+type ServiceDeploymentCounts struct {
+	ServiceID    int64          `json:"service_id"`
+	Counts       map[string]int `json:"counts"`
+	Environments []string       `json:"environments"`
+}
+
+// ServiceSummary is a service with what the services list shows about it, materialized in the
+// service_summaries table so the whole list is read at once
+type ServiceSummary struct {
+	Microservice
+	LastBuild        *Action                     `json:"last_build"`
+	LastDeployment   *Action                     `json:"last_deployment"`
+	Environments     []ServiceEnvironmentSummary `json:"environments"` // in display order
+	OpenPullRequests *int                        `json:"open_pull_requests"` // nil until the service's pull requests have been fetched
+	OpenTasks        *int                        `json:"open_tasks"`         // tasks not completed whose tickets its last fetched commits mention; nil until fetched
+	SummarizedAt     *time.Time                  `json:"summarized_at"`      // nil when the service hasn't been summarized yet
+}
+
+// ServiceEnvironmentSummary is a service's most recently updated deployment in an environment
+type ServiceEnvironmentSummary struct {
+	Environment string    `json:"environment"`
+	Region      string    `json:"region"`
+	Tag         string    `json:"tag"`
+	Targets     int       `json:"targets"` // deployment targets (region/namespace) in the environment
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// ServiceSummaryFilter narrows the services list. Empty fields don't filter.
+type ServiceSummaryFilter struct {
+	RepositoryID  int64               `json:"repository_id"` // 0 for the services of all monorepos
+	IncludeHidden bool                `json:"include_hidden"`
+	BuildStatus   string              `json:"build_status"` // status of the last build, e.g. failure
+	Owner         string              `json:"owner"`
+	Search        string              `json:"search"` // part of the name or path, case-insensitive
+	CustomFields  []CustomFieldFilter `json:"custom_fields"`
+}
+
+// DeploymentDimensions are the distinct environments, regions and namespaces deployments exist in,
+// for filters
+type DeploymentDimensions struct {
+	Environments []string `json:"environments"` // in the configured environment order
+	Regions      []string `json:"regions"`
+	Namespaces   []string `json:"namespaces"` // without the empty namespace of deployments that have none
+}
+
+// RepositoryWebhook is a webhook the app created on a repository. Secret is encrypted at rest
+// and never sent to the frontend.
+type RepositoryWebhook struct {
+	RepositoryID int64     `json:"repository_id" db:"repository_id"`
+	HookID       int64     `json:"hook_id" db:"hook_id"`
+	TargetURL    string    `json:"target_url" db:"target_url"`
+	Secret       string    `json:"-" db:"secret"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
+
+// WebhookStatus reports whether a repository's webhook still exists on GitHub and how its recent deliveries went
+type WebhookStatus struct {
+	RepositoryID       int64      `json:"repository_id"`
+	Installed          bool       `json:"installed"`
+	MissingOnGitHub    bool       `json:"missing_on_github"` // recorded locally but deleted on GitHub
+	HookID             int64      `json:"hook_id,omitempty"`
+	TargetURL          string     `json:"target_url,omitempty"`
+	Active             bool       `json:"active"`
+	Events             []string   `json:"events"`
+	RecentDeliveries   int        `json:"recent_deliveries"`
+	FailedDeliveries   int        `json:"failed_deliveries"`
+	LastDeliveryAt     *time.Time `json:"last_delivery_at,omitempty"`
+	LastDeliveryStatus string     `json:"last_delivery_status,omitempty"`
+	Error              string     `json:"error,omitempty"`
+}
+
+// ActionArtifact is a file archive the workflow run behind an action uploaded. Expired artifacts
+// can't be downloaded anymore; TooLarge ones exceed the download limit.
+type ActionArtifact struct {
+	ID        int64      `json:"id"`
+	Name      string     `json:"name"`
+	SizeBytes int64      `json:"size_bytes"`
+	Expired   bool       `json:"expired"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	TooLarge  bool       `json:"too_large"`
+}
+
+// AuditEntry records a change the app made outside itself, such as installing a webhook
+type AuditEntry struct {
+	ID           int64     `json:"id" db:"id"`
+	Action       string    `json:"action" db:"action"`
+	RepositoryID *int64    `json:"repository_id" db:"repository_id"`
+	Detail       string    `json:"detail" db:"detail"`
+	Success      bool      `json:"success" db:"success"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
+
+// UsageEventType names a view recorded by local usage analytics
+type UsageEventType string
+
+const (
+	UsageServiceOpened          UsageEventType = "service_opened"
+	UsageDeploymentMatrixViewed UsageEventType = "deployment_matrix_viewed"
+	UsageTaskBoardViewed        UsageEventType = "task_board_viewed"
+)
+
+// UsageEvent is one recorded view. It never leaves the local database.
+type UsageEvent struct {
+	ID        int64          `json:"id" db:"id"`
+	Event     UsageEventType `json:"event" db:"event"`
+	ServiceID *int64         `json:"service_id" db:"service_id"`
+	CreatedAt time.Time      `json:"created_at" db:"created_at"`
+}
+
+// ServiceViewCount is how often a service was opened or had its deployments viewed
+type ServiceViewCount struct {
+	ServiceID    int64     `json:"service_id"`
+	ServiceName  string    `json:"service_name"`
+	Views        int       `json:"views"`
+	LastViewedAt time.Time `json:"last_viewed_at"`
+}
+
+// HourCount is the number of usage events in one local hour of the day (0-23)
+type HourCount struct {
+	Hour   int `json:"hour"`
+	Events int `json:"events"`
+}
+
+// UsageInsights summarizes local usage analytics over a window of days
+type UsageInsights struct {
+	Days         int                    `json:"days"`
+	Since        time.Time              `json:"since"`
+	Enabled      bool                   `json:"enabled"`
+	TotalEvents  int                    `json:"total_events"`
+	EventCounts  map[UsageEventType]int `json:"event_counts"`
+	TopServices  []ServiceViewCount     `json:"top_services"`
+	BusiestHours []HourCount            `json:"busiest_hours"` // busiest first, hours without events left out
+}
+
+// TelemetryCounter is how often a feature was used; Pending is the part not yet sent to the
+// telemetry endpoint
+type TelemetryCounter struct {
+	Feature    string     `json:"feature"`
+	Count      int64      `json:"count"`
+	Pending    int64      `json:"pending"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"` // kept locally, never sent
+}
+
+// TelemetrySummary shows everything opt-in telemetry collects and what the next flush would send
+type TelemetrySummary struct {
+	Enabled        bool               `json:"enabled"`
+	Endpoint       string             `json:"endpoint"` // empty when counts are only kept locally
+	Features       []string           `json:"features"` // all features that can be counted
+	Counters       []TelemetryCounter `json:"counters"`
+	NextPayload    string             `json:"next_payload"` // JSON body of the next flush, exactly as sent
+	LastFlushAt    *time.Time         `json:"last_flush_at,omitempty"`
+	LastFlushError string             `json:"last_flush_error,omitempty"`
+}
+
+// SecurityAlertSource is the GitHub feature a security alert comes from
+type SecurityAlertSource string
+
+const (
+	SecurityAlertDependabot   SecurityAlertSource = "dependabot"
+	SecurityAlertCodeScanning SecurityAlertSource = "code_scanning"
+)
+
+// SecurityAlert summarizes an open security alert
+type SecurityAlert struct {
+	Number    int       `json:"number"`
+	Severity  string    `json:"severity"` // critical, high, medium or low
+	Summary   string    `json:"summary"`
+	Location  string    `json:"location"` // vulnerable package or file
+	URL       string    `json:"url"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ServicePackageManifest is a go.mod or package.json read for a service's packages
+type ServicePackageManifest struct {
+	ServiceID  int64     `json:"service_id" db:"service_id"`
+	Path       string    `json:"path" db:"path"`
+	Ecosystem  string    `json:"ecosystem" db:"ecosystem"` // go or npm
+	BlobSHA    string    `json:"blob_sha" db:"blob_sha"`
+	ParseError string    `json:"parse_error,omitempty" db:"parse_error"` // why the manifest couldn't be read; it has no packages then
+	ParsedAt   time.Time `json:"parsed_at" db:"parsed_at"`
+}
+
+// ServicePackage is a direct dependency declared in a service's manifest, with the latest version
+// when version lookups are enabled
+type ServicePackage struct {
+	ID              int64      `json:"id" db:"id"`
+	ServiceID       int64      `json:"service_id" db:"service_id"`
+	ManifestPath    string     `json:"manifest_path" db:"manifest_path"`
+	Ecosystem       string     `json:"ecosystem" db:"ecosystem"`
+	Name            string     `json:"name" db:"name"`
+	Version         string     `json:"version" db:"version"` // as declared: a module version or an npm version range
+	Dev             bool       `json:"dev" db:"dev"`
+	LatestVersion   string     `json:"latest_version,omitempty" db:"latest_version"`
+	LookupError     string     `json:"lookup_error,omitempty" db:"lookup_error"`
+	LatestCheckedAt *time.Time `json:"latest_checked_at,omitempty" db:"latest_checked_at"`
+	MajorBehind     bool       `json:"major_behind" db:"-"` // the latest version is a newer major version
+}
+
+// ServicePackages lists a service's manifests and the packages they declare
+type ServicePackages struct {
+	Manifests   []*ServicePackageManifest `json:"manifests"`
+	Packages    []*ServicePackage         `json:"packages"`
+	MajorBehind int                       `json:"major_behind"` // packages a major version or more behind
+}
+
+// SecurityAlertReport is a repository's open alerts from one source as of the last check. When
+// Available is false, Detail says why the alerts couldn't be read and the counts are zero.
+type SecurityAlertReport struct {
+	RepositoryID int64               `json:"repository_id"`
+	Source       SecurityAlertSource `json:"source"`
+	Available    bool                `json:"available"`
+	Detail       string              `json:"detail,omitempty"`
+	Critical     int                 `json:"critical"`
+	High         int                 `json:"high"`
+	Medium       int                 `json:"medium"`
+	Low          int                 `json:"low"`
+	TopAlerts    []SecurityAlert     `json:"top_alerts"` // most severe first, then newest
+	CheckedAt    time.Time           `json:"checked_at"`
+}
+
+// Total is the number of open alerts
+func (r *SecurityAlertReport) Total() int {
+	return r.Critical + r.High + r.Medium + r.Low
+}
+
+// SecurityAlertCounts are a repository's open alerts, shown next to it in the repository list.
+// A source whose alerts aren't available counts zero and is flagged.
+type SecurityAlertCounts struct {
+	Dependabot              int  `json:"dependabot"`
+	CodeScanning            int  `json:"code_scanning"`
+	Critical                int  `json:"critical"`
+	High                    int  `json:"high"`
+	DependabotUnavailable   bool `json:"dependabot_unavailable"`
+	CodeScanningUnavailable bool `json:"code_scanning_unavailable"`
+}
+
+// EntityType names a kind of data that background sync can change
+type EntityType string
+
+const (
+	EntityServices    EntityType = "services"
+	EntityDeployments EntityType = "deployments"
+	EntityActions     EntityType = "actions"
+	EntityResources   EntityType = "resources"
+	EntityTasks       EntityType = "tasks"
+	EntitySecurity    EntityType = "security_alerts"
+	EntityPackages    EntityType = "packages"
+)
+
+// DataChangedEvent is the payload of the data:changed event emitted after a sync changes the database.
+// Changes maps each affected entity type to the repositories it changed in.
+type DataChangedEvent struct {
+	Entities      []EntityType           `json:"entities"`
+	RepositoryIDs []int64                `json:"repository_ids"`
+	Changes       map[EntityType][]int64 `json:"changes"`
+}
+
+// Secret handling modes for settings exports
+const (
+	SecretsInclude = "include"
+	SecretsRedact  = "redact"
+	SecretsEncrypt = "encrypt"
+)
+
+type SettingsExportOptions struct {
+	Secrets    string `json:"secrets"`              // include, redact or encrypt
+	Passphrase string `json:"passphrase,omitempty"` // required to encrypt secrets
+}
+
+// RepositorySettings is the portable configuration of a repository, matched by URL on import.
+// Discovery scripts run commands on the machine, so they aren't part of it.
+type RepositorySettings struct {
+	Name            string         `json:"name"`
+	URL             string         `json:"url"`
+	Type            RepositoryType `json:"type"`
+	Description     string         `json:"description,omitempty"`
+	ServiceName     string         `json:"service_name,omitempty"`
+	ServiceLocation string         `json:"service_location,omitempty"`
+	ManualSyncOnly  bool           `json:"manual_sync_only,omitempty"`
+	DiscoveryReview bool           `json:"discovery_review,omitempty"`
+	CollectPackages bool           `json:"collect_packages,omitempty"`
+	WorkflowBranches string        `json:"workflow_branches,omitempty"`
+}
+
+// SettingsExport is the JSON document produced by ExportSettings and read by ImportSettings.
+// Redacted secrets are left out; encrypted secrets are stored in EncryptedConfig.
+type SettingsExport struct {
+	Version         int                  `json:"version"`
+	ExportedAt      time.Time            `json:"exported_at"`
+	Secrets         string               `json:"secrets"`
+	Salt            string               `json:"salt,omitempty"`
+	Config          map[string]string    `json:"config"`
+	EncryptedConfig map[string]string    `json:"encrypted_config,omitempty"`
+	Repositories    []RepositorySettings `json:"repositories"`
+}
+
+// ServiceCatalog is the JSON document written by ExportServiceCatalog. Repository, Name and Path
+// identify a service and are never changed by an import; the other fields are its editable metadata.
+type ServiceCatalog struct {
+	ExportedAt time.Time              `json:"exported_at"`
+	Services   []*ServiceCatalogEntry `json:"services"`
+}
+
+type ServiceCatalogEntry struct {
+	Repository         string            `json:"repository"`
+	Name               string            `json:"name"`
+	Path               string            `json:"path"`
+	Description        string            `json:"description"`
+	Owner              string            `json:"owner"`
+	PrimaryEnvironment string            `json:"primary_environment"`
+	CustomFields       map[string]string `json:"custom_fields"` // by field name; missing fields are left unchanged
+}
+
+// Service catalog fields an import can change; custom fields are "custom:<name>"
+const (
+	CatalogFieldDescription        = "description"
+	CatalogFieldOwner              = "owner"
+	CatalogFieldPrimaryEnvironment = "primary_environment"
+	CatalogCustomFieldPrefix       = "custom:"
+)
+
+// ServiceCatalogChange is one field of a service an import changes
+type ServiceCatalogChange struct {
+	Row         int    `json:"row"` // CSV line or 1-based JSON entry
+	ServiceID   int64  `json:"service_id"`
+	ServiceName string `json:"service_name"`
+	Field       string `json:"field"`
+	FieldID     int64  `json:"field_id,omitempty"` // custom fields only
+	Old         string `json:"old"`
+	New         string `json:"new"`
+}
+
+// ServiceCatalogProblem is a row, or part of one, an import skips
+type ServiceCatalogProblem struct {
+	Row     int    `json:"row"`
+	Message string `json:"message"`
+}
+
+// ServiceCatalogImport is the plan of a catalog import, and its outcome once Applied
+type ServiceCatalogImport struct {
+	Path      string                   `json:"path"`
+	Format    string                   `json:"format"`
+	Rows      int                      `json:"rows"`
+	Unchanged int                      `json:"unchanged"` // matched rows without changes
+	Changes   []*ServiceCatalogChange  `json:"changes"`
+	Problems  []*ServiceCatalogProblem `json:"problems"`
+	Applied   bool                     `json:"applied"`
+}
+
+type SettingsImportResult struct {
+	ConfigApplied       int      `json:"config_applied"`
+	ConfigSkipped       int      `json:"config_skipped"`
+	RepositoriesCreated int      `json:"repositories_created"`
+	RepositoriesUpdated int      `json:"repositories_updated"`
+	RepositoriesSkipped int      `json:"repositories_skipped"`
+	MissingSecrets      []string `json:"missing_secrets"` // secret keys the export left out
+	UnknownConfig       []string `json:"unknown_config"`  // config keys this version doesn't know, not imported
+}
+
+// ConfigKey describes a config key the app knows
+type ConfigKey struct {
+	Name        string   `json:"name"`
+	Type        string   `json:"type"` // string, bool, int, number, enum, url or list
+	Description string   `json:"description"`
+	Default     string   `json:"default,omitempty"` // what an empty value means
+	Values      []string `json:"values,omitempty"`  // allowed values of enum keys
+	Secret      bool     `json:"secret"`
+	Prefix      bool     `json:"prefix"`   // stands for every key starting with Name
+	Internal    bool     `json:"internal"` // maintained by the app rather than set in the settings
+}
+
+// StartupError describes a failure during application startup, such as a failed migration
+type StartupError struct {
+	Stage      string `json:"stage"` // database, migration, or legacy_database while a legacy database waits for MigrateLegacyDatabase
+	Message    string `json:"message"`
+	Migration  string `json:"migration,omitempty"`
+	BackupPath string `json:"backup_path,omitempty"`
+	Restored   bool   `json:"restored"`
+}
+
+// TokenSource is where the GitHub token in use came from
+type TokenSource string
+
+const (
+	TokenSourceNone            TokenSource = ""
+	TokenSourceSettings        TokenSource = "settings"
+	TokenSourceCredentialsFile TokenSource = "credentials_file" // imported from ~/.dev-dashboard/credentials.json
+	TokenSourceKeychain        TokenSource = "keychain"         // imported from the OS keychain
+	TokenSourceEnvironment     TokenSource = "environment"      // the GITHUB_TOKEN environment variable
+)
+
+// SetupStatus reports how far the app is set up, for first-run guidance
+type SetupStatus struct {
+	DatabaseReady     bool        `json:"database_ready"`
+	GitHubTokenSource TokenSource `json:"github_token_source"` // empty while no token is configured
+	CredentialsFile   string      `json:"credentials_file"`    // where a token is imported from on startup
+	SyncRunning       bool        `json:"sync_running"`
+}
+
+// SystemWarning is a problem GetSystemStatus reports before it breaks something, e.g. a GitHub
+// token about to expire
+type SystemWarning struct {
+	Kind     string `json:"kind"`     // e.g. github_token_expiry
+	Severity string `json:"severity"` // warning, or error once it broke something
+	Message  string `json:"message"`
+}
+
+// SystemStatus is the app's health, shown as a banner above every page
+type SystemStatus struct {
+	GitHubTokenExpiresAt *time.Time      `json:"github_token_expires_at,omitempty"` // as GitHub last reported it; nil when the token doesn't expire or it isn't known
+	Warnings             []SystemWarning `json:"warnings"`
+}
+
+// LegacyDatabase is a database an earlier build of the app left under its old data directory
+type LegacyDatabase struct {
+	Path           string    `json:"path"`
+	SizeBytes      int64     `json:"size_bytes"`
+	ModifiedAt     time.Time `json:"modified_at"`
+	HasData        bool      `json:"has_data"`
+	CurrentPath    string    `json:"current_path"`
+	CurrentHasData bool      `json:"current_has_data"` // both hold data, so migrating needs a choice of which to keep
+}
+
+// SlowQuery is a database statement that exceeded the slow query threshold
+type SlowQuery struct {
+	SQL        string    `json:"sql"`
+	DurationMs float64   `json:"duration_ms"`
+	ExecutedAt time.Time `json:"executed_at"`
+}
+// JobStatus is the state of a background job
+type JobStatus string
+
+const (
+	JobRunning   JobStatus = "running"
+	JobSucceeded JobStatus = "succeeded"
+	JobFailed    JobStatus = "failed"
+	JobCancelled JobStatus = "cancelled"
+)
+
+// Kinds of background jobs StartJob accepts
+const (
+	// JobRediscoverServices rediscovers the services of every monorepo, or of the monorepo given by
+	// the repository_id parameter
+	JobRediscoverServices = "rediscover_services"
+	// JobExportUsageData writes every recorded usage event as JSON to the file given by the path
+	// parameter, or chosen in a save dialog when it's empty
+	JobExportUsageData = "export_usage_data"
+	// JobPruneWorkflowRuns deletes the recorded workflow runs on branches outside the workflow
+	// branch filter of every repository, or of the repository given by the repository_id parameter
+	JobPruneWorkflowRuns = "prune_workflow_runs"
+)
+
+// Job is a long-running operation run in the background. Finished jobs stay listed until they're
+// acknowledged.
+type Job struct {
+	ID             int64      `json:"id"`
+	Kind           string     `json:"kind"`
+	Params         string     `json:"params"` // JSON object
+	Status         JobStatus  `json:"status"`
+	Progress       int        `json:"progress"` // percent
+	Message        string     `json:"message,omitempty"`
+	Result         string     `json:"result,omitempty"`    // JSON, set when the job succeeded
+	FilePath       string     `json:"file_path,omitempty"` // file the job wrote, if any
+	Error          string     `json:"error,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	FinishedAt     *time.Time `json:"finished_at,omitempty"`
+	AcknowledgedAt *time.Time `json:"acknowledged_at,omitempty"`
+}
+
+// DiscoveryChangeKind is what a sync's discovery would change about a repository's services
+type DiscoveryChangeKind string
+
+const (
+	DiscoveryAdd    DiscoveryChangeKind = "add"
+	DiscoveryRemove DiscoveryChangeKind = "remove"
+	// DiscoveryRename moves an existing service to a new name or path, keeping its ID
+	DiscoveryRename DiscoveryChangeKind = "rename"
+)
+
+type DiscoveryChangeStatus string
+
+const (
+	DiscoveryChangePending  DiscoveryChangeStatus = "pending"
+	DiscoveryChangeRejected DiscoveryChangeStatus = "rejected"
+	// DiscoveryChangeExpired changes weren't reviewed within the review window
+	DiscoveryChangeExpired DiscoveryChangeStatus = "expired"
+)
+
+// DiscoveryChange is a service add, removal or rename found by the sync of a repository in review
+// mode, waiting to be applied. Rejected and expired changes are kept until discovery stops
+// reporting them, so they aren't raised again on every sync.
+type DiscoveryChange struct {
+	ID           int64                 `json:"id"`
+	RepositoryID int64                 `json:"repository_id"`
+	Kind         DiscoveryChangeKind   `json:"kind"`
+	Status       DiscoveryChangeStatus `json:"status"`
+	ServiceID    *int64                `json:"service_id,omitempty"` // service removed or renamed, nil for adds
+	Name         string                `json:"name"`                 // the removed service's name for removals
+	Path         string                `json:"path"`
+	OldName      string                `json:"old_name,omitempty"` // set for renames
+	OldPath      string                `json:"old_path,omitempty"` // set for renames
+	Description  string                `json:"description,omitempty"`
+	Domain       string                `json:"domain,omitempty"`
+	HasReadme    *bool                 `json:"has_readme,omitempty"`
+	DetectedAt   time.Time             `json:"detected_at"`
+	ExpiresAt    *time.Time            `json:"expires_at,omitempty"` // when a pending change is applied or expired unreviewed, nil if never
+}
+
+// DiscoveryChangeDecision accepts or rejects a pending discovery change
+type DiscoveryChangeDecision struct {
+	ID     int64 `json:"id"`
+	Accept bool  `json:"accept"`
+}
+
+// ServiceMergeResult counts the records MergeServices moved from the duplicate to the kept service
+type ServiceMergeResult struct {
+	Deployments       int64 `json:"deployments"`
+	DeploymentHistory int64 `json:"deployment_history"`
+	Actions           int64 `json:"actions"`
+	UsageEvents       int64 `json:"usage_events"`
+	// DroppedDeployments targeted a region and namespace the kept service already has a deployment
+	// for; they were deleted with the duplicate
+	DroppedDeployments int64 `json:"dropped_deployments"`
+}
+
+// Services a watch rule applies to
+const (
+	WatchScopeAll = "all"
+	// WatchScopeServices applies to the services listed in ServiceIDs
+	WatchScopeServices = "services"
+	// WatchScopeCustomField applies to services whose custom field ScopeField is ScopeValue, e.g. tier=critical
+	WatchScopeCustomField = "custom_field"
+	// WatchScopeDomain applies to the services of domain ScopeValue
+	WatchScopeDomain = "domain"
+)
+
+// Conditions a watch rule fires on
+const (
+	// WatchTagChanged fires when an environment starts running another tag
+	WatchTagChanged = "tag_changed"
+	// WatchDeployFailed fires when a deployment run fails. Runs aren't recorded per environment, so
+	// a rule's environments don't narrow it.
+	WatchDeployFailed = "deploy_failed"
+	// WatchNoDeploy fires when an environment hasn't been deployed to for Threshold days
+	WatchNoDeploy = "no_deploy"
+	// WatchDrift fires when an environment is more than Threshold commits behind the one before it
+	// in the promotion order
+	WatchDrift = "drift"
+)
+
+// WatchCondition is one condition of a watch rule; Threshold is used by no_deploy and drift
+type WatchCondition struct {
+	Type      string `json:"type"`
+	Threshold int    `json:"threshold,omitempty"`
+}
+
+// WatchRule raises notifications for deployment events of the services and environments it scopes.
+// Rules are evaluated at the end of each sync pass; event conditions (tag changed, deploy failed)
+// fire for what happened since the previous evaluation, state conditions (no deploy, drift) fire
+// when they start to hold.
+type WatchRule struct {
+	ID         int64   `json:"id"`
+	Name       string  `json:"name"`
+	Enabled    bool    `json:"enabled"`
+	Scope      string  `json:"scope"`
+	ServiceIDs []int64 `json:"service_ids,omitempty"`
+	ScopeField string  `json:"scope_field,omitempty"`
+	ScopeValue string  `json:"scope_value,omitempty"`
+	// Environments the rule watches, matched ignoring case; empty watches all of them
+	Environments    []string         `json:"environments"`
+	Conditions      []WatchCondition `json:"conditions"`
+	LastEvaluatedAt *time.Time       `json:"last_evaluated_at,omitempty"`
+	LastTriggeredAt *time.Time       `json:"last_triggered_at,omitempty"`
+	CreatedAt       time.Time        `json:"created_at"`
+}
+
+// WatchOutcome is whether a rule condition held for one service and environment, and why
+type WatchOutcome struct {
+	ServiceID   int64  `json:"service_id"`
+	ServiceName string `json:"service_name"`
+	Environment string `json:"environment,omitempty"`
+	Condition   string `json:"condition"`
+	Fired       bool   `json:"fired"`
+	Detail      string `json:"detail"` // e.g. "prd is 14 commits behind stg (threshold 10)"
+}
+
+// WatchEvaluation is the log entry of one evaluation of a watch rule
+type WatchEvaluation struct {
+	ID          int64          `json:"id"`
+	RuleID      int64          `json:"rule_id"`
+	EvaluatedAt time.Time      `json:"evaluated_at"`
+	Services    int            `json:"services"` // services in the rule's scope
+	Fired       int            `json:"fired"`
+	Outcomes    []WatchOutcome `json:"outcomes"`
+	Error       string         `json:"error,omitempty"`
+}
@@ -0,0 +1,347 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	gosync "sync"
+
+	"dev-dashboard/internal/telemetry"
+)
+
+// Kinds of values presentation mode disguises, each with its own pseudonym sequence
+const (
+	pseudonymRepository = "repository"
+	pseudonymService    = "service"
+	pseudonymProject    = "project"
+	pseudonymURL        = "url"
+	pseudonymJiraKey    = "jira"
+)
+
+// presentationReadPrefixes are the name prefixes of bindings that only read; everything else is
+// blocked in presentation mode so nothing acts on disguised entities
+var presentationReadPrefixes = []string{"Get", "Filter", "Preview", "Diagnose", "FetchJira", "Validate", "Is"}
+
+// presentationReadBindings are the bindings without a read prefix that only read. Prefixes such as
+// Generate or Test are left out, since a later binding named like that needn't be as harmless.
+var presentationReadBindings = map[string]bool{
+	"GenerateEnvironmentComparisonReport": true,
+	"GenerateServiceReport":               true,
+	"TestGitHubConnection":                true,
+	"TestJiraConnection":                  true,
+	"Greet":                               true,
+}
+
+// presentationNameKinds are the types whose Name (and OldName) field names a repository, service
+// or project
+var presentationNameKinds = map[string]string{
+	"Repository":            pseudonymRepository,
+	"RepositoryAccessEntry": pseudonymRepository,
+	"RepositorySyncStatus":  pseudonymRepository,
+	"RepositorySettings":    pseudonymRepository,
+	"Microservice":          pseudonymService,
+	"DiscoveredService":     pseudonymService,
+	"ServiceCatalogEntry":   pseudonymService,
+	"DiscoveryChange":       pseudonymService,
+	"Project":               pseudonymProject,
+}
+
+// presentationJiraKey finds JIRA keys in free text such as titles and messages
+var presentationJiraKey = regexp.MustCompile(`\b[A-Z][A-Z0-9]{1,9}-\d+\b`)
+
+// presentationMinNameLength is the shortest name replaced inside free text; shorter ones would
+// match ordinary words
+const presentationMinNameLength = 3
+
+// presentationRedactor replaces names, URLs and JIRA keys in binding results with pseudonyms.
+// Pseudonyms are handed out in order of first sight and kept, in memory only, for the rest of the
+// app run, so the UI stays coherent when presentation mode is toggled.
+type presentationRedactor struct {
+	mu         gosync.Mutex
+	enabled    bool
+	pseudonyms map[string]string // kind + real value -> pseudonym
+	counts     map[string]int
+	// names are the repository, service and project names, replaced in free text too: those in the
+	// database when presentation mode was turned on and those seen in results since
+	names       map[string]string
+	namesRegexp *regexp.Regexp // nil when names changed since it was built
+}
+
+func newPresentationRedactor() *presentationRedactor {
+	return &presentationRedactor{
+		pseudonyms: make(map[string]string),
+		counts:     make(map[string]int),
+		names:      make(map[string]string),
+	}
+}
+
+// SetPresentationMode turns presentation mode on or off. While it's on, the frontend sends every
+// binding call through CallInPresentationMode. Turning it on hands out the pseudonyms of every
+// repository, service and project first, and fails when they can't be read.
+func (a *App) SetPresentationMode(enabled bool) error {
+	if enabled {
+		if err := a.loadPresentationNames(); err != nil {
+			return fmt.Errorf("failed to load names to disguise: %w", err)
+		}
+		a.recordFeature(telemetry.PresentationMode)
+	}
+	a.presentation.mu.Lock()
+	defer a.presentation.mu.Unlock()
+	a.presentation.enabled = enabled
+	return nil
+}
+
+// loadPresentationNames gives every repository, service and project name in the database its
+// pseudonym, so free text is disguised even before a result names them in a field
+func (a *App) loadPresentationNames() error {
+	if a.repoModel == nil || a.serviceModel == nil || a.projectModel == nil {
+		return fmt.Errorf("repository model not initialized")
+	}
+	repos, err := a.repoModel.GetAll()
+	if err != nil {
+		return err
+	}
+	services, err := a.serviceModel.GetAll()
+	if err != nil {
+		return err
+	}
+	projects, err := a.projectModel.GetAll()
+	if err != nil {
+		return err
+	}
+
+	for _, repo := range repos {
+		a.presentation.pseudonym(pseudonymRepository, repo.Name)
+	}
+	for _, service := range services {
+		a.presentation.pseudonym(pseudonymService, service.Name)
+	}
+	for _, project := range projects {
+		a.presentation.pseudonym(pseudonymProject, project.Name)
+	}
+	return nil
+}
+
+// GetPresentationMode reports whether presentation mode is on
+func (a *App) GetPresentationMode() bool {
+	a.presentation.mu.Lock()
+	defer a.presentation.mu.Unlock()
+	return a.presentation.enabled
+}
+
+// CallInPresentationMode calls a read binding with JSON arguments and returns its result, encoded
+// as JSON, with repository, service and project names, URLs and JIRA keys replaced by pseudonyms.
+// IDs and the shape of the result are left alone, so the frontend reads it like the binding's own.
+// Bindings that change anything are refused.
+func (a *App) CallInPresentationMode(method string, args []interface{}) (json.RawMessage, error) {
+	if !a.GetPresentationMode() {
+		return nil, fmt.Errorf("presentation mode is off")
+	}
+	if !isReadBinding(method) {
+		return nil, fmt.Errorf("%s is disabled in presentation mode", method)
+	}
+	binding := reflect.ValueOf(a).MethodByName(method)
+	if !binding.IsValid() {
+		return nil, fmt.Errorf("unknown binding %s", method)
+	}
+
+	bindingType := binding.Type()
+	if bindingType.IsVariadic() || len(args) != bindingType.NumIn() {
+		return nil, fmt.Errorf("%s takes %d arguments, got %d", method, bindingType.NumIn(), len(args))
+	}
+	in := make([]reflect.Value, len(args))
+	for i, arg := range args {
+		// Arguments arrive decoded from JSON; encode them again to decode them into the parameter type
+		data, err := json.Marshal(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid argument %d of %s: %w", i+1, method, err)
+		}
+		value := reflect.New(bindingType.In(i))
+		if err := json.Unmarshal(data, value.Interface()); err != nil {
+			return nil, fmt.Errorf("invalid argument %d of %s: %w", i+1, method, err)
+		}
+		in[i] = value.Elem()
+	}
+
+	var result reflect.Value
+	for _, out := range binding.Call(in) {
+		if out.Type() == reflect.TypeOf((*error)(nil)).Elem() {
+			if !out.IsNil() {
+				return nil, out.Interface().(error)
+			}
+			continue
+		}
+		result = out
+	}
+	if !result.IsValid() {
+		return nil, nil
+	}
+	data, err := json.Marshal(a.presentation.redact(result, "").Interface())
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode result of %s: %w", method, err)
+	}
+	return data, nil
+}
+
+func isReadBinding(method string) bool {
+	if presentationReadBindings[method] {
+		return true
+	}
+	for _, prefix := range presentationReadPrefixes {
+		if strings.HasPrefix(method, prefix) {
+			return method != "GetPresentationMode"
+		}
+	}
+	return false
+}
+
+// pseudonym returns the pseudonym of a value of a kind, handing out the next one on first sight
+func (r *presentationRedactor) pseudonym(kind, value string) string {
+	if value == "" {
+		return ""
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := kind + "\x00" + value
+	if pseudonym, ok := r.pseudonyms[key]; ok {
+		return pseudonym
+	}
+	r.counts[kind]++
+	n := r.counts[kind]
+	var pseudonym string
+	switch kind {
+	case pseudonymURL:
+		pseudonym = fmt.Sprintf("https://example.com/redacted/%d", n)
+	case pseudonymJiraKey:
+		pseudonym = fmt.Sprintf("DEMO-%d", n)
+	default:
+		pseudonym = fmt.Sprintf("%s-%d", kind, n)
+	}
+	r.pseudonyms[key] = pseudonym
+	if kind != pseudonymURL && kind != pseudonymJiraKey && len(value) >= presentationMinNameLength {
+		r.names[value] = pseudonym
+		r.namesRegexp = nil
+	}
+	return pseudonym
+}
+
+// redactText replaces the JIRA keys and the known names in free text
+func (r *presentationRedactor) redactText(text string) string {
+	text = presentationJiraKey.ReplaceAllStringFunc(text, func(key string) string {
+		return r.pseudonym(pseudonymJiraKey, key)
+	})
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.names) == 0 {
+		return text
+	}
+	if r.namesRegexp == nil {
+		names := make([]string, 0, len(r.names))
+		for name := range r.names {
+			names = append(names, regexp.QuoteMeta(name))
+		}
+		// Longer names first, so a name containing another is replaced whole
+		sort.Slice(names, func(i, j int) bool { return len(names[i]) > len(names[j]) })
+		r.namesRegexp = regexp.MustCompile(`\b(?:` + strings.Join(names, "|") + `)\b`)
+	}
+	return r.namesRegexp.ReplaceAllStringFunc(text, func(name string) string {
+		return r.names[name]
+	})
+}
+
+// redact returns a copy of a value with its disguised fields replaced; the value itself, which
+// may be cached, is left untouched. kind is the pseudonym kind of a string value, empty for free
+// text, whose JIRA keys and known names are replaced.
+func (r *presentationRedactor) redact(v reflect.Value, kind string) reflect.Value {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.New(v.Type().Elem())
+		out.Elem().Set(r.redact(v.Elem(), kind))
+		return out
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.New(v.Type()).Elem()
+		out.Set(r.redact(v.Elem(), kind))
+		return out
+	case reflect.Struct:
+		// The copy keeps unexported fields, e.g. a time.Time's
+		out := reflect.New(v.Type()).Elem()
+		out.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			out.Field(i).Set(r.redact(v.Field(i), presentationFieldKind(v.Type(), field)))
+		}
+		return out
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(r.redact(v.Index(i), kind))
+		}
+		return out
+	case reflect.Array:
+		out := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(r.redact(v.Index(i), kind))
+		}
+		return out
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			valueKind := kind
+			// Config values such as jira_url
+			if key := iter.Key(); key.Kind() == reflect.String && strings.HasSuffix(key.String(), "_url") {
+				valueKind = pseudonymURL
+			}
+			out.SetMapIndex(iter.Key(), r.redact(iter.Value(), valueKind))
+		}
+		return out
+	case reflect.String:
+		var redacted string
+		if kind != "" {
+			redacted = r.pseudonym(kind, v.String())
+		} else {
+			redacted = r.redactText(v.String())
+		}
+		return reflect.ValueOf(redacted).Convert(v.Type())
+	default:
+		return v
+	}
+}
+
+// presentationFieldKind returns the pseudonym kind of a struct field, empty for free text
+func presentationFieldKind(structType reflect.Type, field reflect.StructField) string {
+	switch name := field.Name; {
+	case strings.HasSuffix(name, "URL"):
+		return pseudonymURL
+	case name == "RepositoryName" || name == "KubernetesRepoName":
+		return pseudonymRepository
+	case strings.HasSuffix(name, "ServiceName"):
+		return pseudonymService
+	case name == "ProjectName":
+		return pseudonymProject
+	case name == "JiraTicketID" || name == "JiraParentKey" || name == "IssueKey":
+		return pseudonymJiraKey
+	case name == "Name" || name == "OldName":
+		return presentationNameKinds[structType.Name()]
+	}
+	return ""
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"dev-dashboard/internal/testsupport"
+	"dev-dashboard/pkg/types"
+)
+
+func TestPresentationModeDisguisesStoredNamesInFreeText(t *testing.T) {
+	app, db := newTestApp(t)
+	conn := db.GetConn()
+	repo := testsupport.Repository(t, conn, func(repo *types.Repository) { repo.Name = "payments-platform" })
+	service := testsupport.Service(t, conn, repo.ID, func(service *types.Microservice) { service.Name = "ledger-api" })
+	project := testsupport.Project(t, conn, func(project *types.Project) { project.Name = "Checkout revamp" })
+
+	if err := app.SetPresentationMode(true); err != nil {
+		t.Fatalf("SetPresentationMode: %v", err)
+	}
+	// No result has named them in a field yet
+	text := "Checkout revamp: move ledger-api out of payments-platform"
+	redacted := app.presentation.redactText(text)
+	for _, name := range []string{repo.Name, service.Name, project.Name} {
+		if strings.Contains(redacted, name) {
+			t.Errorf("%q still names %s", redacted, name)
+		}
+	}
+	if pseudonym := app.presentation.pseudonym(pseudonymService, service.Name); !strings.Contains(redacted, pseudonym) {
+		t.Errorf("%q doesn't use the service's pseudonym %s", redacted, pseudonym)
+	}
+}
+
+func TestPresentationModeOnlyRunsListedReadBindings(t *testing.T) {
+	for method, read := range map[string]bool{
+		"GetRepositories":                     true,
+		"FilterMicroservices":                 true,
+		"GenerateServiceReport":               true,
+		"GenerateEnvironmentComparisonReport": true,
+		"TestGitHubConnection":                true,
+		"TestJiraConnection":                  true,
+		"GetPresentationMode":                 false,
+		"GenerateWebhookSecret":               false,
+		"TestWebhookDelivery":                 false,
+		"CreateRepository":                    false,
+	} {
+		if got := isReadBinding(method); got != read {
+			t.Errorf("isReadBinding(%s) = %v, want %v", method, got, read)
+		}
+	}
+}
+
+func TestCallInPresentationModeReturnsRedactedJSON(t *testing.T) {
+	app, db := newTestApp(t)
+	repo := testsupport.Repository(t, db.GetConn())
+	if err := app.SetPresentationMode(true); err != nil {
+		t.Fatalf("SetPresentationMode: %v", err)
+	}
+
+	data, err := app.CallInPresentationMode("GetRepositories", nil)
+	if err != nil {
+		t.Fatalf("CallInPresentationMode: %v", err)
+	}
+	var repos []*types.Repository
+	if err := json.Unmarshal(data, &repos); err != nil {
+		t.Fatalf("result isn't the binding's JSON: %v", err)
+	}
+	if len(repos) != 1 || repos[0].ID != repo.ID {
+		t.Fatalf("got %d repositories, want repository %d", len(repos), repo.ID)
+	}
+	if repos[0].Name == repo.Name || repos[0].URL == repo.URL {
+		t.Errorf("got repository %s at %s, want both disguised", repos[0].Name, repos[0].URL)
+	}
+
+	if _, err := app.CallInPresentationMode("DeleteRepository", []interface{}{repo.ID}); err == nil {
+		t.Error("a binding changing something should be refused")
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// primaryEnvironmentKey names the environment that is live for services that don't set their own,
+// e.g. "live" or "prod-main". Without it, environments named prd, prod or production are live.
+const primaryEnvironmentKey = "primary_environment"
+
+// getPrimaryEnvironment returns the primary environment configured for a service, falling back to
+// the primary_environment config key, or "" when neither is set
+func (a *App) getPrimaryEnvironment(serviceID int64) string {
+	if a.serviceModel != nil {
+		if service, err := a.serviceModel.GetByID(serviceID); err == nil && service.PrimaryEnvironment != "" {
+			return service.PrimaryEnvironment
+		}
+	}
+	if a.configModel != nil {
+		if config, err := a.configModel.Get(primaryEnvironmentKey); err == nil && config != nil {
+			return strings.TrimSpace(config.Value)
+		}
+	}
+	return ""
+}
+
+// isPrimaryEnvironment reports whether environment is the configured primary environment, or
+// looks like production when none is configured
+func isPrimaryEnvironment(primary, environment string) bool {
+	if primary == "" {
+		return isProductionEnvironment(environment)
+	}
+	return strings.EqualFold(primary, environment)
+}
+
+// GetServicePrimaryEnvironment returns the environment rollups treat as live for a service: its
+// own setting, else the primary_environment config key. An empty result means environments named
+// prd, prod or production are used.
+func (a *App) GetServicePrimaryEnvironment(serviceID int64) (string, error) {
+	if a.serviceModel == nil {
+		return "", fmt.Errorf("service model not initialized")
+	}
+	return a.getPrimaryEnvironment(serviceID), nil
+}
+
+// SetServicePrimaryEnvironment overrides the primary environment of one service; an empty
+// environment reverts it to the global default
+func (a *App) SetServicePrimaryEnvironment(serviceID int64, environment string) error {
+	if a.serviceModel == nil {
+		return fmt.Errorf("service model not initialized")
+	}
+	return a.serviceModel.SetPrimaryEnvironment(serviceID, strings.TrimSpace(environment))
+}
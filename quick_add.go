@@ -0,0 +1,184 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"dev-dashboard/internal/telemetry"
+	"dev-dashboard/pkg/types"
+)
+
+// taskDefaultProjectKey is the ID of the project quick-added tasks go to
+const taskDefaultProjectKey = "task_default_project_id"
+
+// quickAddTicket matches a #TICKET token, e.g. #PROJ-123
+var quickAddTicket = regexp.MustCompile(`^#([A-Za-z][A-Za-z0-9_]*-\d+)$`)
+
+// quickAddRelativeDate matches relative dates such as +3d or +2w
+var quickAddRelativeDate = regexp.MustCompile(`^\+(\d+)([dw])$`)
+
+var quickAddPriorities = map[string]types.TaskPriority{
+	"low":    types.TaskPriorityLow,
+	"medium": types.TaskPriorityMedium,
+	"med":    types.TaskPriorityMedium,
+	"high":   types.TaskPriorityHigh,
+}
+
+// quickAddTask is what a quick-add line describes
+type quickAddTask struct {
+	Title         string
+	JiraTicketID  string
+	Priority      types.TaskPriority
+	ScheduledDate *time.Time
+	Deadline      *time.Time
+}
+
+// parseQuickAdd parses a quick-add line such as "Fix login bug #PROJ-123 !high @friday @due:+1w".
+// Tokens are taken out of the line and the remaining words make the title, which is the ticket's
+// when there are none:
+//   - #TICKET links the JIRA ticket
+//   - !low, !medium (!med) or !high sets the priority
+//   - @date schedules the task and @due:date sets its deadline, where a date is today, tomorrow,
+//     a weekday (the next one, today included), +Nd or +Nw, or YYYY-MM-DD
+//
+// Dates are relative to now, in its location.
+func parseQuickAdd(input string, now time.Time) (*quickAddTask, error) {
+	task := &quickAddTask{}
+	var words []string
+	for _, word := range strings.Fields(input) {
+		switch {
+		case quickAddTicket.MatchString(word):
+			if task.JiraTicketID != "" {
+				return nil, fmt.Errorf("only one ticket can be linked, got %s and %s", task.JiraTicketID, word)
+			}
+			task.JiraTicketID = strings.ToUpper(word[1:])
+		case len(word) > 1 && word[0] == '!':
+			priority, ok := quickAddPriorities[strings.ToLower(word[1:])]
+			if !ok {
+				return nil, fmt.Errorf("unknown priority %q, use !low, !medium or !high", word)
+			}
+			task.Priority = priority
+		case len(word) > 1 && word[0] == '@':
+			value, due := strings.CutPrefix(strings.ToLower(word[1:]), "due:")
+			date, err := parseQuickAddDate(value, now)
+			if err != nil {
+				return nil, err
+			}
+			if due {
+				// Due by the end of the day, as deadlines picked in the task form are
+				deadline := time.Date(date.Year(), date.Month(), date.Day(), 23, 59, 59, 0, date.Location())
+				task.Deadline = &deadline
+			} else {
+				task.ScheduledDate = &date
+			}
+		default:
+			words = append(words, word)
+		}
+	}
+
+	task.Title = strings.Join(words, " ")
+	// Tasks are unique per project and ticket, so each needs one
+	if task.JiraTicketID == "" {
+		return nil, fmt.Errorf("link the task's JIRA ticket with #TICKET, e.g. #PROJ-123")
+	}
+	return task, nil
+}
+
+// parseQuickAddDate returns the start of the day a quick-add date names
+func parseQuickAddDate(value string, now time.Time) (time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch value {
+	case "today":
+		return today, nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	}
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		name := strings.ToLower(weekday.String())
+		if value == name || value == name[:3] {
+			return today.AddDate(0, 0, (int(weekday)-int(today.Weekday())+7)%7), nil
+		}
+	}
+	if match := quickAddRelativeDate.FindStringSubmatch(value); match != nil {
+		n, err := strconv.Atoi(match[1])
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid date %q", value)
+		}
+		if match[2] == "w" {
+			n *= 7
+		}
+		return today.AddDate(0, 0, n), nil
+	}
+	if date, err := time.ParseInLocation("2006-01-02", value, now.Location()); err == nil {
+		return date, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q, use today, tomorrow, a weekday, +Nd, +Nw or YYYY-MM-DD", value)
+}
+
+// defaultTaskProject returns the project quick-added tasks go to: the configured one, or the only
+// project when there's just one
+func (a *App) defaultTaskProject() (*types.Project, error) {
+	if a.projectModel == nil {
+		return nil, fmt.Errorf("project model not initialized")
+	}
+	if value, err := a.GetConfig(taskDefaultProjectKey); err == nil && value != "" {
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q", taskDefaultProjectKey, value)
+		}
+		project, err := a.projectModel.GetByID(id)
+		if err != nil {
+			return nil, fmt.Errorf("default project %d not found", id)
+		}
+		return project, nil
+	}
+
+	projects, err := a.projectModel.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(projects) == 1 {
+		return projects[0], nil
+	}
+	return nil, fmt.Errorf("set a default project for quick add in Settings")
+}
+
+// QuickAddTask creates a task in the default project from a line such as
+// "Fix login bug #PROJ-123 !high @friday", see parseQuickAdd. The task is scheduled for today unless
+// the line gives a date, and a linked ticket's title is fetched as when creating a task.
+func (a *App) QuickAddTask(input string) (*types.Task, error) {
+	if a.taskModel == nil {
+		return nil, fmt.Errorf("task model not initialized")
+	}
+	a.recordFeature(telemetry.QuickAdd)
+	parsed, err := parseQuickAdd(input, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	project, err := a.defaultTaskProject()
+	if err != nil {
+		return nil, err
+	}
+
+	task := types.Task{
+		ProjectID:     project.ID,
+		JiraTicketID:  parsed.JiraTicketID,
+		Title:         parsed.Title,
+		ScheduledDate: parsed.ScheduledDate,
+		Deadline:      parsed.Deadline,
+		Status:        types.TaskPending,
+		Priority:      parsed.Priority,
+	}
+	if task.ScheduledDate == nil {
+		now := time.Now()
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		task.ScheduledDate = &today
+	}
+	if err := a.createTaskWithJiraFields(&task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
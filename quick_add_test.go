@@ -0,0 +1,112 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"dev-dashboard/pkg/types"
+)
+
+// quickAddNow is a Tuesday morning
+var quickAddNow = time.Date(2024, time.March, 5, 10, 30, 0, 0, time.UTC)
+
+func mustParseQuickAdd(t *testing.T, input string) *quickAddTask {
+	t.Helper()
+	task, err := parseQuickAdd(input, quickAddNow)
+	if err != nil {
+		t.Fatalf("parseQuickAdd(%q): %v", input, err)
+	}
+	return task
+}
+
+func quickAddDay(month time.Month, d int) time.Time {
+	return time.Date(2024, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestParseQuickAddTicket(t *testing.T) {
+	task := mustParseQuickAdd(t, "Fix login #proj-123")
+	if task.JiraTicketID != "PROJ-123" {
+		t.Errorf("got ticket %q, want PROJ-123", task.JiraTicketID)
+	}
+
+	for _, input := range []string{"Fix login", "Fix login #123", "Fix login #PROJ-123 #PROJ-124"} {
+		if _, err := parseQuickAdd(input, quickAddNow); err == nil {
+			t.Errorf("parseQuickAdd(%q) succeeded, want an error", input)
+		}
+	}
+}
+
+func TestParseQuickAddPriority(t *testing.T) {
+	tests := map[string]types.TaskPriority{
+		"!low":    types.TaskPriorityLow,
+		"!medium": types.TaskPriorityMedium,
+		"!med":    types.TaskPriorityMedium,
+		"!HIGH":   types.TaskPriorityHigh,
+	}
+	for token, want := range tests {
+		if task := mustParseQuickAdd(t, "#PROJ-1 "+token); task.Priority != want {
+			t.Errorf("%s set priority %q, want %q", token, task.Priority, want)
+		}
+	}
+	if task := mustParseQuickAdd(t, "#PROJ-1"); task.Priority != "" {
+		t.Errorf("got priority %q without a token, want none", task.Priority)
+	}
+	if _, err := parseQuickAdd("#PROJ-1 !urgent", quickAddNow); err == nil {
+		t.Error("an unknown priority was accepted")
+	}
+}
+
+func TestParseQuickAddScheduledDate(t *testing.T) {
+	tests := map[string]time.Time{
+		"@today":      quickAddDay(time.March, 5),
+		"@tomorrow":   quickAddDay(time.March, 6),
+		"@tuesday":    quickAddDay(time.March, 5),
+		"@fri":        quickAddDay(time.March, 8),
+		"@Monday":     quickAddDay(time.March, 11),
+		"@+3d":        quickAddDay(time.March, 8),
+		"@+2w":        quickAddDay(time.March, 19),
+		"@2024-04-01": quickAddDay(time.April, 1),
+	}
+	for token, want := range tests {
+		task := mustParseQuickAdd(t, "#PROJ-1 "+token)
+		if task.ScheduledDate == nil || !task.ScheduledDate.Equal(want) {
+			t.Errorf("%s scheduled the task for %v, want %v", token, task.ScheduledDate, want)
+		}
+		if task.Deadline != nil {
+			t.Errorf("%s set deadline %v, want none", token, task.Deadline)
+		}
+	}
+	for _, token := range []string{"@someday", "@+3m", "@2024-13-01"} {
+		if _, err := parseQuickAdd("#PROJ-1 "+token, quickAddNow); err == nil {
+			t.Errorf("%s was accepted as a date", token)
+		}
+	}
+}
+
+func TestParseQuickAddDeadline(t *testing.T) {
+	task := mustParseQuickAdd(t, "#PROJ-1 @due:+1w")
+	want := time.Date(2024, time.March, 12, 23, 59, 59, 0, time.UTC)
+	if task.Deadline == nil || !task.Deadline.Equal(want) {
+		t.Errorf("got deadline %v, want the end of %v", task.Deadline, want)
+	}
+	if task.ScheduledDate != nil {
+		t.Errorf("scheduled for %v, want only a deadline", task.ScheduledDate)
+	}
+	if _, err := parseQuickAdd("#PROJ-1 @due:never", quickAddNow); err == nil {
+		t.Error("an invalid deadline was accepted")
+	}
+}
+
+func TestParseQuickAddTitle(t *testing.T) {
+	task := mustParseQuickAdd(t, "Fix #PROJ-123 the  login !high @friday bug @due:+1w")
+	if task.Title != "Fix the login bug" {
+		t.Errorf("got title %q, want the words left between the tokens", task.Title)
+	}
+	// A lone ! or @ is a word, not a token
+	if task := mustParseQuickAdd(t, "Ship it ! @ #PROJ-1"); task.Title != "Ship it ! @" {
+		t.Errorf("got title %q, want \"Ship it ! @\"", task.Title)
+	}
+	if task := mustParseQuickAdd(t, "#PROJ-1 !low"); task.Title != "" {
+		t.Errorf("got title %q from tokens only, want none", task.Title)
+	}
+}
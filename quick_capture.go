@@ -0,0 +1,163 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"dev-dashboard/internal/telemetry"
+	"dev-dashboard/pkg/types"
+)
+
+// quickCapture is what a quick capture line describes
+type quickCapture struct {
+	Title        string
+	JiraTicketID string
+	Project      string // the @project token without the @, empty for the default project
+	Deadline     *time.Time
+}
+
+// parseQuickCapture parses a quick capture line such as "fix login bug @payments #PAY-123 ^friday".
+// Tokens are taken out of the line and the remaining words make the title:
+//   - #TICKET links the JIRA ticket, as in quick add
+//   - @project picks the project whose name starts with it, spaces written as dashes
+//   - ^date sets the deadline, with the dates of quick add (see parseQuickAddDate)
+func parseQuickCapture(input string, now time.Time) (*quickCapture, error) {
+	capture := &quickCapture{}
+	var words []string
+	for _, word := range strings.Fields(input) {
+		switch {
+		case quickAddTicket.MatchString(word):
+			if capture.JiraTicketID != "" {
+				return nil, fmt.Errorf("only one ticket can be linked, got %s and %s", capture.JiraTicketID, word)
+			}
+			capture.JiraTicketID = strings.ToUpper(word[1:])
+		case len(word) > 1 && word[0] == '@':
+			if capture.Project != "" {
+				return nil, fmt.Errorf("only one project can be given, got @%s and %s", capture.Project, word)
+			}
+			capture.Project = strings.ToLower(word[1:])
+		case len(word) > 1 && word[0] == '^':
+			if capture.Deadline != nil {
+				return nil, fmt.Errorf("only one deadline can be given, got two with %s", word)
+			}
+			date, err := parseQuickAddDate(strings.ToLower(word[1:]), now)
+			if err != nil {
+				return nil, err
+			}
+			// Due by the end of the day, as deadlines picked in the task form are
+			deadline := time.Date(date.Year(), date.Month(), date.Day(), 23, 59, 59, 0, date.Location())
+			capture.Deadline = &deadline
+		default:
+			words = append(words, word)
+		}
+	}
+
+	capture.Title = strings.Join(words, " ")
+	// Tasks are unique per project and ticket, so each needs one
+	if capture.JiraTicketID == "" {
+		return nil, fmt.Errorf("link the task's JIRA ticket with #TICKET, e.g. #PROJ-123")
+	}
+	return capture, nil
+}
+
+// quickCaptureProjectToken returns the @token a project is picked with: its name in lower case with
+// dashes for spaces
+func quickCaptureProjectToken(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), "-")
+}
+
+// matchQuickCaptureProject returns the projects an @project token picks: the one whose token it is,
+// or else every project whose token starts with it
+func matchQuickCaptureProject(projects []*types.Project, token string) []*types.Project {
+	var exact, prefixed []*types.Project
+	for _, project := range projects {
+		name := quickCaptureProjectToken(project.Name)
+		if name == token {
+			exact = append(exact, project)
+		} else if strings.HasPrefix(name, token) {
+			prefixed = append(prefixed, project)
+		}
+	}
+	if len(exact) > 0 {
+		return exact
+	}
+	return prefixed
+}
+
+// quickCaptureProjectSuggestion offers projects to replace an @project token with
+func quickCaptureProjectSuggestion(token, message string, projects []*types.Project) *types.QuickCaptureSuggestion {
+	suggestion := &types.QuickCaptureSuggestion{Field: "project", Token: token, Message: message, Options: []types.QuickCaptureOption{}}
+	for _, project := range projects {
+		suggestion.Options = append(suggestion.Options, types.QuickCaptureOption{
+			Label: project.Name,
+			Token: "@" + quickCaptureProjectToken(project.Name),
+		})
+	}
+	return suggestion
+}
+
+// QuickCaptureTask creates a task from a quick capture line such as
+// "fix login bug @payments #PAY-123 ^friday" (see parseQuickCapture) and returns what was inferred
+// from it so the capture popup can confirm. Without an @project the task goes to the quick add
+// default project. When the project can't be told, no task is created and the result suggests the
+// projects to pick from instead. The task is scheduled for today.
+func (a *App) QuickCaptureTask(text string) (*types.QuickCaptureResult, error) {
+	if a.taskModel == nil || a.projectModel == nil {
+		return nil, fmt.Errorf("task model not initialized")
+	}
+	a.recordFeature(telemetry.QuickCapture)
+	parsed, err := parseQuickCapture(text, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	result := &types.QuickCaptureResult{
+		JiraTicketID: parsed.JiraTicketID,
+		Title:        parsed.Title,
+		Deadline:     parsed.Deadline,
+		Suggestions:  []*types.QuickCaptureSuggestion{},
+	}
+
+	projects, err := a.projectModel.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	var project *types.Project
+	if parsed.Project == "" {
+		if project, err = a.defaultTaskProject(); err != nil {
+			result.Suggestions = append(result.Suggestions, quickCaptureProjectSuggestion("", "No default project is set; add an @project", projects))
+			return result, nil
+		}
+	} else {
+		token := "@" + parsed.Project
+		switch matches := matchQuickCaptureProject(projects, parsed.Project); len(matches) {
+		case 0:
+			result.Suggestions = append(result.Suggestions, quickCaptureProjectSuggestion(token, fmt.Sprintf("No project starts with %q", parsed.Project), projects))
+			return result, nil
+		case 1:
+			project = matches[0]
+		default:
+			result.Suggestions = append(result.Suggestions, quickCaptureProjectSuggestion(token, fmt.Sprintf("%d projects start with %q", len(matches), parsed.Project), matches))
+			return result, nil
+		}
+	}
+	result.ProjectID = project.ID
+	result.ProjectName = project.Name
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	task := types.Task{
+		ProjectID:     project.ID,
+		JiraTicketID:  parsed.JiraTicketID,
+		Title:         parsed.Title,
+		ScheduledDate: &today,
+		Deadline:      parsed.Deadline,
+		Status:        types.TaskPending,
+	}
+	if err := a.createTaskWithJiraFields(&task); err != nil {
+		return nil, err
+	}
+	result.Task = &task
+	result.Title = task.Title
+	return result, nil
+}
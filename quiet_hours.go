@@ -0,0 +1,85 @@
+package main
+
+import (
+	"log"
+	"strings"
+
+	"dev-dashboard/internal/sync"
+)
+
+const (
+	// quietHoursKey is the daily window, e.g. "22:00-07:00" in local time, during which
+	// notifications are held back; empty turns quiet hours off
+	quietHoursKey = "quiet_hours"
+	// quietHoursEnvironmentsKey overrides the window per environment, e.g. "dev=20:00-09:00,prd=off"
+	quietHoursEnvironmentsKey = "quiet_hours_environments"
+	// quietHoursExemptEnvironmentsKey and quietHoursExemptTypesKey list the environments and
+	// notification types that are delivered even during quiet hours
+	quietHoursExemptEnvironmentsKey = "quiet_hours_exempt_environments"
+	quietHoursExemptTypesKey        = "quiet_hours_exempt_types"
+	// quietHoursModeKey is "queue" (the default) to deliver held back notifications when the window
+	// ends, or "suppress" to drop them
+	quietHoursModeKey = "quiet_hours_mode"
+)
+
+func isQuietHoursKey(key string) bool {
+	switch key {
+	case quietHoursKey, quietHoursEnvironmentsKey, quietHoursExemptEnvironmentsKey, quietHoursExemptTypesKey, quietHoursModeKey:
+		return true
+	}
+	return false
+}
+
+// getQuietHours reads the quiet hours from config, or nil when no window is configured. Invalid
+// values are logged and ignored.
+func (a *App) getQuietHours() *sync.QuietHours {
+	config, err := a.GetAllConfig()
+	if err != nil {
+		log.Printf("Failed to read quiet hours: %v", err)
+		return nil
+	}
+
+	window, err := sync.ParseQuietWindow(config[quietHoursKey])
+	if err != nil {
+		log.Printf("Ignoring %s: %v", quietHoursKey, err)
+	}
+	environments, err := sync.ParseEnvironmentQuietWindows(config[quietHoursEnvironmentsKey])
+	if err != nil {
+		log.Printf("Ignoring %s: %v", quietHoursEnvironmentsKey, err)
+	}
+	if window == nil && len(environments) == 0 {
+		return nil
+	}
+
+	return &sync.QuietHours{
+		Window:             window,
+		Environments:       environments,
+		ExemptEnvironments: commaSet(config[quietHoursExemptEnvironmentsKey], strings.ToLower),
+		ExemptTypes:        commaSet(config[quietHoursExemptTypesKey], nil),
+		Suppress:           config[quietHoursModeKey] == "suppress",
+	}
+}
+
+// applyQuietHours hands the configured quiet hours to the notifier
+func (a *App) applyQuietHours() {
+	if a.notifier != nil {
+		a.notifier.SetQuietHours(a.getQuietHours())
+	}
+}
+
+// commaSet splits a comma-separated config value into a set, normalizing entries with normalize
+// when given
+func commaSet(value string, normalize func(string) string) map[string]bool {
+	set := make(map[string]bool)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if normalize != nil {
+			entry = normalize(entry)
+		}
+		set[entry] = true
+	}
+	return set
+}
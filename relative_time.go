@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"dev-dashboard/internal/timefmt"
+	"dev-dashboard/pkg/types"
+)
+
+// timezoneKey is the IANA time zone (e.g. "Europe/Berlin") relative timestamps are shown in;
+// empty uses the system's local time zone
+const timezoneKey = "timezone"
+
+func validateTimezone(value string) error {
+	if _, err := time.LoadLocation(value); err != nil {
+		return fmt.Errorf("%s must be an IANA time zone such as Europe/Berlin, got %q", timezoneKey, value)
+	}
+	return nil
+}
+
+// displayClock formats timestamps relative to one moment, so all entries of a response agree
+type displayClock struct {
+	now time.Time
+	loc *time.Location
+}
+
+// displayClock returns a clock at the current time in the configured time zone
+func (a *App) displayClock() displayClock {
+	loc := time.Local
+	if value, err := a.GetConfig(timezoneKey); err == nil && value != "" {
+		if configured, err := time.LoadLocation(value); err == nil {
+			loc = configured
+		} else {
+			log.Printf("Ignoring %s %q: %v", timezoneKey, value, err)
+		}
+	}
+	return displayClock{now: time.Now(), loc: loc}
+}
+
+func (c displayClock) relative(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return timefmt.Relative(t, c.now, c.loc)
+}
+
+func (c displayClock) relativePtr(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return c.relative(*t)
+}
+
+func (c displayClock) annotateAction(action *types.Action) {
+	action.StartedAtRelative = c.relative(action.StartedAt)
+	action.CompletedAtRelative = c.relativePtr(action.CompletedAt)
+}
+
+func (c displayClock) annotateActions(actions []*types.Action) {
+	for _, action := range actions {
+		c.annotateAction(action)
+	}
+}
+
+func (c displayClock) annotateActionsWithDetails(actions []*types.ActionWithDetails) {
+	for _, action := range actions {
+		c.annotateAction(&action.Action)
+	}
+}
+
+func (c displayClock) annotateDeployments(deployments []*types.DeploymentOverview) {
+	for _, deployment := range deployments {
+		deployment.UpdatedAtRelative = c.relative(deployment.UpdatedAt)
+	}
+}
+
+// annotateCommits returns annotated copies of the commits, which may be shared with the service
+// data cache
+func (c displayClock) annotateCommits(commits []*types.Commit) []*types.Commit {
+	if commits == nil {
+		return nil
+	}
+	annotated := make([]*types.Commit, len(commits))
+	for i, commit := range commits {
+		copied := *commit
+		copied.DateRelative = c.relative(commit.Date)
+		annotated[i] = &copied
+	}
+	return annotated
+}
+
+func (c displayClock) annotateAnnotations(annotations []*types.Annotation) {
+	for _, annotation := range annotations {
+		annotation.CreatedAtRelative = c.relative(annotation.CreatedAt)
+	}
+}
+
+func (c displayClock) annotateCommitDeployments(statuses []*types.CommitDeploymentStatus) {
+	for _, status := range statuses {
+		status.Commit.DateRelative = c.relative(status.Commit.Date)
+		for i := range status.Deployments {
+			status.Deployments[i].DeployedAtRelative = c.relativePtr(status.Deployments[i].DeployedAt)
+		}
+	}
+}
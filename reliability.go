@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"dev-dashboard/internal/models"
+	"dev-dashboard/pkg/types"
+)
+
+const (
+	outcomeSuccess   = "success"
+	outcomeFailure   = "failure"
+	outcomeCancelled = "cancelled"
+)
+
+// actionOutcome maps a workflow run conclusion onto success, failure or cancelled.
+// Other conclusions (skipped, neutral, or none recorded) return "" and aren't counted.
+func actionOutcome(conclusion string) string {
+	switch conclusion {
+	case "success":
+		return outcomeSuccess
+	case "failure", "timed_out", "startup_failure":
+		return outcomeFailure
+	case "cancelled":
+		return outcomeCancelled
+	default:
+		return ""
+	}
+}
+
+// GetServiceReliability computes build and deployment success rates, the current streak, the mean time
+// between failures and a daily series for a service over the last days days. Cancelled runs count as
+// unsuccessful unless the reliability_exclude_cancelled config flag is set.
+func (a *App) GetServiceReliability(serviceID int64, days int) (*types.ServiceReliability, error) {
+	if a.actionModel == nil {
+		return nil, fmt.Errorf("action model not initialized")
+	}
+	if days <= 0 {
+		return nil, fmt.Errorf("days must be positive")
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	start := today.AddDate(0, 0, -(days - 1))
+	excludeCancelled := a.getConfigFlag("reliability_exclude_cancelled")
+
+	builds, err := a.actionModel.GetCompletedByServiceSince(serviceID, types.BuildAction, start)
+	if err != nil {
+		return nil, err
+	}
+	deployments, err := a.actionModel.GetCompletedByServiceSince(serviceID, types.DeploymentAction, start)
+	if err != nil {
+		return nil, err
+	}
+
+	reliability := &types.ServiceReliability{
+		ServiceID:        serviceID,
+		Since:            start,
+		ExcludeCancelled: excludeCancelled,
+		Build:            summarizeReliability(types.BuildAction, builds, excludeCancelled),
+		Deployment:       summarizeReliability(types.DeploymentAction, deployments, excludeCancelled),
+		Daily:            []types.ReliabilityPoint{},
+	}
+
+	buildDays := groupByDay(builds)
+	deploymentDays := groupByDay(deployments)
+	for day := start; !day.After(today); day = day.AddDate(0, 0, 1) {
+		date := day.Format(models.StatsDateFormat)
+		build := summarizeReliability(types.BuildAction, buildDays[date], excludeCancelled)
+		deployment := summarizeReliability(types.DeploymentAction, deploymentDays[date], excludeCancelled)
+		reliability.Daily = append(reliability.Daily, types.ReliabilityPoint{
+			Date:                  date,
+			BuildRuns:             build.Runs,
+			BuildSuccessRate:      build.SuccessRate,
+			DeploymentRuns:        deployment.Runs,
+			DeploymentSuccessRate: deployment.SuccessRate,
+		})
+	}
+
+	return reliability, nil
+}
+
+// summarizeReliability aggregates completed actions, which must be ordered oldest first
+func summarizeReliability(actionType types.ActionType, actions []*types.Action, excludeCancelled bool) types.ActionReliability {
+	summary := types.ActionReliability{Type: actionType}
+
+	var failures []time.Time
+	for _, action := range actions {
+		outcome := actionOutcome(action.Conclusion)
+		switch outcome {
+		case outcomeSuccess:
+			summary.Succeeded++
+		case outcomeFailure:
+			summary.Failed++
+			failures = append(failures, action.StartedAt)
+		case outcomeCancelled:
+			summary.Cancelled++
+		default:
+			continue
+		}
+
+		// The streak only follows successes and failures; cancelled runs neither extend nor break it
+		if outcome == outcomeCancelled {
+			continue
+		}
+		if outcome == summary.CurrentStreakOutcome {
+			summary.CurrentStreak++
+		} else {
+			summary.CurrentStreakOutcome = outcome
+			summary.CurrentStreak = 1
+		}
+	}
+
+	summary.Runs = summary.Succeeded + summary.Failed
+	if !excludeCancelled {
+		summary.Runs += summary.Cancelled
+	}
+	if summary.Runs > 0 {
+		rate := float64(summary.Succeeded) / float64(summary.Runs)
+		summary.SuccessRate = &rate
+	}
+
+	if len(failures) > 1 {
+		mean := int64(failures[len(failures)-1].Sub(failures[0]).Seconds()) / int64(len(failures)-1)
+		summary.MeanTimeBetweenFailuresSeconds = &mean
+	}
+
+	return summary
+}
+
+func groupByDay(actions []*types.Action) map[string][]*types.Action {
+	byDay := make(map[string][]*types.Action)
+	for _, action := range actions {
+		date := action.StartedAt.In(time.Local).Format(models.StatsDateFormat)
+		byDay[date] = append(byDay[date], action)
+	}
+	return byDay
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"dev-dashboard/pkg/types"
+)
+
+// accessStateOrder lists access states from most to least in need of attention
+var accessStateOrder = map[types.RepositoryAccess]int{
+	types.AccessTokenMissing: 0,
+	types.AccessForbidden:    1,
+	types.AccessNotFound:     2,
+	types.AccessOK:           3,
+}
+
+// GetAccessReport summarizes which tracked repositories the GitHub token can still see, from the
+// access state recorded by the last sync of each: repositories by state, each with the HTTP status,
+// the reason, when a forbidden repository is retried next and when it last synced successfully
+func (a *App) GetAccessReport() (*types.RepositoryAccessReport, error) {
+	if a.repoModel == nil {
+		return nil, fmt.Errorf("repository model not initialized")
+	}
+
+	repos, err := a.repoModel.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &types.RepositoryAccessReport{
+		GeneratedAt:     time.Now(),
+		TokenConfigured: a.getGitHubToken() != "",
+		Counts:          make(map[string]int),
+		Repositories:    make([]*types.RepositoryAccessEntry, 0, len(repos)),
+	}
+	for _, repo := range repos {
+		report.Counts[string(repo.AccessState)]++
+		report.Repositories = append(report.Repositories, &types.RepositoryAccessEntry{
+			RepositoryID: repo.ID,
+			Name:         repo.Name,
+			URL:          repo.URL,
+			Status:       repo.Status,
+			State:        repo.AccessState,
+			HTTPStatus:   repo.AccessStatus,
+			Reason:       repo.LastSyncError,
+			CheckedAt:    repo.AccessCheckedAt,
+			RetryAt:      repo.AccessRetryAt,
+			LastSyncAt:   repo.LastSyncAt,
+		})
+	}
+
+	sort.SliceStable(report.Repositories, func(i, j int) bool {
+		x, y := report.Repositories[i], report.Repositories[j]
+		if accessStateOrder[x.State] != accessStateOrder[y.State] {
+			return accessStateOrder[x.State] < accessStateOrder[y.State]
+		}
+		return x.Name < y.Name
+	})
+	return report, nil
+}
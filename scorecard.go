@@ -0,0 +1,320 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"dev-dashboard/internal/scorecard"
+	"dev-dashboard/pkg/types"
+)
+
+const (
+	// scorecardDisabledChecksKey is a comma separated list of check IDs that don't run
+	scorecardDisabledChecksKey = "scorecard_disabled_checks"
+	// scorecardThresholdKeyPrefix followed by a check ID overrides the check's threshold
+	scorecardThresholdKeyPrefix = "scorecard_threshold_"
+
+	scorecardBuildWindow = 30 * 24 * time.Hour
+)
+
+// getScorecardConfig reads the enabled checks and thresholds from the config
+func (a *App) getScorecardConfig() scorecard.Config {
+	config := scorecard.Config{
+		Disabled:   make(map[string]bool),
+		Thresholds: make(map[string]float64),
+	}
+	if a.configModel == nil {
+		return config
+	}
+
+	if value, err := a.configModel.Get(scorecardDisabledChecksKey); err == nil && value != nil {
+		for _, id := range strings.Split(value.Value, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				config.Disabled[id] = true
+			}
+		}
+	}
+	for _, check := range scorecard.Checks() {
+		if check.ThresholdUnit == "" {
+			continue
+		}
+		value, err := a.configModel.Get(scorecardThresholdKeyPrefix + check.ID)
+		if err != nil || value == nil {
+			continue
+		}
+		if threshold, err := strconv.ParseFloat(value.Value, 64); err == nil {
+			config.Thresholds[check.ID] = threshold
+		}
+	}
+	return config
+}
+
+// scorecardInput gathers the local data a service is scored on: its metadata, deployment history,
+// recorded builds and the pull requests cached from the last time they were fetched
+func (a *App) scorecardInput(service *types.Microservice, now time.Time) (*scorecard.Input, error) {
+	input := &scorecard.Input{
+		Service:            service,
+		Now:                now,
+		PrimaryEnvironment: a.getPrimaryEnvironment(service.ID),
+		BuildWindow:        scorecardBuildWindow,
+	}
+
+	history, err := a.deploymentModel.GetHistoryByServiceID(service.ID, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range history {
+		if isPrimaryEnvironment(input.PrimaryEnvironment, entry.Environment) {
+			observedAt := entry.ObservedAt
+			input.LastPrimaryDeployAt = &observedAt
+		}
+	}
+
+	builds, err := a.actionModel.GetCompletedByServiceSince(service.ID, types.BuildAction, now.Add(-scorecardBuildWindow))
+	if err != nil {
+		return nil, err
+	}
+	reliability := summarizeReliability(types.BuildAction, builds, a.getConfigFlag("reliability_exclude_cancelled"))
+	input.BuildRuns = reliability.Runs
+	input.BuildSuccessRate = reliability.SuccessRate
+
+	if prs, _, ok := a.serviceDataCache.getPullRequests(service.ID); ok {
+		input.OpenPullRequests = []*types.PullRequest{}
+		for _, pr := range prs {
+			if pr.Status == "open" {
+				input.OpenPullRequests = append(input.OpenPullRequests, pr)
+			}
+		}
+	}
+
+	return input, nil
+}
+
+// evaluateScorecard scores a service and stores the result
+func (a *App) evaluateScorecard(service *types.Microservice, config scorecard.Config, now time.Time) (*types.ServiceScorecard, error) {
+	input, err := a.scorecardInput(service, now)
+	if err != nil {
+		return nil, err
+	}
+
+	result := scorecard.Evaluate(input, config)
+	if err := a.scorecardModel.Save(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// updateScorecards rescores every visible service. It runs at the end of each sync pass and only
+// reads local data, so it makes no GitHub requests.
+func (a *App) updateScorecards() {
+	if a.scorecardModel == nil || a.serviceModel == nil {
+		return
+	}
+
+	services, err := a.serviceModel.GetAll()
+	if err != nil {
+		log.Printf("Failed to get services for scorecards: %v", err)
+		return
+	}
+
+	config := a.getScorecardConfig()
+	now := time.Now()
+	for _, service := range services {
+		if service.IsHidden {
+			continue
+		}
+		if _, err := a.evaluateScorecard(service, config, now); err != nil {
+			log.Printf("Failed to evaluate scorecard of %s: %v", service.Name, err)
+		}
+	}
+}
+
+// GetServiceScorecard returns a service's scorecard from the last sync pass, scoring the service
+// now if it hasn't been scored yet
+func (a *App) GetServiceScorecard(serviceID int64) (*types.ServiceScorecard, error) {
+	if a.scorecardModel == nil {
+		return nil, fmt.Errorf("scorecard model not initialized")
+	}
+
+	stored, err := a.scorecardModel.GetByServiceID(serviceID)
+	if err != nil {
+		return nil, err
+	}
+	if stored != nil {
+		nameScorecardResults(stored)
+		return stored, nil
+	}
+
+	service, err := a.serviceModel.GetByID(serviceID)
+	if err != nil {
+		return nil, fmt.Errorf("service not found: %w", err)
+	}
+	return a.evaluateScorecard(service, a.getScorecardConfig(), time.Now())
+}
+
+// GetScorecardSummary returns the scorecards of all visible services, worst grade first, with
+// the number of services per grade and how each check fares across services
+func (a *App) GetScorecardSummary() (*types.ScorecardSummary, error) {
+	if a.scorecardModel == nil {
+		return nil, fmt.Errorf("scorecard model not initialized")
+	}
+
+	services, err := a.serviceModel.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	stored, err := a.scorecardModel.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	byService := make(map[int64]*types.ServiceScorecard, len(stored))
+	for _, scorecard := range stored {
+		byService[scorecard.ServiceID] = scorecard
+	}
+
+	summary := &types.ScorecardSummary{
+		Scorecards:  []types.ServiceScorecard{},
+		GradeCounts: make(map[string]int),
+		Checks:      []types.ScorecardCheckSummary{},
+	}
+	checkSummaries := make(map[string]*types.ScorecardCheckSummary)
+	config := a.getScorecardConfig()
+	now := time.Now()
+	for _, service := range services {
+		if service.IsHidden {
+			continue
+		}
+		result, ok := byService[service.ID]
+		if !ok {
+			if result, err = a.evaluateScorecard(service, config, now); err != nil {
+				log.Printf("Failed to evaluate scorecard of %s: %v", service.Name, err)
+				continue
+			}
+		}
+		nameScorecardResults(result)
+
+		summary.Scorecards = append(summary.Scorecards, *result)
+		summary.GradeCounts[result.Grade]++
+		for _, checkResult := range result.Results {
+			checkSummary, ok := checkSummaries[checkResult.CheckID]
+			if !ok {
+				checkSummary = &types.ScorecardCheckSummary{CheckID: checkResult.CheckID, Name: checkResult.Name}
+				checkSummaries[checkResult.CheckID] = checkSummary
+			}
+			switch checkResult.Status {
+			case types.ScorecardPass:
+				checkSummary.Passed++
+			case types.ScorecardFail:
+				checkSummary.Failed++
+			default:
+				checkSummary.Unknown++
+			}
+		}
+	}
+
+	// Worst first; services without a score go last
+	sort.SliceStable(summary.Scorecards, func(i, j int) bool {
+		si, sj := summary.Scorecards[i].Score, summary.Scorecards[j].Score
+		if si == nil || sj == nil {
+			return si != nil && sj == nil
+		}
+		return *si < *sj
+	})
+	for _, check := range scorecard.Checks() {
+		if checkSummary, ok := checkSummaries[check.ID]; ok {
+			summary.Checks = append(summary.Checks, *checkSummary)
+		}
+	}
+
+	return summary, nil
+}
+
+// nameScorecardResults fills in the check names of stored results, which only keep check IDs
+func nameScorecardResults(result *types.ServiceScorecard) {
+	for i := range result.Results {
+		if check, ok := scorecard.Lookup(result.Results[i].CheckID); ok {
+			result.Results[i].Name = check.Name
+		} else {
+			result.Results[i].Name = result.Results[i].CheckID
+		}
+	}
+}
+
+// GetScorecardChecks lists the scorecard checks with their configured state and thresholds
+func (a *App) GetScorecardChecks() []types.ScorecardCheck {
+	config := a.getScorecardConfig()
+
+	var checks []types.ScorecardCheck
+	for _, check := range scorecard.Checks() {
+		checks = append(checks, types.ScorecardCheck{
+			ID:               check.ID,
+			Name:             check.Name,
+			Description:      check.Description,
+			Enabled:          !config.Disabled[check.ID],
+			HasThreshold:     check.ThresholdUnit != "",
+			Threshold:        config.Threshold(check),
+			DefaultThreshold: check.DefaultThreshold,
+			ThresholdUnit:    check.ThresholdUnit,
+		})
+	}
+	return checks
+}
+
+// SetScorecardCheck enables or disables a check and sets its threshold (ignored for checks without
+// one), then rescores every service
+func (a *App) SetScorecardCheck(checkID string, enabled bool, threshold float64) error {
+	if a.configModel == nil {
+		return fmt.Errorf("config model not initialized")
+	}
+	check, ok := scorecard.Lookup(checkID)
+	if !ok {
+		return fmt.Errorf("unknown scorecard check %q", checkID)
+	}
+	if check.ThresholdUnit != "" && threshold < 0 {
+		return fmt.Errorf("threshold must not be negative")
+	}
+
+	disabled := a.getScorecardConfig().Disabled
+	disabled[checkID] = !enabled
+	var ids []string
+	for _, other := range scorecard.Checks() {
+		if disabled[other.ID] {
+			ids = append(ids, other.ID)
+		}
+	}
+	if err := a.configModel.Set(scorecardDisabledChecksKey, strings.Join(ids, ",")); err != nil {
+		return err
+	}
+
+	if check.ThresholdUnit != "" {
+		if err := a.configModel.Set(scorecardThresholdKeyPrefix+checkID, strconv.FormatFloat(threshold, 'f', -1, 64)); err != nil {
+			return err
+		}
+	}
+
+	a.updateScorecards()
+	return nil
+}
+
+// SetServiceOwner records the team or person owning a service and rescores it
+func (a *App) SetServiceOwner(serviceID int64, owner string) error {
+	if a.serviceModel == nil {
+		return fmt.Errorf("service model not initialized")
+	}
+	if err := a.serviceModel.SetOwner(serviceID, strings.TrimSpace(owner)); err != nil {
+		return err
+	}
+
+	if a.scorecardModel != nil {
+		if service, err := a.serviceModel.GetByID(serviceID); err == nil {
+			if _, err := a.evaluateScorecard(service, a.getScorecardConfig(), time.Now()); err != nil {
+				log.Printf("Failed to evaluate scorecard of %s: %v", service.Name, err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"dev-dashboard/pkg/types"
+)
+
+// GetSecurityAlerts returns a repository's open Dependabot and code scanning alerts as of the last
+// sync: counts by severity and the most severe alerts of each source. A source GitHub wouldn't
+// list alerts from is marked unavailable with the reason.
+func (a *App) GetSecurityAlerts(repoID int64) ([]*types.SecurityAlertReport, error) {
+	if a.securityAlertModel == nil {
+		return nil, fmt.Errorf("security alert model not initialized")
+	}
+	return a.securityAlertModel.GetByRepositoryID(repoID)
+}
+
+// attachSecurityAlertCounts sets the open alert counts of each repository. Failures are only
+// logged so the repository list still loads.
+func (a *App) attachSecurityAlertCounts(repos []*types.Repository) {
+	if a.securityAlertModel == nil {
+		return
+	}
+	counts, err := a.securityAlertModel.GetCounts()
+	if err != nil {
+		log.Printf("Failed to get security alert counts: %v", err)
+		return
+	}
+	for _, repo := range repos {
+		repo.SecurityAlerts = counts[repo.ID]
+	}
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+
+	"dev-dashboard/internal/models"
+	"dev-dashboard/internal/vcs"
+	"dev-dashboard/pkg/types"
+)
+
+// sensitivePullRequestTracker serializes flagging so pull requests fetched by two views at once
+// aren't notified about twice
+type sensitivePullRequestTracker struct {
+	mu    sync.Mutex
+	model *models.SensitivePullRequestModel
+}
+
+func newSensitivePullRequestTracker(model *models.SensitivePullRequestModel) *sensitivePullRequestTracker {
+	return &sensitivePullRequestTracker{model: model}
+}
+
+// sensitivePathMatcher matches changed files against a repository's patterns, relative to the
+// repository root, and a service's patterns, relative to the service directory
+type sensitivePathMatcher struct {
+	servicePath     string
+	repoPatterns    []string
+	servicePatterns []string
+}
+
+// sensitivePathMatcher returns the matcher for a service's pull requests, or nil when neither the
+// service nor its repository has sensitive paths
+func (a *App) sensitivePathMatcher(service *types.Microservice) *sensitivePathMatcher {
+	repoPatterns, err := a.repoModel.GetSensitivePaths(service.RepositoryID)
+	if err != nil {
+		log.Printf("Failed to get sensitive paths of repository %d: %v", service.RepositoryID, err)
+	}
+	servicePatterns, err := a.serviceModel.GetSensitivePaths(service.ID)
+	if err != nil {
+		log.Printf("Failed to get sensitive paths of service %s: %v", service.Name, err)
+	}
+	if len(repoPatterns) == 0 && len(servicePatterns) == 0 {
+		return nil
+	}
+	return &sensitivePathMatcher{
+		servicePath:     strings.Trim(service.Path, "/"),
+		repoPatterns:    repoPatterns,
+		servicePatterns: servicePatterns,
+	}
+}
+
+// match returns the files that match any sensitive path pattern
+func (m *sensitivePathMatcher) match(files []string) []string {
+	var matched []string
+	for _, file := range files {
+		if m.matches(file) {
+			matched = append(matched, file)
+		}
+	}
+	return matched
+}
+
+func (m *sensitivePathMatcher) matches(file string) bool {
+	if vcs.MatchPathGlobs(m.repoPatterns, file) {
+		return true
+	}
+	relative, ok := strings.CutPrefix(file, m.servicePath+"/")
+	return ok && vcs.MatchPathGlobs(m.servicePatterns, relative)
+}
+
+// flagSensitivePullRequests records the sensitive files of the service's open pull requests and
+// raises a notification for each pull request that touches sensitive files it didn't before,
+// whether it was just opened or a later push added them
+func (a *App) flagSensitivePullRequests(service *types.Microservice, prs []*types.PullRequest) {
+	if a.sensitivePRs == nil {
+		return
+	}
+	a.sensitivePRs.mu.Lock()
+	defer a.sensitivePRs.mu.Unlock()
+
+	previous, err := a.sensitivePRs.model.GetByServiceID(service.ID)
+	if err != nil {
+		log.Printf("Failed to get flagged pull requests of %s: %v", service.Name, err)
+		return
+	}
+
+	// Pull requests missing from this fetch (e.g. their file listing failed) keep their flags, so
+	// they aren't notified about again once they're back
+	flagged := make(map[int][]string)
+	for number, files := range previous {
+		flagged[number] = files
+	}
+	for _, pr := range prs {
+		delete(flagged, pr.Number)
+		if !pr.Sensitive {
+			continue
+		}
+		flagged[pr.Number] = pr.SensitiveFiles
+
+		known := make(map[string]bool)
+		for _, file := range previous[pr.Number] {
+			known[file] = true
+		}
+		var added []string
+		for _, file := range pr.SensitiveFiles {
+			if !known[file] {
+				added = append(added, file)
+			}
+		}
+		if len(added) == 0 {
+			continue
+		}
+
+		repositoryID := service.RepositoryID
+		a.notifier.Notify(&types.Notification{
+			RepositoryID: &repositoryID,
+			Type:         "sensitive_pull_request",
+			Title:        fmt.Sprintf("PR #%d touches sensitive files of %s", pr.Number, service.Name),
+			Message:      fmt.Sprintf("%s by %s changes %s", pr.Title, pr.Author, strings.Join(added, ", ")),
+		})
+	}
+
+	if err := a.sensitivePRs.model.Replace(service.ID, flagged); err != nil {
+		log.Printf("Failed to store flagged pull requests of %s: %v", service.Name, err)
+	}
+}
+
+// normalizeSensitivePaths trims, validates and deduplicates path patterns
+func normalizeSensitivePaths(patterns []string) ([]string, error) {
+	seen := make(map[string]bool)
+	normalized := []string{}
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" || seen[pattern] {
+			continue
+		}
+		if err := vcs.ValidatePathGlob(pattern); err != nil {
+			return nil, err
+		}
+		seen[pattern] = true
+		normalized = append(normalized, pattern)
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
+
+// GetRepositorySensitivePaths returns the path patterns, relative to the repository root, that
+// flag the pull requests changing them
+func (a *App) GetRepositorySensitivePaths(repositoryID int64) ([]string, error) {
+	if a.repoModel == nil {
+		return nil, fmt.Errorf("repository model not initialized")
+	}
+	return a.repoModel.GetSensitivePaths(repositoryID)
+}
+
+// SetRepositorySensitivePaths replaces the repository's sensitive path patterns, e.g.
+// "**/openapi.yaml" or "db/migrations/"
+func (a *App) SetRepositorySensitivePaths(repositoryID int64, patterns []string) error {
+	if a.repoModel == nil {
+		return fmt.Errorf("repository model not initialized")
+	}
+	normalized, err := normalizeSensitivePaths(patterns)
+	if err != nil {
+		return err
+	}
+	return a.repoModel.UpdateSensitivePaths(repositoryID, normalized)
+}
+
+// GetServiceSensitivePaths returns the path patterns, relative to the service directory, that
+// flag the pull requests changing them
+func (a *App) GetServiceSensitivePaths(serviceID int64) ([]string, error) {
+	if a.serviceModel == nil {
+		return nil, fmt.Errorf("service model not initialized")
+	}
+	return a.serviceModel.GetSensitivePaths(serviceID)
+}
+
+// SetServiceSensitivePaths replaces the service's sensitive path patterns, e.g. "openapi.yaml"
+// or "migrations/"
+func (a *App) SetServiceSensitivePaths(serviceID int64, patterns []string) error {
+	if a.serviceModel == nil {
+		return fmt.Errorf("service model not initialized")
+	}
+	normalized, err := normalizeSensitivePaths(patterns)
+	if err != nil {
+		return err
+	}
+	return a.serviceModel.SetSensitivePaths(serviceID, normalized)
+}
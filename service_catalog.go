@@ -0,0 +1,527 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"dev-dashboard/internal/telemetry"
+	"dev-dashboard/pkg/types"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// catalogColumns are the fixed columns of a CSV service catalog; custom fields follow as
+// "custom:<name>" columns
+var catalogColumns = []string{"repository", "name", "path", types.CatalogFieldDescription, types.CatalogFieldOwner, types.CatalogFieldPrimaryEnvironment}
+
+// utf8BOM is written at the start of CSV files by some spreadsheet applications
+const utf8BOM = "\ufeff"
+
+// catalogRow is a service read from a catalog file, with the fields the file sets by catalog field
+// name. Fields it leaves out (a missing column, or a custom field missing from a JSON entry) are left
+// unchanged by the import.
+type catalogRow struct {
+	line       int
+	repository string
+	name       string
+	path       string
+	fields     map[string]string
+}
+
+// ExportServiceCatalog writes the editable metadata of every service to a CSV or JSON file, which
+// ImportServiceCatalog reads back after bulk editing. An empty path opens a save dialog. It returns
+// the file's path, or an empty string when the dialog was cancelled.
+func (a *App) ExportServiceCatalog(path, format string) (string, error) {
+	a.recordFeature(telemetry.ServiceCatalogExport)
+	if format == "" {
+		format = types.ReportCSV
+	}
+	if format != types.ReportCSV && format != types.ReportJSON {
+		return "", fmt.Errorf("unsupported catalog format %q, expected %s or %s", format, types.ReportCSV, types.ReportJSON)
+	}
+	catalog, fields, err := a.serviceCatalog()
+	if err != nil {
+		return "", err
+	}
+	data, err := renderServiceCatalog(catalog, fields, format)
+	if err != nil {
+		return "", err
+	}
+
+	if path == "" {
+		filter := runtime.FileFilter{DisplayName: "CSV (*.csv)", Pattern: "*.csv"}
+		if format == types.ReportJSON {
+			filter = runtime.FileFilter{DisplayName: "JSON (*.json)", Pattern: "*.json"}
+		}
+		path, err = runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+			Title:           "Export service catalog",
+			DefaultFilename: fmt.Sprintf("service-catalog-%s.%s", time.Now().Format("2006-01-02"), format),
+			Filters:         []runtime.FileFilter{filter},
+		})
+		if err != nil || path == "" {
+			return "", err
+		}
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to save service catalog: %w", err)
+	}
+	return path, nil
+}
+
+// PreviewServiceCatalogImport reads a catalog file and returns the changes importing it would make,
+// without applying them. Rows naming unknown services and values that don't validate are listed as
+// problems and skipped; they don't fail the import. An empty path opens a file dialog; nil is
+// returned when it's cancelled.
+func (a *App) PreviewServiceCatalogImport(path string) (*types.ServiceCatalogImport, error) {
+	path, err := a.catalogImportPath(path)
+	if err != nil || path == "" {
+		return nil, err
+	}
+	return a.planServiceCatalogImport(path)
+}
+
+// ImportServiceCatalog applies the changes PreviewServiceCatalogImport lists for a catalog file, all
+// in one transaction. Services are never created or deleted; only their metadata is updated. The
+// file is read again, so the result reflects the file as it is now.
+func (a *App) ImportServiceCatalog(path string) (*types.ServiceCatalogImport, error) {
+	a.recordFeature(telemetry.ServiceCatalogImport)
+	path, err := a.catalogImportPath(path)
+	if err != nil || path == "" {
+		return nil, err
+	}
+	plan, err := a.planServiceCatalogImport(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(plan.Changes) > 0 {
+		if err := a.serviceModel.ApplyCatalogChanges(plan.Changes); err != nil {
+			return nil, err
+		}
+	}
+	plan.Applied = true
+
+	for _, change := range plan.Changes {
+		if change.Field == types.CatalogFieldOwner || change.Field == types.CatalogFieldPrimaryEnvironment {
+			a.updateScorecards()
+			break
+		}
+	}
+	return plan, nil
+}
+
+// catalogImportPath returns path, or asks for a file when it's empty
+func (a *App) catalogImportPath(path string) (string, error) {
+	if path != "" {
+		return path, nil
+	}
+	return runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+		Title: "Import service catalog",
+		Filters: []runtime.FileFilter{
+			{DisplayName: "Service catalog (*.csv, *.json)", Pattern: "*.csv;*.json"},
+		},
+	})
+}
+
+// serviceCatalog returns the catalog of every service, ordered by repository and name, and the
+// service custom fields
+func (a *App) serviceCatalog() (*types.ServiceCatalog, []*types.CustomFieldDefinition, error) {
+	if a.serviceModel == nil || a.repoModel == nil || a.customFieldModel == nil {
+		return nil, nil, fmt.Errorf("service model not initialized")
+	}
+	services, err := a.serviceModel.GetAll()
+	if err != nil {
+		return nil, nil, err
+	}
+	repositories, err := a.repositoryNames()
+	if err != nil {
+		return nil, nil, err
+	}
+	fields, err := a.customFieldModel.GetDefinitions(types.CustomFieldEntityService)
+	if err != nil {
+		return nil, nil, err
+	}
+	values, err := a.customFieldModel.GetValues(types.CustomFieldEntityService)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	catalog := &types.ServiceCatalog{ExportedAt: time.Now(), Services: []*types.ServiceCatalogEntry{}}
+	for _, service := range services {
+		entry := &types.ServiceCatalogEntry{
+			Repository:         repositories[service.RepositoryID],
+			Name:               service.Name,
+			Path:               service.Path,
+			Description:        service.Description,
+			Owner:              service.Owner,
+			PrimaryEnvironment: service.PrimaryEnvironment,
+			CustomFields:       make(map[string]string, len(fields)),
+		}
+		for _, field := range fields {
+			entry.CustomFields[field.Name] = values[service.ID][field.Name]
+		}
+		catalog.Services = append(catalog.Services, entry)
+	}
+	sort.SliceStable(catalog.Services, func(i, j int) bool {
+		if catalog.Services[i].Repository != catalog.Services[j].Repository {
+			return catalog.Services[i].Repository < catalog.Services[j].Repository
+		}
+		return catalog.Services[i].Name < catalog.Services[j].Name
+	})
+	return catalog, fields, nil
+}
+
+// repositoryNames returns the names of all repositories by ID
+func (a *App) repositoryNames() (map[int64]string, error) {
+	repositories, err := a.repoModel.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[int64]string, len(repositories))
+	for _, repository := range repositories {
+		names[repository.ID] = repository.Name
+	}
+	return names, nil
+}
+
+func renderServiceCatalog(catalog *types.ServiceCatalog, fields []*types.CustomFieldDefinition, format string) ([]byte, error) {
+	if format == types.ReportJSON {
+		data, err := json.MarshalIndent(catalog, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode service catalog: %w", err)
+		}
+		return append(data, '\n'), nil
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	header := append([]string{}, catalogColumns...)
+	for _, field := range fields {
+		header = append(header, types.CatalogCustomFieldPrefix+field.Name)
+	}
+	w.Write(header)
+	for _, entry := range catalog.Services {
+		record := []string{entry.Repository, entry.Name, entry.Path, entry.Description, entry.Owner, entry.PrimaryEnvironment}
+		for _, field := range fields {
+			record = append(record, entry.CustomFields[field.Name])
+		}
+		w.Write(record)
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("failed to encode service catalog: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// planServiceCatalogImport reads a catalog file and computes the changes importing it makes
+func (a *App) planServiceCatalogImport(path string) (*types.ServiceCatalogImport, error) {
+	if a.serviceModel == nil || a.repoModel == nil || a.customFieldModel == nil {
+		return nil, fmt.Errorf("service model not initialized")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read service catalog: %w", err)
+	}
+
+	plan := &types.ServiceCatalogImport{
+		Path:     path,
+		Format:   catalogFormat(path, data),
+		Changes:  []*types.ServiceCatalogChange{},
+		Problems: []*types.ServiceCatalogProblem{},
+	}
+	problem := func(line int, format string, args ...interface{}) {
+		plan.Problems = append(plan.Problems, &types.ServiceCatalogProblem{Row: line, Message: fmt.Sprintf(format, args...)})
+	}
+
+	var rows []*catalogRow
+	if plan.Format == types.ReportJSON {
+		rows, err = parseJSONCatalog(data, problem)
+	} else {
+		rows, err = parseCSVCatalog(data, problem)
+	}
+	if err != nil {
+		return nil, err
+	}
+	plan.Rows = len(rows)
+
+	services, err := a.serviceModel.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	repositories, err := a.repositoryNames()
+	if err != nil {
+		return nil, err
+	}
+	fields, err := a.customFieldModel.GetDefinitions(types.CustomFieldEntityService)
+	if err != nil {
+		return nil, err
+	}
+	values, err := a.customFieldModel.GetValues(types.CustomFieldEntityService)
+	if err != nil {
+		return nil, err
+	}
+
+	byKey := make(map[string][]*types.Microservice)
+	for _, service := range services {
+		key := service.Name + "|" + service.Path
+		byKey[key] = append(byKey[key], service)
+	}
+	listedOn := make(map[int64]int)
+
+	for _, row := range rows {
+		if row.name == "" {
+			problem(row.line, "name is required to identify a service")
+			continue
+		}
+		var matches []*types.Microservice
+		for _, service := range byKey[row.name+"|"+row.path] {
+			if row.repository == "" || strings.EqualFold(repositories[service.RepositoryID], row.repository) {
+				matches = append(matches, service)
+			}
+		}
+		if len(matches) == 0 {
+			problem(row.line, "unknown service %s at path %q; imports don't create services", row.name, row.path)
+			continue
+		}
+		if len(matches) > 1 {
+			problem(row.line, "%s at path %q exists in %d repositories; set the repository column", row.name, row.path, len(matches))
+			continue
+		}
+		service := matches[0]
+		if line, ok := listedOn[service.ID]; ok {
+			problem(row.line, "%s is already listed on row %d", service.Name, line)
+			continue
+		}
+		listedOn[service.ID] = row.line
+
+		changes := 0
+		change := func(field string, fieldID int64, old, new string) {
+			if old == new {
+				return
+			}
+			plan.Changes = append(plan.Changes, &types.ServiceCatalogChange{
+				Row: row.line, ServiceID: service.ID, ServiceName: service.Name,
+				Field: field, FieldID: fieldID, Old: old, New: new,
+			})
+			changes++
+		}
+
+		for _, name := range []string{types.CatalogFieldDescription, types.CatalogFieldOwner, types.CatalogFieldPrimaryEnvironment} {
+			value, ok := row.fields[name]
+			if !ok {
+				continue
+			}
+			var old string
+			switch name {
+			case types.CatalogFieldDescription:
+				old = service.Description
+			case types.CatalogFieldOwner:
+				old = service.Owner
+			case types.CatalogFieldPrimaryEnvironment:
+				old = service.PrimaryEnvironment
+			}
+			change(name, 0, old, strings.TrimSpace(value))
+		}
+
+		names := make([]string, 0, len(row.fields))
+		for name := range row.fields {
+			if strings.HasPrefix(name, types.CatalogCustomFieldPrefix) {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fieldName := strings.TrimPrefix(name, types.CatalogCustomFieldPrefix)
+			field := customFieldByName(fields, fieldName)
+			if field == nil {
+				// Unknown CSV columns are reported once, against the header
+				if plan.Format == types.ReportJSON {
+					problem(row.line, "unknown custom field %q is ignored", fieldName)
+				}
+				continue
+			}
+			value, err := normalizeCustomFieldValue(field, row.fields[name])
+			if err != nil {
+				problem(row.line, "%s: %v", service.Name, err)
+				continue
+			}
+			change(types.CatalogCustomFieldPrefix+field.Name, field.ID, values[service.ID][field.Name], value)
+		}
+
+		if changes == 0 {
+			plan.Unchanged++
+		}
+	}
+	return plan, nil
+}
+
+// catalogFormat tells a catalog's format from its extension, or from its content when the extension
+// is neither .csv nor .json
+func catalogFormat(path string, data []byte) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return types.ReportJSON
+	case ".csv":
+		return types.ReportCSV
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(bytes.TrimPrefix(data, []byte(utf8BOM))), []byte("{")) {
+		return types.ReportJSON
+	}
+	return types.ReportCSV
+}
+
+// parseCSVCatalog reads a CSV catalog. The header must have name and path columns; unknown and
+// repeated columns are reported against the header row and ignored.
+func parseCSVCatalog(data []byte, problem func(int, string, ...interface{})) ([]*catalogRow, error) {
+	r := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte(utf8BOM))))
+	r.FieldsPerRecord = -1
+
+	header, err := r.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("the service catalog is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid service catalog: %w", err)
+	}
+
+	columns := make([]string, len(header)) // catalog field of each column, empty when ignored
+	seen := make(map[string]bool)
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		column := strings.ToLower(name)
+		if strings.HasPrefix(column, types.CatalogCustomFieldPrefix) {
+			column = types.CatalogCustomFieldPrefix + strings.TrimSpace(name[len(types.CatalogCustomFieldPrefix):])
+		} else if !slices.Contains(catalogColumns, column) {
+			problem(1, "unknown column %q is ignored", name)
+			continue
+		}
+		key := strings.ToLower(column)
+		if seen[key] {
+			problem(1, "column %q is repeated; only the first is used", name)
+			continue
+		}
+		seen[key] = true
+		columns[i] = column
+	}
+	if !seen["name"] || !seen["path"] {
+		return nil, fmt.Errorf("invalid service catalog: the name and path columns are required")
+	}
+
+	var rows []*catalogRow
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				return nil, fmt.Errorf("invalid service catalog on line %d: %w", parseErr.Line, parseErr.Err)
+			}
+			return nil, fmt.Errorf("invalid service catalog: %w", err)
+		}
+		line, _ := r.FieldPos(0)
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		if len(record) != len(header) {
+			problem(line, "expected %d columns, got %d", len(header), len(record))
+			continue
+		}
+
+		row := &catalogRow{line: line, fields: make(map[string]string)}
+		for i, column := range columns {
+			value := record[i]
+			switch column {
+			case "":
+			case "repository":
+				row.repository = strings.TrimSpace(value)
+			case "name":
+				row.name = strings.TrimSpace(value)
+			case "path":
+				row.path = strings.TrimSpace(value)
+			default:
+				row.fields[column] = value
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// parseJSONCatalog reads a JSON catalog in the format ExportServiceCatalog writes. Unknown keys of an
+// entry are reported on its row.
+func parseJSONCatalog(data []byte, problem func(int, string, ...interface{})) ([]*catalogRow, error) {
+	var catalog struct {
+		Services []map[string]json.RawMessage `json:"services"`
+	}
+	if err := json.Unmarshal(bytes.TrimPrefix(data, []byte(utf8BOM)), &catalog); err != nil {
+		return nil, fmt.Errorf("invalid service catalog: %w", err)
+	}
+	if catalog.Services == nil {
+		return nil, fmt.Errorf("invalid service catalog: no services list")
+	}
+
+	rows := make([]*catalogRow, 0, len(catalog.Services))
+	for i, entry := range catalog.Services {
+		row := &catalogRow{line: i + 1, fields: make(map[string]string)}
+		keys := make([]string, 0, len(entry))
+		for key := range entry {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		valid := true
+		for _, key := range keys {
+			raw := entry[key]
+			if key == "custom_fields" {
+				var custom map[string]string
+				if err := json.Unmarshal(raw, &custom); err != nil {
+					problem(row.line, "custom_fields must map field names to strings")
+					continue
+				}
+				for name, value := range custom {
+					row.fields[types.CatalogCustomFieldPrefix+strings.TrimSpace(name)] = value
+				}
+				continue
+			}
+			if !slices.Contains(catalogColumns, key) {
+				problem(row.line, "unknown field %q is ignored", key)
+				continue
+			}
+			var value string
+			if err := json.Unmarshal(raw, &value); err != nil {
+				problem(row.line, "%s must be a string", key)
+				if key == "name" || key == "path" || key == "repository" {
+					valid = false
+				}
+				continue
+			}
+			switch key {
+			case "repository":
+				row.repository = strings.TrimSpace(value)
+			case "name":
+				row.name = strings.TrimSpace(value)
+			case "path":
+				row.path = strings.TrimSpace(value)
+			default:
+				row.fields[key] = value
+			}
+		}
+		if valid {
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
@@ -0,0 +1,327 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"dev-dashboard/pkg/types"
+
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// serviceDataFreshFor is how long fetched GitHub data is served without asking GitHub again
+	serviceDataFreshFor = 2 * time.Minute
+
+	githubSectionTimeout   = 20 * time.Second
+	databaseSectionTimeout = 5 * time.Second
+)
+
+// serviceDataCache keeps the last pull requests and commits fetched from GitHub for each service,
+// so the detail page and its sub pages don't refetch them and can fall back to them when GitHub is slow
+type serviceDataCache struct {
+	mu      sync.Mutex
+	entries map[int64]*serviceDataEntry
+}
+
+type serviceDataEntry struct {
+	pullRequests   []*types.PullRequest
+	pullRequestsAt time.Time
+	commits        []*types.Commit
+	commitsAt      time.Time
+}
+
+func newServiceDataCache() *serviceDataCache {
+	return &serviceDataCache{entries: make(map[int64]*serviceDataEntry)}
+}
+
+func (c *serviceDataCache) entry(serviceID int64) *serviceDataEntry {
+	entry, ok := c.entries[serviceID]
+	if !ok {
+		entry = &serviceDataEntry{}
+		c.entries[serviceID] = entry
+	}
+	return entry
+}
+
+func (c *serviceDataCache) getPullRequests(serviceID int64) ([]*types.PullRequest, time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[serviceID]
+	if !ok || entry.pullRequestsAt.IsZero() {
+		return nil, time.Time{}, false
+	}
+	return entry.pullRequests, entry.pullRequestsAt, true
+}
+
+func (c *serviceDataCache) putPullRequests(serviceID int64, prs []*types.PullRequest) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry := c.entry(serviceID)
+	entry.pullRequests = prs
+	entry.pullRequestsAt = time.Now()
+}
+
+func (c *serviceDataCache) getCommits(serviceID int64) ([]*types.Commit, time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[serviceID]
+	if !ok || entry.commitsAt.IsZero() {
+		return nil, time.Time{}, false
+	}
+	return entry.commits, entry.commitsAt, true
+}
+
+func (c *serviceDataCache) putCommits(serviceID int64, commits []*types.Commit) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry := c.entry(serviceID)
+	entry.commits = commits
+	entry.commitsAt = time.Now()
+}
+
+// snapshot returns a copy of every service's cached data
+func (c *serviceDataCache) snapshot() map[int64]serviceDataEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entries := make(map[int64]serviceDataEntry, len(c.entries))
+	for serviceID, entry := range c.entries {
+		entries[serviceID] = *entry
+	}
+	return entries
+}
+
+// GetServiceDetail gathers the service detail page in one call. Sections load concurrently with their
+// own timeouts; a failing section reports its error (and cached data, if any, marked stale) without
+// failing the others. Sections not requested in options are skipped.
+func (a *App) GetServiceDetail(serviceID int64, options types.ServiceDetailOptions) (*types.ServiceDetail, error) {
+	if a.serviceModel == nil || a.repoModel == nil {
+		return nil, fmt.Errorf("service model not initialized")
+	}
+
+	service, err := a.serviceModel.GetByID(serviceID)
+	if err != nil {
+		return nil, err
+	}
+	a.recordUsage(types.UsageServiceOpened, &serviceID)
+	a.attachServiceCustomFields([]*types.Microservice{service})
+
+	repo, err := a.repoModel.GetByID(service.RepositoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.loadServiceDetail(service, repo, options), nil
+}
+
+// loadServiceDetail loads the requested sections of a service's detail concurrently
+func (a *App) loadServiceDetail(service *types.Microservice, repo *types.Repository, options types.ServiceDetailOptions) *types.ServiceDetail {
+	detail := &types.ServiceDetail{
+		Service:    service,
+		Repository: repo,
+	}
+
+	// Sections record their own errors, so no goroutine fails the group
+	var g errgroup.Group
+
+	if options.PullRequests {
+		g.Go(func() error {
+			detail.PullRequests = a.loadPullRequestsSection(service, repo, options.ForceRefresh)
+			return nil
+		})
+	}
+
+	// Commit deployments are built from the same commits, so they're fetched once for both sections
+	if options.Commits || options.CommitDeployments {
+		g.Go(func() error {
+			commits := a.loadCommitsSection(service, repo, options.ForceRefresh)
+			if options.Commits {
+				detail.Commits = commits
+			}
+			if options.CommitDeployments {
+				detail.CommitDeployments = a.loadCommitDeploymentsSection(service, commits)
+			}
+			return nil
+		})
+	}
+
+	if options.Deployments {
+		g.Go(func() error {
+			detail.Deployments = a.loadDeploymentsSection(service)
+			return nil
+		})
+	}
+
+	if options.Actions {
+		g.Go(func() error {
+			detail.Actions = a.loadActionsSection(service, options.ActionsLimit)
+			return nil
+		})
+	}
+
+	g.Wait()
+
+	clock := a.displayClock()
+	detail.Commits.Data = clock.annotateCommits(detail.Commits.Data)
+	a.attachCommitTickets(detail.Commits.Data)
+	clock.annotateCommitDeployments(detail.CommitDeployments.Data)
+	clock.annotateDeployments(detail.Deployments.Data)
+	clock.annotateActions(detail.Actions.Data)
+	a.annotateCommitDeploymentStaleness(detail.CommitDeployments.Data)
+
+	return detail
+}
+
+func (a *App) loadPullRequestsSection(service *types.Microservice, repo *types.Repository, forceRefresh bool) types.PullRequestsSection {
+	section := types.PullRequestsSection{Data: []*types.PullRequest{}, Status: types.SectionStatus{Requested: true}}
+
+	cached, cachedAt, hasCached := a.serviceDataCache.getPullRequests(service.ID)
+	if hasCached && !forceRefresh && time.Since(cachedAt) < serviceDataFreshFor {
+		section.Data = cached
+		section.Status.FetchedAt = &cachedAt
+		return section
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), githubSectionTimeout)
+	defer cancel()
+
+	prs, err := a.fetchServicePullRequests(ctx, service, repo)
+	if err != nil {
+		section.Status.Error = err.Error()
+		if hasCached {
+			section.Data = cached
+			section.Status.Stale = true
+			section.Status.FetchedAt = &cachedAt
+		}
+		return section
+	}
+
+	a.serviceDataCache.putPullRequests(service.ID, prs)
+	a.summarizePullRequests(service.ID, prs)
+	now := time.Now()
+	if prs != nil {
+		section.Data = prs
+	}
+	section.Status.FetchedAt = &now
+	return section
+}
+
+func (a *App) loadCommitsSection(service *types.Microservice, repo *types.Repository, forceRefresh bool) types.CommitsSection {
+	section := types.CommitsSection{Data: []*types.Commit{}, Status: types.SectionStatus{Requested: true}}
+
+	cached, cachedAt, hasCached := a.serviceDataCache.getCommits(service.ID)
+	if hasCached && !forceRefresh && time.Since(cachedAt) < serviceDataFreshFor {
+		section.Data = cached
+		section.Status.FetchedAt = &cachedAt
+		return section
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), githubSectionTimeout)
+	defer cancel()
+
+	commits, err := a.fetchServiceCommits(ctx, service, repo)
+	if err != nil {
+		section.Status.Error = err.Error()
+		if hasCached {
+			section.Data = cached
+			section.Status.Stale = true
+			section.Status.FetchedAt = &cachedAt
+		}
+		return section
+	}
+
+	a.serviceDataCache.putCommits(service.ID, commits)
+	a.recordCommitTicketRefs(repo.ID, commits)
+	a.summarizeCommits(service.ID, commits)
+	now := time.Now()
+	if commits != nil {
+		section.Data = commits
+	}
+	section.Status.FetchedAt = &now
+	return section
+}
+
+// loadCommitDeploymentsSection correlates already loaded commits with the service's deployments,
+// inheriting the commits' stale and error status
+func (a *App) loadCommitDeploymentsSection(service *types.Microservice, commits types.CommitsSection) types.CommitDeploymentsSection {
+	section := types.CommitDeploymentsSection{Data: []*types.CommitDeploymentStatus{}, Status: commits.Status}
+
+	deployments, err := runWithTimeout(databaseSectionTimeout, func() ([]*types.Deployment, error) {
+		return a.deploymentModel.GetByServiceID(service.ID)
+	})
+	if err != nil {
+		section.Status.Error = err.Error()
+		return section
+	}
+
+	if result := buildCommitDeployments(commits.Data, deployments); result != nil {
+		section.Data = result
+	}
+	return section
+}
+
+func (a *App) loadDeploymentsSection(service *types.Microservice) types.DeploymentsSection {
+	section := types.DeploymentsSection{Data: []*types.DeploymentOverview{}, Status: types.SectionStatus{Requested: true}}
+
+	deployments, err := runWithTimeout(databaseSectionTimeout, func() ([]*types.DeploymentOverview, error) {
+		return a.deploymentModel.GetDeploymentOverview(service.ID)
+	})
+	if err != nil {
+		section.Status.Error = err.Error()
+		return section
+	}
+
+	now := time.Now()
+	if deployments != nil {
+		section.Data = deployments
+	}
+	a.attachDeploymentStaleness(service.ID, section.Data)
+	section.Status.FetchedAt = &now
+	return section
+}
+
+func (a *App) loadActionsSection(service *types.Microservice, limit int) types.ActionsSection {
+	section := types.ActionsSection{Data: []*types.Action{}, Status: types.SectionStatus{Requested: true}}
+	if limit == 0 {
+		limit = 50
+	}
+
+	actions, err := runWithTimeout(databaseSectionTimeout, func() ([]*types.Action, error) {
+		return a.actionModel.GetByServiceID(service.ID, limit)
+	})
+	if err != nil {
+		section.Status.Error = err.Error()
+		return section
+	}
+
+	now := time.Now()
+	if actions != nil {
+		section.Data = actions
+	}
+	section.Status.FetchedAt = &now
+	return section
+}
+
+// runWithTimeout runs a query that doesn't take a context, giving up on it after the timeout
+func runWithTimeout[T any](timeout time.Duration, query func() (T, error)) (T, error) {
+	type result struct {
+		value T
+		err   error
+	}
+
+	done := make(chan result, 1)
+	go func() {
+		value, err := query()
+		done <- result{value, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.value, r.err
+	case <-time.After(timeout):
+		var zero T
+		return zero, fmt.Errorf("timed out after %s", timeout)
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"sort"
+
+	"dev-dashboard/pkg/types"
+)
+
+// serviceDomainFoldersKey makes built-in discovery treat the directories under the service root as
+// domain folders holding the services, e.g. services/payments/ledger
+const serviceDomainFoldersKey = "service_domain_folders"
+
+// GetMicroservicesGroupedByDomain returns the services of a repository (0 for all repositories)
+// grouped by their domain folder, domains in alphabetical order. Services directly under the
+// discovery root come last, in the "ungrouped" group.
+func (a *App) GetMicroservicesGroupedByDomain(repositoryID int64, includeHidden bool) ([]*types.ServiceDomainGroup, error) {
+	services, err := a.GetMicroservices(repositoryID, includeHidden)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := []*types.ServiceDomainGroup{}
+	byDomain := make(map[string]*types.ServiceDomainGroup)
+	for _, service := range services {
+		domain := service.Domain
+		if domain == "" {
+			domain = types.UngroupedDomain
+		}
+		group, ok := byDomain[domain]
+		if !ok {
+			group = &types.ServiceDomainGroup{Domain: domain, Services: []*types.Microservice{}}
+			byDomain[domain] = group
+			groups = append(groups, group)
+		}
+		group.Services = append(group.Services, service)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if (groups[i].Domain == types.UngroupedDomain) != (groups[j].Domain == types.UngroupedDomain) {
+			return groups[j].Domain == types.UngroupedDomain
+		}
+		return groups[i].Domain < groups[j].Domain
+	})
+	return groups, nil
+}
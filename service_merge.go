@@ -0,0 +1,22 @@
+package main
+
+import (
+	"fmt"
+
+	"dev-dashboard/pkg/types"
+)
+
+// MergeServices folds a duplicate service into the one kept: its deployments, deployment history,
+// actions and usage move over and the duplicate is deleted. Both must belong to the same
+// repository. It returns how many records moved.
+func (a *App) MergeServices(keepID, mergeID int64) (*types.ServiceMergeResult, error) {
+	if a.serviceModel == nil {
+		return nil, fmt.Errorf("service model not initialized")
+	}
+	result, err := a.serviceModel.Merge(keepID, mergeID)
+	if err != nil {
+		return nil, err
+	}
+	a.refreshServiceSummaries([]int64{keepID})
+	return result, nil
+}
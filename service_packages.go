@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+
+	"dev-dashboard/internal/packages"
+	"dev-dashboard/pkg/types"
+)
+
+// packageVersionLookupKey turns on looking up the latest versions of collected packages in the Go
+// module proxy and the npm registry; off by default since it sends package names to them
+const packageVersionLookupKey = "package_version_lookup"
+
+// SetRepositoryCollectPackages sets whether the sync reads the go.mod and package.json of a
+// monorepo's services. The packages show up after the next sync.
+func (a *App) SetRepositoryCollectPackages(id int64, collect bool) error {
+	if a.repoModel == nil {
+		return fmt.Errorf("repository model not initialized")
+	}
+	return a.repoModel.SetCollectPackages(id, collect)
+}
+
+// GetServicePackages returns the direct dependencies declared in a service's go.mod and
+// package.json, flagging those whose latest version is a newer major version
+func (a *App) GetServicePackages(serviceID int64) (*types.ServicePackages, error) {
+	if a.servicePackageModel == nil {
+		return nil, fmt.Errorf("service package model not initialized")
+	}
+	manifests, err := a.servicePackageModel.GetManifests(serviceID)
+	if err != nil {
+		return nil, err
+	}
+	servicePackages, err := a.servicePackageModel.GetByServiceID(serviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &types.ServicePackages{Manifests: manifests, Packages: servicePackages}
+	for _, pkg := range servicePackages {
+		pkg.MajorBehind = pkg.LatestVersion != "" && packages.MajorBehind(pkg.Version, pkg.LatestVersion)
+		if pkg.MajorBehind {
+			result.MajorBehind++
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"dev-dashboard/pkg/types"
+)
+
+// RediscoverAllServices reruns service discovery for every monorepo with the configured GitHub
+// token, e.g. after changing the discovery roots or globs, and returns what changed in each. Only
+// the services are updated; workflows and deployments aren't synced. A repository failing doesn't
+// stop the others, its Error says why.
+func (a *App) RediscoverAllServices() ([]*types.ServiceRediscovery, error) {
+	if a.repoModel == nil || a.serviceModel == nil {
+		return nil, fmt.Errorf("repository model not initialized")
+	}
+	if a.getGitHubToken() == "" {
+		return nil, fmt.Errorf("GitHub token is required - please configure it in Settings")
+	}
+	repos, err := a.rediscoverableMonorepos()
+	if err != nil {
+		return nil, err
+	}
+	return a.rediscoverMonorepos(context.Background(), repos, func(int, string) {})
+}
+
+// rediscoverableMonorepos returns the repositories stored as monorepos, whose services discovery
+// finds. Kubernetes repositories are left out by their stored type, not guessed from their name.
+func (a *App) rediscoverableMonorepos() ([]*types.Repository, error) {
+	all, err := a.repoModel.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	var repos []*types.Repository
+	for _, repo := range all {
+		if repo.Type == types.MonorepoType {
+			repos = append(repos, repo)
+		}
+	}
+	return repos, nil
+}
+
+// rediscoverMonorepos rediscovers the services of each repository in turn, reporting progress as it
+// goes. A failed repository gets a summary with its error; only cancelling ctx stops the others.
+func (a *App) rediscoverMonorepos(ctx context.Context, repos []*types.Repository, report jobReporter) ([]*types.ServiceRediscovery, error) {
+	results := []*types.ServiceRediscovery{}
+	for i, repo := range repos {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		report(i*100/len(repos), fmt.Sprintf("Rediscovering services of %s", repo.Name))
+		result, err := a.rediscoverRepositoryServices(ctx, repo, "pat", map[string]interface{}{})
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			log.Printf("Failed to rediscover services of %s: %v", repo.Name, err)
+			result = &types.ServiceRediscovery{RepositoryID: repo.ID, RepositoryName: repo.Name, Error: err.Error()}
+		} else {
+			log.Printf("Rediscovered %d services of %s: %d added, %d updated, %d removed", result.Services, repo.Name, result.Added, result.Updated, result.Removed)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
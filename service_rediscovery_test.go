@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"dev-dashboard/internal/testsupport"
+	"dev-dashboard/pkg/types"
+)
+
+func TestRediscoverableMonoreposGoesByStoredType(t *testing.T) {
+	app, db := newTestApp(t)
+	conn := db.GetConn()
+	// A monorepo whose name looks like a kubernetes repository's is still a monorepo
+	monorepo := testsupport.Repository(t, conn, func(repo *types.Repository) {
+		repo.Name = "k8s-operators"
+		repo.URL = "https://github.com/acme/k8s-operators"
+	})
+	testsupport.KubernetesRepository(t, conn)
+
+	repos, err := app.rediscoverableMonorepos()
+	if err != nil {
+		t.Fatalf("rediscoverableMonorepos: %v", err)
+	}
+	if len(repos) != 1 || repos[0].ID != monorepo.ID {
+		t.Errorf("got %d repositories, want only monorepo %s", len(repos), monorepo.Name)
+	}
+}
+
+func TestRediscoverRepositoryServicesLeavesScriptsAndReviewToTheSync(t *testing.T) {
+	app, db := newTestApp(t)
+	conn := db.GetConn()
+	scripted := testsupport.Repository(t, conn)
+	scripted.DiscoveryScript = "./discover.sh"
+	reviewed := testsupport.Repository(t, conn)
+	reviewed.DiscoveryReview = true
+
+	for _, repo := range []*types.Repository{scripted, reviewed} {
+		// Refused before discovery, so the missing token isn't what fails it
+		_, err := app.rediscoverRepositoryServices(context.Background(), repo, "pat", map[string]interface{}{})
+		if err == nil || !strings.Contains(err.Error(), "sync") {
+			t.Errorf("rediscovering %s returned %v, want it left to the sync", repo.Name, err)
+		}
+	}
+}
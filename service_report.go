@@ -0,0 +1,229 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+
+	"dev-dashboard/internal/telemetry"
+	"dev-dashboard/pkg/types"
+)
+
+const (
+	// appVersion matches productVersion in wails.json
+	appVersion = "1.0.0"
+
+	reportCommitLimit = 20
+	reportActionLimit = 20
+)
+
+// reportGenerator identifies the app that generated a report, like a user agent
+func reportGenerator() string {
+	return fmt.Sprintf("dev-dashboard/%s (%s/%s)", appVersion, runtime.GOOS, runtime.GOARCH)
+}
+
+// GenerateServiceReport assembles a service's description, owner, recent commits, open pull requests,
+// deployments and recent build/deploy outcomes into a Markdown or JSON document for handoffs
+func (a *App) GenerateServiceReport(serviceID int64, format string) (string, error) {
+	if a.serviceModel == nil || a.repoModel == nil {
+		return "", fmt.Errorf("service model not initialized")
+	}
+	a.recordFeature(telemetry.ServiceReport)
+	if format == "" {
+		format = types.ReportMarkdown
+	}
+	if format != types.ReportMarkdown && format != types.ReportJSON {
+		return "", fmt.Errorf("unsupported report format %q, expected %s or %s", format, types.ReportMarkdown, types.ReportJSON)
+	}
+
+	service, err := a.serviceModel.GetByID(serviceID)
+	if err != nil {
+		return "", err
+	}
+	repo, err := a.repoModel.GetByID(service.RepositoryID)
+	if err != nil {
+		return "", err
+	}
+
+	detail := a.loadServiceDetail(service, repo, types.ServiceDetailOptions{
+		PullRequests: true,
+		Commits:      true,
+		Deployments:  true,
+		Actions:      true,
+		ActionsLimit: reportActionLimit,
+	})
+
+	report := &types.ServiceReport{
+		GeneratedAt:   time.Now(),
+		GeneratedBy:   reportGenerator(),
+		Service:       service,
+		Repository:    repo.Name,
+		RepositoryURL: repo.URL,
+		Commits:       detail.Commits.Data,
+		PullRequests:  []*types.PullRequest{},
+		Deployments:   detail.Deployments.Data,
+		Actions:       detail.Actions.Data,
+	}
+	if len(report.Commits) > reportCommitLimit {
+		report.Commits = report.Commits[:reportCommitLimit]
+	}
+	for _, pr := range detail.PullRequests.Data {
+		if pr.Status == "open" {
+			report.PullRequests = append(report.PullRequests, pr)
+		}
+	}
+	sections := []struct {
+		name   string
+		status types.SectionStatus
+	}{
+		{"commits", detail.Commits.Status},
+		{"pull requests", detail.PullRequests.Status},
+		{"deployments", detail.Deployments.Status},
+		{"actions", detail.Actions.Status},
+	}
+	for _, section := range sections {
+		if section.status.Error != "" {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("%s: %s", section.name, section.status.Error))
+		}
+	}
+	annotations, err := a.serviceReportAnnotations(report)
+	if err != nil {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("annotations: %v", err))
+	}
+	report.Annotations = annotations
+
+	if format == types.ReportJSON {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to encode report: %w", err)
+		}
+		return string(data), nil
+	}
+	return renderServiceReportMarkdown(report, a.displayClock().loc), nil
+}
+
+// renderServiceReportMarkdown writes the report as Markdown with timestamps in loc
+func renderServiceReportMarkdown(report *types.ServiceReport, loc *time.Location) string {
+	stamp := func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.In(loc).Format("2006-01-02 15:04 MST")
+	}
+
+	var b strings.Builder
+	service := report.Service
+
+	fmt.Fprintf(&b, "# %s\n\n", service.Name)
+	fmt.Fprintf(&b, "_Generated %s by %s_\n\n", stamp(report.GeneratedAt), report.GeneratedBy)
+	if service.Description != "" {
+		fmt.Fprintf(&b, "%s\n\n", service.Description)
+	}
+	fmt.Fprintf(&b, "- **Repository:** [%s](%s)\n", report.Repository, report.RepositoryURL)
+	fmt.Fprintf(&b, "- **Path:** `%s`\n", service.Path)
+	if service.Domain != "" {
+		fmt.Fprintf(&b, "- **Domain:** %s\n", service.Domain)
+	}
+	owner := service.Owner
+	if owner == "" {
+		owner = "unassigned"
+	}
+	fmt.Fprintf(&b, "- **Owner:** %s\n", owner)
+
+	b.WriteString("\n## Deployments\n\n")
+	if len(report.Deployments) == 0 {
+		b.WriteString("Not deployed anywhere.\n")
+	} else {
+		b.WriteString("| Environment | Region | Namespace | Tag | Commit | Updated |\n")
+		b.WriteString("|---|---|---|---|---|---|\n")
+		for _, d := range report.Deployments {
+			tag := d.Tag
+			if !d.Synced {
+				tag = fmt.Sprintf("%s (pending sync, cluster runs %s)", d.Tag, d.ActualTag)
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | `%s` | %s |\n",
+				markdownCell(d.Environment), markdownCell(d.Region), markdownCell(d.Namespace),
+				markdownCell(tag), shortSHA(d.CommitSHA), stamp(d.UpdatedAt))
+		}
+	}
+
+	b.WriteString("\n## Open Pull Requests\n\n")
+	if len(report.PullRequests) == 0 {
+		b.WriteString("None.\n")
+	}
+	for _, pr := range report.PullRequests {
+		fmt.Fprintf(&b, "- #%d %s by %s (`%s`, opened %s)", pr.Number, pr.Title, pr.Author, pr.Branch, stamp(pr.CreatedAt))
+		if pr.Sensitive {
+			fmt.Fprintf(&b, " **sensitive:** %s", strings.Join(pr.SensitiveFiles, ", "))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n## Recent Commits\n\n")
+	if len(report.Commits) == 0 {
+		b.WriteString("None.\n")
+	}
+	for _, commit := range report.Commits {
+		message, _, _ := strings.Cut(commit.Message, "\n")
+		fmt.Fprintf(&b, "- `%s` %s by %s, %s%s\n", shortSHA(commit.Hash), message, commit.Author, stamp(commit.Date), markdownTickets(commit.Tickets))
+	}
+
+	b.WriteString("\n## Recent Builds and Deployments\n\n")
+	if len(report.Actions) == 0 {
+		b.WriteString("None.\n")
+	} else {
+		b.WriteString("| Type | Status | Conclusion | Branch | Commit | Started |\n")
+		b.WriteString("|---|---|---|---|---|---|\n")
+		for _, action := range report.Actions {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | `%s` | %s |\n",
+				action.Type, markdownCell(action.Status), markdownCell(action.Conclusion),
+				markdownCell(action.Branch), shortSHA(action.Commit), stamp(action.StartedAt))
+		}
+	}
+
+	if len(report.Annotations) > 0 {
+		b.WriteString("\n## Annotations\n\n")
+		for _, annotation := range report.Annotations {
+			fmt.Fprintf(&b, "- %s, %s: %s\n", stamp(annotation.CreatedAt), annotation.Subject, strings.ReplaceAll(annotation.Text, "\n", " "))
+		}
+	}
+
+	if len(report.Warnings) > 0 {
+		b.WriteString("\n## Warnings\n\n")
+		for _, warning := range report.Warnings {
+			fmt.Fprintf(&b, "- %s\n", warning)
+		}
+	}
+
+	return b.String()
+}
+
+// shortSHA abbreviates a commit SHA the way GitHub displays it
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+// markdownCell escapes a value for a Markdown table cell
+func markdownCell(value string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(value, "|", "\\|"), "\n", " ")
+}
+
+// markdownTickets lists the JIRA issues of a commit after its line, linked when they have a URL
+func markdownTickets(tickets []types.CommitTicket) string {
+	if len(tickets) == 0 {
+		return ""
+	}
+	links := make([]string, len(tickets))
+	for i, ticket := range tickets {
+		links[i] = ticket.Key
+		if ticket.URL != "" {
+			links[i] = fmt.Sprintf("[%s](%s)", ticket.Key, ticket.URL)
+		}
+	}
+	return " (" + strings.Join(links, ", ") + ")"
+}
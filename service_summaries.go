@@ -0,0 +1,172 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"strings"
+
+	"dev-dashboard/pkg/types"
+)
+
+// GetServiceSummaries returns the services list in one call: every service of a monorepo (or of all
+// monorepos) with its last build and deployment, its deployed environments, and the open pull
+// requests and tasks known from its last fetched pull requests and commits, narrowed by filter.
+func (a *App) GetServiceSummaries(filter types.ServiceSummaryFilter) ([]*types.ServiceSummary, error) {
+	if a.serviceSummaryModel == nil || a.repoModel == nil {
+		return nil, fmt.Errorf("service summary model not initialized")
+	}
+	match, err := a.customFieldMatcher(filter.CustomFields)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries, err := a.serviceSummaryModel.GetByRepositoryID(filter.RepositoryID, filter.IncludeHidden)
+	if err != nil {
+		return nil, err
+	}
+
+	services := make([]*types.Microservice, len(summaries))
+	for i, summary := range summaries {
+		services[i] = &summary.Microservice
+	}
+	a.attachServiceCustomFields(services)
+
+	search := strings.ToLower(strings.TrimSpace(filter.Search))
+	owner := strings.TrimSpace(filter.Owner)
+	rank := a.environmentRank()
+	filtered := []*types.ServiceSummary{}
+	var actions []*types.Action
+	for _, summary := range summaries {
+		if !match(&summary.Microservice) {
+			continue
+		}
+		if filter.BuildStatus != "" && (summary.LastBuild == nil || summary.LastBuild.Status != filter.BuildStatus) {
+			continue
+		}
+		if owner != "" && !strings.EqualFold(summary.Owner, owner) {
+			continue
+		}
+		if search != "" && !strings.Contains(strings.ToLower(summary.Name), search) && !strings.Contains(strings.ToLower(summary.Path), search) {
+			continue
+		}
+
+		sort.SliceStable(summary.Environments, func(i, j int) bool {
+			return rank.less(summary.Environments[i].Environment, summary.Environments[j].Environment)
+		})
+		for _, action := range []*types.Action{summary.LastBuild, summary.LastDeployment} {
+			if action != nil {
+				actions = append(actions, action)
+			}
+		}
+		filtered = append(filtered, summary)
+	}
+	a.displayClock().annotateActions(actions)
+	return filtered, nil
+}
+
+// RebuildServiceSummaries recomputes every service's summary from scratch, for when the services list
+// drifted from the data it summarizes. Open pull request and task counts are kept only for services
+// whose pull requests and commits were fetched since the app started.
+func (a *App) RebuildServiceSummaries() error {
+	if a.serviceSummaryModel == nil {
+		return fmt.Errorf("service summary model not initialized")
+	}
+	if err := a.serviceSummaryModel.Rebuild(); err != nil {
+		return err
+	}
+	for serviceID, entry := range a.serviceDataCache.snapshot() {
+		if !entry.pullRequestsAt.IsZero() {
+			a.summarizePullRequests(serviceID, entry.pullRequests)
+		}
+		if !entry.commitsAt.IsZero() {
+			a.summarizeCommits(serviceID, entry.commits)
+		}
+	}
+	return nil
+}
+
+// refreshServiceSummaries recomputes the summaries of services from their actions and deployments;
+// nil refreshes every service
+func (a *App) refreshServiceSummaries(serviceIDs []int64) {
+	if a.serviceSummaryModel == nil {
+		return
+	}
+	if err := a.serviceSummaryModel.Refresh(serviceIDs); err != nil {
+		log.Printf("Failed to refresh service summaries: %v", err)
+	}
+}
+
+// refreshChangedServiceSummaries patches the summaries a sync's changes affect before the frontend is
+// told about them: the services of repositories whose services or actions changed, and every service
+// when deployments changed, since deployments removed from a kubernetes repository no longer point
+// at their service.
+func (a *App) refreshChangedServiceSummaries(event types.DataChangedEvent) {
+	if a.serviceSummaryModel == nil || a.serviceModel == nil {
+		return
+	}
+	if len(event.Changes[types.EntityDeployments]) > 0 {
+		a.refreshServiceSummaries(nil)
+		return
+	}
+
+	var repositoryIDs []int64
+	for _, entity := range []types.EntityType{types.EntityServices, types.EntityActions} {
+		for _, repositoryID := range event.Changes[entity] {
+			if !slices.Contains(repositoryIDs, repositoryID) {
+				repositoryIDs = append(repositoryIDs, repositoryID)
+			}
+		}
+	}
+	serviceIDs := []int64{}
+	for _, repositoryID := range repositoryIDs {
+		services, err := a.serviceModel.GetByRepositoryID(repositoryID, true)
+		if err != nil {
+			log.Printf("Failed to get services of repository %d for summaries: %v", repositoryID, err)
+			continue
+		}
+		for _, service := range services {
+			serviceIDs = append(serviceIDs, service.ID)
+		}
+	}
+	if len(serviceIDs) > 0 {
+		a.refreshServiceSummaries(serviceIDs)
+	}
+}
+
+// summarizePullRequests records how many of a service's freshly fetched pull requests are open
+func (a *App) summarizePullRequests(serviceID int64, prs []*types.PullRequest) {
+	if a.serviceSummaryModel == nil {
+		return
+	}
+	open := 0
+	for _, pr := range prs {
+		if pr.Status == "open" {
+			open++
+		}
+	}
+	if err := a.serviceSummaryModel.SetOpenPullRequests(serviceID, open); err != nil {
+		log.Printf("Failed to summarize pull requests: %v", err)
+	}
+}
+
+// summarizeCommits records the JIRA issues a service's freshly fetched commits mention, which its
+// open task count is read from
+func (a *App) summarizeCommits(serviceID int64, commits []*types.Commit) {
+	if a.serviceSummaryModel == nil {
+		return
+	}
+	_, extractor := a.commitTicketPattern()
+	keys := []string{}
+	for _, commit := range commits {
+		for _, key := range extractor.Extract(commit.Message) {
+			if !slices.Contains(keys, key) {
+				keys = append(keys, key)
+			}
+		}
+	}
+	if err := a.serviceSummaryModel.SetTicketKeys(serviceID, keys); err != nil {
+		log.Printf("Failed to summarize commits: %v", err)
+	}
+}
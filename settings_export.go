@@ -0,0 +1,368 @@
+package main
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"dev-dashboard/internal/telemetry"
+	"dev-dashboard/pkg/types"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const settingsExportVersion = 1
+
+// isSecretConfigKey reports whether a config key holds a credential
+func isSecretConfigKey(key string) bool {
+	for _, suffix := range []string{"_token", "_password", "_secret"} {
+		if strings.HasSuffix(key, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isMachineConfigKey reports whether a config key describes this machine's GitHub token, where it
+// came from and when it expires, rather than a setting to take to another machine
+func isMachineConfigKey(key string) bool {
+	return key == githubTokenSourceKey || key == githubTokenExpiresAtKey
+}
+
+// ExportSettings returns all config and repository settings as JSON for moving the app to another machine.
+// Secrets are included, left out, or encrypted with a passphrase depending on options.
+func (a *App) ExportSettings(options types.SettingsExportOptions) (string, error) {
+	if a.configModel == nil || a.repoModel == nil {
+		return "", fmt.Errorf("config model not initialized")
+	}
+	a.recordFeature(telemetry.SettingsExport)
+
+	if options.Secrets == "" {
+		options.Secrets = types.SecretsRedact
+	}
+	if options.Secrets == types.SecretsEncrypt && options.Passphrase == "" {
+		return "", fmt.Errorf("a passphrase is required to encrypt secrets")
+	}
+
+	config, err := a.configModel.GetAll()
+	if err != nil {
+		return "", err
+	}
+
+	export := types.SettingsExport{
+		Version:      settingsExportVersion,
+		ExportedAt:   time.Now(),
+		Secrets:      options.Secrets,
+		Config:       make(map[string]string),
+		Repositories: []types.RepositorySettings{},
+	}
+
+	var key []byte
+	if options.Secrets == types.SecretsEncrypt {
+		salt := make([]byte, 16)
+		if _, err := rand.Read(salt); err != nil {
+			return "", fmt.Errorf("failed to generate salt: %w", err)
+		}
+		export.Salt = base64.StdEncoding.EncodeToString(salt)
+		export.EncryptedConfig = make(map[string]string)
+
+		key, err = deriveSettingsKey(options.Passphrase, salt)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	for name, value := range config {
+		if isMachineConfigKey(name) {
+			continue
+		}
+		if !isSecretConfigKey(name) || value == "" {
+			export.Config[name] = value
+			continue
+		}
+
+		switch options.Secrets {
+		case types.SecretsInclude:
+			export.Config[name] = value
+		case types.SecretsEncrypt:
+			encrypted, err := encryptSetting(key, value)
+			if err != nil {
+				return "", err
+			}
+			export.EncryptedConfig[name] = encrypted
+		case types.SecretsRedact:
+			// left out so importing doesn't clear the secret on the other machine
+		default:
+			return "", fmt.Errorf("unknown secrets mode %q", options.Secrets)
+		}
+	}
+
+	repos, err := a.repoModel.GetAll()
+	if err != nil {
+		return "", err
+	}
+	for _, repo := range repos {
+		export.Repositories = append(export.Repositories, types.RepositorySettings{
+			Name:             repo.Name,
+			URL:              repo.URL,
+			Type:             repo.Type,
+			Description:      repo.Description,
+			ServiceName:      repo.ServiceName,
+			ServiceLocation:  repo.ServiceLocation,
+			ManualSyncOnly:   repo.ManualSyncOnly,
+			DiscoveryReview:  repo.DiscoveryReview,
+			CollectPackages:  repo.CollectPackages,
+			WorkflowBranches: repo.WorkflowBranches,
+		})
+	}
+
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode settings: %w", err)
+	}
+	return string(data), nil
+}
+
+// ImportSettings applies settings produced by ExportSettings. Without overwrite, config keys that
+// already have a value and repositories that already exist (matched by URL) are left alone.
+// The passphrase is only needed when the export contains encrypted secrets.
+func (a *App) ImportSettings(data string, overwrite bool, passphrase string) (*types.SettingsImportResult, error) {
+	if a.configModel == nil || a.repoModel == nil {
+		return nil, fmt.Errorf("config model not initialized")
+	}
+	a.recordFeature(telemetry.SettingsImport)
+
+	var export types.SettingsExport
+	if err := json.Unmarshal([]byte(data), &export); err != nil {
+		return nil, fmt.Errorf("invalid settings file: %w", err)
+	}
+	if export.Version == 0 || export.Version > settingsExportVersion {
+		return nil, fmt.Errorf("unsupported settings file version %d", export.Version)
+	}
+
+	config := make(map[string]string, len(export.Config)+len(export.EncryptedConfig))
+	for name, value := range export.Config {
+		config[name] = value
+	}
+
+	// Decrypt everything before writing anything so a wrong passphrase leaves settings untouched
+	if len(export.EncryptedConfig) > 0 {
+		if passphrase == "" {
+			return nil, fmt.Errorf("this settings file has encrypted secrets; a passphrase is required")
+		}
+		salt, err := base64.StdEncoding.DecodeString(export.Salt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid salt in settings file: %w", err)
+		}
+		key, err := deriveSettingsKey(passphrase, salt)
+		if err != nil {
+			return nil, err
+		}
+		for name, encrypted := range export.EncryptedConfig {
+			value, err := decryptSetting(key, encrypted)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decrypt %s (wrong passphrase?): %w", name, err)
+			}
+			config[name] = value
+		}
+	}
+
+	existing, err := a.configModel.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &types.SettingsImportResult{MissingSecrets: []string{}, UnknownConfig: []string{}}
+
+	names := make([]string, 0, len(config))
+	for name := range config {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		// Exports from before these were left out still have them
+		if isMachineConfigKey(name) {
+			continue
+		}
+		// Keys this version doesn't know, e.g. from a newer or older export, are reported rather
+		// than failing the whole import
+		if _, ok := a.configSchema.Lookup(name); !ok {
+			result.UnknownConfig = append(result.UnknownConfig, name)
+			continue
+		}
+		if !overwrite && existing[name] != "" {
+			result.ConfigSkipped++
+			continue
+		}
+		if err := a.SetConfig(name, config[name]); err != nil {
+			return result, fmt.Errorf("failed to import %s: %w", name, err)
+		}
+		result.ConfigApplied++
+	}
+
+	if export.Secrets == types.SecretsRedact {
+		for _, name := range []string{"github_token", "jira_token"} {
+			if existing[name] == "" {
+				result.MissingSecrets = append(result.MissingSecrets, name)
+			}
+		}
+	}
+
+	if err := a.importRepositorySettings(export.Repositories, overwrite, result); err != nil {
+		return result, err
+	}
+
+	log.Printf("Imported settings: %d config keys applied, %d skipped, %d unknown, %d repositories created, %d updated",
+		result.ConfigApplied, result.ConfigSkipped, len(result.UnknownConfig), result.RepositoriesCreated, result.RepositoriesUpdated)
+
+	return result, nil
+}
+
+func (a *App) importRepositorySettings(settings []types.RepositorySettings, overwrite bool, result *types.SettingsImportResult) error {
+	repos, err := a.repoModel.GetAll()
+	if err != nil {
+		return err
+	}
+
+	byURL := make(map[string]*types.Repository)
+	for _, repo := range repos {
+		byURL[normalizeRepositoryURL(repo.URL)] = repo
+	}
+
+	for _, setting := range settings {
+		if setting.URL == "" || setting.Name == "" {
+			continue
+		}
+		if setting.Type != types.MonorepoType && setting.Type != types.KubernetesType {
+			return fmt.Errorf("repository %s has unknown type %q", setting.Name, setting.Type)
+		}
+
+		repo, exists := byURL[normalizeRepositoryURL(setting.URL)]
+		if !exists {
+			repo = &types.Repository{}
+		} else if !overwrite {
+			result.RepositoriesSkipped++
+			continue
+		}
+
+		repo.Name = setting.Name
+		repo.URL = setting.URL
+		repo.Type = setting.Type
+		repo.Description = setting.Description
+		repo.ServiceName = setting.ServiceName
+		repo.ServiceLocation = setting.ServiceLocation
+
+		if exists {
+			if err := a.repoModel.Update(repo); err != nil {
+				return err
+			}
+			if err := a.repoModel.SetManualSyncOnly(repo.ID, setting.ManualSyncOnly); err != nil {
+				return err
+			}
+			if err := a.repoModel.SetDiscoveryReview(repo.ID, setting.DiscoveryReview); err != nil {
+				return err
+			}
+			if err := a.repoModel.SetCollectPackages(repo.ID, setting.CollectPackages); err != nil {
+				return err
+			}
+			if err := a.SetRepositoryWorkflowBranches(repo.ID, setting.WorkflowBranches); err != nil {
+				return err
+			}
+			result.RepositoriesUpdated++
+			continue
+		}
+
+		// Services and resources are discovered by the next sync
+		if err := a.repoModel.Create(repo); err != nil {
+			return err
+		}
+		if err := a.repoModel.SetManualSyncOnly(repo.ID, setting.ManualSyncOnly); err != nil {
+			return err
+		}
+		if err := a.repoModel.SetDiscoveryReview(repo.ID, setting.DiscoveryReview); err != nil {
+			return err
+		}
+		if err := a.repoModel.SetCollectPackages(repo.ID, setting.CollectPackages); err != nil {
+			return err
+		}
+		if err := a.SetRepositoryWorkflowBranches(repo.ID, setting.WorkflowBranches); err != nil {
+			return err
+		}
+		repo.ManualSyncOnly = setting.ManualSyncOnly
+		repo.DiscoveryReview = setting.DiscoveryReview
+		repo.CollectPackages = setting.CollectPackages
+		repo.WorkflowBranches = setting.WorkflowBranches
+		byURL[normalizeRepositoryURL(repo.URL)] = repo
+		result.RepositoriesCreated++
+	}
+
+	return nil
+}
+
+// normalizeRepositoryURL makes URLs that point at the same repository compare equal
+func normalizeRepositoryURL(url string) string {
+	url = strings.ToLower(strings.TrimSpace(url))
+	url = strings.TrimSuffix(url, "/")
+	return strings.TrimSuffix(url, ".git")
+}
+
+func deriveSettingsKey(passphrase string, salt []byte) ([]byte, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, 1<<15, 8, 1, 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+	return key, nil
+}
+
+// encryptSetting seals a value with AES-GCM, returning base64 of nonce followed by ciphertext
+func encryptSetting(key []byte, value string) (string, error) {
+	gcm, err := newSettingsGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(value), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func decryptSetting(key []byte, encrypted string) (string, error) {
+	gcm, err := newSettingsGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return "", fmt.Errorf("ciphertext too short")
+	}
+
+	value, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", err
+	}
+	return string(value), nil
+}
+
+func newSettingsGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
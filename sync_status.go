@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+
+	"dev-dashboard/pkg/types"
+)
+
+// GetSyncStatus returns where the sync of each repository stands: whether a pass is running or was
+// interrupted, its current phase and completed phases, and the last pass that completed every phase
+func (a *App) GetSyncStatus() ([]*types.RepositorySyncStatus, error) {
+	if a.repoModel == nil {
+		return nil, fmt.Errorf("repository model not initialized")
+	}
+
+	repos, err := a.repoModel.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]*types.RepositorySyncStatus, 0, len(repos))
+	for _, repo := range repos {
+		status := &types.RepositorySyncStatus{
+			RepositoryID:    repo.ID,
+			Name:            repo.Name,
+			Running:         a.syncService != nil && a.syncService.Syncing(repo.ID),
+			CompletedPhases: []types.SyncPhase{},
+			LastSyncAt:      repo.LastSyncAt,
+			LastSyncError:   repo.LastSyncError,
+		}
+		if state := repo.SyncState; state != nil {
+			startedAt := state.StartedAt
+			status.Interrupted = !status.Running
+			status.Phase = state.Phase
+			status.CompletedPhases = state.Completed
+			status.StartedAt = &startedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
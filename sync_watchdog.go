@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"dev-dashboard/internal/sync"
+)
+
+// syncStuckMultipleKey is how many times its usual duration a background sync pass may run before
+// the watchdog cancels it (default sync.DefaultStuckPassMultiple, 0 disables the watchdog)
+const syncStuckMultipleKey = "sync_stuck_multiple"
+
+func parseSyncStuckMultiple(value string) (float64, error) {
+	multiple, err := strconv.ParseFloat(value, 64)
+	if err != nil || multiple < 0 || (multiple > 0 && multiple < 1) {
+		return 0, fmt.Errorf("%s must be 0 or a multiple of at least 1, got %q", syncStuckMultipleKey, value)
+	}
+	return multiple, nil
+}
+
+// getSyncStuckMultiple returns the sync_stuck_multiple config key
+func (a *App) getSyncStuckMultiple() float64 {
+	if a.configModel != nil {
+		if config, err := a.configModel.Get(syncStuckMultipleKey); err == nil && config != nil && config.Value != "" {
+			if multiple, err := parseSyncStuckMultiple(config.Value); err == nil {
+				return multiple
+			}
+		}
+	}
+	return sync.DefaultStuckPassMultiple
+}
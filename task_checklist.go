@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"dev-dashboard/pkg/types"
+)
+
+// GetTaskChecklist returns a task's checklist items in order
+func (a *App) GetTaskChecklist(taskID int64) ([]*types.TaskChecklistItem, error) {
+	if a.taskChecklistModel == nil {
+		return []*types.TaskChecklistItem{}, nil
+	}
+	return a.taskChecklistModel.GetByTaskID(taskID)
+}
+
+// AddTaskChecklistItem appends an item to the end of a task's checklist
+func (a *App) AddTaskChecklistItem(taskID int64, text string) (*types.TaskChecklistItem, error) {
+	if a.taskChecklistModel == nil {
+		return nil, fmt.Errorf("task checklist model not initialized")
+	}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, fmt.Errorf("checklist item text is required")
+	}
+
+	item := &types.TaskChecklistItem{TaskID: taskID, Text: text}
+	if err := a.taskChecklistModel.Add(item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// ToggleTaskChecklistItem checks an item off, or unchecks it, and returns the updated item
+func (a *App) ToggleTaskChecklistItem(id int64) (*types.TaskChecklistItem, error) {
+	if a.taskChecklistModel == nil {
+		return nil, fmt.Errorf("task checklist model not initialized")
+	}
+	item, err := a.taskChecklistModel.Toggle(id)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, fmt.Errorf("checklist item %d not found", id)
+	}
+	return item, nil
+}
+
+// ReorderTaskChecklist puts a task's checklist in the order of itemIDs, which must list every item
+func (a *App) ReorderTaskChecklist(taskID int64, itemIDs []int64) error {
+	if a.taskChecklistModel == nil {
+		return fmt.Errorf("task checklist model not initialized")
+	}
+	return a.taskChecklistModel.Reorder(taskID, itemIDs)
+}
+
+// DeleteTaskChecklistItem removes an item from its task's checklist
+func (a *App) DeleteTaskChecklistItem(id int64) error {
+	if a.taskChecklistModel == nil {
+		return fmt.Errorf("task checklist model not initialized")
+	}
+	return a.taskChecklistModel.Delete(id)
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+
+	"dev-dashboard/pkg/types"
+)
+
+// GetTaskDetail returns a task with its project's name, links to its JIRA tickets, its JIRA labels
+// as tags and its checklist, so the task detail view needs a single call
+func (a *App) GetTaskDetail(id int64) (*types.TaskDetail, error) {
+	if a.taskModel == nil || a.projectModel == nil {
+		return nil, fmt.Errorf("task model not initialized")
+	}
+
+	task, err := a.taskModel.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	project, err := a.projectModel.GetByID(task.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	detail := &types.TaskDetail{
+		Task:        task,
+		ProjectName: project.Name,
+		Tags:        task.JiraLabels,
+		Checklist:   []*types.TaskChecklistItem{},
+	}
+	if detail.Tags == nil {
+		detail.Tags = []string{}
+	}
+	if a.jiraClient != nil {
+		if task.JiraTicketID != "" {
+			detail.JiraURL = a.jiraClient.IssueURL(task.JiraTicketID)
+		}
+		if task.JiraParentKey != "" {
+			detail.JiraParentURL = a.jiraClient.IssueURL(task.JiraParentKey)
+		}
+	}
+	if a.taskChecklistModel != nil {
+		checklist, err := a.taskChecklistModel.GetByTaskID(id)
+		if err != nil {
+			return nil, err
+		}
+		detail.Checklist = checklist
+	}
+	return detail, nil
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	gosync "sync"
+	"time"
+
+	"dev-dashboard/internal/models"
+	"dev-dashboard/internal/telemetry"
+	"dev-dashboard/pkg/types"
+)
+
+const (
+	// telemetryEnabledKey turns on counting how often features are used. Off by default; turning it
+	// off deletes the counts.
+	telemetryEnabledKey = "telemetry_enabled"
+	// telemetryEndpointKey is where the counts are sent every telemetryFlushInterval; empty keeps
+	// them local
+	telemetryEndpointKey = "telemetry_endpoint"
+
+	telemetryFlushInterval = time.Hour
+	telemetryFlushTimeout  = 30 * time.Second
+)
+
+// telemetryReporter counts feature use and sends the counts to the configured endpoint
+type telemetryReporter struct {
+	model  *models.TelemetryModel
+	client *http.Client
+
+	mu             gosync.Mutex
+	lastFlushAt    *time.Time
+	lastFlushError string
+}
+
+func newTelemetryReporter(model *models.TelemetryModel) *telemetryReporter {
+	return &telemetryReporter{model: model, client: &http.Client{Timeout: telemetryFlushTimeout}}
+}
+
+// recordFeature counts a use of a feature when telemetry is enabled. Failures are only logged so
+// they never break the feature itself.
+func (a *App) recordFeature(feature telemetry.Feature) {
+	if a.telemetry == nil || !a.getConfigFlag(telemetryEnabledKey) {
+		return
+	}
+	if err := a.telemetry.model.Increment(string(feature)); err != nil {
+		log.Printf("Failed to count use of %s: %v", feature, err)
+	}
+}
+
+// getTelemetryEndpoint returns the telemetry_endpoint config key
+func (a *App) getTelemetryEndpoint() string {
+	if a.configModel != nil {
+		if config, err := a.configModel.Get(telemetryEndpointKey); err == nil && config != nil {
+			return config.Value
+		}
+	}
+	return ""
+}
+
+// applyTelemetryEnabled deletes the counts when telemetry is turned off
+func (a *App) applyTelemetryEnabled() {
+	if a.telemetry == nil || a.getConfigFlag(telemetryEnabledKey) {
+		return
+	}
+	if err := a.telemetry.model.Clear(); err != nil {
+		log.Printf("Failed to clear telemetry: %v", err)
+	}
+}
+
+// GetTelemetrySummary shows everything telemetry has collected and the exact body the next flush
+// would send
+func (a *App) GetTelemetrySummary() (*types.TelemetrySummary, error) {
+	if a.telemetry == nil {
+		return nil, fmt.Errorf("telemetry model not initialized")
+	}
+
+	counters, err := a.telemetry.model.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	body, err := telemetryPayload(counters).JSON()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode telemetry: %w", err)
+	}
+
+	summary := &types.TelemetrySummary{
+		Enabled:     a.getConfigFlag(telemetryEnabledKey),
+		Endpoint:    a.getTelemetryEndpoint(),
+		Features:    make([]string, 0, len(telemetry.Features)),
+		Counters:    counters,
+		NextPayload: string(body),
+	}
+	for _, feature := range telemetry.Features {
+		summary.Features = append(summary.Features, string(feature))
+	}
+	a.telemetry.mu.Lock()
+	summary.LastFlushAt, summary.LastFlushError = a.telemetry.lastFlushAt, a.telemetry.lastFlushError
+	a.telemetry.mu.Unlock()
+	return summary, nil
+}
+
+// FlushTelemetry sends the pending counts to the telemetry endpoint now
+func (a *App) FlushTelemetry() error {
+	if a.telemetry == nil {
+		return fmt.Errorf("telemetry model not initialized")
+	}
+	if !a.getConfigFlag(telemetryEnabledKey) {
+		return fmt.Errorf("telemetry is disabled")
+	}
+	endpoint := a.getTelemetryEndpoint()
+	if endpoint == "" {
+		return fmt.Errorf("no telemetry endpoint configured")
+	}
+	return a.flushTelemetry(endpoint)
+}
+
+// flushTelemetry sends the pending counts, if any, and marks them sent
+func (a *App) flushTelemetry(endpoint string) error {
+	counters, err := a.telemetry.model.GetAll()
+	if err != nil {
+		return err
+	}
+	payload := telemetryPayload(counters)
+	if payload.Empty() {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(a.ctx, telemetryFlushTimeout)
+	defer cancel()
+	err = telemetry.Send(ctx, a.telemetry.client, endpoint, payload)
+
+	now := time.Now()
+	a.telemetry.mu.Lock()
+	a.telemetry.lastFlushAt = &now
+	a.telemetry.lastFlushError = ""
+	if err != nil {
+		a.telemetry.lastFlushError = err.Error()
+	}
+	a.telemetry.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	sent := make(map[string]int64, len(payload.Counts))
+	for feature, count := range payload.Counts {
+		sent[string(feature)] = count
+	}
+	return a.telemetry.model.MarkFlushed(sent)
+}
+
+// flushTelemetryPeriodically flushes the counts every telemetryFlushInterval while telemetry is
+// enabled and an endpoint is configured, until the app shuts down
+func (a *App) flushTelemetryPeriodically() {
+	ticker := time.NewTicker(telemetryFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-a.ctx.Done():
+			return
+		case <-ticker.C:
+			endpoint := a.getTelemetryEndpoint()
+			if !a.getConfigFlag(telemetryEnabledKey) || endpoint == "" {
+				continue
+			}
+			if err := a.flushTelemetry(endpoint); err != nil {
+				log.Printf("Failed to flush telemetry: %v", err)
+			}
+		}
+	}
+}
+
+// telemetryPayload is the payload of the counts not sent yet
+func telemetryPayload(counters []types.TelemetryCounter) telemetry.Payload {
+	pending := make(map[telemetry.Feature]int64, len(counters))
+	for _, counter := range counters {
+		pending[telemetry.Feature(counter.Feature)] = counter.Pending
+	}
+	return telemetry.NewPayload(pending)
+}
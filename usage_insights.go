@@ -0,0 +1,135 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"dev-dashboard/pkg/types"
+)
+
+const (
+	collectUsageAnalyticsKey = "collect_usage_analytics"
+
+	usageInsightsTopServices = 10
+)
+
+// recordUsage stores a view in the local usage analytics when the collect_usage_analytics config
+// flag is enabled. Failures are only logged so they never break the view itself.
+func (a *App) recordUsage(event types.UsageEventType, serviceID *int64) {
+	if a.usageEventModel == nil || !a.getConfigFlag(collectUsageAnalyticsKey) {
+		return
+	}
+	if err := a.usageEventModel.Record(event, serviceID); err != nil {
+		log.Printf("Failed to record usage event %s: %v", event, err)
+	}
+}
+
+// GetUsageInsights summarizes the views recorded over the last `days` days: the most viewed
+// services and the hours of the day the dashboard is used most
+func (a *App) GetUsageInsights(days int) (*types.UsageInsights, error) {
+	if a.usageEventModel == nil {
+		return nil, fmt.Errorf("usage event model not initialized")
+	}
+	if days <= 0 {
+		return nil, fmt.Errorf("days must be positive")
+	}
+
+	since := time.Now().AddDate(0, 0, -days)
+	events, err := a.usageEventModel.GetSince(since)
+	if err != nil {
+		return nil, err
+	}
+
+	insights := &types.UsageInsights{
+		Days:         days,
+		Since:        since,
+		Enabled:      a.getConfigFlag(collectUsageAnalyticsKey),
+		TotalEvents:  len(events),
+		EventCounts:  make(map[types.UsageEventType]int),
+		TopServices:  []types.ServiceViewCount{},
+		BusiestHours: []types.HourCount{},
+	}
+
+	views := make(map[int64]*types.ServiceViewCount)
+	var hours [24]int
+	for _, event := range events {
+		insights.EventCounts[event.Event]++
+		hours[event.CreatedAt.Local().Hour()]++
+
+		if event.ServiceID == nil {
+			continue
+		}
+		count, ok := views[*event.ServiceID]
+		if !ok {
+			count = &types.ServiceViewCount{ServiceID: *event.ServiceID}
+			views[*event.ServiceID] = count
+		}
+		count.Views++
+		if event.CreatedAt.After(count.LastViewedAt) {
+			count.LastViewedAt = event.CreatedAt
+		}
+	}
+
+	for _, count := range views {
+		insights.TopServices = append(insights.TopServices, *count)
+	}
+	sort.Slice(insights.TopServices, func(i, j int) bool {
+		if insights.TopServices[i].Views != insights.TopServices[j].Views {
+			return insights.TopServices[i].Views > insights.TopServices[j].Views
+		}
+		return insights.TopServices[i].LastViewedAt.After(insights.TopServices[j].LastViewedAt)
+	})
+	if len(insights.TopServices) > usageInsightsTopServices {
+		insights.TopServices = insights.TopServices[:usageInsightsTopServices]
+	}
+	for i := range insights.TopServices {
+		if service, err := a.serviceModel.GetByID(insights.TopServices[i].ServiceID); err == nil {
+			insights.TopServices[i].ServiceName = service.Name
+		}
+	}
+
+	for hour, count := range hours {
+		if count > 0 {
+			insights.BusiestHours = append(insights.BusiestHours, types.HourCount{Hour: hour, Events: count})
+		}
+	}
+	sort.SliceStable(insights.BusiestHours, func(i, j int) bool {
+		return insights.BusiestHours[i].Events > insights.BusiestHours[j].Events
+	})
+
+	return insights, nil
+}
+
+// ExportUsageData returns every recorded usage event as JSON
+func (a *App) ExportUsageData() (string, error) {
+	if a.usageEventModel == nil {
+		return "", fmt.Errorf("usage event model not initialized")
+	}
+
+	events, err := a.usageEventModel.GetSince(time.Time{})
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.MarshalIndent(events, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode usage data: %w", err)
+	}
+	return string(data), nil
+}
+
+// ClearUsageData permanently deletes all recorded usage events
+func (a *App) ClearUsageData() error {
+	if a.usageEventModel == nil {
+		return fmt.Errorf("usage event model not initialized")
+	}
+	if err := a.usageEventModel.Clear(); err != nil {
+		return err
+	}
+
+	log.Println("Cleared usage analytics data")
+	return nil
+}
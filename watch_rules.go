@@ -0,0 +1,366 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"dev-dashboard/internal/models"
+	"dev-dashboard/internal/telemetry"
+	"dev-dashboard/internal/watch"
+	"dev-dashboard/pkg/types"
+)
+
+// watchRuleRunner serializes evaluations so a manual run and the end of a sync pass don't report
+// the same events twice
+type watchRuleRunner struct {
+	mu    sync.Mutex
+	model *models.WatchRuleModel
+}
+
+func newWatchRuleRunner(model *models.WatchRuleModel) *watchRuleRunner {
+	return &watchRuleRunner{model: model}
+}
+
+// validateWatchRule checks a rule and normalizes it: names and environments are trimmed, and a
+// custom field scope uses the field's name and value as they're stored
+func (a *App) validateWatchRule(rule *types.WatchRule) error {
+	rule.Name = strings.TrimSpace(rule.Name)
+	if rule.Name == "" {
+		return fmt.Errorf("watch rule name is required")
+	}
+
+	switch rule.Scope {
+	case types.WatchScopeAll:
+	case types.WatchScopeServices:
+		if len(rule.ServiceIDs) == 0 {
+			return fmt.Errorf("select at least one service to watch")
+		}
+		for _, id := range rule.ServiceIDs {
+			if _, err := a.serviceModel.GetByID(id); err != nil {
+				return fmt.Errorf("service %d not found", id)
+			}
+		}
+	case types.WatchScopeCustomField:
+		fields, err := a.customFieldModel.GetDefinitions(types.CustomFieldEntityService)
+		if err != nil {
+			return err
+		}
+		field := customFieldByName(fields, strings.TrimSpace(rule.ScopeField))
+		if field == nil {
+			return fmt.Errorf("unknown service custom field %q", rule.ScopeField)
+		}
+		value, err := normalizeCustomFieldValue(field, rule.ScopeValue)
+		if err != nil {
+			return err
+		}
+		if value == "" {
+			return fmt.Errorf("a value of %s is required", field.Name)
+		}
+		rule.ScopeField, rule.ScopeValue = field.Name, value
+	case types.WatchScopeDomain:
+		rule.ScopeValue = strings.TrimSpace(rule.ScopeValue)
+		if rule.ScopeValue == "" {
+			return fmt.Errorf("a domain is required")
+		}
+	default:
+		return fmt.Errorf("invalid watch rule scope %q", rule.Scope)
+	}
+	if rule.Scope != types.WatchScopeServices {
+		rule.ServiceIDs = nil
+	}
+	if rule.Scope != types.WatchScopeCustomField {
+		rule.ScopeField = ""
+	}
+	if rule.Scope == types.WatchScopeAll || rule.Scope == types.WatchScopeServices {
+		rule.ScopeValue = ""
+	}
+
+	environments := []string{}
+	for _, environment := range rule.Environments {
+		if environment = strings.TrimSpace(environment); environment != "" {
+			environments = append(environments, environment)
+		}
+	}
+	rule.Environments = environments
+
+	if len(rule.Conditions) == 0 {
+		return fmt.Errorf("add at least one condition")
+	}
+	for i, condition := range rule.Conditions {
+		switch condition.Type {
+		case types.WatchTagChanged, types.WatchDeployFailed:
+			rule.Conditions[i].Threshold = 0
+		case types.WatchNoDeploy, types.WatchDrift:
+			if condition.Threshold <= 0 {
+				return fmt.Errorf("the threshold of %s must be positive", condition.Type)
+			}
+		default:
+			return fmt.Errorf("invalid watch condition %q", condition.Type)
+		}
+	}
+	return nil
+}
+
+// CreateWatchRule adds a rule raising notifications for deployment events of the services and
+// environments it scopes. It's evaluated from the end of the next sync pass on.
+func (a *App) CreateWatchRule(rule types.WatchRule) (*types.WatchRule, error) {
+	if a.watchRules == nil {
+		return nil, fmt.Errorf("watch rule model not initialized")
+	}
+	a.recordFeature(telemetry.WatchRuleCreated)
+	if err := a.validateWatchRule(&rule); err != nil {
+		return nil, err
+	}
+	if err := a.watchRules.model.Create(&rule); err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// UpdateWatchRule changes a rule. Conditions that hold are reported again at the next evaluation.
+func (a *App) UpdateWatchRule(rule types.WatchRule) error {
+	if a.watchRules == nil {
+		return fmt.Errorf("watch rule model not initialized")
+	}
+	if err := a.validateWatchRule(&rule); err != nil {
+		return err
+	}
+	return a.watchRules.model.Update(&rule)
+}
+
+// SetWatchRuleEnabled turns a rule on or off
+func (a *App) SetWatchRuleEnabled(id int64, enabled bool) error {
+	if a.watchRules == nil {
+		return fmt.Errorf("watch rule model not initialized")
+	}
+	return a.watchRules.model.SetEnabled(id, enabled)
+}
+
+// DeleteWatchRule deletes a rule along with its evaluation log
+func (a *App) DeleteWatchRule(id int64) error {
+	if a.watchRules == nil {
+		return fmt.Errorf("watch rule model not initialized")
+	}
+	return a.watchRules.model.Delete(id)
+}
+
+// GetWatchRules returns every watch rule
+func (a *App) GetWatchRules() ([]*types.WatchRule, error) {
+	if a.watchRules == nil {
+		return nil, fmt.Errorf("watch rule model not initialized")
+	}
+	return a.watchRules.model.GetAll()
+}
+
+// GetWatchRuleEvaluations returns the most recent evaluations of a rule, newest first
+func (a *App) GetWatchRuleEvaluations(ruleID int64, limit int) ([]*types.WatchEvaluation, error) {
+	if a.watchRules == nil {
+		return nil, fmt.Errorf("watch rule model not initialized")
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+	return a.watchRules.model.GetEvaluations(ruleID, limit)
+}
+
+// EvaluateWatchRules evaluates the enabled rules now instead of waiting for the next sync pass
+func (a *App) EvaluateWatchRules() error {
+	if a.watchRules == nil {
+		return fmt.Errorf("watch rule model not initialized")
+	}
+	return a.evaluateWatchRules()
+}
+
+// watchStates builds the service states rules are evaluated against, each service's only once
+// per pass and its drift only when a rule needs it
+type watchStates struct {
+	app    *App
+	since  time.Time // deployment runs are read from then on
+	states map[int64]*watch.ServiceState
+}
+
+func (w *watchStates) get(service *types.Microservice, withDrift bool) (*watch.ServiceState, error) {
+	state, ok := w.states[service.ID]
+	if !ok {
+		history, err := w.app.deploymentModel.GetHistoryByServiceID(service.ID, time.Time{})
+		if err != nil {
+			return nil, err
+		}
+		deploys, err := w.app.actionModel.GetCompletedByServiceSince(service.ID, types.DeploymentAction, w.since)
+		if err != nil {
+			return nil, err
+		}
+		deployments, err := w.app.deploymentModel.GetByServiceID(service.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		state = &watch.ServiceState{
+			Service:      service,
+			LastDeployAt: make(map[string]time.Time),
+			History:      history,
+			Deploys:      deploys,
+		}
+		for _, entry := range history {
+			state.LastDeployAt[entry.Environment] = entry.ObservedAt
+		}
+		seen := make(map[string]bool)
+		for _, deployment := range deployments {
+			if !seen[deployment.Environment] {
+				seen[deployment.Environment] = true
+				state.Environments = append(state.Environments, deployment.Environment)
+			}
+		}
+		w.app.sortEnvironments(state.Environments)
+		w.states[service.ID] = state
+	}
+
+	if withDrift && state.Drift == nil {
+		deployments, err := w.app.deploymentModel.GetByServiceID(service.ID)
+		if err != nil {
+			return nil, err
+		}
+		current := representativeDeployments(deployments)
+		compare := w.app.deployedCommitComparer(service.ID)
+		state.Drift = make(map[string]*types.DeploymentDrift)
+		for i := 1; i < len(state.Environments); i++ {
+			environment := state.Environments[i]
+			state.Drift[environment] = environmentDrift(current[environment], current[state.Environments[i-1]], compare)
+		}
+	}
+	return state, nil
+}
+
+// evaluateWatchRules evaluates every enabled rule over what happened since its previous
+// evaluation, logs the outcome and raises a notification for each condition that fired. It runs
+// at the end of each sync pass; only drift conditions make GitHub requests, to compare commits.
+func (a *App) evaluateWatchRules() error {
+	if a.watchRules == nil || a.serviceModel == nil {
+		return nil
+	}
+	a.watchRules.mu.Lock()
+	defer a.watchRules.mu.Unlock()
+
+	rules, err := a.watchRules.model.GetAll()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	states := &watchStates{app: a, since: now, states: make(map[int64]*watch.ServiceState)}
+	var enabled []*types.WatchRule
+	for _, rule := range rules {
+		if !rule.Enabled {
+			continue
+		}
+		enabled = append(enabled, rule)
+		if since := watchRuleSince(rule); since.Before(states.since) {
+			states.since = since
+		}
+	}
+	if len(enabled) == 0 {
+		return nil
+	}
+
+	services, err := a.GetMicroservices(0, false)
+	if err != nil {
+		return fmt.Errorf("failed to get services for watch rules: %w", err)
+	}
+
+	for _, rule := range enabled {
+		evaluation := &types.WatchEvaluation{RuleID: rule.ID, EvaluatedAt: now, Outcomes: []types.WatchOutcome{}}
+		result, err := a.evaluateWatchRule(rule, services, states, now)
+		if err != nil {
+			log.Printf("Failed to evaluate watch rule %s: %v", rule.Name, err)
+			evaluation.Error = err.Error()
+		} else {
+			evaluation.Services = len(result.services)
+			evaluation.Outcomes = result.Outcomes
+			evaluation.Fired = len(result.Fired())
+		}
+
+		var active []string
+		if result != nil {
+			active = result.Active
+		}
+		if err := a.watchRules.model.RecordEvaluation(evaluation, active); err != nil {
+			log.Printf("Failed to record evaluation of watch rule %s: %v", rule.Name, err)
+			continue
+		}
+		if result != nil {
+			a.notifyWatchOutcomes(rule, result)
+		}
+	}
+	return nil
+}
+
+// watchRuleResult is a rule's evaluation result along with the services it scoped
+type watchRuleResult struct {
+	*watch.Result
+	services map[int64]*types.Microservice
+}
+
+// evaluateWatchRule evaluates one rule against the services in its scope
+func (a *App) evaluateWatchRule(rule *types.WatchRule, services []*types.Microservice, states *watchStates, now time.Time) (*watchRuleResult, error) {
+	withDrift := false
+	for _, condition := range rule.Conditions {
+		if condition.Type == types.WatchDrift {
+			withDrift = true
+		}
+	}
+
+	scoped := make(map[int64]*types.Microservice)
+	var inScope []*watch.ServiceState
+	for _, service := range services {
+		if !watch.InScope(rule, service) {
+			continue
+		}
+		state, err := states.get(service, withDrift)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get deployments of %s: %w", service.Name, err)
+		}
+		scoped[service.ID] = service
+		inScope = append(inScope, state)
+	}
+
+	active, err := a.watchRules.model.GetActiveKeys(rule.ID)
+	if err != nil {
+		return nil, err
+	}
+	result := watch.Evaluate(rule, inScope, active, watchRuleSince(rule), now)
+	return &watchRuleResult{Result: result, services: scoped}, nil
+}
+
+// watchRuleSince is when the window of a rule's next evaluation starts
+func watchRuleSince(rule *types.WatchRule) time.Time {
+	if rule.LastEvaluatedAt != nil {
+		return *rule.LastEvaluatedAt
+	}
+	return rule.CreatedAt
+}
+
+// notifyWatchOutcomes raises a notification for each condition of a rule that fired
+func (a *App) notifyWatchOutcomes(rule *types.WatchRule, result *watchRuleResult) {
+	if a.notifier == nil {
+		return
+	}
+	for _, outcome := range result.Fired() {
+		title := fmt.Sprintf("%s: %s", rule.Name, outcome.ServiceName)
+		if outcome.Environment != "" {
+			title += " in " + outcome.Environment
+		}
+		notification := &types.Notification{
+			Type:        "watch_rule",
+			Title:       title,
+			Message:     outcome.Detail,
+			Environment: outcome.Environment,
+		}
+		if service := result.services[outcome.ServiceID]; service != nil {
+			repositoryID := service.RepositoryID
+			notification.RepositoryID = &repositoryID
+		}
+		a.notifier.Notify(notification)
+	}
+}
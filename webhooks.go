@@ -0,0 +1,281 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"dev-dashboard/internal/github"
+	"dev-dashboard/internal/vcs"
+	"dev-dashboard/pkg/types"
+)
+
+const (
+	auditWebhookInstall = "webhook_install"
+	auditWebhookRemove  = "webhook_remove"
+
+	webhookDeliveriesChecked = 20
+)
+
+// InstallRepositoryWebhook creates a webhook on the repository that sends push, pull request and
+// workflow run events to targetURL, signed with a newly generated secret that is stored encrypted.
+// Requires the write_actions_enabled config flag.
+func (a *App) InstallRepositoryWebhook(repoID int64, targetURL string) (webhook *types.RepositoryWebhook, err error) {
+	if !a.writeActionsEnabled() {
+		return nil, fmt.Errorf("write actions are disabled - enable write_actions_enabled in settings")
+	}
+	if a.webhookModel == nil || a.repoModel == nil {
+		return nil, fmt.Errorf("webhook model not initialized")
+	}
+
+	parsed, err := url.Parse(targetURL)
+	if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid webhook target URL %q", targetURL)
+	}
+
+	repo, err := a.repoModel.GetByID(repoID)
+	if err != nil {
+		return nil, fmt.Errorf("repository not found: %w", err)
+	}
+
+	defer func() {
+		a.audit(auditWebhookInstall, repoID, fmt.Sprintf("%s -> %s", repo.Name, targetURL), err)
+	}()
+
+	existing, err := a.webhookModel.GetByRepositoryID(repoID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("a webhook is already installed on %s - remove it first", repo.Name)
+	}
+
+	githubClient, owner, repoName, err := a.repositoryGitHubClient(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	secretBytes := make([]byte, 32)
+	if _, err := rand.Read(secretBytes); err != nil {
+		return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	secret := hex.EncodeToString(secretBytes)
+
+	key, err := localSecretKey()
+	if err != nil {
+		return nil, err
+	}
+	encryptedSecret, err := encryptSetting(key, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	hook, err := githubClient.CreateWebhook(context.Background(), owner, repoName, targetURL, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook = &types.RepositoryWebhook{
+		RepositoryID: repoID,
+		HookID:       hook.ID,
+		TargetURL:    targetURL,
+		Secret:       encryptedSecret,
+	}
+	if err := a.webhookModel.Upsert(webhook); err != nil {
+		// Don't leave a webhook on GitHub whose secret we've lost
+		if deleteErr := githubClient.DeleteWebhook(context.Background(), owner, repoName, hook.ID); deleteErr != nil {
+			log.Printf("Failed to delete webhook %d on %s after save failed: %v", hook.ID, repo.Name, deleteErr)
+		}
+		return nil, err
+	}
+
+	log.Printf("Installed webhook %d on %s", hook.ID, repo.Name)
+	return webhook, nil
+}
+
+// RemoveRepositoryWebhook deletes the repository's webhook on GitHub and forgets its secret.
+// Requires the write_actions_enabled config flag.
+func (a *App) RemoveRepositoryWebhook(repoID int64) (err error) {
+	if !a.writeActionsEnabled() {
+		return fmt.Errorf("write actions are disabled - enable write_actions_enabled in settings")
+	}
+	if a.webhookModel == nil || a.repoModel == nil {
+		return fmt.Errorf("webhook model not initialized")
+	}
+
+	repo, err := a.repoModel.GetByID(repoID)
+	if err != nil {
+		return fmt.Errorf("repository not found: %w", err)
+	}
+
+	webhook, err := a.webhookModel.GetByRepositoryID(repoID)
+	if err != nil {
+		return err
+	}
+	if webhook == nil {
+		return fmt.Errorf("no webhook installed on %s", repo.Name)
+	}
+
+	defer func() {
+		a.audit(auditWebhookRemove, repoID, fmt.Sprintf("%s -> %s (hook %d)", repo.Name, webhook.TargetURL, webhook.HookID), err)
+	}()
+
+	githubClient, owner, repoName, err := a.repositoryGitHubClient(repo)
+	if err != nil {
+		return err
+	}
+
+	if err := githubClient.DeleteWebhook(context.Background(), owner, repoName, webhook.HookID); err != nil {
+		return err
+	}
+	if err := a.webhookModel.Delete(repoID); err != nil {
+		return err
+	}
+
+	log.Printf("Removed webhook %d from %s", webhook.HookID, repo.Name)
+	return nil
+}
+
+// GetWebhookStatus checks that the repository's webhook still exists on GitHub and summarizes its
+// most recent deliveries. GitHub errors are reported in the status rather than returned.
+func (a *App) GetWebhookStatus(repoID int64) (*types.WebhookStatus, error) {
+	if a.webhookModel == nil || a.repoModel == nil {
+		return nil, fmt.Errorf("webhook model not initialized")
+	}
+
+	repo, err := a.repoModel.GetByID(repoID)
+	if err != nil {
+		return nil, fmt.Errorf("repository not found: %w", err)
+	}
+
+	status := &types.WebhookStatus{RepositoryID: repoID, Events: []string{}}
+
+	webhook, err := a.webhookModel.GetByRepositoryID(repoID)
+	if err != nil {
+		return nil, err
+	}
+	if webhook == nil {
+		return status, nil
+	}
+	status.HookID = webhook.HookID
+	status.TargetURL = webhook.TargetURL
+
+	githubClient, owner, repoName, err := a.repositoryGitHubClient(repo)
+	if err != nil {
+		status.Error = err.Error()
+		return status, nil
+	}
+
+	ctx := context.Background()
+	hook, err := githubClient.GetWebhook(ctx, owner, repoName, webhook.HookID)
+	if err != nil {
+		status.MissingOnGitHub = errors.Is(err, github.ErrHookNotFound)
+		status.Error = err.Error()
+		return status, nil
+	}
+	status.Installed = true
+	status.Active = hook.Active
+	if hook.Events != nil {
+		status.Events = hook.Events
+	}
+
+	deliveries, err := githubClient.ListWebhookDeliveries(ctx, owner, repoName, webhook.HookID, webhookDeliveriesChecked)
+	if err != nil {
+		status.Error = err.Error()
+		return status, nil
+	}
+
+	status.RecentDeliveries = len(deliveries)
+	for _, delivery := range deliveries {
+		if !delivery.Succeeded() {
+			status.FailedDeliveries++
+		}
+	}
+	if len(deliveries) > 0 {
+		deliveredAt := deliveries[0].DeliveredAt
+		status.LastDeliveryAt = &deliveredAt
+		status.LastDeliveryStatus = deliveries[0].Status
+	}
+
+	return status, nil
+}
+
+// GetAuditLog returns the most recent changes the app made on GitHub
+func (a *App) GetAuditLog(limit int) ([]*types.AuditEntry, error) {
+	if a.auditModel == nil {
+		return nil, fmt.Errorf("audit model not initialized")
+	}
+	return a.auditModel.GetRecent(limit)
+}
+
+// audit records the outcome of an action; failures to write the audit log are only logged
+func (a *App) audit(action string, repoID int64, detail string, actionErr error) {
+	if a.auditModel == nil {
+		return
+	}
+
+	entry := &types.AuditEntry{
+		Action:       action,
+		RepositoryID: &repoID,
+		Detail:       detail,
+		Success:      actionErr == nil,
+	}
+	if actionErr != nil {
+		entry.Detail = fmt.Sprintf("%s: %v", detail, actionErr)
+	}
+
+	if err := a.auditModel.Create(entry); err != nil {
+		log.Printf("Failed to write audit log entry for %s: %v", action, err)
+	}
+}
+
+// repositoryGitHubClient returns a GitHub client for the configured token along with the repository's owner and name
+func (a *App) repositoryGitHubClient(repo *types.Repository) (*github.Client, string, string, error) {
+	githubToken := a.getGitHubToken()
+	if githubToken == "" {
+		return nil, "", "", fmt.Errorf("GitHub token not configured")
+	}
+
+	owner, repoName, err := vcs.ParseGitHubURL(repo.URL)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("invalid repository URL: %w", err)
+	}
+
+	return github.NewClientWithBaseURL(githubToken, a.getGitHubEnterpriseURL(), a.githubClientOptions()...), owner, repoName, nil
+}
+
+// localSecretKey returns the key used to encrypt secrets stored in the database, creating it on
+// first use. It lives next to the database, readable only by the current user.
+func localSecretKey() ([]byte, error) {
+	dbPath, err := databasePath()
+	if err != nil {
+		return nil, err
+	}
+	keyPath := filepath.Join(filepath.Dir(dbPath), "secret.key")
+
+	key, err := os.ReadFile(keyPath)
+	if err == nil {
+		if len(key) != 32 {
+			return nil, fmt.Errorf("secret key %s is corrupt", keyPath)
+		}
+		return key, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read secret key: %w", err)
+	}
+
+	key = make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate secret key: %w", err)
+	}
+	if err := os.WriteFile(keyPath, key, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write secret key: %w", err)
+	}
+	return key, nil
+}
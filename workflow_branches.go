@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"dev-dashboard/internal/sync"
+	"dev-dashboard/pkg/types"
+)
+
+// SetRepositoryWorkflowBranches sets the comma-separated branch patterns whose workflow runs the
+// sync records for a repository, e.g. "{default},release/*,hotfix/*" or "*" for every branch; an
+// empty value restores the default branch and release/*. Runs already recorded on other branches
+// stay until a prune_workflow_runs job removes them.
+func (a *App) SetRepositoryWorkflowBranches(id int64, branches string) error {
+	if a.repoModel == nil {
+		return fmt.Errorf("repository model not initialized")
+	}
+	var patterns []string
+	for _, pattern := range strings.Split(branches, ",") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			patterns = append(patterns, pattern)
+		}
+	}
+	branches = strings.Join(patterns, ",")
+	if _, err := sync.ParseBranchFilter(branches); err != nil {
+		return err
+	}
+	return a.repoModel.SetWorkflowBranches(id, branches)
+}
+
+// pruneWorkflowRunsResult is the result of a prune_workflow_runs job
+type pruneWorkflowRunsResult struct {
+	Repositories int `json:"repositories"`
+	Deleted      int `json:"deleted"`
+}
+
+// pruneWorkflowRunsJob deletes the recorded workflow runs of every repository, or of the one given
+// by repository_id, on branches its workflow branch filter doesn't let through, e.g. the feature
+// branch runs recorded before the filter was set
+func (a *App) pruneWorkflowRunsJob(params map[string]interface{}) (jobFunc, error) {
+	if a.repoModel == nil || a.actionModel == nil {
+		return nil, fmt.Errorf("repository model not initialized")
+	}
+	repositoryID, err := jobParamID(params, "repository_id")
+	if err != nil {
+		return nil, err
+	}
+
+	var repos []*types.Repository
+	if repositoryID != 0 {
+		repo, err := a.repoModel.GetByID(repositoryID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get repository: %w", err)
+		}
+		repos = append(repos, repo)
+	} else if repos, err = a.repoModel.GetAll(); err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context, report jobReporter) (*jobOutcome, error) {
+		result := &pruneWorkflowRunsResult{}
+		for i, repo := range repos {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			report(i*100/len(repos), fmt.Sprintf("Pruning workflow runs of %s", repo.Name))
+
+			filter, err := sync.ParseBranchFilter(repo.WorkflowBranches)
+			if err != nil {
+				log.Printf("Skipping %s, its workflow branch filter is invalid: %v", repo.Name, err)
+				continue
+			}
+			branches, err := a.actionModel.GetBranches(repo.ID)
+			if err != nil {
+				return nil, err
+			}
+			var excluded []string
+			for _, branch := range branches {
+				if !filter.Match(branch, repo.DefaultBranch) {
+					excluded = append(excluded, branch)
+				}
+			}
+			deleted, err := a.actionModel.DeleteByBranches(repo.ID, excluded)
+			if err != nil {
+				return nil, err
+			}
+			if deleted > 0 {
+				log.Printf("Pruned %d workflow runs of %s on %d branches", deleted, repo.Name, len(excluded))
+			}
+			result.Repositories++
+			result.Deleted += int(deleted)
+		}
+		return &jobOutcome{Result: result}, nil
+	}, nil
+}